@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var (
+	auditUser    string
+	auditCommand string
+	auditSince   time.Duration
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Lists the minikube commands which changed the clusters, from the audit log",
+	Long: `Lists the minikube commands which changed the clusters, from the audit log.
+
+The commands which change the cluster of a profile, such as start, stop, delete and addons enable, record in the audit log
+when they started, completed or failed, and who ran them. The entries of all the profiles are listed, unless --profile is given.
+
+The log is rotated once it grows above audit-max-size (1MB by default), keeping the entries of the last audit-max-age (720h by default)
+in the rotated log, which replaces the previous one. Both can be changed with 'minikube config set', and 0 disables them.`,
+	Example: `minikube audit --command=addons --since=24h
+minikube audit --user=alice -o json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("usage: minikube audit")
+		}
+		f := audit.Filter{User: auditUser, Command: auditCommand}
+		if cmd.Flags().Changed(config.MachineProfile) {
+			f.Profile = viper.GetString(config.MachineProfile)
+		}
+		if auditSince > 0 {
+			f.Since = time.Now().Add(-auditSince)
+		}
+
+		entries, err := audit.Read()
+		if err != nil {
+			exit.WithError("Failed to read the audit log", err)
+		}
+		selected := []audit.Entry{}
+		for _, e := range entries {
+			if f.Match(e) {
+				selected = append(selected, e)
+			}
+		}
+
+		if out.IsJSON() {
+			if err := out.JSON(selected); err != nil {
+				exit.WithError("Error writing the audit log", err)
+			}
+			return
+		}
+		if len(selected) == 0 {
+			out.T(out.Empty, "No entries in the audit log")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Time", "Profile", "Command", "Event", "User", "Version", "Message"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, e := range selected {
+			table.Append([]string{e.Time.Local().Format("2006-01-02 15:04:05"), e.Profile, e.Command, e.Event, e.User, e.Version, e.Message})
+		}
+		table.Render()
+	},
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditUser, "user", "", "Only list the commands run by this user")
+	auditCmd.Flags().StringVar(&auditCommand, "command", "", "Only list this command and its subcommands, such as 'addons' or 'addons enable'")
+	auditCmd.Flags().DurationVar(&auditSince, "since", 0, "Only list the commands of this recent period, such as 24h")
+}
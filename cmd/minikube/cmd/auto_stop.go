@@ -0,0 +1,254 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/autostop"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// autoStopInterval is how often the auto-stop process checks whether the API server is idle
+const autoStopInterval = time.Minute
+
+// autoStopCmd represents the auto-stop command
+var autoStopCmd = &cobra.Command{
+	Use:   "auto-stop",
+	Short: "Show or disable the auto-stop of an idle cluster, enabled with 'minikube start --auto-stop'",
+	Long: `Show or disable the auto-stop of an idle cluster, enabled with 'minikube start --auto-stop'.
+
+With auto-stop, kubectl reaches the API server through a listener on localhost. The cluster is stopped once the listener
+has seen no request for the given duration. With --auto-stop-restart, the next request starts the cluster again.`,
+}
+
+// autoStopStatusCmd represents the auto-stop status command
+var autoStopStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the auto-stop configuration of the profile",
+	Long:  "Shows the auto-stop configuration of the profile, selected with --profile.",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		s, err := autostop.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading auto-stop", err)
+		}
+		if s == nil {
+			out.T(out.Meh, "Auto-stop is disabled for {{.profile}}", out.V{"profile": profile})
+			return
+		}
+		out.T(out.Option, "{{.profile}} stops after {{.after}} without API requests, through localhost:{{.port}}", out.V{"profile": profile, "after": s.After, "port": s.Port})
+		if s.Restart {
+			out.T(out.Option, "The next request after a stop starts it again")
+		}
+		if s.Running() {
+			out.T(out.Running, "The auto-stop process is running, logs are written to {{.path}}", out.V{"path": autostop.LogFilePath(profile)})
+		} else {
+			out.T(out.Stopped, "The auto-stop process is not running: it starts with the cluster")
+		}
+	},
+}
+
+// autoStopDisableCmd represents the auto-stop disable command
+var autoStopDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disables the auto-stop of the profile",
+	Long: `Disables the auto-stop of the profile, selected with --profile.
+
+kubectl keeps using the listener on localhost until the next 'minikube start', which points it to the API server again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		if err := autostop.Disable(profile); err != nil {
+			exit.WithError("Error disabling auto-stop", err)
+		}
+		out.T(out.Stopped, "Disabled the auto-stop of {{.profile}}", out.V{"profile": profile})
+		out.T(out.Tip, "Run 'minikube start -p {{.profile}}' to update the kubectl context", out.V{"profile": profile})
+	},
+}
+
+// autoStopRunCmd is run in the background by 'minikube start --auto-stop' to proxy the API server and stop it when idle
+var autoStopRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		s, err := autostop.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading auto-stop", err)
+		}
+		if s == nil {
+			exit.WithCodeT(exit.Config, "Auto-stop is disabled for {{.profile}}", out.V{"profile": profile})
+		}
+		ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(s.Port)))
+		if err != nil {
+			exit.WithError("Error listening for API requests", err)
+		}
+		var start func() error
+		if s.Restart {
+			start = func() error { return autoStopRestart(profile) }
+		}
+		l := autostop.NewListener(ln, autoStopTarget, start)
+		go func() {
+			if err := l.Serve(); err != nil {
+				glog.Errorf("serve: %v", err)
+			}
+		}()
+
+		for {
+			autostop.WaitIdle(l, s.After, autoStopInterval)
+			glog.Infof("No API request for %s: stopping %s", s.After, profile)
+			if err := autoStopHost(); err != nil {
+				glog.Errorf("stopping %s: %v", profile, err)
+				continue
+			}
+			l.SetStopped(true)
+			if !s.Restart {
+				break
+			}
+		}
+		if err := l.Close(); err != nil {
+			glog.Infof("close: %v", err)
+		}
+		if err := autostop.Kill(profile); err != nil {
+			exit.WithError("Error saving auto-stop", err)
+		}
+	},
+}
+
+// autoStopTarget returns the address of the API server of the profile
+func autoStopTarget() (string, error) {
+	cc, err := config.Load()
+	if err != nil {
+		return "", errors.Wrap(err, "loading profile config")
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return "", errors.Wrap(err, "getting client")
+	}
+	defer api.Close()
+	ip, err := cluster.GetHostDriverIP(api, config.GetMachineName())
+	if err != nil {
+		return "", errors.Wrap(err, "getting IP")
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(cc.KubernetesConfig.NodePort)), nil
+}
+
+// autoStopHost stops the VM of the profile, keeping the kubectl context which points to the listener
+func autoStopHost() error {
+	// Another command, such as start, may change the cluster in the meantime: retry later
+	l, err := config.LockProfile(config.GetMachineName(), "auto-stop")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := l.Unlock(); err != nil {
+			glog.Warningf("unlocking profile: %v", err)
+		}
+	}()
+	cmdcfg.LogAudit(config.GetMachineName(), "auto-stop", audit.Started, "")
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		cmdcfg.LogAudit(config.GetMachineName(), "auto-stop", audit.Failed, err.Error())
+		return errors.Wrap(err, "getting client")
+	}
+	defer api.Close()
+	err = cluster.StopHost(api)
+	if err != nil {
+		cmdcfg.LogAudit(config.GetMachineName(), "auto-stop", audit.Failed, err.Error())
+		return err
+	}
+	cmdcfg.LogAudit(config.GetMachineName(), "auto-stop", audit.Completed, "")
+	return nil
+}
+
+// autoStopRestart starts the stopped cluster of a profile again, as it is configured
+func autoStopRestart(profile string) error {
+	glog.Infof("API request received: starting %s", profile)
+	cc, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, "loading profile config")
+	}
+	c := exec.Command(os.Args[0], append([]string{"start"}, profileStartArgs(cc)...)...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// configureAutoStop saves the auto-stop state of the profile if --auto-stop is given, before the kubectl context is updated
+func configureAutoStop(cmd *cobra.Command) {
+	if !cmd.Flags().Changed(autoStop) {
+		return
+	}
+	profile := config.GetMachineName()
+	after := viper.GetDuration(autoStop)
+	if after <= 0 {
+		if err := autostop.Disable(profile); err != nil {
+			exit.WithError("Error disabling auto-stop", err)
+		}
+		return
+	}
+	if after < autoStopInterval {
+		exit.UsageT("--auto-stop must be at least {{.min}}", out.V{"min": autoStopInterval})
+	}
+	s := &autostop.State{Profile: profile, After: after, Restart: viper.GetBool(autoStopRestartFlag)}
+	// Keep the port of the previous state, so that kubectl contexts using it stay valid
+	if old, err := autostop.Load(profile); err == nil && old != nil {
+		s.Port = old.Port
+	}
+	if err := autostop.Kill(profile); err != nil {
+		exit.WithError("Error stopping auto-stop", err)
+	}
+	if err := autostop.Save(s); err != nil {
+		exit.WithError("Error saving auto-stop", err)
+	}
+}
+
+// startAutoStop runs the auto-stop process of the profile, unless auto-stop is disabled or it is running already
+func startAutoStop() {
+	profile := config.GetMachineName()
+	s, err := autostop.Load(profile)
+	if err != nil {
+		exit.WithError("Error loading auto-stop", err)
+	}
+	if s == nil || s.Running() {
+		return
+	}
+	if err := autostop.Start(s); err != nil {
+		exit.WithError("Error starting auto-stop", err)
+	}
+	out.T(out.Notice, "{{.profile}} will stop after {{.after}} without API requests, logs will be written to {{.path}}", out.V{"profile": profile, "after": s.After, "path": autostop.LogFilePath(profile)})
+}
+
+func init() {
+	autoStopCmd.AddCommand(autoStopStatusCmd)
+	autoStopCmd.AddCommand(autoStopDisableCmd)
+	autoStopCmd.AddCommand(autoStopRunCmd)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/backup"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/version"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup [FILE]",
+	Short: "Saves an etcd snapshot, the certificates and the kubeadm configuration of the cluster to a file",
+	Long: `Saves an etcd snapshot, the certificates and the kubeadm configuration of the cluster to a file.
+
+The backup can be restored with 'minikube restore', to recover a broken cluster, or to move the state of the cluster to another machine.
+The file defaults to <profile>-backup-<date>.tar.gz in the current directory. Only the kubeadm bootstrapper is supported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.UsageT("usage: minikube backup [FILE]")
+		}
+		checkBackupBootstrapper()
+		dst := fmt.Sprintf("%s-backup-%s.tar.gz", config.GetMachineName(), time.Now().Format("20060102-150405"))
+		if len(args) == 1 {
+			dst = args[0]
+		}
+
+		runner, cc := profileRunner()
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		out.T(out.Caching, "Saving a backup of {{.profile}} to {{.path}} ...", out.V{"profile": config.GetMachineName(), "path": dst})
+		m := backup.Manifest{
+			MinikubeVersion:   version.GetVersion(),
+			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			NodeName:          cc.KubernetesConfig.NodeName,
+			Created:           time.Now().UTC(),
+		}
+		if err := backup.Save(runner, cr, m, dst); err != nil {
+			exit.WithError("Failed to save backup", err)
+		}
+		out.T(out.SuccessType, "Saved the backup to {{.path}}", out.V{"path": dst})
+	},
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore FILE",
+	Short: "Restores the etcd data, the certificates and the kubeadm configuration of the cluster from a backup",
+	Long: `Restores the etcd data, the certificates and the kubeadm configuration of the cluster from a backup made with 'minikube backup'.
+
+The cluster must be running: to move a cluster to another machine, run 'minikube start' there first, then restore the backup.
+The control plane is restarted, and workloads are recreated from the restored state.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube restore FILE")
+		}
+		checkBackupBootstrapper()
+		m, files, err := backup.Load(args[0])
+		if err != nil {
+			exit.WithCodeT(exit.Data, "Unable to load backup {{.path}}: {{.error}}", out.V{"path": args[0], "error": err})
+		}
+
+		runner, cc := profileRunner()
+		if m.KubernetesVersion != cc.KubernetesConfig.KubernetesVersion {
+			out.WarningT("The backup was taken from Kubernetes {{.backup}}, but this cluster runs Kubernetes {{.version}}", out.V{"backup": m.KubernetesVersion, "version": cc.KubernetesConfig.KubernetesVersion})
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		out.T(out.Restarting, "Restoring the backup taken on {{.date}} to {{.profile}} ...", out.V{"date": m.Created.Local().Format(time.RFC1123), "profile": config.GetMachineName()})
+		if err := backup.Restore(runner, cr, cc.KubernetesConfig, files); err != nil {
+			exit.WithError("Failed to restore backup", err)
+		}
+		out.T(out.Ready, "Restored the backup, the control plane is restarting")
+		out.T(out.Tip, "Run 'minikube status' to check when the cluster is ready again")
+	},
+}
+
+// checkBackupBootstrapper exits if the bootstrapper keeps its state outside etcd
+func checkBackupBootstrapper() {
+	if b := viper.GetString(cmdcfg.Bootstrapper); b != bootstrapper.BootstrapperTypeKubeadm {
+		exit.WithCodeT(exit.Config, "Backups are not supported by the {{.bootstrapper}} bootstrapper", out.V{"bootstrapper": b})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/perf"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	benchIterations int
+	benchDrivers    []string
+	benchRuntimes   []string
+	benchBaseline   string
+	benchSave       string
+)
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench [-- START FLAGS]",
+	Short: "Measures how long minikube takes to start, stop, restart and delete clusters.",
+	Long: `Measures how long minikube takes to start, stop, restart and delete clusters.
+
+Every iteration creates a cluster with 'minikube start', stops it, starts it again and deletes it, for each
+driver and container runtime. The clusters use their own profiles, bench-DRIVER-RUNTIME, which must not exist.
+The median (p50) and 95th percentile (p95) of each phase are reported, with their change since a --baseline,
+which is the file --save wrote in an earlier run. Flags after -- are passed to every 'minikube start'.
+The output of the commands is written to logs/bench.log in the minikube home directory.`,
+	Example: `minikube bench --iterations 5 --drivers kvm2 --container-runtimes docker,containerd --save baseline.json
+minikube bench --iterations 5 --drivers kvm2 --baseline baseline.json -- --memory 4g`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if benchIterations < 1 {
+			exit.UsageT("--iterations must be at least 1")
+		}
+		var base *perf.Report
+		if benchBaseline != "" {
+			b, err := perf.ReadReport(benchBaseline)
+			if err != nil {
+				exit.WithCodeT(exit.NoInput, "Unable to read the baseline: {{.error}}", out.V{"error": err})
+			}
+			base = b
+		}
+		drivers := benchDrivers
+		if len(drivers) == 0 {
+			drivers = []string{constants.DefaultVMDriver}
+			if d := viper.GetString(vmDriver); d != "" {
+				drivers = []string{d}
+			}
+		}
+		for _, d := range drivers {
+			for _, rt := range benchRuntimes {
+				p := benchProfile(d, rt)
+				if _, err := os.Stat(constants.MakeMiniPath("profiles", p)); err == nil {
+					exit.WithCodeT(exit.Config, "The profile {{.name}} exists: delete it first, as minikube bench deletes its clusters", out.V{"name": p})
+				}
+			}
+		}
+		self, err := os.Executable()
+		if err != nil {
+			exit.WithError("Failed to find the minikube binary", err)
+		}
+		logPath := constants.MakeMiniPath("logs", "bench.log")
+		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+			exit.WithError("Failed to create the logs directory", err)
+		}
+		log, err := os.Create(logPath)
+		if err != nil {
+			exit.WithError("Failed to create the bench log", err)
+		}
+		defer log.Close()
+
+		r := &perf.Report{MinikubeVersion: version.GetVersion()}
+		for _, d := range drivers {
+			for _, rt := range benchRuntimes {
+				for i := 1; i <= benchIterations; i++ {
+					out.T(out.Running, "{{.driver}}/{{.runtime}}: iteration {{.i}} of {{.n}}", out.V{"driver": d, "runtime": rt, "i": i, "n": benchIterations})
+					benchIteration(self, d, rt, args, r, log)
+				}
+			}
+		}
+		if benchSave != "" {
+			if err := perf.WriteReport(benchSave, r); err != nil {
+				exit.WithError("Failed to write the report", err)
+			}
+		}
+
+		deltas := perf.Compare(r, base)
+		if out.IsJSON() {
+			if err := out.JSON(deltas); err != nil {
+				exit.WithError("Error writing timings", err)
+			}
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Driver", "Runtime", "Phase", "Iterations", "p50", "p95", "p50 change", "p95 change"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, d := range deltas {
+			table.Append([]string{
+				d.Driver,
+				d.Runtime,
+				d.Phase,
+				fmt.Sprint(d.Iterations),
+				fmt.Sprintf("%.1fs", d.P50),
+				fmt.Sprintf("%.1fs", d.P95),
+				perf.Change(d.P50, d.BaseP50),
+				perf.Change(d.P95, d.BaseP95),
+			})
+		}
+		table.Render()
+	},
+}
+
+// benchProfile returns the profile of the clusters of a driver and container runtime
+func benchProfile(driver string, runtime string) string {
+	return fmt.Sprintf("bench-%s-%s", driver, runtime)
+}
+
+// benchIteration times each phase of a cluster. When a phase fails, the cluster is deleted.
+func benchIteration(self string, driver string, runtime string, startArgs []string, r *perf.Report, log *os.File) {
+	p := benchProfile(driver, runtime)
+	start := append([]string{"start", "-p", p, "--vm-driver", driver, "--container-runtime", runtime}, startArgs...)
+	args := map[string][]string{
+		"start":   start,
+		"stop":    {"stop", "-p", p},
+		"restart": start,
+		"delete":  {"delete", "-p", p},
+	}
+	for _, phase := range perf.Phases {
+		d, err := perf.Run(self, args[phase], log)
+		if err != nil {
+			if _, err := perf.Run(self, args["delete"], log); err != nil {
+				out.WarningT("Failed to delete {{.name}}: {{.error}}", out.V{"name": p, "error": err})
+			}
+			exit.WithCodeT(exit.Failure, "{{.phase}} of {{.name}} failed: {{.error}}. See {{.log}}", out.V{"phase": phase, "name": p, "error": err, "log": log.Name()})
+		}
+		r.Add(driver, runtime, phase, d)
+		out.T(out.Check, "{{.phase}}: {{.duration}}", out.V{"phase": phase, "duration": d.Round(100 * time.Millisecond)})
+	}
+}
+
+func init() {
+	benchCmd.Flags().IntVar(&benchIterations, "iterations", 3, "How many times each driver and container runtime are measured")
+	benchCmd.Flags().StringSliceVar(&benchDrivers, "drivers", []string{}, "The drivers to measure (default the vm-driver setting)")
+	benchCmd.Flags().StringSliceVar(&benchRuntimes, "container-runtimes", []string{"docker"}, "The container runtimes to measure")
+	benchCmd.Flags().StringVar(&benchBaseline, "baseline", "", "A report written by --save, to which the timings are compared")
+	benchCmd.Flags().StringVar(&benchSave, "save", "", "Write the timings to this JSON file, to be used as a later --baseline")
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleK8sVersion      string
+	bundleISOURL          string
+	bundleImageRepository string
+	bundleImages          []string
+	bundleFile            string
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create or load an archive of everything minikube needs to start offline.",
+	Long:  "Create or load an archive of everything minikube needs to start offline: the ISO, the Kubernetes binaries, and the images of Kubernetes and its addons.",
+}
+
+// createBundleCmd represents the bundle create command
+var createBundleCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Download the files needed to start a cluster, and archive them into a bundle.",
+	Long: `Download the files needed to start a cluster, and archive them into a bundle.
+
+The bundle can be copied to a machine without internet access, and loaded there with 'minikube bundle load'.
+Images added with 'minikube cache add' are included, as are any given with --images.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("usage: minikube bundle create [flags]")
+		}
+		bs := viper.GetString(cmdcfg.Bootstrapper)
+		output := bundleFile
+		if output == "" {
+			output = fmt.Sprintf("minikube-%s-%s.tar.gz", version.GetVersion(), bundleK8sVersion)
+		}
+
+		extra, err := imagesInConfigFile()
+		if err != nil {
+			exit.WithError("Failed to read the image cache list", err)
+		}
+		extra = append(extra, bundleImages...)
+
+		m, err := cacheBundle(bs, extra)
+		if err != nil {
+			exit.WithError("Failed to download the files of the bundle", err)
+		}
+		out.T(out.FileDownload, "Writing {{.count}} files to {{.path}} ...", out.V{"count": len(m.Files), "path": output})
+		if err := bundle.Create(output, constants.GetMinipath(), m); err != nil {
+			exit.WithError("Failed to create bundle", err)
+		}
+		out.T(out.Celebrate, "Created the bundle {{.path}} for Kubernetes {{.version}}", out.V{"path": output, "version": m.KubernetesVersion})
+	},
+}
+
+// loadBundleCmd represents the bundle load command
+var loadBundleCmd = &cobra.Command{
+	Use:   "load FILE",
+	Short: "Load a bundle into the local cache, so that a cluster can be started without internet access.",
+	Long:  "Load a bundle into the local cache, so that a cluster can be started without internet access.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube bundle load FILE")
+		}
+		out.T(out.FileDownload, "Loading {{.path}} into {{.cache}} ...", out.V{"path": args[0], "cache": constants.MakeMiniPath("cache")})
+		m, err := bundle.Load(args[0], constants.GetMinipath())
+		if err != nil {
+			exit.WithError("Failed to load bundle", err)
+		}
+		if m.MinikubeVersion != version.GetVersion() {
+			out.WarningT("The bundle was created by minikube {{.bundle}}, but this is minikube {{.version}}", out.V{"bundle": m.MinikubeVersion, "version": version.GetVersion()})
+		}
+		if len(m.Images) > 0 {
+			if err := cmdcfg.AddToConfigMap(constants.Cache, m.Images); err != nil {
+				exit.WithError("Failed to update config", err)
+			}
+		}
+
+		startArgs := []string{"minikube", "start", "--kubernetes-version=" + m.KubernetesVersion}
+		if m.Bootstrapper != constants.DefaultClusterBootstrapper {
+			startArgs = append(startArgs, "--bootstrapper="+m.Bootstrapper)
+		}
+		if m.ISO != "" && m.ISO != filepath.Base(constants.DefaultISOURL) {
+			iso := filepath.ToSlash(constants.MakeMiniPath("cache", "iso", m.ISO))
+			startArgs = append(startArgs, "--iso-url=file://"+iso)
+		}
+		out.T(out.Ready, "Loaded the bundle. To start a cluster from it, run: {{.command}}", out.V{"command": strings.Join(startArgs, " ")})
+	},
+}
+
+// cacheBundle downloads everything a bundle needs, and returns its manifest
+func cacheBundle(bs string, extraImages []string) (bundle.Manifest, error) {
+	m := bundle.Manifest{
+		MinikubeVersion:   version.GetVersion(),
+		KubernetesVersion: bundleK8sVersion,
+		Bootstrapper:      bs,
+		Images:            extraImages,
+	}
+	home := constants.GetMinipath()
+	add := func(path string) error {
+		rel, err := filepath.Rel(home, path)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, filepath.ToSlash(rel))
+		return nil
+	}
+
+	d := util.DefaultDownloader{}
+	out.T(out.ISODownload, "Downloading {{.url}} ...", out.V{"url": bundleISOURL})
+	if err := d.CacheMinikubeISOFromURL(bundleISOURL); err != nil {
+		return m, err
+	}
+	m.ISO = filepath.Base(bundleISOURL)
+	if err := add(d.GetISOCacheFilepath(bundleISOURL)); err != nil {
+		return m, err
+	}
+
+	out.T(out.FileDownload, "Downloading Kubernetes {{.version}} binaries ...", out.V{"version": bundleK8sVersion})
+	if err := machine.CacheBinariesForBootstrapper(bundleK8sVersion, bs); err != nil {
+		return m, err
+	}
+	for _, bin := range bootstrapper.GetCachedBinaryList(bs) {
+		if err := add(constants.MakeMiniPath("cache", bundleK8sVersion, bin)); err != nil {
+			return m, err
+		}
+	}
+
+	images := append(bootstrapper.GetCachedImageList(bundleImageRepository, bundleK8sVersion, bs), extraImages...)
+	out.T(out.Caching, "Downloading {{.count}} images ...", out.V{"count": len(images)})
+	if err := machine.CacheImages(images, constants.ImageCacheDir); err != nil {
+		return m, err
+	}
+	for _, img := range images {
+		if err := add(machine.ImageCachePath(img)); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+func init() {
+	createBundleCmd.Flags().StringVar(&bundleK8sVersion, "kubernetes-version", constants.DefaultKubernetesVersion, "The kubernetes version to bundle (ex: v1.2.3)")
+	createBundleCmd.Flags().StringVar(&bundleISOURL, "iso-url", constants.DefaultISOURL, "Location of the minikube iso to bundle")
+	createBundleCmd.Flags().StringVar(&bundleImageRepository, "image-repository", "", "Alternative image repository to pull the Kubernetes images from")
+	createBundleCmd.Flags().StringSliceVar(&bundleImages, "images", []string{}, "Additional images to bundle, such as the images used by addons")
+	createBundleCmd.Flags().StringVarP(&bundleFile, "file", "f", "", "Path of the bundle to create (default \"minikube-<version>-<kubernetes-version>.tar.gz\")")
+	bundleCmd.AddCommand(createBundleCmd)
+	bundleCmd.AddCommand(loadBundleCmd)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"path/filepath"
+
+	units "github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var cacheGCDryRun bool
+
+// gcCacheCmd represents the cache gc command
+var gcCacheCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Delete the cached binaries and images of the Kubernetes versions which no profile uses.",
+	Long: `Delete the cached binaries and images of the Kubernetes versions which no profile uses.
+
+The images added with 'minikube cache add' are kept, as well as the images which are not cached for a Kubernetes version.
+The profiles whose config can't be loaded are ignored.`,
+	Example: `minikube cache gc --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles, _, err := config.ListProfiles()
+		if err != nil {
+			exit.WithError("Failed to list profiles", err)
+		}
+		configs := []config.KubernetesConfig{}
+		for _, p := range profiles {
+			configs = append(configs, p.Config.KubernetesConfig)
+		}
+		keep, err := cmdConfig.ListConfigMap(constants.Cache)
+		if err != nil {
+			exit.WithError("Failed to get image map", err)
+		}
+		dir := constants.MakeMiniPath("cache")
+		files, err := machine.UnusedCache(dir, configs, keep)
+		if err != nil {
+			exit.WithError("Failed to list the unused cache", err)
+		}
+		if len(files) == 0 {
+			out.T(out.Empty, "No unused cache")
+			return
+		}
+		var size int64
+		for _, f := range files {
+			rel, err := filepath.Rel(dir, f.Path)
+			if err != nil {
+				rel = f.Path
+			}
+			out.T(out.Empty, "{{.path}} ({{.size}})", out.V{"path": filepath.ToSlash(rel), "size": units.HumanSize(float64(f.Size))})
+			size += f.Size
+		}
+		if cacheGCDryRun {
+			out.T(out.Option, "Would delete {{.count}} files from {{.dir}}, reclaiming {{.size}}", out.V{"count": len(files), "dir": dir, "size": units.HumanSize(float64(size))})
+			return
+		}
+		if err := machine.RemoveCache(files); err != nil {
+			exit.WithError("Failed to delete the unused cache", err)
+		}
+		out.T(out.SuccessType, "Deleted {{.count}} files from {{.dir}}, reclaiming {{.size}}", out.V{"count": len(files), "dir": dir, "size": units.HumanSize(float64(size))})
+	},
+}
+
+func init() {
+	gcCacheCmd.Flags().BoolVar(&cacheGCDryRun, "dry-run", false, "List the files which would be deleted, without deleting them")
+	cacheCmd.AddCommand(gcCacheCmd)
+}
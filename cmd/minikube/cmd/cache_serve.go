@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var cacheServeListen string
+
+// serveCacheCmd represents the cache serve command
+var serveCacheCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the local cache over HTTP, so that other machines download the ISO, binaries and preloads from it.",
+	Long: `Serve the local cache over HTTP, so that other machines download the ISO, binaries and preloads from it.
+
+Other machines use the cache with 'minikube config set download-cache http://HOST:PORT', and download the files
+it doesn't have as usual. The files are served without authentication, so only serve the cache on trusted networks.
+Press Ctrl-C to stop.`,
+	Example: `minikube cache serve --listen 0.0.0.0:8989`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir := constants.MakeMiniPath("cache")
+		l, err := net.Listen("tcp", cacheServeListen)
+		if err != nil {
+			exit.WithError("Failed to listen", err)
+		}
+		out.T(out.Fileserver, "Serving {{.dir}} on {{.address}} ...", out.V{"dir": dir, "address": l.Addr()})
+		out.T(out.Tip, "On the other machines, run: {{.command}}", out.V{"command": "minikube config set download-cache http://" + advertisedAddr(l.Addr().String())})
+		if err := http.Serve(l, download.CacheHandler(dir)); err != nil {
+			exit.WithError("Failed to serve the cache", err)
+		}
+	},
+}
+
+// advertisedAddr returns the address other machines reach a listener on: its host, or the host name when it listens on all interfaces
+func advertisedAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		if host, err = os.Hostname(); err != nil {
+			host = "localhost"
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func init() {
+	serveCacheCmd.Flags().StringVar(&cacheServeListen, "listen", "0.0.0.0:8989", "The address to serve the cache on")
+	cacheCmd.AddCommand(serveCacheCmd)
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/util/validation"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var certsWarnWithin time.Duration
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Check and renew the certificates of the cluster",
+	Long: `Check and renew the certificates of the cluster.
+
+The certificates which kubeadm signs for the control plane are valid for one year. The CAs are valid for ten years,
+and are kept when the certificates are renewed. Only the kubeadm bootstrapper is supported.`,
+}
+
+// certsCheckCmd represents the certs check command
+var certsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Lists when the certificates of the cluster expire",
+	Long: `Lists when the certificates of the VM, and the client certificates of kubectl and of the control plane, expire.
+
+The exit status is 1 if a certificate expires within --warn-within, so that it can be used in scripts.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkCertsBootstrapper()
+		runner, _ := profileRunner()
+		certs := clusterCerts(runner)
+		expiring := bootstrapper.Expiring(certs, time.Now(), certsWarnWithin)
+
+		if out.IsJSON() {
+			if err := out.JSON(certs); err != nil {
+				exit.WithError("Error writing certificates", err)
+			}
+		} else {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"Certificate", "Expires", "Remaining"})
+			table.SetAutoFormatHeaders(false)
+			table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+			table.SetCenterSeparator("|")
+			for _, c := range certs {
+				table.Append([]string{c.Name, c.NotAfter.Local().Format(time.RFC1123), remaining(c.NotAfter, time.Now())})
+			}
+			table.Render()
+		}
+		if len(expiring) > 0 {
+			out.WarningT("{{.count}} certificates expire within {{.duration}}, run 'minikube certs rotate' to renew them", out.V{"count": len(expiring), "duration": certsWarnWithin})
+			os.Exit(exit.Failure)
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Renews the certificates of the cluster, and restarts the control plane",
+	Long: `Renews the certificates which are signed by the CAs of the cluster, and the kubeconfig files of the control plane,
+then restarts the control plane and the kubelet to use them.
+
+The CAs are kept, so that the kubeconfig files of the users stay valid. If the client certificate of kubectl is
+embedded in the kubeconfig, it is embedded again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkCertsBootstrapper()
+		runner, cc := profileRunner()
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		out.T(out.Permissions, "Renewing the certificates of {{.profile}} ...", out.V{"profile": config.GetMachineName()})
+		if err := kubeadm.RotateCerts(runner, cr, cc.KubernetesConfig); err != nil {
+			exit.WithError("Failed to renew certificates", err)
+		}
+		profile := config.GetMachineName()
+		updated, err := pkgutil.UpdateKubeconfigCerts(cmdutil.GetProfileKubeConfigPath(profile), profile, constants.MakeMiniPath("client.crt"), constants.MakeMiniPath("client.key"))
+		if err != nil {
+			exit.WithError("Failed to update kubeconfig", err)
+		}
+		if updated {
+			out.T(out.Option, "Embedded the new client certificate in the kubeconfig")
+		}
+		out.T(out.Ready, "Renewed the certificates, the control plane is restarting")
+		out.T(out.Tip, "Run 'minikube status' to check when the cluster is ready again")
+	},
+}
+
+// certsAddSANCmd represents the certs add-san command
+var certsAddSANCmd = &cobra.Command{
+	Use:   "add-san NAME|IP...",
+	Short: "Adds names or IPs to the serving certificate of the apiserver, and restarts it",
+	Long: `Adds names or IPs to the serving certificate of the apiserver, then restarts the apiserver to use it.
+
+This lets other hosts and VMs reach the apiserver by a name or an address which the certificate did not include,
+without recreating the cluster. The names and IPs are saved in the profile, and kept by the next 'minikube start'.`,
+	Example: `minikube certs add-san minikube.example.com 192.168.1.10`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("usage: minikube certs add-san NAME|IP...")
+		}
+		for _, san := range args {
+			if err := validateSAN(san); err != nil {
+				exit.UsageT("Invalid name or IP {{.san}}: {{.error}}", out.V{"san": san, "error": err})
+			}
+		}
+		checkCertsBootstrapper()
+		runner, cc := profileRunner()
+		added := []string{}
+		for _, san := range args {
+			if !pkgutil.ContainsString(cc.KubernetesConfig.ExtraSANs, san) {
+				cc.KubernetesConfig.ExtraSANs = append(cc.KubernetesConfig.ExtraSANs, san)
+				added = append(added, san)
+			}
+		}
+		if len(added) == 0 {
+			out.T(out.Meh, "The apiserver certificate already includes {{.sans}}", out.V{"sans": strings.Join(args, ", ")})
+			return
+		}
+
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		out.T(out.Permissions, "Adding {{.sans}} to the apiserver certificate ...", out.V{"sans": strings.Join(added, ", ")})
+		if err := kubeadm.UpdateAPIServerCert(runner, cr, cc.KubernetesConfig); err != nil {
+			exit.WithError("Failed to update the apiserver certificate", err)
+		}
+		if err := saveConfig(cc); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+		out.T(out.Ready, "Updated the apiserver certificate, the apiserver is restarting")
+	},
+}
+
+// validateSAN returns an error if san is neither an IP nor a DNS name
+func validateSAN(san string) error {
+	if net.ParseIP(san) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(strings.TrimPrefix(san, "*.")); len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// checkCertsBootstrapper exits if the bootstrapper manages its certificates by itself
+func checkCertsBootstrapper() {
+	if b := viper.GetString(cmdcfg.Bootstrapper); b != bootstrapper.BootstrapperTypeKubeadm {
+		exit.WithCodeT(exit.Config, "Certificates are not managed by minikube with the {{.bootstrapper}} bootstrapper", out.V{"bootstrapper": b})
+	}
+}
+
+// clusterCerts returns when the certificates of the VM and the client certificate of kubectl expire
+func clusterCerts(runner command.Runner) []bootstrapper.CertExpiry {
+	certs, err := bootstrapper.CheckCerts(runner)
+	if err != nil {
+		exit.WithError("Failed to check certificates", err)
+	}
+	client, err := bootstrapper.CheckClientCert()
+	if err != nil {
+		exit.WithError("Failed to check the client certificate", err)
+	}
+	return append(certs, client)
+}
+
+// remaining returns how long until t, in days, or that t is past
+func remaining(t time.Time, now time.Time) string {
+	if !t.After(now) {
+		return "expired"
+	}
+	days := int(t.Sub(now).Hours() / 24)
+	if days == 1 {
+		return "1 day"
+	}
+	return strconv.Itoa(days) + " days"
+}
+
+// warnExpiringCerts warns about the certificates which expire soon, without failing
+func warnExpiringCerts(runner command.Runner) {
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		return
+	}
+	certs, err := bootstrapper.CheckCerts(runner)
+	if err != nil {
+		glog.Warningf("Unable to check certificates: %v", err)
+		return
+	}
+	if client, err := bootstrapper.CheckClientCert(); err == nil {
+		certs = append(certs, client)
+	}
+	expiring := bootstrapper.Expiring(certs, time.Now(), bootstrapper.CertExpiryWarning)
+	if len(expiring) == 0 {
+		return
+	}
+	first := expiring[0]
+	if first.NotAfter.Before(time.Now()) {
+		out.WarningT("The certificate {{.name}} has expired, run 'minikube certs rotate' to renew the certificates", out.V{"name": first.Name})
+		return
+	}
+	out.WarningT("{{.count}} certificates expire in less than {{.days}} days, starting with {{.name}} on {{.date}}: run 'minikube certs rotate' to renew them",
+		out.V{"count": len(expiring), "days": int(bootstrapper.CertExpiryWarning.Hours() / 24), "name": first.Name, "date": first.NotAfter.Local().Format("2006-01-02")})
+}
+
+func init() {
+	certsCheckCmd.Flags().DurationVar(&certsWarnWithin, "warn-within", bootstrapper.CertExpiryWarning, "Report the certificates which expire within this duration, and exit with status 1")
+	certsCmd.AddCommand(certsCheckCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+	certsCmd.AddCommand(certsAddSANCmd)
+}
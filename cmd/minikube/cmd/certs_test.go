@@ -0,0 +1,55 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateSAN(t *testing.T) {
+	tests := []struct {
+		san     string
+		wantErr bool
+	}{
+		{san: "192.168.1.10"},
+		{san: "fd00::10"},
+		{san: "minikube.example.com"},
+		{san: "*.example.com"},
+		{san: "Not A Name", wantErr: true},
+		{san: "-example.com", wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := validateSAN(tc.san); (err != nil) != tc.wantErr {
+			t.Errorf("validateSAN(%q) = %v, want error: %v", tc.san, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	now := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[time.Time]string{
+		now.Add(-time.Hour):          "expired",
+		now.Add(36 * time.Hour):      "1 day",
+		now.Add(30 * 24 * time.Hour): "30 days",
+	}
+	for expiry, want := range tests {
+		if got := remaining(expiry, now); got != want {
+			t.Errorf("remaining(%s) = %q, want %q", expiry, got, want)
+		}
+	}
+}
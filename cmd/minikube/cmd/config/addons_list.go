@@ -25,14 +25,15 @@ import (
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
 )
 
 var addonListFormat string
 
 // AddonListTemplate represents the addon list template
 type AddonListTemplate struct {
-	AddonName   string
-	AddonStatus string
+	AddonName   string `json:"name"`
+	AddonStatus string `json:"status"`
 }
 
 var addonsListCmd = &cobra.Command{
@@ -71,6 +72,18 @@ func addonList() error {
 	}
 	sort.Strings(addonNames)
 
+	if out.IsJSON() {
+		l := []AddonListTemplate{}
+		for _, addonName := range addonNames {
+			addonStatus, err := assets.Addons[addonName].IsEnabled()
+			if err != nil {
+				return err
+			}
+			l = append(l, AddonListTemplate{addonName, stringFromStatus(addonStatus)})
+		}
+		return out.JSON(l)
+	}
+
 	for _, addonName := range addonNames {
 		addonBundle := assets.Addons[addonName]
 		addonStatus, err := addonBundle.IsEnabled()
@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/pkg/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+const (
+	argoCDNamespace      = "argocd"
+	argoCDServerSelector = "app.kubernetes.io/name=argocd-server"
+)
+
+// argoCDAdminPassword returns the initial password of the admin user of Argo CD. argocd-server sets it to the name of
+// its pod on its first start, so it is the name of the oldest argocd-server pod, unless the pods were replaced since.
+func argoCDAdminPassword(client kubernetes.Interface) (string, error) {
+	pods, err := client.CoreV1().Pods(argoCDNamespace).List(meta.ListOptions{LabelSelector: argoCDServerSelector})
+	if err != nil {
+		return "", errors.Wrap(err, "list pods")
+	}
+	if len(pods.Items) == 0 {
+		return "", errors.Errorf("no pod matches %s in namespace %s", argoCDServerSelector, argoCDNamespace)
+	}
+	oldest := pods.Items[0]
+	for _, p := range pods.Items[1:] {
+		if p.CreationTimestamp.Before(&oldest.CreationTimestamp) {
+			oldest = p
+		}
+	}
+	return oldest.Name, nil
+}
+
+// showArgoCDLogin prints how to log in to Argo CD, on stderr so that the output of --url stays a list of URLs
+func showArgoCDLogin() {
+	client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+	if err != nil {
+		out.WarningT("Unable to get the initial password of Argo CD: {{.error}}", out.V{"error": err})
+		return
+	}
+	password, err := argoCDAdminPassword(client)
+	if err != nil {
+		out.WarningT("Unable to get the initial password of Argo CD: {{.error}}", out.V{"error": err})
+		return
+	}
+	out.ErrT(out.Tip, "Log in to Argo CD as admin, with the initial password: {{.password}}", out.V{"password": password})
+}
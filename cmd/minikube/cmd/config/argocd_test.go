@@ -0,0 +1,56 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func argoCDPod(name string, created time.Time, labels map[string]string) *core.Pod {
+	return &core.Pod{ObjectMeta: meta.ObjectMeta{
+		Name:              name,
+		Namespace:         argoCDNamespace,
+		Labels:            labels,
+		CreationTimestamp: meta.NewTime(created),
+	}}
+}
+
+func TestArgoCDAdminPassword(t *testing.T) {
+	now := time.Now()
+	server := map[string]string{"app.kubernetes.io/name": "argocd-server"}
+	client := fake.NewSimpleClientset(
+		argoCDPod("argocd-server-5d9f8c7b6-newer", now, server),
+		argoCDPod("argocd-server-5d9f8c7b6-first", now.Add(-time.Hour), server),
+		argoCDPod("argocd-repo-server-7c9b8d-older", now.Add(-2*time.Hour), map[string]string{"app.kubernetes.io/name": "argocd-repo-server"}),
+	)
+	got, err := argoCDAdminPassword(client)
+	if err != nil {
+		t.Fatalf("argoCDAdminPassword: %v", err)
+	}
+	if got != "argocd-server-5d9f8c7b6-first" {
+		t.Errorf("argoCDAdminPassword() = %q, want the name of the oldest argocd-server pod", got)
+	}
+
+	if _, err := argoCDAdminPassword(fake.NewSimpleClientset()); err == nil {
+		t.Error("argoCDAdminPassword() without pods: expected an error")
+	}
+}
@@ -28,6 +28,16 @@ import (
 // Bootstrapper is the name for bootstrapper
 const Bootstrapper = "bootstrapper"
 
+// ImagePullSecret is the name for the docker config.json which the pull-secrets addon adds to every namespace
+const ImagePullSecret = "image-pull-secret"
+
+const (
+	// RegistryMirror is the name for the comma-separated registry mirrors of Docker Hub
+	RegistryMirror = "registry-mirror"
+	// InsecureRegistry is the name for the comma-separated insecure registries
+	InsecureRegistry = "insecure-registry"
+)
+
 type setFn func(string, string) error
 
 // Setting represents a setting
@@ -70,6 +80,18 @@ var settings = []Setting{
 		validations: []setFn{IsValidDiskSize},
 		callbacks:   []setFn{RequiresRestartMsg},
 	},
+	{
+		name:        RegistryMirror,
+		set:         SetString,
+		validations: []setFn{IsValidRegistryMirrors},
+		callbacks:   []setFn{ApplyRegistries},
+	},
+	{
+		name:        InsecureRegistry,
+		set:         SetString,
+		validations: []setFn{IsValidInsecureRegistries},
+		callbacks:   []setFn{ApplyRegistries},
+	},
 	{
 		name:        "host-only-cidr",
 		set:         SetString,
@@ -111,6 +133,50 @@ var settings = []Setting{
 		name: config.WantReportErrorPrompt,
 		set:  SetBool,
 	},
+	{
+		name:        config.ErrorReportingURL,
+		set:         SetString,
+		validations: []setFn{IsValidURL},
+	},
+	{
+		name: config.WantRemoteProblems,
+		set:  SetBool,
+	},
+	{
+		name:        config.ProblemsURL,
+		set:         SetString,
+		validations: []setFn{IsValidHTTPURL},
+	},
+	{
+		name:        config.AuditMaxSize,
+		set:         SetString,
+		validations: []setFn{IsValidSize},
+	},
+	{
+		name:        config.AuditMaxAge,
+		set:         SetString,
+		validations: []setFn{IsValidDuration},
+	},
+	{
+		name:        constants.DownloadMirrors,
+		set:         SetString,
+		validations: []setFn{IsValidDownloadMirrors},
+	},
+	{
+		name:        constants.DownloadCache,
+		set:         SetString,
+		validations: []setFn{IsValidHTTPURL},
+	},
+	{
+		name:        constants.VerifyDownloads,
+		set:         SetString,
+		validations: []setFn{IsValidVerifyDownloads},
+	},
+	{
+		name:        constants.VerifyDownloadsKey,
+		set:         SetString,
+		validations: []setFn{IsValidPath},
+	},
 	{
 		name: config.WantKubectlDownloadMsg,
 		set:  SetBool,
@@ -124,8 +190,10 @@ var settings = []Setting{
 		set:  SetString,
 	},
 	{
-		name: Bootstrapper,
-		set:  SetString, //TODO(r2d4): more validation here?
+		name:        Bootstrapper,
+		set:         SetString,
+		validations: []setFn{IsValidBootstrapper},
+		callbacks:   []setFn{RequiresRestartMsg},
 	},
 	{
 		name: config.ShowDriverDeprecationNotification,
@@ -177,12 +245,42 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "registry-proxy-cache",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableRegistryProxyCache},
+	},
 	{
 		name:        "registry-creds",
 		set:         SetBool,
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "cert-manager",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "cert-manager-issuer",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "argocd",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "knative",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableKnative},
+	},
 	{
 		name:        "freshpod",
 		set:         SetBool,
@@ -207,6 +305,12 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableStorageClasses},
 	},
+	{
+		name:        "longhorn",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
 	{
 		name:        "metrics-server",
 		set:         SetBool,
@@ -225,6 +329,24 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "nvidia-device-plugin",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "rocm-gpu",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "intel-gpu",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
 	{
 		name:        "logviewer",
 		set:         SetBool,
@@ -236,6 +358,60 @@ var settings = []Setting{
 		validations: []setFn{IsValidAddon, IsContainerdRuntime},
 		callbacks:   []setFn{EnableOrDisableAddon},
 	},
+	{
+		name:        "multus",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon, IsCNIEnabled},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "sriov-sim",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon, IsCNIEnabled},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "kata-containers",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon, IsKataContainersSupported},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "wasm",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon, IsContainerdRuntime},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "loadbalancer",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "dex",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "monitoring",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        "pull-secrets",
+		set:         SetBool,
+		validations: []setFn{IsValidAddon},
+		callbacks:   []setFn{EnableOrDisableAddon},
+	},
+	{
+		name:        ImagePullSecret,
+		set:         SetString,
+		validations: []setFn{IsValidImagePullSecret},
+		callbacks:   []setFn{ApplyImagePullSecret},
+	},
 	{
 		name: "hyperv-virtual-switch",
 		set:  SetString,
@@ -253,6 +429,10 @@ var settings = []Setting{
 		name: "embed-certs",
 		set:  SetBool,
 	},
+	{
+		name: "exec-credential",
+		set:  SetBool,
+	},
 }
 
 // ConfigCmd represents the config command
@@ -148,6 +148,8 @@ var addonsConfigureCmd = &cobra.Command{
 			if err != nil {
 				out.WarningT("ERROR creating `registry-creds-dpr` secret")
 			}
+		case "registry":
+			configureRegistry()
 		default:
 			out.FailureT("{{.name}} has no available configuration options", out.V{"name": addon})
 			return
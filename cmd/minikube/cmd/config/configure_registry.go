@@ -0,0 +1,252 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// registryPort is the port of the registry on the node, published by registry-proxy
+	registryPort = 5000
+	// registryPullSecret is the docker-registry secret which pods can use as imagePullSecrets
+	registryPullSecret = "minikube-registry"
+)
+
+var registryLabels = map[string]string{"kubernetes.io/minikube-addons": "registry"}
+
+// registryHosts returns the addresses of the registry, within the VM and from the host
+func registryHosts(nodeIP string) []string {
+	return []string{fmt.Sprintf("localhost:%d", registryPort), fmt.Sprintf("%s:%d", nodeIP, registryPort)}
+}
+
+// registryCAPaths returns where the minikube CA is copied in the VM for the runtime to trust the registry
+func registryCAPaths(runtime string, hosts []string) []string {
+	switch runtime {
+	case "containerd":
+		// containerd 1.2 has no per-registry CA, and only trusts the certificates of the system
+		return []string{"/etc/ssl/certs/minikube-registry-ca.pem"}
+	case "crio", "cri-o":
+		paths := []string{}
+		for _, h := range hosts {
+			paths = append(paths, path.Join("/etc/containers/certs.d", h, "ca.crt"))
+		}
+		return paths
+	default:
+		paths := []string{}
+		for _, h := range hosts {
+			paths = append(paths, path.Join("/etc/docker/certs.d", h, "ca.crt"))
+		}
+		return paths
+	}
+}
+
+// registryAlternateNames returns the DNS names of the registry service
+func registryAlternateNames(dnsDomain string) []string {
+	return []string{
+		"localhost",
+		"registry",
+		"registry.kube-system",
+		"registry.kube-system.svc",
+		"registry.kube-system.svc." + dnsDomain,
+	}
+}
+
+// htpasswd returns the htpasswd entry of user, with a bcrypt hash, the only one the registry supports
+func htpasswd(user, password string) (string, error) {
+	if user == "" || strings.Contains(user, ":") {
+		return "", fmt.Errorf("invalid user name %q", user)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Wrap(err, "hashing password")
+	}
+	return fmt.Sprintf("%s:%s\n", user, hash), nil
+}
+
+// dockerConfigJSON returns a docker config.json with the credentials of user for every host
+func dockerConfigJSON(hosts []string, user, password string) (string, error) {
+	type authEntry struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	}
+	auths := map[string]authEntry{}
+	for _, h := range hosts {
+		auths[h] = authEntry{
+			Username: user,
+			Password: password,
+			Auth:     base64.StdEncoding.EncodeToString([]byte(user + ":" + password)),
+		}
+	}
+	b, err := json.Marshal(map[string]map[string]authEntry{"auths": auths})
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling docker config")
+	}
+	return string(b), nil
+}
+
+// configureRegistry prompts for the TLS and authentication settings of the registry addon, and applies them
+func configureRegistry() {
+	posResponses := []string{"yes", "y"}
+	negResponses := []string{"no", "n"}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+	cluster.EnsureMinikubeRunningOrExit(api, 0)
+
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		exit.WithError("Error getting host", err)
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.WithError("Error getting command runner", err)
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		exit.WithError("Error getting host IP", err)
+	}
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error loading profile config", err)
+	}
+	hosts := registryHosts(ip)
+
+	if AskForYesNoConfirmation("\nDo you want to enable TLS, with a certificate signed by the minikube CA?", posResponses, negResponses) {
+		if err := configureRegistryTLS(runner, cc.KubernetesConfig, ip, hosts); err != nil {
+			exit.WithError("Error configuring TLS", err)
+		}
+	} else if err := service.DeleteSecret("kube-system", "registry-tls"); err == nil {
+		out.T(out.Option, "Disabled TLS")
+	}
+
+	if AskForYesNoConfirmation("\nDo you want to enable authentication?", posResponses, negResponses) {
+		user := AskForStaticValue("-- Enter registry username: ")
+		password := AskForPasswordValue("-- Enter registry password: ")
+		if err := configureRegistryAuth(hosts, user, password); err != nil {
+			exit.WithError("Error configuring authentication", err)
+		}
+		out.T(out.Tip, "Pods can pull from the registry with the {{.secret}} image pull secret of the default namespace", out.V{"secret": registryPullSecret})
+	} else if err := service.DeleteSecret("kube-system", "registry-auth"); err == nil {
+		out.T(out.Option, "Disabled authentication")
+	}
+
+	// The registry reads its settings on start, and the replication controller recreates it
+	if err := service.DeletePods("kube-system", "actual-registry=true"); err != nil {
+		exit.WithError("Error restarting the registry", err)
+	}
+	out.T(out.Tip, "The registry is available at {{.hosts}}", out.V{"hosts": strings.Join(hosts, " and ")})
+}
+
+// configureRegistryTLS creates the registry-tls secret, and makes the container runtime of the VM trust it
+func configureRegistryTLS(runner command.Runner, k8s config.KubernetesConfig, ip string, hosts []string) error {
+	dir, err := ioutil.TempDir("", "registry-tls")
+	if err != nil {
+		return errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+	ips := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP(ip)}
+	if err := util.GenerateSignedCert(certPath, keyPath, "minikube-registry", ips, registryAlternateNames(k8s.DNSDomain),
+		constants.MakeMiniPath("ca.crt"), constants.MakeMiniPath("ca.key")); err != nil {
+		return errors.Wrap(err, "generating certificate")
+	}
+	crt, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return errors.Wrap(err, "reading certificate")
+	}
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "reading key")
+	}
+	if err := service.CreateSecret("kube-system", "registry-tls", map[string]string{"tls.crt": string(crt), "tls.key": string(key)}, registryLabels); err != nil {
+		return errors.Wrap(err, "creating registry-tls secret")
+	}
+
+	ca, err := ioutil.ReadFile(constants.MakeMiniPath("ca.crt"))
+	if err != nil {
+		return errors.Wrap(err, "reading CA")
+	}
+	for _, p := range registryCAPaths(k8s.ContainerRuntime, hosts) {
+		// A copy in the files directory is synced to the VM on every start
+		local := constants.MakeMiniPath("files", filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+			return errors.Wrapf(err, "mkdir %s", filepath.Dir(local))
+		}
+		if err := ioutil.WriteFile(local, ca, 0644); err != nil {
+			return errors.Wrapf(err, "writing %s", local)
+		}
+		if err := runner.Copy(assets.NewMemoryAssetTarget(ca, p, "0644")); err != nil {
+			return errors.Wrapf(err, "copying %s", p)
+		}
+	}
+	if k8s.ContainerRuntime == "containerd" {
+		// containerd loads the certificates of the system once
+		if err := runner.Run("sudo systemctl restart containerd"); err != nil {
+			return errors.Wrap(err, "restarting containerd")
+		}
+	}
+	out.T(out.Check, "Enabled TLS, trusted by the container runtime of the VM. From the host, trust {{.ca}}", out.V{"ca": constants.MakeMiniPath("ca.crt")})
+	return nil
+}
+
+// configureRegistryAuth creates the registry-auth secret, and a pull secret with the same credentials
+func configureRegistryAuth(hosts []string, user, password string) error {
+	entry, err := htpasswd(user, password)
+	if err != nil {
+		return err
+	}
+	if err := service.CreateSecret("kube-system", "registry-auth", map[string]string{"htpasswd": entry}, registryLabels); err != nil {
+		return errors.Wrap(err, "creating registry-auth secret")
+	}
+	cfg, err := dockerConfigJSON(hosts, user, password)
+	if err != nil {
+		return err
+	}
+	if err := service.CreatePullSecret("default", registryPullSecret, cfg, registryLabels); err != nil {
+		return errors.Wrapf(err, "creating %s secret", registryPullSecret)
+	}
+	out.T(out.Check, "Enabled authentication for {{.user}}", out.V{"user": user})
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHtpasswd(t *testing.T) {
+	entry, err := htpasswd("alice", "s3cret")
+	if err != nil {
+		t.Fatalf("htpasswd: %v", err)
+	}
+	fields := strings.SplitN(strings.TrimSuffix(entry, "\n"), ":", 2)
+	if len(fields) != 2 || fields[0] != "alice" {
+		t.Fatalf("htpasswd() = %q, want an entry for alice", entry)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(fields[1]), []byte("s3cret")); err != nil {
+		t.Errorf("hash does not match the password: %v", err)
+	}
+
+	for _, user := range []string{"", "a:b"} {
+		if _, err := htpasswd(user, "s3cret"); err == nil {
+			t.Errorf("htpasswd(%q) = nil, want error", user)
+		}
+	}
+}
+
+func TestDockerConfigJSON(t *testing.T) {
+	hosts := registryHosts("192.168.39.2")
+	cfg, err := dockerConfigJSON(hosts, "alice", "s3cret")
+	if err != nil {
+		t.Fatalf("dockerConfigJSON: %v", err)
+	}
+	var got struct {
+		Auths map[string]struct {
+			Username string
+			Password string
+			Auth     string
+		}
+	}
+	if err := json.Unmarshal([]byte(cfg), &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.Auths) != 2 {
+		t.Fatalf("auths = %v, want %v", got.Auths, hosts)
+	}
+	want := base64.StdEncoding.EncodeToString([]byte("alice:s3cret"))
+	for _, h := range hosts {
+		if a := got.Auths[h]; a.Username != "alice" || a.Password != "s3cret" || a.Auth != want {
+			t.Errorf("auths[%s] = %+v", h, a)
+		}
+	}
+}
+
+func TestRegistryCAPaths(t *testing.T) {
+	hosts := registryHosts("192.168.39.2")
+	var tests = []struct {
+		runtime string
+		want    []string
+	}{
+		{"", []string{"/etc/docker/certs.d/localhost:5000/ca.crt", "/etc/docker/certs.d/192.168.39.2:5000/ca.crt"}},
+		{"docker", []string{"/etc/docker/certs.d/localhost:5000/ca.crt", "/etc/docker/certs.d/192.168.39.2:5000/ca.crt"}},
+		{"cri-o", []string{"/etc/containers/certs.d/localhost:5000/ca.crt", "/etc/containers/certs.d/192.168.39.2:5000/ca.crt"}},
+		{"containerd", []string{"/etc/ssl/certs/minikube-registry-ca.pem"}},
+	}
+	for _, tc := range tests {
+		if got := registryCAPaths(tc.runtime, hosts); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("registryCAPaths(%q) = %v, want %v", tc.runtime, got, tc.want)
+		}
+	}
+}
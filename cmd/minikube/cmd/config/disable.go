@@ -18,6 +18,7 @@ package config
 
 import (
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/out"
 )
@@ -30,8 +31,15 @@ var addonsDisableCmd = &cobra.Command{
 		if len(args) != 1 {
 			exit.UsageT("usage: minikube addons disable ADDON_NAME")
 		}
+		unlock := LockProfile("addons disable")
+		defer unlock()
 
 		addon := args[0]
+		for _, d := range assets.Dependents(addon) {
+			if enabled, err := isEnabled(d); err == nil && enabled {
+				out.WarningT("{{.dependent}} depends on {{.addonName}}, and will not work until it is enabled again", out.V{"dependent": d, "addonName": addon})
+			}
+		}
 		err := Set(addon, "false")
 		if err != nil {
 			exit.WithError("disable failed", err)
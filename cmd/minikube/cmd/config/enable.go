@@ -17,7 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/out"
 )
@@ -30,16 +33,65 @@ var addonsEnableCmd = &cobra.Command{
 		if len(args) != 1 {
 			exit.UsageT("usage: minikube addons enable ADDON_NAME")
 		}
+		unlock := LockProfile("addons enable")
+		defer unlock()
 
 		addon := args[0]
-		err := Set(addon, "true")
+		deps, err := assets.Dependencies(addon)
+		if err != nil {
+			exit.WithError("enable failed", err)
+		}
+		if err := checkConflicts(append(deps, addon), isEnabled); err != nil {
+			exit.WithCodeT(exit.Config, "Unable to enable {{.addonName}}: {{.error}}", out.V{"addonName": addon, "error": err})
+		}
+		for _, d := range deps {
+			enabled, err := isEnabled(d)
+			if err != nil {
+				exit.WithError("enable failed", err)
+			}
+			if enabled {
+				continue
+			}
+			out.T(out.Enabling, "Enabling {{.dependency}}, which {{.addonName}} depends on", out.V{"dependency": d, "addonName": addon})
+			if err := Set(d, "true"); err != nil {
+				exit.WithError("enable failed", err)
+			}
+		}
+		err = Set(addon, "true")
 		if err != nil {
 			exit.WithError("enable failed", err)
 		}
 		out.SuccessT("{{.addonName}} was successfully enabled", out.V{"addonName": addon})
+		// The addon is enabled, but its images are still being pulled: the pulls stop if minikube exits
+		waitForAddonPulls()
 	},
 }
 
 func init() {
 	AddonsCmd.AddCommand(addonsEnableCmd)
 }
+
+// isEnabled returns whether the addon name is enabled
+func isEnabled(name string) (bool, error) {
+	a, ok := assets.Addons[name]
+	if !ok {
+		return false, fmt.Errorf("%s is not a valid addon", name)
+	}
+	return a.IsEnabled()
+}
+
+// checkConflicts returns an error if one of names conflicts with an enabled addon
+func checkConflicts(names []string, enabled func(string) (bool, error)) error {
+	for _, name := range names {
+		for _, c := range assets.Conflicts(name) {
+			e, err := enabled(c)
+			if err != nil {
+				return err
+			}
+			if e {
+				return fmt.Errorf("%s conflicts with the enabled addon %s, disable it first with 'minikube addons disable %s'", name, c, c)
+			}
+		}
+	}
+	return nil
+}
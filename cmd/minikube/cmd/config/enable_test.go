@@ -16,10 +16,31 @@ limitations under the License.
 
 package config
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestEnableUnknownAddon(t *testing.T) {
 	if err := Set("InvalidAddon", "false"); err == nil {
 		t.Fatalf("Enable did not return error for unknown addon")
 	}
 }
+
+func TestCheckConflicts(t *testing.T) {
+	enabled := func(name string) (bool, error) {
+		switch name {
+		case "nvidia-gpu-device-plugin":
+			return true, nil
+		case "nvidia-device-plugin":
+			return false, nil
+		}
+		return false, errors.New("unexpected addon " + name)
+	}
+	if err := checkConflicts([]string{"dashboard", "nvidia-device-plugin"}, enabled); err == nil {
+		t.Errorf("checkConflicts() = nil, want a conflict with nvidia-gpu-device-plugin")
+	}
+	if err := checkConflicts([]string{"nvidia-driver-installer", "nvidia-gpu-device-plugin"}, enabled); err != nil {
+		t.Errorf("checkConflicts() = %v, want nil", err)
+	}
+}
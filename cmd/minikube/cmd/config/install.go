@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var addonsInstallCmd = &cobra.Command{
+	Use:   "install SOURCE",
+	Short: "Installs a third-party addon from an OCI artifact, a URL or a file",
+	Long: `Installs a third-party addon from an OCI artifact, a URL or a file. The addon can then be enabled and disabled like the addons bundled with minikube.
+
+An addon bundle is a tarball, optionally gzipped, with an addon.yaml file and the manifests of the addon, with no subdirectories:
+
+  name: my-addon
+  description: Tools of my team
+  images:
+  - registry.example.com/tools:v1
+
+Manifests ending in .tmpl are templates, like the ones of the bundled addons. The images are cached on install, and loaded when the addon is enabled.
+From an OCI registry, the bundle is the single layer of the artifact, for example: minikube addons install registry.example.com/addons/my-addon:v1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube addons install SOURCE")
+		}
+		src := args[0]
+		r, err := openAddonBundle(src)
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Unable to fetch the addon bundle {{.source}}: {{.error}}", out.V{"source": src, "error": err})
+		}
+		defer r.Close()
+		m, err := assets.InstallAddon(r)
+		if err != nil {
+			exit.WithCodeT(exit.Data, "Unable to install the addon from {{.source}}: {{.error}}", out.V{"source": src, "error": err})
+		}
+		if len(m.Images) > 0 {
+			out.T(out.Caching, "Caching the images of {{.addonName}} ...", out.V{"addonName": m.Name})
+			if err := machine.CacheImages(m.Images, constants.ImageCacheDir); err != nil {
+				exit.WithError("Failed to cache images", err)
+			}
+		}
+		out.SuccessT("{{.addonName}} was successfully installed, enable it with: minikube addons enable {{.addonName}}", out.V{"addonName": m.Name})
+	},
+}
+
+var addonsUninstallCmd = &cobra.Command{
+	Use:   "uninstall ADDON_NAME",
+	Short: "Uninstalls an addon installed with 'minikube addons install'",
+	Long:  "Uninstalls an addon installed with 'minikube addons install'. The addon must be disabled first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube addons uninstall ADDON_NAME")
+		}
+		addon := args[0]
+		if enabled, err := isEnabled(addon); err == nil && enabled {
+			exit.WithCodeT(exit.Config, "{{.addonName}} is enabled, disable it first with: minikube addons disable {{.addonName}}", out.V{"addonName": addon})
+		}
+		if err := assets.UninstallAddon(addon); err != nil {
+			exit.WithCodeT(exit.Config, "Unable to uninstall {{.addonName}}: {{.error}}", out.V{"addonName": addon, "error": err})
+		}
+		out.SuccessT("{{.addonName}} was successfully uninstalled", out.V{"addonName": addon})
+	},
+}
+
+// openAddonBundle returns the uncompressed tarball of an addon bundle from a URL, a file or an OCI reference
+func openAddonBundle(src string) (io.ReadCloser, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		resp, err := http.Get(src)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+		}
+		return gunzipped(resp.Body)
+	}
+	if _, err := os.Stat(src); err == nil {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		return gunzipped(f)
+	}
+
+	ref, err := name.ParseReference(src, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrap(err, "not a URL, a file or an OCI reference")
+	}
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching OCI artifact")
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "layers")
+	}
+	if len(layers) != 1 {
+		return nil, fmt.Errorf("the artifact has %d layers, an addon bundle has one", len(layers))
+	}
+	return layers[0].Uncompressed()
+}
+
+// readCloser closes c after reading from r
+type readCloser struct {
+	io.Reader
+	c io.Closer
+}
+
+func (r readCloser) Close() error {
+	return r.c.Close()
+}
+
+// gunzipped decompresses rc if it is gzipped
+func gunzipped(rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	magic, err := br.Peek(2)
+	if err != nil {
+		rc.Close()
+		return nil, errors.Wrap(err, "reading bundle")
+	}
+	if magic[0] != 0x1f || magic[1] != 0x8b {
+		return readCloser{br, rc}, nil
+	}
+	gr, err := gzip.NewReader(br)
+	if err != nil {
+		rc.Close()
+		return nil, errors.Wrap(err, "gzip")
+	}
+	return readCloser{gr, rc}, nil
+}
+
+func init() {
+	AddonsCmd.AddCommand(addonsInstallCmd)
+	AddonsCmd.AddCommand(addonsUninstallCmd)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilwait "k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+const (
+	knativeSelector = "kubernetes.io/minikube-addons=knative"
+	// knativeTimeout includes the minute the addon manager may take to apply the manifests, and pulling the images
+	knativeTimeout  = 5 * time.Minute
+	knativeInterval = 2 * time.Second
+)
+
+// knativeNotReady returns the deployments of Knative Serving and Kourier which are not available yet,
+// or all of them when the addon manager has not created them yet
+func knativeNotReady(client kubernetes.Interface) ([]string, error) {
+	deployments, err := client.AppsV1().Deployments(meta.NamespaceAll).List(meta.ListOptions{LabelSelector: knativeSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "list deployments")
+	}
+	if len(deployments.Items) == 0 {
+		return []string{"knative-serving", "kourier-system"}, nil
+	}
+	notReady := []string{}
+	for _, d := range deployments.Items {
+		if d.Spec.Replicas != nil && d.Status.AvailableReplicas < *d.Spec.Replicas {
+			notReady = append(notReady, d.Namespace+"/"+d.Name)
+		}
+	}
+	return notReady, nil
+}
+
+// EnableOrDisableKnative enables or disables the knative addon. Once enabled, it waits for Knative Serving and Kourier
+// to be ready, and shows the domain of the routes.
+func EnableOrDisableKnative(name, val string) error {
+	if err := EnableOrDisableAddon(name, val); err != nil {
+		return err
+	}
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if !enable {
+		return nil
+	}
+
+	client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+	if err != nil {
+		return errors.Wrap(err, "k8s client")
+	}
+	out.T(out.Waiting, "Waiting for Knative Serving and Kourier to be ready ...")
+	var notReady []string
+	err = utilwait.PollImmediate(knativeInterval, knativeTimeout, func() (bool, error) {
+		nr, err := knativeNotReady(client)
+		if err != nil {
+			return false, err
+		}
+		notReady = nr
+		return len(nr) == 0, nil
+	})
+	if err == utilwait.ErrWaitTimeout {
+		out.WarningT("Knative is not ready yet, waiting for {{.deployments}}: check with 'kubectl get pods -n knative-serving' and 'kubectl get pods -n kourier-system'", out.V{"deployments": strings.Join(notReady, ", ")})
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "waiting for knative")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, "loading config")
+	}
+	out.T(out.Ready, "Knative is ready: services are served at http://NAME.NAMESPACE.{{.domain}}", out.V{"domain": cfg.KubernetesConfig.NodeIP + ".nip.io"})
+	return nil
+}
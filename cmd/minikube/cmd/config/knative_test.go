@@ -0,0 +1,85 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func knativeDeployment(namespace, name string, available int32) *apps.Deployment {
+	replicas := int32(1)
+	return &apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"kubernetes.io/minikube-addons": "knative"},
+		},
+		Spec:   apps.DeploymentSpec{Replicas: &replicas},
+		Status: apps.DeploymentStatus{AvailableReplicas: available},
+	}
+}
+
+func TestKnativeNotReady(t *testing.T) {
+	var tests = []struct {
+		description string
+		deployments []*apps.Deployment
+		want        []string
+	}{
+		{
+			description: "not created yet",
+			want:        []string{"knative-serving", "kourier-system"},
+		},
+		{
+			description: "starting",
+			deployments: []*apps.Deployment{
+				knativeDeployment("knative-serving", "controller", 1),
+				knativeDeployment("kourier-system", "3scale-kourier-gateway", 0),
+			},
+			want: []string{"kourier-system/3scale-kourier-gateway"},
+		},
+		{
+			description: "ready",
+			deployments: []*apps.Deployment{
+				knativeDeployment("knative-serving", "controller", 1),
+				knativeDeployment("kourier-system", "3scale-kourier-gateway", 1),
+			},
+			want: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, d := range test.deployments {
+				if _, err := client.AppsV1().Deployments(d.Namespace).Create(d); err != nil {
+					t.Fatalf("create: %v", err)
+				}
+			}
+			got, err := knativeNotReady(client)
+			if err != nil {
+				t.Fatalf("knativeNotReady: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("knativeNotReady() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
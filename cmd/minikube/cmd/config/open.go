@@ -20,6 +20,7 @@ import (
 	"text/template"
 
 	"github.com/spf13/cobra"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -80,12 +81,12 @@ To enable this addon run:
 minikube addons enable {{.name}}`, out.V{"name": addonName})
 		}
 
-		namespace := "kube-system"
 		key := "kubernetes.io/minikube-addons-endpoint"
 
-		serviceList, err := service.GetServiceListByLabel(namespace, key, addonName)
+		// Most addons run in kube-system, but some have a namespace of their own
+		serviceList, err := service.GetServiceListByLabel(meta.NamespaceAll, key, addonName)
 		if err != nil {
-			exit.WithCodeT(exit.Unavailable, "Error getting service with namespace: {{.namespace}} and labels {{.labelName}}:{{.addonName}}: {{.error}}", out.V{"namespace": namespace, "labelName": key, "addonName": addonName, "error": err})
+			exit.WithCodeT(exit.Unavailable, "Error getting services with labels {{.labelName}}:{{.addonName}}: {{.error}}", out.V{"labelName": key, "addonName": addonName, "error": err})
 		}
 		if len(serviceList.Items) == 0 {
 			exit.WithCodeT(exit.Config, `This addon does not have an endpoint defined for the 'addons open' command.
@@ -93,10 +94,14 @@ You can add one by annotating a service with the label {{.labelName}}:{{.addonNa
 		}
 		for i := range serviceList.Items {
 			svc := serviceList.Items[i].ObjectMeta.Name
+			namespace := serviceList.Items[i].ObjectMeta.Namespace
 			if err := service.WaitAndMaybeOpenService(api, namespace, svc, addonsURLTemplate, addonsURLMode, https, wait, interval); err != nil {
 				exit.WithCodeT(exit.Unavailable, "Wait failed: {{.error}}", out.V{"error": err})
 			}
 		}
+		if addonName == "argocd" {
+			showArgoCDLogin()
+		}
 	},
 }
 
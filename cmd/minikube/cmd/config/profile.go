@@ -58,7 +58,9 @@ var ProfileCmd = &cobra.Command{
 			out.ErrT(out.Sad, `Error loading profile config: {{.error}}`, out.V{"error": err})
 		}
 		if err == nil {
-			if cc.MachineConfig.KeepContext {
+			if cc.MachineConfig.KubeconfigPerProfile {
+				out.T(out.Kubectl, "To connect to this cluster, use: export KUBECONFIG={{.path}}", out.V{"path": constants.MakeProfileKubeconfigPath(profile)})
+			} else if cc.MachineConfig.KeepContext {
 				out.SuccessT("Skipped switching kubectl context for {{.profile_name}} , because --keep-context", out.V{"profile_name": profile})
 				out.SuccessT("To connect to this cluster, use: kubectl --context={{.profile_name}}", out.V{"profile_name": profile})
 			} else {
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/proxy"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	profileExportFile string
+	profileImportName string
+)
+
+var profileExportCmd = &cobra.Command{
+	Use:   "export [MINIKUBE_PROFILE_NAME]",
+	Short: "Writes the config of a profile to a YAML file, which can be imported with 'minikube profile import'.",
+	Long: `Writes the config of a profile to a YAML file, which can be imported with 'minikube profile import'.
+
+The file describes the cluster: its driver, container runtime, resources, extra-config, mount and addons.
+Proxy settings of the host, and the IP and UUID of the VM, are left out. The current profile is exported if no name is given.`,
+	Example: `minikube profile export dev -f dev.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.UsageT("usage: minikube profile export [MINIKUBE_PROFILE_NAME] [-f FILE]")
+		}
+		name := pkgConfig.GetMachineName()
+		if len(args) == 1 {
+			name = args[0]
+		}
+		cc, err := pkgConfig.DefaultLoader.LoadConfigFromFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				exit.WithCodeT(exit.NoInput, "Profile {{.name}} does not exist", out.V{"name": name})
+			}
+			exit.WithError("Error loading profile config", err)
+		}
+		addons, err := exportedAddons()
+		if err != nil {
+			exit.WithError("Error reading addons config", err)
+		}
+		p := pkgConfig.ProfileExport{
+			Name:            name,
+			MinikubeVersion: version.GetVersion(),
+			Addons:          addons,
+			Config:          exportedConfig(*cc),
+		}
+
+		var w io.Writer = os.Stdout
+		if profileExportFile != "" {
+			f, err := os.Create(profileExportFile)
+			if err != nil {
+				exit.WithError("Failed to create the export file", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := pkgConfig.WriteProfileExport(w, p); err != nil {
+			exit.WithError("Failed to write the profile export", err)
+		}
+		if profileExportFile != "" {
+			out.T(out.SuccessType, "Exported profile {{.name}} to {{.path}}", out.V{"name": name, "path": profileExportFile})
+		}
+	},
+}
+
+var profileImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Creates a profile from a file written by 'minikube profile export'.",
+	Long: `Creates a profile from a file written by 'minikube profile export'.
+
+The cluster is created by the next 'minikube start -p NAME', with the imported config. Flags given to start take precedence over it.
+The addons of the file are set in the minikube config, which is shared by all profiles.`,
+	Example: `minikube profile import dev.yaml --name dev2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube profile import FILE [--name NAME]")
+		}
+		p, err := pkgConfig.ReadProfileExport(args[0])
+		if err != nil {
+			if os.IsNotExist(err) {
+				exit.WithCodeT(exit.NoInput, "Cannot find {{.path}}", out.V{"path": args[0]})
+			}
+			exit.WithCodeT(exit.Data, "Unable to load {{.path}}: {{.error}}", out.V{"path": args[0], "error": err})
+		}
+		name := p.Name
+		if profileImportName != "" {
+			name = profileImportName
+		}
+		if name == "" {
+			exit.UsageT("The file has no profile name, please specify one with --name")
+		}
+		if p.MinikubeVersion != "" && p.MinikubeVersion != version.GetVersion() {
+			out.WarningT("{{.path}} was exported by minikube {{.exported}}, and is imported by {{.version}}", out.V{"path": args[0], "exported": p.MinikubeVersion, "version": version.GetVersion()})
+		}
+		if err := pkgConfig.CreateProfile(name, p.Config); err != nil {
+			exit.WithError("Failed to import profile", err)
+		}
+		if err := importAddons(p.Addons); err != nil {
+			exit.WithError("Failed to import addons", err)
+		}
+		out.T(out.Ready, "Imported profile {{.name}}. To create the cluster, run: minikube start -p {{.name}}", out.V{"name": name})
+	},
+}
+
+// exportedAddons returns the addons which are enabled or disabled in the minikube config
+func exportedAddons() (map[string]bool, error) {
+	m, err := pkgConfig.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	addons := map[string]bool{}
+	for name := range assets.Addons {
+		v, ok := m[name]
+		if !ok {
+			continue
+		}
+		enabled, err := strconv.ParseBool(fmt.Sprint(v))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		addons[name] = enabled
+	}
+	return addons, nil
+}
+
+// exportedConfig leaves out of a profile config what only makes sense on this host, or for this VM
+func exportedConfig(cc pkgConfig.Config) pkgConfig.Config {
+	var env []string
+	for _, e := range cc.MachineConfig.DockerEnv {
+		if !isProxyEnv(e) {
+			env = append(env, e)
+		}
+	}
+	cc.MachineConfig.DockerEnv = env
+	cc.MachineConfig.UUID = ""
+	cc.KubernetesConfig.NodeIP = ""
+	return cc
+}
+
+// isProxyEnv returns whether a KEY=VALUE entry sets one of the proxy variables
+func isProxyEnv(e string) bool {
+	key := strings.SplitN(e, "=", 2)[0]
+	for _, v := range proxy.EnvVars {
+		if key == v {
+			return true
+		}
+	}
+	return false
+}
+
+// importAddons sets the addons in the minikube config. The addons are deployed by the next start,
+// so the callbacks of 'minikube addons enable', which need a running cluster, are not run.
+func importAddons(addons map[string]bool) error {
+	if len(addons) == 0 {
+		return nil
+	}
+	m, err := pkgConfig.ReadConfig()
+	if err != nil {
+		return err
+	}
+	names := []string{}
+	for name := range addons {
+		if err := IsValidAddon(name, ""); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m[name] = addons[name]
+		out.T(out.Enabling, "{{.name}} addon: {{.enabled}}", out.V{"name": name, "enabled": addons[name]})
+	}
+	return pkgConfig.WriteConfig(constants.ConfigFile, m)
+}
+
+func init() {
+	profileExportCmd.Flags().StringVarP(&profileExportFile, "file", "f", "", "The file to write the profile to (default: stdout)")
+	profileImportCmd.Flags().StringVar(&profileImportName, "name", "", "The name of the new profile (default: the name in the file)")
+	ProfileCmd.AddCommand(profileExportCmd)
+	ProfileCmd.AddCommand(profileImportCmd)
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestExportedConfig(t *testing.T) {
+	cc := pkgConfig.Config{
+		MachineConfig: pkgConfig.MachineConfig{
+			VMDriver:  "hyperkit",
+			UUID:      "3b5e4a34-0000-0000-0000-000000000000",
+			DockerEnv: []string{"HTTP_PROXY=http://proxy:3128", "FOO=bar", "no_proxy=localhost"},
+		},
+		KubernetesConfig: pkgConfig.KubernetesConfig{
+			KubernetesVersion: "v1.15.2",
+			NodeIP:            "192.168.64.2",
+		},
+	}
+	got := exportedConfig(cc)
+	if !reflect.DeepEqual(got.MachineConfig.DockerEnv, []string{"FOO=bar"}) {
+		t.Errorf("DockerEnv = %v, want [FOO=bar]", got.MachineConfig.DockerEnv)
+	}
+	if got.MachineConfig.UUID != "" || got.KubernetesConfig.NodeIP != "" {
+		t.Errorf("UUID %q and NodeIP %q were exported", got.MachineConfig.UUID, got.KubernetesConfig.NodeIP)
+	}
+	if got.MachineConfig.VMDriver != "hyperkit" || got.KubernetesConfig.KubernetesVersion != "v1.15.2" {
+		t.Errorf("exportedConfig(%+v) = %+v", cc, got)
+	}
+}
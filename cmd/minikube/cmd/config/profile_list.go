@@ -29,11 +29,30 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// ProfileListJSON is the output of 'minikube profile list --output=json'
+type ProfileListJSON struct {
+	Valid   []ProfileJSON `json:"valid"`
+	Invalid []ProfileJSON `json:"invalid"`
+}
+
+// ProfileJSON describes a profile in the output of 'minikube profile list --output=json'
+type ProfileJSON struct {
+	Name              string `json:"name"`
+	VMDriver          string `json:"vmDriver,omitempty"`
+	NodeIP            string `json:"nodeIP,omitempty"`
+	NodePort          int    `json:"nodePort,omitempty"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+}
+
 var profileListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "Lists all minikube profiles.",
 	Long:  "Lists all valid minikube profiles and detects all possible invalid profiles.",
 	Run: func(cmd *cobra.Command, args []string) {
+		if out.IsJSON() {
+			profileListJSON()
+			return
+		}
 
 		var validData [][]string
 
@@ -71,6 +90,29 @@ var profileListCmd = &cobra.Command{
 	},
 }
 
+func profileListJSON() {
+	validProfiles, invalidProfiles, err := config.ListProfiles()
+	if err != nil && !os.IsNotExist(err) {
+		exit.WithCodeT(exit.Config, "error loading profiles: {{.error}}", out.V{"error": err})
+	}
+	l := ProfileListJSON{Valid: []ProfileJSON{}, Invalid: []ProfileJSON{}}
+	for _, p := range validProfiles {
+		l.Valid = append(l.Valid, ProfileJSON{
+			Name:              p.Name,
+			VMDriver:          p.Config.MachineConfig.VMDriver,
+			NodeIP:            p.Config.KubernetesConfig.NodeIP,
+			NodePort:          p.Config.KubernetesConfig.NodePort,
+			KubernetesVersion: p.Config.KubernetesConfig.KubernetesVersion,
+		})
+	}
+	for _, p := range invalidProfiles {
+		l.Invalid = append(l.Invalid, ProfileJSON{Name: p.Name})
+	}
+	if err := out.JSON(l); err != nil {
+		exit.WithError("Error writing profiles", err)
+	}
+}
+
 func init() {
 	ProfileCmd.AddCommand(profileListCmd)
 }
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+const (
+	// PullSecretsAddon is the addon which copies the image pull secret to every namespace
+	PullSecretsAddon = "pull-secrets"
+	// pullSecretName is the name of the image pull secret, in kube-system and in the namespaces it is copied to
+	pullSecretName = "minikube-image-pull-secret"
+)
+
+var pullSecretLabels = map[string]string{"kubernetes.io/minikube-addons": PullSecretsAddon}
+
+// ApplyImagePullSecret creates the image pull secret in the running cluster, and adds it to every namespace.
+// A cluster which is stopped or not created yet gets it on the next start.
+func ApplyImagePullSecret(name, val string) error {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		glog.Infof("Not applying %s: %v", name, err)
+		out.T(out.Tip, "The image pull secret will be added by the next 'minikube start'")
+		return nil
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return errors.Wrap(err, "host state")
+	}
+	if s != state.Running {
+		out.T(out.Tip, "The image pull secret will be added by the next 'minikube start'")
+		return nil
+	}
+	if err := CreateImagePullSecret(val); err != nil {
+		return err
+	}
+	// The setting of the addon is saved by the next start: Set would overwrite it with the config it read before
+	// running this callback
+	enabled, err := assets.Addons[PullSecretsAddon].IsEnabled()
+	if err != nil {
+		return errors.Wrap(err, "addon status")
+	}
+	if !enabled {
+		return EnableOrDisableAddon(PullSecretsAddon, "true")
+	}
+	return nil
+}
+
+// CreateImagePullSecret creates the image pull secret of kube-system from a docker config.json, which the
+// pull-secrets addon copies to the other namespaces
+func CreateImagePullSecret(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "reading docker config")
+	}
+	if err := service.CreatePullSecret("kube-system", pullSecretName, string(b), pullSecretLabels); err != nil {
+		return errors.Wrapf(err, "creating %s secret", pullSecretName)
+	}
+	out.T(out.Tip, "The default service account of every namespace pulls images with the credentials of {{.path}}", out.V{"path": path})
+	return nil
+}
@@ -17,17 +17,27 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/audit"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -55,6 +65,15 @@ func findSetting(name string) (Setting, error) {
 			return s, nil
 		}
 	}
+	// Installed addons are only known at runtime
+	if a, ok := assets.Addons[name]; ok && a.Installed() {
+		return Setting{
+			name:        name,
+			set:         SetBool,
+			validations: []setFn{IsValidAddon},
+			callbacks:   []setFn{EnableOrDisableAddon},
+		}, nil
+	}
 	return Setting{}, fmt.Errorf("property name %q not found", name)
 }
 
@@ -133,8 +152,79 @@ func EnableOrDisableAddon(name string, val string) error {
 		exit.WithCodeT(exit.Data, "Unable to load config: {{.error}}", out.V{"error": err})
 	}
 
+	// With "none", images are pulled by the docker daemon of the host
+	if enable && len(addon.Images) > 0 && cfg.MachineConfig.VMDriver != constants.DriverNone {
+		if err := machine.CacheImages(addon.Images, constants.ImageCacheDir); err != nil {
+			return errors.Wrap(err, "caching addon images")
+		}
+		if err := machine.LoadImages(cmd, addon.Images, constants.ImageCacheDir); err != nil {
+			return errors.Wrap(err, "loading addon images")
+		}
+	}
+
 	data := assets.GenerateTemplateData(cfg.KubernetesConfig)
-	return enableOrDisableAddonInternal(addon, cmd, data, enable)
+	if err := enableOrDisableAddonInternal(addon, cmd, data, enable); err != nil {
+		return err
+	}
+	if enable && cfg.MachineConfig.VMDriver != constants.DriverNone {
+		startAddonPulls(addon, cmd, cfg.KubernetesConfig.ContainerRuntime, data)
+	}
+	return nil
+}
+
+// pulls tracks the pulls of addon images started by startAddonPulls
+var pulls sync.WaitGroup
+
+// startAddonPulls pulls, in the background, the images of an addon which the container runtime does not have yet, while
+// the addon manager applies its manifests, so that its pods start without waiting for the kubelet to pull the images one
+// at a time. Failures are only warnings, as the kubelet pulls the images which are still missing.
+func startAddonPulls(addon *assets.Addon, cmd command.Runner, runtime string, data interface{}) {
+	images, err := addon.PullImages(data)
+	if err != nil {
+		glog.Warningf("images of %s: %v", addon.Name(), err)
+		return
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: runtime, Runner: cmd})
+	if err != nil {
+		glog.Warningf("runtime: %v", err)
+		return
+	}
+	pulls.Add(1)
+	go func() {
+		defer pulls.Done()
+		missing := missingImages(images, cr)
+		for i, img := range missing {
+			out.T(out.Pulling, "Pulling {{.image}} ({{.i}} of {{.n}}) ...", out.V{"image": img, "i": i + 1, "n": len(missing)})
+			if err := cr.PullImage(img); err != nil {
+				out.WarningT("Unable to pull {{.image}}: {{.error}}", out.V{"image": img, "error": err})
+			}
+		}
+	}()
+}
+
+// waitForAddonPulls waits for the pulls started by startAddonPulls, which stop with the minikube process
+func waitForAddonPulls() {
+	pulls.Wait()
+}
+
+// missingImages returns the images which the container runtime does not have, comparing fully qualified references,
+// as Docker lists the images of Docker Hub by their short names and containerd and cri-o by their full ones
+func missingImages(images []string, cr cruntime.Manager) []string {
+	present := map[string]bool{}
+	if existing, err := cr.ListImages(); err != nil {
+		glog.Warningf("list images: %v", err)
+	} else {
+		for _, img := range existing {
+			present[cruntime.NormalizeImage(img)] = true
+		}
+	}
+	missing := []string{}
+	for _, img := range images {
+		if !present[cruntime.NormalizeImage(img)] {
+			missing = append(missing, img)
+		}
+	}
+	return missing
 }
 
 func isAddonAlreadySet(addon *assets.Addon, enable bool) error {
@@ -226,3 +316,229 @@ func EnableOrDisableStorageClasses(name, val string) error {
 
 	return EnableOrDisableAddon(name, val)
 }
+
+// EnableOrDisableRegistryProxyCache enables or disables the registry-proxy-cache addon, and its mirror of Docker Hub
+func EnableOrDisableRegistryProxyCache(name, val string) error {
+	if err := EnableOrDisableAddon(name, val); err != nil {
+		return err
+	}
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	// Without the cache, the container runtime falls back to Docker Hub, until /etc is reset by the next start
+	if !enable {
+		return nil
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+	host, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "getting host")
+	}
+	cmd, err := machine.CommandRunner(host)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return errors.Wrap(err, "loading config")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: cmd})
+	if err != nil {
+		return errors.Wrap(err, "container runtime")
+	}
+	return ConfigureRegistries(cr, *cfg, true)
+}
+
+// ConfigureRegistries configures the registry mirrors and insecure registries of the container runtime,
+// with the mirror of the registry-proxy-cache addon first if proxyCache is set
+func ConfigureRegistries(cr cruntime.Manager, cfg config.Config, proxyCache bool) error {
+	// dockerd gets the registries of the config as flags from the provisioner
+	if cr.Name() == "Docker" {
+		if !proxyCache {
+			return nil
+		}
+		// dockerd refuses to start with registry mirrors both in its flags and in daemon.json
+		if len(cfg.MachineConfig.RegistryMirror) > 0 {
+			out.WarningT("Docker Hub is not mirrored by registry-proxy-cache, since --registry-mirror is set")
+			return nil
+		}
+		out.T(out.Option, "Mirroring Docker Hub with {{.url}}", out.V{"url": constants.RegistryProxyCacheURL})
+		return cr.ConfigureRegistries(cruntime.Registries{Mirrors: []string{constants.RegistryProxyCacheURL}})
+	}
+
+	r := cruntime.Registries{Mirrors: cfg.MachineConfig.RegistryMirror, Insecure: cfg.MachineConfig.InsecureRegistry}
+	if proxyCache {
+		out.T(out.Option, "Mirroring Docker Hub with {{.url}}", out.V{"url": constants.RegistryProxyCacheURL})
+		r.Mirrors = append([]string{constants.RegistryProxyCacheURL}, r.Mirrors...)
+	}
+	if len(r.Mirrors) == 0 && len(r.Insecure) == 0 {
+		return nil
+	}
+	return cr.ConfigureRegistries(r)
+}
+
+// SplitList returns the values of a comma-separated setting
+func SplitList(val string) []string {
+	list := []string{}
+	for _, v := range strings.Split(val, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// ListSetting returns the values of a comma-separated setting of the minikube config file
+func ListSetting(name string) []string {
+	val, err := config.Get(name)
+	if err != nil {
+		return nil
+	}
+	return SplitList(val)
+}
+
+// ApplyRegistries applies the registry-mirror or insecure-registry setting to the cluster of the profile.
+// Without a cluster, the setting is used by the next 'minikube start'.
+func ApplyRegistries(name, val string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		glog.Infof("Not applying %s: %v", name, err)
+		return nil
+	}
+	switch name {
+	case RegistryMirror:
+		cfg.MachineConfig.RegistryMirror = SplitList(val)
+	case InsecureRegistry:
+		cfg.MachineConfig.InsecureRegistry = SplitList(val)
+	}
+	if err := saveProfileConfig(cfg); err != nil {
+		return errors.Wrap(err, "saving config")
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return errors.Wrap(err, "machine client")
+	}
+	defer api.Close()
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		return errors.Wrap(err, "getting host")
+	}
+	if err := cluster.SetRegistries(api, h, cfg.MachineConfig); err != nil {
+		return errors.Wrap(err, "saving host")
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return errors.Wrap(err, "host state")
+	}
+	if s != state.Running {
+		out.T(out.Tip, "The registries will be configured by the next 'minikube start'")
+		return nil
+	}
+
+	out.T(out.Restarting, "Restarting the {{.runtime}} container runtime with the new registries ...", out.V{"runtime": cfg.KubernetesConfig.ContainerRuntime})
+	cmd, err := machine.CommandRunner(h)
+	if err != nil {
+		return errors.Wrap(err, "command runner")
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cfg.KubernetesConfig.ContainerRuntime, Runner: cmd})
+	if err != nil {
+		return errors.Wrap(err, "container runtime")
+	}
+	if cr.Name() == "Docker" {
+		// Provisioning rewrites the flags of dockerd, and restarts it
+		return h.ConfigureAuth()
+	}
+	proxyCache, err := assets.Addons["registry-proxy-cache"].IsEnabled()
+	if err != nil {
+		return errors.Wrap(err, "registry-proxy-cache")
+	}
+	return ConfigureRegistries(cr, *cfg, proxyCache)
+}
+
+// saveProfileConfig writes the config of the profile
+func saveProfileConfig(cfg *config.Config) error {
+	data, err := json.MarshalIndent(cfg, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(constants.GetProfileFile(config.GetMachineName()), data, 0600)
+}
+
+// LockProfile acquires the lock of the current profile for a command which changes its cluster, and exits
+// if another command holds it. The command is recorded in the audit log. It returns the function which releases the lock.
+func LockProfile(command string) func() {
+	profile := config.GetMachineName()
+	l, err := config.LockProfile(profile, command)
+	if err != nil {
+		if busy, ok := err.(*config.ProfileBusyError); ok {
+			exit.WithCodeT(exit.Unavailable, `Profile "{{.name}}" is busy: 'minikube {{.command}}' (pid {{.pid}}) is running. Try again once it is done, or remove {{.path}} if it is no longer running.`,
+				out.V{"name": profile, "command": busy.Holder.Command, "pid": busy.Holder.Pid, "path": busy.Path})
+		}
+		exit.WithError("Failed to lock the profile", err)
+	}
+	completed := auditCommand(profile, command)
+	return func() {
+		completed()
+		if err := l.Unlock(); err != nil {
+			glog.Warningf("unlocking profile %s: %v", profile, err)
+		}
+	}
+}
+
+// auditCommand records in the audit log that a command started, and that it failed if it exits with an error.
+// It returns the function which records that the command completed.
+func auditCommand(profile string, command string) func() {
+	done := false
+	record := func(event string, msg string) {
+		if !done {
+			LogAudit(profile, command, event, msg)
+		}
+	}
+	record(audit.Started, "")
+	exit.AtExit(func(code int, msg string) {
+		record(audit.Failed, msg)
+		done = true
+	})
+	return func() {
+		record(audit.Completed, "")
+		done = true
+	}
+}
+
+// LogAudit records an event of a command in the audit log, which is rotated as configured
+func LogAudit(profile string, command string, event string, msg string) {
+	e := audit.NewEntry(profile, command, event)
+	e.Message = msg
+	if err := audit.Log(e, AuditPolicy()); err != nil {
+		glog.Warningf("Unable to write the audit log: %v", err)
+	}
+}
+
+// AuditPolicy returns the retention policy of the audit log, as configured with audit-max-size and audit-max-age
+func AuditPolicy() audit.Policy {
+	p := audit.DefaultPolicy
+	if s := viper.GetString(config.AuditMaxSize); s != "" {
+		size, err := units.FromHumanSize(s)
+		if err != nil {
+			glog.Warningf("invalid %s %q: %v", config.AuditMaxSize, s, err)
+		} else {
+			p.MaxSize = size
+		}
+	}
+	if s := viper.GetString(config.AuditMaxAge); s != "" {
+		age, err := time.ParseDuration(s)
+		if err != nil {
+			glog.Warningf("invalid %s %q: %v", config.AuditMaxAge, s, err)
+		} else {
+			p.MaxAge = age
+		}
+	}
+	return p
+}
@@ -19,9 +19,11 @@ package config
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"k8s.io/minikube/pkg/minikube/assets"
 	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 )
 
 var minikubeConfig = pkgConfig.MinikubeConfig{
@@ -111,3 +113,36 @@ func TestIsAddonAlreadySet(t *testing.T) {
 		}
 	}
 }
+
+// crictlRunner is a cruntime.CommandRunner which lists the given images with crictl
+type crictlRunner struct {
+	images string
+}
+
+func (c crictlRunner) CombinedOutput(cmd string) (string, error) {
+	if cmd == "sudo crictl images -o json" {
+		return c.images, nil
+	}
+	return "", nil
+}
+
+func (c crictlRunner) Run(cmd string) error {
+	return nil
+}
+
+func TestMissingImages(t *testing.T) {
+	runner := crictlRunner{images: `{"images":[` +
+		`{"repoTags":["docker.io/library/nginx:1.17"]},` +
+		`{"repoTags":["docker.io/kubernetesui/dashboard:v2.0.0"]},` +
+		`{"repoTags":["k8s.gcr.io/pause:3.1"]}]}`}
+	cr, err := cruntime.New(cruntime.Config{Type: "containerd", Runner: runner})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	images := []string{"nginx:1.17", "kubernetesui/dashboard:v2.0.0", "k8s.gcr.io/pause:3.1", "nginx:1.18", "busybox"}
+	got := missingImages(images, cr)
+	want := []string{"nginx:1.18", "busybox"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("missingImages() returned diff (-want +got):\n%s", diff)
+	}
+}
@@ -17,19 +17,25 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -43,6 +49,26 @@ and then start minikube again with the following flags:
 
 minikube start --container-runtime=containerd --docker-opt containerd=/var/run/containerd/containerd.sock`
 
+// kataAddonMsg is the message shown when kata-containers is enabled on a cluster which can't run it
+const kataAddonMsg = `
+The kata-containers addon runs pods in VMs nested in the minikube VM, with the containerd or cri-o runtime. Please first delete minikube with:
+
+minikube delete
+
+and then start minikube again with the following flags, on a host with nested virtualization enabled:
+
+minikube start --vm-driver=kvm2 --container-runtime=containerd`
+
+// cniOnlyAddonMsg is the message shown when an addon which needs CNI is enabled on a cluster which does not use it
+const cniOnlyAddonMsg = `
+This addon can only be enabled on a cluster using CNI. Please first stop minikube with:
+
+minikube stop
+
+and then start minikube again with the following flags:
+
+minikube start --network-plugin=cni --enable-default-cni`
+
 // IsValidDriver checks if a driver is supported
 func IsValidDriver(string, driver string) error {
 	for _, d := range constants.SupportedVMDrivers {
@@ -53,6 +79,15 @@ func IsValidDriver(string, driver string) error {
 	return fmt.Errorf("driver %q is not supported", driver)
 }
 
+// IsValidBootstrapper checks if a bootstrapper is supported
+func IsValidBootstrapper(_ string, name string) error {
+	switch name {
+	case bootstrapper.BootstrapperTypeKubeadm, bootstrapper.BootstrapperTypeK3s, bootstrapper.BootstrapperTypeK0s:
+		return nil
+	}
+	return fmt.Errorf("bootstrapper %q is not supported", name)
+}
+
 // RequiresRestartMsg returns the "requires restart" message
 func RequiresRestartMsg(string, string) error {
 	out.T(out.WarningType, "These changes will take effect upon a minikube delete and then a minikube start")
@@ -68,6 +103,26 @@ func IsValidDiskSize(name string, disksize string) error {
 	return nil
 }
 
+// IsValidSize checks if a string is a valid size, such as 10MB. 0 disables the limit.
+func IsValidSize(name string, size string) error {
+	if _, err := units.FromHumanSize(size); err != nil {
+		return fmt.Errorf("%s: invalid size: %v", name, err)
+	}
+	return nil
+}
+
+// IsValidDuration checks if a string is a valid duration, such as 720h. 0 disables the limit.
+func IsValidDuration(name string, d string) error {
+	v, err := time.ParseDuration(d)
+	if err != nil {
+		return fmt.Errorf("%s: invalid duration: %v", name, err)
+	}
+	if v < 0 {
+		return fmt.Errorf("%s must not be negative", name)
+	}
+	return nil
+}
+
 // IsValidURL checks if a location is a valid URL
 func IsValidURL(name string, location string) error {
 	_, err := url.Parse(location)
@@ -132,6 +187,60 @@ func IsValidCIDR(name string, cidr string) error {
 	return nil
 }
 
+// IsValidRegistryMirrors checks if a string is a comma-separated list of registry mirror URLs, such as http://localhost:5000
+func IsValidRegistryMirrors(name string, val string) error {
+	for _, m := range SplitList(val) {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || strings.Trim(u.Path, "/") != "" {
+			return fmt.Errorf("%s is not a valid registry mirror URL", m)
+		}
+	}
+	return nil
+}
+
+// IsValidHTTPURL checks if a string is an http or https URL, which may have a path
+func IsValidHTTPURL(name string, val string) error {
+	u, err := url.Parse(val)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s is not a valid http or https URL", val)
+	}
+	return nil
+}
+
+// IsValidDownloadMirrors checks if a string is a comma-separated list of http or https URLs, which may have a path
+func IsValidDownloadMirrors(name string, val string) error {
+	for _, m := range SplitList(val) {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s is not a valid download mirror URL", m)
+		}
+	}
+	return nil
+}
+
+// IsValidVerifyDownloads checks if a string is a mode of the verification of the signatures of downloads
+func IsValidVerifyDownloads(name string, val string) error {
+	for _, m := range download.VerifyModes {
+		if val == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not a valid value of %s, use one of %s", val, name, strings.Join(download.VerifyModes, ", "))
+}
+
+// IsValidInsecureRegistries checks if a string is a comma-separated list of registries, as host[:port] or CIDR
+func IsValidInsecureRegistries(name string, val string) error {
+	for _, r := range SplitList(val) {
+		if _, _, err := net.ParseCIDR(r); err == nil {
+			continue
+		}
+		if u, err := url.Parse("//" + r); err != nil || u.Host != r {
+			return fmt.Errorf("%s is not a valid registry: use host[:port] or a CIDR", r)
+		}
+	}
+	return nil
+}
+
 // IsValidPath checks if a string is a valid path
 func IsValidPath(name string, path string) error {
 	_, err := os.Stat(path)
@@ -141,6 +250,24 @@ func IsValidPath(name string, path string) error {
 	return nil
 }
 
+// IsValidImagePullSecret checks if a string is the absolute path of a docker config.json with credentials, as written by docker login
+func IsValidImagePullSecret(name string, path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%s must be an absolute path", name)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%s path is not valid: %v", name, err)
+	}
+	var dockerConfig struct {
+		Auths map[string]json.RawMessage `json:"auths"`
+	}
+	if err := json.Unmarshal(b, &dockerConfig); err != nil || len(dockerConfig.Auths) == 0 {
+		return fmt.Errorf("%s has no registry credentials: it should be a docker config.json, with an auths section", path)
+	}
+	return nil
+}
+
 // IsValidAddon checks if a string is a valid addon
 func IsValidAddon(name string, val string) error {
 	if _, ok := assets.Addons[name]; ok {
@@ -165,3 +292,41 @@ func IsContainerdRuntime(_, _ string) error {
 	}
 	return nil
 }
+
+// IsCNIEnabled is a validator which returns an error unless the current profile uses CNI
+func IsCNIEnabled(_, _ string) error {
+	config, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("config.Load: %v", err)
+	}
+	if config.KubernetesConfig.NetworkPlugin != "cni" {
+		return fmt.Errorf(cniOnlyAddonMsg)
+	}
+	return nil
+}
+
+// IsKataContainersSupported is a validator which returns an error unless the driver and runtime of the current profile can run kata containers
+func IsKataContainersSupported(_, _ string) error {
+	config, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("config.Load: %v", err)
+	}
+	return checkKataContainers(config.MachineConfig.VMDriver, config.KubernetesConfig.ContainerRuntime)
+}
+
+// checkKataContainers returns an error unless the driver passes the virtualization extensions through to the VM,
+// and the runtime supports runtime handlers
+func checkKataContainers(driver string, runtime string) error {
+	if driver != constants.DriverKvm2 && driver != constants.DriverQemu2 {
+		return fmt.Errorf(kataAddonMsg)
+	}
+	r, err := cruntime.New(cruntime.Config{Type: runtime})
+	if err != nil {
+		return err
+	}
+	switch r.(type) {
+	case *cruntime.Containerd, *cruntime.CRIO:
+		return nil
+	}
+	return fmt.Errorf(kataAddonMsg)
+}
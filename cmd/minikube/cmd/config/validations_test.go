@@ -17,7 +17,9 @@ limitations under the License.
 package config
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -55,6 +57,48 @@ func TestDriver(t *testing.T) {
 
 }
 
+func TestBootstrapper(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "kubeadm",
+			shouldErr: false,
+		},
+		{
+			value:     "k3s",
+			shouldErr: false,
+		},
+		{
+			value:     "k0s",
+			shouldErr: false,
+		},
+		{
+			value:     "localkube",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "bootstrapper", IsValidBootstrapper)
+}
+
+func TestCheckKataContainers(t *testing.T) {
+	var tests = []struct {
+		driver  string
+		runtime string
+		wantErr bool
+	}{
+		{"kvm2", "containerd", false},
+		{"qemu2", "crio", false},
+		{"kvm2", "docker", true},
+		{"virtualbox", "containerd", true},
+		{"none", "containerd", true},
+	}
+	for _, tc := range tests {
+		if err := checkKataContainers(tc.driver, tc.runtime); (err != nil) != tc.wantErr {
+			t.Errorf("checkKataContainers(%q, %q) = %v, want error: %v", tc.driver, tc.runtime, err, tc.wantErr)
+		}
+	}
+}
+
 func TestValidCIDR(t *testing.T) {
 	var tests = []validationTest{
 		{
@@ -98,6 +142,57 @@ func TestValidCIDR(t *testing.T) {
 	runValidations(t, tests, "cidr", IsValidCIDR)
 }
 
+func TestValidRegistryMirrors(t *testing.T) {
+	var tests = []validationTest{
+		{value: "http://localhost:5000", shouldErr: false},
+		{value: "https://mirror.gcr.io, http://10.0.0.5:5000/", shouldErr: false},
+		{value: "localhost:5000", shouldErr: true},
+		{value: "https://mirror.gcr.io/v2", shouldErr: true},
+		{value: "ftp://mirror.gcr.io", shouldErr: true},
+	}
+	runValidations(t, tests, "registry-mirror", IsValidRegistryMirrors)
+}
+
+func TestValidDownloadMirrors(t *testing.T) {
+	var tests = []validationTest{
+		{value: "https://mirror.example.com", shouldErr: false},
+		{value: "https://mirror.example.com/cache/,http://10.0.0.5:8080", shouldErr: false},
+		{value: "mirror.example.com", shouldErr: true},
+		{value: "ftp://mirror.example.com", shouldErr: true},
+	}
+	runValidations(t, tests, "download-mirrors", IsValidDownloadMirrors)
+}
+
+func TestValidHTTPURL(t *testing.T) {
+	var tests = []validationTest{
+		{value: "http://ci-cache.example.com:8989", shouldErr: false},
+		{value: "https://10.0.0.5/minikube", shouldErr: false},
+		{value: "ci-cache.example.com:8989", shouldErr: true},
+		{value: "", shouldErr: true},
+	}
+	runValidations(t, tests, "download-cache", IsValidHTTPURL)
+}
+
+func TestValidVerifyDownloads(t *testing.T) {
+	var tests = []validationTest{
+		{value: "strict", shouldErr: false},
+		{value: "off", shouldErr: false},
+		{value: "", shouldErr: true},
+		{value: "yes", shouldErr: true},
+	}
+	runValidations(t, tests, "verify-downloads", IsValidVerifyDownloads)
+}
+
+func TestValidInsecureRegistries(t *testing.T) {
+	var tests = []validationTest{
+		{value: "10.0.0.5:5000", shouldErr: false},
+		{value: "registry.local,10.96.0.0/12", shouldErr: false},
+		{value: "http://registry.local", shouldErr: true},
+		{value: "registry.local/path", shouldErr: true},
+	}
+	runValidations(t, tests, "insecure-registry", IsValidInsecureRegistries)
+}
+
 func TestIsURLExists(t *testing.T) {
 
 	self, err := os.Executable()
@@ -119,3 +214,48 @@ func TestIsURLExists(t *testing.T) {
 
 	runValidations(t, tests, "url", IsURLExists)
 }
+
+func TestValidSize(t *testing.T) {
+	var tests = []validationTest{
+		{value: "10MB", shouldErr: false},
+		{value: "0", shouldErr: false},
+		{value: "ten", shouldErr: true},
+	}
+	runValidations(t, tests, "audit-max-size", IsValidSize)
+}
+
+func TestValidDuration(t *testing.T) {
+	var tests = []validationTest{
+		{value: "720h", shouldErr: false},
+		{value: "0", shouldErr: false},
+		{value: "-1h", shouldErr: true},
+		{value: "30d", shouldErr: true},
+	}
+	runValidations(t, tests, "audit-max-age", IsValidDuration)
+}
+
+func TestValidImagePullSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pull-secret")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"config.json": `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`,
+		"empty.json":  `{"auths":{}}`,
+		"other.json":  `{"credsStore":"desktop"}`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	var tests = []validationTest{
+		{value: filepath.Join(dir, "config.json"), shouldErr: false},
+		{value: filepath.Join(dir, "empty.json"), shouldErr: true},
+		{value: filepath.Join(dir, "other.json"), shouldErr: true},
+		{value: filepath.Join(dir, "missing.json"), shouldErr: true},
+		{value: "config.json", shouldErr: true},
+	}
+	runValidations(t, tests, "image-pull-secret", IsValidImagePullSecret)
+}
@@ -0,0 +1,421 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh/terminal"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/console"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/events"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var consoleInterval time.Duration
+
+// consoleEvents is how many of the recent events of the selected profile the console keeps
+const consoleEvents = 50
+
+// The escape sequences which hide and show the cursor of the terminal
+const (
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+)
+
+// consoleCmd represents the console command
+var consoleCmd = &cobra.Command{
+	Use:   "console",
+	Short: "Shows the profiles, and the health and the events of the selected cluster, in a terminal UI",
+	Long: `Shows the profiles, and the health and the events of the selected cluster, in a terminal UI.
+
+The console lists the profiles with the state of their host. For the selected profile, it shows the status of the kubelet
+and of the API server, the health of the nodes and of the enabled addons, and the recent events, as listed by 'minikube events'.
+The screen is refreshed every --interval.
+
+Keys:
+  up/down, k/j  select a profile
+  s             stop the selected profile, after confirmation
+  o             open a service of the selected profile in the browser, as with 'minikube service'
+  l             tail the logs of the selected profile, as with 'minikube logs --follow', until Ctrl-C
+  r             refresh now
+  q, Ctrl-C     quit`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("usage: minikube console")
+		}
+		if out.IsJSON() {
+			exit.UsageT("minikube console does not support --output=json: use 'minikube status --watch' instead")
+		}
+		fd := int(os.Stdin.Fd())
+		if !terminal.IsTerminal(fd) || !terminal.IsTerminal(int(os.Stdout.Fd())) {
+			exit.UsageT("minikube console needs a terminal")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+
+		ui := &consoleUI{fd: fd, profile: config.GetMachineName(), keys: make(chan console.Key)}
+		if err := ui.run(api); err != nil {
+			exit.WithError("Console failed", err)
+		}
+	},
+}
+
+// consoleUI is the state of a console
+type consoleUI struct {
+	fd int
+	// raw is the state of the terminal before it was put in raw mode
+	raw *terminal.State
+	// profile is the name of the selected profile
+	profile string
+	screen  console.Screen
+	keys    chan console.Key
+	// prompt is the question of the status line, answered by calling answer with the input
+	prompt string
+	input  []rune
+	answer func(string)
+	// stale means that the screen should be refreshed right away
+	stale bool
+}
+
+// run shows the console until it is quit
+func (ui *consoleUI) run(api libmachine.API) error {
+	raw, err := terminal.MakeRaw(ui.fd)
+	if err != nil {
+		return errors.Wrap(err, "raw mode")
+	}
+	ui.raw = raw
+	fmt.Print(console.ClearScreen + hideCursor)
+	defer func() {
+		fmt.Print(console.ClearScreen + showCursor)
+		if err := terminal.Restore(ui.fd, ui.raw); err != nil {
+			glog.Warningf("restore terminal: %v", err)
+		}
+	}()
+
+	go ui.readKeys()
+	screens := make(chan console.Screen)
+	refreshing := false
+	refresh := func() {
+		if refreshing {
+			ui.stale = true
+			return
+		}
+		refreshing = true
+		ui.stale = false
+		go func(profile string) { screens <- consoleScreen(api, profile) }(ui.profile)
+	}
+	refresh()
+	tick := time.NewTicker(consoleInterval)
+	defer tick.Stop()
+	for {
+		ui.draw()
+		select {
+		case s := <-screens:
+			refreshing = false
+			if ui.stale {
+				// Another profile was selected during the refresh
+				refresh()
+				break
+			}
+			if ui.screen.Status != "" && s.Status == "" {
+				s.Status = ui.screen.Status
+			}
+			ui.screen = s
+			if len(s.Profiles) > 0 {
+				ui.profile = s.Profiles[s.Selected].Name
+			}
+		case <-tick.C:
+			refresh()
+		case k, ok := <-ui.keys:
+			if !ok || ui.handle(k) {
+				return nil
+			}
+			if ui.stale {
+				ui.stale = false
+				refresh()
+			}
+		}
+	}
+}
+
+// readKeys sends the keys which are pressed, until the input is closed
+func (ui *consoleUI) readKeys() {
+	defer close(ui.keys)
+	r := bufio.NewReader(os.Stdin)
+	for {
+		k, err := console.ReadKey(r)
+		if err != nil {
+			glog.Infof("read key: %v", err)
+			return
+		}
+		ui.keys <- k
+	}
+}
+
+// draw draws the screen, with the prompt if there is one
+func (ui *consoleUI) draw() {
+	width, height, err := terminal.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+	s := ui.screen
+	if ui.answer != nil {
+		s.Status = ui.prompt + string(ui.input)
+	}
+	if err := console.Render(os.Stdout, s, width, height); err != nil {
+		glog.Warningf("render: %v", err)
+	}
+}
+
+// handle handles a key, and returns whether to quit
+func (ui *consoleUI) handle(k console.Key) bool {
+	if ui.answer != nil {
+		ui.edit(k)
+		return false
+	}
+	if k.Code == console.KeyInterrupt {
+		return true
+	}
+	action := k.Rune
+	switch k.Code {
+	case console.KeyUp:
+		action = 'k'
+	case console.KeyDown:
+		action = 'j'
+	case console.KeyRune:
+	default:
+		return false
+	}
+
+	ui.screen.Status = ""
+	switch action {
+	case 'q':
+		return true
+	case 'r':
+		ui.stale = true
+	case 'k':
+		ui.selectProfile(-1)
+	case 'j':
+		ui.selectProfile(1)
+	}
+	if len(ui.screen.Profiles) == 0 {
+		return false
+	}
+	profile := ui.profile
+	switch action {
+	case 's':
+		ui.ask(fmt.Sprintf("Stop %s? [y/n]: ", profile), func(a string) {
+			if strings.HasPrefix(strings.ToLower(a), "y") {
+				ui.runCommand(profile, "stop")
+			}
+		})
+	case 'o':
+		ui.ask("Service to open ([NAMESPACE/]NAME): ", func(a string) {
+			if a == "" {
+				return
+			}
+			ns, name := "default", a
+			if i := strings.Index(a, "/"); i > 0 {
+				ns, name = a[:i], a[i+1:]
+			}
+			ui.runCommand(profile, "service", "--namespace", ns, name)
+		})
+	case 'l':
+		ui.runCommand(profile, "logs", "--follow")
+	}
+	return false
+}
+
+// selectProfile moves the selection by delta profiles. The health of the cluster is shown once it is refreshed.
+func (ui *consoleUI) selectProfile(delta int) {
+	s := &ui.screen
+	i := s.Selected + delta
+	if i < 0 || i >= len(s.Profiles) {
+		return
+	}
+	s.Selected = i
+	ui.profile = s.Profiles[i].Name
+	s.Kubelet, s.APIServer, s.Nodes, s.Addons, s.Events = "", "", nil, nil, nil
+	ui.stale = true
+}
+
+// ask shows a prompt in the status line, and calls answer with the input once Enter is pressed. Escape cancels it.
+func (ui *consoleUI) ask(prompt string, answer func(string)) {
+	ui.prompt = prompt
+	ui.input = nil
+	ui.answer = answer
+}
+
+// edit handles a key pressed at a prompt
+func (ui *consoleUI) edit(k console.Key) {
+	switch k.Code {
+	case console.KeyRune:
+		ui.input = append(ui.input, k.Rune)
+	case console.KeyBackspace:
+		if len(ui.input) > 0 {
+			ui.input = ui.input[:len(ui.input)-1]
+		}
+	case console.KeyEscape, console.KeyInterrupt:
+		ui.answer = nil
+	case console.KeyEnter:
+		answer := ui.answer
+		ui.answer = nil
+		answer(strings.TrimSpace(string(ui.input)))
+	}
+}
+
+// runCommand runs a minikube command for a profile in the terminal, outside of the console, and returns to the console
+// once a key is pressed. Ctrl-C stops the command, but not the console.
+func (ui *consoleUI) runCommand(profile string, args ...string) {
+	self, err := os.Executable()
+	if err != nil {
+		ui.screen.Status = fmt.Sprintf("Unable to find the minikube binary: %v", err)
+		return
+	}
+	if err := terminal.Restore(ui.fd, ui.raw); err != nil {
+		glog.Warningf("restore terminal: %v", err)
+	}
+	fmt.Print(console.ClearScreen + showCursor)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	c := exec.Command(self, append([]string{"--profile", profile}, args...)...)
+	// The console keeps reading the keys: the commands need no input
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	err = c.Run()
+	signal.Stop(sigs)
+
+	ui.screen.Status = fmt.Sprintf("minikube %s: done", strings.Join(args, " "))
+	if err != nil {
+		ui.screen.Status = fmt.Sprintf("minikube %s: %v", strings.Join(args, " "), err)
+	}
+	fmt.Print("\nPress any key to return to the console")
+	if raw, err := terminal.MakeRaw(ui.fd); err != nil {
+		glog.Warningf("raw mode: %v", err)
+	} else {
+		ui.raw = raw
+	}
+	<-ui.keys
+	fmt.Print(console.ClearScreen + hideCursor)
+	ui.stale = true
+}
+
+// consoleScreen returns the profiles with the state of their host, and the health and the recent events of the cluster
+// of the selected profile, or of the first one if it no longer exists
+func consoleScreen(api libmachine.API, selected string) console.Screen {
+	s := console.Screen{Updated: time.Now()}
+	valid, _, err := config.ListProfiles()
+	if err != nil && !os.IsNotExist(err) {
+		s.Status = fmt.Sprintf("Unable to list the profiles: %v", err)
+	}
+	for _, p := range valid {
+		viper.Set(config.MachineProfile, p.Name)
+		host, err := cluster.GetHostStatus(api)
+		if err != nil {
+			glog.Warningf("%s host status: %v", p.Name, err)
+			host = "Error"
+		}
+		if p.Name == selected {
+			s.Selected = len(s.Profiles)
+		}
+		s.Profiles = append(s.Profiles, console.Profile{
+			Name:              p.Name,
+			Host:              host,
+			Driver:            p.Config.MachineConfig.VMDriver,
+			KubernetesVersion: p.Config.KubernetesConfig.KubernetesVersion,
+		})
+	}
+	if len(s.Profiles) == 0 {
+		return s
+	}
+
+	p := s.Profiles[s.Selected]
+	viper.Set(config.MachineProfile, p.Name)
+	running := p.Host == state.Running.String()
+	if running {
+		status, _, err := getStatus(api)
+		if err != nil {
+			s.Status = fmt.Sprintf("Unable to get the status of %s: %v", p.Name, err)
+		}
+		s.Kubelet, s.APIServer = status.Kubelet, status.APIServer
+		for _, n := range status.Nodes {
+			s.Nodes = append(s.Nodes, console.Item{Name: n.Name, Status: n.Kubelet, Message: n.Message, OK: n.Kubelet == "Ready"})
+		}
+		for _, a := range status.Addons {
+			i := console.Item{Name: a.Name, Status: "Ready", Message: a.Message, OK: a.Ready}
+			if !a.Ready {
+				i.Status = "NotReady"
+			}
+			s.Addons = append(s.Addons, i)
+		}
+	}
+	s.Events = recentEvents(p.Name, running)
+	return s
+}
+
+// recentEvents returns the latest events of a profile, with the events of its cluster if it is running
+func recentEvents(profile string, running bool) []events.Event {
+	entries, err := audit.Read()
+	if err != nil {
+		glog.Warningf("audit log: %v", err)
+	}
+	list := profileEvents(profile, entries)
+	if running {
+		if client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout); err != nil {
+			glog.Warningf("kubernetes client: %v", err)
+		} else if l, err := client.CoreV1().Events("").List(meta.ListOptions{}); err != nil {
+			glog.Warningf("list events: %v", err)
+		} else {
+			for _, e := range l.Items {
+				list = append(list, events.FromKubernetes(e))
+			}
+		}
+	}
+	events.Sort(list)
+	if len(list) > consoleEvents {
+		list = list[len(list)-consoleEvents:]
+	}
+	return list
+}
+
+func init() {
+	consoleCmd.Flags().DurationVar(&consoleInterval, "interval", 5*time.Second, "How often the screen is refreshed")
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var cpNode string
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp SOURCE TARGET",
+	Short: "Copies files and directories between the host and a node of minikube.",
+	Long: `Copies files and directories between the host and a node of minikube.
+
+Paths of the node are absolute, and prefixed by the name of the node, as in 'minikube:/etc/hosts', or selected by --node.
+The prefix of the source copies from the node to the host, otherwise files are copied from the host to the node.
+The source may be a pattern, such as '*.yaml', and directories are copied recursively. As with cp, if the target is
+a directory, or ends with '/', the sources are copied into it.`,
+	Example: `minikube cp ./manifests /etc/kubernetes/manifests
+minikube cp 'logs/*.log' minikube:/var/log/app/
+minikube cp minikube:/var/log/pods ./pod-logs`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.UsageT("usage: minikube cp SOURCE TARGET")
+		}
+		srcNode, src := parseCpPath(args[0])
+		dstNode, dst := parseCpPath(args[1])
+		if srcNode != "" && dstNode != "" {
+			exit.UsageT("Copying between nodes is not supported: one of SOURCE and TARGET must be on the host")
+		}
+		node := cpNode
+		if srcNode != "" {
+			node = srcNode
+		} else if dstNode != "" {
+			node = dstNode
+		}
+		// The cluster has a single node, named after the profile
+		if node != "" && node != config.GetMachineName() {
+			exit.WithCodeT(exit.Data, "Node {{.name}} not found, the nodes of {{.profile}} are: {{.nodes}}", out.V{"name": node, "profile": config.GetMachineName(), "nodes": config.GetMachineName()})
+		}
+
+		runner, _ := profileRunner()
+		var copied []string
+		var err error
+		if srcNode != "" {
+			copied, err = machine.CopyFromGuest(runner, src, dst)
+		} else {
+			copied, err = machine.CopyToGuest(runner, src, dst)
+		}
+		if err != nil {
+			exit.WithError("Failed to copy", err)
+		}
+		out.T(out.Copying, "Copied {{.count}} files to {{.target}}", out.V{"count": len(copied), "target": args[1]})
+	},
+}
+
+// parseCpPath splits a NODE:PATH argument of cp into the node and the path. Other arguments are paths of the host.
+func parseCpPath(arg string) (string, string) {
+	i := strings.Index(arg, ":")
+	// Drive letters of windows paths, such as C:\, are not nodes
+	if i < 2 || !strings.HasPrefix(arg[i+1:], "/") || strings.ContainsAny(arg[:i], `/\`) {
+		return "", arg
+	}
+	return arg[:i], arg[i+1:]
+}
+
+func init() {
+	cpCmd.Flags().StringVarP(&cpNode, "node", "n", "", "The node to copy from or to, if the path does not have a NODE: prefix")
+}
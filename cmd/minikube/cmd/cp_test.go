@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func TestParseCpPath(t *testing.T) {
+	var tests = []struct {
+		arg  string
+		node string
+		path string
+	}{
+		{"minikube:/etc/hosts", "minikube", "/etc/hosts"},
+		{"m02:/var/log/", "m02", "/var/log/"},
+		{"/etc/hosts", "", "/etc/hosts"},
+		{"./logs/*.log", "", "./logs/*.log"},
+		{`C:\Users\me`, "", `C:\Users\me`},
+		{"C:/Users/me", "", "C:/Users/me"},
+		{"dir/a:/b", "", "dir/a:/b"},
+		{"minikube:relative", "", "minikube:relative"},
+	}
+	for _, tc := range tests {
+		node, path := parseCpPath(tc.arg)
+		if node != tc.node || path != tc.path {
+			t.Errorf("parseCpPath(%q) = %q, %q, want %q, %q", tc.arg, node, path, tc.node, tc.path)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/credential"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// credentialCmd represents the credential command
+var credentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Writes the client certificate of minikube as an exec credential, for kubectl",
+	Long: `Writes the client certificate of minikube as an exec credential, for kubectl.
+
+This implements the exec credential protocol of client-go: 'minikube start --exec-credential' writes a kubeconfig user which runs
+this command, instead of referencing or embedding the certificate files. kubectl then always uses the current certificate,
+even once it is regenerated.`,
+	Hidden: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// The standard output is read by kubectl: nothing else should be written to it
+		enableUpdateNotification = false
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("usage: minikube credential")
+		}
+		c, err := credential.New(constants.MakeMiniPath("client.crt"), constants.MakeMiniPath("client.key"))
+		if err != nil {
+			exit.WithCodeT(exit.NoInput, "Unable to read the client certificate: {{.error}}. Run 'minikube start' first", out.V{"error": err})
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(c); err != nil {
+			exit.WithError("Error writing credential", err)
+		}
+	},
+}
+
+// credentialExec returns the kubeconfig exec credential plugin of a profile, which is this minikube binary
+func credentialExec(profile string) *api.ExecConfig {
+	self, err := os.Executable()
+	if err != nil {
+		exit.WithError("Failed to find the minikube binary", err)
+	}
+	e := &api.ExecConfig{
+		APIVersion: credential.APIVersion,
+		Command:    self,
+		Args:       []string{"credential", "--profile", profile},
+	}
+	// kubectl may run without the environment of minikube
+	if home := os.Getenv(constants.MinikubeHome); home != "" {
+		e.Env = []api.ExecEnvVar{{Name: constants.MinikubeHome, Value: home}}
+	}
+	return e
+}
@@ -26,12 +26,16 @@ import (
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/autostop"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	pkg_config "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/dns"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/nerdctl"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
 	pkgutil "k8s.io/minikube/pkg/util"
 )
 
@@ -49,6 +53,8 @@ func runDelete(cmd *cobra.Command, args []string) {
 	if len(args) > 0 {
 		exit.UsageT("usage: minikube delete")
 	}
+	unlock := cmdcfg.LockProfile("delete")
+	defer unlock()
 	profile := viper.GetString(pkg_config.MachineProfile)
 	api, err := machine.NewAPIClient()
 	if err != nil {
@@ -60,6 +66,8 @@ func runDelete(cmd *cobra.Command, args []string) {
 	if err != nil && !os.IsNotExist(err) {
 		out.ErrT(out.Sad, "Error loading profile config: {{.error}}", out.V{"name": profile})
 	}
+	// The config of the profile is removed below
+	profileKubeconfig := pkg_config.ProfileKubeconfig(profile)
 
 	// In the case of "none", we want to uninstall Kubernetes as there is no VM to delete
 	if err == nil && cc.MachineConfig.VMDriver == constants.DriverNone {
@@ -79,6 +87,22 @@ func runDelete(cmd *cobra.Command, args []string) {
 		out.FatalT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
 
+	if err := autostop.Disable(profile); err != nil {
+		out.T(out.WarningType, "Unable to kill auto-stop process: {{.error}}", out.V{"error": err})
+	}
+
+	if err := portforward.RemoveAll(profile); err != nil {
+		out.T(out.WarningType, "Unable to stop port forwards: {{.error}}", out.V{"error": err})
+	}
+
+	if err := dns.Disable(profile); err != nil {
+		out.T(out.WarningType, "Unable to disable DNS: {{.error}}", out.V{"error": err})
+	}
+
+	if err := nerdctl.Stop(profile); err != nil {
+		out.T(out.WarningType, "Unable to stop forwarding the nerdctl sockets: {{.error}}", out.V{"error": err})
+	}
+
 	if err := os.RemoveAll(constants.GetProfilePath(viper.GetString(pkg_config.MachineProfile))); err != nil {
 		if os.IsNotExist(err) {
 			out.T(out.Meh, `"{{.profile_name}}" profile does not exist`, out.V{"profile_name": profile})
@@ -89,7 +113,11 @@ func runDelete(cmd *cobra.Command, args []string) {
 	out.T(out.Crushed, `The "{{.cluster_name}}" cluster has been deleted.`, out.V{"cluster_name": profile})
 
 	machineName := pkg_config.GetMachineName()
-	if err := pkgutil.DeleteKubeConfigContext(constants.KubeconfigPath, machineName); err != nil {
+	if profileKubeconfig != "" {
+		if err := os.Remove(profileKubeconfig); err != nil && !os.IsNotExist(err) {
+			exit.WithError("Failed to remove kubeconfig", err)
+		}
+	} else if err := pkgutil.DeleteKubeConfigContext(cmdUtil.GetKubeConfigPath(), machineName); err != nil {
 		exit.WithError("update config", err)
 	}
 
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/dev"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	devDeployment     string
+	devNamespace      string
+	devIgnore         []string
+	devInterval       time.Duration
+	devRolloutTimeout time.Duration
+)
+
+// devCmd represents the dev command
+var devCmd = &cobra.Command{
+	Use:   "dev PATH",
+	Short: "Rebuilds an image and restarts a deployment whenever a local directory changes.",
+	Long: `Rebuilds an image and restarts a deployment whenever a local directory changes.
+
+The image is built in minikube, as with 'minikube image build', once on start and then after every change to PATH.
+Once built, the pods of --deployment are replaced, as with 'kubectl rollout restart', so that they run the new image.
+The deployment should use the image with the same tag, and an imagePullPolicy of IfNotPresent or Never.
+Build failures are reported, and the loop waits for the next change. Press Ctrl-C to stop.`,
+	Example: `minikube dev -t my-app:dev --deployment my-app .`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube dev -t TAG [--deployment NAME] PATH")
+		}
+		if buildTag == "" {
+			exit.UsageT("Please specify the name of the image with --tag")
+		}
+		src, err := filepath.Abs(args[0])
+		if err != nil {
+			exit.WithError("Failed to get the build context path", err)
+		}
+		if _, err := os.Stat(filepath.Join(src, dockerfilePath())); err != nil {
+			exit.WithCodeT(exit.NoInput, "Cannot find {{.dockerfile}} in the build context {{.path}}", out.V{"dockerfile": dockerfilePath(), "path": src})
+		}
+
+		runner, cc := profileRunner()
+		var client kubernetes.Interface
+		if devDeployment != "" {
+			client, err = service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+			if err != nil {
+				exit.WithError("Failed to get Kubernetes client", err)
+			}
+		}
+
+		devIteration(runner, cc, client, src)
+		out.T(out.Waiting, "Watching {{.path}} for changes ...", out.V{"path": src})
+		for files := range dev.Watch(src, devIgnore, devInterval, make(chan struct{})) {
+			out.T(out.Option, "Changed: {{.files}}", out.V{"files": summarizeFiles(files, 5)})
+			devIteration(runner, cc, client, src)
+			out.T(out.Waiting, "Watching {{.path}} for changes ...", out.V{"path": src})
+		}
+	},
+}
+
+// devIteration builds the image, and restarts the deployment if the build succeeded
+func devIteration(runner command.Runner, cc *config.Config, client kubernetes.Interface, src string) {
+	opts := cruntime.BuildOptions{
+		Tag:        buildTag,
+		Dockerfile: dockerfilePath(),
+		BuildArgs:  buildArgs,
+	}
+	out.T(out.Copying, "Building {{.tag}} from {{.path}} ...", out.V{"tag": buildTag, "path": src})
+	if err := machine.BuildImage(runner, cc.KubernetesConfig, src, opts); err != nil {
+		out.ErrT(out.FailureType, "Failed to build image: {{.error}}", out.V{"error": err})
+		return
+	}
+	if client == nil {
+		out.T(out.SuccessType, "Successfully built {{.tag}}", out.V{"tag": buildTag})
+		return
+	}
+
+	out.T(out.Restarting, "Restarting deployment {{.namespace}}/{{.name}} ...", out.V{"namespace": devNamespace, "name": devDeployment})
+	if err := dev.RestartDeployment(client, devNamespace, devDeployment, time.Now()); err != nil {
+		out.ErrT(out.FailureType, "Failed to restart deployment: {{.error}}", out.V{"error": err})
+		return
+	}
+	if err := dev.WaitForRollout(client, devNamespace, devDeployment, devRolloutTimeout); err != nil {
+		out.ErrT(out.FailureType, "Deployment did not roll out: {{.error}}", out.V{"error": err})
+		return
+	}
+	out.T(out.Ready, "Deployment {{.namespace}}/{{.name}} runs the new image", out.V{"namespace": devNamespace, "name": devDeployment})
+}
+
+// summarizeFiles lists the first max files, and how many others there are
+func summarizeFiles(files []string, max int) string {
+	if len(files) <= max {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:max], ", "), len(files)-max)
+}
+
+func init() {
+	// The build flags are shared with minikube image build
+	devCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Name and optionally a tag of the image, in the 'name:tag' format")
+	devCmd.Flags().StringVarP(&buildFile, "file", "f", "", "Path of the Dockerfile, relative to PATH (default \"Dockerfile\")")
+	devCmd.Flags().StringArrayVar(&buildArgs, "build-arg", []string{}, "Build-time variables, in the KEY=VALUE format")
+	devCmd.Flags().StringVarP(&devDeployment, "deployment", "d", "", "The deployment to restart after each build. If empty, the image is only built")
+	devCmd.Flags().StringVarP(&devNamespace, "namespace", "n", "default", "The namespace of the deployment")
+	devCmd.Flags().StringSliceVar(&devIgnore, "ignore", []string{".git"}, "Files and directories whose changes are ignored, as names or patterns such as '*.log'")
+	devCmd.Flags().DurationVar(&devInterval, "interval", time.Second, "How often PATH is checked for changes")
+	devCmd.Flags().DurationVar(&devRolloutTimeout, "rollout-timeout", 2*time.Minute, "How long to wait for the new pods of the deployment to be available")
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/dns"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	dnsDomainName string
+	dnsPort       int
+)
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolve the services of the cluster from the host, as <service>.<namespace>.svc.<domain>.",
+	Long: `Resolve the services of the cluster from the host, as <service>.<namespace>.svc.<domain>.
+
+A DNS server runs in the background on localhost, and the resolver of the host forwards the queries for the domain to it:
+through /etc/resolver on macOS, systemd-resolved on Linux, and the Name Resolution Policy Table on Windows.
+A name resolves to the external IP of a LoadBalancer service assigned by 'minikube tunnel', to the IP of the node for NodePort
+and LoadBalancer services, and otherwise to the cluster IP of the service, which is reachable while 'minikube tunnel' runs.`,
+}
+
+// dnsEnableCmd represents the dns enable command
+var dnsEnableCmd = &cobra.Command{
+	Use:     "enable",
+	Short:   "Starts the DNS server of the profile, and registers it with the resolver of the host",
+	Long:    "Starts the DNS server of the profile, and registers it with the resolver of the host. Registering it may ask for the password of sudo, or need an administrator on Windows.",
+	Example: `minikube dns enable && curl http://web.default.svc.minikube:30080`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		domain := strings.ToLower(strings.Trim(dnsDomainName, "."))
+		if domain == "" || strings.ContainsAny(domain, "/ \\") {
+			exit.UsageT("{{.domain}} is not a valid domain", out.V{"domain": dnsDomainName})
+		}
+		if old, err := dns.Load(profile); err == nil && old != nil {
+			if err := dns.Disable(profile); err != nil {
+				exit.WithError("Error disabling the previous DNS server", err)
+			}
+		}
+		s := &dns.State{Profile: profile, Domain: domain, Port: dnsPort}
+		if err := dns.Start(s); err != nil {
+			exit.WithError("Error starting the DNS server", err)
+		}
+		out.T(out.Connectivity, "Registering the DNS server for {{.domain}} with the resolver of the host ...", out.V{"domain": domain})
+		if err := dns.Register(domain, dnsPort); err != nil {
+			if err := dns.Kill(profile); err != nil {
+				glog.Errorf("kill: %v", err)
+			}
+			exit.WithError("Error registering the DNS server", err)
+		}
+		out.T(out.Ready, "Services of {{.profile}} resolve as <service>.<namespace>.svc.{{.domain}}", out.V{"profile": profile, "domain": domain})
+	},
+}
+
+// dnsDisableCmd represents the dns disable command
+var dnsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stops the DNS server of the profile, and removes it from the resolver of the host",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		if err := dns.Disable(profile); err != nil {
+			exit.WithError("Error disabling DNS", err)
+		}
+		out.T(out.Stopped, "Disabled the DNS server of {{.profile}}", out.V{"profile": profile})
+	},
+}
+
+// dnsStatusCmd represents the dns status command
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the DNS configuration of the profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		s, err := dns.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading DNS", err)
+		}
+		if s == nil {
+			out.T(out.Meh, "DNS is disabled for {{.profile}}", out.V{"profile": profile})
+			return
+		}
+		out.T(out.Option, "Services of {{.profile}} resolve as <service>.<namespace>.svc.{{.domain}}, through 127.0.0.1:{{.port}}", out.V{"profile": profile, "domain": s.Domain, "port": s.Port})
+		if s.Running() {
+			out.T(out.Running, "The DNS server is running, logs are written to {{.path}}", out.V{"path": dns.LogFilePath(profile)})
+		} else {
+			out.T(out.Stopped, "The DNS server is not running: run 'minikube dns enable' to start it again")
+		}
+	},
+}
+
+// dnsRunCmd is run in the background by 'minikube dns enable' to answer the queries of the host
+var dnsRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		s, err := dns.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading DNS", err)
+		}
+		if s == nil {
+			exit.WithCodeT(exit.Config, "DNS is disabled for {{.profile}}", out.V{"profile": profile})
+		}
+		conn, err := net.ListenPacket("udp", net.JoinHostPort("127.0.0.1", strconv.Itoa(s.Port)))
+		if err != nil {
+			exit.WithError("Error listening for DNS queries", err)
+		}
+		client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+		if err != nil {
+			exit.WithError("Failed to get Kubernetes client", err)
+		}
+		glog.Infof("Answering for %s on %s", s.Domain, conn.LocalAddr())
+		if err := dns.Serve(conn, s.Domain, func(name string) (net.IP, bool) { return lookupService(client, profile, s.Domain, name) }); err != nil {
+			exit.WithError("Error answering DNS queries", err)
+		}
+	},
+}
+
+// lookupService returns the address of the service named <service>.<namespace>.svc.<domain>
+func lookupService(client kubernetes.Interface, profile string, domain string, name string) (net.IP, bool) {
+	parts := strings.Split(strings.TrimSuffix(name, ".svc."+domain), ".")
+	if len(parts) != 2 || !strings.HasSuffix(name, ".svc."+domain) {
+		return nil, false
+	}
+	svc, err := client.CoreV1().Services(parts[1]).Get(parts[0], meta.GetOptions{})
+	if err != nil {
+		glog.Infof("service %s/%s: %v", parts[1], parts[0], err)
+		return nil, false
+	}
+	for _, i := range svc.Status.LoadBalancer.Ingress {
+		if ip := net.ParseIP(i.IP); ip != nil {
+			return ip, true
+		}
+	}
+	if svc.Spec.Type == core.ServiceTypeNodePort || svc.Spec.Type == core.ServiceTypeLoadBalancer {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			glog.Warningf("api client: %v", err)
+			return nil, false
+		}
+		defer api.Close()
+		ip, err := cluster.GetHostDriverIP(api, profile)
+		if err != nil {
+			glog.Warningf("node IP: %v", err)
+			return nil, false
+		}
+		return ip, true
+	}
+	if ip := net.ParseIP(svc.Spec.ClusterIP); ip != nil {
+		return ip, true
+	}
+	return nil, false
+}
+
+func init() {
+	dnsEnableCmd.Flags().StringVar(&dnsDomainName, "domain", "minikube", "The domain whose names are resolved, as <service>.<namespace>.svc.<domain>")
+	dnsEnableCmd.Flags().IntVar(&dnsPort, "port", dns.DefaultPort, "The port of localhost the DNS server listens on")
+	dnsCmd.AddCommand(dnsEnableCmd)
+	dnsCmd.AddCommand(dnsDisableCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+	dnsCmd.AddCommand(dnsRunCmd)
+}
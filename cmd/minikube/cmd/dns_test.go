@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLookupService(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       core.ServiceSpec{Type: core.ServiceTypeLoadBalancer, ClusterIP: "10.96.0.10"},
+			Status:     core.ServiceStatus{LoadBalancer: core.LoadBalancerStatus{Ingress: []core.LoadBalancerIngress{{IP: "10.96.0.10"}}}},
+		},
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "db", Namespace: "data"},
+			Spec:       core.ServiceSpec{Type: core.ServiceTypeClusterIP, ClusterIP: "10.96.0.20"},
+		},
+		&core.Service{
+			ObjectMeta: meta.ObjectMeta{Name: "headless", Namespace: "default"},
+			Spec:       core.ServiceSpec{Type: core.ServiceTypeClusterIP, ClusterIP: core.ClusterIPNone},
+		},
+	)
+	var tests = []struct {
+		name string
+		want net.IP
+	}{
+		{"web.default.svc.minikube", net.ParseIP("10.96.0.10")},
+		{"db.data.svc.minikube", net.ParseIP("10.96.0.20")},
+		{"headless.default.svc.minikube", nil},
+		{"missing.default.svc.minikube", nil},
+		{"db.data.minikube", nil},
+		{"a.db.data.svc.minikube", nil},
+	}
+	for _, tc := range tests {
+		ip, ok := lookupService(client, "minikube", "minikube", tc.name)
+		if ok != (tc.want != nil) || !ip.Equal(tc.want) {
+			t.Errorf("lookupService(%s) = %v, %v, want %v", tc.name, ip, ok, tc.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/translate"
+)
+
+var doctorDriver string
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the host for issues which keep minikube from working, and suggests fixes",
+	Long: `Checks the host for issues which keep minikube from working, and suggests fixes.
+
+The checks cover hardware virtualization, the binaries the VM driver needs, cgroups, proxy and VPN settings,
+port conflicts, disk space and DNS. The driver of the profile is checked, unless another one is given with --vm-driver.
+Exits with a non-zero code if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		driver := doctorDriver
+		if !cmd.Flags().Changed("vm-driver") {
+			if cc, err := config.Load(); err == nil {
+				driver = cc.MachineConfig.VMDriver
+			} else if !os.IsNotExist(err) {
+				glog.Warningf("Error loading profile config: %v", err)
+			}
+		}
+		env := doctor.Env{GOOS: runtime.GOOS, Driver: driver, MinikubeHome: constants.GetMinipath()}
+		out.T(out.Verifying, "Checking the host for the {{.driver}} driver ...", out.V{"driver": driver})
+
+		failed := false
+		for _, r := range doctor.Run(env, doctor.Checks) {
+			v := out.V{"name": r.Name, "message": r.Message}
+			switch r.Status {
+			case doctor.OK:
+				out.T(out.Check, "{{.name}}: {{.message}}", v)
+			case doctor.Skipped:
+				out.T(out.Option, "{{.name}}: skipped, {{.message}}", v)
+			case doctor.Warning:
+				out.T(out.WarningType, "{{.name}}: {{.message}}", v)
+			case doctor.Failure:
+				failed = true
+				out.T(out.FailureType, "{{.name}}: {{.message}}", v)
+			}
+			if r.Problem == nil {
+				continue
+			}
+			out.T(out.Tip, "Suggestion: {{.advice}}", out.V{"advice": translate.T(r.Problem.Advice)})
+			if r.Problem.URL != "" {
+				out.T(out.Documentation, "Documentation: {{.url}}", out.V{"url": r.Problem.URL})
+			}
+		}
+		if failed {
+			os.Exit(exit.Config)
+		}
+		out.T(out.Celebrate, "No issues found which keep minikube from working")
+	},
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&doctorDriver, "vm-driver", constants.DefaultVMDriver, "The VM driver to check the host for")
+}
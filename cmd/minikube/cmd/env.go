@@ -197,6 +197,31 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		shellCfg.NoProxyValue = noProxyValue
 	}
 
+	setShellSyntax(shellCfg, userShell)
+	return shellCfg, nil
+}
+
+func shellCfgUnset() (*ShellConfig, error) {
+
+	userShell, err := defaultShellDetector.GetShell(forceShell)
+	if err != nil {
+		return nil, err
+	}
+
+	shellCfg := &ShellConfig{
+		UsageHint: generateUsageHint(userShell),
+	}
+
+	if noProxy {
+		shellCfg.NoProxyVar, shellCfg.NoProxyValue = defaultNoProxyGetter.GetNoProxyVar()
+	}
+
+	unsetShellSyntax(shellCfg, userShell)
+	return shellCfg, nil
+}
+
+// setShellSyntax sets the syntax of the commands which set the variables of the shell
+func setShellSyntax(shellCfg *ShellConfig, userShell string) {
 	switch userShell {
 	case "fish":
 		shellCfg.Prefix = fishSetPfx
@@ -224,25 +249,10 @@ func shellCfgSet(api libmachine.API) (*ShellConfig, error) {
 		shellCfg.Suffix = bashSetSfx
 		shellCfg.Delimiter = bashSetDelim
 	}
-
-	return shellCfg, nil
 }
 
-func shellCfgUnset() (*ShellConfig, error) {
-
-	userShell, err := defaultShellDetector.GetShell(forceShell)
-	if err != nil {
-		return nil, err
-	}
-
-	shellCfg := &ShellConfig{
-		UsageHint: generateUsageHint(userShell),
-	}
-
-	if noProxy {
-		shellCfg.NoProxyVar, shellCfg.NoProxyValue = defaultNoProxyGetter.GetNoProxyVar()
-	}
-
+// unsetShellSyntax sets the syntax of the commands which unset the variables of the shell
+func unsetShellSyntax(shellCfg *ShellConfig, userShell string) {
 	switch userShell {
 	case "fish":
 		shellCfg.Prefix = fishUnsetPfx
@@ -270,8 +280,6 @@ func shellCfgUnset() (*ShellConfig, error) {
 		shellCfg.Suffix = bashUnsetSfx
 		shellCfg.Delimiter = bashUnsetDelim
 	}
-
-	return shellCfg, nil
 }
 
 func executeTemplateStdout(shellCfg *ShellConfig) error {
@@ -0,0 +1,236 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/audit"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/events"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var (
+	eventsFollow    bool
+	eventsSince     time.Duration
+	eventsSources   []string
+	eventsTypes     []string
+	eventsNamespace string
+)
+
+// eventsFormat is the format of the lines of minikube events in text
+const eventsFormat = "%-19s  %-10s  %-7s  %-20s  %-40s  %s\n"
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Lists the lifecycle events of minikube with the events of the cluster, in chronological order",
+	Long: `Lists the lifecycle events of minikube with the events of the cluster, in chronological order.
+
+The events of minikube are read from the audit log, which records when the commands which change the cluster of a profile,
+such as start, stop, delete and addons enable, started, completed or failed. The events of the cluster are its Kubernetes events,
+which are only listed while it is running. With --follow, new events are listed as they occur.`,
+	Example: `minikube events --since=1h --type=Warning
+minikube events --follow --source=kubernetes --namespace=default`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			exit.UsageT("usage: minikube events")
+		}
+		for _, s := range eventsSources {
+			if !strings.EqualFold(s, events.Minikube) && !strings.EqualFold(s, events.Kubernetes) {
+				exit.UsageT("Invalid --source {{.source}}, expected {{.minikube}} or {{.kubernetes}}", out.V{"source": s, "minikube": events.Minikube, "kubernetes": events.Kubernetes})
+			}
+		}
+		f := events.Filter{Sources: eventsSources, Types: eventsTypes}
+		if eventsSince > 0 {
+			f.Since = time.Now().Add(-eventsSince)
+		}
+		profile := config.GetMachineName()
+
+		entries, err := audit.Read()
+		if err != nil {
+			exit.WithError("Failed to read the audit log", err)
+		}
+		list := profileEvents(profile, entries)
+		var client kubernetes.Interface
+		resourceVersion := ""
+		if f.HasSource(events.Kubernetes) {
+			var kevs []events.Event
+			client, kevs, resourceVersion = clusterEvents()
+			list = append(list, kevs...)
+		}
+		events.Sort(list)
+
+		if !out.IsJSON() {
+			fmt.Fprintf(os.Stdout, eventsFormat, "TIME", "SOURCE", "TYPE", "REASON", "OBJECT", "MESSAGE")
+		}
+		for _, e := range list {
+			if f.Match(e) {
+				writeEvent(e)
+			}
+		}
+		if !eventsFollow {
+			return
+		}
+
+		ch := make(chan events.Event)
+		last := time.Time{}
+		if len(entries) > 0 {
+			last = entries[len(entries)-1].Time
+		}
+		go followAudit(profile, last, ch)
+		if client != nil {
+			go followCluster(client, resourceVersion, ch)
+		}
+		for e := range ch {
+			if f.Match(e) {
+				writeEvent(e)
+			}
+		}
+	},
+}
+
+// profileEvents returns the events of the entries of the audit log for a profile
+func profileEvents(profile string, entries []audit.Entry) []events.Event {
+	evs := []events.Event{}
+	for _, e := range entries {
+		if e.Profile == profile {
+			evs = append(evs, events.FromAudit(e))
+		}
+	}
+	return evs
+}
+
+// clusterEvents returns a client of the cluster, its events, and the resource version to watch them from.
+// The client is nil if the cluster is not running.
+func clusterEvents() (kubernetes.Interface, []events.Event, string) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	s, err := cluster.GetHostStatus(api)
+	api.Close()
+	if err != nil {
+		exit.WithError("Error getting host status", err)
+	}
+	if s != state.Running.String() {
+		out.WarningT("The cluster is {{.state}}: only the events of minikube are listed", out.V{"state": s})
+		return nil, nil, ""
+	}
+	client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+	if err != nil {
+		exit.WithError("Failed to get Kubernetes client", err)
+	}
+	l, err := client.CoreV1().Events(eventsNamespace).List(meta.ListOptions{})
+	if err != nil {
+		out.WarningT("Unable to list the events of the cluster: {{.error}}", out.V{"error": err})
+		return nil, nil, ""
+	}
+	evs := []events.Event{}
+	for _, e := range l.Items {
+		evs = append(evs, events.FromKubernetes(e))
+	}
+	return client, evs, l.ResourceVersion
+}
+
+// followAudit sends the events of the entries which are added to the audit log for a profile, after the last listed one.
+// Entries are told apart by time rather than by position, as the log may be rotated in the meantime.
+func followAudit(profile string, last time.Time, ch chan<- events.Event) {
+	for range time.Tick(time.Second) {
+		entries, err := audit.Read()
+		if err != nil {
+			glog.Warningf("reading audit log: %v", err)
+			continue
+		}
+		added := []audit.Entry{}
+		for _, e := range entries {
+			if e.Time.After(last) {
+				added = append(added, e)
+				last = e.Time
+			}
+		}
+		for _, e := range profileEvents(profile, added) {
+			ch <- e
+		}
+	}
+}
+
+// followCluster sends the events of the cluster which occur after a resource version, watching them again when the watch ends
+func followCluster(client kubernetes.Interface, resourceVersion string, ch chan<- events.Event) {
+	for {
+		w, err := client.CoreV1().Events(eventsNamespace).Watch(meta.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			glog.Warningf("watching events: %v", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		for r := range w.ResultChan() {
+			switch r.Type {
+			case watch.Added, watch.Modified:
+				if e, ok := r.Object.(*core.Event); ok {
+					resourceVersion = e.ResourceVersion
+					ch <- events.FromKubernetes(*e)
+				}
+			case watch.Error:
+				// The resource version is too old: watch from the current one
+				glog.Warningf("watch error: %v", r.Object)
+				if l, err := client.CoreV1().Events(eventsNamespace).List(meta.ListOptions{Limit: 1}); err == nil {
+					resourceVersion = l.ResourceVersion
+				}
+			}
+		}
+	}
+}
+
+// writeEvent writes an event, as a JSON line or as a line of columns
+func writeEvent(e events.Event) {
+	if out.IsJSON() {
+		if err := out.JSON(e); err != nil {
+			exit.WithError("Error writing event", err)
+		}
+		return
+	}
+	object := e.Object
+	if e.Namespace != "" {
+		object = e.Namespace + "/" + object
+	}
+	fmt.Fprintf(os.Stdout, eventsFormat, e.Time.Local().Format("2006-01-02 15:04:05"), e.Source, e.Type, e.Reason, object, e.Message)
+}
+
+func init() {
+	eventsCmd.Flags().BoolVarP(&eventsFollow, "follow", "f", false, "List new events as they occur")
+	eventsCmd.Flags().DurationVar(&eventsSince, "since", 0, "Only list the events of this recent period, such as 1h")
+	eventsCmd.Flags().StringSliceVar(&eventsSources, "source", []string{}, "The sources of the events to list: minikube, kubernetes, or both if empty")
+	eventsCmd.Flags().StringSliceVar(&eventsTypes, "type", []string{}, "The types of the events to list, such as Warning. All of them if empty")
+	eventsCmd.Flags().StringVarP(&eventsNamespace, "namespace", "n", "", "The namespace of the events of the cluster to list. All of them if empty")
+}
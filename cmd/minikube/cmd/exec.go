@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var (
+	execNode string
+	execAll  bool
+)
+
+// execResult is the outcome of a command on a node
+type execResult struct {
+	Node   string
+	Output string
+	Code   int
+}
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [--node NODE | --all] -- COMMAND",
+	Short: "Runs a command on nodes of minikube, without a terminal.",
+	Long: `Runs a command on nodes of minikube, without a terminal, and exits with its exit code.
+
+With --all, the command runs on every node in parallel. Each line of output is prefixed by the name of its node,
+and the exit code is the highest one of the nodes.`,
+	Example: `minikube exec -- sudo crictl ps
+minikube exec --all -- df -h /var`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("usage: minikube exec [--node NODE | --all] -- COMMAND")
+		}
+		if execAll && execNode != "" {
+			exit.UsageT("--node and --all cannot be used together")
+		}
+		// The cluster has a single node, named after the profile, which --all and --node select
+		nodes := []string{config.GetMachineName()}
+		if execNode != "" && execNode != config.GetMachineName() {
+			exit.WithCodeT(exit.Data, "Node {{.name}} not found, the nodes of {{.profile}} are: {{.nodes}}", out.V{"name": execNode, "profile": config.GetMachineName(), "nodes": strings.Join(nodes, ", ")})
+		}
+
+		runner, _ := profileRunner()
+		runners := map[string]command.Runner{config.GetMachineName(): runner}
+		results := execOnNodes(runners, nodes, strings.Join(args, " "))
+		code := 0
+		for _, r := range results {
+			if execAll {
+				fmt.Print(prefixLines(r.Node, r.Output))
+			} else {
+				fmt.Print(r.Output)
+			}
+			if r.Code > code {
+				code = r.Code
+			}
+		}
+		if execAll && code != 0 {
+			for _, r := range results {
+				if r.Code != 0 {
+					out.ErrT(out.FailureType, "{{.node}}: exit code {{.code}}", out.V{"node": r.Node, "code": r.Code})
+				}
+			}
+		}
+		os.Exit(code)
+	},
+}
+
+// execOnNodes runs cmd on the nodes in parallel, and returns the results in the order of nodes
+func execOnNodes(runners map[string]command.Runner, nodes []string, cmd string) []execResult {
+	results := make([]execResult, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n string) {
+			defer wg.Done()
+			var b bytes.Buffer
+			err := runners[n].CombinedOutputTo(cmd, &b)
+			results[i] = execResult{Node: n, Output: b.String(), Code: exitStatus(err)}
+		}(i, n)
+	}
+	wg.Wait()
+	return results
+}
+
+// exitStatus returns the exit code of a command from its error. It is 255, as with ssh, if the command could not run.
+func exitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+	switch e := errors.Cause(err).(type) {
+	case *ssh.ExitError:
+		return e.ExitStatus()
+	case *exec.ExitError:
+		return e.ExitCode()
+	}
+	return 255
+}
+
+// prefixLines prefixes every line of output by the name of the node
+func prefixLines(node string, output string) string {
+	if output == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, l := range strings.SplitAfter(output, "\n") {
+		if l == "" {
+			continue
+		}
+		b.WriteString("[" + node + "] " + l)
+	}
+	if !strings.HasSuffix(output, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func init() {
+	execCmd.Flags().StringVarP(&execNode, "node", "n", "", "The node to run the command on. Defaults to the primary node")
+	execCmd.Flags().BoolVar(&execAll, "all", false, "Run the command on all nodes")
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestExecOnNodes(t *testing.T) {
+	ok := command.NewFakeCommandRunner()
+	ok.SetCommandToOutput(map[string]string{"uptime": "up 1 day\n"})
+	failing := command.NewFakeCommandRunner()
+
+	runners := map[string]command.Runner{"m01": ok, "m02": failing}
+	got := execOnNodes(runners, []string{"m01", "m02"}, "uptime")
+	want := []execResult{{Node: "m01", Output: "up 1 day\n", Code: 0}, {Node: "m02", Output: "", Code: 255}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("execOnNodes() = %+v, want %+v", got, want)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	if got := exitStatus(nil); got != 0 {
+		t.Errorf("exitStatus(nil) = %d, want 0", got)
+	}
+	if got := exitStatus(fmt.Errorf("NewSession: EOF")); got != 255 {
+		t.Errorf("exitStatus(EOF) = %d, want 255", got)
+	}
+}
+
+func TestPrefixLines(t *testing.T) {
+	var tests = []struct {
+		output string
+		want   string
+	}{
+		{"", ""},
+		{"a\nb\n", "[m01] a\n[m01] b\n"},
+		{"a\nb", "[m01] a\n[m01] b\n"},
+	}
+	for _, tc := range tests {
+		if got := prefixLines("m01", tc.output); got != tc.want {
+			t.Errorf("prefixLines(%q) = %q, want %q", tc.output, got, tc.want)
+		}
+	}
+}
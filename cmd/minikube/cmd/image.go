@@ -0,0 +1,293 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/dev"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var (
+	buildTag       string
+	buildFile      string
+	buildArgs      []string
+	buildCacheFrom []string
+	buildCacheTo   []string
+	buildPlatforms []string
+	syncWatch      bool
+	syncInterval   time.Duration
+	pruneAll       bool
+	pruneDryRun    bool
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage images in minikube.",
+	Long:  "Manage images in minikube.",
+}
+
+// buildImageCmd represents the image build command
+var buildImageCmd = &cobra.Command{
+	Use:   "build PATH",
+	Short: "Build an image in minikube from a local build context.",
+	Long: `Build an image in minikube from a local build context.
+
+The image is built with BuildKit by the container runtime of the cluster, so it can be used by pods right away:
+the docker daemon for docker, and buildkitd for containerd and cri-o.
+
+Build caches can be imported and exported with --cache-from and --cache-to, using the buildkit cache syntax,
+such as type=local,dest=/data/buildcache or type=registry,ref=<image>. The paths of local caches are in the VM.
+The docker runtime only supports images as cache sources, and type=inline to export the cache.
+
+Images for other platforms than the one of the VM, such as linux/arm64, are built with --platform, running their binaries with qemu.
+With containerd, several platforms can be given, and the image is stored as a manifest list of all of them.
+The docker and cri-o runtimes store a single platform per image.`,
+	Example: `minikube image build -t my-app:dev .
+minikube image build -t my-app:dev --cache-to type=local,dest=/data/buildcache --cache-from type=local,src=/data/buildcache .
+minikube image build -t my-app:dev --platform linux/amd64,linux/arm64 .`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube image build -t TAG PATH")
+		}
+		if buildTag == "" {
+			exit.UsageT("Please specify the name of the image with --tag")
+		}
+		src, err := filepath.Abs(args[0])
+		if err != nil {
+			exit.WithError("Failed to get the build context path", err)
+		}
+		if _, err := os.Stat(filepath.Join(src, dockerfilePath())); err != nil {
+			exit.WithCodeT(exit.NoInput, "Cannot find {{.dockerfile}} in the build context {{.path}}", out.V{"dockerfile": dockerfilePath(), "path": src})
+		}
+
+		runner, cc := profileRunner()
+		opts := cruntime.BuildOptions{
+			Tag:        buildTag,
+			Dockerfile: dockerfilePath(),
+			BuildArgs:  buildArgs,
+			CacheFrom:  buildCacheFrom,
+			CacheTo:    buildCacheTo,
+			Platforms:  buildPlatforms,
+		}
+		out.T(out.Copying, "Building {{.tag}} from {{.path}} ...", out.V{"tag": buildTag, "path": src})
+		if err := machine.BuildImage(runner, cc.KubernetesConfig, src, opts); err != nil {
+			exit.WithError("Failed to build image", err)
+		}
+		out.T(out.SuccessType, "Successfully built {{.tag}}", out.V{"tag": buildTag})
+	},
+}
+
+// imageJSON describes an image in the output of 'minikube image ls --output=json'
+type imageJSON struct {
+	Name string `json:"name"`
+}
+
+// listImagesCmd represents the image ls command
+var listImagesCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List the images stored by the container runtime of minikube.",
+	Long:    "List the images stored by the container runtime of minikube, sorted by name.",
+	Run: func(cmd *cobra.Command, args []string) {
+		runner, cc := profileRunner()
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		images, err := cr.ListImages()
+		if err != nil {
+			exit.WithError("Failed to list images", err)
+		}
+		if out.IsJSON() {
+			l := []imageJSON{}
+			for _, i := range images {
+				l = append(l, imageJSON{Name: i})
+			}
+			if err := out.JSON(l); err != nil {
+				exit.WithError("Error writing images", err)
+			}
+			return
+		}
+		for _, i := range images {
+			out.Ln(i)
+		}
+	},
+}
+
+// pruneImagesCmd represents the image prune command
+var pruneImagesCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the images of minikube which no container uses.",
+	Long: `Remove the images of minikube which no container uses.
+
+By default, only the images without a tag are removed, such as the ones left by rebuilding a tag with 'minikube image build'.
+With --all, the tagged images are removed too, including the ones cached with 'minikube cache add' which no pod runs yet.
+The sizes include the layers shared with other images, so less space may be reclaimed.`,
+	Example: `minikube image prune --all --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner, cc := profileRunner()
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		images, err := cr.UnusedImages(pruneAll)
+		if err != nil {
+			exit.WithError("Failed to list unused images", err)
+		}
+		if len(images) == 0 {
+			out.T(out.Empty, "No unused images")
+			return
+		}
+		var size int64
+		ids := []string{}
+		for _, i := range images {
+			name := strings.Join(i.Tags, ", ")
+			if name == "" {
+				name = i.ID
+			}
+			out.T(out.Empty, "{{.image}} ({{.size}})", out.V{"image": name, "size": units.HumanSize(float64(i.Size))})
+			ids = append(ids, i.ID)
+			size += i.Size
+		}
+		if pruneDryRun {
+			out.T(out.Option, "Would remove {{.count}} images, reclaiming up to {{.size}}", out.V{"count": len(images), "size": units.HumanSize(float64(size))})
+			return
+		}
+		if err := cr.RemoveImages(ids); err != nil {
+			exit.WithError("Failed to remove images", err)
+		}
+		out.T(out.SuccessType, "Removed {{.count}} images, reclaiming up to {{.size}}", out.V{"count": len(images), "size": units.HumanSize(float64(size))})
+	},
+}
+
+// syncImagesCmd represents the image sync command
+var syncImagesCmd = &cobra.Command{
+	Use:   "sync IMAGE...",
+	Short: "Load images of the docker daemon of the host into minikube.",
+	Long: `Load images of the docker daemon of the host into minikube.
+
+The images are saved with the docker client of the host, and loaded into the container runtime of the cluster,
+so that images built on the host can be used by pods without a registry. The cluster has a single node, which gets the images.
+
+With --watch, the images are checked every --interval, and loaded again whenever their ID changes, such as after a
+'docker build' on the host. Images which are missing on the host are skipped until they exist. Press Ctrl-C to stop.`,
+	Example: `minikube image sync my-app:dev
+minikube image sync --watch my-app:dev my-worker:dev`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.UsageT("usage: minikube image sync [--watch] IMAGE...")
+		}
+		ids := dev.ImageIDs(args, machine.HostImageID)
+		if len(ids) == 0 && !syncWatch {
+			exit.WithCodeT(exit.NoInput, "None of the images exist in the docker daemon of the host: {{.images}}", out.V{"images": strings.Join(args, ", ")})
+		}
+
+		runner, cc := profileRunner()
+		for _, image := range args {
+			if _, ok := ids[image]; !ok {
+				out.WarningT("Skipping {{.image}}, which does not exist in the docker daemon of the host", out.V{"image": image})
+				continue
+			}
+			if !syncImage(runner, cc, image) && !syncWatch {
+				os.Exit(exit.Failure)
+			}
+		}
+		if !syncWatch {
+			return
+		}
+
+		out.T(out.Waiting, "Watching {{.images}} for changes ...", out.V{"images": strings.Join(args, ", ")})
+		for images := range dev.WatchImages(args, ids, machine.HostImageID, syncInterval, make(chan struct{})) {
+			for _, image := range images {
+				syncImage(runner, cc, image)
+			}
+			out.T(out.Waiting, "Watching {{.images}} for changes ...", out.V{"images": strings.Join(args, ", ")})
+		}
+	},
+}
+
+// syncImage loads an image of the host into the cluster, and reports whether it succeeded
+func syncImage(runner command.Runner, cc *config.Config, image string) bool {
+	out.T(out.Copying, "Loading {{.image}} ...", out.V{"image": image})
+	if err := machine.LoadHostImage(runner, cc.KubernetesConfig, image); err != nil {
+		out.ErrT(out.FailureType, "Failed to load {{.image}}: {{.error}}", out.V{"image": image, "error": err})
+		return false
+	}
+	out.T(out.SuccessType, "Loaded {{.image}}", out.V{"image": image})
+	return true
+}
+
+// profileRunner returns a command runner for the host of the profile, and its config
+func profileRunner() (command.Runner, *config.Config) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		exit.WithError("Error getting host", err)
+	}
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error loading profile config", err)
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.WithError("Failed to get command runner", err)
+	}
+	return runner, cc
+}
+
+// dockerfilePath returns the path of the Dockerfile relative to the build context
+func dockerfilePath() string {
+	if buildFile == "" {
+		return "Dockerfile"
+	}
+	return filepath.ToSlash(filepath.Clean(buildFile))
+}
+
+func init() {
+	buildImageCmd.Flags().StringVarP(&buildTag, "tag", "t", "", "Name and optionally a tag of the image, in the 'name:tag' format")
+	buildImageCmd.Flags().StringVarP(&buildFile, "file", "f", "", "Path of the Dockerfile, relative to PATH (default \"Dockerfile\")")
+	buildImageCmd.Flags().StringArrayVar(&buildArgs, "build-arg", []string{}, "Build-time variables, in the KEY=VALUE format")
+	buildImageCmd.Flags().StringArrayVar(&buildCacheFrom, "cache-from", []string{}, "Cache sources to import, e.g. type=local,src=/data/buildcache or type=registry,ref=<image>")
+	buildImageCmd.Flags().StringArrayVar(&buildCacheTo, "cache-to", []string{}, "Cache destinations to export, e.g. type=local,dest=/data/buildcache or type=inline")
+	buildImageCmd.Flags().StringSliceVar(&buildPlatforms, "platform", []string{}, "Platforms to build the image for, e.g. linux/amd64,linux/arm64. Defaults to the platform of the VM")
+	syncImagesCmd.Flags().BoolVarP(&syncWatch, "watch", "w", false, "Keep running, and load the images again whenever they change on the host")
+	syncImagesCmd.Flags().DurationVar(&syncInterval, "interval", 2*time.Second, "How often the images are checked for changes, with --watch")
+	imageCmd.AddCommand(buildImageCmd)
+	imageCmd.AddCommand(listImagesCmd)
+	pruneImagesCmd.Flags().BoolVarP(&pruneAll, "all", "a", false, "Remove the unused tagged images too, not only the images without a tag")
+	pruneImagesCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List the images which would be removed, without removing them")
+	imageCmd.AddCommand(syncImagesCmd)
+	imageCmd.AddCommand(pruneImagesCmd)
+}
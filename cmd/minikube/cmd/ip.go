@@ -51,6 +51,14 @@ var ipCmd = &cobra.Command{
 		if err != nil {
 			exit.WithError("Error getting IP", err)
 		}
+		if out.IsJSON() {
+			if err := out.JSON(struct {
+				IP string `json:"ip"`
+			}{ip}); err != nil {
+				exit.WithError("Error writing IP", err)
+			}
+			return
+		}
 		out.Ln(ip)
 	},
 }
@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var (
+	kubeconfigPathOnly   bool
+	kubeconfigEmbedCerts bool
+)
+
+// kubeconfigCmd represents the kubeconfig command
+var kubeconfigCmd = &cobra.Command{
+	Use:   "kubeconfig [PROFILE]",
+	Short: "Prints a kubeconfig which only holds the context of a profile",
+	Long: `Prints a kubeconfig which only holds the context of a profile, the current profile by default, with its cluster and its user.
+
+The context is read from ~/.kube/minikube-<profile>.yaml for the profiles started with --kubeconfig-per-profile,
+and from the shared kubeconfig otherwise. With --embed-certs, the certificates are embedded, so that the kubeconfig
+can be used on its own, for example on another host. With --path, the file holding the context is printed instead.`,
+	Example: `minikube kubeconfig dev --embed-certs > dev.yaml
+export KUBECONFIG=$(minikube kubeconfig dev --path)`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// The output is often redirected to a file
+		enableUpdateNotification = false
+		RootCmd.PersistentPreRun(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.UsageT("usage: minikube kubeconfig [PROFILE]")
+		}
+		profile := config.GetMachineName()
+		if len(args) == 1 {
+			profile = args[0]
+		}
+		file := cmdutil.GetProfileKubeConfigPath(profile)
+		if kubeconfigPathOnly {
+			fmt.Println(file)
+			return
+		}
+
+		c, err := pkgutil.ExtractContext(file, profile, kubeconfigEmbedCerts)
+		if err != nil {
+			exit.WithCodeT(exit.Data, "Unable to get the context of {{.profile}} from {{.path}}: {{.error}}", out.V{"profile": profile, "path": file, "error": err})
+		}
+		b, err := pkgutil.EncodeConfig(c)
+		if err != nil {
+			exit.WithError("Error encoding kubeconfig", err)
+		}
+		if _, err := os.Stdout.Write(b); err != nil {
+			exit.WithError("Error writing kubeconfig", err)
+		}
+	},
+}
+
+func init() {
+	kubeconfigCmd.Flags().BoolVar(&kubeconfigPathOnly, "path", false, "Print the path of the kubeconfig file which holds the context, instead of the kubeconfig")
+	kubeconfigCmd.Flags().BoolVar(&kubeconfigEmbedCerts, "embed-certs", false, "Embed the certificates, instead of referencing the files in the minikube directory")
+}
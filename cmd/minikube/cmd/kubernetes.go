@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/drain"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/logs"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	upgradeVersion      string
+	upgradeDrainTimeout time.Duration
+)
+
+// kubernetesCmd represents the kubernetes command
+var kubernetesCmd = &cobra.Command{
+	Use:   "kubernetes",
+	Short: "Manage the Kubernetes version of the cluster.",
+	Long:  "Manage the Kubernetes version of the cluster.",
+}
+
+// kubernetesUpgradeCmd represents the kubernetes upgrade command
+var kubernetesUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the Kubernetes version of a running cluster, draining the node first",
+	Long: `Upgrades the Kubernetes version of a running cluster, draining the node first.
+
+The binaries and images of the new version are downloaded while the cluster is still running. The node is then cordoned and drained,
+the control plane is upgraded by the bootstrapper, and the node is uncordoned so the evicted pods are scheduled again.
+The mirror pods of the control plane and the pods of daemon sets are not evicted. Downgrades are not supported.`,
+	Example: `minikube kubernetes upgrade --kubernetes-version=v1.15.2`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if upgradeVersion == "" {
+			exit.UsageT("usage: minikube kubernetes upgrade --kubernetes-version=<version>")
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		old := cc.KubernetesConfig.KubernetesVersion
+		nv := upgradeTarget(old, upgradeVersion)
+
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			exit.WithError("Failed to get command runner", err)
+		}
+		bsName := viper.GetString(cmdcfg.Bootstrapper)
+		bs, err := getClusterBootstrapper(api, bsName)
+		if err != nil {
+			exit.WithError("Failed to get bootstrapper", err)
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		kc := cc.KubernetesConfig
+		kc.KubernetesVersion = nv
+
+		out.T(out.ThumbsUp, "Upgrading from Kubernetes {{.old}} to {{.new}}", out.V{"old": old, "new": nv})
+		out.T(out.FileDownload, "Downloading Kubernetes {{.version}} binaries ...", out.V{"version": nv})
+		if err := machine.CacheBinariesForBootstrapper(nv, bsName); err != nil {
+			exit.WithError("Failed to cache binaries", err)
+		}
+		out.T(out.Pulling, "Pulling images ...")
+		if err := bs.PullImages(kc); err != nil {
+			out.T(out.FailureType, "Unable to pull images, which may be OK: {{.error}}", out.V{"error": err})
+		}
+
+		client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+		if err != nil {
+			exit.WithError("Failed to get Kubernetes client", err)
+		}
+		node := kc.NodeName
+		out.T(out.Waiting, "Draining node {{.node}} ...", out.V{"node": node})
+		if err := drain.Cordon(client, node, true); err != nil {
+			exit.WithError("Failed to cordon node", err)
+		}
+		if err := drain.Drain(client, node, upgradeDrainTimeout); err != nil {
+			if err := drain.Cordon(client, node, false); err != nil {
+				glog.Errorf("uncordon %s: %v", node, err)
+			}
+			exit.WithError("Failed to drain node", err)
+		}
+
+		out.T(out.Restarting, "Upgrading the control plane using {{.bootstrapper}} ...", out.V{"bootstrapper": bsName})
+		if err := bs.UpdateCluster(kc); err != nil {
+			exit.WithError("Failed to update cluster", err)
+		}
+		if err := bs.RestartCluster(kc); err != nil {
+			exit.WithLogEntries("Error restarting cluster", err, logs.FindProblems(cr, bs, runner))
+		}
+		cc.KubernetesConfig = kc
+		if err := saveConfig(cc); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+		if err := bs.WaitCluster(kc); err != nil {
+			exit.WithError("Wait failed", err)
+		}
+
+		out.T(out.Option, "Uncordoning node {{.node}} ...", out.V{"node": node})
+		if err := drain.Cordon(client, node, false); err != nil {
+			exit.WithError("Failed to uncordon node", err)
+		}
+		out.T(out.Ready, "Upgraded to Kubernetes {{.version}}", out.V{"version": nv})
+	},
+}
+
+// upgradeTarget returns the normalized version to upgrade to, and exits unless it is newer than the current one
+func upgradeTarget(current string, target string) string {
+	nvs, err := semver.Make(strings.TrimPrefix(target, version.VersionPrefix))
+	if err != nil {
+		exit.WithCodeT(exit.Data, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": target, "error": err})
+	}
+	ovs, err := semver.Make(strings.TrimPrefix(current, version.VersionPrefix))
+	if err != nil {
+		exit.WithCodeT(exit.Data, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": current, "error": err})
+	}
+	if !nvs.GT(ovs) {
+		exit.WithCodeT(exit.Config, "The cluster already runs Kubernetes {{.version}}: only upgrades to a newer version are supported", out.V{"version": current})
+	}
+	return version.VersionPrefix + nvs.String()
+}
+
+func init() {
+	kubernetesUpgradeCmd.Flags().StringVar(&upgradeVersion, kubernetesVersion, "", "The Kubernetes version to upgrade to (ex: v1.2.3)")
+	kubernetesUpgradeCmd.Flags().DurationVar(&upgradeDrainTimeout, "drain-timeout", 5*time.Minute, "How long to wait for the pods of the node to be evicted")
+	kubernetesCmd.AddCommand(kubernetesUpgradeCmd)
+}
@@ -17,14 +17,20 @@ limitations under the License.
 package cmd
 
 import (
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/logs"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
 )
 
 const (
@@ -39,18 +45,41 @@ var (
 	numberOfLines int
 	// showProblems only shows lines that match known issues
 	showProblems bool
+	// logsSince only shows the lines of this recent period
+	logsSince time.Duration
+	// logsNode is the node whose logs are shown
+	logsNode string
+	// logsComponents are the logs to show, or all of them if empty
+	logsComponents []string
+	// logsFormat overrides the output format for the logs
+	logsFormat string
 )
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "Gets the logs of the running instance, used for debugging minikube, not user code.",
-	Long:  `Gets the logs of the running instance, used for debugging minikube, not user code.`,
+	Long: `Gets the logs of the running instance, used for debugging minikube, not user code.
+
+The logs of the Kubernetes components, of the container runtime and of the kernel are shown one after the other,
+or all together with --follow, where each line is prefixed by the name of its component.
+Use --component to select some of them, such as --component=apiserver,kubelet, and --since to only show recent lines.
+The audit log of the apiserver, enabled with 'minikube start --apiserver-audit-policy', is only shown with --component=apiserver-audit.`,
+	Example: `minikube logs --component=apiserver,kubelet --since=10m
+minikube logs --follow --format=json`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if logsFormat != "" {
+			if err := out.SetOutputFormat(logsFormat); err != nil {
+				exit.UsageT("{{.error}}", out.V{"error": err})
+			}
+		}
 		cfg, err := config.Load()
 		if err != nil {
 			exit.WithError("Error getting config", err)
 		}
+		if logsNode != "" && logsNode != config.GetMachineName() && logsNode != cfg.KubernetesConfig.NodeName {
+			exit.WithCodeT(exit.Data, "Node {{.name}} not found, the nodes of {{.profile}} are: {{.nodes}}", out.V{"name": logsNode, "profile": config.GetMachineName(), "nodes": config.GetMachineName()})
+		}
 
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -75,19 +104,26 @@ var logsCmd = &cobra.Command{
 		if err != nil {
 			exit.WithError("Unable to get runtime", err)
 		}
-		if followLogs {
-			err := logs.Follow(cr, bs, runner)
-			if err != nil {
-				exit.WithError("Follow", err)
-			}
-			return
-		}
 		if showProblems {
 			problems := logs.FindProblems(cr, bs, runner)
 			logs.OutputProblems(problems, numberOfProblems)
 			return
 		}
-		err = logs.Output(cr, bs, runner, numberOfLines)
+		o := logs.Options{Lines: numberOfLines, Since: logsSince, Components: logsComponents, Node: config.GetMachineName()}
+		if cfg.KubernetesConfig.AuditPolicy != "" {
+			o.AuditLog = kubeadm.AuditLogPath
+		}
+		if followLogs {
+			err = logs.Follow(cr, bs, runner, o)
+		} else {
+			err = logs.Output(cr, bs, runner, o)
+		}
+		if e, ok := err.(*logs.UnknownComponentError); ok {
+			if pkgutil.ContainsString(e.Unknown, logs.AuditComponent) {
+				exit.UsageT("The apiserver has no audit log: start minikube with --apiserver-audit-policy")
+			}
+			exit.UsageT("No logs for {{.unknown}}, the components are: {{.components}}", out.V{"unknown": strings.Join(e.Unknown, ", "), "components": strings.Join(e.Available, ", ")})
+		}
 		if err != nil {
 			exit.WithError("Error getting machine logs", err)
 		}
@@ -98,4 +134,8 @@ func init() {
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 30, "Number of lines back to go within the log")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only show the lines of this recent period, such as 10m")
+	logsCmd.Flags().StringVar(&logsNode, "node", "", "The node to show the logs of. The cluster has a single node, named after the profile")
+	logsCmd.Flags().StringSliceVar(&logsComponents, "component", []string{}, "The logs to show, such as apiserver,kubelet. All of them if empty")
+	logsCmd.Flags().StringVar(&logsFormat, "format", "", "Format of the logs: 'text' or 'json'. Defaults to the format of --output")
 }
@@ -21,11 +21,13 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 
+	"github.com/docker/machine/libmachine/host"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	cmdUtil "k8s.io/minikube/cmd/util"
@@ -35,12 +37,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/third_party/go9p/ufs"
 )
 
 // nineP is the value of --type used for the 9p filesystem.
 const nineP = "9p"
 
+// virtiofs is the value of --type used for virtiofs, which is shared by the hypervisor.
+const virtiofs = "virtiofs"
+
+// sshfs is the value of --type used for sshfs, which is served by the sftp server of the host.
+const sshfs = "sshfs"
+
 // placeholders for flag values
 var mountIP string
 var mountVersion string
@@ -51,9 +60,10 @@ var gid string
 var mSize int
 var options []string
 var mode uint
+var sftpServer string
 
 // supportedFilesystems is a map of filesystem types to not warn against.
-var supportedFilesystems = map[string]bool{nineP: true}
+var supportedFilesystems = map[string]bool{nineP: true, virtiofs: true, sshfs: true}
 
 // mountCmd represents the mount command
 var mountCmd = &cobra.Command{
@@ -106,6 +116,14 @@ var mountCmd = &cobra.Command{
 		if host.Driver.DriverName() == constants.DriverNone {
 			exit.UsageT(`'none' driver does not support 'minikube mount' command`)
 		}
+		if mountType == virtiofs {
+			mountVirtiofs(host, hostPath, vmPath)
+			return
+		}
+		if mountType == sshfs {
+			mountSSHFS(host, hostPath, vmPath)
+			return
+		}
 		var ip net.IP
 		if mountIP == "" {
 			ip, err = cluster.GetVMHostIP(host)
@@ -131,16 +149,7 @@ var mountCmd = &cobra.Command{
 			MSize:   mSize,
 			Port:    port,
 			Mode:    os.FileMode(mode),
-			Options: map[string]string{},
-		}
-
-		for _, o := range options {
-			if !strings.Contains(o, "=") {
-				cfg.Options[o] = ""
-				continue
-			}
-			parts := strings.Split(o, "=")
-			cfg.Options[parts[0]] = parts[1]
+			Options: mountOptions(options),
 		}
 
 		out.T(out.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
@@ -201,7 +210,7 @@ var mountCmd = &cobra.Command{
 
 func init() {
 	mountCmd.Flags().StringVar(&mountIP, "ip", "", "Specify the ip that the mount should be setup on")
-	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p)")
+	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p, virtiofs, sshfs)")
 	mountCmd.Flags().StringVar(&mountVersion, "9p-version", constants.DefaultMountVersion, "Specify the 9p version that the mount should use")
 	mountCmd.Flags().BoolVar(&isKill, "kill", false, "Kill the mount process spawned by minikube start")
 	mountCmd.Flags().StringVar(&uid, "uid", "docker", "Default user id used for the mount")
@@ -209,4 +218,108 @@ func init() {
 	mountCmd.Flags().UintVar(&mode, "mode", 0755, "File permissions used for the mount")
 	mountCmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
 	mountCmd.Flags().IntVar(&mSize, "msize", constants.DefaultMsize, "The number of bytes to use for 9p packet payload")
+	mountCmd.Flags().StringVar(&sftpServer, "sftp-server", "", "The sftp server of the host used by sshfs mounts. Defaults to the one of OpenSSH")
+}
+
+// mountVirtiofs mounts a directory shared with the VM when it was created. Unlike 9p, no file
+// server runs on the host, so the mount stays in place after this process exits.
+func mountVirtiofs(h *host.Host, hostPath string, vmPath string) {
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error loading profile config", err)
+	}
+	share, ok := cluster.FindVirtiofsShare(cc.MachineConfig.VirtiofsShares, hostPath)
+	if !ok {
+		exit.WithCodeT(exit.Config, `{{.path}} is not shared with the VM over virtiofs. Shares are set up when the VM is created, with: minikube start --mount --mount-type=virtiofs --mount-string="{{.path}}:{{.target}}"`, out.V{"path": hostPath, "target": vmPath})
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.WithError("Failed to get command runner", err)
+	}
+
+	cfg := &cluster.MountConfig{
+		Type:    virtiofs,
+		Mode:    os.FileMode(mode),
+		Options: mountOptions(options),
+	}
+
+	out.T(out.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	out.T(out.Option, "Mount type:   {{.name}}", out.V{"name": cfg.Type})
+	out.T(out.Option, "Share:        {{.share}}", out.V{"share": share})
+	if err := cluster.MountVirtiofs(runner, share, hostPath, vmPath, cfg); err != nil {
+		exit.WithError("mount failed", err)
+	}
+	out.T(out.SuccessType, "Successfully mounted {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+}
+
+// mountSSHFS mounts a directory with sshfs, served by the sftp server of the host over the ssh connection to the VM.
+// Like 9p, the mount is only accessible while this process is alive.
+func mountSSHFS(h *host.Host, hostPath string, vmPath string) {
+	srv := sftpServer
+	if srv == "" {
+		var err error
+		srv, err = cluster.FindSftpServer()
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Unable to mount with sshfs: {{.error}}. Use --sftp-server to specify its path", out.V{"error": err})
+		}
+	}
+	abs, err := filepath.Abs(hostPath)
+	if err != nil {
+		exit.WithError("Unable to get absolute path", err)
+	}
+	client, err := sshutil.NewSSHClient(h.Driver)
+	if err != nil {
+		exit.WithError("Failed to get ssh client", err)
+	}
+	defer client.Close()
+
+	cfg := &cluster.MountConfig{
+		Type:    sshfs,
+		UID:     uid,
+		GID:     gid,
+		Mode:    os.FileMode(mode),
+		Options: mountOptions(options),
+	}
+	out.T(out.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	out.T(out.Option, "Mount type:   {{.name}}", out.V{"name": cfg.Type})
+	out.T(out.Option, "SFTP server:  {{.server}}", out.V{"server": srv})
+	out.T(out.Option, "User ID:      {{.userID}}", out.V{"userID": cfg.UID})
+	out.T(out.Option, "Group ID:     {{.groupID}}", out.V{"groupID": cfg.GID})
+	out.T(out.Option, "Options:      {{.options}}", out.V{"options": cfg.Options})
+
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.WithError("Failed to get command runner", err)
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range c {
+			out.T(out.Unmount, "Unmounting {{.path}} ...", out.V{"path": vmPath})
+			if err := cluster.Unmount(runner, vmPath); err != nil {
+				out.ErrT(out.FailureType, "Failed unmount: {{.error}}", out.V{"error": err})
+			}
+			exit.WithCodeT(exit.Interrupted, "Received {{.name}} signal", out.V{"name": sig})
+		}
+	}()
+
+	out.T(out.Notice, "NOTE: This process must stay alive for the mount to be accessible ...")
+	if err := cluster.MountSSHFS(client, srv, abs, vmPath, cfg); err != nil {
+		exit.WithError("mount failed", err)
+	}
+	out.T(out.Stopped, "{{.path}} was unmounted", out.V{"path": vmPath})
+}
+
+// mountOptions parses the --options flag into mount options
+func mountOptions(opts []string) map[string]string {
+	options := map[string]string{}
+	for _, o := range opts {
+		if !strings.Contains(o, "=") {
+			options[o] = ""
+			continue
+		}
+		parts := strings.Split(o, "=")
+		options[parts[0]] = parts[1]
+	}
+	return options
 }
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/nerdctl"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+)
+
+const nerdctlEnvTmpl = `{{ .Prefix }}CONTAINERD_ADDRESS{{ .Delimiter }}{{ .ContainerdAddress }}{{ .Suffix }}{{ .Prefix }}CONTAINERD_NAMESPACE{{ .Delimiter }}{{ .ContainerdNamespace }}{{ .Suffix }}{{ .Prefix }}BUILDKIT_HOST{{ .Delimiter }}{{ .BuildkitHost }}{{ .Suffix }}{{ .UsageHint }}`
+
+// NerdctlShellConfig represents the shell config of nerdctl
+type NerdctlShellConfig struct {
+	ShellConfig
+	ContainerdAddress   string
+	ContainerdNamespace string
+	BuildkitHost        string
+}
+
+// nerdctlEnvCmd represents the nerdctl-env command
+var nerdctlEnvCmd = &cobra.Command{
+	Use:   "nerdctl-env",
+	Short: "Sets up nerdctl env variables, to use the containerd runtime of minikube from the host",
+	Long: `Sets up nerdctl env variables, to use the containerd runtime of minikube from the host.
+
+The containerd and BuildKit sockets of the node are forwarded to sockets of the profile directory, by a process which runs in the background
+until 'minikube nerdctl-env --unset'. nerdctl then builds, pulls, tags and pushes the images of the cluster, in the k8s.io namespace.
+Commands which run containers, such as 'nerdctl run', need a containerd on the same machine: use 'kubectl run' instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		userShell, err := defaultShellDetector.GetShell(forceShell)
+		if err != nil {
+			exit.WithError("Error detecting shell", err)
+		}
+		if unset {
+			if err := nerdctl.Stop(profile); err != nil {
+				exit.WithError("Error stopping the socket forwarding", err)
+			}
+			if err := executeNerdctlTemplate(os.Stdout, nerdctlShellCfgUnset(userShell)); err != nil {
+				exit.WithError("Error executing template", err)
+			}
+			return
+		}
+		if runtime.GOOS == "windows" {
+			exit.WithCodeT(exit.Unavailable, "nerdctl-env is not supported on Windows, which has no unix sockets for containerd")
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		h, err := cluster.CheckIfHostExistsAndLoad(api, profile)
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		hostSt, err := cluster.GetHostStatus(api)
+		if err != nil {
+			exit.WithError("Error getting host status", err)
+		}
+		if hostSt != state.Running.String() {
+			exit.WithCodeT(exit.Unavailable, `The host is currently not running`)
+		}
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			exit.WithError("Error getting command runner", err)
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Error getting container runtime", err)
+		}
+		containerd, ok := cr.(*cruntime.Containerd)
+		if !ok {
+			exit.WithCodeT(exit.Config, "nerdctl-env requires the containerd runtime, but the cluster runs {{.runtime}}: use 'minikube docker-env' instead", out.V{"runtime": cr.Name()})
+		}
+		if err := containerd.EnableBuildkit(); err != nil {
+			exit.WithError("Error enabling BuildKit", err)
+		}
+
+		// With the none driver, containerd already runs on the host
+		if h.Driver.DriverName() == constants.DriverNone {
+			if err := executeNerdctlTemplate(os.Stdout, nerdctlShellCfgSet(userShell, containerd.SocketPath(), containerd.BuildkitSocket())); err != nil {
+				exit.WithError("Error executing template", err)
+			}
+			return
+		}
+
+		s, err := nerdctl.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading the socket forwarding", err)
+		}
+		if s == nil || !s.Running() {
+			s = &nerdctl.State{
+				Profile: profile,
+				Sockets: map[string]string{
+					nerdctl.ContainerdSocket: containerd.SocketPath(),
+					nerdctl.BuildkitSocket:   containerd.BuildkitSocket(),
+				},
+			}
+			if err := nerdctl.Start(s); err != nil {
+				exit.WithError("Error forwarding the containerd socket", err)
+			}
+		}
+		cfg := nerdctlShellCfgSet(userShell, nerdctl.SocketPath(profile, nerdctl.ContainerdSocket), nerdctl.SocketPath(profile, nerdctl.BuildkitSocket))
+		if err := executeNerdctlTemplate(os.Stdout, cfg); err != nil {
+			exit.WithError("Error executing template", err)
+		}
+	},
+}
+
+// nerdctlEnvRunCmd is run in the background by 'minikube nerdctl-env' to forward the sockets of the node
+var nerdctlEnvRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		s, err := nerdctl.Load(profile)
+		if err != nil || s == nil {
+			exit.WithCodeT(exit.Data, "The sockets of {{.profile}} are not forwarded: {{.error}}", out.V{"profile": profile, "error": err})
+		}
+		d := &nodeDialer{profile: profile}
+		errs := make(chan error, len(s.Sockets))
+		for name, target := range s.Sockets {
+			ln, err := nerdctl.Listen(profile, name)
+			if err != nil {
+				exit.WithError("Error listening", err)
+			}
+			target := target
+			glog.Infof("Forwarding %s to %s", ln.Addr(), target)
+			go func() {
+				errs <- portforward.Serve(ln, func() (net.Conn, error) { return d.DialSocket(target) })
+			}()
+		}
+		if err := <-errs; err != nil {
+			exit.WithError("Error forwarding", err)
+		}
+	},
+}
+
+// nerdctlShellCfgSet returns the shell config which points nerdctl to the containerd and BuildKit sockets
+func nerdctlShellCfgSet(userShell string, containerdSocket string, buildkitSocket string) *NerdctlShellConfig {
+	cfg := &NerdctlShellConfig{
+		ShellConfig:         ShellConfig{UsageHint: nerdctlUsageHint(userShell)},
+		ContainerdAddress:   containerdSocket,
+		ContainerdNamespace: nerdctl.Namespace,
+		BuildkitHost:        "unix://" + buildkitSocket,
+	}
+	setShellSyntax(&cfg.ShellConfig, userShell)
+	return cfg
+}
+
+// nerdctlShellCfgUnset returns the shell config which unsets the variables of nerdctl
+func nerdctlShellCfgUnset(userShell string) *NerdctlShellConfig {
+	cfg := &NerdctlShellConfig{ShellConfig: ShellConfig{UsageHint: nerdctlUsageHint(userShell)}}
+	unsetShellSyntax(&cfg.ShellConfig, userShell)
+	return cfg
+}
+
+// nerdctlUsageHint returns the usage hint of docker-env, for nerdctl-env
+func nerdctlUsageHint(userShell string) string {
+	return strings.Replace(generateUsageHint(userShell), "minikube docker-env", "minikube nerdctl-env", -1)
+}
+
+func executeNerdctlTemplate(w io.Writer, cfg *NerdctlShellConfig) error {
+	tmpl := template.Must(template.New("nerdctlEnvConfig").Parse(nerdctlEnvTmpl))
+	return tmpl.Execute(w, cfg)
+}
+
+func init() {
+	nerdctlEnvCmd.Flags().StringVar(&forceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	nerdctlEnvCmd.Flags().BoolVarP(&unset, "unset", "u", false, "Unset variables, and stop forwarding the sockets")
+	nerdctlEnvCmd.AddCommand(nerdctlEnvRunCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNerdctlShellCfg(t *testing.T) {
+	var tests = []struct {
+		shell string
+		cfg   *NerdctlShellConfig
+		want  string
+	}{
+		{
+			shell: "bash",
+			cfg:   nerdctlShellCfgSet("bash", "/home/u/.minikube/profiles/p1/nerdctl/containerd.sock", "/home/u/.minikube/profiles/p1/nerdctl/buildkitd.sock"),
+			want: `export CONTAINERD_ADDRESS="/home/u/.minikube/profiles/p1/nerdctl/containerd.sock"
+export CONTAINERD_NAMESPACE="k8s.io"
+export BUILDKIT_HOST="unix:///home/u/.minikube/profiles/p1/nerdctl/buildkitd.sock"
+# Run this command to configure your shell:
+# eval $(minikube nerdctl-env)
+`,
+		},
+		{
+			shell: "fish",
+			cfg:   nerdctlShellCfgUnset("fish"),
+			want: `set -e CONTAINERD_ADDRESS;
+set -e CONTAINERD_NAMESPACE;
+set -e BUILDKIT_HOST;
+# Run this command to configure your shell:
+# eval (minikube nerdctl-env)
+`,
+		},
+		{
+			shell: "none",
+			cfg:   nerdctlShellCfgSet("none", "/run/containerd/containerd.sock", "/run/buildkit/buildkitd.sock"),
+			want: `CONTAINERD_ADDRESS=/run/containerd/containerd.sock
+CONTAINERD_NAMESPACE=k8s.io
+BUILDKIT_HOST=unix:///run/buildkit/buildkitd.sock
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.shell, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := executeNerdctlTemplate(&b, tc.cfg); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got := b.String(); got != tc.want {
+				t.Errorf("nerdctl-env = %q, want %q", got, tc.want)
+			}
+			if strings.Contains(tc.cfg.UsageHint, "docker-env") {
+				t.Errorf("usage hint %q refers to docker-env", tc.cfg.UsageHint)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/netcheck"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var networkCheckTimeout time.Duration
+
+// networkCmd represents the network command
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Diagnose the network of the cluster",
+	Long:  "Diagnose the network of the cluster.",
+}
+
+// networkCheckCmd represents the network check command
+var networkCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Checks pod, service, DNS and host connectivity, and tells which layer of the network is broken",
+	Long: `Checks pod, service, DNS and host connectivity, and tells which layer of the network is broken.
+
+Runs short-lived busybox pods in the minikube-netcheck namespace, which connect to a test server pod:
+directly (CNI), through its service (kube-proxy), and through the name of the service (DNS).
+Then checks that pods reach the host, and the host reaches the NodePort of the service (host firewall, VPN, driver network).
+The namespace is deleted afterwards. Exits with a non-zero code if any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		nodeIP, err := h.Driver.GetIP()
+		if err != nil {
+			exit.WithError("Error getting IP", err)
+		}
+		hostIP := nodeIP
+		if h.DriverName != constants.DriverNone {
+			ip, err := cluster.GetVMHostIP(h)
+			if err != nil {
+				glog.Warningf("Error getting the host IP address: %v", err)
+				hostIP = ""
+			} else {
+				hostIP = ip.String()
+			}
+		}
+		dnsDomain := cc.KubernetesConfig.DNSDomain
+		if dnsDomain == "" {
+			dnsDomain = constants.ClusterDNSDomain
+		}
+		client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+		if err != nil {
+			exit.WithError("Failed to get Kubernetes client", err)
+		}
+
+		c := netcheck.NewChecker(client, netcheck.Config{NodeIP: nodeIP, HostIP: hostIP, DNSDomain: dnsDomain, Timeout: networkCheckTimeout})
+		if !checkNetwork(c, cc.MachineConfig.VMDriver) {
+			os.Exit(exit.Unavailable)
+		}
+	},
+}
+
+// checkNetwork runs the checks and reports the broken layer, if any. It returns whether all checks passed.
+func checkNetwork(c *netcheck.Checker, driver string) bool {
+	out.T(out.Verifying, "Starting the test server in the {{.namespace}} namespace ...", out.V{"namespace": netcheck.Namespace})
+	defer func() {
+		if err := c.Cleanup(); err != nil {
+			out.WarningT("Failed to delete the {{.namespace}} namespace: {{.error}}", out.V{"namespace": netcheck.Namespace, "error": err})
+		}
+	}()
+	if err := c.Setup(); err != nil {
+		out.ErrT(out.FailureType, "Failed to start the test server: {{.error}}", out.V{"error": err})
+		return false
+	}
+
+	results := c.Run()
+	for _, r := range results {
+		v := out.V{"name": r.Name, "message": r.Message, "error": r.Err}
+		if r.Err != nil {
+			out.T(out.FailureType, "{{.name}} ({{.message}}): {{.error}}", v)
+			continue
+		}
+		out.T(out.Check, "{{.name}}: {{.message}}", v)
+	}
+	layer, broken := netcheck.Broken(results)
+	if !broken {
+		out.T(out.Celebrate, "The network of the cluster works")
+		return true
+	}
+	out.T(out.WarningType, "The {{.layer}} looks broken", out.V{"layer": layer})
+	for _, h := range netcheck.Hints(layer, driver) {
+		out.T(out.Tip, "Suggestion: {{.advice}}", out.V{"advice": h})
+	}
+	if layer == netcheck.Host {
+		env := doctor.Env{GOOS: runtime.GOOS, Driver: driver, MinikubeHome: constants.GetMinipath()}
+		for _, r := range doctor.Run(env, hostNetworkChecks()) {
+			if r.Status == doctor.Warning || r.Status == doctor.Failure {
+				out.T(out.Tip, "{{.name}}: {{.message}}", out.V{"name": r.Name, "message": r.Message})
+			}
+		}
+	}
+	return false
+}
+
+// hostNetworkChecks are the doctor checks which explain why the host and the VM can't reach each other
+func hostNetworkChecks() []doctor.Check {
+	checks := []doctor.Check{}
+	for _, c := range doctor.Checks {
+		if c.Name == "Proxy" || c.Name == "VPN" {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+func init() {
+	networkCheckCmd.Flags().DurationVar(&networkCheckTimeout, "timeout", time.Minute, "How long to wait for each check")
+	networkCmd.AddCommand(networkCheckCmd)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/drain"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+)
+
+var nodeDrainTimeout time.Duration
+
+// nodeInfo describes a node of the cluster, for minikube node list
+type nodeInfo struct {
+	Name        string
+	NodeName    string
+	Host        string
+	Schedulable string
+}
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Cordon, drain and list the nodes of the cluster.",
+	Long: `Cordon, drain and list the nodes of the cluster.
+
+Nodes are named after their machine, or by their name in Kubernetes. The cluster has a single node, named after the profile,
+which the commands use when no name is given.`,
+}
+
+// nodeListCmd represents the node list command
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes of the cluster, with the state of their machine",
+	Run: func(cmd *cobra.Command, args []string) {
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		n := nodeInfo{Name: config.GetMachineName(), NodeName: cc.KubernetesConfig.NodeName, Host: nodeHostState().String()}
+		if n.Host == state.Running.String() {
+			if client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout); err == nil {
+				if kn, err := client.CoreV1().Nodes().Get(n.NodeName, meta.GetOptions{}); err == nil {
+					n.Schedulable = strconv.FormatBool(!kn.Spec.Unschedulable)
+				}
+			}
+		}
+		if out.IsJSON() {
+			if err := out.JSON([]nodeInfo{n}); err != nil {
+				exit.WithError("Error writing nodes", err)
+			}
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Kubernetes Node", "Host", "Schedulable"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.Append([]string{n.Name, n.NodeName, n.Host, n.Schedulable})
+		table.Render()
+	},
+}
+
+// nodeCordonCmd represents the node cordon command
+var nodeCordonCmd = &cobra.Command{
+	Use:   "cordon [NAME]",
+	Short: "Marks a node as unschedulable, so that no new pods are scheduled on it",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, node := runningNode(args)
+		if err := drain.Cordon(client, node, true); err != nil {
+			exit.WithError("Failed to cordon node", err)
+		}
+		out.T(out.Stopped, "Node {{.node}} is cordoned", out.V{"node": node})
+	},
+}
+
+// nodeUncordonCmd represents the node uncordon command
+var nodeUncordonCmd = &cobra.Command{
+	Use:   "uncordon [NAME]",
+	Short: "Marks a node as schedulable again",
+	Run: func(cmd *cobra.Command, args []string) {
+		client, node := runningNode(args)
+		if err := drain.Cordon(client, node, false); err != nil {
+			exit.WithError("Failed to uncordon node", err)
+		}
+		out.T(out.Ready, "Node {{.node}} is schedulable", out.V{"node": node})
+	},
+}
+
+// nodeDrainCmd represents the node drain command
+var nodeDrainCmd = &cobra.Command{
+	Use:   "drain [NAME]",
+	Short: "Cordons a node, and evicts its pods",
+	Long: `Cordons a node, and evicts its pods through the eviction API, so that pod disruption budgets are respected.
+
+The mirror pods of the control plane, the pods of daemon sets and finished pods are not evicted, as with 'kubectl drain --ignore-daemonsets'.
+The node stays cordoned, even if some pods could not be evicted before --timeout: run 'minikube node uncordon' to schedule pods on it again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, node := runningNode(args)
+		out.T(out.Waiting, "Draining node {{.node}} ...", out.V{"node": node})
+		if err := drain.Cordon(client, node, true); err != nil {
+			exit.WithError("Failed to cordon node", err)
+		}
+		if err := drain.Drain(client, node, nodeDrainTimeout); err != nil {
+			out.T(out.Tip, "The node is still cordoned. Run 'minikube node uncordon' to schedule pods on it again")
+			exit.WithError("Failed to drain node", err)
+		}
+		out.T(out.Ready, "Node {{.node}} is drained", out.V{"node": node})
+	},
+}
+
+// nodeHostState returns the state of the machine of the node
+func nodeHostState() state.State {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		exit.WithError("Error getting host", err)
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		exit.WithError("Error getting host state", err)
+	}
+	return s
+}
+
+// nodeNotRunningError is returned by resolveNode when the machine of the node is not running
+type nodeNotRunningError struct {
+	node  string
+	state state.State
+}
+
+func (e *nodeNotRunningError) Error() string {
+	return fmt.Sprintf("the machine of node %s is %s", e.node, e.state)
+}
+
+// resolveNode returns the Kubernetes name of the node named by args, by its machine name or its Kubernetes name,
+// or of the node of the cluster when no name is given. It fails unless the machine of the node is running.
+func resolveNode(args []string, machineName string, nodeName string, hostState func() state.State) (string, error) {
+	if len(args) == 1 && args[0] != nodeName && args[0] != machineName {
+		return "", fmt.Errorf("node %s not found", args[0])
+	}
+	if s := hostState(); s != state.Running {
+		return "", &nodeNotRunningError{node: nodeName, state: s}
+	}
+	return nodeName, nil
+}
+
+// runningNode returns a client of the cluster and the Kubernetes name of the node named by args,
+// exiting unless the node exists and its machine is running
+func runningNode(args []string) (kubernetes.Interface, string) {
+	if len(args) > 1 {
+		exit.UsageT("Specify a single node")
+	}
+	cc, err := config.Load()
+	if err != nil {
+		exit.WithError("Error loading profile config", err)
+	}
+	node, err := resolveNode(args, config.GetMachineName(), cc.KubernetesConfig.NodeName, nodeHostState)
+	if e, ok := err.(*nodeNotRunningError); ok {
+		exit.WithCodeT(exit.Unavailable, "The machine of node {{.node}} is {{.state}}: run 'minikube start' first", out.V{"node": e.node, "state": e.state})
+	}
+	if err != nil {
+		exit.WithCodeT(exit.Data, "Node {{.name}} not found, the nodes of {{.profile}} are: {{.nodes}}", out.V{"name": args[0], "profile": config.GetMachineName(), "nodes": config.GetMachineName()})
+	}
+	client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+	if err != nil {
+		exit.WithError("Failed to get Kubernetes client", err)
+	}
+	return client, node
+}
+
+func init() {
+	nodeDrainCmd.Flags().DurationVar(&nodeDrainTimeout, "timeout", 5*time.Minute, "How long to wait for the pods of the node to be evicted")
+	nodeCmd.AddCommand(nodeListCmd)
+	nodeCmd.AddCommand(nodeCordonCmd)
+	nodeCmd.AddCommand(nodeUncordonCmd)
+	nodeCmd.AddCommand(nodeDrainCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+)
+
+func TestResolveNode(t *testing.T) {
+	var tests = []struct {
+		description string
+		args        []string
+		state       state.State
+		want        string
+		notFound    bool
+		notRunning  bool
+	}{
+		{description: "no name", state: state.Running, want: "m01"},
+		{description: "machine name", args: []string{"demo"}, state: state.Running, want: "m01"},
+		{description: "kubernetes name", args: []string{"m01"}, state: state.Running, want: "m01"},
+		{description: "unknown name", args: []string{"m02"}, state: state.Running, notFound: true},
+		{description: "unknown name of stopped machine", args: []string{"m02"}, state: state.Stopped, notFound: true},
+		{description: "stopped machine", state: state.Stopped, notRunning: true},
+		{description: "paused machine by name", args: []string{"demo"}, state: state.Paused, notRunning: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := resolveNode(tc.args, "demo", "m01", func() state.State { return tc.state })
+			_, notRunning := err.(*nodeNotRunningError)
+			if notRunning != tc.notRunning || (err != nil && !notRunning) != tc.notFound {
+				t.Fatalf("resolveNode(%v) error = %v, want not found %v, not running %v", tc.args, err, tc.notFound, tc.notRunning)
+			}
+			if got != tc.want {
+				t.Errorf("resolveNode(%v) = %q, want %q", tc.args, got, tc.want)
+			}
+		})
+	}
+}
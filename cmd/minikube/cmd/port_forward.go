@@ -0,0 +1,274 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/nerdctl"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+)
+
+var (
+	portForwardNamespace string
+	portForwardPort      int
+	portForwardAll       bool
+)
+
+// portForwardCmd represents the port-forward command
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward SERVICE [HOST_PORT:]PORT",
+	Short: "Forwards a port of localhost to a service of the cluster, in the background.",
+	Long: `Forwards a port of localhost to a service of the cluster, in the background.
+
+Connections are tunneled over the ssh connection of the VM to the cluster IP of the service, so any type of service can be reached,
+with every driver, even when the VM cannot be reached directly from the host. PORT is the port of the service, or its name, and the
+port of localhost is the same unless HOST_PORT is given. The forward keeps running until 'minikube port-forward stop', or until the
+cluster is deleted. It reconnects after the VM restarts. Run 'minikube port-forward list' to see the forwards of the profile.`,
+	Example: `minikube port-forward dashboard-service 9090:80 -n kube-system
+minikube port-forward list
+minikube port-forward stop 9090`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.UsageT("usage: minikube port-forward SERVICE [HOST_PORT:]PORT")
+		}
+		hostPort, port := splitPortSpec(args[1])
+		client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+		if err != nil {
+			exit.WithError("Failed to get Kubernetes client", err)
+		}
+		svc, err := client.CoreV1().Services(portForwardNamespace).Get(args[0], meta.GetOptions{})
+		if err != nil {
+			exit.WithCodeT(exit.Data, "Service {{.namespace}}/{{.name}} not found: {{.error}}", out.V{"namespace": portForwardNamespace, "name": args[0], "error": err})
+		}
+		sp, err := servicePort(svc, port)
+		if err != nil {
+			exit.WithCodeT(exit.Data, "{{.error}}", out.V{"error": err})
+		}
+		if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == core.ClusterIPNone {
+			exit.WithCodeT(exit.Data, "Service {{.namespace}}/{{.name}} is headless: there is no cluster IP to forward to", out.V{"namespace": svc.Namespace, "name": svc.Name})
+		}
+		if hostPort == 0 {
+			hostPort = int(sp.Port)
+		}
+		// Fail now rather than in the background if the port of the host is taken
+		ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(hostPort)))
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Port {{.port}} of localhost is not available: {{.error}}", out.V{"port": hostPort, "error": err})
+		}
+		ln.Close()
+
+		f := portforward.Forward{
+			HostPort:  hostPort,
+			Namespace: svc.Namespace,
+			Service:   svc.Name,
+			Port:      int(sp.Port),
+			Target:    net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(int(sp.Port))),
+		}
+		if err := portforward.Start(config.GetMachineName(), f); err != nil {
+			exit.WithError("Failed to start port forward", err)
+		}
+		out.T(out.Connectivity, "Forwarding 127.0.0.1:{{.host_port}} to {{.namespace}}/{{.name}}:{{.port}}", out.V{"host_port": hostPort, "namespace": f.Namespace, "name": f.Service, "port": f.Port})
+	},
+}
+
+// portForwardListCmd represents the port-forward list command
+var portForwardListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the port forwards of the profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		fs, err := portforward.List(config.GetMachineName())
+		if err != nil {
+			exit.WithError("Failed to list port forwards", err)
+		}
+		if out.IsJSON() {
+			if err := out.JSON(fs); err != nil {
+				exit.WithError("Error writing port forwards", err)
+			}
+			return
+		}
+		if len(fs) == 0 {
+			out.T(out.Empty, "No port forwards found. You can create one using `minikube port-forward SERVICE PORT`.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Host Port", "Namespace", "Service", "Port", "Running"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, f := range fs {
+			table.Append([]string{strconv.Itoa(f.HostPort), f.Namespace, f.Service, strconv.Itoa(f.Port), strconv.FormatBool(f.Running())})
+		}
+		table.Render()
+	},
+}
+
+// portForwardStopCmd represents the port-forward stop command
+var portForwardStopCmd = &cobra.Command{
+	Use:   "stop [HOST_PORT...]",
+	Short: "Stops port forwards of the profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		if portForwardAll {
+			if err := portforward.RemoveAll(profile); err != nil {
+				exit.WithError("Failed to stop port forwards", err)
+			}
+			out.T(out.Stopped, "Stopped the port forwards of {{.profile}}", out.V{"profile": profile})
+			return
+		}
+		if len(args) == 0 {
+			exit.UsageT("usage: minikube port-forward stop HOST_PORT... | --all")
+		}
+		for _, a := range args {
+			port, err := strconv.Atoi(a)
+			if err != nil {
+				exit.UsageT("{{.port}} is not a port", out.V{"port": a})
+			}
+			if err := portforward.Remove(profile, port); err != nil {
+				exit.WithCodeT(exit.Data, "{{.error}}", out.V{"error": err})
+			}
+			out.T(out.Stopped, "Stopped forwarding port {{.port}}", out.V{"port": port})
+		}
+	},
+}
+
+// portForwardRunCmd is run in the background by 'minikube port-forward' to forward a port of localhost
+var portForwardRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		f, err := portforward.Get(profile, portForwardPort)
+		if err != nil {
+			exit.WithError("Error loading port forward", err)
+		}
+		ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(f.HostPort)))
+		if err != nil {
+			exit.WithError("Error listening", err)
+		}
+		d := &nodeDialer{profile: profile}
+		glog.Infof("Forwarding %s to %s/%s:%d at %s", ln.Addr(), f.Namespace, f.Service, f.Port, f.Target)
+		if err := portforward.Serve(ln, func() (net.Conn, error) { return d.Dial(f.Target) }); err != nil {
+			exit.WithError("Error forwarding", err)
+		}
+	},
+}
+
+// nodeDialer connects to addresses from the node, over its ssh connection. It reconnects once the connection is lost.
+type nodeDialer struct {
+	profile string
+	mu      sync.Mutex
+	client  *ssh.Client
+}
+
+// Dial connects to addr from the node
+func (d *nodeDialer) Dial(addr string) (net.Conn, error) {
+	return d.dial(addr, func(c *ssh.Client) (net.Conn, error) { return c.Dial("tcp", addr) })
+}
+
+// DialSocket connects to a unix socket of the node, as root
+func (d *nodeDialer) DialSocket(path string) (net.Conn, error) {
+	return d.dial(path, func(c *ssh.Client) (net.Conn, error) { return nerdctl.DialSocket(c, path) })
+}
+
+// dial connects to target with the ssh connection, and reconnects once if it fails
+func (d *nodeDialer) dial(target string, fn func(*ssh.Client) (net.Conn, error)) (net.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.client != nil {
+		conn, err := fn(d.client)
+		if err == nil {
+			return conn, nil
+		}
+		glog.Infof("dial %s: %v, reconnecting", target, err)
+		d.client.Close()
+		d.client = nil
+	}
+	client, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	d.client = client
+	return fn(d.client)
+}
+
+// connect opens an ssh connection to the node
+func (d *nodeDialer) connect() (*ssh.Client, error) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "api client")
+	}
+	defer api.Close()
+	h, err := api.Load(d.profile)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading machine")
+	}
+	return sshutil.NewSSHClient(h.Driver)
+}
+
+// splitPortSpec splits [HOST_PORT:]PORT. The port of the host is 0 when it is not given.
+func splitPortSpec(spec string) (int, string) {
+	i := strings.LastIndex(spec, ":")
+	if i < 0 {
+		return 0, spec
+	}
+	hostPort, err := strconv.Atoi(spec[:i])
+	if err != nil || hostPort <= 0 || hostPort > 65535 {
+		exit.UsageT("{{.port}} is not a port", out.V{"port": spec[:i]})
+	}
+	return hostPort, spec[i+1:]
+}
+
+// servicePort returns the port of a service from its number or its name
+func servicePort(svc *core.Service, port string) (core.ServicePort, error) {
+	names := []string{}
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port || strconv.Itoa(int(p.Port)) == port {
+			return p, nil
+		}
+		names = append(names, strconv.Itoa(int(p.Port)))
+	}
+	return core.ServicePort{}, fmt.Errorf("service %s/%s has no port %s, its ports are: %s", svc.Namespace, svc.Name, port, strings.Join(names, ", "))
+}
+
+func init() {
+	portForwardCmd.Flags().StringVarP(&portForwardNamespace, "namespace", "n", "default", "The namespace of the service")
+	portForwardRunCmd.Flags().IntVar(&portForwardPort, "port", 0, "The port of localhost to forward")
+	portForwardStopCmd.Flags().BoolVar(&portForwardAll, "all", false, "Stop every port forward of the profile")
+	portForwardCmd.AddCommand(portForwardListCmd)
+	portForwardCmd.AddCommand(portForwardStopCmd)
+	portForwardCmd.AddCommand(portForwardRunCmd)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitPortSpec(t *testing.T) {
+	var tests = []struct {
+		spec     string
+		hostPort int
+		port     string
+	}{
+		{"80", 0, "80"},
+		{"http", 0, "http"},
+		{"9090:80", 9090, "80"},
+		{"9090:http", 9090, "http"},
+	}
+	for _, tc := range tests {
+		hostPort, port := splitPortSpec(tc.spec)
+		if hostPort != tc.hostPort || port != tc.port {
+			t.Errorf("splitPortSpec(%q) = %d, %q, want %d, %q", tc.spec, hostPort, port, tc.hostPort, tc.port)
+		}
+	}
+}
+
+func TestServicePort(t *testing.T) {
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: core.ServiceSpec{Ports: []core.ServicePort{
+			{Name: "http", Port: 80},
+			{Name: "https", Port: 443},
+		}},
+	}
+	for _, port := range []string{"443", "https"} {
+		p, err := servicePort(svc, port)
+		if err != nil || p.Port != 443 {
+			t.Errorf("servicePort(%q) = %v, %v, want port 443", port, p, err)
+		}
+	}
+	if _, err := servicePort(svc, "8080"); err == nil {
+		t.Errorf("servicePort(8080) = nil, want error")
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/preload"
+)
+
+var (
+	preloadK8sVersion      string
+	preloadImagesFrom      string
+	preloadImageRepository string
+	preloadFile            string
+)
+
+// preloadCmd represents the preload command
+var preloadCmd = &cobra.Command{
+	Use:   "preload",
+	Short: "Create archives of images, which minikube start loads before Kubernetes starts.",
+	Long:  "Create archives of images, which 'minikube start --preload' loads into the container runtime before Kubernetes starts, instead of pulling them.",
+}
+
+// createPreloadCmd represents the preload create command
+var createPreloadCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a preload from the images of the running cluster, or from a list of images.",
+	Long: `Create a preload from the images of the running cluster, or from a list of images.
+
+Without --images-from, every tagged image of the container runtime of the cluster is saved, including the images built
+or loaded into it, and the preload can only be loaded into the same container runtime.
+With --images-from, the images of Kubernetes and the images listed in the file, one per line, are pulled from their registries.`,
+	Example: `minikube preload create --kubernetes-version v1.15.2 --images-from images.txt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 {
+			exit.UsageT("usage: minikube preload create [flags]")
+		}
+		var m preload.Manifest
+		var files map[string]string
+		if preloadImagesFrom != "" {
+			m, files = pullPreload()
+		} else {
+			dir, err := ioutil.TempDir("", "minikube-preload")
+			if err != nil {
+				exit.WithError("Failed to create a temp dir", err)
+			}
+			defer os.RemoveAll(dir)
+			m, files = savePreload(dir)
+		}
+		output := preloadFile
+		if output == "" {
+			output = fmt.Sprintf("preload-%s.tar.gz", m.KubernetesVersion)
+		}
+		out.T(out.FileDownload, "Writing {{.count}} images to {{.path}} ...", out.V{"count": len(files), "path": output})
+		if err := preload.Create(output, m, files); err != nil {
+			exit.WithError("Failed to create preload", err)
+		}
+		out.T(out.Celebrate, "Created the preload {{.path}} for Kubernetes {{.version}}. To use it, run: {{.command}}", out.V{"path": output, "version": m.KubernetesVersion, "command": "minikube start --kubernetes-version=" + m.KubernetesVersion + " --preload=" + output})
+	},
+}
+
+// pullPreload pulls the images of Kubernetes and of the --images-from file into the image cache
+func pullPreload() (preload.Manifest, map[string]string) {
+	f, err := os.Open(preloadImagesFrom)
+	if err != nil {
+		exit.WithCodeT(exit.NoInput, "Unable to read {{.path}}: {{.error}}", out.V{"path": preloadImagesFrom, "error": err})
+	}
+	extra, err := readImageList(f)
+	f.Close()
+	if err != nil {
+		exit.WithError("Failed to read the image list", err)
+	}
+	m := preload.Manifest{KubernetesVersion: preloadK8sVersion}
+	if m.KubernetesVersion == "" {
+		m.KubernetesVersion = constants.DefaultKubernetesVersion
+	}
+	images := append(bootstrapper.GetCachedImageList(preloadImageRepository, m.KubernetesVersion, viper.GetString(cmdcfg.Bootstrapper)), extra...)
+	out.T(out.Caching, "Downloading {{.count}} images ...", out.V{"count": len(images)})
+	if err := machine.CacheImages(images, constants.ImageCacheDir); err != nil {
+		exit.WithError("Failed to download images", err)
+	}
+	files := map[string]string{}
+	for _, img := range images {
+		files[img] = machine.ImageCachePath(img)
+	}
+	return m, files
+}
+
+// savePreload saves the images of the container runtime of the running cluster to dir
+func savePreload(dir string) (preload.Manifest, map[string]string) {
+	runner, cc := profileRunner()
+	m := preload.Manifest{KubernetesVersion: cc.KubernetesConfig.KubernetesVersion}
+	if preloadK8sVersion != "" && preloadK8sVersion != m.KubernetesVersion {
+		exit.UsageT("The cluster runs Kubernetes {{.version}}. To create a preload for {{.wanted}}, use --images-from", out.V{"version": m.KubernetesVersion, "wanted": preloadK8sVersion})
+	}
+	r, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		exit.WithError("Failed to get the container runtime", err)
+	}
+	m.ContainerRuntime = r.Name()
+	images, err := r.ListImages()
+	if err != nil {
+		exit.WithError("Failed to list images", err)
+	}
+	out.T(out.Caching, "Saving {{.count}} images from {{.runtime}} ...", out.V{"count": len(images), "runtime": r.Name()})
+	files, err := machine.SaveImages(runner, cc.KubernetesConfig, images, dir)
+	if err != nil {
+		exit.WithError("Failed to save images", err)
+	}
+	return m, files
+}
+
+// readImageList returns the images listed one per line, ignoring blank lines and comments
+func readImageList(r io.Reader) ([]string, error) {
+	images := []string{}
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, s.Err()
+}
+
+func init() {
+	createPreloadCmd.Flags().StringVar(&preloadK8sVersion, "kubernetes-version", "", "The kubernetes version of the preload (ex: v1.2.3). Defaults to the version of the running cluster, or with --images-from, to "+constants.DefaultKubernetesVersion)
+	createPreloadCmd.Flags().StringVar(&preloadImagesFrom, "images-from", "", "Pull the images of Kubernetes and the images listed in this file, instead of saving the images of the running cluster")
+	createPreloadCmd.Flags().StringVar(&preloadImageRepository, "image-repository", "", "Alternative image repository to pull the Kubernetes images from, with --images-from")
+	createPreloadCmd.Flags().StringVarP(&preloadFile, "file", "f", "", "Path of the preload to create (default \"preload-<kubernetes-version>.tar.gz\")")
+	preloadCmd.AddCommand(createPreloadCmd)
+}
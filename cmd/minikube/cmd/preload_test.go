@@ -0,0 +1,35 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadImageList(t *testing.T) {
+	list := "# base images\nregistry.example.com/base/java:11\n\n  registry.example.com/base/node:10  \n"
+	got, err := readImageList(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("readImageList: %v", err)
+	}
+	want := []string{"registry.example.com/base/java:11", "registry.example.com/base/node:10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("readImageList() = %v, want %v", got, want)
+	}
+}
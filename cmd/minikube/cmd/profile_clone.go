@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	units "github.com/docker/go-units"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+)
+
+var cloneDisk bool
+
+// profileCloneCmd represents the profile clone command
+var profileCloneCmd = &cobra.Command{
+	Use:   "clone SOURCE DESTINATION",
+	Short: "Creates a profile with the config of another one.",
+	Long: `Creates a profile with the config of another one, such as to run a second, identical cluster for A/B testing.
+
+The cluster of the new profile is created by the next 'minikube start -p DESTINATION'. Flags given to start take precedence over the cloned config.
+With --disk, the new VM starts from a copy of the disk of the source VM, with its images and the objects of its cluster.
+The source VM must be stopped, and use the kvm2, qemu2, hyperkit or vfkit driver.`,
+	Example: `minikube profile clone minikube minikube-b --disk`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.UsageT("usage: minikube profile clone SOURCE DESTINATION [--disk]")
+		}
+		src, dst := args[0], args[1]
+		var cc *config.Config
+		if cloneDisk {
+			cc = stoppedSnapshotHost(src)
+		} else {
+			var err error
+			cc, err = config.DefaultLoader.LoadConfigFromFile(src)
+			if err != nil {
+				if os.IsNotExist(err) {
+					exit.WithCodeT(exit.NoInput, "Profile {{.name}} does not exist", out.V{"name": src})
+				}
+				exit.WithError("Error loading profile config", err)
+			}
+		}
+		if _, err := os.Stat(constants.MakeMiniPath("machines", dst, "config.json")); err == nil {
+			exit.WithCodeT(exit.Config, "A VM already exists for profile {{.name}}: delete it with 'minikube delete -p {{.name}}'", out.V{"name": dst})
+		}
+
+		// The UUID and IP identify the source VM
+		cc.MachineConfig.UUID = ""
+		cc.KubernetesConfig.NodeIP = ""
+		if err := config.CreateProfile(dst, *cc); err != nil {
+			exit.WithError("Failed to create profile", err)
+		}
+		if cloneDisk {
+			out.T(out.Copying, "Copying the disk of {{.src}} to {{.dst}} ...", out.V{"src": src, "dst": dst})
+			size, err := cloneMachineDisk(src, dst)
+			if err != nil {
+				exit.WithError("Failed to copy the disk", err)
+			}
+			out.T(out.SuccessType, "Copied the disk ({{.size}})", out.V{"size": units.HumanSize(float64(size))})
+		}
+		out.T(out.Ready, "Created profile {{.dst}} from {{.src}}. To create its cluster, run: minikube start -p {{.dst}}", out.V{"src": src, "dst": dst})
+	},
+}
+
+// cloneMachineDisk copies the disk and the ssh keys of the VM of src to the machine directory of dst.
+// The driver reuses them when it creates the VM of dst, since the disk only authorizes the key of src.
+func cloneMachineDisk(src, dst string) (int64, error) {
+	srcDir := constants.MakeMiniPath("machines", src)
+	dstDir := constants.MakeMiniPath("machines", dst)
+	if err := os.MkdirAll(dstDir, 0700); err != nil {
+		return 0, err
+	}
+	for _, key := range []string{"id_rsa", "id_rsa.pub"} {
+		b, err := ioutil.ReadFile(filepath.Join(srcDir, key))
+		if err != nil {
+			return 0, errors.Wrap(err, "reading ssh key")
+		}
+		if err := ioutil.WriteFile(filepath.Join(dstDir, key), b, 0600); err != nil {
+			return 0, errors.Wrap(err, "writing ssh key")
+		}
+	}
+	return snapshot.CopyDisk(snapshotDisk(src), snapshotDisk(dst))
+}
+
+func init() {
+	profileCloneCmd.Flags().BoolVar(&cloneDisk, "disk", false, "Also copy the disk of the stopped source VM, so that the new cluster starts with its images and objects")
+	configCmd.ProfileCmd.AddCommand(profileCloneCmd)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/util/homedir"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/doctor"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/logs"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/report"
+	"k8s.io/minikube/pkg/version"
+)
+
+const (
+	// reportLogFiles is how many of the most recent minikube log files are added to a report
+	reportLogFiles = 5
+	// reportMaxLogSize is the size above which only the end of a log file is added to a report
+	reportMaxLogSize = 1024 * 1024
+)
+
+var (
+	reportFile   string
+	reportLength int
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Gathers diagnostics of minikube and the cluster into a tarball to attach to an issue.",
+	Long: `Gathers diagnostics of minikube and the cluster into a tarball to attach to an issue.
+
+The report holds the version of minikube and of the host, the results of 'minikube doctor', the configuration of the profile
+and of its machine, the most recent minikube logs, and, when the cluster is running, the logs shown by 'minikube logs'.
+Private keys, passwords, tokens and the credentials of proxy URLs are redacted, as are the name and the home directory of the user.
+Check the content of the report before you share it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		path := reportFile
+		if path == "" {
+			path = fmt.Sprintf("minikube-report-%s-%s.tar.gz", profile, time.Now().Format("20060102-150405"))
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			exit.WithError("Failed to create the report", err)
+		}
+		b := report.NewBundle(f, reportRedactor())
+
+		out.T(out.Verifying, "Gathering diagnostics of {{.profile}} ...", out.V{"profile": profile})
+		cc, err := config.Load()
+		if err != nil && !os.IsNotExist(err) {
+			glog.Warningf("Error loading profile config: %v", err)
+		}
+		driver := constants.DefaultVMDriver
+		if cc != nil {
+			driver = cc.MachineConfig.VMDriver
+		}
+		addReport(b, "report.txt", reportSummary(profile, driver))
+		addReport(b, "doctor.txt", reportDoctor(driver))
+		addReportDir(b, "profile", constants.GetProfilePath(profile))
+		addReportFile(b, "machine/config.json", filepath.Join(constants.GetMinipath(), "machines", profile, "config.json"))
+		for _, p := range reportLogs() {
+			addReportFile(b, filepath.Join("logs", filepath.Base(p)), p)
+		}
+		if cc != nil {
+			for name, data := range reportClusterLogs(cc) {
+				addReport(b, filepath.Join("cluster", name+".log"), data)
+			}
+		}
+
+		if err := b.Close(); err != nil {
+			exit.WithError("Failed to write the report", err)
+		}
+		if err := f.Close(); err != nil {
+			exit.WithError("Failed to write the report", err)
+		}
+		out.T(out.SuccessType, "Wrote the report to {{.path}}", out.V{"path": path})
+		out.T(out.Tip, "Check its content before you attach it to an issue: tar -tzvf {{.path}}", out.V{"path": path})
+	},
+}
+
+// reportRedactor returns a redactor of the name and the home directory of the current user
+func reportRedactor() *report.Redactor {
+	name := ""
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	} else {
+		glog.Warningf("Unable to get the current user: %v", err)
+	}
+	return report.NewRedactor(name, homedir.HomeDir())
+}
+
+// addReport adds a file to the report
+func addReport(b *report.Bundle, name string, data []byte) {
+	if err := b.Add(name, data); err != nil {
+		exit.WithError("Failed to write the report", err)
+	}
+}
+
+// addReportFile adds a file of the host to the report, skipping the files which do not exist
+func addReportFile(b *report.Bundle, name string, path string) {
+	if err := b.AddFile(name, path, reportMaxLogSize); err != nil {
+		if !os.IsNotExist(err) {
+			out.WarningT("Unable to add {{.path}} to the report: {{.error}}", out.V{"path": path, "error": err})
+		}
+	}
+}
+
+// addReportDir adds the JSON files of a directory of the host to the report
+func addReportDir(b *report.Bundle, name string, dir string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		glog.Warningf("glob %s: %v", dir, err)
+		return
+	}
+	for _, p := range paths {
+		addReportFile(b, filepath.Join(name, filepath.Base(p)), p)
+	}
+}
+
+// reportSummary describes minikube and the host
+func reportSummary(profile string, driver string) []byte {
+	var s bytes.Buffer
+	fmt.Fprintf(&s, "minikube version: %s\n", version.GetVersion())
+	fmt.Fprintf(&s, "commit: %s\n", version.GetGitCommitID())
+	fmt.Fprintf(&s, "host: %s %s/%s\n", platform(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&s, "profile: %s\n", profile)
+	fmt.Fprintf(&s, "driver: %s\n", driver)
+	fmt.Fprintf(&s, "created: %s\n", time.Now().UTC().Format(time.RFC3339))
+	return s.Bytes()
+}
+
+// doctorStatus names the outcomes of the checks of minikube doctor
+var doctorStatus = map[doctor.Status]string{
+	doctor.OK:      "ok",
+	doctor.Warning: "warning",
+	doctor.Failure: "failure",
+	doctor.Skipped: "skipped",
+}
+
+// reportDoctor returns the results of the checks of minikube doctor
+func reportDoctor(driver string) []byte {
+	var s bytes.Buffer
+	env := doctor.Env{GOOS: runtime.GOOS, Driver: driver, MinikubeHome: constants.GetMinipath()}
+	for _, r := range doctor.Run(env, doctor.Checks) {
+		fmt.Fprintf(&s, "%s: %s: %s\n", doctorStatus[r.Status], r.Name, r.Message)
+	}
+	return s.Bytes()
+}
+
+// reportLogs returns the most recent log files of minikube, newest first
+func reportLogs() []string {
+	dir := constants.MakeMiniPath("logs")
+	if f := pflag.Lookup("log_dir"); f != nil && f.Value.String() != "" {
+		dir = f.Value.String()
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "minikube.*.log.*"))
+	if err != nil {
+		glog.Warningf("glob %s: %v", dir, err)
+		return nil
+	}
+	mtimes := map[string]time.Time{}
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err == nil {
+			mtimes[p] = fi.ModTime()
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return mtimes[paths[i]].After(mtimes[paths[j]]) })
+	if len(paths) > reportLogFiles {
+		paths = paths[:reportLogFiles]
+	}
+	return paths
+}
+
+// reportClusterLogs returns the logs of the cluster, if it is running
+func reportClusterLogs(cc *config.Config) map[string][]byte {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		glog.Warningf("Error getting client: %v", err)
+		return nil
+	}
+	defer api.Close()
+	h, err := api.Load(config.GetMachineName())
+	if err != nil {
+		glog.Infof("No machine to gather logs from: %v", err)
+		return nil
+	}
+	if s, err := h.Driver.GetState(); err != nil || s != state.Running {
+		out.T(out.Meh, "The cluster is not running: its logs are not part of the report")
+		return nil
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		glog.Warningf("command runner: %v", err)
+		return nil
+	}
+	bs, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+	if err != nil {
+		glog.Warningf("Error getting cluster bootstrapper: %v", err)
+		return nil
+	}
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		glog.Warningf("Unable to get runtime: %v", err)
+		return nil
+	}
+	l, err := logs.Collect(cr, bs, runner, reportLength)
+	if err != nil {
+		out.WarningT("Some logs of the cluster are missing from the report: {{.error}}", out.V{"error": err})
+	}
+	return l
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportFile, "file", "f", "", "Path of the report to create (default \"minikube-report-<profile>-<time>.tar.gz\")")
+	reportCmd.Flags().IntVarP(&reportLength, "length", "n", 500, "Number of lines of each log of the cluster to add to the report")
+}
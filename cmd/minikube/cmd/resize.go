@@ -0,0 +1,151 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var (
+	resizeCPUs     int
+	resizeMemory   string
+	resizeDiskSize string
+)
+
+// resizeCmd represents the resize command
+var resizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Changes the CPUs, memory and disk size of the minikube VM, without deleting it.",
+	Long: `Changes the CPUs, memory and disk size of the minikube VM, without deleting it.
+
+A running VM is stopped, resized, and started again with 'minikube start'. The disk can only grow: the data partition
+of the VM is grown to the end of the disk when it boots. Resizing is supported by the kvm2, qemu2, hyperkit, vfkit
+and hyperv drivers, and by the virtualbox driver for CPUs and memory.`,
+	Example: `minikube resize --cpus 4 --memory 8g --disk-size 40g`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flags().Changed("cpus") && !cmd.Flags().Changed("memory") && !cmd.Flags().Changed("disk-size") {
+			exit.UsageT("Please specify --cpus, --memory or --disk-size")
+		}
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		r := resizeResources(cmd)
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		s, err := h.Driver.GetState()
+		if err != nil {
+			exit.WithError("Error getting host state", err)
+		}
+		if s == state.Running {
+			if err := pkgutil.RetryAfter(3, func() error { return cluster.StopHost(api) }, 2*time.Second); err != nil {
+				exit.WithError("Unable to stop VM", err)
+			}
+		}
+
+		out.T(out.Reconfiguring, "Resizing {{.profile}} ...", out.V{"profile": config.GetMachineName()})
+		if err := cluster.ResizeHost(api, config.GetMachineName(), r); err != nil {
+			exit.WithError("Failed to resize VM", err)
+		}
+		if r.CPUs != 0 {
+			cc.MachineConfig.CPUs = r.CPUs
+		}
+		if r.Memory != 0 {
+			cc.MachineConfig.Memory = r.Memory
+		}
+		if r.DiskSize != 0 {
+			cc.MachineConfig.DiskSize = r.DiskSize
+		}
+		if err := saveConfig(cc); err != nil {
+			exit.WithError("Failed to save config", err)
+		}
+		out.T(out.SuccessType, "{{.profile}} now has CPUs={{.cpus}}, Memory={{.memory}}MB, Disk={{.disk}}MB", out.V{"profile": config.GetMachineName(), "cpus": cc.MachineConfig.CPUs, "memory": cc.MachineConfig.Memory, "disk": cc.MachineConfig.DiskSize})
+
+		if s != state.Running {
+			out.T(out.Tip, "Run 'minikube start' to start it")
+			return
+		}
+		c := exec.Command(os.Args[0], append([]string{"start"}, profileStartArgs(cc)...)...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			exit.WithError("Failed to start the resized VM", err)
+		}
+	},
+}
+
+// resizeResources returns the resources given on the command line, in the units of the driver configurations
+func resizeResources(cmd *cobra.Command) cluster.Resources {
+	var r cluster.Resources
+	if cmd.Flags().Changed("cpus") {
+		if resizeCPUs < 1 {
+			exit.UsageT("--cpus must be at least 1")
+		}
+		r.CPUs = resizeCPUs
+	}
+	if cmd.Flags().Changed("memory") {
+		r.Memory = pkgutil.CalculateSizeInMB(resizeMemory)
+		if r.Memory < pkgutil.CalculateSizeInMB(constants.MinimumMemorySize) {
+			exit.UsageT("Requested memory allocation {{.requested_size}} is less than the minimum allowed of {{.minimum_size}}", out.V{"requested_size": r.Memory, "minimum_size": pkgutil.CalculateSizeInMB(constants.MinimumMemorySize)})
+		}
+	}
+	if cmd.Flags().Changed("disk-size") {
+		r.DiskSize = pkgutil.CalculateSizeInMB(resizeDiskSize)
+	}
+	return r
+}
+
+// profileStartArgs returns the arguments of 'minikube start' which start the cluster of a profile as it is configured
+func profileStartArgs(cc *config.Config) []string {
+	m := cc.MachineConfig
+	return []string{
+		"--" + config.MachineProfile + "=" + config.GetMachineName(),
+		"--" + vmDriver + "=" + m.VMDriver,
+		"--" + containerRuntime + "=" + m.ContainerRuntime,
+		"--" + kubernetesVersion + "=" + cc.KubernetesConfig.KubernetesVersion,
+		"--" + cpus + "=" + strconv.Itoa(m.CPUs),
+		"--" + memory + "=" + strconv.Itoa(m.Memory) + "mb",
+		"--" + humanReadableDiskSize + "=" + strconv.Itoa(m.DiskSize) + "mb",
+	}
+}
+
+func init() {
+	resizeCmd.Flags().IntVar(&resizeCPUs, "cpus", 0, "Number of CPUs allocated to the minikube VM")
+	resizeCmd.Flags().StringVar(&resizeMemory, "memory", "", "Amount of RAM allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g)")
+	resizeCmd.Flags().StringVar(&resizeDiskSize, "disk-size", "", "Disk size allocated to the minikube VM, which can only grow (format: <number>[<unit>], where unit = b, k, m or g)")
+}
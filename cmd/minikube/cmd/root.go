@@ -21,7 +21,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/log"
@@ -32,13 +34,19 @@ import (
 	"github.com/spf13/viper"
 	"k8s.io/kubectl/pkg/util/templates"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/k0s"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/k3s"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/errreport"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/notify"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/translate"
+	"k8s.io/minikube/pkg/version"
 )
 
 var dirs = [...]string{
@@ -57,6 +65,9 @@ var (
 	enableUpdateNotification = true
 )
 
+// outputFormat is the name of the flag which selects text or JSON output
+const outputFormat = "output"
+
 var viperWhiteList = []string{
 	"v",
 	"alsologtostderr",
@@ -69,12 +80,20 @@ var RootCmd = &cobra.Command{
 	Short: "Minikube is a tool for managing local Kubernetes clusters.",
 	Long:  `Minikube is a CLI tool that provisions and manages single-node Kubernetes clusters optimized for development workflows.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := out.SetOutputFormat(viper.GetString(outputFormat)); err != nil {
+			exit.UsageT("{{.error}}", out.V{"error": err})
+		}
+
 		for _, path := range dirs {
 			if err := os.MkdirAll(path, 0777); err != nil {
 				exit.WithError("Error creating minikube directory", err)
 			}
 		}
 
+		if err := assets.LoadInstalledAddons(); err != nil {
+			glog.Warningf("Unable to load installed addons: %v", err)
+		}
+
 		// Log level 3 or greater enables libmachine logs
 		if !glog.V(3) {
 			log.SetOutWriter(ioutil.Discard)
@@ -93,12 +112,46 @@ var RootCmd = &cobra.Command{
 			}
 		}
 
-		if enableUpdateNotification {
+		setupErrorReporting(cmd)
+
+		// The notification would mix with the JSON documents written by commands
+		if enableUpdateNotification && !out.IsJSON() {
 			notify.MaybePrintUpdateTextFromGithub()
 		}
 	},
 }
 
+// setupErrorReporting reports the errors minikube exits with, if the user opted in
+func setupErrorReporting(cmd *cobra.Command) {
+	if !viper.GetBool(config.WantReportError) {
+		return
+	}
+	url := viper.GetString(config.ErrorReportingURL)
+	if url == "" {
+		glog.Infof("%s is set, but no %s to report errors to", config.WantReportError, config.ErrorReportingURL)
+		return
+	}
+	r := errreport.New(url, constants.MakeMiniPath("error-reports"))
+	exit.AtExit(func(code int, msg string) {
+		// Usage errors and interruptions are not failures of minikube
+		if code == exit.BadUsage || code == exit.Interrupted {
+			return
+		}
+		err := r.Send(errreport.Report{
+			Version: version.GetVersion(),
+			GOOS:    runtime.GOOS,
+			GOARCH:  runtime.GOARCH,
+			Command: cmd.CommandPath(),
+			Code:    code,
+			Message: msg,
+			Time:    time.Now().UTC(),
+		})
+		if err != nil {
+			glog.Warningf("Unable to report error: %v", err)
+		}
+	})
+}
+
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -175,7 +228,8 @@ func setFlagsUsingViper() {
 func init() {
 	translate.DetermineLocale()
 	RootCmd.PersistentFlags().StringP(config.MachineProfile, "p", constants.DefaultMachineName, `The name of the minikube VM being used. This can be set to allow having multiple instances of minikube independently.`)
-	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", constants.DefaultClusterBootstrapper, "The name of the cluster bootstrapper that will set up the kubernetes cluster.")
+	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", constants.DefaultClusterBootstrapper, "The name of the cluster bootstrapper that will set up the kubernetes cluster. (kubeadm, k3s, k0s)")
+	RootCmd.PersistentFlags().StringP(outputFormat, "o", out.TextOutput, "Format of the output: 'text' or 'json'. Messages are written as JSON lines, and commands such as status write their result as a single JSON document.")
 
 	groups := templates.CommandGroups{
 		{
@@ -186,13 +240,19 @@ func init() {
 				stopCmd,
 				deleteCmd,
 				dashboardCmd,
+				consoleCmd,
 			},
 		},
 		{
 			Message: translate.T("Images Commands:"),
 			Commands: []*cobra.Command{
 				dockerEnvCmd,
+				nerdctlEnvCmd,
 				cacheCmd,
+				imageCmd,
+				bundleCmd,
+				preloadCmd,
+				devCmd,
 			},
 		},
 		{
@@ -202,6 +262,18 @@ func init() {
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
 				updateContextCmd,
+				kubeconfigCmd,
+				scheduleCmd,
+				autoStopCmd,
+				backupCmd,
+				restoreCmd,
+				certsCmd,
+				secretsCmd,
+				kubernetesCmd,
+				nodeCmd,
+				storageCmd,
+				snapshotCmd,
+				resizeCmd,
 			},
 		},
 		{
@@ -209,6 +281,10 @@ func init() {
 			Commands: []*cobra.Command{
 				serviceCmd,
 				tunnelCmd,
+				portForwardCmd,
+				dnsCmd,
+				trustCmd,
+				networkCmd,
 			},
 		},
 		{
@@ -216,6 +292,8 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				cpCmd,
+				execCmd,
 				kubectlCmd,
 			},
 		},
@@ -223,8 +301,15 @@ func init() {
 			Message: translate.T("Troubleshooting Commands:"),
 			Commands: []*cobra.Command{
 				sshKeyCmd,
+				sshConfigCmd,
 				ipCmd,
 				logsCmd,
+				eventsCmd,
+				auditCmd,
+				topCmd,
+				reportCmd,
+				doctorCmd,
+				benchCmd,
 				updateCheckCmd,
 				versionCmd,
 			},
@@ -269,6 +354,7 @@ func setupViper() {
 	viper.SetDefault(config.WantReportErrorPrompt, true)
 	viper.SetDefault(config.WantKubectlDownloadMsg, true)
 	viper.SetDefault(config.WantNoneDriverWarning, true)
+	viper.SetDefault(config.WantRemoteProblems, true)
 	viper.SetDefault(config.ShowDriverDeprecationNotification, true)
 	viper.SetDefault(config.ShowBootstrapperDeprecationNotification, true)
 	setFlagsUsingViper()
@@ -284,6 +370,16 @@ func getClusterBootstrapper(api libmachine.API, bootstrapperName string) (bootst
 		if err != nil {
 			return nil, errors.Wrap(err, "getting kubeadm bootstrapper")
 		}
+	case bootstrapper.BootstrapperTypeK3s:
+		b, err = k3s.NewK3sBootstrapper(api)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting k3s bootstrapper")
+		}
+	case bootstrapper.BootstrapperTypeK0s:
+		b, err = k0s.NewK0sBootstrapper(api)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting k0s bootstrapper")
+		}
 	default:
 		return nil, fmt.Errorf("unknown bootstrapper: %s", bootstrapperName)
 	}
@@ -0,0 +1,152 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/schedule"
+)
+
+// scheduleCmd represents the schedule command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "List or clear the clusters scheduled to start with 'minikube start --schedule'",
+	Long:  "List or clear the clusters scheduled to start with 'minikube start --schedule'.",
+}
+
+// scheduleListCmd represents the schedule list command
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the scheduled starts of all profiles",
+	Long:  "Lists the scheduled starts of all profiles.",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := schedule.List()
+		if err != nil {
+			exit.WithError("Error listing scheduled starts", err)
+		}
+		if len(entries) == 0 {
+			out.T(out.Empty, "No cluster is scheduled to start")
+			return
+		}
+		for _, e := range entries {
+			out.T(out.Option, "{{.profile}}: {{.when}}", out.V{"profile": e.Profile, "when": e.String()})
+		}
+	},
+}
+
+// scheduleClearCmd represents the schedule clear command
+var scheduleClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Cancels the scheduled start of the profile",
+	Long:  "Cancels the scheduled start of the profile, selected with --profile.",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		e, err := schedule.Load(profile)
+		if err != nil {
+			exit.WithError("Error loading scheduled start", err)
+		}
+		if e == nil {
+			out.T(out.Meh, "No start is scheduled for {{.profile}}", out.V{"profile": profile})
+			return
+		}
+		if err := schedule.Clear(profile); err != nil {
+			exit.WithError("Error clearing scheduled start", err)
+		}
+		out.T(out.Stopped, "Cleared the scheduled start of {{.profile}}", out.V{"profile": profile})
+	},
+}
+
+// scheduleRunCmd is run in the background by 'minikube start --schedule' to wait for the start time
+var scheduleRunCmd = &cobra.Command{
+	Use:    "run",
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := config.GetMachineName()
+		startArgs, err := schedule.Wait(profile)
+		if err != nil {
+			exit.WithError("Error waiting for scheduled start", err)
+		}
+		glog.Infof("Running: minikube %s", strings.Join(startArgs, " "))
+		c := exec.Command(os.Args[0], startArgs...)
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			exit.WithError("Scheduled start failed", err)
+		}
+	},
+}
+
+// scheduleStart schedules the current start command to run later, instead of running it
+func scheduleStart(cmd *cobra.Command, when string) {
+	at, onLogin, err := schedule.Parse(when, time.Now())
+	if err != nil {
+		exit.UsageT("{{.error}}", out.V{"error": err})
+	}
+	profile := config.GetMachineName()
+	e, err := schedule.Add(schedule.Entry{
+		Profile: profile,
+		At:      at,
+		OnLogin: onLogin,
+		Args:    scheduledStartArgs(os.Args[1:], cmd.Flags().Changed(config.MachineProfile), profile),
+	})
+	if err != nil {
+		exit.WithError("Error scheduling start", err)
+	}
+	if e.OnLogin {
+		out.T(out.Notice, "{{.profile}} will start each time you log in, through {{.path}}", out.V{"profile": profile, "path": schedule.LoginItemPath(profile)})
+	} else {
+		out.T(out.Notice, "{{.profile}} will start at {{.when}}, logs will be written to {{.path}}", out.V{"profile": profile, "when": e.String(), "path": schedule.LogFilePath(profile)})
+		out.T(out.Tip, "The scheduled start is cancelled if the host restarts first")
+	}
+	out.T(out.Tip, "To cancel it, run: minikube schedule clear -p {{.profile}}", out.V{"profile": profile})
+}
+
+// scheduledStartArgs returns the arguments of the start command without --schedule,
+// and with the profile, so that the scheduled start does not depend on the current profile
+func scheduledStartArgs(args []string, hasProfile bool, profile string) []string {
+	result := []string{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--"+startSchedule {
+			i++
+			continue
+		}
+		if strings.HasPrefix(a, "--"+startSchedule+"=") {
+			continue
+		}
+		result = append(result, a)
+	}
+	if !hasProfile {
+		result = append(result, "--"+config.MachineProfile+"="+profile)
+	}
+	return result
+}
+
+func init() {
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleClearCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+}
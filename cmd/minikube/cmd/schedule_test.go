@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScheduledStartArgs(t *testing.T) {
+	var tests = []struct {
+		args       []string
+		hasProfile bool
+		want       []string
+	}{
+		{
+			args: []string{"start", "--schedule", "2h", "--memory=4096"},
+			want: []string{"start", "--memory=4096", "--profile=minikube"},
+		},
+		{
+			args:       []string{"start", "-p", "dev", "--schedule=login"},
+			hasProfile: true,
+			want:       []string{"start", "-p", "dev"},
+		},
+	}
+	for _, tc := range tests {
+		got := scheduledStartArgs(tc.args, tc.hasProfile, "minikube")
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("scheduledStartArgs(%v) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
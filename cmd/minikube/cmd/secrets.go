@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/encryption"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+// secretsCmd represents the secrets command
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage the encryption of the secrets of the cluster",
+	Long: `Manage the encryption of the secrets of the cluster, enabled with 'minikube start --encrypt-secrets'.
+
+The key which encrypts the secrets in etcd is stored in ~/.minikube/profiles/<profile>/encryption-config.yaml.`,
+}
+
+// secretsRotateKeyCmd represents the secrets rotate-key command
+var secretsRotateKeyCmd = &cobra.Command{
+	Use:   "rotate-key",
+	Short: "Encrypts the secrets of the cluster with a new key",
+	Long: `Encrypts the secrets of the cluster with a new key, and removes the old key.
+
+A new key is added first, and the apiserver is restarted to encrypt with it. Every secret is then written again,
+so that it is encrypted with the new key. Finally, the old key is removed, and the apiserver is restarted again.
+If the rotation is interrupted, run it again: the old keys are kept until all the secrets are written again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runner, cc := profileRunner()
+		if !cc.KubernetesConfig.EncryptSecrets {
+			exit.WithCodeT(exit.Config, "The secrets of {{.profile}} are not encrypted, start it with --{{.flag}}", out.V{"profile": config.GetMachineName(), "flag": encryptSecrets})
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			exit.WithError("Unable to get runtime", err)
+		}
+		path := encryption.Path(config.GetMachineName())
+		ec, err := encryption.Load(path)
+		if err != nil {
+			exit.WithCodeT(exit.Data, "Unable to load the encryption config {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+		}
+
+		name, err := ec.AddKey()
+		if err != nil {
+			exit.WithError("Failed to add a key", err)
+		}
+		out.T(out.Permissions, "Encrypting secrets with {{.key}} ...", out.V{"key": name})
+		applyEncryption(runner, cr, ec, path)
+
+		var n int
+		rewrite := func() error {
+			client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+			if err != nil {
+				return err
+			}
+			n, err = encryption.RewriteSecrets(client)
+			return err
+		}
+		// The apiserver is restarting
+		if err := pkgutil.RetryAfter(30, rewrite, 2*time.Second); err != nil {
+			exit.WithError("Failed to write the secrets again", err)
+		}
+		out.T(out.Option, "Encrypted {{.count}} secrets with {{.key}}", out.V{"count": n, "key": name})
+
+		if err := ec.RemoveOldKeys(); err != nil {
+			exit.WithError("Failed to remove the old keys", err)
+		}
+		applyEncryption(runner, cr, ec, path)
+		out.T(out.Ready, "Rotated the encryption key, the apiserver is restarting")
+	},
+}
+
+// applyEncryption saves the EncryptionConfiguration, copies it to the VM, and restarts the apiserver to read it
+func applyEncryption(runner command.Runner, cr cruntime.Manager, ec *encryption.Configuration, path string) {
+	if err := ec.Save(path); err != nil {
+		exit.WithError("Failed to save the encryption config", err)
+	}
+	if err := ec.Copy(runner); err != nil {
+		exit.WithError("Failed to copy the encryption config", err)
+	}
+	if err := kubeadm.RestartAPIServer(cr); err != nil {
+		exit.WithError("Failed to restart the apiserver", err)
+	}
+}
+
+func init() {
+	secretsCmd.AddCommand(secretsRotateKeyCmd)
+}
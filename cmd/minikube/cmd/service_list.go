@@ -48,6 +48,20 @@ var serviceListCmd = &cobra.Command{
 			os.Exit(exit.Unavailable)
 		}
 
+		if out.IsJSON() {
+			l := service.URLs{}
+			for _, u := range serviceURLs {
+				if u.URLs == nil {
+					u.URLs = []string{}
+				}
+				l = append(l, u)
+			}
+			if err := out.JSON(l); err != nil {
+				exit.WithError("Error writing service URLs", err)
+			}
+			return
+		}
+
 		var data [][]string
 		for _, serviceURL := range serviceURLs {
 			if len(serviceURL.URLs) == 0 {
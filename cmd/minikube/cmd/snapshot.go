@@ -0,0 +1,180 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+)
+
+// snapshotDrivers are the drivers which keep the disk of the VM in a raw image
+var snapshotDrivers = []string{constants.DriverKvm2, constants.DriverQemu2, constants.DriverHyperkit, constants.DriverVfkit}
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore the disk of the minikube VM.",
+	Long: `Save and restore the disk of the minikube VM, with the configuration of the profile.
+
+Snapshots let you roll back to a known-good cluster after risky experiments. The VM must be stopped, with 'minikube stop',
+to create or restore a snapshot. Snapshots are supported by the kvm2, qemu2, hyperkit and vfkit drivers.`,
+}
+
+// createSnapshotCmd represents the snapshot create command
+var createSnapshotCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Saves the disk of the stopped minikube VM to a snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube snapshot create NAME")
+		}
+		cc := stoppedSnapshotHost(config.GetMachineName())
+		out.T(out.Caching, "Creating snapshot {{.name}} of {{.profile}} ...", out.V{"name": args[0], "profile": config.GetMachineName()})
+		s, err := snapshot.Create(config.GetMachineName(), args[0], snapshotDisk(config.GetMachineName()), cc.KubernetesConfig.KubernetesVersion)
+		if err != nil {
+			exit.WithError("Failed to create snapshot", err)
+		}
+		out.T(out.SuccessType, "Created snapshot {{.name}} ({{.size}})", out.V{"name": s.Name, "size": units.HumanSize(float64(s.Size))})
+	},
+}
+
+// listSnapshotsCmd represents the snapshot list command
+var listSnapshotsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of the profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		ss, err := snapshot.List(config.GetMachineName())
+		if err != nil {
+			exit.WithError("Failed to list snapshots", err)
+		}
+		if out.IsJSON() {
+			if err := out.JSON(ss); err != nil {
+				exit.WithError("Error writing snapshots", err)
+			}
+			return
+		}
+		if len(ss) == 0 {
+			out.T(out.Empty, "No snapshots found. You can create one using `minikube snapshot create NAME`.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Created", "Kubernetes Version", "Size"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, s := range ss {
+			table.Append([]string{s.Name, s.Created.Local().Format(time.RFC1123), s.KubernetesVersion, units.HumanSize(float64(s.Size))})
+		}
+		table.Render()
+	},
+}
+
+// restoreSnapshotCmd represents the snapshot restore command
+var restoreSnapshotCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Replaces the disk of the stopped minikube VM by a snapshot",
+	Long: `Replaces the disk of the stopped minikube VM by a snapshot, and the configuration of the profile by the one saved with it.
+
+Everything written to the VM since the snapshot was created is lost. Run 'minikube start' afterwards to start the restored cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube snapshot restore NAME")
+		}
+		stoppedSnapshotHost(config.GetMachineName())
+		s, err := snapshot.Restore(config.GetMachineName(), args[0], snapshotDisk(config.GetMachineName()))
+		if err != nil {
+			exit.WithError("Failed to restore snapshot", err)
+		}
+		out.T(out.Restarting, "Restored snapshot {{.name}} created on {{.date}}", out.V{"name": s.Name, "date": s.Created.Local().Format(time.RFC1123)})
+		out.T(out.Tip, "Run 'minikube start' to start the restored cluster")
+	},
+}
+
+// deleteSnapshotCmd represents the snapshot delete command
+var deleteSnapshotCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Deletes a snapshot",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.UsageT("usage: minikube snapshot delete NAME")
+		}
+		if err := snapshot.Delete(config.GetMachineName(), args[0]); err != nil {
+			exit.WithError("Failed to delete snapshot", err)
+		}
+		out.T(out.DeletingHost, "Deleted snapshot {{.name}}", out.V{"name": args[0]})
+	},
+}
+
+// stoppedSnapshotHost exits unless the VM of the profile exists, is stopped, and its driver supports snapshots
+func stoppedSnapshotHost(profile string) *config.Config {
+	cc, err := config.DefaultLoader.LoadConfigFromFile(profile)
+	if err != nil {
+		exit.WithError("Error loading profile config", err)
+	}
+	supported := false
+	for _, d := range snapshotDrivers {
+		if cc.MachineConfig.VMDriver == d {
+			supported = true
+		}
+	}
+	if !supported {
+		exit.WithCodeT(exit.Config, "Snapshots are not supported by the {{.driver}} driver", out.V{"driver": cc.MachineConfig.VMDriver})
+	}
+
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		exit.WithError("Error getting client", err)
+	}
+	defer api.Close()
+	h, err := cluster.CheckIfHostExistsAndLoad(api, profile)
+	if err != nil {
+		exit.WithError("Error getting host", err)
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		exit.WithError("Error getting host state", err)
+	}
+	if s != state.Stopped {
+		exit.WithCodeT(exit.Unavailable, "The VM is {{.state}}: run 'minikube stop' first", out.V{"state": s})
+	}
+	return cc
+}
+
+// snapshotDisk returns the path of the disk image of the VM of a profile
+func snapshotDisk(profile string) string {
+	return filepath.Join(constants.GetMinipath(), "machines", profile, profile+".rawdisk")
+}
+
+func init() {
+	snapshotCmd.AddCommand(createSnapshotCmd)
+	snapshotCmd.AddCommand(listSnapshotsCmd)
+	snapshotCmd.AddCommand(restoreSnapshotCmd)
+	snapshotCmd.AddCommand(deleteSnapshotCmd)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var sshConfigAll bool
+
+// sshHost is how OpenSSH connects to a node
+type sshHost struct {
+	Name         string
+	HostName     string
+	Port         int
+	User         string
+	IdentityFile string
+}
+
+// sshConfigCmd represents the ssh-config command
+var sshConfigCmd = &cobra.Command{
+	Use:   "ssh-config [PROFILE...]",
+	Short: "Prints the OpenSSH configuration of the nodes of minikube",
+	Long: `Prints the OpenSSH configuration of the nodes of minikube, as a Host block named after each node.
+
+Add the output to ~/.ssh/config, or include it from there, to connect with ssh, rsync, Ansible or VS Code Remote-SSH.
+The nodes of the current profile are printed, unless profiles are given, or --all is. Nodes whose machine is stopped are skipped,
+as their address is only known once they run. The host key of the machines is not checked, as it changes with every 'minikube delete'.`,
+	Example: `minikube ssh-config >> ~/.ssh/config
+ssh minikube`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profiles := args
+		if sshConfigAll {
+			valid, _, err := config.ListProfiles()
+			if err != nil {
+				exit.WithError("Error getting profiles", err)
+			}
+			profiles = []string{}
+			for _, p := range valid {
+				profiles = append(profiles, p.Name)
+			}
+		}
+		if len(profiles) == 0 {
+			profiles = []string{config.GetMachineName()}
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		blocks := []string{}
+		for _, p := range profiles {
+			h, err := profileSSHHost(api, p)
+			if err != nil {
+				out.WarningT("Skipping {{.profile}}: {{.error}}", out.V{"profile": p, "error": err})
+				continue
+			}
+			blocks = append(blocks, sshConfigBlock(h))
+		}
+		if len(blocks) == 0 {
+			exit.WithCodeT(exit.Unavailable, "No running node to connect to")
+		}
+		out.String("%s", strings.Join(blocks, "\n"))
+	},
+}
+
+// profileSSHHost returns how to connect to the node of a profile
+func profileSSHHost(api libmachine.API, profile string) (sshHost, error) {
+	h, err := api.Load(profile)
+	if err != nil {
+		return sshHost{}, errors.Wrap(err, "loading machine")
+	}
+	if h.Driver.DriverName() == constants.DriverNone {
+		return sshHost{}, fmt.Errorf("the none driver runs on the host, without ssh")
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return sshHost{}, errors.Wrap(err, "state")
+	}
+	if s != state.Running {
+		return sshHost{}, fmt.Errorf("the machine is %s", s)
+	}
+	hostname, err := h.Driver.GetSSHHostname()
+	if err != nil {
+		return sshHost{}, errors.Wrap(err, "hostname")
+	}
+	port, err := h.Driver.GetSSHPort()
+	if err != nil {
+		return sshHost{}, errors.Wrap(err, "port")
+	}
+	glog.Infof("ssh of %s: %s:%d", profile, hostname, port)
+	return sshHost{Name: profile, HostName: hostname, Port: port, User: h.Driver.GetSSHUsername(), IdentityFile: h.Driver.GetSSHKeyPath()}, nil
+}
+
+// sshConfigBlock returns the Host block of OpenSSH configuration for a node
+func sshConfigBlock(h sshHost) string {
+	key := h.IdentityFile
+	if strings.ContainsAny(key, " \t") {
+		key = `"` + key + `"`
+	}
+	lines := []string{
+		"Host " + h.Name,
+		"  HostName " + h.HostName,
+		fmt.Sprintf("  Port %d", h.Port),
+		"  User " + h.User,
+		"  IdentityFile " + key,
+		"  IdentitiesOnly yes",
+		"  StrictHostKeyChecking no",
+		"  UserKnownHostsFile /dev/null",
+		"  LogLevel ERROR",
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func init() {
+	sshConfigCmd.Flags().BoolVar(&sshConfigAll, "all", false, "Print the nodes of every profile")
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func TestSSHConfigBlock(t *testing.T) {
+	var tests = []struct {
+		key  string
+		want string
+	}{
+		{"/home/u/.minikube/machines/minikube/id_rsa", "  IdentityFile /home/u/.minikube/machines/minikube/id_rsa\n"},
+		{`C:\Users\Jane Doe\.minikube\machines\minikube\id_rsa`, `  IdentityFile "C:\Users\Jane Doe\.minikube\machines\minikube\id_rsa"` + "\n"},
+	}
+	for _, tc := range tests {
+		got := sshConfigBlock(sshHost{Name: "minikube", HostName: "192.168.39.2", Port: 22, User: "docker", IdentityFile: tc.key})
+		want := "Host minikube\n  HostName 192.168.39.2\n  Port 22\n  User docker\n" + tc.want +
+			"  IdentitiesOnly yes\n  StrictHostKeyChecking no\n  UserKnownHostsFile /dev/null\n  LogLevel ERROR\n"
+		if got != want {
+			t.Errorf("sshConfigBlock(%q) = %q, want %q", tc.key, got, want)
+		}
+	}
+}
@@ -32,6 +32,8 @@ import (
 	"strings"
 	"time"
 
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	"k8s.io/minikube/pkg/minikube/drivers/none"
 
 	"github.com/blang/semver"
@@ -41,24 +43,31 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
 	gopshost "github.com/shirou/gopsutil/host"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"golang.org/x/sync/errgroup"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/autostop"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/checkpoint"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/command"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/logs"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/problem"
 	"k8s.io/minikube/pkg/minikube/proxy"
+	"k8s.io/minikube/pkg/trace"
 	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
@@ -75,6 +84,7 @@ const (
 	hostOnlyCIDR          = "host-only-cidr"
 	containerRuntime      = "container-runtime"
 	criSocket             = "cri-socket"
+	snapshotter           = "snapshotter"
 	networkPlugin         = "network-plugin"
 	enableDefaultCNI      = "enable-default-cni"
 	hypervVirtualSwitch   = "hyperv-virtual-switch"
@@ -98,11 +108,47 @@ const (
 	vpnkitSock            = "hyperkit-vpnkit-sock"
 	vsockPorts            = "hyperkit-vsock-ports"
 	embedCerts            = "embed-certs"
+	execCredential        = "exec-credential"
+	kubeconfigPerProfile  = "kubeconfig-per-profile"
 	noVTXCheck            = "no-vtx-check"
 	downloadOnly          = "download-only"
 	dnsProxy              = "dns-proxy"
 	hostDNSResolver       = "host-dns-resolver"
 	waitUntilHealthy      = "wait"
+	qemuNetwork           = "qemu-network"
+	qemuFirmwarePath      = "qemu-firmware-path"
+	socketVMnetClientPath = "socket-vmnet-client-path"
+	socketVMnetPath       = "socket-vmnet-path"
+	clusterSpecFile       = "file"
+	ipFamily              = "ip-family"
+	startSchedule         = "schedule"
+	continueStart         = "continue"
+	startTrace            = "trace"
+	traceEndpoint         = "trace-endpoint"
+	profileOutput         = "profile-output"
+	autoStop              = "auto-stop"
+	autoStopRestartFlag   = "auto-stop-restart"
+	mountFSType           = "mount-type"
+	rootless              = "rootless"
+	customCACert          = "custom-ca-cert"
+	customCAKey           = "custom-ca-key"
+	gpus                  = "gpus"
+	staticIP              = "static-ip"
+	preloadFile           = "preload"
+	eventListener         = "event-listener"
+	oidcIssuerURL         = "oidc-issuer-url"
+	oidcClientID          = "oidc-client-id"
+	oidcUsernameClaim     = "oidc-username-claim"
+	oidcGroupsClaim       = "oidc-groups-claim"
+	oidcCAFile            = "oidc-ca-file"
+	oidcDex               = "oidc-dex"
+	encryptSecrets        = "encrypt-secrets"
+	apiServerAuditPolicy  = "apiserver-audit-policy"
+	usbPassthrough        = "usb-passthrough"
+	hugePages             = "hugepages"
+	numaNodeCount         = "numa-node-count"
+	kvmSRIOVVFs           = "kvm-sriov-vfs"
+	extraDisks            = "extra-disks"
 )
 
 var (
@@ -137,17 +183,37 @@ func initMinikubeFlags() {
 	startCmd.Flags().Int(cpus, constants.DefaultCPUS, "Number of CPUs allocated to the minikube VM")
 	startCmd.Flags().String(memory, constants.DefaultMemorySize, "Amount of RAM allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g)")
 	startCmd.Flags().String(humanReadableDiskSize, constants.DefaultDiskSize, "Disk size allocated to the minikube VM (format: <number>[<unit>], where unit = b, k, m or g)")
+	startCmd.Flags().Int(extraDisks, 0, "The number of extra disks, of --disk-size, attached to the VM as unformatted block devices /dev/vdb, /dev/vdc... (only supported with the kvm2, qemu2 and hyperkit drivers)")
+	startCmd.Flags().String(hugePages, "", "Huge pages reserved in the VM on every start, out of --memory, as size:count with sizes 2Mi and 1Gi, such as 2Mi:512,1Gi:4")
+	startCmd.Flags().Int(numaNodeCount, 1, "The number of NUMA nodes the CPUs and memory of the VM are split between. With more than one, the kubelet aligns containers with NUMA nodes (only supported with the kvm2 and qemu2 drivers)")
 	startCmd.Flags().Bool(downloadOnly, false, "If true, only download and cache files for later use - don't install or start anything.")
 	startCmd.Flags().Bool(cacheImages, true, "If true, cache docker images for the current bootstrapper and load them into the machine. Always false with --vm-driver=none.")
+	startCmd.Flags().String(preloadFile, "", "Path or URL of a preload created with 'minikube preload create', whose images are loaded before Kubernetes starts, instead of downloading them")
 	startCmd.Flags().String(isoURL, constants.DefaultISOURL, "Location of the minikube iso")
+	startCmd.Flags().String(constants.VerifyDownloads, download.VerifyOff, "How the signature of the downloaded ISO is verified: strict fails without a valid signature, warn only reports it, off skips it")
+	startCmd.Flags().String(constants.VerifyDownloadsKey, "", "Path of the cosign public key which verifies the signatures of downloads. Defaults to the key minikube is built with")
 	startCmd.Flags().Bool(keepContext, constants.DefaultKeepContext, "This will keep the existing kubectl context and will create a minikube context.")
+	startCmd.Flags().Bool(kubeconfigPerProfile, false, "Write the kubectl context of the profile to ~/.kube/minikube-<profile>.yaml, instead of merging it into the shared kubeconfig")
+	startCmd.Flags().Bool(execCredential, false, "Have kubectl get the client certificate by running 'minikube credential', instead of reading the certificate files, so that it always uses the current certificate")
 	startCmd.Flags().String(containerRuntime, "docker", "The container runtime to be used (docker, crio, containerd)")
 	startCmd.Flags().Bool(createMount, false, "This will start the mount daemon and automatically mount files into minikube")
 	startCmd.Flags().String(mountString, constants.DefaultMountDir+":"+constants.DefaultMountEndpoint, "The argument to pass the minikube mount command on start")
+	startCmd.Flags().String(mountFSType, nineP, "The filesystem used by --mount: 9p, sshfs, or virtiofs to share the directory with the VM when it is created (virtiofs is only supported with the qemu2, kvm2 and vfkit drivers)")
+	startCmd.Flags().Bool(rootless, false, "Run the containers in a user namespace, where root is remapped to an unprivileged user of the VM (only supported with the docker container runtime)")
 	startCmd.Flags().String(criSocket, "", "The cri socket path to be used")
+	startCmd.Flags().String(snapshotter, cruntime.OverlayfsSnapshotter, "The snapshotter of the containerd runtime: overlayfs, or stargz to lazily pull eStargz images, fetching their files as containers read them")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
 	startCmd.Flags().Bool(enableDefaultCNI, false, "Enable the default CNI plugin (/etc/cni/net.d/k8s.conf). Used in conjunction with \"--network-plugin=cni\"")
 	startCmd.Flags().Bool(waitUntilHealthy, true, "Wait until Kubernetes core services are healthy before exiting")
+	startCmd.Flags().Bool(continueStart, false, "Continue the last start, if it failed, skipping the phases which completed: download, machine, provision, bootstrap, addons and verify")
+	startCmd.Flags().String(startTrace, "", "Record the phases of the start as a trace, and export it: otlp sends it to an OpenTelemetry collector, such as Jaeger, Tempo or Honeycomb")
+	startCmd.Flags().String(profileOutput, "", "Write the timeline of the phases of the start to this file, as a Chrome trace for chrome://tracing or Perfetto, or as OTLP JSON if its name ends with .otlp.json")
+	startCmd.Flags().String(eventListener, "", "Stream the messages of the start as JSON events over a WebSocket at ws://ADDRESS/events, for IDEs to show its progress. An address such as :9999 only listens on localhost")
+	startCmd.Flags().String(traceEndpoint, "", "The OTLP/HTTP endpoint which --trace=otlp exports to (default $OTEL_EXPORTER_OTLP_ENDPOINT or http://localhost:4318). Headers are read from $OTEL_EXPORTER_OTLP_HEADERS")
+	startCmd.Flags().String(startSchedule, "", "Start the cluster later instead of now: after a duration (2h), at a time of day (08:30), at an RFC3339 timestamp, or each time you log in to the host (login)")
+	startCmd.Flags().Duration(autoStop, 0, "Stop the cluster once its API server has received no request for this duration (e.g. 30m). 0 disables auto-stop. Kept for the next starts unless given again.")
+	startCmd.Flags().Bool(autoStopRestartFlag, false, "With --auto-stop, start the cluster again on the next request to its API server")
+	startCmd.Flags().StringP(clusterSpecFile, "f", "", "A YAML or JSON file describing the cluster to start. Flags given on the command line take precedence over it.")
 }
 
 // initKubernetesFlags inits the commandline flags for kubernetes related options
@@ -156,7 +222,7 @@ func initKubernetesFlags() {
 	startCmd.Flags().Var(&extraOptions, "extra-config",
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
-		Valid components are: kubelet, kubeadm, apiserver, controller-manager, etcd, proxy, scheduler
+		Valid components are: kubelet, kubeadm, apiserver, controller-manager, etcd, proxy, scheduler, k3s (with --bootstrapper=k3s), k0s (with --bootstrapper=k0s)
 		Valid kubeadm parameters: `+fmt.Sprintf("%s, %s", strings.Join(kubeadm.KubeadmExtraArgsWhitelist[kubeadm.KubeadmCmdParam], ", "), strings.Join(kubeadm.KubeadmExtraArgsWhitelist[kubeadm.KubeadmConfigParam], ",")))
 	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
 	startCmd.Flags().String(dnsDomain, constants.ClusterDNSDomain, "The cluster dns domain name used in the kubernetes cluster")
@@ -164,18 +230,34 @@ func initKubernetesFlags() {
 	startCmd.Flags().String(apiServerName, constants.APIServerName, "The apiserver name which is used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().StringArrayVar(&apiServerNames, "apiserver-names", nil, "A set of apiserver names which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
 	startCmd.Flags().IPSliceVar(&apiServerIPs, "apiserver-ips", nil, "A set of apiserver IP Addresses which are used in the generated certificate for kubernetes.  This can be used if you want to make the apiserver available from outside the machine")
+	startCmd.Flags().String(customCACert, "", "A PEM encoded CA certificate to use as the cluster and front-proxy CA, instead of generating them. Requires --custom-ca-key")
+	startCmd.Flags().String(customCAKey, "", "The PEM encoded RSA private key of --custom-ca-cert")
+	startCmd.Flags().String(oidcIssuerURL, "", "The https URL of the OpenID Connect provider whose ID tokens the apiserver accepts")
+	startCmd.Flags().String(oidcClientID, "", "The client ID of the OpenID Connect provider which the ID tokens must be issued for")
+	startCmd.Flags().String(oidcUsernameClaim, "", "The claim of the ID tokens to use as the user name (default \"sub\", or \"email\" with --oidc-dex)")
+	startCmd.Flags().String(oidcGroupsClaim, "", "The claim of the ID tokens to use as the groups of the user")
+	startCmd.Flags().String(oidcCAFile, "", "A PEM encoded CA certificate which signed the certificate of the OpenID Connect provider, copied into the VM for the apiserver")
+	startCmd.Flags().Bool(oidcDex, false, "Enable the dex addon, a local OpenID Connect provider, and configure the apiserver to accept its ID tokens")
+	startCmd.Flags().Bool(encryptSecrets, false, "Encrypt the secrets in etcd with a key generated for the profile. Once enabled, it is kept by the next starts")
+	startCmd.Flags().String(apiServerAuditPolicy, "", "The audit policy of the apiserver, which enables its audit log, shown by 'minikube logs --component=apiserver-audit'")
 }
 
 // initDriverFlags inits the commandline flags for vm drivers
 func initDriverFlags() {
 	startCmd.Flags().String(vmDriver, constants.DefaultVMDriver, fmt.Sprintf("VM driver is one of: %v", constants.SupportedVMDrivers))
 	startCmd.Flags().Bool(disableDriverMounts, false, "Disables the filesystem mounts provided by the hypervisors")
+	startCmd.Flags().StringSlice(usbPassthrough, []string{}, "USB devices of the host to pass through to the VM when it is created, as vendor:product IDs such as 1050:0407 (only supported with the kvm2 and qemu2 drivers)")
 
 	// kvm2
 	startCmd.Flags().String(kvmNetwork, "default", "The KVM network name. (only supported with KVM driver)")
 	startCmd.Flags().String(kvmQemuURI, "qemu:///system", "The KVM QEMU connection URI. (works only with kvm2 driver on linux)")
 	startCmd.Flags().Bool(kvmGPU, false, "Enable experimental NVIDIA GPU support in minikube")
 	startCmd.Flags().Bool(kvmHidden, false, "Hide the hypervisor signature from the guest in minikube")
+	startCmd.Flags().Int(kvmSRIOVVFs, 0, "The number of SR-IOV virtual functions, up to 7, of an emulated igb NIC added to the VM, for the sriov-sim addon. Needs libvirt 9.3 and QEMU 8.0 (only supported with kvm2 driver)")
+	startCmd.Flags().String(staticIP, "", "Always give the VM this IP address, from "+kvmPrivateNetworkCIDR+", so that it survives restarts (only supported with kvm2 driver)")
+
+	// none
+	startCmd.Flags().String(gpus, "", "Allow pods to use the GPUs of the host. Options include: [all nvidia amd intel], where all is the NVIDIA GPUs (only supported with the none and kvm2 drivers)")
 
 	// virtualbox
 	startCmd.Flags().String(hostOnlyCIDR, "192.168.99.1/24", "The CIDR to be used for the minikube VM (only supported with Virtualbox driver)")
@@ -192,15 +274,22 @@ func initDriverFlags() {
 
 	// hyperv
 	startCmd.Flags().String(hypervVirtualSwitch, "", "The hyperv virtual switch name. Defaults to first found. (only supported with HyperV driver)")
+
+	// qemu2
+	startCmd.Flags().String(qemuNetwork, qemu.NetworkUser, fmt.Sprintf("The network used by the VM, one of: %v (only supported with qemu2 driver)", qemu.SupportedNetworks))
+	startCmd.Flags().String(qemuFirmwarePath, "", "Path to the UEFI firmware to boot the VM with, required on arm64 hosts (only supported with qemu2 driver)")
+	startCmd.Flags().String(socketVMnetClientPath, "/opt/socket_vmnet/bin/socket_vmnet_client", "Path to the socket_vmnet_client binary (only supported with qemu2 driver)")
+	startCmd.Flags().String(socketVMnetPath, "/var/run/socket_vmnet", "Path to the socket_vmnet daemon socket (only supported with qemu2 driver)")
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
 func initNetworkingFlags() {
-	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
-	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
+	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure registries to pass to the container runtime.  The default service CIDR range will automatically be added.")
+	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors of Docker Hub to pass to the container runtime")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn")
 	startCmd.Flags().String(serviceCIDR, pkgutil.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
+	startCmd.Flags().String(ipFamily, pkgutil.IPFamilyIPv4, "The IP family of the cluster: ipv4, ipv6 or dual (ipv6 and dual are only supported with the kvm2 and none drivers)")
 	startCmd.Flags().StringArrayVar(&dockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&dockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 }
@@ -213,6 +302,21 @@ var startCmd = &cobra.Command{
 	Run:   runStart,
 }
 
+// updateProblems refreshes the cached remote database of known problems once a day, so that the failures of the start
+// are matched with its latest signatures. They are matched with the previous database or the built-in problems meanwhile, or offline.
+func updateProblems() {
+	if !viper.GetBool(cfg.WantRemoteProblems) {
+		return
+	}
+	url := viper.GetString(cfg.ProblemsURL)
+	if url == "" {
+		url = constants.DefaultProblemsURL
+	}
+	if err := problem.UpdateIfStale(url, 24*time.Hour); err != nil {
+		glog.Infof("Unable to update the known problems: %v", err)
+	}
+}
+
 // platform generates a user-readable platform message
 func platform() string {
 	var s strings.Builder
@@ -243,12 +347,33 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
+	stopEvents := startEventServer()
+	defer stopEvents()
+
 	prefix := ""
 	if viper.GetString(cfg.MachineProfile) != constants.DefaultMachineName {
 		prefix = fmt.Sprintf("[%s] ", viper.GetString(cfg.MachineProfile))
 	}
 	out.T(out.Happy, "{{.prefix}}minikube {{.version}} on {{.platform}}", out.V{"prefix": prefix, "version": version.GetVersion(), "platform": platform()})
 
+	if viper.GetString(startSchedule) != "" {
+		scheduleStart(cmd, viper.GetString(startSchedule))
+		return
+	}
+	unlock := cmdcfg.LockProfile("start")
+	defer unlock()
+	go updateProblems()
+
+	initTrace()
+	root := trace.StartSpan("minikube start")
+
+	var spec *cfg.ClusterSpec
+	if viper.GetString(clusterSpecFile) != "" {
+		spec = applyClusterSpec(cmd, viper.GetString(clusterSpecFile))
+	} else {
+		applyProfileConfig(cmd)
+	}
+
 	// if --registry-mirror specified when run minikube start,
 	// take arg precedence over MINIKUBE_REGISTRY_MIRROR
 	// actually this is a hack, because viper 1.0.0 can assign env to variable if StringSliceVar
@@ -259,6 +384,13 @@ func runStart(cmd *cobra.Command, args []string) {
 	if len(registryMirror) == 0 {
 		registryMirror = viper.GetStringSlice("registry_mirror")
 	}
+	// values set with 'minikube config set' are comma-separated strings, which viper does not split
+	if len(registryMirror) == 0 {
+		registryMirror = cmdcfg.ListSetting(cmdcfg.RegistryMirror)
+	}
+	if len(insecureRegistry) == 0 {
+		insecureRegistry = cmdcfg.ListSetting(cmdcfg.InsecureRegistry)
+	}
 
 	if err := cmdcfg.IsValidDriver(runtime.GOOS, viper.GetString(vmDriver)); err != nil {
 		exit.WithCodeT(
@@ -272,21 +404,50 @@ func runStart(cmd *cobra.Command, args []string) {
 	validateUser()
 	validateDriverVersion(viper.GetString(vmDriver))
 
+	// k3s and k0s are released separately from Kubernetes, so default to a version they ship
+	if !cmd.Flags().Changed(kubernetesVersion) {
+		switch viper.GetString(cmdcfg.Bootstrapper) {
+		case bootstrapper.BootstrapperTypeK3s:
+			viper.Set(kubernetesVersion, constants.DefaultK3sKubernetesVersion)
+		case bootstrapper.BootstrapperTypeK0s:
+			viper.Set(kubernetesVersion, constants.DefaultK0sKubernetesVersion)
+		}
+	}
+
 	k8sVersion, isUpgrade := getKubernetesVersion()
+	validateIPFamily(k8sVersion)
+	validateEncryption(k8sVersion)
+	validateAuditPolicy(k8sVersion)
+	validateNUMA(k8sVersion)
+	validateStaticIP()
 	config, err := generateConfig(cmd, k8sVersion)
 	if err != nil {
 		exit.WithError("Failed to generate config", err)
 	}
 
-	// For non-"none", the ISO is required to boot, so block until it is downloaded
-	downloadISO(config)
+	traceConfig(root, config)
+
+	// --continue skips the phases which completed in the last start, if it failed with the same config
+	cp := startCheckpoint(config)
 
 	// With "none", images are persistently stored in Docker, so internal caching isn't necessary.
 	skipCache(&config)
 
-	// Now that the ISO is downloaded, pull images in the background while the VM boots.
 	var cacheGroup errgroup.Group
-	beginCacheImages(&cacheGroup, config.KubernetesConfig.ImageRepository, k8sVersion)
+	span := startPhase(cp, checkpoint.Download)
+	if !cp.Completed(checkpoint.Download) {
+		// For non-"none", the ISO is required to boot, so block until it is downloaded
+		s := trace.StartSpan("download iso")
+		downloadISO(config)
+		s.Finish()
+		s = trace.StartSpan("download preload")
+		downloadPreload()
+		s.Finish()
+
+		// Now that the ISO is downloaded, pull images in the background while the VM boots.
+		beginCacheImages(&cacheGroup, config.KubernetesConfig.ImageRepository, k8sVersion)
+	}
+	span.Finish()
 
 	// Abstraction leakage alert: startHost requires the config to be saved, to satistfy pkg/provision/buildroot.
 	// Hence, saveConfig must be called before startHost, and again afterwards when we know the IP.
@@ -296,30 +457,96 @@ func runStart(cmd *cobra.Command, args []string) {
 
 	// exits here in case of --download-only option.
 	handleDownloadOnly(&cacheGroup, k8sVersion)
+	span = startPhase(cp, checkpoint.Machine)
 	mRunner, preExists, machineAPI, host := startMachine(&config)
 	defer machineAPI.Close()
-	// configure the runtime (docker, containerd, crio)
-	cr := configureRuntimes(mRunner)
+	preExists = checkpointMachine(cp, mRunner, preExists)
+	span.Finish()
+
+	span = startPhase(cp, checkpoint.Provision)
+	var cr cruntime.Manager
+	if cp.Completed(checkpoint.Provision) {
+		cr = runtimeManager(mRunner)
+	} else {
+		// configure the runtime (docker, containerd, crio)
+		s := trace.StartSpan("configure runtime")
+		cr = configureRuntimes(mRunner)
+		configureRegistries(cr, config)
+		s.Finish()
+		s = trace.StartSpan("extract preload")
+		loadPreload(mRunner, config.KubernetesConfig)
+		s.Finish()
+	}
 	showVersionInfo(k8sVersion, cr)
+	span.Finish()
+	span = trace.StartSpan("cache images")
 	waitCacheImages(&cacheGroup)
-
-	// setup kube adm and certs and return bootstrapperx
-	bs := setupKubeAdm(machineAPI, config.KubernetesConfig)
+	span.Finish()
+	completePhase(cp, checkpoint.Download)
+	completePhase(cp, checkpoint.Provision)
+
+	span = startPhase(cp, checkpoint.Bootstrap)
+	var bs bootstrapper.Bootstrapper
+	if cp.Completed(checkpoint.Bootstrap) {
+		bs = clusterBootstrapper(machineAPI)
+	} else {
+		// setup kube adm and certs and return bootstrapperx
+		configureOIDC(mRunner, &config)
+		configureEncryption(mRunner, &config)
+		configureAudit(mRunner, &config)
+		configureHugePages(mRunner, &config)
+		configureVirtualFunctions(mRunner, &config)
+		bs = setupKubeAdm(machineAPI, config.KubernetesConfig)
+	}
+	configureAutoStop(cmd)
 	// The kube config must be update must come before bootstrapping, otherwise health checks may use a stale IP
 	kubeconfig := updateKubeConfig(host, &config)
-	// pull images or restart cluster
-	bootstrapCluster(bs, cr, mRunner, config.KubernetesConfig, preExists, isUpgrade)
-	configureMounts()
-	if err = loadCachedImagesInConfigFile(); err != nil {
-		out.T(out.FailureType, "Unable to load cached images from config file.")
+	if !cp.Completed(checkpoint.Bootstrap) {
+		// pull images or restart cluster
+		bootstrapCluster(bs, cr, mRunner, config.KubernetesConfig, preExists, isUpgrade)
+		completePhase(cp, checkpoint.Bootstrap)
 	}
+	span.Finish()
+	configureMounts(host)
 	// special ops for none driver, like change minikube directory.
 	prepareNone(viper.GetString(vmDriver))
+	span = startPhase(cp, checkpoint.Addons)
+	if !cp.Completed(checkpoint.Addons) {
+		if err = loadCachedImagesInConfigFile(); err != nil {
+			out.T(out.FailureType, "Unable to load cached images from config file.")
+		}
+		if g := viper.GetString(gpus); g != "" {
+			enableAddon(gpuDevicePlugin(viper.GetString(vmDriver), gpuVendors[g]))
+		}
+		if viper.GetBool(oidcDex) {
+			enableDex(mRunner, config.KubernetesConfig.NodeIP)
+		}
+		if config.MachineConfig.SRIOVVFs > 0 {
+			enableAddon("sriov-sim")
+		}
+		if f := viper.GetString(cmdcfg.ImagePullSecret); f != "" {
+			enableImagePullSecret(f)
+		}
+		if spec != nil {
+			applySpecAddons(spec)
+		}
+		completePhase(cp, checkpoint.Addons)
+	}
+	span.Finish()
+	span = startPhase(cp, checkpoint.Verify)
 	if viper.GetBool(waitUntilHealthy) {
 		if err := bs.WaitCluster(config.KubernetesConfig); err != nil {
 			exit.WithError("Wait failed", err)
 		}
 	}
+	span.Finish()
+	warnExpiringCerts(mRunner)
+	if err := checkpoint.Clear(cp.Profile); err != nil {
+		glog.Warningf("Unable to clear the start checkpoint: %v", err)
+	}
+	startAutoStop()
+	root.Finish()
+	flushTrace()
 	showKubectlConnectInfo(kubeconfig)
 
 }
@@ -337,6 +564,7 @@ func handleDownloadOnly(cacheGroup *errgroup.Group, k8sVersion string) {
 		exit.WithError("Failed to cache images", err)
 	}
 	out.T(out.Check, "Download complete!")
+	flushTrace()
 	os.Exit(0)
 
 }
@@ -347,6 +575,10 @@ func startMachine(config *cfg.Config) (runner command.Runner, preExists bool, ma
 		exit.WithError("Failed to get machine client", err)
 	}
 	host, preExists = startHost(m, config.MachineConfig)
+	runner, err = machine.CommandRunner(host)
+	if err != nil {
+		exit.WithError("Failed to get command runner", err)
+	}
 
 	ip := validateNetwork(host)
 	// Bypass proxy for minikube's vm host ip
@@ -354,19 +586,52 @@ func startMachine(config *cfg.Config) (runner command.Runner, preExists bool, ma
 	if err != nil {
 		out.ErrT(out.FailureType, "Failed to set NO_PROXY Env. Please use `export NO_PROXY=$NO_PROXY,{{.ip}}`.", out.V{"ip": ip})
 	}
+	// With user-mode networking, the host reaches the guest through forwarded loopback ports,
+	// but the guest itself is only aware of its slirp address.
+	if config.MachineConfig.VMDriver == constants.DriverQemu2 && config.MachineConfig.QemuNetwork == qemu.NetworkUser {
+		config.KubernetesConfig.APIServerIPs = append(config.KubernetesConfig.APIServerIPs, net.ParseIP(ip))
+		ip = qemu.UserNetworkGuestIP
+	}
+	// The host keeps talking to the VM over IPv4, while Kubernetes only knows about the IPv6 address
+	if config.KubernetesConfig.IPFamily == pkgutil.IPFamilyIPv6 {
+		config.KubernetesConfig.APIServerIPs = append(config.KubernetesConfig.APIServerIPs, net.ParseIP(ip))
+		ip = guestIPv6(runner)
+	}
 	// Save IP to configuration file for subsequent use
 	config.KubernetesConfig.NodeIP = ip
 	if err := saveConfig(config); err != nil {
 		exit.WithError("Failed to save config", err)
 	}
-	runner, err = machine.CommandRunner(host)
-	if err != nil {
-		exit.WithError("Failed to get command runner", err)
-	}
 
 	return runner, preExists, m, host
 }
 
+// guestIPv6 returns the global IPv6 address of the VM, waiting for it to be configured
+func guestIPv6(runner command.Runner) string {
+	var ip net.IP
+	getIP := func() error {
+		rr, err := runner.CombinedOutput("ip -6 -o addr show scope global")
+		if err != nil {
+			return err
+		}
+		ip, err = pkgutil.ParseGlobalIPv6(rr)
+		return err
+	}
+	if err := pkgutil.RetryAfter(30, getIP, 2*time.Second); err != nil {
+		exit.WithError("Unable to get the IPv6 address of the VM", err)
+	}
+	return ip.String()
+}
+
+// existingKubernetesConfig returns the Kubernetes config of the last start of the profile, if any
+func existingKubernetesConfig() cfg.KubernetesConfig {
+	cc, err := cfg.Load()
+	if err != nil {
+		return cfg.KubernetesConfig{}
+	}
+	return cc.KubernetesConfig
+}
+
 func getKubernetesVersion() (k8sVersion string, isUpgrade bool) {
 	oldConfig, err := cfg.Load()
 	if err != nil && !os.IsNotExist(err) {
@@ -388,6 +653,40 @@ func skipCache(config *cfg.Config) {
 		viper.Set(cacheImages, false)
 		config.KubernetesConfig.ShouldLoadCachedImages = false
 	}
+	// The preload provides the images of Kubernetes
+	if viper.GetString(preloadFile) != "" {
+		viper.Set(cacheImages, false)
+		config.KubernetesConfig.ShouldLoadCachedImages = false
+	}
+}
+
+// downloadPreload downloads the --preload archive into the cache, if it is an URL
+func downloadPreload() {
+	p := viper.GetString(preloadFile)
+	if !strings.HasPrefix(p, "http://") && !strings.HasPrefix(p, "https://") {
+		return
+	}
+	local, err := machine.CachePreload(p)
+	if err != nil {
+		exit.WithError("Failed to download the preload", err)
+	}
+	viper.Set(preloadFile, local)
+}
+
+// loadPreload loads the images of the --preload archive into the container runtime
+func loadPreload(runner command.Runner, kc cfg.KubernetesConfig) {
+	p := viper.GetString(preloadFile)
+	if p == "" {
+		return
+	}
+	out.T(out.Caching, "Loading images from {{.path}} ...", out.V{"path": p})
+	m, err := machine.LoadPreload(runner, kc, p)
+	if err != nil {
+		exit.WithError("Failed to load the preload", err)
+	}
+	if m.KubernetesVersion != kc.KubernetesVersion {
+		out.WarningT("{{.path}} was created for Kubernetes {{.preload}}, the missing images of Kubernetes {{.version}} will be pulled", out.V{"path": p, "preload": m.KubernetesVersion, "version": kc.KubernetesVersion})
+	}
 }
 
 func showVersionInfo(k8sVersion string, cr cruntime.Manager) {
@@ -402,7 +701,9 @@ func showVersionInfo(k8sVersion string, cr cruntime.Manager) {
 }
 
 func showKubectlConnectInfo(kubeconfig *pkgutil.KubeConfigSetup) {
-	if kubeconfig.KeepContext {
+	if viper.GetBool(kubeconfigPerProfile) {
+		out.T(out.Ready, "Done! To connect to this cluster, use: export KUBECONFIG={{.path}}", out.V{"path": kubeconfig.GetKubeConfigFile()})
+	} else if kubeconfig.KeepContext {
 		out.T(out.Kubectl, "To connect to this cluster, use: kubectl --context={{.name}}", out.V{"name": kubeconfig.ClusterName})
 	} else {
 		out.T(out.Ready, `Done! kubectl is now configured to use "{{.name}}"`, out.V{"name": cfg.GetMachineName()})
@@ -512,7 +813,221 @@ func validateConfig() {
 		}
 	}
 
+	if viper.GetString(vmDriver) == constants.DriverQemu2 {
+		if err := qemu.ValidateNetwork(viper.GetString(qemuNetwork), runtime.GOOS); err != nil {
+			exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": qemuNetwork, "error": err})
+		}
+	}
+
+	switch viper.GetString(mountFSType) {
+	case nineP, sshfs:
+	case virtiofs:
+		switch viper.GetString(vmDriver) {
+		case constants.DriverKvm2, constants.DriverVfkit:
+		case constants.DriverQemu2:
+			if err := qemu.ValidateVirtiofs(runtime.GOOS); err != nil {
+				exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": mountFSType, "error": err})
+			}
+		default:
+			exit.UsageT("The {{.driver}} driver does not support virtiofs mounts, use the qemu2, kvm2 or vfkit driver", out.V{"driver": viper.GetString(vmDriver)})
+		}
+	default:
+		exit.UsageT("Invalid --{{.flag}}: {{.type}} is not one of 9p, virtiofs, sshfs", out.V{"flag": mountFSType, "type": viper.GetString(mountFSType)})
+	}
+
+	validateUSBPassthrough()
+	validateSnapshotter()
+	validateExtraDisks()
+	validateHugePages()
+	validateVirtualFunctions()
+
+	if viper.GetBool(rootless) {
+		if viper.GetString(containerRuntime) != "docker" {
+			exit.UsageT("--{{.flag}} is only supported with the docker container runtime", out.V{"flag": rootless})
+		}
+		if viper.GetString(vmDriver) == constants.DriverNone {
+			exit.UsageT("--{{.flag}} is not supported by the none driver: configure user namespaces in the docker daemon of the host instead", out.V{"flag": rootless})
+		}
+	}
+
+	validateCustomCA()
+	validateOIDC()
+	validateGPUs()
 	validateRegistryMirror()
+	if err := cmdcfg.IsValidVerifyDownloads(constants.VerifyDownloads, viper.GetString(constants.VerifyDownloads)); err != nil {
+		exit.UsageT("{{.error}}", out.V{"error": err})
+	}
+}
+
+const (
+	// nvidiaRuntime is the docker runtime installed by nvidia-container-toolkit
+	nvidiaRuntime = "nvidia"
+	// amdGPU is the vendor of --gpus=amd
+	amdGPU = "amd"
+	// intelGPU is the vendor of --gpus=intel
+	intelGPU = "intel"
+)
+
+// gpuVendors are the vendors of the GPUs selected by each value of --gpus
+var gpuVendors = map[string]string{
+	"all":    "nvidia",
+	"nvidia": "nvidia",
+	amdGPU:   amdGPU,
+	intelGPU: intelGPU,
+}
+
+// validateGPUs checks that the GPUs of the host can be used by pods: with kvm2 they are passed through to the VM,
+// with the none driver the docker daemon of the host has to run containers with the NVIDIA runtime, or the AMD or Intel driver has to be loaded
+func validateGPUs() {
+	g := viper.GetString(gpus)
+	if g == "" {
+		return
+	}
+	vendor, ok := gpuVendors[g]
+	if !ok {
+		exit.UsageT("Invalid --{{.flag}}: {{.value}} is not one of [all nvidia amd intel]", out.V{"flag": gpus, "value": g})
+	}
+	switch viper.GetString(vmDriver) {
+	case constants.DriverKvm2:
+		// The devices are looked up by the driver when it creates the VM
+		return
+	case constants.DriverNone:
+	default:
+		exit.UsageT("--{{.flag}} is only supported with the none and kvm2 drivers", out.V{"flag": gpus})
+	}
+	if vendor != "nvidia" {
+		if err := checkGPUDevices("/dev", vendor); err != nil {
+			exit.WithCodeT(exit.Config, "Unable to use the GPUs of the host: {{.error}}", out.V{"error": err})
+		}
+		return
+	}
+	info, err := exec.Command("docker", "info", "--format", "{{.DefaultRuntime}} {{json .Runtimes}}").Output()
+	if err != nil {
+		exit.WithError("Unable to get the runtimes of the docker daemon", err)
+	}
+	if err := checkNvidiaRuntime(string(info)); err != nil {
+		exit.WithCodeT(exit.Config, "Unable to use the NVIDIA GPUs of the host: {{.error}}", out.V{"error": err})
+	}
+}
+
+// checkGPUDevices checks that the device files which the device plugin of the vendor hands to containers exist in dev.
+// The ROCm device plugin needs kfd, the compute interface of the amdgpu kernel driver, and the render nodes of dri.
+// The Intel device plugin needs the render nodes of dri, created by the i915 kernel driver.
+func checkGPUDevices(dev string, vendor string) error {
+	driver := "i915"
+	if vendor == amdGPU {
+		driver = "amdgpu"
+		if _, err := os.Stat(filepath.Join(dev, "kfd")); err != nil {
+			return fmt.Errorf("%s does not exist, load the %s kernel driver first", filepath.Join(dev, "kfd"), driver)
+		}
+	}
+	nodes, err := filepath.Glob(filepath.Join(dev, "dri", "renderD*"))
+	if err != nil {
+		return errors.Wrap(err, "render nodes")
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no render nodes in %s, load the %s kernel driver first", filepath.Join(dev, "dri"), driver)
+	}
+	return nil
+}
+
+// gpuDevicePlugin returns the addon which advertises the GPUs of a vendor to the kubelet
+func gpuDevicePlugin(driver string, vendor string) string {
+	switch vendor {
+	case amdGPU:
+		return "rocm-gpu"
+	case intelGPU:
+		return "intel-gpu"
+	}
+	if driver == constants.DriverKvm2 {
+		// The driver of the VM is installed by nvidia-driver-installer, which this addon depends on
+		return "nvidia-gpu-device-plugin"
+	}
+	return "nvidia-device-plugin"
+}
+
+// checkNvidiaRuntime checks the output of docker info for the NVIDIA runtime, installed by nvidia-container-toolkit.
+// It has to be the default runtime, as the kubelet does not ask docker for a runtime per pod.
+func checkNvidiaRuntime(info string) error {
+	fields := strings.SplitN(strings.TrimSpace(info), " ", 2)
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected docker info output: %q", info)
+	}
+	runtimes := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(fields[1]), &runtimes); err != nil {
+		return errors.Wrap(err, "parsing docker runtimes")
+	}
+	if _, ok := runtimes[nvidiaRuntime]; !ok {
+		return errors.New("the nvidia runtime is not registered with docker, install nvidia-container-toolkit first")
+	}
+	if fields[0] != nvidiaRuntime {
+		return fmt.Errorf("the default runtime of docker is %s, set \"default-runtime\": \"%s\" in /etc/docker/daemon.json and restart docker", fields[0], nvidiaRuntime)
+	}
+	return nil
+}
+
+// enableAddon enables an addon which start needs, such as the device plugin of the GPUs of the host, after the addons it depends on
+func enableAddon(addon string) {
+	deps, err := assets.Dependencies(addon)
+	if err != nil {
+		exit.WithError("Failed to get addon dependencies", err)
+	}
+	for _, a := range append(deps, addon) {
+		enabled, err := assets.Addons[a].IsEnabled()
+		if err != nil {
+			exit.WithError("Failed to check addon status", err)
+		}
+		if enabled {
+			continue
+		}
+		span := trace.StartSpan("enable addon " + a)
+		if err := cmdcfg.Set(a, "true"); err != nil {
+			exit.WithError("Failed to enable addon", err)
+		}
+		span.Finish()
+		out.T(out.Enabling, "Enabled addon {{.name}}", out.V{"name": a})
+	}
+}
+
+// enableImagePullSecret creates the image pull secret of the image-pull-secret setting, and enables the pull-secrets
+// addon which adds it to every namespace
+func enableImagePullSecret(path string) {
+	if err := pkgutil.RetryAfter(10, func() error { return cmdcfg.CreateImagePullSecret(path) }, 2*time.Second); err != nil {
+		out.WarningT("Unable to create the image pull secret: {{.error}}", out.V{"error": err})
+		return
+	}
+	enabled, err := assets.Addons[cmdcfg.PullSecretsAddon].IsEnabled()
+	if err != nil {
+		exit.WithError("Failed to check addon status", err)
+	}
+	if enabled {
+		return
+	}
+	if err := cmdcfg.Set(cmdcfg.PullSecretsAddon, "true"); err != nil {
+		exit.WithError("Failed to enable the pull-secrets addon", err)
+	}
+	out.T(out.Enabling, "Enabled addon {{.name}}", out.V{"name": cmdcfg.PullSecretsAddon})
+}
+
+// validateCustomCA validates --custom-ca-cert and --custom-ca-key, and makes their paths absolute
+func validateCustomCA() {
+	cert, key := viper.GetString(customCACert), viper.GetString(customCAKey)
+	if cert == "" && key == "" {
+		return
+	}
+	if cert == "" || key == "" {
+		exit.UsageT("--{{.cert}} and --{{.key}} must be used together", out.V{"cert": customCACert, "key": customCAKey})
+	}
+	if err := pkgutil.ValidateCACert(cert, key); err != nil {
+		exit.WithCodeT(exit.Data, "Invalid custom CA: {{.error}}", out.V{"error": err})
+	}
+	for flag, p := range map[string]string{customCACert: cert, customCAKey: key} {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			exit.WithError("Unable to get absolute path", err)
+		}
+		viper.Set(flag, abs)
+	}
 }
 
 // This function validates if the --registry-mirror
@@ -561,6 +1076,8 @@ func waitCacheImages(g *errgroup.Group) {
 
 // generateConfig generates cfg.Config based on flags and supplied arguments
 func generateConfig(cmd *cobra.Command, k8sVersion string) (cfg.Config, error) {
+	// Some settings of the cluster are kept from the last start, as changing them would break it
+	existing := existingKubernetesConfig()
 	r, err := cruntime.New(cruntime.Config{Type: viper.GetString(containerRuntime)})
 	if err != nil {
 		return cfg.Config{}, err
@@ -590,6 +1107,19 @@ func generateConfig(cmd *cobra.Command, k8sVersion string) (cfg.Config, error) {
 		}
 	}
 
+	selectedServiceCIDR := viper.GetString(serviceCIDR)
+	if !cmd.Flags().Changed(serviceCIDR) {
+		selectedServiceCIDR = pkgutil.DefaultServiceCIDRs(viper.GetString(ipFamily))
+	}
+	selectedFeatureGates := viper.GetString(featureGates)
+	if viper.GetString(ipFamily) == pkgutil.IPFamilyDual && !strings.Contains(selectedFeatureGates, "IPv6DualStack") {
+		selectedFeatureGates = strings.TrimPrefix(selectedFeatureGates+",IPv6DualStack=true", ",")
+	}
+	if viper.GetBool(rootless) {
+		selectedFeatureGates = rootlessFeatureGates(selectedFeatureGates)
+	}
+	selectedFeatureGates, extraOptions = numaKubeletConfig(viper.GetInt(numaNodeCount), k8sVersion, selectedFeatureGates, extraOptions)
+
 	repository := viper.GetString(imageRepository)
 	mirrorCountry := strings.ToLower(viper.GetString(imageMirrorCountry))
 	if strings.ToLower(repository) == "auto" || mirrorCountry != "" {
@@ -615,33 +1145,51 @@ func generateConfig(cmd *cobra.Command, k8sVersion string) (cfg.Config, error) {
 
 	cfg := cfg.Config{
 		MachineConfig: cfg.MachineConfig{
-			KeepContext:         viper.GetBool(keepContext),
-			MinikubeISO:         viper.GetString(isoURL),
-			Memory:              pkgutil.CalculateSizeInMB(viper.GetString(memory)),
-			CPUs:                viper.GetInt(cpus),
-			DiskSize:            pkgutil.CalculateSizeInMB(viper.GetString(humanReadableDiskSize)),
-			VMDriver:            viper.GetString(vmDriver),
-			ContainerRuntime:    viper.GetString(containerRuntime),
-			HyperkitVpnKitSock:  viper.GetString(vpnkitSock),
-			HyperkitVSockPorts:  viper.GetStringSlice(vsockPorts),
-			NFSShare:            viper.GetStringSlice(nfsShare),
-			NFSSharesRoot:       viper.GetString(nfsSharesRoot),
-			DockerEnv:           dockerEnv,
-			DockerOpt:           dockerOpt,
-			InsecureRegistry:    insecureRegistry,
-			RegistryMirror:      registryMirror,
-			HostOnlyCIDR:        viper.GetString(hostOnlyCIDR),
-			HypervVirtualSwitch: viper.GetString(hypervVirtualSwitch),
-			KVMNetwork:          viper.GetString(kvmNetwork),
-			KVMQemuURI:          viper.GetString(kvmQemuURI),
-			KVMGPU:              viper.GetBool(kvmGPU),
-			KVMHidden:           viper.GetBool(kvmHidden),
-			Downloader:          pkgutil.DefaultDownloader{},
-			DisableDriverMounts: viper.GetBool(disableDriverMounts),
-			UUID:                viper.GetString(uuid),
-			NoVTXCheck:          viper.GetBool(noVTXCheck),
-			DNSProxy:            viper.GetBool(dnsProxy),
-			HostDNSResolver:     viper.GetBool(hostDNSResolver),
+			KeepContext:           viper.GetBool(keepContext),
+			KubeconfigPerProfile:  viper.GetBool(kubeconfigPerProfile),
+			MinikubeISO:           viper.GetString(isoURL),
+			Memory:                pkgutil.CalculateSizeInMB(viper.GetString(memory)),
+			CPUs:                  viper.GetInt(cpus),
+			DiskSize:              pkgutil.CalculateSizeInMB(viper.GetString(humanReadableDiskSize)),
+			VMDriver:              viper.GetString(vmDriver),
+			ContainerRuntime:      viper.GetString(containerRuntime),
+			HyperkitVpnKitSock:    viper.GetString(vpnkitSock),
+			HyperkitVSockPorts:    viper.GetStringSlice(vsockPorts),
+			NFSShare:              viper.GetStringSlice(nfsShare),
+			NFSSharesRoot:         viper.GetString(nfsSharesRoot),
+			DockerEnv:             dockerEnv,
+			DockerOpt:             dockerOpt,
+			InsecureRegistry:      insecureRegistry,
+			RegistryMirror:        registryMirror,
+			HostOnlyCIDR:          viper.GetString(hostOnlyCIDR),
+			HypervVirtualSwitch:   viper.GetString(hypervVirtualSwitch),
+			KVMNetwork:            viper.GetString(kvmNetwork),
+			KVMQemuURI:            viper.GetString(kvmQemuURI),
+			KVMGPU:                viper.GetBool(kvmGPU) || (viper.GetString(gpus) != "" && viper.GetString(vmDriver) == constants.DriverKvm2),
+			KVMGPUVendor:          gpuVendors[viper.GetString(gpus)],
+			KVMHidden:             viper.GetBool(kvmHidden),
+			Downloader:            pkgutil.DefaultDownloader{},
+			DisableDriverMounts:   viper.GetBool(disableDriverMounts),
+			UUID:                  viper.GetString(uuid),
+			NoVTXCheck:            viper.GetBool(noVTXCheck),
+			DNSProxy:              viper.GetBool(dnsProxy),
+			HostDNSResolver:       viper.GetBool(hostDNSResolver),
+			QemuNetwork:           viper.GetString(qemuNetwork),
+			QemuFirmwarePath:      viper.GetString(qemuFirmwarePath),
+			SocketVMnetClientPath: viper.GetString(socketVMnetClientPath),
+			SocketVMnetPath:       viper.GetString(socketVMnetPath),
+			APIServerPort:         viper.GetInt(apiServerPort),
+			IPFamily:              viper.GetString(ipFamily),
+			VirtiofsShares:        virtiofsShares(),
+			USBDevices:            viper.GetStringSlice(usbPassthrough),
+			NUMANodes:             viper.GetInt(numaNodeCount),
+			SRIOVVFs:              viper.GetInt(kvmSRIOVVFs),
+			ExtraDisks:            viper.GetInt(extraDisks),
+			HugePages:             viper.GetString(hugePages),
+			Rootless:              viper.GetBool(rootless),
+			StaticIP:              viper.GetString(staticIP),
+			MountString:           startMountString(),
+			MountType:             startMountType(),
 		},
 		KubernetesConfig: cfg.KubernetesConfig{
 			KubernetesVersion:      k8sVersion,
@@ -650,14 +1198,21 @@ func generateConfig(cmd *cobra.Command, k8sVersion string) (cfg.Config, error) {
 			APIServerName:          viper.GetString(apiServerName),
 			APIServerNames:         apiServerNames,
 			APIServerIPs:           apiServerIPs,
+			ExtraSANs:              existing.ExtraSANs,
+			EncryptSecrets:         viper.GetBool(encryptSecrets) || existing.EncryptSecrets,
+			AuditPolicy:            auditPolicy(existing),
 			DNSDomain:              viper.GetString(dnsDomain),
-			FeatureGates:           viper.GetString(featureGates),
+			FeatureGates:           selectedFeatureGates,
 			ContainerRuntime:       viper.GetString(containerRuntime),
 			CRISocket:              viper.GetString(criSocket),
+			Snapshotter:            viper.GetString(snapshotter),
 			NetworkPlugin:          selectedNetworkPlugin,
-			ServiceCIDR:            viper.GetString(serviceCIDR),
+			ServiceCIDR:            selectedServiceCIDR,
+			IPFamily:               viper.GetString(ipFamily),
 			ImageRepository:        repository,
 			ExtraOptions:           extraOptions,
+			CustomCACert:           viper.GetString(customCACert),
+			CustomCAKey:            viper.GetString(customCAKey),
 			ShouldLoadCachedImages: viper.GetBool(cacheImages),
 			EnableDefaultCNI:       selectedEnableDefaultCNI,
 		},
@@ -665,6 +1220,15 @@ func generateConfig(cmd *cobra.Command, k8sVersion string) (cfg.Config, error) {
 	return cfg, nil
 }
 
+// rootlessFeatureGates enables the feature gate which lets the kubelet run pods using the host namespaces,
+// or privileged, outside of the user namespace of the containers
+func rootlessFeatureGates(gates string) string {
+	if strings.Contains(gates, "ExperimentalHostUserNamespaceDefaulting") {
+		return gates
+	}
+	return strings.TrimPrefix(gates+",ExperimentalHostUserNamespaceDefaulting=true", ",")
+}
+
 // autoSetOptions sets the options needed for specific vm-driver automatically.
 func autoSetOptions(vmDriver string) error {
 	//  options for none driver
@@ -759,6 +1323,65 @@ func validateNetwork(h *host.Host) string {
 	return ip
 }
 
+// validateIPFamily ensures that the driver, bootstrapper and Kubernetes version support the IP family
+func validateIPFamily(k8sVersion string) {
+	family := viper.GetString(ipFamily)
+	if err := pkgutil.ValidateIPFamily(family); err != nil {
+		exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": ipFamily, "error": err})
+	}
+	if family == pkgutil.IPFamilyIPv4 {
+		return
+	}
+	if driver := viper.GetString(vmDriver); driver != constants.DriverKvm2 && driver != constants.DriverNone {
+		exit.UsageT("--{{.flag}}={{.family}} is not supported by the {{.driver}} driver, use kvm2 or none", out.V{"flag": ipFamily, "family": family, "driver": driver})
+	}
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		exit.UsageT("--{{.flag}}={{.family}} is only supported by the kubeadm bootstrapper", out.V{"flag": ipFamily, "family": family})
+	}
+	if family == pkgutil.IPFamilyDual && semver.MustParse(strings.TrimPrefix(k8sVersion, version.VersionPrefix)).LT(semver.MustParse("1.16.0")) {
+		exit.UsageT("Dual-stack clusters require Kubernetes v1.16.0 or newer, use --kubernetes-version", out.V{})
+	}
+}
+
+// kvmPrivateNetworkCIDR is the subnet of the private network which the kvm2 driver creates for its VMs
+const kvmPrivateNetworkCIDR = "192.168.39.0/24"
+
+// validateStaticIP ensures that the driver supports --static-ip, and that the VM can get the address
+func validateStaticIP() {
+	ip := viper.GetString(staticIP)
+	if ip == "" {
+		return
+	}
+	if driver := viper.GetString(vmDriver); driver != constants.DriverKvm2 {
+		exit.UsageT("--{{.flag}} is not supported by the {{.driver}} driver, use kvm2", out.V{"flag": staticIP, "driver": driver})
+	}
+	if err := checkStaticIP(ip, kvmPrivateNetworkCIDR); err != nil {
+		exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": staticIP, "error": err})
+	}
+}
+
+// checkStaticIP returns an error unless ip is an IPv4 address which hosts of cidr can use
+func checkStaticIP(ip string, cidr string) error {
+	addr := net.ParseIP(ip)
+	if addr == nil || addr.To4() == nil {
+		return fmt.Errorf("%q is not an IPv4 address", ip)
+	}
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	if !subnet.Contains(addr) {
+		return fmt.Errorf("%s is not in %s", ip, cidr)
+	}
+	// The first address is the network, the second the host (gateway), and the last one the broadcast address
+	last := addr.To4()[3]
+	mask := subnet.Mask[3]
+	if host := last &^ mask; host == 0 || host == 1 || host == ^mask {
+		return fmt.Errorf("%s is reserved in %s, use an address from .2 to .254", ip, cidr)
+	}
+	return nil
+}
+
 // validateKubernetesVersions ensures that the requested version is reasonable
 func validateKubernetesVersions(old *cfg.Config) (string, bool) {
 	rawVersion := viper.GetString(kubernetesVersion)
@@ -794,13 +1417,19 @@ func validateKubernetesVersions(old *cfg.Config) (string, bool) {
 	return nv, isUpgrade
 }
 
-// setupKubeAdm adds any requested files into the VM before Kubernetes is started
-func setupKubeAdm(mAPI libmachine.API, kc cfg.KubernetesConfig) bootstrapper.Bootstrapper {
+// clusterBootstrapper returns the bootstrapper selected with --bootstrapper
+func clusterBootstrapper(mAPI libmachine.API) bootstrapper.Bootstrapper {
 	bs, err := getClusterBootstrapper(mAPI, viper.GetString(cmdcfg.Bootstrapper))
 	if err != nil {
 		exit.WithError("Failed to get bootstrapper", err)
 	}
-	for _, eo := range extraOptions {
+	return bs
+}
+
+// setupKubeAdm adds any requested files into the VM before Kubernetes is started
+func setupKubeAdm(mAPI libmachine.API, kc cfg.KubernetesConfig) bootstrapper.Bootstrapper {
+	bs := clusterBootstrapper(mAPI)
+	for _, eo := range kc.ExtraOptions {
 		out.T(out.Option, "{{.extra_option_component_name}}.{{.key}}={{.value}}", out.V{"extra_option_component_name": eo.Component, "key": eo.Key, "value": eo.Value})
 	}
 	// Loads cached images, generates config files, download binaries
@@ -819,10 +1448,20 @@ func updateKubeConfig(h *host.Host, c *cfg.Config) *pkgutil.KubeConfigSetup {
 	if err != nil {
 		exit.WithError("Failed to get driver URL", err)
 	}
-	addr = strings.Replace(addr, "tcp://", "https://", -1)
-	addr = strings.Replace(addr, ":2376", ":"+strconv.Itoa(c.KubernetesConfig.NodePort), -1)
+	u, err := url.Parse(addr)
+	if err != nil {
+		exit.WithError("Failed to parse driver URL", err)
+	}
+	hostname := u.Hostname()
 	if c.KubernetesConfig.APIServerName != constants.APIServerName {
-		addr = strings.Replace(addr, c.KubernetesConfig.NodeIP, c.KubernetesConfig.APIServerName, -1)
+		hostname = c.KubernetesConfig.APIServerName
+	}
+	addr = "https://" + net.JoinHostPort(hostname, strconv.Itoa(c.KubernetesConfig.NodePort))
+	if s, err := autostop.Load(cfg.GetMachineName()); err != nil {
+		exit.WithError("Failed to load auto-stop", err)
+	} else if s != nil {
+		// kubectl goes through the auto-stop listener, which sees the API requests
+		addr = "https://" + net.JoinHostPort("localhost", strconv.Itoa(s.Port))
 	}
 
 	kcs := &pkgutil.KubeConfigSetup{
@@ -834,33 +1473,59 @@ func updateKubeConfig(h *host.Host, c *cfg.Config) *pkgutil.KubeConfigSetup {
 		KeepContext:          viper.GetBool(keepContext),
 		EmbedCerts:           viper.GetBool(embedCerts),
 	}
-	kcs.SetKubeConfigFile(cmdutil.GetKubeConfigPath())
+	if viper.GetBool(execCredential) {
+		kcs.Exec = credentialExec(cfg.GetMachineName())
+	}
+	if viper.GetBool(kubeconfigPerProfile) {
+		// The file holds no other context to keep
+		kcs.KeepContext = false
+		kcs.SetKubeConfigFile(constants.MakeProfileKubeconfigPath(cfg.GetMachineName()))
+	} else {
+		kcs.SetKubeConfigFile(cmdutil.GetKubeConfigPath())
+	}
 	if err := pkgutil.SetupKubeConfig(kcs); err != nil {
 		exit.WithError("Failed to setup kubeconfig", err)
 	}
 	return kcs
 }
 
-// configureRuntimes does what needs to happen to get a runtime going.
-func configureRuntimes(runner cruntime.CommandRunner) cruntime.Manager {
-	config := cruntime.Config{Type: viper.GetString(containerRuntime), Runner: runner}
+// runtimeManager returns the manager of the container runtime selected with --container-runtime
+func runtimeManager(runner cruntime.CommandRunner) cruntime.Manager {
+	config := cruntime.Config{Type: viper.GetString(containerRuntime), Runner: runner, Snapshotter: viper.GetString(snapshotter)}
 	cr, err := cruntime.New(config)
 	if err != nil {
 		exit.WithError("Failed runtime", err)
 	}
+	return cr
+}
+
+// configureRuntimes does what needs to happen to get a runtime going.
+func configureRuntimes(runner cruntime.CommandRunner) cruntime.Manager {
+	cr := runtimeManager(runner)
 
 	disableOthers := true
 	if viper.GetString(vmDriver) == constants.DriverNone {
 		disableOthers = false
 	}
-	err = cr.Enable(disableOthers)
-	if err != nil {
+	if err := cr.Enable(disableOthers); err != nil {
 		exit.WithError("Failed to enable container runtime", err)
 	}
 
 	return cr
 }
 
+// configureRegistries configures the registries of the container runtime, and mirrors Docker Hub with the
+// registry-proxy-cache addon if it is enabled, as /etc was reset when the VM booted
+func configureRegistries(cr cruntime.Manager, config cfg.Config) {
+	enabled, err := assets.Addons["registry-proxy-cache"].IsEnabled()
+	if err != nil {
+		glog.Warningf("registry-proxy-cache: %v", err)
+	}
+	if err := cmdcfg.ConfigureRegistries(cr, config, enabled); err != nil {
+		out.WarningT("Unable to configure registries: {{.error}}", out.V{"error": err})
+	}
+}
+
 // bootstrapCluster starts Kubernetes using the chosen bootstrapper
 func bootstrapCluster(bs bootstrapper.Bootstrapper, r cruntime.Manager, runner command.Runner, kc cfg.KubernetesConfig, preexisting bool, isUpgrade bool) {
 	// hum. bootstrapper.Bootstrapper should probably have a Name function.
@@ -868,13 +1533,16 @@ func bootstrapCluster(bs bootstrapper.Bootstrapper, r cruntime.Manager, runner c
 
 	if isUpgrade || !preexisting {
 		out.T(out.Pulling, "Pulling images ...")
+		span := trace.StartSpan("pull images")
 		if err := bs.PullImages(kc); err != nil {
 			out.T(out.FailureType, "Unable to pull images, which may be OK: {{.error}}", out.V{"error": err})
 		}
+		span.Finish()
 	}
 
 	if preexisting {
 		out.T(out.Restarting, "Relaunching Kubernetes using {{.bootstrapper}} ... ", out.V{"bootstrapper": bsName})
+		defer trace.StartSpan(bsName + " restart").Finish()
 		if err := bs.RestartCluster(kc); err != nil {
 			exit.WithLogEntries("Error restarting cluster", err, logs.FindProblems(r, bs, runner))
 		}
@@ -882,16 +1550,22 @@ func bootstrapCluster(bs bootstrapper.Bootstrapper, r cruntime.Manager, runner c
 	}
 
 	out.T(out.Launch, "Launching Kubernetes ... ")
+	defer trace.StartSpan(bsName + " init").Finish()
 	if err := bs.StartCluster(kc); err != nil {
 		exit.WithLogEntries("Error starting cluster", err, logs.FindProblems(r, bs, runner))
 	}
 }
 
 // configureMounts configures any requested filesystem mounts
-func configureMounts() {
+func configureMounts(h *host.Host) {
 	if !viper.GetBool(createMount) {
 		return
 	}
+	if viper.GetString(mountFSType) == virtiofs {
+		hostPath, vmPath := splitMountString(viper.GetString(mountString))
+		mountVirtiofs(h, hostPath, vmPath)
+		return
+	}
 
 	out.T(out.Mounting, "Creating mount {{.name}} ...", out.V{"name": viper.GetString(mountString)})
 	path := os.Args[0]
@@ -899,7 +1573,7 @@ func configureMounts() {
 	if glog.V(8) {
 		mountDebugVal = 1
 	}
-	mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), viper.GetString(mountString))
+	mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), "--type="+viper.GetString(mountFSType), viper.GetString(mountString))
 	mountCmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
 	if glog.V(8) {
 		mountCmd.Stdout = os.Stdout
@@ -913,6 +1587,95 @@ func configureMounts() {
 	}
 }
 
+// splitMountString splits a <source directory>:<target directory> mount string
+func splitMountString(s string) (string, string) {
+	idx := strings.LastIndex(s, ":")
+	if idx == -1 {
+		exit.UsageT(`mount argument "{{.value}}" must be in form: <source directory>:<target directory>`, out.V{"value": s})
+	}
+	return s[:idx], s[idx+1:]
+}
+
+// validateUSBPassthrough checks that the driver can pass USB devices through, and the format of the devices
+func validateUSBPassthrough() {
+	devices := viper.GetStringSlice(usbPassthrough)
+	if len(devices) == 0 {
+		return
+	}
+	switch viper.GetString(vmDriver) {
+	case constants.DriverKvm2, constants.DriverQemu2:
+	case constants.DriverHyperkit:
+		exit.UsageT("The hyperkit driver does not emulate USB, so it can not pass USB devices through: use the qemu2 driver")
+	default:
+		exit.UsageT("--{{.flag}} is only supported with the kvm2 and qemu2 drivers", out.V{"flag": usbPassthrough})
+	}
+	for _, d := range devices {
+		if _, err := pkgdrivers.ParseUSBDevice(d); err != nil {
+			exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": usbPassthrough, "error": err})
+		}
+	}
+}
+
+// validateExtraDisks checks that the driver can attach --extra-disks to the VM
+func validateExtraDisks() {
+	n := viper.GetInt(extraDisks)
+	if n == 0 {
+		return
+	}
+	if n < 0 || n > pkgdrivers.MaxExtraDisks {
+		exit.UsageT("Invalid --{{.flag}}: {{.count}} is not between 1 and {{.max}}", out.V{"flag": extraDisks, "count": n, "max": pkgdrivers.MaxExtraDisks})
+	}
+	switch viper.GetString(vmDriver) {
+	case constants.DriverKvm2, constants.DriverQemu2, constants.DriverHyperkit:
+	default:
+		exit.UsageT("--{{.flag}} is only supported with the kvm2, qemu2 and hyperkit drivers", out.V{"flag": extraDisks})
+	}
+}
+
+// validateSnapshotter checks that --snapshotter is supported by the container runtime
+func validateSnapshotter() {
+	name := viper.GetString(snapshotter)
+	switch name {
+	case "", cruntime.OverlayfsSnapshotter:
+		return
+	case cruntime.StargzSnapshotter:
+	default:
+		exit.UsageT("Invalid --{{.flag}}: {{.name}} is not one of {{.snapshotters}}", out.V{"flag": snapshotter, "name": name, "snapshotters": strings.Join(cruntime.Snapshotters, ", ")})
+	}
+	if viper.GetString(containerRuntime) != "containerd" {
+		exit.UsageT("--{{.flag}}={{.name}} is only supported with the containerd container runtime", out.V{"flag": snapshotter, "name": name})
+	}
+	// The preloaded images are unpacked for overlayfs, which the stargz snapshotter does not read
+	if viper.GetString(preloadFile) != "" {
+		exit.UsageT("--{{.flag}}={{.name}} can not be used with --{{.preload}}", out.V{"flag": snapshotter, "name": name, "preload": preloadFile})
+	}
+}
+
+// virtiofsShares returns the host directories to share with the VM over virtiofs
+func virtiofsShares() []string {
+	if !viper.GetBool(createMount) || viper.GetString(mountFSType) != virtiofs {
+		return nil
+	}
+	hostPath, _ := splitMountString(viper.GetString(mountString))
+	return []string{hostPath}
+}
+
+// startMountString returns the mount created by start, if any
+func startMountString() string {
+	if !viper.GetBool(createMount) {
+		return ""
+	}
+	return viper.GetString(mountString)
+}
+
+// startMountType returns the type of the mount created by start, if any
+func startMountType() string {
+	if !viper.GetBool(createMount) {
+		return ""
+	}
+	return viper.GetString(mountFSType)
+}
+
 // saveConfig saves profile cluster configuration in $MINIKUBE_HOME/profiles/<profilename>/config.json
 func saveConfig(clusterConfig *cfg.Config) error {
 	data, err := json.MarshalIndent(clusterConfig, "", "    ")
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+// auditOptions are the apiserver options which enable the audit log, rotated so that it does not fill the disk.
+// Options set with --extra-config take precedence.
+var auditOptions = pkgutil.ExtraOptionSlice{
+	{Component: kubeadm.Apiserver, Key: "audit-policy-file", Value: kubeadm.AuditPolicyPath},
+	{Component: kubeadm.Apiserver, Key: "audit-log-path", Value: kubeadm.AuditLogPath},
+	{Component: kubeadm.Apiserver, Key: "audit-log-maxsize", Value: "100"},
+	{Component: kubeadm.Apiserver, Key: "audit-log-maxbackup", Value: "1"},
+}
+
+// validateAuditPolicy validates --apiserver-audit-policy
+func validateAuditPolicy(k8sVersion string) {
+	policy := viper.GetString(apiServerAuditPolicy)
+	if policy == "" {
+		return
+	}
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		exit.UsageT("--{{.flag}} is only supported by the kubeadm bootstrapper", out.V{"flag": apiServerAuditPolicy})
+	}
+	if semver.MustParse(strings.TrimPrefix(k8sVersion, version.VersionPrefix)).LT(semver.MustParse("1.13.0")) {
+		exit.UsageT("--{{.flag}} requires Kubernetes v1.13.0 or newer, use --kubernetes-version", out.V{"flag": apiServerAuditPolicy})
+	}
+	if err := checkAuditPolicy(policy); err != nil {
+		exit.WithCodeT(exit.NoInput, "Invalid --{{.flag}}: {{.error}}", out.V{"flag": apiServerAuditPolicy, "error": err})
+	}
+}
+
+// checkAuditPolicy returns an error if the file is not an audit policy
+func checkAuditPolicy(file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var policy struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if err := yaml.Unmarshal(b, &policy); err != nil {
+		return errors.Wrapf(err, "parsing %s", file)
+	}
+	if policy.Kind != "Policy" || !strings.HasPrefix(policy.APIVersion, "audit.k8s.io/") {
+		return fmt.Errorf("%s is not an audit.k8s.io Policy", file)
+	}
+	return nil
+}
+
+// auditPolicy returns the absolute path of --apiserver-audit-policy, or the policy of the existing profile,
+// so that the audit log is kept by the next starts
+func auditPolicy(existing cfg.KubernetesConfig) string {
+	policy := viper.GetString(apiServerAuditPolicy)
+	if policy == "" {
+		return existing.AuditPolicy
+	}
+	abs, err := filepath.Abs(policy)
+	if err != nil {
+		exit.WithError("Failed to get the audit policy path", err)
+	}
+	return abs
+}
+
+// configureAudit copies the audit policy to the VM, and adds the apiserver options which enable the audit log
+func configureAudit(runner command.Runner, c *cfg.Config) {
+	policy := c.KubernetesConfig.AuditPolicy
+	if policy == "" {
+		return
+	}
+	f, err := assets.NewFileAsset(policy, path.Dir(kubeadm.AuditPolicyPath), path.Base(kubeadm.AuditPolicyPath), "0644")
+	if err != nil {
+		exit.WithCodeT(exit.NoInput, "Cannot read the audit policy {{.path}}: {{.error}}", out.V{"path": policy, "error": err})
+	}
+	if err := runner.Copy(f); err != nil {
+		exit.WithError("Failed to copy the audit policy", err)
+	}
+	for _, o := range auditOptions {
+		if c.KubernetesConfig.ExtraOptions.Get(o.Key, o.Component) == "" {
+			c.KubernetesConfig.ExtraOptions = append(c.KubernetesConfig.ExtraOptions, o)
+		}
+	}
+	if err := saveConfig(c); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+	out.T(out.Documentation, "Auditing the apiserver with {{.policy}}, see 'minikube logs --component=apiserver-audit'", out.V{"policy": policy})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/checkpoint"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// startCheckpoint returns the checkpoint of the last start with --continue, if it failed with the same config,
+// and a new checkpoint otherwise
+func startCheckpoint(config cfg.Config) *checkpoint.Checkpoint {
+	profile := viper.GetString(cfg.MachineProfile)
+	fingerprint, err := checkpoint.Fingerprint(config)
+	if err != nil {
+		exit.WithError("Failed to fingerprint config", err)
+	}
+	last, err := checkpoint.Load(profile)
+	if err != nil {
+		glog.Warningf("Ignoring the start checkpoint: %v", err)
+		last = nil
+	}
+
+	switch {
+	case !viper.GetBool(continueStart):
+		if last != nil && last.Last() != "" {
+			out.T(out.Tip, "The last start of {{.profile}} failed after the {{.phase}} phase: 'minikube start --continue' resumes it", out.V{"profile": profile, "phase": last.Last()})
+		}
+	case last == nil:
+		out.T(out.Meh, "There is no failed start of {{.profile}} to continue, starting from the beginning", out.V{"profile": profile})
+	case last.Config != fingerprint:
+		out.WarningT("The configuration changed since the last start of {{.profile}}, starting from the beginning", out.V{"profile": profile})
+	default:
+		out.T(out.Restarting, "Continuing the last start of {{.profile}} after the {{.phase}} phase", out.V{"profile": profile, "phase": last.Last()})
+		return last
+	}
+
+	cp := checkpoint.New(profile, fingerprint)
+	if err := cp.Save(); err != nil {
+		glog.Warningf("Unable to save the start checkpoint: %v", err)
+	}
+	return cp
+}
+
+// checkpointMachine completes the machine phase. It returns whether the VM pre-existed this start, or the one it continues.
+func checkpointMachine(cp *checkpoint.Checkpoint, runner command.Runner, preExists bool) bool {
+	if !cp.Completed(checkpoint.Machine) {
+		cp.Created = !preExists
+	}
+	bootID, err := runner.CombinedOutput("cat /proc/sys/kernel/random/boot_id")
+	if err != nil {
+		glog.Warningf("Unable to get the boot ID: %v", err)
+	}
+	if cp.Boot(strings.TrimSpace(bootID)) {
+		out.T(out.Notice, "The VM restarted since the last start, which is continued from the provision phase")
+	}
+	completePhase(cp, checkpoint.Machine)
+	return preExists && !cp.Created
+}
+
+// completePhase records that a phase of the start completed
+func completePhase(cp *checkpoint.Checkpoint, p checkpoint.Phase) {
+	glog.Infof("Completed the %s phase", p)
+	if err := cp.Complete(p); err != nil {
+		glog.Warningf("Unable to save the start checkpoint: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/encryption"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+// encryptionOption is the option of the apiserver which reads the EncryptionConfiguration
+const encryptionOption = "encryption-provider-config"
+
+// validateEncryption validates --encrypt-secrets
+func validateEncryption(k8sVersion string) {
+	if !viper.GetBool(encryptSecrets) {
+		return
+	}
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		exit.UsageT("--{{.flag}} is only supported by the kubeadm bootstrapper", out.V{"flag": encryptSecrets})
+	}
+	if semver.MustParse(strings.TrimPrefix(k8sVersion, version.VersionPrefix)).LT(semver.MustParse("1.13.0")) {
+		exit.UsageT("--{{.flag}} requires Kubernetes v1.13.0 or newer, use --kubernetes-version", out.V{"flag": encryptSecrets})
+	}
+}
+
+// configureEncryption copies the EncryptionConfiguration of the profile to the VM, generating its key on the
+// first start, and adds the apiserver option which reads it
+func configureEncryption(runner command.Runner, c *cfg.Config) {
+	if !c.KubernetesConfig.EncryptSecrets {
+		return
+	}
+	ec, err := encryption.LoadOrNew(encryption.Path(viper.GetString(cfg.MachineProfile)))
+	if err != nil {
+		exit.WithError("Failed to load the encryption config", err)
+	}
+	if err := ec.Copy(runner); err != nil {
+		exit.WithError("Failed to copy the encryption config", err)
+	}
+	if c.KubernetesConfig.ExtraOptions.Get(encryptionOption, kubeadm.Apiserver) == "" {
+		c.KubernetesConfig.ExtraOptions = append(c.KubernetesConfig.ExtraOptions,
+			pkgutil.ExtraOption{Component: kubeadm.Apiserver, Key: encryptionOption, Value: encryption.VMPath})
+	}
+	if err := saveConfig(c); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+	out.T(out.Permissions, "Encrypting secrets with {{.key}}", out.V{"key": ec.Keys()[0]})
+}
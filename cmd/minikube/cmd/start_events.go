@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/golang/glog"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/eventserver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// startEventServer streams the messages of the start as events with --event-listener, until the returned function is called.
+// The events are also sent if the start fails.
+func startEventServer() func() {
+	addr := viper.GetString(eventListener)
+	if addr == "" {
+		return func() {}
+	}
+	srv, err := eventserver.Listen(addr)
+	if err != nil {
+		exit.WithCodeT(exit.Unavailable, "Unable to listen for events on {{.address}}: {{.error}}", out.V{"address": addr, "error": err})
+	}
+	out.AddListener(srv.Send)
+	closeServer := func() {
+		if err := srv.Close(); err != nil {
+			glog.Warningf("closing the event server: %v", err)
+		}
+	}
+	exit.AtExit(func(code int, msg string) {
+		closeServer()
+	})
+	out.T(out.Notice, "Streaming events to {{.url}}", out.V{"url": srv.URL()})
+	return closeServer
+}
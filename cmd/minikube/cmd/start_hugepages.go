@@ -0,0 +1,130 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+// numaOptions are the kubelet options which give Guaranteed pods exclusive CPUs, aligned with their devices on
+// the NUMA nodes of the VM. The static CPU manager needs CPUs reserved for the system. Options set with
+// --extra-config take precedence.
+var numaOptions = pkgutil.ExtraOptionSlice{
+	{Component: kubeadm.Kubelet, Key: "cpu-manager-policy", Value: "static"},
+	{Component: kubeadm.Kubelet, Key: "system-reserved", Value: "cpu=500m"},
+	{Component: kubeadm.Kubelet, Key: "topology-manager-policy", Value: "best-effort"},
+}
+
+// topologyManagerVersion is the first Kubernetes version with the topology manager of the kubelet
+var topologyManagerVersion = semver.MustParse("1.16.0")
+
+// validateHugePages validates --hugepages, which have to leave enough memory to the rest of the VM
+func validateHugePages() {
+	s := viper.GetString(hugePages)
+	if s == "" {
+		return
+	}
+	if viper.GetString(vmDriver) == constants.DriverNone {
+		exit.UsageT("--{{.flag}} is not supported by the none driver: reserve huge pages on the host instead", out.V{"flag": hugePages})
+	}
+	pages, err := cluster.ParseHugePages(s)
+	if err != nil {
+		exit.UsageT("Invalid --{{.flag}}: {{.error}}", out.V{"flag": hugePages, "error": err})
+	}
+	memoryMB := pkgutil.CalculateSizeInMB(viper.GetString(memory))
+	left := memoryMB - cluster.HugePagesMemory(pages)
+	if min := pkgutil.CalculateSizeInMB(constants.MinimumMemorySize); left < min {
+		exit.UsageT("--{{.flag}} leaves {{.left}}MB of the {{.memory}}MB of the VM, less than the minimum of {{.min}}MB: increase --memory",
+			out.V{"flag": hugePages, "left": left, "memory": memoryMB, "min": min})
+	}
+}
+
+// validateNUMA validates --numa-node-count
+func validateNUMA(k8sVersion string) {
+	n := viper.GetInt(numaNodeCount)
+	if n < 1 {
+		exit.UsageT("Invalid --{{.flag}}: {{.count}} is not a positive number", out.V{"flag": numaNodeCount, "count": n})
+	}
+	if n == 1 {
+		return
+	}
+	if driver := viper.GetString(vmDriver); driver != constants.DriverKvm2 && driver != constants.DriverQemu2 {
+		exit.UsageT("--{{.flag}} is only supported with the kvm2 and qemu2 drivers", out.V{"flag": numaNodeCount})
+	}
+	if c := viper.GetInt(cpus); n > c {
+		exit.UsageT("--{{.flag}} can not be more than the {{.cpus}} CPUs of the VM: increase --cpus", out.V{"flag": numaNodeCount, "cpus": c})
+	}
+	if !topologyManagerSupported(k8sVersion) {
+		out.WarningT("The kubelet of Kubernetes {{.version}} does not align containers with NUMA nodes, which needs v1.16.0 or newer", out.V{"version": k8sVersion})
+	}
+}
+
+// topologyManagerSupported returns whether the kubelet of a Kubernetes version has the topology manager
+func topologyManagerSupported(k8sVersion string) bool {
+	v, err := semver.Make(strings.TrimPrefix(k8sVersion, version.VersionPrefix))
+	if err != nil {
+		return false
+	}
+	return v.GTE(topologyManagerVersion)
+}
+
+// numaKubeletConfig enables the topology manager of the kubelet, with the static CPU manager, for VMs with several NUMA nodes
+func numaKubeletConfig(nodes int, k8sVersion string, gates string, opts pkgutil.ExtraOptionSlice) (string, pkgutil.ExtraOptionSlice) {
+	if nodes <= 1 || !topologyManagerSupported(k8sVersion) {
+		return gates, opts
+	}
+	for _, o := range numaOptions {
+		if opts.Get(o.Key, o.Component) == "" {
+			opts = append(opts, o)
+		}
+	}
+	if !strings.Contains(gates, "TopologyManager") {
+		gates = strings.TrimPrefix(gates+",TopologyManager=true", ",")
+	}
+	return gates, opts
+}
+
+// configureHugePages reserves the huge pages of the VM, which are lost when it reboots. A running kubelet is
+// restarted, as it only reads the huge pages of the node when it starts.
+func configureHugePages(runner command.Runner, c *cfg.Config) {
+	if c.MachineConfig.HugePages == "" {
+		return
+	}
+	pages, err := cluster.ParseHugePages(c.MachineConfig.HugePages)
+	if err != nil {
+		exit.WithCodeT(exit.Data, "Invalid huge pages in the profile config: {{.error}}", out.V{"error": err})
+	}
+	if err := cluster.ReserveHugePages(runner, pages); err != nil {
+		exit.WithCodeT(exit.Unavailable, "Failed to reserve huge pages: {{.error}}", out.V{"error": err})
+	}
+	if err := runner.Run("sudo systemctl is-active --quiet kubelet && sudo systemctl restart kubelet || true"); err != nil {
+		exit.WithError("Failed to restart the kubelet", err)
+	}
+	out.T(out.Option, "Reserved huge pages: {{.pages}}", out.V{"pages": c.MachineConfig.HugePages})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+const (
+	// dexAddon is the addon of the dex OpenID Connect provider
+	dexAddon = "dex"
+	// dexNodePort is the port of the node which dex listens on
+	dexNodePort = 32000
+	// dexClientID is the static client of dex for kubectl
+	dexClientID = "minikube"
+	// oidcCAName is the name of the copy of --oidc-ca-file in the certificates directory of the VM
+	oidcCAName = "oidc-ca.crt"
+)
+
+// oidcFlags maps the --oidc flags to the options of the apiserver, in the order in which they are added
+var oidcFlags = []struct {
+	flag string
+	key  string
+}{
+	{oidcIssuerURL, "oidc-issuer-url"},
+	{oidcClientID, "oidc-client-id"},
+	{oidcUsernameClaim, "oidc-username-claim"},
+	{oidcGroupsClaim, "oidc-groups-claim"},
+	{oidcCAFile, "oidc-ca-file"},
+}
+
+// validateOIDC validates the --oidc flags
+func validateOIDC() {
+	issuer := viper.GetString(oidcIssuerURL)
+	used := viper.GetBool(oidcDex)
+	for _, f := range oidcFlags {
+		used = used || viper.GetString(f.flag) != ""
+	}
+	if !used {
+		return
+	}
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		exit.UsageT("The --oidc flags are only supported by the kubeadm bootstrapper")
+	}
+	if !viper.GetBool(oidcDex) && (issuer == "" || viper.GetString(oidcClientID) == "") {
+		exit.UsageT("--{{.issuer}} and --{{.client}} are required to use OpenID Connect, or --{{.dex}} for a local provider", out.V{"issuer": oidcIssuerURL, "client": oidcClientID, "dex": oidcDex})
+	}
+	if issuer != "" {
+		u, err := url.Parse(issuer)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			exit.UsageT("--{{.flag}} must be an https URL, such as https://accounts.example.com", out.V{"flag": oidcIssuerURL})
+		}
+	}
+	if f := viper.GetString(oidcCAFile); f != "" {
+		if _, err := os.Stat(f); err != nil {
+			exit.WithCodeT(exit.NoInput, "Cannot read --{{.flag}}: {{.error}}", out.V{"flag": oidcCAFile, "error": err})
+		}
+	}
+}
+
+// oidcOptions returns existing with the apiserver options of the --oidc flags. With --oidc-dex, the options
+// which are not given default to the dex addon at nodeIP, whose certificate is signed by the minikube CA.
+// Options of existing, which come from --extra-config, take precedence over the flags.
+func oidcOptions(existing pkgutil.ExtraOptionSlice, nodeIP string) pkgutil.ExtraOptionSlice {
+	values := map[string]string{}
+	for _, f := range oidcFlags {
+		values[f.key] = viper.GetString(f.flag)
+	}
+	if values["oidc-ca-file"] != "" {
+		values["oidc-ca-file"] = pkgutil.DefaultCertPath + oidcCAName
+	}
+	if viper.GetBool(oidcDex) {
+		defaults := map[string]string{
+			"oidc-issuer-url":     dexIssuerURL(nodeIP),
+			"oidc-client-id":      dexClientID,
+			"oidc-username-claim": "email",
+			"oidc-groups-claim":   "groups",
+			"oidc-ca-file":        pkgutil.DefaultCertPath + "ca.crt",
+		}
+		for k, v := range defaults {
+			if values[k] == "" {
+				values[k] = v
+			}
+		}
+	}
+
+	opts := append(pkgutil.ExtraOptionSlice{}, existing...)
+	for _, f := range oidcFlags {
+		v := values[f.key]
+		if v == "" || existing.Get(f.key, kubeadm.Apiserver) != "" {
+			continue
+		}
+		opts = append(opts, pkgutil.ExtraOption{Component: kubeadm.Apiserver, Key: f.key, Value: v})
+	}
+	return opts
+}
+
+// dexIssuerURL returns the URL of the dex addon on the node
+func dexIssuerURL(nodeIP string) string {
+	return "https://" + net.JoinHostPort(nodeIP, strconv.Itoa(dexNodePort)) + "/dex"
+}
+
+// configureOIDC adds the --oidc options to the config of the apiserver, now that the IP of the node is known,
+// and copies --oidc-ca-file into the VM
+func configureOIDC(runner command.Runner, c *cfg.Config) {
+	if viper.GetString(oidcIssuerURL) == "" && !viper.GetBool(oidcDex) {
+		return
+	}
+	if f := viper.GetString(oidcCAFile); f != "" {
+		ca, err := assets.NewFileAsset(f, pkgutil.DefaultCertPath, oidcCAName, "0644")
+		if err != nil {
+			exit.WithCodeT(exit.NoInput, "Cannot read --{{.flag}}: {{.error}}", out.V{"flag": oidcCAFile, "error": err})
+		}
+		if err := runner.Copy(ca); err != nil {
+			exit.WithError("Failed to copy the OpenID Connect CA", err)
+		}
+	}
+	c.KubernetesConfig.ExtraOptions = oidcOptions(c.KubernetesConfig.ExtraOptions, c.KubernetesConfig.NodeIP)
+	if err := saveConfig(c); err != nil {
+		exit.WithError("Failed to save config", err)
+	}
+}
+
+// enableDex creates the certificate of dex for the node, signed by the minikube CA, and enables the dex addon
+func enableDex(runner command.Runner, nodeIP string) {
+	if err := copyDexCert(runner, nodeIP); err != nil {
+		exit.WithError("Failed to create the dex certificate", err)
+	}
+	enabled, err := assets.Addons[dexAddon].IsEnabled()
+	if err != nil {
+		exit.WithError("Failed to check addon status", err)
+	}
+	if !enabled {
+		if err := cmdcfg.Set(dexAddon, "true"); err != nil {
+			exit.WithError("Failed to enable dex", err)
+		}
+		out.T(out.Enabling, "Enabled addon {{.name}}", out.V{"name": dexAddon})
+	}
+	out.T(out.Tip, "dex issues ID tokens for {{.user}} at {{.url}}", out.V{"user": "admin@example.com", "url": dexIssuerURL(nodeIP)})
+}
+
+// copyDexCert copies a certificate for nodeIP, signed by the minikube CA, to the certificates directory of the VM,
+// which the dex pod mounts
+func copyDexCert(runner command.Runner, nodeIP string) error {
+	dir, err := ioutil.TempDir("", "dex-tls")
+	if err != nil {
+		return errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "dex.crt")
+	keyPath := filepath.Join(dir, "dex.key")
+	ips := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP(nodeIP)}
+	names := []string{"localhost", "dex", "dex.kube-system", "dex.kube-system.svc"}
+	if err := pkgutil.GenerateSignedCert(certPath, keyPath, "dex", ips, names,
+		constants.MakeMiniPath("ca.crt"), constants.MakeMiniPath("ca.key")); err != nil {
+		return errors.Wrap(err, "generating certificate")
+	}
+	for p, perm := range map[string]string{certPath: "0644", keyPath: "0600"} {
+		f, err := assets.NewFileAsset(p, pkgutil.DefaultCertPath, filepath.Base(p), perm)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", p)
+		}
+		if err := runner.Copy(f); err != nil {
+			return errors.Wrapf(err, "copying %s", p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// applyProfileConfig sets the start flags from the config of a profile which has no VM yet, such as one
+// created by 'minikube profile import'. As with a cluster spec, flags given on the command line take precedence.
+func applyProfileConfig(cmd *cobra.Command) {
+	name := viper.GetString(cfg.MachineProfile)
+	if _, err := os.Stat(constants.MakeMiniPath("machines", name, "config.json")); err == nil {
+		return
+	}
+	cc, err := cfg.DefaultLoader.LoadConfigFromFile(name)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Warningf("Unable to load the config of profile %s: %v", name, err)
+		}
+		return
+	}
+
+	out.T(out.Notice, "Using the config of profile {{.name}}", out.V{"name": name})
+	flags, lists := profileFlags(*cc)
+	for name, value := range flags {
+		if cmd.Flags().Changed(name) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			exit.WithCodeT(exit.Data, "Invalid {{.name}} in the profile config: {{.error}}", out.V{"name": name, "error": err})
+		}
+	}
+	for name, values := range lists {
+		if cmd.Flags().Changed(name) {
+			continue
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, v); err != nil {
+				exit.WithCodeT(exit.Data, "Invalid {{.name}} in the profile config: {{.error}}", out.V{"name": name, "error": err})
+			}
+		}
+	}
+
+	if !cmd.Flags().Changed("extra-config") {
+		for _, e := range cc.KubernetesConfig.ExtraOptions {
+			if err := extraOptions.Set(e.String()); err != nil {
+				exit.WithCodeT(exit.Data, "Invalid extra-config in the profile config: {{.error}}", out.V{"error": err})
+			}
+		}
+	}
+}
+
+// profileFlags returns the start flags which create the cluster of a profile config: the values of the
+// scalar flags, and the values of the list flags, which are set one by one
+func profileFlags(cc cfg.Config) (map[string]string, map[string][]string) {
+	m := cc.MachineConfig
+	k := cc.KubernetesConfig
+	flags := map[string]string{
+		keepContext:          strconv.FormatBool(m.KeepContext),
+		kubeconfigPerProfile: strconv.FormatBool(m.KubeconfigPerProfile),
+		kvmGPU:               strconv.FormatBool(m.KVMGPU),
+		kvmHidden:            strconv.FormatBool(m.KVMHidden),
+		disableDriverMounts:  strconv.FormatBool(m.DisableDriverMounts),
+		noVTXCheck:           strconv.FormatBool(m.NoVTXCheck),
+		dnsProxy:             strconv.FormatBool(m.DNSProxy),
+		hostDNSResolver:      strconv.FormatBool(m.HostDNSResolver),
+		rootless:             strconv.FormatBool(m.Rootless),
+		cacheImages:          strconv.FormatBool(k.ShouldLoadCachedImages),
+		enableDefaultCNI:     strconv.FormatBool(k.EnableDefaultCNI),
+		encryptSecrets:       strconv.FormatBool(k.EncryptSecrets),
+	}
+	strs := map[string]string{
+		isoURL:                m.MinikubeISO,
+		vmDriver:              m.VMDriver,
+		containerRuntime:      m.ContainerRuntime,
+		vpnkitSock:            m.HyperkitVpnKitSock,
+		nfsSharesRoot:         m.NFSSharesRoot,
+		hostOnlyCIDR:          m.HostOnlyCIDR,
+		hypervVirtualSwitch:   m.HypervVirtualSwitch,
+		kvmNetwork:            m.KVMNetwork,
+		kvmQemuURI:            m.KVMQemuURI,
+		qemuNetwork:           m.QemuNetwork,
+		qemuFirmwarePath:      m.QemuFirmwarePath,
+		socketVMnetClientPath: m.SocketVMnetClientPath,
+		socketVMnetPath:       m.SocketVMnetPath,
+		ipFamily:              m.IPFamily,
+		staticIP:              m.StaticIP,
+		kubernetesVersion:     k.KubernetesVersion,
+		apiServerName:         k.APIServerName,
+		dnsDomain:             k.DNSDomain,
+		criSocket:             k.CRISocket,
+		snapshotter:           k.Snapshotter,
+		networkPlugin:         k.NetworkPlugin,
+		featureGates:          k.FeatureGates,
+		serviceCIDR:           k.ServiceCIDR,
+		imageRepository:       k.ImageRepository,
+		customCACert:          k.CustomCACert,
+		customCAKey:           k.CustomCAKey,
+		apiServerAuditPolicy:  k.AuditPolicy,
+		hugePages:             m.HugePages,
+	}
+	for name, value := range strs {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	ints := map[string]int{
+		memory:                m.Memory,
+		cpus:                  m.CPUs,
+		humanReadableDiskSize: m.DiskSize,
+		apiServerPort:         m.APIServerPort,
+		numaNodeCount:         m.NUMANodes,
+		kvmSRIOVVFs:           m.SRIOVVFs,
+		extraDisks:            m.ExtraDisks,
+	}
+	for name, value := range ints {
+		if value != 0 {
+			flags[name] = strconv.Itoa(value)
+		}
+	}
+	if m.MountString != "" {
+		flags[createMount] = "true"
+		flags[mountString] = m.MountString
+		if m.MountType != "" {
+			flags[mountFSType] = m.MountType
+		}
+	}
+
+	var ips []string
+	for _, ip := range k.APIServerIPs {
+		ips = append(ips, ip.String())
+	}
+	lists := map[string][]string{
+		vsockPorts:          m.HyperkitVSockPorts,
+		nfsShare:            m.NFSShare,
+		"docker-env":        m.DockerEnv,
+		"docker-opt":        m.DockerOpt,
+		"insecure-registry": m.InsecureRegistry,
+		"registry-mirror":   m.RegistryMirror,
+		"apiserver-names":   k.APIServerNames,
+		usbPassthrough:      m.USBDevices,
+	}
+	if len(ips) > 0 {
+		lists["apiserver-ips"] = []string{strings.Join(ips, ",")}
+	}
+	for name, values := range lists {
+		if len(values) == 0 {
+			delete(lists, name)
+		}
+	}
+	return flags, lists
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/assets"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// applyClusterSpec sets the start flags described by a cluster spec file. Flags given on the
+// command line take precedence, so that the same file can be re-applied with local overrides.
+func applyClusterSpec(cmd *cobra.Command, path string) *cfg.ClusterSpec {
+	spec, err := cfg.LoadClusterSpec(path)
+	if err != nil {
+		exit.WithCodeT(exit.Data, "Unable to load cluster spec {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+	}
+
+	flags := map[string]string{
+		kubernetesVersion:     spec.KubernetesVersion,
+		vmDriver:              spec.Driver,
+		containerRuntime:      spec.ContainerRuntime,
+		cmdcfg.Bootstrapper:   spec.Bootstrapper,
+		memory:                spec.Memory,
+		humanReadableDiskSize: spec.DiskSize,
+		featureGates:          spec.FeatureGates,
+	}
+	if spec.CPUs > 0 {
+		flags[cpus] = strconv.Itoa(spec.CPUs)
+	}
+	if len(spec.Mounts) > 0 && !cmd.Flags().Changed(createMount) && !cmd.Flags().Changed(mountString) {
+		flags[createMount] = "true"
+		flags[mountString] = spec.Mounts[0]
+	}
+	for name, value := range flags {
+		if value == "" || cmd.Flags().Changed(name) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			exit.WithCodeT(exit.Data, "Invalid {{.name}} in cluster spec {{.path}}: {{.error}}", out.V{"name": name, "path": path, "error": err})
+		}
+	}
+
+	if !cmd.Flags().Changed("extra-config") {
+		for _, e := range spec.ExtraConfig {
+			if err := extraOptions.Set(e); err != nil {
+				exit.WithCodeT(exit.Data, "Invalid extraConfig in cluster spec {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+			}
+		}
+	}
+
+	for name := range spec.Addons {
+		if err := cmdcfg.IsValidAddon(name, ""); err != nil {
+			exit.WithCodeT(exit.Data, "Invalid addons in cluster spec {{.path}}: {{.error}}", out.V{"path": path, "error": err})
+		}
+	}
+	return spec
+}
+
+// applySpecAddons enables and disables addons to match the cluster spec, leaving the others as they are
+func applySpecAddons(spec *cfg.ClusterSpec) {
+	names := []string{}
+	for name := range spec.Addons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		enable := spec.Addons[name]
+		enabled, err := assets.Addons[name].IsEnabled()
+		if err != nil {
+			exit.WithError("Failed to check addon status", err)
+		}
+		if enabled == enable {
+			continue
+		}
+		if err := cmdcfg.Set(name, strconv.FormatBool(enable)); err != nil {
+			exit.WithError("Failed to apply cluster spec addons", err)
+		}
+		if enable {
+			out.T(out.Enabling, "Enabled addon {{.name}}", out.V{"name": name})
+		} else {
+			out.T(out.Enabling, "Disabled addon {{.name}}", out.V{"name": name})
+		}
+	}
+}
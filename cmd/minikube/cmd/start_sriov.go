@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// validateVirtualFunctions validates --kvm-sriov-vfs. The emulated NIC is added when the VM is created.
+func validateVirtualFunctions() {
+	n := viper.GetInt(kvmSRIOVVFs)
+	if n == 0 {
+		return
+	}
+	if n < 0 || n > cluster.MaxVirtualFunctions {
+		exit.UsageT("Invalid --{{.flag}}: {{.count}} is not between 1 and {{.max}}", out.V{"flag": kvmSRIOVVFs, "count": n, "max": cluster.MaxVirtualFunctions})
+	}
+	if viper.GetString(vmDriver) != constants.DriverKvm2 {
+		exit.UsageT("--{{.flag}} is only supported with the kvm2 driver", out.V{"flag": kvmSRIOVVFs})
+	}
+	// The docker runtime does not use CNI by default, which Multus needs to attach the VFs to the pods
+	if viper.GetString(containerRuntime) == "docker" && viper.GetString(networkPlugin) != "cni" {
+		exit.UsageT("--{{.flag}} needs CNI: add --network-plugin=cni --enable-default-cni, or use the containerd or cri-o runtime", out.V{"flag": kvmSRIOVVFs})
+	}
+}
+
+// configureVirtualFunctions creates the SR-IOV virtual functions of the VM, which are lost when it reboots,
+// before the kubelet starts the device plugin of the sriov-sim addon
+func configureVirtualFunctions(runner command.Runner, c *cfg.Config) {
+	n := c.MachineConfig.SRIOVVFs
+	if n == 0 {
+		return
+	}
+	if err := cluster.EnableVirtualFunctions(runner, n); err != nil {
+		exit.WithCodeT(exit.Unavailable, "Failed to create SR-IOV virtual functions: {{.error}}", out.V{"error": err})
+	}
+	out.T(out.Option, "Created {{.count}} SR-IOV virtual functions", out.V{"count": n})
+}
@@ -17,7 +17,16 @@ limitations under the License.
 package cmd
 
 import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/spf13/viper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	pkgutil "k8s.io/minikube/pkg/util"
 )
 
 func Test_extractVMDriverVersion(t *testing.T) {
@@ -43,3 +52,258 @@ func Test_extractVMDriverVersion(t *testing.T) {
 		t.Errorf("Expected version: %s, got: %s", expectedVersion, v)
 	}
 }
+
+func Test_rootlessFeatureGates(t *testing.T) {
+	var tests = []struct {
+		gates string
+		want  string
+	}{
+		{"", "ExperimentalHostUserNamespaceDefaulting=true"},
+		{"IPv6DualStack=true", "IPv6DualStack=true,ExperimentalHostUserNamespaceDefaulting=true"},
+		{"ExperimentalHostUserNamespaceDefaulting=false", "ExperimentalHostUserNamespaceDefaulting=false"},
+	}
+	for _, tc := range tests {
+		if got := rootlessFeatureGates(tc.gates); got != tc.want {
+			t.Errorf("rootlessFeatureGates(%q) = %q, want %q", tc.gates, got, tc.want)
+		}
+	}
+}
+
+func Test_checkNvidiaRuntime(t *testing.T) {
+	var tests = []struct {
+		info    string
+		wantErr bool
+	}{
+		{"nvidia {\"nvidia\":{\"path\":\"nvidia-container-runtime\"},\"runc\":{\"path\":\"runc\"}}\n", false},
+		{"runc {\"nvidia\":{\"path\":\"nvidia-container-runtime\"},\"runc\":{\"path\":\"runc\"}}\n", true},
+		{"runc {\"runc\":{\"path\":\"runc\"}}\n", true},
+		{"nvidia", true},
+		{"nvidia not-json", true},
+	}
+	for _, tc := range tests {
+		if err := checkNvidiaRuntime(tc.info); (err != nil) != tc.wantErr {
+			t.Errorf("checkNvidiaRuntime(%q) = %v, want error: %v", tc.info, err, tc.wantErr)
+		}
+	}
+}
+
+func Test_checkGPUDevices(t *testing.T) {
+	dev, err := ioutil.TempDir("", "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dev)
+	if err := os.Mkdir(filepath.Join(dev, "dri"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkGPUDevices(dev, "intel"); err == nil {
+		t.Error("checkGPUDevices(intel) = nil, want an error without render nodes")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dev, "dri", "renderD128"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkGPUDevices(dev, "intel"); err != nil {
+		t.Errorf("checkGPUDevices(intel) = %v, want nil", err)
+	}
+	if err := checkGPUDevices(dev, "amd"); err == nil {
+		t.Error("checkGPUDevices(amd) = nil, want an error without kfd")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dev, "kfd"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkGPUDevices(dev, "amd"); err != nil {
+		t.Errorf("checkGPUDevices(amd) = %v, want nil", err)
+	}
+}
+
+func Test_gpuDevicePlugin(t *testing.T) {
+	var tests = []struct {
+		driver string
+		gpus   string
+		want   string
+	}{
+		{"none", "all", "nvidia-device-plugin"},
+		{"kvm2", "nvidia", "nvidia-gpu-device-plugin"},
+		{"none", "amd", "rocm-gpu"},
+		{"kvm2", "amd", "rocm-gpu"},
+		{"none", "intel", "intel-gpu"},
+	}
+	for _, tc := range tests {
+		if got := gpuDevicePlugin(tc.driver, gpuVendors[tc.gpus]); got != tc.want {
+			t.Errorf("gpuDevicePlugin(%q, %q) = %q, want %q", tc.driver, tc.gpus, got, tc.want)
+		}
+	}
+}
+
+func Test_numaKubeletConfig(t *testing.T) {
+	gates, opts := numaKubeletConfig(1, "v1.16.0", "", nil)
+	if gates != "" || len(opts) != 0 {
+		t.Errorf("numaKubeletConfig(1) = %q, %v, want no change", gates, opts)
+	}
+	gates, opts = numaKubeletConfig(2, "v1.15.2", "", nil)
+	if gates != "" || len(opts) != 0 {
+		t.Errorf("numaKubeletConfig(v1.15.2) = %q, %v, want no change", gates, opts)
+	}
+
+	custom := pkgutil.ExtraOptionSlice{{Component: "kubelet", Key: "system-reserved", Value: "cpu=1,memory=1Gi"}}
+	gates, opts = numaKubeletConfig(2, "v1.16.0", "IPv6DualStack=true", custom)
+	if gates != "IPv6DualStack=true,TopologyManager=true" {
+		t.Errorf("gates = %q, want the TopologyManager gate added", gates)
+	}
+	for key, want := range map[string]string{
+		"cpu-manager-policy":      "static",
+		"system-reserved":         "cpu=1,memory=1Gi",
+		"topology-manager-policy": "best-effort",
+	} {
+		if got := opts.Get(key, "kubelet"); got != want {
+			t.Errorf("kubelet.%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func Test_checkStaticIP(t *testing.T) {
+	var tests = []struct {
+		ip      string
+		wantErr bool
+	}{
+		{"192.168.39.10", false},
+		{"192.168.39.2", false},
+		{"192.168.39.254", false},
+		{"192.168.39.0", true},
+		{"192.168.39.1", true},
+		{"192.168.39.255", true},
+		{"192.168.40.10", true},
+		{"fd00:39::10", true},
+		{"minikube", true},
+	}
+	for _, tc := range tests {
+		if err := checkStaticIP(tc.ip, kvmPrivateNetworkCIDR); (err != nil) != tc.wantErr {
+			t.Errorf("checkStaticIP(%q) = %v, want error: %v", tc.ip, err, tc.wantErr)
+		}
+	}
+}
+
+func Test_profileFlags(t *testing.T) {
+	cc := cfg.Config{
+		MachineConfig: cfg.MachineConfig{
+			Memory:           4096,
+			VMDriver:         "kvm2",
+			ContainerRuntime: "containerd",
+			DockerEnv:        []string{"A=1", "B=2"},
+			MountString:      "/src:/src",
+			MountType:        "sshfs",
+		},
+		KubernetesConfig: cfg.KubernetesConfig{
+			KubernetesVersion: "v1.15.2",
+			APIServerIPs:      []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")},
+		},
+	}
+	flags, lists := profileFlags(cc)
+
+	want := map[string]string{
+		memory:            "4096",
+		vmDriver:          "kvm2",
+		containerRuntime:  "containerd",
+		kubernetesVersion: "v1.15.2",
+		createMount:       "true",
+		mountString:       "/src:/src",
+		mountFSType:       "sshfs",
+		keepContext:       "false",
+	}
+	for name, value := range want {
+		if flags[name] != value {
+			t.Errorf("flag %s = %q, want %q", name, flags[name], value)
+		}
+	}
+	if _, ok := flags[cpus]; ok {
+		t.Errorf("flag %s is set from a zero value", cpus)
+	}
+	wantLists := map[string][]string{
+		"docker-env":    {"A=1", "B=2"},
+		"apiserver-ips": {"10.0.0.1,10.0.0.2"},
+	}
+	if !reflect.DeepEqual(lists, wantLists) {
+		t.Errorf("lists = %v, want %v", lists, wantLists)
+	}
+
+	for name := range flags {
+		if startCmd.Flags().Lookup(name) == nil {
+			t.Errorf("%s is not a start flag", name)
+		}
+	}
+	for name := range lists {
+		if startCmd.Flags().Lookup(name) == nil {
+			t.Errorf("%s is not a start flag", name)
+		}
+	}
+}
+
+func Test_oidcOptions(t *testing.T) {
+	defer viper.Reset()
+	existing := pkgutil.ExtraOptionSlice{
+		{Component: "apiserver", Key: "oidc-username-claim", Value: "sub"},
+		{Component: "kubelet", Key: "oidc-client-id", Value: "unrelated"},
+	}
+
+	viper.Set(oidcIssuerURL, "https://accounts.example.com")
+	viper.Set(oidcClientID, "kubernetes")
+	viper.Set(oidcUsernameClaim, "email")
+	viper.Set(oidcCAFile, "/home/user/ca.pem")
+	want := append(existing,
+		pkgutil.ExtraOption{Component: "apiserver", Key: "oidc-issuer-url", Value: "https://accounts.example.com"},
+		pkgutil.ExtraOption{Component: "apiserver", Key: "oidc-client-id", Value: "kubernetes"},
+		pkgutil.ExtraOption{Component: "apiserver", Key: "oidc-ca-file", Value: "/var/lib/minikube/certs/oidc-ca.crt"},
+	)
+	if got := oidcOptions(existing, "192.168.39.2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("oidcOptions() = %v, want %v", got, want)
+	}
+	if len(existing) != 2 {
+		t.Errorf("oidcOptions() modified the existing options: %v", existing)
+	}
+
+	viper.Reset()
+	viper.Set(oidcDex, true)
+	viper.Set(oidcGroupsClaim, "roles")
+	want = pkgutil.ExtraOptionSlice{
+		{Component: "apiserver", Key: "oidc-issuer-url", Value: "https://192.168.39.2:32000/dex"},
+		{Component: "apiserver", Key: "oidc-client-id", Value: "minikube"},
+		{Component: "apiserver", Key: "oidc-username-claim", Value: "email"},
+		{Component: "apiserver", Key: "oidc-groups-claim", Value: "roles"},
+		{Component: "apiserver", Key: "oidc-ca-file", Value: "/var/lib/minikube/certs/ca.crt"},
+	}
+	if got := oidcOptions(nil, "192.168.39.2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("oidcOptions() with dex = %v, want %v", got, want)
+	}
+}
+
+func Test_checkAuditPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var tests = []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"policy", "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Metadata\n", false},
+		{"not a policy", "apiVersion: v1\nkind: ConfigMap\n", true},
+		{"not yaml", "rules: [", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := filepath.Join(dir, "policy.yaml")
+			if err := ioutil.WriteFile(f, []byte(tc.content), 0644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if err := checkAuditPolicy(f); (err != nil) != tc.wantErr {
+				t.Errorf("checkAuditPolicy() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+	if err := checkAuditPolicy(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing policy")
+	}
+}
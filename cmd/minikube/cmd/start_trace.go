@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strconv"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/checkpoint"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/trace"
+)
+
+// initTrace enables the tracing of the start with --trace or --profile-output. The trace is also exported if the start fails.
+func initTrace() {
+	if err := trace.Initialize(viper.GetString(startTrace), viper.GetString(traceEndpoint), viper.GetString(profileOutput)); err != nil {
+		exit.UsageT("Invalid --trace: {{.error}}", out.V{"error": err})
+	}
+	exit.AtExit(func(code int, msg string) {
+		trace.Fail(msg)
+		flushTrace()
+	})
+}
+
+// flushTrace exports the trace of the start, if tracing is enabled
+func flushTrace() {
+	if err := trace.Flush(); err != nil {
+		out.WarningT("Unable to export the trace of the start: {{.error}}", out.V{"error": err})
+	}
+}
+
+// traceConfig records the settings which most affect the duration of a start on its span
+func traceConfig(span *trace.Span, config cfg.Config) {
+	span.SetAttribute("minikube.profile", viper.GetString(cfg.MachineProfile))
+	span.SetAttribute("minikube.driver", config.MachineConfig.VMDriver)
+	span.SetAttribute("minikube.container_runtime", config.MachineConfig.ContainerRuntime)
+	span.SetAttribute("minikube.kubernetes_version", config.KubernetesConfig.KubernetesVersion)
+	span.SetAttribute("minikube.cpus", strconv.Itoa(config.MachineConfig.CPUs))
+	span.SetAttribute("minikube.memory_mb", strconv.Itoa(config.MachineConfig.Memory))
+}
+
+// startPhase starts the span of a phase of the start, which is skipped if it completed in the start it continues
+func startPhase(cp *checkpoint.Checkpoint, p checkpoint.Phase) *trace.Span {
+	span := trace.StartSpan(string(p))
+	if cp.Completed(p) {
+		span.SetAttribute("minikube.skipped", "true")
+	}
+	return span
+}
@@ -17,38 +17,76 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"text/template"
+	"time"
 
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/service"
 	pkgutil "k8s.io/minikube/pkg/util"
 )
 
-var statusFormat string
+var (
+	statusFormat   string
+	statusWatch    bool
+	statusInterval time.Duration
+)
 
 // Status represents the status
 type Status struct {
-	Host       string
-	Kubelet    string
-	APIServer  string
-	Kubeconfig string
+	Host       string `json:"host"`
+	Kubelet    string `json:"kubelet"`
+	APIServer  string `json:"apiServer"`
+	Kubeconfig string `json:"kubeconfig"`
+	// Nodes are the health of the kubelet of each node, as reported to the API server
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+	// Addons are the readiness of the enabled addons
+	Addons []AddonStatus `json:"addons,omitempty"`
+	// Ready is true when the exit status is 0: everything above is running and ready
+	Ready bool `json:"ready"`
+}
+
+// NodeStatus is the health of the kubelet of a node, from the Ready condition of the node
+type NodeStatus struct {
+	Name    string `json:"name"`
+	Kubelet string `json:"kubelet"`
+	Message string `json:"message,omitempty"`
+}
+
+// AddonStatus is the readiness of an enabled addon, from the workloads and pods it runs
+type AddonStatus struct {
+	Name    string `json:"name"`
+	Ready   bool   `json:"ready"`
+	Message string `json:"message,omitempty"`
 }
 
 const (
 	minikubeNotRunningStatusFlag = 1 << 0
 	clusterNotRunningStatusFlag  = 1 << 1
 	k8sNotRunningStatusFlag      = 1 << 2
+	notReadyStatusFlag           = 1 << 3
 )
 
 // statusCmd represents the status command
@@ -57,91 +95,318 @@ var statusCmd = &cobra.Command{
 	Short: "Gets the status of a local kubernetes cluster",
 	Long: `Gets the status of a local kubernetes cluster.
 	Exit status contains the status of minikube's VM, cluster and kubernetes encoded on it's bits in this order from right to left.
-	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)`,
+	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)
+	8 is added when the VM, cluster and kubernetes are running, but a node or an enabled addon is not ready yet,
+	so that 0 means that the cluster is ready to use.
+
+	With --watch, the status is written again whenever it changes, until Ctrl-C.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var returnCode = 0
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			exit.WithCodeT(exit.Unavailable, "Error getting client: {{.error}}", out.V{"error": err})
 		}
 		defer api.Close()
 
-		hostSt, err := cluster.GetHostStatus(api)
+		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
-			exit.WithError("Error getting host status", err)
+			exit.WithError("Error creating status template", err)
+		}
+		if statusWatch {
+			watchStatus(api, tmpl)
+			return
 		}
 
-		kubeletSt := state.None.String()
-		kubeconfigSt := state.None.String()
-		apiserverSt := state.None.String()
+		status, returnCode, err := getStatus(api)
+		if err != nil {
+			exit.WithError("Error getting status", err)
+		}
+		if err := writeStatus(status, tmpl); err != nil {
+			exit.WithError("Error writing status", err)
+		}
+		if status.Host == state.Running.String() {
+			warnStatusCerts(api)
+		}
+		os.Exit(returnCode)
+	},
+}
 
-		if hostSt == state.Running.String() {
-			clusterBootstrapper, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
-			if err != nil {
-				exit.WithError("Error getting bootstrapper", err)
-			}
-			kubeletSt, err = clusterBootstrapper.GetKubeletStatus()
-			if err != nil {
-				glog.Warningf("kubelet err: %v", err)
-				returnCode |= clusterNotRunningStatusFlag
-			} else if kubeletSt != state.Running.String() {
-				returnCode |= clusterNotRunningStatusFlag
-			}
+// getStatus returns the status of the cluster, and the exit status which encodes it
+func getStatus(api libmachine.API) (Status, int, error) {
+	var returnCode = 0
+	hostSt, err := cluster.GetHostStatus(api)
+	if err != nil {
+		return Status{}, 0, errors.Wrap(err, "host status")
+	}
 
-			ip, err := cluster.GetHostDriverIP(api, config.GetMachineName())
-			if err != nil {
-				glog.Errorln("Error host driver ip status:", err)
-			}
+	kubeletSt := state.None.String()
+	kubeconfigSt := state.None.String()
+	apiserverSt := state.None.String()
+	var nodes []NodeStatus
+	var addons []AddonStatus
 
-			apiserverPort, err := pkgutil.GetPortFromKubeConfig(util.GetKubeConfigPath(), config.GetMachineName())
-			if err != nil {
-				// Fallback to presuming default apiserver port
-				apiserverPort = pkgutil.APIServerPort
-			}
+	if hostSt == state.Running.String() {
+		clusterBootstrapper, err := getClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			return Status{}, 0, errors.Wrap(err, "bootstrapper")
+		}
+		kubeletSt, err = clusterBootstrapper.GetKubeletStatus()
+		if err != nil {
+			glog.Warningf("kubelet err: %v", err)
+			returnCode |= clusterNotRunningStatusFlag
+		} else if kubeletSt != state.Running.String() {
+			returnCode |= clusterNotRunningStatusFlag
+		}
+
+		ip, err := cluster.GetHostDriverIP(api, config.GetMachineName())
+		if err != nil {
+			glog.Errorln("Error host driver ip status:", err)
+		}
+
+		apiserverPort, err := pkgutil.GetPortFromKubeConfig(util.GetProfileKubeConfigPath(config.GetMachineName()), config.GetMachineName())
+		if err != nil {
+			// Fallback to presuming default apiserver port
+			apiserverPort = pkgutil.APIServerPort
+		}
+
+		apiserverSt, err = clusterBootstrapper.GetAPIServerStatus(ip, apiserverPort)
+		if err != nil {
+			glog.Errorln("Error apiserver status:", err)
+		} else if apiserverSt != state.Running.String() {
+			returnCode |= clusterNotRunningStatusFlag
+		}
 
-			apiserverSt, err = clusterBootstrapper.GetAPIServerStatus(ip, apiserverPort)
-			if err != nil {
-				glog.Errorln("Error apiserver status:", err)
-			} else if apiserverSt != state.Running.String() {
-				returnCode |= clusterNotRunningStatusFlag
+		ks, err := pkgutil.GetKubeConfigStatus(ip, util.GetProfileKubeConfigPath(config.GetMachineName()), config.GetMachineName())
+		if err != nil {
+			glog.Errorln("Error kubeconfig status:", err)
+		}
+		if ks {
+			kubeconfigSt = "Correctly Configured: pointing to minikube-vm at " + ip.String()
+		} else {
+			kubeconfigSt = "Misconfigured: pointing to stale minikube-vm." +
+				"\nTo fix the kubectl context, run minikube update-context"
+			returnCode |= k8sNotRunningStatusFlag
+		}
+
+		if returnCode == 0 {
+			nodes, addons = readiness()
+			if !allReady(nodes, addons) {
+				returnCode |= notReadyStatusFlag
 			}
+		}
+	} else {
+		returnCode |= minikubeNotRunningStatusFlag
+	}
+
+	status := Status{
+		Host:       hostSt,
+		Kubelet:    kubeletSt,
+		APIServer:  apiserverSt,
+		Kubeconfig: kubeconfigSt,
+		Nodes:      nodes,
+		Addons:     addons,
+		Ready:      returnCode == 0,
+	}
+	return status, returnCode, nil
+}
+
+// warnStatusCerts warns about the certificates of the running VM which expire soon
+func warnStatusCerts(api libmachine.API) {
+	h, err := cluster.CheckIfHostExistsAndLoad(api, config.GetMachineName())
+	if err != nil {
+		glog.Warningf("Unable to load host: %v", err)
+		return
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		glog.Warningf("Unable to get command runner: %v", err)
+		return
+	}
+	warnExpiringCerts(runner)
+}
+
+// readiness returns the health of the nodes and the readiness of the enabled addons, from the API server
+func readiness() ([]NodeStatus, []AddonStatus) {
+	client, err := service.K8s.GetClientset(constants.DefaultK8sClientTimeout)
+	if err != nil {
+		glog.Warningf("kubernetes client: %v", err)
+		return []NodeStatus{{Name: config.GetMachineName(), Kubelet: "Unknown", Message: err.Error()}}, nil
+	}
+	nodes, err := nodeStatuses(client)
+	if err != nil {
+		glog.Warningf("node status: %v", err)
+		nodes = []NodeStatus{{Name: config.GetMachineName(), Kubelet: "Unknown", Message: err.Error()}}
+	}
+
+	names := []string{}
+	for name, a := range assets.Addons {
+		if enabled, err := a.IsEnabled(); err == nil && enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var addons []AddonStatus
+	for _, name := range names {
+		s, err := addonStatus(client, name, assets.Addons[name].Selector())
+		if err != nil {
+			glog.Warningf("addon %s status: %v", name, err)
+			s = AddonStatus{Name: name, Message: err.Error()}
+		}
+		addons = append(addons, s)
+	}
+	return nodes, addons
+}
 
-			ks, err := pkgutil.GetKubeConfigStatus(ip, util.GetKubeConfigPath(), config.GetMachineName())
-			if err != nil {
-				glog.Errorln("Error kubeconfig status:", err)
+// allReady returns whether all the nodes have a ready kubelet, and all the addons are ready
+func allReady(nodes []NodeStatus, addons []AddonStatus) bool {
+	for _, n := range nodes {
+		if n.Kubelet != "Ready" {
+			return false
+		}
+	}
+	for _, a := range addons {
+		if !a.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// nodeStatuses returns the health of the kubelet of each node
+func nodeStatuses(client kubernetes.Interface) ([]NodeStatus, error) {
+	list, err := client.CoreV1().Nodes().List(meta.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var nodes []NodeStatus
+	for _, n := range list.Items {
+		s := NodeStatus{Name: n.Name, Kubelet: "Unknown"}
+		for _, c := range n.Status.Conditions {
+			if c.Type != core.NodeReady {
+				continue
 			}
-			if ks {
-				kubeconfigSt = "Correctly Configured: pointing to minikube-vm at " + ip.String()
+			if c.Status == core.ConditionTrue {
+				s.Kubelet = "Ready"
 			} else {
-				kubeconfigSt = "Misconfigured: pointing to stale minikube-vm." +
-					"\nTo fix the kubectl context, run minikube update-context"
-				returnCode |= k8sNotRunningStatusFlag
+				s.Kubelet = "NotReady"
+				s.Message = c.Message
 			}
-		} else {
-			returnCode |= minikubeNotRunningStatusFlag
 		}
+		nodes = append(nodes, s)
+	}
+	return nodes, nil
+}
+
+// addonStatus returns the readiness of an addon: all the deployments, daemonsets, statefulsets, replication
+// controllers and standalone pods matching selector are ready. Addons which run none are ready once enabled.
+func addonStatus(client kubernetes.Interface, name string, selector string) (AddonStatus, error) {
+	opts := meta.ListOptions{LabelSelector: selector}
+	var notReady []string
+	total := 0
+	check := func(kind string, name string, ready bool) {
+		total++
+		if !ready {
+			notReady = append(notReady, kind+"/"+name)
+		}
+	}
 
-		status := Status{
-			Host:       hostSt,
-			Kubelet:    kubeletSt,
-			APIServer:  apiserverSt,
-			Kubeconfig: kubeconfigSt,
+	deployments, err := client.AppsV1().Deployments("").List(opts)
+	if err != nil {
+		return AddonStatus{}, err
+	}
+	for _, d := range deployments.Items {
+		check("deployment", d.Name, d.Spec.Replicas == nil || d.Status.AvailableReplicas >= *d.Spec.Replicas)
+	}
+	daemonsets, err := client.AppsV1().DaemonSets("").List(opts)
+	if err != nil {
+		return AddonStatus{}, err
+	}
+	for _, d := range daemonsets.Items {
+		check("daemonset", d.Name, d.Status.NumberReady >= d.Status.DesiredNumberScheduled)
+	}
+	statefulsets, err := client.AppsV1().StatefulSets("").List(opts)
+	if err != nil {
+		return AddonStatus{}, err
+	}
+	for _, s := range statefulsets.Items {
+		check("statefulset", s.Name, s.Spec.Replicas == nil || s.Status.ReadyReplicas >= *s.Spec.Replicas)
+	}
+	rcs, err := client.CoreV1().ReplicationControllers("").List(opts)
+	if err != nil {
+		return AddonStatus{}, err
+	}
+	for _, rc := range rcs.Items {
+		check("replicationcontroller", rc.Name, rc.Spec.Replicas == nil || rc.Status.ReadyReplicas >= *rc.Spec.Replicas)
+	}
+	pods, err := client.CoreV1().Pods("").List(opts)
+	if err != nil {
+		return AddonStatus{}, err
+	}
+	for _, p := range pods.Items {
+		// The pods of the workloads above are covered by their readiness
+		if len(p.OwnerReferences) > 0 {
+			continue
 		}
-		tmpl, err := template.New("status").Parse(statusFormat)
+		check("pod", p.Name, podReady(p))
+	}
+
+	s := AddonStatus{Name: name, Ready: len(notReady) == 0}
+	if len(notReady) > 0 {
+		s.Message = fmt.Sprintf("%d/%d ready, waiting for %s", total-len(notReady), total, strings.Join(notReady, ", "))
+	}
+	return s, nil
+}
+
+// podReady returns whether a pod has completed, or is running with all its containers ready
+func podReady(p core.Pod) bool {
+	if p.Status.Phase == core.PodSucceeded {
+		return true
+	}
+	for _, c := range p.Status.Conditions {
+		if c.Type == core.PodReady {
+			return c.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// writeStatus writes the status as JSON, or with the template
+func writeStatus(status Status, tmpl *template.Template) error {
+	if out.IsJSON() {
+		return out.JSON(status)
+	}
+	return tmpl.Execute(os.Stdout, status)
+}
+
+// watchStatus writes the status whenever it changes, checking it every --interval
+func watchStatus(api libmachine.API, tmpl *template.Template) {
+	var last []byte
+	for {
+		status, _, err := getStatus(api)
 		if err != nil {
-			exit.WithError("Error creating status template", err)
+			glog.Warningf("status: %v", err)
+			status = Status{Host: "Error: " + err.Error()}
 		}
-		err = tmpl.Execute(os.Stdout, status)
+		b, err := json.Marshal(status)
 		if err != nil {
-			exit.WithError("Error executing status template", err)
+			exit.WithError("Error writing status", err)
 		}
-
-		os.Exit(returnCode)
-	},
+		if !bytes.Equal(b, last) {
+			if last != nil && !out.IsJSON() {
+				out.T(out.Empty, "")
+			}
+			if err := writeStatus(status, tmpl); err != nil {
+				exit.WithError("Error writing status", err)
+			}
+			last = b
+		}
+		time.Sleep(statusInterval)
+	}
 }
 
 func init() {
 	statusCmd.Flags().StringVar(&statusFormat, "format", constants.DefaultStatusFormat,
 		`Go template format string for the status output.  The format for Go templates can be found here: https://golang.org/pkg/text/template/
 For the list accessible variables for the template, see the struct values here: https://godoc.org/k8s.io/minikube/cmd/minikube/cmd#Status`)
+	statusCmd.Flags().BoolVarP(&statusWatch, "watch", "w", false, "Keep watching the status, and write it again whenever it changes")
+	statusCmd.Flags().DurationVar(&statusInterval, "interval", 2*time.Second, "How often the status is checked with --watch")
 }
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNodeStatuses(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&core.Node{
+			ObjectMeta: meta.ObjectMeta{Name: "a"},
+			Status:     core.NodeStatus{Conditions: []core.NodeCondition{{Type: core.NodeReady, Status: core.ConditionTrue}}},
+		},
+		&core.Node{
+			ObjectMeta: meta.ObjectMeta{Name: "b"},
+			Status:     core.NodeStatus{Conditions: []core.NodeCondition{{Type: core.NodeReady, Status: core.ConditionFalse, Message: "PLEG is not healthy"}}},
+		},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "c"}},
+	)
+	got, err := nodeStatuses(client)
+	if err != nil {
+		t.Fatalf("nodeStatuses: %v", err)
+	}
+	want := []NodeStatus{
+		{Name: "a", Kubelet: "Ready"},
+		{Name: "b", Kubelet: "NotReady", Message: "PLEG is not healthy"},
+		{Name: "c", Kubelet: "Unknown"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeStatuses() = %+v, want %+v", got, want)
+	}
+	if allReady(got, nil) || !allReady(got[:1], []AddonStatus{{Name: "x", Ready: true}}) {
+		t.Errorf("allReady does not follow the kubelets")
+	}
+}
+
+func TestAddonStatus(t *testing.T) {
+	two := int32(2)
+	labels := map[string]string{"kubernetes.io/minikube-addons": "registry"}
+	ready := core.PodStatus{Conditions: []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}}}
+	client := fake.NewSimpleClientset(
+		&apps.Deployment{
+			ObjectMeta: meta.ObjectMeta{Name: "registry", Namespace: "kube-system", Labels: labels},
+			Spec:       apps.DeploymentSpec{Replicas: &two},
+			Status:     apps.DeploymentStatus{AvailableReplicas: 1},
+		},
+		&apps.DaemonSet{
+			ObjectMeta: meta.ObjectMeta{Name: "registry-proxy", Namespace: "kube-system", Labels: labels},
+			Status:     apps.DaemonSetStatus{DesiredNumberScheduled: 1, NumberReady: 1},
+		},
+		&core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: "registry-abc", Namespace: "kube-system", Labels: labels,
+				OwnerReferences: []meta.OwnerReference{{Kind: "ReplicaSet", Name: "registry-1"}}},
+		},
+		&core.Pod{
+			ObjectMeta: meta.ObjectMeta{Name: "storage-provisioner", Namespace: "kube-system",
+				Labels: map[string]string{"integration-test": "storage-provisioner"}},
+			Status: ready,
+		},
+	)
+	var tests = []struct {
+		name     string
+		selector string
+		want     AddonStatus
+	}{
+		{"registry", "kubernetes.io/minikube-addons=registry", AddonStatus{Name: "registry", Message: "1/2 ready, waiting for deployment/registry"}},
+		{"storage-provisioner", "integration-test=storage-provisioner", AddonStatus{Name: "storage-provisioner", Ready: true}},
+		{"default-storageclass", "kubernetes.io/minikube-addons=default-storageclass", AddonStatus{Name: "default-storageclass", Ready: true}},
+	}
+	for _, tc := range tests {
+		got, err := addonStatus(client, tc.name, tc.selector)
+		if err != nil {
+			t.Fatalf("addonStatus(%s): %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("addonStatus(%s) = %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
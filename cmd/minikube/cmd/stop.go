@@ -23,10 +23,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/autostop"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	pkg_config "k8s.io/minikube/pkg/minikube/config"
-	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -45,6 +46,8 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 // runStop handles the executes the flow of "minikube stop"
 func runStop(cmd *cobra.Command, args []string) {
 	profile := viper.GetString(pkg_config.MachineProfile)
+	unlock := cmdcfg.LockProfile("stop")
+	defer unlock()
 	api, err := machine.NewAPIClient()
 	if err != nil {
 		exit.WithError("Error getting client", err)
@@ -75,8 +78,12 @@ func runStop(cmd *cobra.Command, args []string) {
 		out.T(out.WarningType, "Unable to kill mount process: {{.error}}", out.V{"error": err})
 	}
 
+	if err := autostop.Kill(profile); err != nil {
+		out.T(out.WarningType, "Unable to kill auto-stop process: {{.error}}", out.V{"error": err})
+	}
+
 	machineName := pkg_config.GetMachineName()
-	err = pkgutil.UnsetCurrentContext(constants.KubeconfigPath, machineName)
+	err = pkgutil.UnsetCurrentContext(cmdUtil.GetProfileKubeConfigPath(machineName), machineName)
 	if err != nil {
 		exit.WithError("update config", err)
 	}
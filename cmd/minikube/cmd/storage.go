@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpv"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+var (
+	storageNode  string
+	storagePath  string
+	storageSize  string
+	storageClass string
+	storageName  string
+)
+
+// storageCmd represents the storage command
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "Manage the storage of the nodes of the cluster",
+	Long:  `Manage the storage of the nodes of the cluster.`,
+}
+
+// storageCreatePVCmd represents the storage create-pv command
+var storageCreatePVCmd = &cobra.Command{
+	Use:   "create-pv --path PATH --size SIZE",
+	Short: "Creates a local persistent volume, backed by a directory of a node",
+	Long: `Creates a local persistent volume, backed by a directory of a node.
+
+The directory is created on the node, writable by all users, and a PersistentVolume of type local is created for it,
+which the pods using it are scheduled with. Claims of its storage class are bound once a pod uses them, and the data
+of the directory is kept when the claim is deleted. The storage class is created if it does not exist.`,
+	Example: `minikube storage create-pv --path /data/postgres --size 10Gi`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 0 || storagePath == "" || storageSize == "" {
+			exit.UsageT("usage: minikube storage create-pv [--node NODE] --path PATH --size SIZE")
+		}
+		var nodeArgs []string
+		if storageNode != "" {
+			nodeArgs = []string{storageNode}
+		}
+		client, node := runningNode(nodeArgs)
+		name := storageName
+		if name == "" {
+			name = localpv.Name(node, storagePath)
+		}
+		pv, err := localpv.Volume(name, node, storagePath, storageSize, storageClass)
+		if err != nil {
+			exit.UsageT("Invalid volume: {{.error}}", out.V{"error": err})
+		}
+		if !localpv.Persistent(storagePath) {
+			out.WarningT("{{.path}} is lost when the VM reboots: use a directory under /data to keep the data of the volume", out.V{"path": storagePath})
+		}
+
+		runner, _ := profileRunner()
+		if err := runner.Run(localpv.PrepareCmd(pv.Spec.Local.Path)); err != nil {
+			exit.WithError("Failed to create the directory of the volume", err)
+		}
+		if err := localpv.EnsureStorageClass(client, storageClass); err != nil {
+			exit.WithError("Failed to create the storage class", err)
+		}
+		if _, err := client.CoreV1().PersistentVolumes().Create(pv); err != nil {
+			exit.WithError("Failed to create the persistent volume", err)
+		}
+		out.T(out.Ready, "Created volume {{.name}} of {{.size}}, in {{.node}}:{{.path}}, with storage class {{.class}}", out.V{"name": name, "size": storageSize, "node": node, "path": pv.Spec.Local.Path, "class": storageClass})
+	},
+}
+
+func init() {
+	storageCreatePVCmd.Flags().StringVarP(&storageNode, "node", "n", "", "The node of the directory. Defaults to the primary node")
+	storageCreatePVCmd.Flags().StringVar(&storagePath, "path", "", "The absolute path of the directory on the node, such as /data/x")
+	storageCreatePVCmd.Flags().StringVar(&storageSize, "size", "", "The capacity of the volume, such as 10Gi")
+	storageCreatePVCmd.Flags().StringVar(&storageClass, "storage-class", localpv.DefaultStorageClass, "The storage class of the volume, which claims request to use it")
+	storageCreatePVCmd.Flags().StringVar(&storageName, "name", "", "The name of the volume. Defaults to a name of the node and the path, such as local-minikube-data-x")
+	storageCmd.AddCommand(storageCreatePVCmd)
+}
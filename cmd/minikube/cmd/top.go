@@ -0,0 +1,129 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/top"
+)
+
+var topInterval time.Duration
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Shows the CPU, memory, disk and network used by the minikube VM on the host.",
+	Long: `Shows the CPU, memory, disk and network used by the minikube VM on the host.
+
+Unlike 'kubectl top', which needs the metrics-server addon and reports what pods use inside the VM, the usage is measured
+from the host: the CPU and resident memory of the hypervisor process, and the space that the disk images take on the host disk.
+The network is the traffic of the interfaces of the VM. CPU is in percent of one host CPU, as with top.
+Supported by the kvm2, qemu2, hyperkit, vfkit and virtualbox drivers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := config.GetMachineName()
+		cc, err := config.Load()
+		if err != nil {
+			exit.WithError("Error loading profile config", err)
+		}
+		if cc.MachineConfig.VMDriver == constants.DriverNone {
+			exit.WithCodeT(exit.Config, "'minikube top' is not supported by the none driver: use top on the host instead")
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			exit.WithError("Error getting client", err)
+		}
+		defer api.Close()
+		h, err := cluster.CheckIfHostExistsAndLoad(api, name)
+		if err != nil {
+			exit.WithError("Error getting host", err)
+		}
+		s, err := h.Driver.GetState()
+		if err != nil {
+			exit.WithError("Error getting host state", err)
+		}
+		if s != state.Running {
+			exit.WithCodeT(exit.Unavailable, "The VM is {{.state}}: run 'minikube start' first", out.V{"state": s})
+		}
+
+		machineDir := constants.MakeMiniPath("machines", name)
+		pid, err := top.HypervisorPid(cc.MachineConfig.VMDriver, name, machineDir)
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Unable to find the hypervisor process of {{.name}}: {{.error}}", out.V{"name": name, "error": err})
+		}
+		u := top.Usage{
+			Name:        name,
+			Driver:      cc.MachineConfig.VMDriver,
+			Pid:         pid,
+			CPUs:        cc.MachineConfig.CPUs,
+			MemoryLimit: uint64(cc.MachineConfig.Memory) * units.MiB,
+			DiskSize:    uint64(cc.MachineConfig.DiskSize) * units.MB,
+		}
+		if u.DiskUsed, err = top.DiskUsed(machineDir); err != nil {
+			exit.WithError("Failed to get the disk usage", err)
+		}
+		runner, err := machine.CommandRunner(h)
+		if err != nil {
+			exit.WithError("Failed to get command runner", err)
+		}
+		if err := top.Measure(&u, runner, topInterval); err != nil {
+			exit.WithError("Failed to measure the usage of the VM", err)
+		}
+
+		if out.IsJSON() {
+			if err := out.JSON([]top.Usage{u}); err != nil {
+				exit.WithError("Error writing usage", err)
+			}
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Driver", "PID", "CPU", "CPUs", "Memory", "Disk", "Net RX", "Net TX"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.Append([]string{
+			u.Name,
+			u.Driver,
+			strconv.Itoa(int(u.Pid)),
+			fmt.Sprintf("%.1f%%", u.CPUPercent),
+			strconv.Itoa(u.CPUs),
+			units.BytesSize(float64(u.MemoryUsed)) + " / " + units.BytesSize(float64(u.MemoryLimit)),
+			units.HumanSize(float64(u.DiskUsed)) + " / " + units.HumanSize(float64(u.DiskSize)),
+			units.HumanSize(u.RxRate) + "/s",
+			units.HumanSize(u.TxRate) + "/s",
+		})
+		table.Render()
+	},
+}
+
+func init() {
+	topCmd.Flags().DurationVar(&topInterval, "interval", time.Second, "How long the CPU and network usage are measured for")
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/x509"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/trust"
+)
+
+var (
+	trustSystem   bool
+	trustBrowsers bool
+)
+
+// trustCmd represents the trust command
+var trustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "Install the minikube CA into the trust stores of the host, so that browsers and tools accept the certificates of the cluster.",
+	Long: `Install the minikube CA into the trust stores of the host, so that browsers and tools accept the certificates of the cluster.
+
+The minikube CA signs the certificates of the apiserver, and of the registry and dex addons. The certificates of ingresses
+can be signed with it too, with the ca.crt and ca.key of the minikube directory. It is shared by all the profiles.
+The CA is added to the trust store of the system: the System keychain on macOS, the ca-certificates of the distribution on Linux,
+and the Trusted Root Certification Authorities on Windows. It is also added to the NSS databases of Firefox, and of Chrome on Linux,
+when the certutil command of NSS is installed.`,
+}
+
+// trustInstallCmd represents the trust install command
+var trustInstallCmd = &cobra.Command{
+	Use:     "install",
+	Short:   "Adds the minikube CA to the trust stores of the host",
+	Long:    "Adds the minikube CA to the trust stores of the host. Changing the store of the system may ask for the password of sudo, or need an administrator on Windows.",
+	Example: `minikube trust install --browsers=false`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runTrust(func(s trust.Store, cert *x509.Certificate, file string) error { return s.Install(cert, file) },
+			"Installed the minikube CA into {{.store}}")
+		if trustBrowsers {
+			out.T(out.Tip, "Restart the browsers for them to trust the minikube CA")
+		}
+	},
+}
+
+// trustUninstallCmd represents the trust uninstall command
+var trustUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Removes the minikube CA from the trust stores of the host",
+	Long:  "Removes the minikube CA from the trust stores of the host. Changing the store of the system may ask for the password of sudo, or need an administrator on Windows.",
+	Run: func(cmd *cobra.Command, args []string) {
+		runTrust(func(s trust.Store, cert *x509.Certificate, file string) error { return s.Uninstall(cert) },
+			"Removed the minikube CA from {{.store}}")
+	},
+}
+
+// runTrust applies change to the selected trust stores, and exits with an error if any of them failed
+func runTrust(change func(trust.Store, *x509.Certificate, string) error, done string) {
+	file := constants.MakeMiniPath("ca.crt")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		exit.WithCodeT(exit.NoInput, "The minikube CA {{.path}} does not exist, run 'minikube start' to create it", out.V{"path": file})
+	}
+	cert, err := trust.ReadCert(file)
+	if err != nil {
+		exit.WithError("Failed to read the minikube CA", err)
+	}
+
+	stores := []trust.Store{}
+	if trustSystem {
+		s, err := trust.SystemStore()
+		if err != nil {
+			exit.WithCodeT(exit.Unavailable, "Cannot change the trust store of the system: {{.error}}", out.V{"error": err})
+		}
+		stores = append(stores, s)
+	}
+	if trustBrowsers {
+		stores = append(stores, trust.BrowserStores()...)
+	}
+
+	failed := false
+	for _, s := range stores {
+		if err := change(s, cert, file); err != nil {
+			out.ErrT(out.FailureType, "Failed to change {{.store}}: {{.error}}", out.V{"store": s.Name(), "error": err})
+			failed = true
+			continue
+		}
+		out.T(out.Check, done, out.V{"store": s.Name()})
+	}
+	if failed {
+		os.Exit(exit.Failure)
+	}
+}
+
+func init() {
+	for _, c := range []*cobra.Command{trustInstallCmd, trustUninstallCmd} {
+		c.Flags().BoolVar(&trustSystem, "system", true, "Change the trust store of the system")
+		c.Flags().BoolVar(&trustBrowsers, "browsers", true, "Change the NSS databases of Firefox, and of Chrome on Linux")
+	}
+	trustCmd.AddCommand(trustInstallCmd)
+	trustCmd.AddCommand(trustUninstallCmd)
+}
@@ -19,7 +19,9 @@ package cmd
 import (
 	"context"
 	"os"
+	"os/exec"
 	"os/signal"
+	"runtime"
 	"time"
 
 	"github.com/golang/glog"
@@ -27,11 +29,15 @@ import (
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/service"
 	"k8s.io/minikube/pkg/minikube/tunnel"
 )
 
-var cleanup bool
+var (
+	cleanup          bool
+	tunnelBackground bool
+)
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
@@ -52,6 +58,11 @@ var tunnelCmd = &cobra.Command{
 			return
 		}
 
+		if tunnelBackground {
+			startBackgroundTunnel(manager)
+			return
+		}
+
 		glog.Infof("Creating docker machine client...")
 		api, err := machine.NewAPIClient()
 		if err != nil {
@@ -81,9 +92,39 @@ var tunnelCmd = &cobra.Command{
 			exit.WithError("error starting tunnel", err)
 		}
 		<-done
+		if err := tunnel.RemovePidFile(config.GetMachineName()); err != nil {
+			glog.Warningf("unable to remove tunnel pid file: %v", err)
+		}
 	},
 }
 
+// startBackgroundTunnel runs the tunnel in a detached process, so that it outlives the terminal
+func startBackgroundTunnel(manager *tunnel.Manager) {
+	machineName := config.GetMachineName()
+	running, err := manager.Running(machineName)
+	if err != nil {
+		exit.WithError("error checking for running tunnels", err)
+	}
+	if len(running) > 0 {
+		exit.WithCodeT(exit.Data, "A tunnel is already running for {{.name}} (pid {{.pid}}), stop it with: minikube tunnel stop", out.V{"name": machineName, "pid": running[0].Pid})
+	}
+
+	// A detached process has no terminal to ask for a sudo password on
+	if runtime.GOOS != "windows" {
+		if err := exec.Command("sudo", "-n", "true").Run(); err != nil {
+			out.WarningT("sudo asks for a password, which a background tunnel cannot prompt for. Adding routes will fail unless the route commands are allowed with NOPASSWD.")
+		}
+	}
+
+	pid, err := tunnel.StartBackground(machineName, []string{"tunnel", "--" + config.MachineProfile, machineName})
+	if err != nil {
+		exit.WithError("error starting background tunnel", err)
+	}
+	out.T(out.Running, "Tunnel running in the background (pid {{.pid}}), logs are in {{.path}}", out.V{"pid": pid, "path": tunnel.LogFilePath(machineName)})
+	out.T(out.Tip, "To stop it, run: minikube tunnel stop")
+}
+
 func init() {
 	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", false, "call with cleanup=true to remove old tunnels")
+	tunnelCmd.Flags().BoolVar(&tunnelBackground, "background", false, "Run the tunnel in a background process that keeps running after the terminal is closed")
 }
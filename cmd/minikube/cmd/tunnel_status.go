@@ -0,0 +1,56 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+// tunnelStatusCmd represents the tunnel status command
+var tunnelStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows whether a tunnel is running for the cluster",
+	Long:  `Shows the tunnels running for the cluster, whether in the background or in a terminal. Exits with a non-zero code if none is running.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		machineName := config.GetMachineName()
+		running, err := tunnel.NewManager().Running(machineName)
+		if err != nil {
+			exit.WithError("error checking for running tunnels", err)
+		}
+		if len(running) == 0 {
+			out.T(out.Stopped, "No tunnel is running for {{.name}}", out.V{"name": machineName})
+			os.Exit(exit.Unavailable)
+		}
+		for _, t := range running {
+			if t.Route == nil {
+				out.T(out.Running, "Tunnel (pid {{.pid}}) is waiting for the cluster", out.V{"pid": t.Pid})
+				continue
+			}
+			out.T(out.Running, "Tunnel (pid {{.pid}}) is routing {{.route}}", out.V{"pid": t.Pid, "route": t.Route})
+		}
+	},
+}
+
+func init() {
+	tunnelCmd.AddCommand(tunnelStatusCmd)
+}
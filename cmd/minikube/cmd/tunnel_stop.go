@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+// tunnelStopCmd represents the tunnel stop command
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stops the tunnels running for the cluster",
+	Long:  `Stops the tunnels running for the cluster, whether in the background or in a terminal, and removes their routes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := tunnel.NewManager().Stop(config.GetMachineName()); err != nil {
+			exit.WithError("error stopping tunnel", err)
+		}
+		out.T(out.Stopped, "Tunnel stopped.")
+	},
+}
+
+func init() {
+	tunnelCmd.AddCommand(tunnelStopCmd)
+}
@@ -18,9 +18,9 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	cmdutil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
-	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -44,7 +44,7 @@ var updateContextCmd = &cobra.Command{
 		if err != nil {
 			exit.WithError("Error host driver ip status", err)
 		}
-		updated, err := util.UpdateKubeconfigIP(ip, constants.KubeconfigPath, machineName)
+		updated, err := util.UpdateKubeconfigIP(ip, cmdutil.GetProfileKubeConfigPath(machineName), machineName)
 		if err != nil {
 			exit.WithError("update config", err)
 		}
@@ -28,6 +28,7 @@ import (
 	"github.com/golang/glog"
 	ps "github.com/mitchellh/go-ps"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -102,3 +103,12 @@ func GetKubeConfigPath() string {
 	}
 	return filepath.SplitList(kubeConfigEnv)[0]
 }
+
+// GetProfileKubeConfigPath gets the path to the kubeconfig holding the context of a profile:
+// its own file if it was started with --kubeconfig-per-profile, or else the first kubeconfig
+func GetProfileKubeConfigPath(profile string) string {
+	if p := config.ProfileKubeconfig(profile); p != "" {
+		return p
+	}
+	return GetKubeConfigPath()
+}
@@ -0,0 +1,20 @@
+################################################################################
+#
+# amdgpu-firmware
+#
+################################################################################
+
+# The firmware which the amdgpu kernel driver loads for the AMD GPUs passed through to the VM
+AMDGPU_FIRMWARE_VERSION = 20190815
+AMDGPU_FIRMWARE_SITE = https://git.kernel.org/pub/scm/linux/kernel/git/firmware/linux-firmware.git/snapshot
+AMDGPU_FIRMWARE_SOURCE = linux-firmware-$(AMDGPU_FIRMWARE_VERSION).tar.gz
+AMDGPU_FIRMWARE_LICENSE = Redistributable
+AMDGPU_FIRMWARE_LICENSE_FILES = LICENSE.amdgpu
+
+define AMDGPU_FIRMWARE_INSTALL_TARGET_CMDS
+	mkdir -p $(TARGET_DIR)/lib/firmware/amdgpu
+	$(INSTALL) -m 0644 $(@D)/amdgpu/*.bin $(TARGET_DIR)/lib/firmware/amdgpu/
+	$(INSTALL) -m 0644 $(@D)/LICENSE.amdgpu $(TARGET_DIR)/lib/firmware/amdgpu/
+endef
+
+$(eval $(generic-package))
@@ -0,0 +1,28 @@
+################################################################################
+#
+# buildkit-bin
+#
+################################################################################
+
+BUILDKIT_BIN_VERSION = v0.6.1
+BUILDKIT_BIN_SITE = https://github.com/moby/buildkit/releases/download/$(BUILDKIT_BIN_VERSION)
+BUILDKIT_BIN_SOURCE = buildkit-$(BUILDKIT_BIN_VERSION).linux-amd64.tar.gz
+BUILDKIT_BIN_STRIP_COMPONENTS = 0
+
+define BUILDKIT_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/bin/buildctl \
+		$(TARGET_DIR)/usr/bin/buildctl
+	$(INSTALL) -D -m 0755 \
+		$(@D)/bin/buildkitd \
+		$(TARGET_DIR)/usr/bin/buildkitd
+endef
+
+# buildkitd is not enabled: minikube starts it for the container runtime in use on the first build
+define BUILDKIT_BIN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -Dm644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/buildkit-bin/buildkit.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/buildkit.service
+endef
+
+$(eval $(generic-package))
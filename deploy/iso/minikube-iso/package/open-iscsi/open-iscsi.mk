@@ -0,0 +1,51 @@
+################################################################################
+#
+# open-iscsi
+#
+################################################################################
+
+# 2.0.874 is the last version which bundles open-isns
+OPEN_ISCSI_VERSION = 2.0.874
+OPEN_ISCSI_SITE = $(call github,open-iscsi,open-iscsi,$(OPEN_ISCSI_VERSION))
+OPEN_ISCSI_LICENSE = GPL-2.0+
+OPEN_ISCSI_LICENSE_FILES = COPYING
+
+# iscsiuio, for the offload of some network cards, is not built
+define OPEN_ISCSI_BUILD_CMDS
+	cd $(@D)/utils/open-isns && $(TARGET_CONFIGURE_OPTS) ./configure \
+		--host=$(GNU_TARGET_NAME) --with-security=no --without-slp
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/utils/open-isns
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/utils/sysdeps
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/utils/fwparam_ibft
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/usr
+	$(TARGET_MAKE_ENV) $(MAKE) $(TARGET_CONFIGURE_OPTS) -C $(@D)/utils
+endef
+
+define OPEN_ISCSI_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/usr/iscsid \
+		$(TARGET_DIR)/usr/sbin/iscsid
+	$(INSTALL) -D -m 0755 \
+		$(@D)/usr/iscsiadm \
+		$(TARGET_DIR)/usr/sbin/iscsiadm
+	$(INSTALL) -D -m 0755 \
+		$(@D)/usr/iscsistart \
+		$(TARGET_DIR)/usr/sbin/iscsistart
+	$(INSTALL) -D -m 0755 \
+		$(@D)/utils/iscsi-iname \
+		$(TARGET_DIR)/usr/sbin/iscsi-iname
+	$(INSTALL) -D -m 0644 \
+		$(@D)/etc/iscsid.conf \
+		$(TARGET_DIR)/etc/iscsi/iscsid.conf
+endef
+
+define OPEN_ISCSI_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -D -m 644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/open-iscsi/iscsid.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/iscsid.service
+
+	ln -fs /usr/lib/systemd/system/iscsid.service \
+		$(TARGET_DIR)/etc/systemd/system/multi-user.target.wants/iscsid.service
+endef
+
+$(eval $(generic-package))
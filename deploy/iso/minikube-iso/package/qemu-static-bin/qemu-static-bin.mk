@@ -0,0 +1,31 @@
+################################################################################
+#
+# qemu-static-bin
+#
+################################################################################
+
+QEMU_STATIC_BIN_VERSION = v4.1.0-1
+QEMU_STATIC_BIN_SITE = https://github.com/multiarch/qemu-user-static/releases/download/$(QEMU_STATIC_BIN_VERSION)
+QEMU_STATIC_BIN_SOURCE = x86_64_qemu-aarch64-static.tar.gz
+QEMU_STATIC_BIN_EXTRA_DOWNLOADS = x86_64_qemu-arm-static.tar.gz
+QEMU_STATIC_BIN_STRIP_COMPONENTS = 0
+
+define QEMU_STATIC_BIN_EXTRACT_EXTRA
+	$(TAR) -xzf $(QEMU_STATIC_BIN_DL_DIR)/$(QEMU_STATIC_BIN_EXTRA_DOWNLOADS) -C $(@D)
+endef
+
+QEMU_STATIC_BIN_POST_EXTRACT_HOOKS += QEMU_STATIC_BIN_EXTRACT_EXTRA
+
+define QEMU_STATIC_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/qemu-aarch64-static \
+		$(TARGET_DIR)/usr/bin/qemu-aarch64-static
+	$(INSTALL) -D -m 0755 \
+		$(@D)/qemu-arm-static \
+		$(TARGET_DIR)/usr/bin/qemu-arm-static
+	$(INSTALL) -D -m 0644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/qemu-static-bin/qemu-static.conf \
+		$(TARGET_DIR)/usr/lib/binfmt.d/qemu-static.conf
+endef
+
+$(eval $(generic-package))
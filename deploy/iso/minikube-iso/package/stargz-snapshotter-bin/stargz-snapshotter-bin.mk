@@ -0,0 +1,28 @@
+################################################################################
+#
+# stargz-snapshotter-bin
+#
+################################################################################
+
+STARGZ_SNAPSHOTTER_BIN_VERSION = v0.2.0
+STARGZ_SNAPSHOTTER_BIN_SITE = https://github.com/containerd/stargz-snapshotter/releases/download/$(STARGZ_SNAPSHOTTER_BIN_VERSION)
+STARGZ_SNAPSHOTTER_BIN_SOURCE = stargz-snapshotter-$(STARGZ_SNAPSHOTTER_BIN_VERSION)-linux-amd64.tar.gz
+STARGZ_SNAPSHOTTER_BIN_STRIP_COMPONENTS = 0
+
+define STARGZ_SNAPSHOTTER_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/containerd-stargz-grpc \
+		$(TARGET_DIR)/usr/bin/containerd-stargz-grpc
+	$(INSTALL) -D -m 0755 \
+		$(@D)/ctr-remote \
+		$(TARGET_DIR)/usr/bin/ctr-remote
+endef
+
+# stargz-snapshotter is not enabled: minikube starts it when containerd runs with --snapshotter=stargz
+define STARGZ_SNAPSHOTTER_BIN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -Dm644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/stargz-snapshotter-bin/stargz-snapshotter.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/stargz-snapshotter.service
+endef
+
+$(eval $(generic-package))
@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// MaxExtraDisks is how many extra disks a VM can have, as the guest names them /dev/vdb to /dev/vdu
+const MaxExtraDisks = 20
+
+// ExtraDiskPaths returns the paths of the images of the extra disks of the machine
+func ExtraDiskPaths(d *drivers.BaseDriver, count int) []string {
+	var paths []string
+	for i := 1; i <= count; i++ {
+		paths = append(paths, filepath.Join(d.ResolveStorePath("."), fmt.Sprintf("%s-%d.rawdisk", d.GetMachineName(), i)))
+	}
+	return paths
+}
+
+// ExtraDiskTarget returns the name of the virtio disk at index i of ExtraDiskPaths, after the boot disk vda
+func ExtraDiskTarget(i int) string {
+	return fmt.Sprintf("vd%c", 'b'+i)
+}
+
+// CreateExtraDisks creates the images of the extra disks of the machine which do not exist yet. They are sparse files
+// without a partition table or the format-me header of the boot disk, so the guest keeps them as raw block devices.
+func CreateExtraDisks(d *drivers.BaseDriver, count int, diskSizeMb int) error {
+	for _, path := range ExtraDiskPaths(d, count) {
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		glog.Infof("Creating extra disk image: %s...", path)
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return errors.Wrap(err, "open")
+		}
+		if err := file.Close(); err != nil {
+			return errors.Wrapf(err, "closing file %s", path)
+		}
+		if err := os.Truncate(path, int64(diskSizeMb)*1000000); err != nil {
+			return errors.Wrap(err, "truncate")
+		}
+	}
+	return nil
+}
@@ -20,8 +20,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/docker/machine/libmachine/drivers"
 	"k8s.io/minikube/pkg/minikube/tests"
 )
 
@@ -48,3 +50,90 @@ func Test_createDiskImage(t *testing.T) {
 		t.Errorf("Disk size is %v, want %v", fi.Size(), sizeInBytes)
 	}
 }
+
+func TestVirtiofsTag(t *testing.T) {
+	tag := VirtiofsTag("/Users/me/src")
+	if len(tag) > 36 {
+		t.Errorf("VirtiofsTag() = %q is longer than 36 bytes", tag)
+	}
+	if got := VirtiofsTag("/Users/me/src/"); got != tag {
+		t.Errorf("expected the same tag with a trailing slash, got %q and %q", got, tag)
+	}
+	if got := VirtiofsTag("/Users/me/other"); got == tag {
+		t.Errorf("expected different tags for different paths, got %q", got)
+	}
+}
+
+func TestParseUSBDevice(t *testing.T) {
+	var tests = []struct {
+		device  string
+		want    USBDevice
+		wantErr bool
+	}{
+		{"1050:0407", USBDevice{Vendor: "1050", Product: "0407"}, false},
+		{"046D:085B", USBDevice{Vendor: "046d", Product: "085b"}, false},
+		{"0x1050:0x0407", USBDevice{}, true},
+		{"1050", USBDevice{}, true},
+		{"1050:04070", USBDevice{}, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseUSBDevice(tc.device)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseUSBDevice(%q) = %v, want error: %v", tc.device, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("ParseUSBDevice(%q) = %+v, want %+v", tc.device, got, tc.want)
+		}
+	}
+}
+
+func TestNUMACells(t *testing.T) {
+	got := NUMACells(5, 4001, 2)
+	want := []NUMACell{{ID: 0, CPUs: "0-2", Memory: 2001}, {ID: 1, CPUs: "3-4", Memory: 2000}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NUMACells(5, 4001, 2) = %+v, want %+v", got, want)
+	}
+	got = NUMACells(2, 2048, 2)
+	want = []NUMACell{{ID: 0, CPUs: "0", Memory: 1024}, {ID: 1, CPUs: "1", Memory: 1024}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NUMACells(2, 2048, 2) = %+v, want %+v", got, want)
+	}
+}
+
+func TestCreateExtraDisks(t *testing.T) {
+	tmpdir := tests.MakeTempDir()
+	defer os.RemoveAll(tmpdir)
+
+	d := &drivers.BaseDriver{MachineName: "minikube", StorePath: tmpdir}
+	paths := ExtraDiskPaths(d, 2)
+	want := []string{
+		filepath.Join(tmpdir, "machines", "minikube", "minikube-1.rawdisk"),
+		filepath.Join(tmpdir, "machines", "minikube", "minikube-2.rawdisk"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("ExtraDiskPaths() = %v, want %v", paths, want)
+	}
+	if err := os.MkdirAll(filepath.Dir(paths[0]), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// An existing disk keeps its data when the VM is created again
+	if err := ioutil.WriteFile(paths[0], []byte("data"), 0644); err != nil {
+		t.Fatalf("writefile: %v", err)
+	}
+
+	if err := CreateExtraDisks(d, 2, 100); err != nil {
+		t.Fatalf("CreateExtraDisks() error = %v", err)
+	}
+	for path, size := range map[string]int64{paths[0]: 4, paths[1]: 100 * 1000000} {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if fi.Size() != size {
+			t.Errorf("%s size is %v, want %v", path, fi.Size(), size)
+		}
+	}
+	if got := ExtraDiskTarget(1); got != "vdc" {
+		t.Errorf("ExtraDiskTarget(1) = %q, want vdc", got)
+	}
+}
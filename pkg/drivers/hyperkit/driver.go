@@ -65,6 +65,7 @@ type Driver struct {
 	UUID           string
 	VpnKitSock     string
 	VSockPorts     []string
+	ExtraDisks     int
 }
 
 // NewDriver creates a new driver for a host
@@ -106,6 +107,9 @@ func (d *Driver) Create() error {
 	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
 		return errors.Wrap(err, "making disk image")
 	}
+	if err := pkgdrivers.CreateExtraDisks(d.BaseDriver, d.ExtraDisks, d.DiskSize); err != nil {
+		return errors.Wrap(err, "creating extra disks")
+	}
 
 	isoPath := d.ResolveStorePath(isoFilename)
 	if err := d.extractKernel(isoPath); err != nil {
@@ -240,7 +244,7 @@ func (d *Driver) Start() error {
 	}
 
 	// Need to strip 0's
-	mac = trimMacAddress(mac)
+	mac = pkgdrivers.TrimMacAddress(mac)
 	log.Debugf("Generated MAC %s", mac)
 	h.Disks = []hyperkit.DiskConfig{
 		{
@@ -249,6 +253,9 @@ func (d *Driver) Start() error {
 			Driver: "virtio-blk",
 		},
 	}
+	for _, path := range pkgdrivers.ExtraDiskPaths(d.BaseDriver, d.ExtraDisks) {
+		h.Disks = append(h.Disks, hyperkit.DiskConfig{Path: path, Size: d.DiskSize, Driver: "virtio-blk"})
+	}
 	log.Debugf("Starting with cmdline: %s", d.Cmdline)
 	if err := h.Start(d.Cmdline); err != nil {
 		return errors.Wrapf(err, "starting with cmd line: %s", d.Cmdline)
@@ -25,7 +25,6 @@ import (
 	"net"
 	"os"
 	"os/exec"
-	"regexp"
 	"strings"
 
 	"github.com/docker/machine/libmachine/log"
@@ -40,10 +39,6 @@ const (
 	SharedNetAddrKey = "Shared_Net_Address"
 )
 
-var (
-	leadingZeroRegexp = regexp.MustCompile(`0([A-Fa-f0-9](:|$))`)
-)
-
 // DHCPEntry holds a parsed DNS entry
 type DHCPEntry struct {
 	Name      string
@@ -122,11 +117,6 @@ func parseDHCPdLeasesFile(file io.Reader) ([]DHCPEntry, error) {
 	return dhcpEntries, scanner.Err()
 }
 
-// trimMacAddress trimming "0" of the ten's digit
-func trimMacAddress(rawUUID string) string {
-	return leadingZeroRegexp.ReplaceAllString(rawUUID, "$1")
-}
-
 // GetNetAddr gets the network address for vmnet
 func GetNetAddr() (net.IP, error) {
 	plistPath := VMNetDomain + ".plist"
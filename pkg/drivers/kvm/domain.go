@@ -25,8 +25,10 @@ import (
 	"net"
 	"text/template"
 
+	"github.com/docker/machine/libmachine/log"
 	libvirt "github.com/libvirt/libvirt-go"
 	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
 )
 
 const domainTmpl = `
@@ -34,6 +36,12 @@ const domainTmpl = `
   <name>{{.MachineName}}</name> 
   <memory unit='MB'>{{.Memory}}</memory>
   <vcpu>{{.CPU}}</vcpu>
+  {{if .VirtiofsShares}}
+  <memoryBacking>
+    <source type='memfd'/>
+    <access mode='shared'/>
+  </memoryBacking>
+  {{end}}
   <features>
     <acpi/>
     <apic/>
@@ -44,9 +52,21 @@ const domainTmpl = `
     </kvm>
     {{end}}
   </features>
-  <cpu mode='host-passthrough'/>
+  <cpu mode='host-passthrough'>
+    {{if gt .NUMANodes 1}}
+    <numa>
+      {{range numaCells .CPU .Memory .NUMANodes}}
+      <cell id='{{.ID}}' cpus='{{.CPUs}}' memory='{{.Memory}}' unit='MB'{{if $.VirtiofsShares}} memAccess='shared'{{end}}/>
+      {{end}}
+    </numa>
+    {{end}}
+  </cpu>
   <os>
+    {{if .SRIOVVFs}}
+    <type machine='q35'>hvm</type>
+    {{else}}
     <type>hvm</type>
+    {{end}}
     <boot dev='cdrom'/>
     <boot dev='hd'/>
     <bootmenu enable='no'/>
@@ -62,6 +82,13 @@ const domainTmpl = `
       <source file='{{.DiskPath}}'/>
       <target dev='hda' bus='virtio'/>
     </disk>
+    {{range $i, $path := extraDiskPaths .BaseDriver .ExtraDisks}}
+    <disk type='file' device='disk'>
+      <driver name='qemu' type='raw' cache='default' io='threads' />
+      <source file='{{$path}}'/>
+      <target dev='{{extraDiskTarget $i}}' bus='virtio'/>
+    </disk>
+    {{end}}
     <interface type='network'>
       <source network='{{.Network}}'/>
       <mac address='{{.MAC}}'/>
@@ -72,6 +99,12 @@ const domainTmpl = `
       <mac address='{{.PrivateMAC}}'/>
       <model type='virtio'/>
     </interface>
+    {{if .SRIOVVFs}}
+    <interface type='network'>
+      <source network='{{.PrivateNetwork}}'/>
+      <model type='igb'/>
+    </interface>
+    {{end}}
     <serial type='pty'>
       <target port='0'/>
     </serial>
@@ -81,9 +114,29 @@ const domainTmpl = `
     <rng model='virtio'>
       <backend model='random'>/dev/random</backend>
     </rng>
+    {{range .VirtiofsShares}}
+    <filesystem type='mount' accessmode='passthrough'>
+      <driver type='virtiofs'/>
+      <source dir='{{.}}'/>
+      <target dir='{{virtiofsTag .}}'/>
+    </filesystem>
+    {{end}}
     {{if .GPU}}
     {{.DevicesXML}}
     {{end}}
+    {{if .USBDevices}}
+    <controller type='usb' model='qemu-xhci'/>
+    {{end}}
+    {{range .USBDevices}}
+    {{with usbDevice .}}
+    <hostdev mode='subsystem' type='usb' managed='yes'>
+      <source startupPolicy='optional'>
+        <vendor id='0x{{.Vendor}}'/>
+        <product id='0x{{.Product}}'/>
+      </source>
+    </hostdev>
+    {{end}}
+    {{end}}
   </devices>
 </domain>
 `
@@ -160,7 +213,13 @@ func (d *Driver) createDomain() (*libvirt.Domain, error) {
 	}
 
 	// create the XML for the domain using our domainTmpl template
-	tmpl := template.Must(template.New("domain").Parse(domainTmpl))
+	tmpl := template.Must(template.New("domain").Funcs(template.FuncMap{
+		"virtiofsTag":     pkgdrivers.VirtiofsTag,
+		"usbDevice":       pkgdrivers.ParseUSBDevice,
+		"numaCells":       pkgdrivers.NUMACells,
+		"extraDiskPaths":  pkgdrivers.ExtraDiskPaths,
+		"extraDiskTarget": pkgdrivers.ExtraDiskTarget,
+	}).Parse(domainTmpl))
 	var domainXML bytes.Buffer
 	if err := tmpl.Execute(&domainXML, d); err != nil {
 		return nil, errors.Wrap(err, "executing domain xml")
@@ -180,3 +239,40 @@ func (d *Driver) createDomain() (*libvirt.Domain, error) {
 
 	return dom, nil
 }
+
+// updateDomainResources applies the memory and CPUs of the driver to the definition of the stopped domain,
+// as they may have been changed by 'minikube resize' since it was defined
+func (d *Driver) updateDomainResources(dom *libvirt.Domain) error {
+	info, err := dom.GetInfo()
+	if err != nil {
+		return errors.Wrap(err, "getting domain info")
+	}
+
+	// The domain XML is in MB, libvirt reports KiB
+	mem := uint64(d.Memory) * 1000 * 1000 / 1024
+	if info.MaxMem != mem {
+		log.Infof("Setting the memory of the domain to %dMB", d.Memory)
+		if err := dom.SetMemoryFlags(mem, libvirt.DOMAIN_MEM_CONFIG|libvirt.DOMAIN_MEM_MAXIMUM); err != nil {
+			return errors.Wrap(err, "setting maximum memory")
+		}
+		if err := dom.SetMemoryFlags(mem, libvirt.DOMAIN_MEM_CONFIG); err != nil {
+			return errors.Wrap(err, "setting memory")
+		}
+	}
+
+	cpus := uint(d.CPU)
+	if uint(info.NrVirtCpu) != cpus {
+		log.Infof("Setting the CPUs of the domain to %d", d.CPU)
+		// The maximum must stay above the current count, so it is changed first when growing, and last when shrinking
+		flags := []libvirt.DomainVcpuFlags{libvirt.DOMAIN_VCPU_CONFIG | libvirt.DOMAIN_VCPU_MAXIMUM, libvirt.DOMAIN_VCPU_CONFIG}
+		if cpus < uint(info.NrVirtCpu) {
+			flags[0], flags[1] = flags[1], flags[0]
+		}
+		for _, f := range flags {
+			if err := dom.SetVcpusFlags(cpus, f); err != nil {
+				return errors.Wrap(err, "setting CPUs")
+			}
+		}
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -33,7 +34,28 @@ import (
 var sysFsPCIDevicesPath = "/sys/bus/pci/devices/"
 var sysKernelIOMMUGroupsPath = "/sys/kernel/iommu_groups/"
 
-const nvidiaVendorID = "0x10de"
+// gpuVendor describes the GPUs of a vendor which can be passed through to the VM
+type gpuVendor struct {
+	// Name is the vendor name shown in messages
+	Name string
+	// ID is the PCI vendor ID of the devices
+	ID string
+	// Class is, if set, the prefix of the PCI class of the devices to passthrough, such as 0x03 for display controllers
+	Class string
+	// Tutorial documents how to prepare the host for the passthrough
+	Tutorial string
+}
+
+// gpuVendors are the GPU vendors supported for passthrough, by the name used in the driver options
+var gpuVendors = map[string]gpuVendor{
+	"nvidia": {Name: "NVIDIA", ID: "0x10de", Tutorial: "https://minikube.sigs.k8s.io/docs/tutorials/nvidia_gpu/"},
+	"amd":    {Name: "AMD", ID: "0x1002", Tutorial: "https://minikube.sigs.k8s.io/docs/tutorials/amd_gpu/"},
+	// Intel makes most of the other devices of the host too, so only its GPUs are passed through
+	"intel": {Name: "Intel", ID: "0x8086", Class: "0x03", Tutorial: "https://minikube.sigs.k8s.io/docs/tutorials/intel_gpu/"},
+}
+
+// defaultGPUVendor is the vendor of the passthrough GPUs when none is set
+const defaultGPUVendor = "nvidia"
 
 const devicesTmpl = `
 <graphics type='spice' autoport='yes'>
@@ -61,14 +83,21 @@ type PCIDevice struct {
 }
 
 // getDevicesXML returns the XML that can be added to the libvirt domain XML to
-// passthrough NVIDIA devices.
-func getDevicesXML() (string, error) {
-	unboundNVIDIADevices, err := getPassthroughableNVIDIADevices()
+// passthrough the devices of a GPU vendor: nvidia if empty, amd or intel.
+func getDevicesXML(vendor string) (string, error) {
+	if vendor == "" {
+		vendor = defaultGPUVendor
+	}
+	v, ok := gpuVendors[vendor]
+	if !ok {
+		return "", fmt.Errorf("couldn't generate devices XML: unsupported GPU vendor %q", vendor)
+	}
+	unboundDevices, err := getPassthroughableDevices(v)
 	if err != nil {
 		return "", fmt.Errorf("couldn't generate devices XML: %v", err)
 	}
 	var pciDevices []PCIDevice
-	for _, device := range unboundNVIDIADevices {
+	for _, device := range unboundDevices {
 		splits := strings.Split(device, ":")
 		if len(splits) != 3 {
 			log.Infof("Error while parsing PCI device %q. Not splitable into domain:bus:slot.function.", device)
@@ -98,11 +127,11 @@ func getDevicesXML() (string, error) {
 	return devicesXML.String(), nil
 }
 
-// getPassthroughableNVIDIADevices returns a list of NVIDIA devices that can be
+// getPassthroughableDevices returns a list of devices of the vendor that can be
 // passthrough from the host to a VM. It returns an error if:
 // - host doesn't support pci passthrough (IOMMU).
-// - there are no passthorughable NVIDIA devices on the host.
-func getPassthroughableNVIDIADevices() ([]string, error) {
+// - there are no passthorughable devices of the vendor on the host.
+func getPassthroughableDevices(v gpuVendor) ([]string, error) {
 
 	// Make sure the host supports IOMMU
 	iommuGroups, err := ioutil.ReadDir(sysKernelIOMMUGroupsPath)
@@ -110,7 +139,7 @@ func getPassthroughableNVIDIADevices() ([]string, error) {
 		return []string{}, fmt.Errorf("error reading %q: %v", sysKernelIOMMUGroupsPath, err)
 	}
 	if len(iommuGroups) == 0 {
-		return []string{}, fmt.Errorf("no IOMMU groups found at %q. Make sure your host supports IOMMU. See instructions at https://minikube.sigs.k8s.io/docs/tutorials/nvidia_gpu/", sysKernelIOMMUGroupsPath)
+		return []string{}, fmt.Errorf("no IOMMU groups found at %q. Make sure your host supports IOMMU. See instructions at %s", sysKernelIOMMUGroupsPath, v.Tutorial)
 	}
 
 	// Get list of PCI devices
@@ -119,7 +148,7 @@ func getPassthroughableNVIDIADevices() ([]string, error) {
 		return []string{}, fmt.Errorf("error reading %q: %v", sysFsPCIDevicesPath, err)
 	}
 
-	unboundNVIDIADevices := make(map[string]bool)
+	unboundDevices := make(map[string]bool)
 	found := false
 	for _, device := range devices {
 		vendorPath := filepath.Join(sysFsPCIDevicesPath, device.Name(), "vendor")
@@ -129,42 +158,58 @@ func getPassthroughableNVIDIADevices() ([]string, error) {
 			continue
 		}
 
-		// Check if this is an NVIDIA device
-		if strings.EqualFold(strings.TrimSpace(string(content)), nvidiaVendorID) {
-			log.Infof("Found device %v with NVIDIA's vendorId %v", device.Name(), nvidiaVendorID)
+		// Check if this is a device of the vendor
+		if strings.EqualFold(strings.TrimSpace(string(content)), v.ID) && hasClass(device.Name(), v.Class) {
+			log.Infof("Found device %v with %s's vendorId %v", device.Name(), v.Name, v.ID)
 			found = true
 
-			// Check whether it's unbound. We don't want the device to be bound to nvidia/nouveau etc.
+			// Check whether it's unbound. We don't want the device to be bound to nvidia/nouveau/amdgpu/i915 etc.
 			if isUnbound(device.Name()) {
 				// Add the unbound device to the map. The value is set to false initially,
 				// it will be set to true later if the device is also isolated.
-				unboundNVIDIADevices[device.Name()] = false
+				unboundDevices[device.Name()] = false
 			}
 		}
 	}
 	if !found {
-		return []string{}, fmt.Errorf("no NVIDIA devices found")
+		return []string{}, fmt.Errorf("no %s devices found", v.Name)
 	}
-	if len(unboundNVIDIADevices) == 0 {
-		return []string{}, fmt.Errorf("some NVIDIA devices were found but none of them were unbound. See instructions at https://minikube.sigs.k8s.io/docs/tutorials/nvidia_gpu/")
+	if len(unboundDevices) == 0 {
+		return []string{}, fmt.Errorf("some %s devices were found but none of them were unbound. See instructions at %s", v.Name, v.Tutorial)
 	}
 
 	// Make sure all the unbound devices are in IOMMU groups that only contain unbound devices.
-	for device := range unboundNVIDIADevices {
-		unboundNVIDIADevices[device] = isIsolated(device)
+	for device := range unboundDevices {
+		unboundDevices[device] = isIsolated(device)
 	}
 
-	isolatedNVIDIADevices := make([]string, 0, len(unboundNVIDIADevices))
-	for unboundNVIDIADevice, isIsolated := range unboundNVIDIADevices {
+	isolatedDevices := make([]string, 0, len(unboundDevices))
+	for unboundDevice, isIsolated := range unboundDevices {
 		if isIsolated {
-			isolatedNVIDIADevices = append(isolatedNVIDIADevices, unboundNVIDIADevice)
+			isolatedDevices = append(isolatedDevices, unboundDevice)
 		}
 	}
-	if len(isolatedNVIDIADevices) == 0 {
-		return []string{}, fmt.Errorf("some unbound NVIDIA devices were found but they had other devices in their IOMMU group that were bound. See instructoins at https://minikube.sigs.k8s.io/docs/tutorials/nvidia_gpu/")
+	if len(isolatedDevices) == 0 {
+		return []string{}, fmt.Errorf("some unbound %s devices were found but they had other devices in their IOMMU group that were bound. See instructions at %s", v.Name, v.Tutorial)
 	}
+	sort.Strings(isolatedDevices)
+
+	return isolatedDevices, nil
+}
 
-	return isolatedNVIDIADevices, nil
+// hasClass returns true if the PCI class of the device starts with class, or if class is empty.
+// The input device is expected to be a string like 0000:03:00.1 (Domain:Bus:Slot.Function)
+func hasClass(device string, class string) bool {
+	if class == "" {
+		return true
+	}
+	classPath := filepath.Join(sysFsPCIDevicesPath, device, "class")
+	content, err := ioutil.ReadFile(classPath)
+	if err != nil {
+		log.Infof("Error while reading %q: %v", classPath, err)
+		return false
+	}
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(string(content))), class)
 }
 
 // isIsolated returns true if the device is an IOMMU group that only consists of unbound devices.
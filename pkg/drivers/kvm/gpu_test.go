@@ -0,0 +1,92 @@
+// +build linux
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kvm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeSysFs creates the sysfs entries of PCI devices, by address with their vendor ID and class, each in its own IOMMU group
+func fakeSysFs(t *testing.T, devices map[string][2]string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, ids := range devices {
+		groupPath := filepath.Join(dir, "devices", name, "iommu_group", "devices", name)
+		if err := os.MkdirAll(groupPath, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "devices", name, "vendor"), []byte(ids[0]+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "devices", name, "class"), []byte(ids[1]+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(dir, "iommu_groups", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGetPassthroughableDevices(t *testing.T) {
+	dir := fakeSysFs(t, map[string][2]string{
+		"0000:01:00.0": {"0x10de", "0x030000"},
+		"0000:01:00.1": {"0x10de", "0x040300"},
+		"0000:02:00.0": {"0x1002", "0x030000"},
+		"0000:00:02.0": {"0x8086", "0x030000"},
+		"0000:00:1f.3": {"0x8086", "0x040380"},
+	})
+	defer os.RemoveAll(dir)
+	defer func(devices, groups string) {
+		sysFsPCIDevicesPath, sysKernelIOMMUGroupsPath = devices, groups
+	}(sysFsPCIDevicesPath, sysKernelIOMMUGroupsPath)
+	sysFsPCIDevicesPath = filepath.Join(dir, "devices")
+	sysKernelIOMMUGroupsPath = filepath.Join(dir, "iommu_groups")
+
+	var tests = []struct {
+		vendor   string
+		expected []string
+	}{
+		{"nvidia", []string{"0000:01:00.0", "0000:01:00.1"}},
+		{"amd", []string{"0000:02:00.0"}},
+		{"intel", []string{"0000:00:02.0"}},
+	}
+	for _, test := range tests {
+		t.Run(test.vendor, func(t *testing.T) {
+			got, err := getPassthroughableDevices(gpuVendors[test.vendor])
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+
+	if _, err := getDevicesXML("matrox"); err == nil {
+		t.Error("expected an error for an unsupported vendor")
+	}
+}
@@ -56,6 +56,9 @@ type Driver struct {
 	// The path of the disk .img
 	DiskPath string
 
+	// The number of extra disks, of DiskSize, attached to the VM as raw block devices
+	ExtraDisks int
+
 	// A file or network URI to fetch the minikube ISO
 	Boot2DockerURL string
 
@@ -73,6 +76,20 @@ type Driver struct {
 	// Whether to passthrough GPU devices from the host to the VM.
 	GPU bool
 
+	// The vendor of the GPU devices to passthrough: nvidia if empty, amd or intel
+	GPUVendor string
+
+	// USB devices of the host to passthrough, as vendor:product IDs.
+	// The VM starts without the devices which are not plugged in.
+	USBDevices []string
+
+	// The number of NUMA nodes the CPUs and memory of the VM are split between. 0 or 1 for none.
+	NUMANodes int
+
+	// The number of virtual functions of an emulated igb NIC, which supports SR-IOV, on the private network. 0 for none.
+	// The VFs are created in the VM by minikube start, as the NIC needs the q35 machine, libvirt 9.3 and QEMU 8.0.
+	SRIOVVFs int
+
 	// Whether to hide the KVM hypervisor signature from the guest
 	Hidden bool
 
@@ -81,6 +98,16 @@ type Driver struct {
 
 	// QEMU Connection URI
 	ConnectionURI string
+
+	// Whether the private network also hands out IPv6 addresses
+	IPv6 bool
+
+	// Host directories shared with the VM over virtiofs
+	VirtiofsShares []string
+
+	// The IP address the private network always leases to the VM.
+	// If empty, the VM gets any free address.
+	StaticIP string
 }
 
 const (
@@ -264,6 +291,10 @@ func (d *Driver) Start() (err error) {
 		}
 	}()
 
+	if err := d.updateDomainResources(dom); err != nil {
+		return errors.Wrap(err, "updating domain resources")
+	}
+
 	log.Info("Creating domain...")
 	if err := dom.Create(); err != nil {
 		return errors.Wrap(err, "error creating VM")
@@ -312,7 +343,7 @@ func (d *Driver) Create() (err error) {
 	}
 	if d.GPU {
 		log.Info("Creating devices...")
-		d.DevicesXML, err = getDevicesXML()
+		d.DevicesXML, err = getDevicesXML(d.GPUVendor)
 		if err != nil {
 			return errors.Wrap(err, "creating devices")
 		}
@@ -329,6 +360,9 @@ func (d *Driver) Create() (err error) {
 	if err = pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
 		return errors.Wrap(err, "error creating disk")
 	}
+	if err := pkgdrivers.CreateExtraDisks(d.BaseDriver, d.ExtraDisks, d.DiskSize); err != nil {
+		return errors.Wrap(err, "creating extra disks")
+	}
 
 	if err := ensureDirPermissions(store); err != nil {
 		log.Errorf("unable to ensure permissions on %s: %v", store, err)
@@ -424,6 +458,12 @@ func (d *Driver) Remove() error {
 	}
 	defer conn.Close()
 
+	if d.StaticIP != "" {
+		if err := d.releaseStaticIP(conn); err != nil {
+			log.Warnf("Releasing static IP %s failed: %v", d.StaticIP, err)
+		}
+	}
+
 	// Tear down network if it exists and is not in use by another minikube instance
 	log.Debug("Trying to delete the networks (if possible)")
 	if err := d.deleteNetwork(); err != nil {
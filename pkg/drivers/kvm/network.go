@@ -43,6 +43,13 @@ const networkTmpl = `
       <range start='192.168.39.2' end='192.168.39.254'/>
     </dhcp>
   </ip>
+{{- if .IPv6}}
+  <ip family='ipv6' address='fd00:39::1' prefix='64'>
+    <dhcp>
+      <range start='fd00:39::2' end='fd00:39::ff'/>
+    </dhcp>
+  </ip>
+{{- end}}
 </network>
 `
 
@@ -103,9 +110,97 @@ func (d *Driver) ensureNetwork() error {
 		return err
 	}
 
+	if d.StaticIP != "" {
+		log.Infof("Reserving %s for %s in network %s", d.StaticIP, d.PrivateMAC, d.PrivateNetwork)
+		if err := d.reserveStaticIP(conn); err != nil {
+			return errors.Wrapf(err, "reserving %s", d.StaticIP)
+		}
+	}
+
 	return nil
 }
 
+// dhcpHost is a static lease of the DHCP server of a libvirt network
+type dhcpHost struct {
+	MAC string `xml:"mac,attr"`
+	IP  string `xml:"ip,attr"`
+}
+
+// xml returns the element of the host, as expected by virNetworkUpdate
+func (h dhcpHost) xml() string {
+	return fmt.Sprintf("<host mac='%s' ip='%s'/>", h.MAC, h.IP)
+}
+
+// parseDHCPHosts returns the static leases of the network defined by networkXML
+func parseDHCPHosts(networkXML string) ([]dhcpHost, error) {
+	type result struct {
+		Hosts []dhcpHost `xml:"ip>dhcp>host"`
+	}
+	v := result{}
+	if err := xml.Unmarshal([]byte(networkXML), &v); err != nil {
+		return nil, errors.Wrap(err, "unmarshal network xml")
+	}
+	return v.Hosts, nil
+}
+
+// conflictingDHCPHosts returns the static leases which have to be removed before want is added
+func conflictingDHCPHosts(hosts []dhcpHost, want dhcpHost) (conflicts []dhcpHost, found bool) {
+	for _, h := range hosts {
+		if h == want {
+			found = true
+			continue
+		}
+		if strings.EqualFold(h.MAC, want.MAC) || h.IP == want.IP {
+			conflicts = append(conflicts, h)
+		}
+	}
+	return conflicts, found
+}
+
+// updateDHCPHost adds or deletes a static lease of the network, both in the running network and in its definition
+func updateDHCPHost(n *libvirt.Network, cmd libvirt.NetworkUpdateCommand, h dhcpHost) error {
+	return n.Update(cmd, libvirt.NETWORK_SECTION_IP_DHCP_HOST, -1, h.xml(), libvirt.NETWORK_UPDATE_AFFECT_LIVE|libvirt.NETWORK_UPDATE_AFFECT_CONFIG)
+}
+
+// reserveStaticIP ensures that the private NIC of the VM always gets StaticIP from the private network.
+// Leases of the same MAC or IP, left over from previous VMs, are replaced.
+func (d *Driver) reserveStaticIP(conn *libvirt.Connect) error {
+	n, err := conn.LookupNetworkByName(d.PrivateNetwork)
+	if err != nil {
+		return errors.Wrapf(err, "looking up network %s", d.PrivateNetwork)
+	}
+
+	networkXML, err := n.GetXMLDesc(0)
+	if err != nil {
+		return errors.Wrapf(err, "getting XML of network %s", d.PrivateNetwork)
+	}
+	hosts, err := parseDHCPHosts(networkXML)
+	if err != nil {
+		return err
+	}
+	want := dhcpHost{MAC: d.PrivateMAC, IP: d.StaticIP}
+	conflicts, found := conflictingDHCPHosts(hosts, want)
+	for _, h := range conflicts {
+		log.Infof("Removing static lease %s for %s", h.IP, h.MAC)
+		if err := updateDHCPHost(n, libvirt.NETWORK_UPDATE_COMMAND_DELETE, h); err != nil {
+			return errors.Wrapf(err, "removing static lease %s for %s", h.IP, h.MAC)
+		}
+	}
+	if found {
+		return nil
+	}
+	return updateDHCPHost(n, libvirt.NETWORK_UPDATE_COMMAND_ADD_LAST, want)
+}
+
+// releaseStaticIP removes the static lease of the VM, for networks which outlive it
+func (d *Driver) releaseStaticIP(conn *libvirt.Connect) error {
+	n, err := conn.LookupNetworkByName(d.PrivateNetwork)
+	if err != nil {
+		return errors.Wrapf(err, "looking up network %s", d.PrivateNetwork)
+	}
+	return updateDHCPHost(n, libvirt.NETWORK_UPDATE_COMMAND_DELETE, dhcpHost{MAC: d.PrivateMAC, IP: d.StaticIP})
+}
+
 // createNetwork is called during creation of the VM only (and not on start)
 func (d *Driver) createNetwork() error {
 	if d.Network == defaultPrivateNetworkName {
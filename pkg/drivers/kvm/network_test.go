@@ -19,6 +19,7 @@ limitations under the License.
 package kvm
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -95,3 +96,44 @@ func Test_parseStatusAndReturnIp(t *testing.T) {
 		})
 	}
 }
+
+func Test_conflictingDHCPHosts(t *testing.T) {
+	networkXML := `<network>
+  <name>minikube-net</name>
+  <ip address='192.168.39.1' netmask='255.255.255.0'>
+    <dhcp>
+      <range start='192.168.39.2' end='192.168.39.254'/>
+      <host mac='a1:b2:c3:d4:e5:f6' ip='192.168.39.10'/>
+      <host mac='a4:b5:c6:d7:e8:f9' ip='192.168.39.20'/>
+    </dhcp>
+  </ip>
+</network>`
+	hosts, err := parseDHCPHosts(networkXML)
+	if err != nil {
+		t.Fatalf("parseDHCPHosts: %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("parseDHCPHosts() = %v, want 2 hosts", hosts)
+	}
+
+	tests := []struct {
+		name      string
+		want      dhcpHost
+		conflicts []dhcpHost
+		found     bool
+	}{
+		{"reserved", dhcpHost{MAC: "a1:b2:c3:d4:e5:f6", IP: "192.168.39.10"}, nil, true},
+		{"new", dhcpHost{MAC: "aa:bb:cc:dd:ee:ff", IP: "192.168.39.30"}, nil, false},
+		{"changed ip", dhcpHost{MAC: "A1:B2:C3:D4:E5:F6", IP: "192.168.39.30"}, []dhcpHost{hosts[0]}, false},
+		{"ip of another vm", dhcpHost{MAC: "aa:bb:cc:dd:ee:ff", IP: "192.168.39.20"}, []dhcpHost{hosts[1]}, false},
+		{"both", dhcpHost{MAC: "a1:b2:c3:d4:e5:f6", IP: "192.168.39.20"}, hosts, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conflicts, found := conflictingDHCPHosts(hosts, tc.want)
+			if !reflect.DeepEqual(conflicts, tc.conflicts) || found != tc.found {
+				t.Errorf("conflictingDHCPHosts() = %v, %v, want %v, %v", conflicts, found, tc.conflicts, tc.found)
+			}
+		})
+	}
+}
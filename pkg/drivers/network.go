@@ -0,0 +1,50 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"crypto/rand"
+	"net"
+	"regexp"
+)
+
+var leadingZeroRegexp = regexp.MustCompile(`0([A-Fa-f0-9](:|$))`)
+
+// TrimMacAddress removes the leading zero of each octet of a MAC address, as the vmnet dhcp leases file does
+func TrimMacAddress(mac string) string {
+	return leadingZeroRegexp.ReplaceAllString(mac, "$1")
+}
+
+// RandomMAC returns a random unicast, locally administered MAC address
+func RandomMAC() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	buf[0] = (buf[0] | 0x02) & 0xfe
+	return net.HardwareAddr(buf).String(), nil
+}
+
+// FreePort asks the kernel for a free TCP port on the loopback interface
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
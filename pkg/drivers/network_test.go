@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestTrimMacAddress(t *testing.T) {
+	var tests = []struct {
+		mac  string
+		want string
+	}{
+		{mac: "52:54:00:0a:1b:c0", want: "52:54:0:a:1b:c0"},
+		{mac: "0e:01:02:03:04:05", want: "e:1:2:3:4:5"},
+		{mac: "aa:bb:cc:dd:ee:ff", want: "aa:bb:cc:dd:ee:ff"},
+		{mac: "0e:01:a2:03:04:50", want: "e:1:a2:3:4:50"},
+	}
+	for _, tc := range tests {
+		if got := TrimMacAddress(tc.mac); got != tc.want {
+			t.Errorf("TrimMacAddress(%q) = %q, want %q", tc.mac, got, tc.want)
+		}
+	}
+}
+
+func TestRandomMAC(t *testing.T) {
+	mac, err := RandomMAC()
+	if err != nil {
+		t.Fatalf("RandomMAC() error = %v", err)
+	}
+	hw, err := net.ParseMAC(mac)
+	if err != nil {
+		t.Fatalf("ParseMAC(%q) error = %v", mac, err)
+	}
+	if hw[0]&0x01 != 0 {
+		t.Errorf("%s is a multicast address", mac)
+	}
+	if hw[0]&0x02 == 0 {
+		t.Errorf("%s is not locally administered", mac)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	port, err := FreePort()
+	if err != nil {
+		t.Fatalf("FreePort() error = %v", err)
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("listening on the free port %d: %v", port, err)
+	}
+	l.Close()
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import "fmt"
+
+// NUMACell is a NUMA node of a VM, with its CPUs as a range such as 0-1, and its memory in MB
+type NUMACell struct {
+	ID     int
+	CPUs   string
+	Memory int
+}
+
+// NUMACells splits the CPUs and the memory of a VM evenly between nodes. The first nodes get the remainders.
+func NUMACells(cpus int, memory int, nodes int) []NUMACell {
+	var cells []NUMACell
+	first := 0
+	for i := 0; i < nodes; i++ {
+		n := cpus / nodes
+		if i < cpus%nodes {
+			n++
+		}
+		mem := memory / nodes
+		if i < memory%nodes {
+			mem++
+		}
+		cpuRange := fmt.Sprintf("%d-%d", first, first+n-1)
+		if n == 1 {
+			cpuRange = fmt.Sprintf("%d", first)
+		}
+		cells = append(cells, NUMACell{ID: i, CPUs: cpuRange, Memory: mem})
+		first += n
+	}
+	return cells
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// NetworkUser is QEMU's user-mode (slirp) networking, which needs no privileges
+	NetworkUser = "user"
+	// NetworkSocketVMnet attaches the VM to a vmnet bridge through socket_vmnet (macOS only)
+	NetworkSocketVMnet = "socket_vmnet"
+
+	// UserNetworkGuestIP is the address slirp hands out to the guest
+	UserNetworkGuestIP = "10.0.2.15"
+	// UserNetworkHostIP is the address the guest can reach the host on with user-mode networking
+	UserNetworkHostIP = "10.0.2.2"
+)
+
+// SupportedNetworks is the list of values accepted for the network setting
+var SupportedNetworks = []string{NetworkUser, NetworkSocketVMnet}
+
+// ValidateNetwork returns an error if network is not a supported network for the given OS
+func ValidateNetwork(network string, goos string) error {
+	switch network {
+	case NetworkUser:
+		return nil
+	case NetworkSocketVMnet:
+		if goos != "darwin" {
+			return fmt.Errorf("%s networking is only supported on macOS", network)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported network %q, valid options: %s", network, strings.Join(SupportedNetworks, ", "))
+	}
+}
+
+// ValidateVirtiofs returns an error if virtiofs shares are not supported on the given OS
+func ValidateVirtiofs(goos string) error {
+	if goos != "linux" {
+		return fmt.Errorf("virtiofs is only supported by the qemu2 driver on Linux, as virtiofsd is not available on %s", goos)
+	}
+	return nil
+}
+
+// portForward maps a port on the host loopback interface to a port inside the guest
+type portForward struct {
+	Host  int
+	Guest int
+}
+
+// hostForwards returns the user-mode netdev hostfwd rules for the given forwards
+func hostForwards(forwards []portForward) string {
+	rules := []string{}
+	for _, f := range forwards {
+		rules = append(rules, fmt.Sprintf("hostfwd=tcp:127.0.0.1:%d-:%d", f.Host, f.Guest))
+	}
+	return strings.Join(rules, ",")
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"testing"
+)
+
+func TestValidateNetwork(t *testing.T) {
+	var tests = []struct {
+		network string
+		goos    string
+		wantErr bool
+	}{
+		{network: NetworkUser, goos: "linux"},
+		{network: NetworkUser, goos: "darwin"},
+		{network: NetworkSocketVMnet, goos: "darwin"},
+		{network: NetworkSocketVMnet, goos: "linux", wantErr: true},
+		{network: "bridge", goos: "linux", wantErr: true},
+	}
+	for _, tc := range tests {
+		err := ValidateNetwork(tc.network, tc.goos)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateNetwork(%q, %q) = %v, wantErr: %v", tc.network, tc.goos, err, tc.wantErr)
+		}
+	}
+}
+
+func TestHostForwards(t *testing.T) {
+	got := hostForwards([]portForward{{Host: 40022, Guest: 22}, {Host: 8443, Guest: 8443}})
+	want := "hostfwd=tcp:127.0.0.1:40022-:22,hostfwd=tcp:127.0.0.1:8443-:8443"
+	if got != want {
+		t.Errorf("hostForwards() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateVirtiofs(t *testing.T) {
+	if err := ValidateVirtiofs("linux"); err != nil {
+		t.Errorf("ValidateVirtiofs(linux) = %v, want nil", err)
+	}
+	for _, goos := range []string{"darwin", "windows"} {
+		if err := ValidateVirtiofs(goos); err == nil {
+			t.Errorf("ValidateVirtiofs(%s) = nil, want an error", goos)
+		}
+	}
+}
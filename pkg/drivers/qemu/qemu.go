@@ -0,0 +1,524 @@
+// +build linux darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+const (
+	isoFilename     = "boot2docker.iso"
+	pidFileName     = "qemu.pid"
+	monitorFileName = "monitor"
+	consoleFileName = "console.log"
+)
+
+// Driver is the machine driver for QEMU
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+
+	// How much memory, in MB, to allocate to the VM
+	Memory int
+
+	// How many cpus to allocate to the VM
+	CPU int
+
+	// The size of the disk to be created for the VM, in MB
+	DiskSize int
+
+	// A file or network URI to fetch the minikube ISO
+	Boot2DockerURL string
+
+	// The qemu-system binary to run. If empty, it is chosen from the host architecture.
+	Program string
+
+	// Path to the UEFI firmware, required on arm64 hosts
+	Firmware string
+
+	// Either NetworkUser or NetworkSocketVMnet
+	Network string
+
+	// Path to the socket_vmnet_client binary
+	SocketVMnetClientPath string
+
+	// Path to the socket_vmnet daemon socket
+	SocketVMnetPath string
+
+	// The MAC address of the virtio NIC. If empty, a random MAC will be generated.
+	MACAddress string
+
+	// The host port forwarded to the docker daemon, only used with user-mode networking
+	EnginePort int
+
+	// The apiserver port, forwarded from the host loopback with user-mode networking
+	APIServerPort int
+
+	// Host directories shared with the VM over virtiofs, each served by its own virtiofsd
+	VirtiofsShares []string
+
+	// USB devices of the host passed through to the VM, as vendor:product IDs
+	USBDevices []string
+
+	// The number of NUMA nodes the CPUs and memory of the VM are split between. 0 or 1 for none.
+	NUMANodes int
+
+	// The number of extra disks, of DiskSize, attached to the VM as raw block devices
+	ExtraDisks int
+}
+
+// NewDriver creates a new driver for a host
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+		Network:      NetworkUser,
+	}
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return "qemu2"
+}
+
+// PreCreateCheck verifies that the configured binaries are present
+func (d *Driver) PreCreateCheck() error {
+	if err := ValidateNetwork(d.Network, runtime.GOOS); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(d.program()); err != nil {
+		return errors.Wrapf(err, "%s is required by the qemu2 driver", d.program())
+	}
+	if d.Network == NetworkSocketVMnet {
+		if _, err := os.Stat(d.SocketVMnetClientPath); err != nil {
+			return errors.Wrap(err, "socket_vmnet_client")
+		}
+		if _, err := os.Stat(d.SocketVMnetPath); err != nil {
+			return errors.Wrap(err, "socket_vmnet socket: is the socket_vmnet daemon running?")
+		}
+	}
+	if len(d.VirtiofsShares) > 0 {
+		if err := ValidateVirtiofs(runtime.GOOS); err != nil {
+			return err
+		}
+		if _, err := findVirtiofsd(); err != nil {
+			return errors.Wrap(err, "virtiofsd is required for virtiofs mounts")
+		}
+	}
+	if _, err := d.usbArgs(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	log.Info("Creating QEMU machine...")
+	if d.MACAddress == "" {
+		mac, err := pkgdrivers.RandomMAC()
+		if err != nil {
+			return errors.Wrap(err, "generating mac address")
+		}
+		d.MACAddress = mac
+	}
+
+	if d.Network == NetworkUser {
+		var err error
+		if d.SSHPort, err = pkgdrivers.FreePort(); err != nil {
+			return errors.Wrap(err, "allocating ssh port")
+		}
+		if d.EnginePort, err = pkgdrivers.FreePort(); err != nil {
+			return errors.Wrap(err, "allocating docker port")
+		}
+	}
+
+	log.Infof("Building disk image from %s", d.Boot2DockerURL)
+	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+		return errors.Wrap(err, "making disk image")
+	}
+	if err := pkgdrivers.CreateExtraDisks(d.BaseDriver, d.ExtraDisks, d.DiskSize); err != nil {
+		return errors.Wrap(err, "creating extra disks")
+	}
+	return d.Start()
+}
+
+// GetIP returns an IP or hostname that this host is available at
+func (d *Driver) GetIP() (string, error) {
+	if d.Network == NetworkUser {
+		return "127.0.0.1", nil
+	}
+	if d.IPAddress != "" {
+		return d.IPAddress, nil
+	}
+	return getIPAddressByMACAddress(d.MACAddress)
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+// GetURL returns a Docker compatible host URL for connecting to this host
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	port := 2376
+	if d.Network == NetworkUser {
+		port = d.EnginePort
+	}
+	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(port))), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	pid, err := d.getPid()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state.Stopped, nil
+		}
+		return state.Error, err
+	}
+	return pidState(pid), nil
+}
+
+// pidState returns whether the process with the given pid is alive
+func pidState(pid int) state.State {
+	if pid == 0 {
+		return state.Stopped
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return state.Stopped
+	}
+	// Signal 0 performs error checking only, without sending a signal
+	if err := p.Signal(syscall.Signal(0)); err != nil {
+		log.Debugf("qemu pid %d is not running: %v", pid, err)
+		return state.Stopped
+	}
+	return state.Running
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	if err := d.removeStalePidFile(); err != nil {
+		return err
+	}
+	if err := d.startVirtiofsd(); err != nil {
+		return errors.Wrap(err, "starting virtiofsd")
+	}
+
+	name, args, err := d.command()
+	if err != nil {
+		return err
+	}
+	log.Debugf("Starting: %s %s", name, strings.Join(args, " "))
+	cmd := exec.Command(name, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		d.stopVirtiofsd()
+		return errors.Wrapf(err, "starting qemu: %s", output)
+	}
+
+	if d.Network == NetworkSocketVMnet {
+		log.Info("Waiting for VM to get an IP address...")
+		d.IPAddress = ""
+		for i := 0; i < 60; i++ {
+			ip, err := getIPAddressByMACAddress(d.MACAddress)
+			if err == nil && ip != "" {
+				d.IPAddress = ip
+				break
+			}
+			log.Debugf("Waiting for machine to come up %d/%d", i, 60)
+			time.Sleep(2 * time.Second)
+		}
+		if d.IPAddress == "" {
+			return fmt.Errorf("IP address never found in dhcp leases file for %s", d.MACAddress)
+		}
+		log.Infof("Found IP for machine: %s", d.IPAddress)
+	}
+
+	log.Info("Waiting for SSH to be available...")
+	if err := drivers.WaitForSSH(d); err != nil {
+		return errors.Wrap(err, "SSH not available after waiting")
+	}
+	return nil
+}
+
+// command returns the program and arguments used to launch the VM
+func (d *Driver) command() (string, []string, error) {
+	args := []string{
+		"-name", d.MachineName,
+		"-m", strconv.Itoa(d.Memory),
+		"-smp", strconv.Itoa(d.CPU),
+		"-boot", "d",
+		"-cdrom", d.ResolveStorePath(isoFilename),
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=raw", pkgdrivers.GetDiskPath(d.BaseDriver)),
+		"-qmp", fmt.Sprintf("unix:%s,server,nowait", d.ResolveStorePath(monitorFileName)),
+		"-pidfile", d.ResolveStorePath(pidFileName),
+		"-serial", fmt.Sprintf("file:%s", d.ResolveStorePath(consoleFileName)),
+		"-display", "none",
+		"-daemonize",
+	}
+	for _, path := range pkgdrivers.ExtraDiskPaths(d.BaseDriver, d.ExtraDisks) {
+		args = append(args, "-drive", fmt.Sprintf("file=%s,if=virtio,format=raw", path))
+	}
+	args = append(args, machineArgs(runtime.GOOS, runtime.GOARCH)...)
+	if d.Firmware != "" {
+		args = append(args, "-drive", fmt.Sprintf("if=pflash,format=raw,readonly,file=%s", d.Firmware))
+	}
+	args = append(args, d.numaArgs()...)
+	args = append(args, d.virtiofsArgs()...)
+	usb, err := d.usbArgs()
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, usb...)
+
+	switch d.Network {
+	case NetworkUser:
+		forwards := []portForward{
+			{Host: d.SSHPort, Guest: 22},
+			{Host: d.EnginePort, Guest: 2376},
+		}
+		if d.APIServerPort != 0 {
+			forwards = append(forwards, portForward{Host: d.APIServerPort, Guest: d.APIServerPort})
+		}
+		args = append(args,
+			"-nic", fmt.Sprintf("user,model=virtio,mac=%s,%s", d.MACAddress, hostForwards(forwards)))
+		return d.program(), args, nil
+	case NetworkSocketVMnet:
+		// socket_vmnet_client connects to the daemon and hands the socket over as fd 3
+		args = append(args,
+			"-device", fmt.Sprintf("virtio-net-pci,netdev=net0,mac=%s", d.MACAddress),
+			"-netdev", "socket,id=net0,fd=3")
+		return d.SocketVMnetClientPath, append([]string{d.SocketVMnetPath, d.program()}, args...), nil
+	default:
+		return "", nil, fmt.Errorf("unsupported network: %q", d.Network)
+	}
+}
+
+// numaArgs returns the arguments splitting the CPUs and memory of the VM between NUMA nodes.
+// The memory of the nodes is backed by a memfd with virtiofs shares, as virtiofsd needs to map it.
+func (d *Driver) numaArgs() []string {
+	if d.NUMANodes <= 1 {
+		return nil
+	}
+	var args []string
+	for _, c := range pkgdrivers.NUMACells(d.CPU, d.Memory, d.NUMANodes) {
+		backend := fmt.Sprintf("memory-backend-ram,id=mem%d,size=%dM", c.ID, c.Memory)
+		if len(d.VirtiofsShares) > 0 {
+			backend = fmt.Sprintf("memory-backend-memfd,id=mem%d,size=%dM,share=on", c.ID, c.Memory)
+		}
+		args = append(args,
+			"-object", backend,
+			"-numa", fmt.Sprintf("node,nodeid=%d,cpus=%s,memdev=mem%d", c.ID, c.CPUs, c.ID))
+	}
+	return args
+}
+
+// machineArgs returns the machine type and accelerator for the host platform
+func machineArgs(goos, goarch string) []string {
+	accel := "tcg"
+	switch goos {
+	case "darwin":
+		accel = "hvf"
+	case "linux":
+		if _, err := os.Stat("/dev/kvm"); err == nil {
+			accel = "kvm"
+		}
+	}
+	cpu := "max"
+	if accel != "tcg" {
+		cpu = "host"
+	}
+	if goarch == "arm64" {
+		return []string{"-machine", fmt.Sprintf("virt,highmem=off,accel=%s", accel), "-cpu", cpu}
+	}
+	return []string{"-machine", fmt.Sprintf("q35,accel=%s", accel), "-cpu", cpu}
+}
+
+// program returns the qemu-system binary for this host
+func (d *Driver) program() string {
+	if d.Program != "" {
+		return d.Program
+	}
+	if runtime.GOARCH == "arm64" {
+		return "qemu-system-aarch64"
+	}
+	return "qemu-system-x86_64"
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	s, err := d.GetState()
+	if err != nil {
+		return errors.Wrap(err, "getting state of VM")
+	}
+	if s == state.Stopped {
+		return nil
+	}
+
+	if err := d.monitorCommand("system_powerdown"); err != nil {
+		log.Warnf("graceful shutdown failed, killing VM: %v", err)
+		return d.Kill()
+	}
+
+	for i := 0; i < 60; i++ {
+		s, err := d.GetState()
+		if err != nil {
+			return errors.Wrap(err, "getting state of VM")
+		}
+		if s == state.Stopped {
+			d.IPAddress = ""
+			d.stopVirtiofsd()
+			return nil
+		}
+		log.Infof("Waiting for machine to stop %d/%d", i, 60)
+		time.Sleep(1 * time.Second)
+	}
+
+	log.Debug("VM did not shut down in time, killing it")
+	return d.Kill()
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	pid, err := d.getPid()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if err := p.Kill(); err != nil && pidState(pid) == state.Running {
+		return errors.Wrapf(err, "killing qemu pid %d", pid)
+	}
+	d.stopVirtiofsd()
+	d.IPAddress = ""
+	return d.removeStalePidFile()
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	s, err := d.GetState()
+	if err != nil {
+		log.Debugf("Error checking machine status: %v, assuming it has been removed already", err)
+	}
+	if s == state.Running {
+		return d.Kill()
+	}
+	return nil
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+// getPid reads the pid written by qemu on startup
+func (d *Driver) getPid() (int, error) {
+	bs, err := ioutil.ReadFile(d.ResolveStorePath(pidFileName))
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(bs)))
+	if err != nil {
+		return 0, errors.Wrap(err, "parsing pidfile")
+	}
+	return pid, nil
+}
+
+// removeStalePidFile removes a pid file left behind by an unclean shutdown
+func (d *Driver) removeStalePidFile() error {
+	pidFile := d.ResolveStorePath(pidFileName)
+	pid, err := d.getPid()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		log.Warnf("unable to read %s, removing it: %v", pidFile, err)
+	} else if pidState(pid) == state.Running {
+		return fmt.Errorf("qemu is already running with pid %d", pid)
+	}
+	if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "removing pidfile %s", pidFile)
+	}
+	return nil
+}
+
+// monitorCommand runs a single command against the QMP monitor socket
+func (d *Driver) monitorCommand(command string) error {
+	conn, err := net.DialTimeout("unix", d.ResolveStorePath(monitorFileName), 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "connecting to qemu monitor")
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	// The server greets us first, and must be put in command mode before it accepts commands.
+	if _, err := r.ReadBytes('\n'); err != nil {
+		return errors.Wrap(err, "reading qmp greeting")
+	}
+	for _, c := range []string{"qmp_capabilities", command} {
+		req, err := json.Marshal(map[string]string{"execute": c})
+		if err != nil {
+			return err
+		}
+		if _, err := conn.Write(append(req, '\n')); err != nil {
+			return errors.Wrapf(err, "sending %s", c)
+		}
+		resp, err := r.ReadBytes('\n')
+		if err != nil {
+			return errors.Wrapf(err, "reading %s response", c)
+		}
+		if strings.Contains(string(resp), `"error"`) {
+			return fmt.Errorf("%s: %s", c, resp)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// +build linux darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+// usbArgs returns the arguments attaching a USB 3 controller, and the USB devices of the host to it.
+// qemu opens the devices with libusb, so it needs to be allowed to write to them, for instance with a udev rule.
+func (d *Driver) usbArgs() ([]string, error) {
+	if len(d.USBDevices) == 0 {
+		return nil, nil
+	}
+	args := []string{"-device", "qemu-xhci,id=xhci"}
+	for _, s := range d.USBDevices {
+		dev, err := pkgdrivers.ParseUSBDevice(s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, "-device", fmt.Sprintf("usb-host,bus=xhci.0,vendorid=0x%s,productid=0x%s", dev.Vendor, dev.Product))
+	}
+	return args, nil
+}
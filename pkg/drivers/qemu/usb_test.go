@@ -0,0 +1,50 @@
+// +build linux darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUSBArgs(t *testing.T) {
+	d := NewDriver("minikube", "/tmp/store")
+	if args, err := d.usbArgs(); err != nil || len(args) != 0 {
+		t.Errorf("usbArgs() = %v, %v without devices, want none", args, err)
+	}
+
+	d.USBDevices = []string{"1050:0407", "046d:085b"}
+	got, err := d.usbArgs()
+	if err != nil {
+		t.Fatalf("usbArgs() = %v", err)
+	}
+	want := []string{
+		"-device", "qemu-xhci,id=xhci",
+		"-device", "usb-host,bus=xhci.0,vendorid=0x1050,productid=0x0407",
+		"-device", "usb-host,bus=xhci.0,vendorid=0x046d,productid=0x085b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("usbArgs() = %v, want %v", got, want)
+	}
+
+	d.USBDevices = []string{"yubikey"}
+	if _, err := d.usbArgs(); err == nil {
+		t.Error("usbArgs() = nil error, want an error for an invalid device")
+	}
+}
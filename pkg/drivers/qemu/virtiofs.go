@@ -0,0 +1,157 @@
+// +build linux darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+const (
+	virtiofsdSocketFileName = "virtiofsd-%d.sock"
+	virtiofsdPidFileName    = "virtiofsd-%d.pid"
+	virtiofsdLogFileName    = "virtiofsd-%d.log"
+)
+
+// virtiofsdCandidates are the places distributions install virtiofsd to, which is rarely on the PATH
+var virtiofsdCandidates = []string{"virtiofsd", "/usr/libexec/virtiofsd", "/usr/lib/qemu/virtiofsd", "/usr/lib/virtiofsd"}
+
+// findVirtiofsd returns the path of the virtiofsd binary
+func findVirtiofsd() (string, error) {
+	for _, c := range virtiofsdCandidates {
+		if path, err := exec.LookPath(c); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("virtiofsd was not found in %s", strings.Join(virtiofsdCandidates, ", "))
+}
+
+// virtiofsArgs returns the arguments attaching a vhost-user-fs device for each share.
+// The guest memory is backed by a memfd, as virtiofsd needs to map it.
+func (d *Driver) virtiofsArgs() []string {
+	if len(d.VirtiofsShares) == 0 {
+		return nil
+	}
+	var args []string
+	if d.NUMANodes <= 1 {
+		// With several NUMA nodes, numaArgs shares the memory of each node instead
+		args = []string{
+			"-object", fmt.Sprintf("memory-backend-memfd,id=mem,size=%dM,share=on", d.Memory),
+			"-numa", "node,memdev=mem",
+		}
+	}
+	for i, share := range d.VirtiofsShares {
+		args = append(args,
+			"-chardev", fmt.Sprintf("socket,id=vfs%d,path=%s", i, d.ResolveStorePath(fmt.Sprintf(virtiofsdSocketFileName, i))),
+			"-device", fmt.Sprintf("vhost-user-fs-pci,chardev=vfs%d,tag=%s", i, pkgdrivers.VirtiofsTag(share)))
+	}
+	return args
+}
+
+// startVirtiofsd starts a virtiofsd for each share, and waits for it to accept connections.
+// virtiofsd exits by itself once qemu disconnects, so it lives exactly as long as the VM.
+func (d *Driver) startVirtiofsd() error {
+	if len(d.VirtiofsShares) == 0 {
+		return nil
+	}
+	d.stopVirtiofsd()
+	path, err := findVirtiofsd()
+	if err != nil {
+		return err
+	}
+
+	for i, share := range d.VirtiofsShares {
+		socket := d.ResolveStorePath(fmt.Sprintf(virtiofsdSocketFileName, i))
+		if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing stale socket %s", socket)
+		}
+		logFile, err := os.Create(d.ResolveStorePath(fmt.Sprintf(virtiofsdLogFileName, i)))
+		if err != nil {
+			return errors.Wrap(err, "creating virtiofsd log")
+		}
+		// The sandbox needs root, which the VM itself doesn't
+		cmd := exec.Command(path, "--socket-path="+socket, "--shared-dir="+share, "--cache=auto", "--sandbox=none")
+		cmd.Stdout = logFile
+		cmd.Stderr = logFile
+		log.Debugf("Starting: %s %s", path, strings.Join(cmd.Args[1:], " "))
+		err = cmd.Start()
+		logFile.Close()
+		if err != nil {
+			return errors.Wrapf(err, "starting virtiofsd for %s", share)
+		}
+		pidFile := d.ResolveStorePath(fmt.Sprintf(virtiofsdPidFileName, i))
+		if err := ioutil.WriteFile(pidFile, []byte(strconv.Itoa(cmd.Process.Pid)), 0600); err != nil {
+			return errors.Wrapf(err, "writing %s", pidFile)
+		}
+		if err := cmd.Process.Release(); err != nil {
+			return errors.Wrap(err, "releasing virtiofsd")
+		}
+		if err := waitForSocket(socket, 10*time.Second); err != nil {
+			return errors.Wrapf(err, "virtiofsd for %s", share)
+		}
+	}
+	return nil
+}
+
+// stopVirtiofsd kills virtiofsd processes left behind, for instance after qemu was killed
+func (d *Driver) stopVirtiofsd() {
+	for i := range d.VirtiofsShares {
+		pidFile := d.ResolveStorePath(fmt.Sprintf(virtiofsdPidFileName, i))
+		bs, err := ioutil.ReadFile(pidFile)
+		if err != nil {
+			continue
+		}
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(bs))); err == nil && pidState(pid) == state.Running {
+			if p, err := os.FindProcess(pid); err == nil {
+				if err := p.Kill(); err != nil {
+					log.Warnf("unable to kill virtiofsd pid %d: %v", pid, err)
+				}
+			}
+		}
+		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+			log.Warnf("unable to remove %s: %v", pidFile, err)
+		}
+	}
+}
+
+// waitForSocket waits until virtiofsd created its socket. It is not probed by connecting,
+// as virtiofsd serves a single connection and exits once that is closed.
+func waitForSocket(path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := os.Stat(path)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Wrapf(err, "waiting for %s", path)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
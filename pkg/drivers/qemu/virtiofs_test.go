@@ -0,0 +1,68 @@
+// +build linux darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"strings"
+	"testing"
+
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+func TestVirtiofsArgs(t *testing.T) {
+	d := NewDriver("minikube", "/tmp/store")
+	d.Memory = 2048
+	if args := d.virtiofsArgs(); len(args) != 0 {
+		t.Errorf("virtiofsArgs() = %v without shares, want none", args)
+	}
+
+	d.VirtiofsShares = []string{"/home/me/src", "/home/me/data"}
+	got := strings.Join(d.virtiofsArgs(), " ")
+	for _, want := range []string{
+		"-object memory-backend-memfd,id=mem,size=2048M,share=on -numa node,memdev=mem",
+		"-chardev socket,id=vfs0,path=/tmp/store/machines/minikube/virtiofsd-0.sock",
+		"-device vhost-user-fs-pci,chardev=vfs0,tag=" + pkgdrivers.VirtiofsTag("/home/me/src"),
+		"-chardev socket,id=vfs1,path=/tmp/store/machines/minikube/virtiofsd-1.sock",
+		"-device vhost-user-fs-pci,chardev=vfs1,tag=" + pkgdrivers.VirtiofsTag("/home/me/data"),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("virtiofsArgs() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestVirtiofsNUMAArgs(t *testing.T) {
+	d := NewDriver("minikube", "/tmp/store")
+	d.CPU = 4
+	d.Memory = 4096
+	d.NUMANodes = 2
+	want := "-object memory-backend-ram,id=mem0,size=2048M -numa node,nodeid=0,cpus=0-1,memdev=mem0 " +
+		"-object memory-backend-ram,id=mem1,size=2048M -numa node,nodeid=1,cpus=2-3,memdev=mem1"
+	if got := strings.Join(d.numaArgs(), " "); got != want {
+		t.Errorf("numaArgs() = %q, want %q", got, want)
+	}
+
+	d.VirtiofsShares = []string{"/home/me/src"}
+	if got := strings.Join(d.numaArgs(), " "); !strings.Contains(got, "memory-backend-memfd,id=mem1,size=2048M,share=on") {
+		t.Errorf("numaArgs() = %q, want memfd backends with virtiofs shares", got)
+	}
+	if got := strings.Join(d.virtiofsArgs(), " "); strings.Contains(got, "-numa") {
+		t.Errorf("virtiofsArgs() = %q, want no memory backend with NUMA nodes", got)
+	}
+}
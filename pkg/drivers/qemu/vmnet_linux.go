@@ -0,0 +1,26 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qemu
+
+import (
+	"fmt"
+)
+
+// getIPAddressByMACAddress is unavailable, as vmnet only exists on macOS
+func getIPAddressByMACAddress(mac string) (string, error) {
+	return "", fmt.Errorf("%s networking is only supported on macOS", NetworkSocketVMnet)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// usbIDRegexp matches the vendor:product IDs of a USB device, as listed by lsusb
+var usbIDRegexp = regexp.MustCompile(`^([0-9a-f]{4}):([0-9a-f]{4})$`)
+
+// USBDevice is a USB device of the host, selected by its vendor and product IDs in hexadecimal
+type USBDevice struct {
+	Vendor  string
+	Product string
+}
+
+// ParseUSBDevice parses a USB device in the vendor:product format, such as 1050:0407
+func ParseUSBDevice(s string) (USBDevice, error) {
+	m := usbIDRegexp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return USBDevice{}, fmt.Errorf("%q is not a USB device in the vendor:product format, such as 1050:0407", s)
+	}
+	return USBDevice{Vendor: m[1], Product: m[2]}, nil
+}
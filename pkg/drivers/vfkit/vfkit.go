@@ -0,0 +1,431 @@
+// +build darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/log"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+	"k8s.io/minikube/pkg/drivers/hyperkit"
+)
+
+const (
+	isoFilename      = "boot2docker.iso"
+	pidFileName      = "vfkit.pid"
+	logFileName      = "vfkit.log"
+	consoleFileName  = "console.log"
+	efiStoreFileName = "efi-variable-store"
+	defaultCmdline   = "loglevel=3 user=docker console=hvc0 noembed nomodeset norestore waitusb=10 systemd.legacy_systemd_cgroup_controller=yes base"
+)
+
+// Driver is the machine driver for vfkit (Virtualization.framework)
+type Driver struct {
+	*drivers.BaseDriver
+	*pkgdrivers.CommonDriver
+
+	// How much memory, in MB, to allocate to the VM
+	Memory int
+
+	// How many cpus to allocate to the VM
+	CPU int
+
+	// The size of the disk to be created for the VM, in MB
+	DiskSize int
+
+	// A file or network URI to fetch the minikube ISO
+	Boot2DockerURL string
+
+	// The kernel command line, only used when booting the extracted kernel directly
+	Cmdline string
+
+	// The MAC address of the virtio NIC. If empty, a random MAC will be generated.
+	MACAddress string
+
+	// The local port vfkit serves its REST API on
+	RestfulPort int
+
+	// Host directories shared with the VM over virtiofs
+	VirtiofsShares []string
+}
+
+// NewDriver creates a new driver for a host
+func NewDriver(hostName, storePath string) *Driver {
+	return &Driver{
+		BaseDriver: &drivers.BaseDriver{
+			MachineName: hostName,
+			StorePath:   storePath,
+			SSHUser:     "docker",
+		},
+		CommonDriver: &pkgdrivers.CommonDriver{},
+	}
+}
+
+// DriverName returns the name of the driver
+func (d *Driver) DriverName() string {
+	return "vfkit"
+}
+
+// PreCreateCheck verifies that vfkit is installed
+func (d *Driver) PreCreateCheck() error {
+	if _, err := exec.LookPath("vfkit"); err != nil {
+		return errors.Wrap(err, "vfkit is required by the vfkit driver")
+	}
+	return nil
+}
+
+// Create a host using the driver's config
+func (d *Driver) Create() error {
+	if d.MACAddress == "" {
+		mac, err := pkgdrivers.RandomMAC()
+		if err != nil {
+			return errors.Wrap(err, "generating mac address")
+		}
+		d.MACAddress = mac
+	}
+	port, err := pkgdrivers.FreePort()
+	if err != nil {
+		return errors.Wrap(err, "allocating vfkit api port")
+	}
+	d.RestfulPort = port
+
+	if err := pkgdrivers.MakeDiskImage(d.BaseDriver, d.Boot2DockerURL, d.DiskSize); err != nil {
+		return errors.Wrap(err, "making disk image")
+	}
+
+	if useLinuxBootloader(runtime.GOARCH) {
+		isoPath := d.ResolveStorePath(isoFilename)
+		for _, f := range []struct {
+			pathInIso string
+			destPath  string
+		}{
+			{"/boot/bzimage", "bzimage"},
+			{"/boot/initrd", "initrd"},
+		} {
+			if err := hyperkit.ExtractFile(isoPath, f.pathInIso, d.ResolveStorePath(f.destPath)); err != nil {
+				return errors.Wrapf(err, "extracting %s", f.pathInIso)
+			}
+		}
+	}
+	return d.Start()
+}
+
+// useLinuxBootloader returns whether the kernel extracted from the ISO should be booted directly.
+// x86_64 guests boot the kernel via the linux bootloader, arm64 guests boot the ISO via EFI.
+func useLinuxBootloader(goarch string) bool {
+	return goarch != "arm64"
+}
+
+// GetIP returns an IP or hostname that this host is available at
+func (d *Driver) GetIP() (string, error) {
+	if d.IPAddress != "" {
+		return d.IPAddress, nil
+	}
+	return hyperkit.GetIPAddressByMACAddress(pkgdrivers.TrimMacAddress(d.MACAddress))
+}
+
+// GetSSHHostname returns hostname for use with ssh
+func (d *Driver) GetSSHHostname() (string, error) {
+	return d.GetIP()
+}
+
+// GetURL returns a Docker compatible host URL for connecting to this host
+func (d *Driver) GetURL() (string, error) {
+	ip, err := d.GetIP()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tcp://%s:2376", ip), nil
+}
+
+// GetState returns the state that the host is in (running, stopped, etc)
+func (d *Driver) GetState() (state.State, error) {
+	pid, err := d.getPid()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state.Stopped, nil
+		}
+		return state.Error, err
+	}
+	if !processRunning(pid) {
+		return state.Stopped, nil
+	}
+	vmState, err := d.getVMState()
+	if err != nil {
+		// vfkit is running, but has not started serving its API yet
+		log.Debugf("unable to query vfkit state: %v", err)
+		return state.Starting, nil
+	}
+	return machineState(vmState), nil
+}
+
+// machineState converts a Virtualization.framework state to a libmachine state
+func machineState(vmState string) state.State {
+	switch vmState {
+	case "VirtualMachineStateRunning":
+		return state.Running
+	case "VirtualMachineStateStarting", "VirtualMachineStateResuming":
+		return state.Starting
+	case "VirtualMachineStateStopping":
+		return state.Stopping
+	case "VirtualMachineStatePaused", "VirtualMachineStatePausing":
+		return state.Paused
+	case "VirtualMachineStateStopped":
+		return state.Stopped
+	case "VirtualMachineStateError":
+		return state.Error
+	default:
+		return state.None
+	}
+}
+
+// Start a host
+func (d *Driver) Start() error {
+	if pid, err := d.getPid(); err == nil {
+		if processRunning(pid) {
+			return fmt.Errorf("vfkit is already running with pid %d", pid)
+		}
+		log.Debugf("Removing stale pid file for pid %d", pid)
+		if err := os.Remove(d.ResolveStorePath(pidFileName)); err != nil {
+			return errors.Wrap(err, "removing stale pid file")
+		}
+	}
+
+	logFile, err := os.OpenFile(d.ResolveStorePath(logFileName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "opening log file")
+	}
+	defer logFile.Close()
+
+	args := d.args(runtime.GOARCH)
+	log.Debugf("Starting: vfkit %s", strings.Join(args, " "))
+	cmd := exec.Command("vfkit", args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// Detach from our process group, so that vfkit outlives minikube
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "starting vfkit")
+	}
+	if err := ioutil.WriteFile(d.ResolveStorePath(pidFileName), []byte(strconv.Itoa(cmd.Process.Pid)), 0600); err != nil {
+		return errors.Wrap(err, "writing pid file")
+	}
+	if err := cmd.Process.Release(); err != nil {
+		return errors.Wrap(err, "releasing vfkit process")
+	}
+
+	d.IPAddress = ""
+	for i := 0; i < 60; i++ {
+		st, err := d.GetState()
+		if err != nil {
+			return errors.Wrap(err, "get state")
+		}
+		if st == state.Stopped || st == state.Error {
+			return fmt.Errorf("vfkit exited unexpectedly, see %s", d.ResolveStorePath(logFileName))
+		}
+		if ip, err := hyperkit.GetIPAddressByMACAddress(pkgdrivers.TrimMacAddress(d.MACAddress)); err == nil && ip != "" {
+			d.IPAddress = ip
+			break
+		}
+		log.Debugf("Waiting for machine to come up %d/%d", i, 60)
+		time.Sleep(2 * time.Second)
+	}
+	if d.IPAddress == "" {
+		return fmt.Errorf("IP address never found in dhcp leases file for %s", d.MACAddress)
+	}
+	log.Debugf("IP: %s", d.IPAddress)
+
+	log.Info("Waiting for SSH to be available...")
+	if err := drivers.WaitForSSH(d); err != nil {
+		return errors.Wrap(err, "SSH not available after waiting")
+	}
+	return nil
+}
+
+// args returns the vfkit command line for this machine
+func (d *Driver) args(goarch string) []string {
+	args := []string{
+		"--cpus", strconv.Itoa(d.CPU),
+		"--memory", strconv.Itoa(d.Memory),
+		"--restful-uri", fmt.Sprintf("tcp://127.0.0.1:%d", d.RestfulPort),
+	}
+	if useLinuxBootloader(goarch) {
+		cmdline := d.Cmdline
+		if cmdline == "" {
+			cmdline = defaultCmdline
+		}
+		args = append(args,
+			"--bootloader", fmt.Sprintf("linux,kernel=%s,initrd=%s,cmdline=\"%s host=%s\"",
+				d.ResolveStorePath("bzimage"), d.ResolveStorePath("initrd"), cmdline, d.MachineName),
+			"--device", fmt.Sprintf("virtio-blk,path=%s", d.ResolveStorePath(isoFilename)))
+	} else {
+		args = append(args,
+			"--bootloader", fmt.Sprintf("efi,variable-store=%s,create", d.ResolveStorePath(efiStoreFileName)),
+			"--device", fmt.Sprintf("usb-mass-storage,path=%s", d.ResolveStorePath(isoFilename)))
+	}
+	args = append(args,
+		"--device", fmt.Sprintf("virtio-blk,path=%s", pkgdrivers.GetDiskPath(d.BaseDriver)),
+		"--device", fmt.Sprintf("virtio-net,nat,mac=%s", d.MACAddress),
+		"--device", "virtio-rng",
+		"--device", fmt.Sprintf("virtio-serial,logFilePath=%s", d.ResolveStorePath(consoleFileName)),
+	)
+	for _, share := range d.VirtiofsShares {
+		args = append(args, "--device", fmt.Sprintf("virtio-fs,sharedDir=%s,mountTag=%s", share, pkgdrivers.VirtiofsTag(share)))
+	}
+	return args
+}
+
+// Stop a host gracefully
+func (d *Driver) Stop() error {
+	s, err := d.GetState()
+	if err != nil {
+		return errors.Wrap(err, "getting state of VM")
+	}
+	if s == state.Stopped {
+		return nil
+	}
+	if err := d.setVMState("Stop"); err != nil {
+		log.Warnf("graceful shutdown failed, killing VM: %v", err)
+		return d.Kill()
+	}
+	for i := 0; i < 60; i++ {
+		s, err := d.GetState()
+		if err != nil {
+			return errors.Wrap(err, "getting state of VM")
+		}
+		if s == state.Stopped {
+			d.IPAddress = ""
+			return nil
+		}
+		log.Infof("Waiting for machine to stop %d/%d", i, 60)
+		time.Sleep(1 * time.Second)
+	}
+	return d.Kill()
+}
+
+// Kill stops a host forcefully
+func (d *Driver) Kill() error {
+	pid, err := d.getPid()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := d.setVMState("HardStop"); err != nil {
+		log.Debugf("HardStop failed, sending SIGKILL: %v", err)
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && processRunning(pid) {
+			return errors.Wrapf(err, "killing vfkit pid %d", pid)
+		}
+	}
+	d.IPAddress = ""
+	if err := os.Remove(d.ResolveStorePath(pidFileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Remove a host
+func (d *Driver) Remove() error {
+	s, err := d.GetState()
+	if err != nil {
+		log.Debugf("Error checking machine status: %v, assuming it has been removed already", err)
+	}
+	if s != state.Stopped {
+		return d.Kill()
+	}
+	return nil
+}
+
+// Restart a host
+func (d *Driver) Restart() error {
+	return pkgdrivers.Restart(d)
+}
+
+func (d *Driver) getPid() (int, error) {
+	bs, err := ioutil.ReadFile(d.ResolveStorePath(pidFileName))
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(bs)))
+	if err != nil {
+		return 0, errors.Wrap(err, "parsing pid file")
+	}
+	return pid, nil
+}
+
+type vmState struct {
+	State string `json:"state"`
+}
+
+// getVMState queries the vfkit REST API for the VM state
+func (d *Driver) getVMState() (string, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(d.restfulURL())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var vs vmState
+	if err := json.NewDecoder(resp.Body).Decode(&vs); err != nil {
+		return "", errors.Wrap(err, "decoding vm state")
+	}
+	return vs.State, nil
+}
+
+// setVMState requests a state change through the vfkit REST API
+func (d *Driver) setVMState(s string) error {
+	body, err := json.Marshal(vmState{State: s})
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(d.restfulURL(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("vfkit returned %s for %s", resp.Status, s)
+	}
+	return nil
+}
+
+func (d *Driver) restfulURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d/vm/state", d.RestfulPort)
+}
+
+func processRunning(pid int) bool {
+	// Signal 0 performs error checking only, without sending a signal
+	return syscall.Kill(pid, syscall.Signal(0)) == nil
+}
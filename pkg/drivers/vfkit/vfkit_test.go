@@ -0,0 +1,77 @@
+// +build darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/state"
+	pkgdrivers "k8s.io/minikube/pkg/drivers"
+)
+
+func TestMachineState(t *testing.T) {
+	var tests = []struct {
+		vmState string
+		want    state.State
+	}{
+		{"VirtualMachineStateRunning", state.Running},
+		{"VirtualMachineStateStopped", state.Stopped},
+		{"VirtualMachineStatePaused", state.Paused},
+		{"VirtualMachineStateError", state.Error},
+		{"unknown", state.None},
+	}
+	for _, tc := range tests {
+		if got := machineState(tc.vmState); got != tc.want {
+			t.Errorf("machineState(%q) = %s, want %s", tc.vmState, got, tc.want)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	d := NewDriver("minikube", "/tmp/store")
+	d.CPU = 2
+	d.Memory = 2048
+	d.RestfulPort = 12345
+	d.MACAddress = "5a:94:ef:e4:0c:ee"
+	d.VirtiofsShares = []string{"/Users/me/src"}
+
+	var tests = []struct {
+		goarch     string
+		bootloader string
+		iso        string
+	}{
+		{"amd64", "--bootloader linux,kernel=", "--device virtio-blk,path=/tmp/store/machines/minikube/boot2docker.iso"},
+		{"arm64", "--bootloader efi,variable-store=", "--device usb-mass-storage,path=/tmp/store/machines/minikube/boot2docker.iso"},
+	}
+	for _, tc := range tests {
+		got := strings.Join(d.args(tc.goarch), " ")
+		for _, want := range []string{
+			"--cpus 2 --memory 2048 --restful-uri tcp://127.0.0.1:12345",
+			tc.bootloader,
+			tc.iso,
+			"--device virtio-net,nat,mac=5a:94:ef:e4:0c:ee",
+			"--device virtio-fs,sharedDir=/Users/me/src,mountTag=" + pkgdrivers.VirtiofsTag("/Users/me/src"),
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("args(%s) = %q, missing %q", tc.goarch, got, want)
+			}
+		}
+	}
+}
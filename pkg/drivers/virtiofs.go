@@ -0,0 +1,31 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drivers
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+)
+
+// VirtiofsTag returns the tag a host directory is exported to the guest with over virtiofs.
+// It is derived from the path, so that the guest side can find a share from the host path alone.
+func VirtiofsTag(hostPath string) string {
+	sum := sha256.Sum256([]byte(filepath.Clean(hostPath)))
+	// The kernel limits tags to 36 bytes
+	return fmt.Sprintf("minikube-%x", sum[:8])
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// syncInterval is how often services are checked for a missing or stale ingress
+const syncInterval = 5 * time.Second
+
+// nodePorts are ports used by the node itself, which a LoadBalancer service must not shadow
+var nodePorts = []int32{
+	22,    // sshd
+	2376,  // dockerd
+	2379,  // etcd client
+	2380,  // etcd peer
+	10250, // kubelet
+	10251, // kube-scheduler
+	10252, // kube-controller-manager
+	10256, // kube-proxy health check
+}
+
+// Controller gives services of type LoadBalancer the IP of the node as their ingress.
+// kube-proxy forwards traffic for a service's ingress IP and port to its endpoints, so once
+// the ingress is set the service is reachable on <node ip>:<service port> from the host.
+//
+// Each port can only be used by one service, so services that conflict with an older
+// service or with the node itself are left pending until the conflict goes away.
+type Controller struct {
+	client kubernetes.Interface
+	nodeIP string
+}
+
+// NewController creates a new Controller publishing services on nodeIP
+func NewController(client kubernetes.Interface, nodeIP string) *Controller {
+	return &Controller{client: client, nodeIP: nodeIP}
+}
+
+// StartLoadBalancerController runs the LoadBalancer controller in the cluster until the process exits
+func StartLoadBalancerController(nodeIP string) error {
+	glog.Infof("Initializing the minikube LoadBalancer controller for %s ...", nodeIP)
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error creating client: %v", err)
+	}
+	NewController(clientset, nodeIP).Run(wait.NeverStop)
+	return nil
+}
+
+// Run syncs services until stop is closed
+func (c *Controller) Run(stop <-chan struct{}) {
+	wait.Until(func() {
+		if err := c.Sync(); err != nil {
+			glog.Errorf("error syncing services: %v", err)
+		}
+	}, syncInterval, stop)
+}
+
+// Sync sets or clears the ingress of every service of type LoadBalancer
+func (c *Controller) Sync() error {
+	reserved, err := c.reservedPorts()
+	if err != nil {
+		return err
+	}
+	list, err := c.client.CoreV1().Services(meta.NamespaceAll).List(meta.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("error listing services: %v", err)
+	}
+
+	services := []core.Service{}
+	for _, svc := range list.Items {
+		if svc.Spec.Type == core.ServiceTypeLoadBalancer {
+			services = append(services, svc)
+		}
+	}
+	// Older services keep their ports when a newer one asks for the same
+	sort.Slice(services, func(i, j int) bool {
+		ti, tj := services[i].CreationTimestamp, services[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return key(&services[i]) < key(&services[j])
+	})
+
+	owners := map[string]string{}
+	for i := range services {
+		svc := &services[i]
+		conflict := c.conflict(svc, reserved, owners)
+		if conflict == "" {
+			for _, p := range svc.Spec.Ports {
+				owners[portKey(p.Protocol, p.Port)] = key(svc)
+			}
+		} else {
+			glog.Warningf("%s: %s, leaving it pending", key(svc), conflict)
+		}
+		if err := c.updateIngress(svc, conflict == ""); err != nil {
+			glog.Errorf("error updating %s: %v", key(svc), err)
+		}
+	}
+	return nil
+}
+
+// conflict returns why a service cannot be published, or "" if it can be
+func (c *Controller) conflict(svc *core.Service, reserved map[string]bool, owners map[string]string) string {
+	if svc.Spec.LoadBalancerIP != "" && svc.Spec.LoadBalancerIP != c.nodeIP {
+		return fmt.Sprintf("requested IP %s is not the node IP %s", svc.Spec.LoadBalancerIP, c.nodeIP)
+	}
+	for _, p := range svc.Spec.Ports {
+		k := portKey(p.Protocol, p.Port)
+		if reserved[k] {
+			return fmt.Sprintf("port %s is used by the node", k)
+		}
+		if owner, ok := owners[k]; ok {
+			return fmt.Sprintf("port %s is already used by %s", k, owner)
+		}
+	}
+	return ""
+}
+
+// updateIngress sets the ingress of a service to the node IP, or removes the node IP if the
+// service is not published. Ingresses set by others, such as minikube tunnel, are left alone then.
+func (c *Controller) updateIngress(svc *core.Service, publish bool) error {
+	got := svc.Status.LoadBalancer.Ingress
+	published := len(got) == 1 && got[0].IP == c.nodeIP
+	if publish == published {
+		return nil
+	}
+	want := []core.LoadBalancerIngress{}
+	if publish {
+		want = append(want, core.LoadBalancerIngress{IP: c.nodeIP})
+	} else {
+		for _, i := range got {
+			if i.IP != c.nodeIP {
+				want = append(want, i)
+			}
+		}
+		if len(want) == len(got) {
+			return nil
+		}
+	}
+	svc.Status.LoadBalancer.Ingress = want
+	if _, err := c.client.CoreV1().Services(svc.Namespace).UpdateStatus(svc); err != nil {
+		return err
+	}
+	if publish {
+		glog.Infof("Published %s on %s", key(svc), c.nodeIP)
+	} else {
+		glog.Infof("Removed the ingress of %s", key(svc))
+	}
+	return nil
+}
+
+// reservedPorts returns the ports of the node itself, including the port the apiserver listens on
+func (c *Controller) reservedPorts() (map[string]bool, error) {
+	reserved := map[string]bool{}
+	for _, p := range nodePorts {
+		reserved[portKey(core.ProtocolTCP, p)] = true
+	}
+	ep, err := c.client.CoreV1().Endpoints(meta.NamespaceDefault).Get("kubernetes", meta.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting apiserver endpoints: %v", err)
+	}
+	for _, s := range ep.Subsets {
+		for _, p := range s.Ports {
+			reserved[portKey(p.Protocol, p.Port)] = true
+		}
+	}
+	return reserved, nil
+}
+
+func key(svc *core.Service) string {
+	return svc.Namespace + "/" + svc.Name
+}
+
+func portKey(protocol core.Protocol, port int32) string {
+	if protocol == "" {
+		protocol = core.ProtocolTCP
+	}
+	return fmt.Sprintf("%d/%s", port, protocol)
+}
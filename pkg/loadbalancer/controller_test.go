@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const nodeIP = "192.168.39.10"
+
+func newService(name string, age time.Duration, svcType core.ServiceType, ports ...int32) *core.Service {
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: meta.NewTime(time.Now().Add(-age)),
+		},
+		Spec: core.ServiceSpec{Type: svcType},
+	}
+	for _, p := range ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, core.ServicePort{Port: p, Protocol: core.ProtocolTCP})
+	}
+	return svc
+}
+
+func apiserverEndpoints(port int32) *core.Endpoints {
+	return &core.Endpoints{
+		ObjectMeta: meta.ObjectMeta{Name: "kubernetes", Namespace: meta.NamespaceDefault},
+		Subsets: []core.EndpointSubset{{
+			Addresses: []core.EndpointAddress{{IP: nodeIP}},
+			Ports:     []core.EndpointPort{{Name: "https", Port: port, Protocol: core.ProtocolTCP}},
+		}},
+	}
+}
+
+func TestSync(t *testing.T) {
+	withIngress := newService("with-ingress", 5*time.Minute, core.ServiceTypeLoadBalancer, 9090)
+	withIngress.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: nodeIP}}
+	otherIP := newService("other-ip", time.Minute, core.ServiceTypeLoadBalancer, 7070)
+	otherIP.Spec.LoadBalancerIP = "10.0.0.1"
+
+	objects := []runtime.Object{
+		apiserverEndpoints(8443),
+		newService("web", 4*time.Minute, core.ServiceTypeLoadBalancer, 80, 443),
+		newService("web-copy", 3*time.Minute, core.ServiceTypeLoadBalancer, 80),
+		newService("apiserver-port", 2*time.Minute, core.ServiceTypeLoadBalancer, 8443),
+		newService("ssh", 2*time.Minute, core.ServiceTypeLoadBalancer, 22),
+		newService("cluster-ip", time.Minute, core.ServiceTypeClusterIP, 8080),
+		withIngress,
+		otherIP,
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	if err := NewController(client, nodeIP).Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	expected := map[string]bool{
+		"web":            true,
+		"web-copy":       false,
+		"apiserver-port": false,
+		"ssh":            false,
+		"cluster-ip":     false,
+		"with-ingress":   true,
+		"other-ip":       false,
+	}
+	for name, published := range expected {
+		svc, err := client.CoreV1().Services("default").Get(name, meta.GetOptions{})
+		if err != nil {
+			t.Fatalf("error getting %s: %v", name, err)
+		}
+		ingress := svc.Status.LoadBalancer.Ingress
+		got := len(ingress) == 1 && ingress[0].IP == nodeIP
+		if got != published {
+			t.Errorf("%s: published = %t, want %t (ingress: %v)", name, got, published, ingress)
+		}
+	}
+}
+
+func TestSyncRemovesIngressOnConflict(t *testing.T) {
+	old := newService("old", 2*time.Minute, core.ServiceTypeLoadBalancer, 80)
+	newer := newService("newer", time.Minute, core.ServiceTypeLoadBalancer, 80)
+	newer.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: nodeIP}}
+	tunneled := newService("tunneled", time.Minute, core.ServiceTypeLoadBalancer, 22)
+	tunneled.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: "10.96.0.20"}}
+	client := fake.NewSimpleClientset(apiserverEndpoints(8443), old, newer, tunneled)
+
+	if err := NewController(client, nodeIP).Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	svc, err := client.CoreV1().Services("default").Get("newer", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting service: %v", err)
+	}
+	if len(svc.Status.LoadBalancer.Ingress) != 0 {
+		t.Errorf("expected the ingress of the conflicting service to be removed, got: %v", svc.Status.LoadBalancer.Ingress)
+	}
+	svc, err = client.CoreV1().Services("default").Get("tunneled", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("error getting service: %v", err)
+	}
+	if len(svc.Status.LoadBalancer.Ingress) != 1 || svc.Status.LoadBalancer.Ingress[0].IP != "10.96.0.20" {
+		t.Errorf("expected the ingress set by the tunnel to be kept, got: %v", svc.Status.LoadBalancer.Ingress)
+	}
+}
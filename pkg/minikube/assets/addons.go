@@ -21,8 +21,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
@@ -35,6 +38,10 @@ type Addon struct {
 	Assets    []*BinAsset
 	enabled   bool
 	addonName string
+	// Images are loaded from the image cache when the addon is enabled
+	Images []string
+	// installed addons were added with 'minikube addons install', and are not bundled with minikube
+	installed bool
 }
 
 // NewAddon creates a new Addon
@@ -52,6 +59,54 @@ func (a *Addon) Name() string {
 	return a.addonName
 }
 
+// Installed returns whether the addon was installed by the user
+func (a *Addon) Installed() bool {
+	return a.installed
+}
+
+// imageRe matches the images of the containers of a manifest
+var imageRe = regexp.MustCompile(`(?m)^\s*(?:-\s+)?image:\s*["']?([^\s"'#]+)`)
+
+// PullImages returns the images to pull when the addon is enabled: Images, then the images of its manifests evaluated with data
+func (a *Addon) PullImages(data interface{}) ([]string, error) {
+	seen := map[string]bool{}
+	images := []string{}
+	add := func(img string) {
+		if !seen[img] {
+			seen[img] = true
+			images = append(images, img)
+		}
+	}
+	for _, img := range a.Images {
+		add(img)
+	}
+	for _, asset := range a.Assets {
+		contents, err := asset.Contents(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluate %s", asset.GetAssetName())
+		}
+		for _, m := range imageRe.FindAllSubmatch(contents, -1) {
+			add(string(m[1]))
+		}
+	}
+	return images, nil
+}
+
+// selectors are the labels of the addons whose workloads are not labelled with kubernetes.io/minikube-addons
+var selectors = map[string]string{
+	"ingress":             "app.kubernetes.io/name=nginx-ingress-controller",
+	"storage-provisioner": "integration-test=storage-provisioner",
+	"loadbalancer":        "integration-test=loadbalancer-controller",
+}
+
+// Selector returns the label selector of the workloads and pods run by the addon, which are ready once the addon is
+func (a *Addon) Selector() string {
+	if s, ok := selectors[a.addonName]; ok {
+		return s
+	}
+	return "kubernetes.io/minikube-addons=" + a.addonName
+}
+
 // IsEnabled checks if an Addon is enabled
 func (a *Addon) IsEnabled() (bool, error) {
 	addonStatusText, err := config.Get(a.addonName)
@@ -131,6 +186,32 @@ var Addons = map[string]*Addon{
 			"0640",
 			false),
 	}, false, "storage-provisioner-gluster"),
+	"longhorn": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/longhorn/longhorn-rbac.yaml.tmpl",
+			constants.AddonsPath,
+			"longhorn-rbac.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/longhorn/longhorn-crds.yaml.tmpl",
+			constants.AddonsPath,
+			"longhorn-crds.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/longhorn/longhorn.yaml.tmpl",
+			constants.AddonsPath,
+			"longhorn.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/longhorn/longhorn-storageclass.yaml.tmpl",
+			constants.AddonsPath,
+			"longhorn-storageclass.yaml",
+			"0640",
+			false),
+	}, false, "longhorn"),
 	"heapster": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/heapster/influx-grafana-rc.yaml.tmpl",
@@ -261,6 +342,14 @@ var Addons = map[string]*Addon{
 			"0640",
 			false),
 	}, false, "registry"),
+	"registry-proxy-cache": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/registry-proxy-cache/registry-proxy-cache.yaml.tmpl",
+			constants.AddonsPath,
+			"registry-proxy-cache.yaml",
+			"0640",
+			false),
+	}, false, "registry-proxy-cache"),
 	"registry-creds": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/registry-creds/registry-creds-rc.yaml.tmpl",
@@ -269,6 +358,82 @@ var Addons = map[string]*Addon{
 			"0640",
 			false),
 	}, false, "registry-creds"),
+	"cert-manager": {
+		Assets: []*BinAsset{
+			MustBinAsset(
+				"deploy/addons/cert-manager/cert-manager-crds.yaml.tmpl",
+				constants.AddonsPath,
+				"cert-manager-crds.yaml",
+				"0640",
+				false),
+			MustBinAsset(
+				"deploy/addons/cert-manager/cert-manager.yaml.tmpl",
+				constants.AddonsPath,
+				"cert-manager.yaml",
+				"0640",
+				false),
+		},
+		addonName: "cert-manager",
+		// Cached on the host, so that they are not downloaded again for every new cluster
+		Images: []string{
+			"quay.io/jetstack/cert-manager-controller:v0.10.1",
+			"quay.io/jetstack/cert-manager-cainjector:v0.10.1",
+		},
+	},
+	"cert-manager-issuer": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/cert-manager/cert-manager-issuer.yaml.tmpl",
+			constants.AddonsPath,
+			"cert-manager-issuer.yaml",
+			"0640",
+			false),
+	}, false, "cert-manager-issuer"),
+	"argocd": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/argocd/argocd-crds.yaml.tmpl",
+			constants.AddonsPath,
+			"argocd-crds.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/argocd/argocd-rbac.yaml.tmpl",
+			constants.AddonsPath,
+			"argocd-rbac.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/argocd/argocd.yaml.tmpl",
+			constants.AddonsPath,
+			"argocd.yaml",
+			"0640",
+			false),
+	}, false, "argocd"),
+	"knative": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/knative/knative-serving-crds.yaml.tmpl",
+			constants.AddonsPath,
+			"knative-serving-crds.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/knative/knative-serving-config.yaml.tmpl",
+			constants.AddonsPath,
+			"knative-serving-config.yaml",
+			"0640",
+			true),
+		MustBinAsset(
+			"deploy/addons/knative/knative-serving.yaml.tmpl",
+			constants.AddonsPath,
+			"knative-serving.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/knative/kourier.yaml.tmpl",
+			constants.AddonsPath,
+			"kourier.yaml",
+			"0640",
+			false),
+	}, false, "knative"),
 	"freshpod": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/freshpod/freshpod-rc.yaml.tmpl",
@@ -293,6 +458,36 @@ var Addons = map[string]*Addon{
 			"0640",
 			true),
 	}, false, "nvidia-gpu-device-plugin"),
+	"nvidia-device-plugin": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/gpu/nvidia-device-plugin.yaml.tmpl",
+			constants.AddonsPath,
+			"nvidia-device-plugin.yaml",
+			"0640",
+			false),
+	}, false, "nvidia-device-plugin"),
+	"rocm-gpu": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/rocm-gpu/rocm-device-plugin.yaml.tmpl",
+			constants.AddonsPath,
+			"rocm-device-plugin.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/rocm-gpu/rocm-node-labeller.yaml.tmpl",
+			constants.AddonsPath,
+			"rocm-node-labeller.yaml",
+			"0640",
+			false),
+	}, false, "rocm-gpu"),
+	"intel-gpu": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/intel-gpu/intel-gpu-plugin.yaml.tmpl",
+			constants.AddonsPath,
+			"intel-gpu-plugin.yaml",
+			"0640",
+			false),
+	}, false, "intel-gpu"),
 	"logviewer": NewAddon([]*BinAsset{
 		MustBinAsset(
 			"deploy/addons/logviewer/logviewer-dp-and-svc.yaml.tmpl",
@@ -327,6 +522,232 @@ var Addons = map[string]*Addon{
 			"0640",
 			false),
 	}, false, "gvisor"),
+	"kata-containers": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/kata-containers/kata-deploy.yaml.tmpl",
+			constants.AddonsPath,
+			"kata-deploy.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/kata-containers/kata-runtimeclasses.yaml.tmpl",
+			constants.AddonsPath,
+			"kata-runtimeclasses.yaml",
+			"0640",
+			false),
+	}, false, "kata-containers"),
+	"wasm": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/wasm/wasm-node-installer.yaml.tmpl",
+			constants.AddonsPath,
+			"wasm-node-installer.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/wasm/wasm-runtimeclasses.yaml.tmpl",
+			constants.AddonsPath,
+			"wasm-runtimeclasses.yaml",
+			"0640",
+			false),
+	}, false, "wasm"),
+	"multus": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/multus/multus-daemonset.yaml.tmpl",
+			constants.AddonsPath,
+			"multus-daemonset.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/multus/multus-networks.yaml.tmpl",
+			constants.AddonsPath,
+			"multus-networks.yaml",
+			"0640",
+			false),
+	}, false, "multus"),
+	"sriov-sim": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/sriov-sim/sriov-device-plugin.yaml.tmpl",
+			constants.AddonsPath,
+			"sriov-device-plugin.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/sriov-sim/sriov-cni.yaml.tmpl",
+			constants.AddonsPath,
+			"sriov-cni.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/sriov-sim/sriov-networks.yaml.tmpl",
+			constants.AddonsPath,
+			"sriov-networks.yaml",
+			"0640",
+			false),
+	}, false, "sriov-sim"),
+	"loadbalancer": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/loadbalancer/loadbalancer-controller.yaml.tmpl",
+			constants.AddonsPath,
+			"loadbalancer-controller.yaml",
+			"0640",
+			true),
+	}, false, "loadbalancer"),
+	"dex": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/dex/dex.yaml.tmpl",
+			constants.AddonsPath,
+			"dex.yaml",
+			"0640",
+			true),
+	}, false, "dex"),
+	"pull-secrets": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/pull-secrets/pull-secrets.yaml.tmpl",
+			constants.AddonsPath,
+			"pull-secrets.yaml",
+			"0640",
+			true),
+	}, false, "pull-secrets"),
+	"monitoring": NewAddon([]*BinAsset{
+		MustBinAsset(
+			"deploy/addons/monitoring/monitoring-ns.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-ns.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/prometheus-rbac.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-prometheus-rbac.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/prometheus-configmap.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-prometheus-configmap.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/prometheus-dp-and-svc.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-prometheus-dp-and-svc.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/node-exporter-ds.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-node-exporter-ds.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/kube-state-metrics.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-kube-state-metrics.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/grafana-configmap.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-grafana-configmap.yaml",
+			"0640",
+			false),
+		MustBinAsset(
+			"deploy/addons/monitoring/grafana-dp-and-svc.yaml.tmpl",
+			constants.AddonsPath,
+			"monitoring-grafana-dp-and-svc.yaml",
+			"0640",
+			false),
+	}, false, "monitoring"),
+}
+
+// addonDependencies are the addons which must be enabled before an addon
+var addonDependencies = map[string][]string{
+	// The GKE device plugin looks for the libraries that the driver installer puts in the VM
+	"nvidia-gpu-device-plugin": {"nvidia-driver-installer"},
+	// Multus attaches the virtual functions to the pods, as secondary interfaces
+	"sriov-sim": {"multus"},
+	// The ClusterIssuer is a resource of cert-manager
+	"cert-manager-issuer": {"cert-manager"},
+}
+
+// addonConflicts are sets of addons which can not be enabled together
+var addonConflicts = [][]string{
+	// Both advertise nvidia.com/gpu to the kubelet
+	{"nvidia-gpu-device-plugin", "nvidia-device-plugin"},
+	// Both listen on the ports 80 and 443 of the node
+	{"ingress", "knative"},
+}
+
+// Dependencies returns the addons which name depends on, directly or not, in the order they have to be enabled
+func Dependencies(name string) ([]string, error) {
+	return dependencies(name, addonDependencies)
+}
+
+func dependencies(name string, deps map[string][]string) ([]string, error) {
+	order := []string{}
+	done := map[string]bool{}
+	visiting := map[string]bool{}
+	var visit func(n string, path []string) error
+	visit = func(n string, path []string) error {
+		if done[n] {
+			return nil
+		}
+		path = append(path, n)
+		if visiting[n] {
+			return fmt.Errorf("addon dependency cycle: %s", strings.Join(path, " -> "))
+		}
+		visiting[n] = true
+		for _, d := range deps[n] {
+			if err := visit(d, path); err != nil {
+				return err
+			}
+		}
+		visiting[n] = false
+		done[n] = true
+		order = append(order, n)
+		return nil
+	}
+	if err := visit(name, nil); err != nil {
+		return nil, err
+	}
+	return order[:len(order)-1], nil
+}
+
+// Dependents returns the addons which depend directly on name
+func Dependents(name string) []string {
+	dependents := []string{}
+	for a, deps := range addonDependencies {
+		for _, d := range deps {
+			if d == name {
+				dependents = append(dependents, a)
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Conflicts returns the addons which can not be enabled together with name
+func Conflicts(name string) []string {
+	conflicts := []string{}
+	for _, set := range addonConflicts {
+		in := false
+		for _, a := range set {
+			if a == name {
+				in = true
+			}
+		}
+		if !in {
+			continue
+		}
+		for _, a := range set {
+			if a != name {
+				conflicts = append(conflicts, a)
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
 }
 
 // AddMinikubeDirAssets adds all addons and files to the list
@@ -397,10 +818,12 @@ func GenerateTemplateData(cfg config.KubernetesConfig) interface{} {
 		Arch            string
 		ExoticArch      string
 		ImageRepository string
+		NodeIP          string
 	}{
 		Arch:            a,
 		ExoticArch:      ea,
 		ImageRepository: cfg.ImageRepository,
+		NodeIP:          cfg.NodeIP,
 	}
 
 	return opts
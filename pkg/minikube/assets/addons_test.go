@@ -17,12 +17,18 @@ limitations under the License.
 package assets
 
 import (
+	"bytes"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -156,3 +162,159 @@ func TestAddMinikubeDirAssets(t *testing.T) {
 	}
 
 }
+
+func TestDependencies(t *testing.T) {
+	deps := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"c"},
+		"d": {"e"},
+		"e": {"d"},
+	}
+	var tests = []struct {
+		name    string
+		want    []string
+		wantErr bool
+	}{
+		{"a", []string{"c", "b"}, false},
+		{"b", []string{"c"}, false},
+		{"c", []string{}, false},
+		{"d", nil, true},
+	}
+	for _, tc := range tests {
+		got, err := dependencies(tc.name, deps)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("dependencies(%q) error = %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("dependencies(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDependenciesAndConflictsAreAddons(t *testing.T) {
+	for name, deps := range addonDependencies {
+		for _, n := range append([]string{name}, deps...) {
+			if _, ok := Addons[n]; !ok {
+				t.Errorf("dependency %s of %s is not an addon", n, name)
+			}
+		}
+		if _, err := Dependencies(name); err != nil {
+			t.Errorf("Dependencies(%q): %v", name, err)
+		}
+	}
+	for _, set := range addonConflicts {
+		for _, n := range set {
+			if _, ok := Addons[n]; !ok {
+				t.Errorf("conflicting %s is not an addon", n)
+			}
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	if got, want := Conflicts("nvidia-device-plugin"), []string{"nvidia-gpu-device-plugin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Conflicts() = %v, want %v", got, want)
+	}
+	if got := Conflicts("dashboard"); len(got) != 0 {
+		t.Errorf("Conflicts() = %v, want none", got)
+	}
+}
+
+func TestPullImages(t *testing.T) {
+	manifest := `kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: {{.ImageRepository}}app:v1
+      initContainers:
+      - image: "busybox:1.31" # init
+        name: init
+---
+kind: DaemonSet
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        image: {{.ImageRepository}}app:v1
+`
+	tmpl, err := NewBinAssetFromData([]byte(manifest), "app.yaml.tmpl", "/etc/kubernetes/addons", "app.yaml", "0640", true)
+	if err != nil {
+		t.Fatalf("NewBinAssetFromData: %v", err)
+	}
+	raw, err := NewBinAssetFromData([]byte("containers:\n- image: 'nginx:1.17'\n"), "raw.yaml", "/etc/kubernetes/addons", "raw.yaml", "0640", false)
+	if err != nil {
+		t.Fatalf("NewBinAssetFromData: %v", err)
+	}
+	a := &Addon{Assets: []*BinAsset{tmpl, raw}, Images: []string{"gcr.io/k8s-minikube/helper:v2", "nginx:1.17"}}
+
+	got, err := a.PullImages(struct{ ImageRepository string }{"registry.example.com/"})
+	if err != nil {
+		t.Fatalf("PullImages: %v", err)
+	}
+	want := []string{"gcr.io/k8s-minikube/helper:v2", "nginx:1.17", "registry.example.com/app:v1", "busybox:1.31"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PullImages() returned diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestAddonManifests(t *testing.T) {
+	var tests = []struct {
+		name  string
+		kinds []string
+	}{
+		{"longhorn", []string{"CustomResourceDefinition", "DaemonSet", "StorageClass"}},
+		{"cert-manager", []string{"CustomResourceDefinition", "Deployment"}},
+		{"cert-manager-issuer", []string{"ClusterIssuer"}},
+		{"monitoring", []string{"Namespace", "DaemonSet", "Deployment", "ConfigMap"}},
+		{"wasm", []string{"DaemonSet", "RuntimeClass"}},
+		{"multus", []string{"CustomResourceDefinition", "DaemonSet", "NetworkAttachmentDefinition"}},
+	}
+	data := GenerateTemplateData(config.KubernetesConfig{})
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, ok := Addons[tc.name]
+			if !ok {
+				t.Fatalf("%s is not an addon", tc.name)
+			}
+			if len(a.Assets) == 0 {
+				t.Fatalf("%s has no manifests", tc.name)
+			}
+			kinds := map[string]bool{}
+			for _, asset := range a.Assets {
+				contents, err := asset.Contents(data)
+				if err != nil {
+					t.Fatalf("Contents(%s): %v", asset.GetAssetName(), err)
+				}
+				d := yaml.NewDecoder(bytes.NewReader(contents))
+				for {
+					var doc map[string]interface{}
+					err := d.Decode(&doc)
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						t.Fatalf("parsing %s: %v", asset.GetAssetName(), err)
+					}
+					if doc == nil {
+						continue
+					}
+					if doc["apiVersion"] == nil || doc["kind"] == nil {
+						t.Errorf("%s has an object without apiVersion or kind: %v", asset.GetAssetName(), doc)
+					}
+					kinds[fmt.Sprint(doc["kind"])] = true
+				}
+			}
+			for _, k := range tc.kinds {
+				if !kinds[k] {
+					t.Errorf("%s has no %s", tc.name, k)
+				}
+			}
+			if _, err := a.PullImages(data); err != nil {
+				t.Errorf("PullImages: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// AddonMetadataName is the name of the file describing an addon bundle
+const AddonMetadataName = "addon.yaml"
+
+// validAddonName matches the names of installed addons, which are also used as directory and file names
+var validAddonName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// AddonMetadata describes an addon bundle. The other files of the bundle are its manifests.
+type AddonMetadata struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Images      []string `json:"images,omitempty"`
+}
+
+// InstalledAddonsDir returns the directory of the addons installed with 'minikube addons install'
+func InstalledAddonsDir() string {
+	return constants.MakeMiniPath("installed-addons")
+}
+
+// LoadInstalledAddons adds the installed addons to Addons, skipping those which can not be loaded
+func LoadInstalledAddons() error {
+	entries, err := ioutil.ReadDir(InstalledAddonsDir())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading installed addons")
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		if a, ok := Addons[e.Name()]; ok && !a.installed {
+			glog.Warningf("ignoring installed addon %s, which has the name of a bundled addon", e.Name())
+			continue
+		}
+		a, err := loadInstalledAddon(filepath.Join(InstalledAddonsDir(), e.Name()))
+		if err != nil {
+			glog.Warningf("ignoring installed addon %s: %v", e.Name(), err)
+			continue
+		}
+		Addons[a.addonName] = a
+	}
+	return nil
+}
+
+// loadInstalledAddon reads an addon bundle extracted in dir
+func loadInstalledAddon(dir string) (*Addon, error) {
+	m, err := readAddonMetadata(filepath.Join(dir, AddonMetadataName))
+	if err != nil {
+		return nil, err
+	}
+	if m.Name != filepath.Base(dir) {
+		return nil, fmt.Errorf("addon %s is installed in %s", m.Name, dir)
+	}
+	return newInstalledAddon(dir, m)
+}
+
+// newInstalledAddon returns the addon of the manifests in dir
+func newInstalledAddon(dir string, m AddonMetadata) (*Addon, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	a := &Addon{addonName: m.Name, Images: m.Images, installed: true}
+	for _, e := range entries {
+		if e.Name() == AddonMetadataName || !isManifest(e.Name()) {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		isTemplate := strings.HasSuffix(e.Name(), ".tmpl")
+		target := m.Name + "-" + strings.TrimSuffix(e.Name(), ".tmpl")
+		asset, err := NewBinAssetFromData(b, filepath.Join(dir, e.Name()), constants.AddonsPath, target, "0640", isTemplate)
+		if err != nil {
+			return nil, errors.Wrapf(err, "manifest %s", e.Name())
+		}
+		a.Assets = append(a.Assets, asset)
+	}
+	if len(a.Assets) == 0 {
+		return nil, fmt.Errorf("addon %s has no manifests", m.Name)
+	}
+	return a, nil
+}
+
+func readAddonMetadata(path string) (AddonMetadata, error) {
+	m := AddonMetadata{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	j, err := yaml.ToJSON(b)
+	if err != nil {
+		return m, errors.Wrap(err, AddonMetadataName)
+	}
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&m); err != nil {
+		return m, errors.Wrap(err, AddonMetadataName)
+	}
+	if !validAddonName.MatchString(m.Name) {
+		return m, fmt.Errorf("invalid addon name %q: it must consist of lower case alphanumeric characters or '-'", m.Name)
+	}
+	return m, nil
+}
+
+// isManifest returns whether the file name of an addon bundle is a manifest
+func isManifest(name string) bool {
+	for _, ext := range []string{".yaml", ".yml", ".json", ".yaml.tmpl", ".yml.tmpl"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallAddon extracts an addon bundle from an uncompressed tarball, replacing a previous installation of the addon.
+// The bundle is a flat directory with the metadata and the manifests of the addon.
+func InstallAddon(r io.Reader) (AddonMetadata, error) {
+	m := AddonMetadata{}
+	if err := os.MkdirAll(InstalledAddonsDir(), 0755); err != nil {
+		return m, err
+	}
+	tmp, err := ioutil.TempDir(InstalledAddonsDir(), ".install")
+	if err != nil {
+		return m, err
+	}
+	defer os.RemoveAll(tmp)
+
+	files := []string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, errors.Wrap(err, "reading addon bundle")
+		}
+		name := strings.TrimPrefix(hdr.Name, "./")
+		if hdr.Typeflag == tar.TypeDir && (name == "" || name == ".") {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg || strings.Contains(name, "/") || strings.HasPrefix(name, ".") {
+			return m, fmt.Errorf("unexpected entry in addon bundle: %s", hdr.Name)
+		}
+		if name != AddonMetadataName && !isManifest(name) {
+			return m, fmt.Errorf("%s is not a manifest: only .yaml, .yml, .json and .tmpl files are supported", name)
+		}
+		f, err := os.OpenFile(filepath.Join(tmp, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			return m, err
+		}
+		_, err = io.Copy(f, tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return m, errors.Wrapf(err, "extracting %s", name)
+		}
+		files = append(files, name)
+	}
+	glog.Infof("Extracted addon bundle: %v", files)
+
+	m, err = readAddonMetadata(filepath.Join(tmp, AddonMetadataName))
+	if err != nil {
+		return m, errors.Wrap(err, "not an addon bundle")
+	}
+	if a, ok := Addons[m.Name]; ok && !a.installed {
+		return m, fmt.Errorf("%s is the name of a bundled addon", m.Name)
+	}
+	if _, err := newInstalledAddon(tmp, m); err != nil {
+		return m, err
+	}
+
+	dst := filepath.Join(InstalledAddonsDir(), m.Name)
+	if err := os.RemoveAll(dst); err != nil {
+		return m, errors.Wrap(err, "removing previous installation")
+	}
+	return m, os.Rename(tmp, dst)
+}
+
+// UninstallAddon removes an installed addon
+func UninstallAddon(name string) error {
+	a, ok := Addons[name]
+	if !ok {
+		return fmt.Errorf("%s is not an addon", name)
+	}
+	if !a.installed {
+		return fmt.Errorf("%s is bundled with minikube, and can not be uninstalled", name)
+	}
+	if err := os.RemoveAll(filepath.Join(InstalledAddonsDir(), name)); err != nil {
+		return err
+	}
+	delete(Addons, name)
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// bundle returns an uncompressed tarball of files, in order
+func bundle(t *testing.T, files ...string) *bytes.Buffer {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	for i := 0; i < len(files); i += 2 {
+		if err := tw.WriteHeader(&tar.Header{Name: files[i], Mode: 0644, Size: int64(len(files[i+1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		if _, err := tw.Write([]byte(files[i+1])); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return &b
+}
+
+func TestInstallAddon(t *testing.T) {
+	home, err := setupTestDir()
+	if err != nil {
+		t.Fatalf("setupTestDir: %v", err)
+	}
+	defer os.RemoveAll(home)
+	defer delete(Addons, "team-tools")
+
+	metadata := "name: team-tools\ndescription: Internal tools\nimages:\n- example.com/tools:v1\n"
+	m, err := InstallAddon(bundle(t,
+		AddonMetadataName, metadata,
+		"./tools-dp.yaml", "kind: Deployment\n",
+		"tools-svc.yaml.tmpl", "kind: Service\n# {{.ImageRepository}}\n"))
+	if err != nil {
+		t.Fatalf("InstallAddon: %v", err)
+	}
+	want := AddonMetadata{Name: "team-tools", Description: "Internal tools", Images: []string{"example.com/tools:v1"}}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("InstallAddon() = %+v, want %+v", m, want)
+	}
+
+	if err := LoadInstalledAddons(); err != nil {
+		t.Fatalf("LoadInstalledAddons: %v", err)
+	}
+	a, ok := Addons["team-tools"]
+	if !ok {
+		t.Fatalf("team-tools was not loaded")
+	}
+	if !a.Installed() || !reflect.DeepEqual(a.Images, want.Images) {
+		t.Errorf("addon = %+v, want an installed addon with images %v", a, want.Images)
+	}
+	targets := []string{}
+	for _, asset := range a.Assets {
+		targets = append(targets, asset.GetTargetName())
+	}
+	if want := []string{"team-tools-tools-dp.yaml", "team-tools-tools-svc.yaml"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("targets = %v, want %v", targets, want)
+	}
+	if !a.Assets[1].IsTemplate() {
+		t.Errorf("%s is not a template", a.Assets[1].GetAssetName())
+	}
+
+	// Installing again replaces the previous version
+	if _, err := InstallAddon(bundle(t, AddonMetadataName, "name: team-tools\n", "tools.yaml", "kind: Deployment\n")); err != nil {
+		t.Fatalf("InstallAddon: %v", err)
+	}
+	if err := LoadInstalledAddons(); err != nil {
+		t.Fatalf("LoadInstalledAddons: %v", err)
+	}
+	if n := len(Addons["team-tools"].Assets); n != 1 {
+		t.Errorf("reinstalled addon has %d assets, want 1", n)
+	}
+
+	if err := UninstallAddon("team-tools"); err != nil {
+		t.Fatalf("UninstallAddon: %v", err)
+	}
+	if _, ok := Addons["team-tools"]; ok {
+		t.Errorf("team-tools is still an addon after UninstallAddon")
+	}
+	if err := UninstallAddon("dashboard"); err == nil {
+		t.Errorf("UninstallAddon(dashboard) = nil, want error")
+	}
+}
+
+func TestInstallAddonErrors(t *testing.T) {
+	home, err := setupTestDir()
+	if err != nil {
+		t.Fatalf("setupTestDir: %v", err)
+	}
+	defer os.RemoveAll(home)
+
+	var tests = []struct {
+		name  string
+		files []string
+	}{
+		{"no metadata", []string{"dp.yaml", "kind: Deployment\n"}},
+		{"no manifests", []string{AddonMetadataName, "name: tools\n"}},
+		{"invalid name", []string{AddonMetadataName, "name: ../tools\n", "dp.yaml", "kind: Deployment\n"}},
+		{"unknown field", []string{AddonMetadataName, "name: tools\nversion: 1\n", "dp.yaml", "kind: Deployment\n"}},
+		{"bundled name", []string{AddonMetadataName, "name: dashboard\n", "dp.yaml", "kind: Deployment\n"}},
+		{"subdirectory", []string{AddonMetadataName, "name: tools\n", "crds/crd.yaml", "kind: CustomResourceDefinition\n"}},
+		{"not a manifest", []string{AddonMetadataName, "name: tools\n", "install.sh", "rm -rf /\n"}},
+		{"empty manifest", []string{AddonMetadataName, "name: tools\n", "dp.yaml", ""}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := InstallAddon(bundle(t, tc.files...)); err == nil {
+				t.Errorf("InstallAddon() = nil, want error")
+			}
+		})
+	}
+	if err := LoadInstalledAddons(); err != nil {
+		t.Errorf("LoadInstalledAddons: %v", err)
+	}
+	if _, ok := Addons["tools"]; ok {
+		t.Errorf("a failed installation was loaded")
+	}
+}
@@ -110,6 +110,14 @@ func (f *FileAsset) Read(p []byte) (int, error) {
 	return f.reader.Read(p)
 }
 
+// Close closes the file of the asset
+func (f *FileAsset) Close() error {
+	if c, ok := f.reader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // MemoryAsset is a memory-based asset
 type MemoryAsset struct {
 	BaseAsset
@@ -145,6 +153,7 @@ type BinAsset struct {
 	BaseAsset
 	reader   io.Reader
 	template *template.Template
+	contents []byte
 	length   int
 }
 
@@ -172,6 +181,21 @@ func NewBinAsset(name, targetDir, targetName, permissions string, isTemplate boo
 	return m, err
 }
 
+// NewBinAssetFromData creates a new BinAsset from contents which are not bundled with minikube, such as installed addons
+func NewBinAssetFromData(contents []byte, name, targetDir, targetName, permissions string, isTemplate bool) (*BinAsset, error) {
+	m := &BinAsset{
+		BaseAsset: BaseAsset{
+			AssetName:   name,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+		template: nil,
+	}
+	err := m.loadContents(contents, isTemplate)
+	return m, err
+}
+
 func defaultValue(defValue string, val interface{}) string {
 	if val == nil {
 		return defValue
@@ -188,7 +212,10 @@ func (m *BinAsset) loadData(isTemplate bool) error {
 	if err != nil {
 		return err
 	}
+	return m.loadContents(contents, isTemplate)
+}
 
+func (m *BinAsset) loadContents(contents []byte, isTemplate bool) error {
 	if isTemplate {
 		tpl, err := template.New(m.AssetName).Funcs(template.FuncMap{"default": defaultValue}).Parse(string(contents))
 		if err != nil {
@@ -198,6 +225,7 @@ func (m *BinAsset) loadData(isTemplate bool) error {
 		m.template = tpl
 	}
 
+	m.contents = contents
 	m.length = len(contents)
 	m.reader = bytes.NewReader(contents)
 	glog.Infof("Created asset %s with %d bytes", m.AssetName, m.length)
@@ -227,6 +255,18 @@ func (m *BinAsset) Evaluate(data interface{}) (*MemoryAsset, error) {
 	return NewMemoryAsset(buf.Bytes(), m.GetTargetDir(), m.GetTargetName(), m.GetPermissions()), nil
 }
 
+// Contents returns the contents of the asset, evaluated with data if it is a template
+func (m *BinAsset) Contents(data interface{}) ([]byte, error) {
+	if !m.IsTemplate() {
+		return m.contents, nil
+	}
+	var buf bytes.Buffer
+	if err := m.template.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // GetLength returns length
 func (m *BinAsset) GetLength() int {
 	return m.length
@@ -0,0 +1,209 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the minikube commands which change a cluster, in a log which minikube events reads
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/version"
+)
+
+// The events of the commands in the audit log
+const (
+	Started   = "started"
+	Completed = "completed"
+	Failed    = "failed"
+)
+
+// Entry is an event of a minikube command which changes the cluster of a profile
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile"`
+	Command string    `json:"command"`
+	Event   string    `json:"event"`
+	User    string    `json:"user"`
+	Pid     int       `json:"pid"`
+	Version string    `json:"version"`
+	// Message is the error of a failed command
+	Message string `json:"message,omitempty"`
+}
+
+// Policy limits the growth of the audit log
+type Policy struct {
+	// MaxSize is the size above which the log is rotated, replacing the previously rotated log. Never if zero.
+	MaxSize int64
+	// MaxAge is how long the entries are kept when the log is rotated. Forever if zero.
+	MaxAge time.Duration
+}
+
+// DefaultPolicy keeps up to two logs of a megabyte, with the entries of the last 30 days
+var DefaultPolicy = Policy{MaxSize: 1 << 20, MaxAge: 30 * 24 * time.Hour}
+
+// Filter selects entries of the audit log. Empty fields select all the entries.
+type Filter struct {
+	Profile string
+	User    string
+	// Command selects the entries of a command, or of its subcommands, such as "addons" for "addons enable"
+	Command string
+	// Since selects the entries which were recorded after it
+	Since time.Time
+}
+
+// Match returns whether the filter selects an entry
+func (f Filter) Match(e Entry) bool {
+	if f.Profile != "" && e.Profile != f.Profile {
+		return false
+	}
+	if f.User != "" && e.User != f.User {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	return f.Since.IsZero() || !e.Time.Before(f.Since)
+}
+
+// NewEntry returns an event of a command which is run by the current process
+func NewEntry(profile string, command string, event string) Entry {
+	e := Entry{
+		Time:    time.Now().UTC(),
+		Profile: profile,
+		Command: command,
+		Event:   event,
+		Pid:     os.Getpid(),
+		Version: version.GetVersion(),
+	}
+	if u, err := user.Current(); err == nil {
+		e.User = u.Username
+	}
+	return e
+}
+
+// Path returns the audit log, which is shared by all the profiles. The rotated log has the same path, with a .1 suffix.
+func Path(miniHome ...string) string {
+	miniPath := constants.GetMinipath()
+	if len(miniHome) > 0 {
+		miniPath = miniHome[0]
+	}
+	return filepath.Join(miniPath, "logs", "audit.json")
+}
+
+// Log appends an entry to the audit log, as a JSON line. The log is rotated first if the entry would grow it above the maximum size of p.
+func Log(e Entry, p Policy, miniHome ...string) error {
+	path := Path(miniHome...)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "making logs dir")
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "encoding entry")
+	}
+	if fi, err := os.Stat(path); err == nil && p.MaxSize > 0 && fi.Size()+int64(len(b))+1 > p.MaxSize {
+		if err := rotate(path, p.MaxAge); err != nil {
+			return errors.Wrap(err, "rotating audit log")
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "opening audit log")
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return errors.Wrap(err, "writing audit log")
+	}
+	return f.Close()
+}
+
+// rotate replaces the rotated log with the entries of the log which are more recent than maxAge, and removes the log
+func rotate(path string, maxAge time.Duration) error {
+	entries, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	for _, e := range entries {
+		if maxAge > 0 && time.Since(e.Time) > maxAge {
+			continue
+		}
+		line, err := json.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, "encoding entry")
+		}
+		b = append(append(b, line...), '\n')
+	}
+	tmp := path + ".1.tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "writing rotated log")
+	}
+	if err := os.Rename(tmp, path+".1"); err != nil {
+		return errors.Wrap(err, "renaming rotated log")
+	}
+	return os.Remove(path)
+}
+
+// Read returns the entries of the rotated audit log and of the audit log, oldest first. There are none if the log does not exist yet.
+func Read(miniHome ...string) ([]Entry, error) {
+	path := Path(miniHome...)
+	rotated, err := readFile(path + ".1")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return append(rotated, entries...), nil
+}
+
+// readFile returns the entries of a log, or none if it does not exist
+func readFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "opening audit log")
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// A line may be partial if minikube was killed while writing it
+			glog.Warningf("skipping audit log entry %q: %v", scanner.Text(), err)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading audit log")
+	}
+	return entries, nil
+}
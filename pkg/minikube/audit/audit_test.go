@@ -0,0 +1,131 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLogRead(t *testing.T) {
+	home, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+
+	entries, err := Read(home)
+	if err != nil {
+		t.Fatalf("Read() of a missing log: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Read() of a missing log = %v, want none", entries)
+	}
+
+	started := Entry{Time: time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC), Profile: "minikube", Command: "start", Event: Started, User: "alice", Pid: 42, Version: "v1.3.0"}
+	failed := started
+	failed.Event = Failed
+	failed.Message = "Error starting cluster"
+	for _, e := range []Entry{started, failed} {
+		if err := Log(e, DefaultPolicy, home); err != nil {
+			t.Fatalf("Log(%v): %v", e, err)
+		}
+	}
+	// A partial line, as written by a process which was killed
+	f, err := os.OpenFile(filepath.Join(home, "logs", "audit.json"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := f.WriteString(`{"time":"2019-08-05T10:01:00Z","prof`); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	f.Close()
+
+	entries, err = Read(home)
+	if err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+	if diff := cmp.Diff([]Entry{started, failed}, entries); diff != "" {
+		t.Errorf("Read() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	home, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+
+	old := Entry{Time: time.Now().UTC().Add(-48 * time.Hour), Profile: "minikube", Command: "start", Event: Started}
+	recent := Entry{Time: time.Now().UTC().Add(-time.Hour), Profile: "minikube", Command: "start", Event: Completed}
+	latest := Entry{Time: time.Now().UTC(), Profile: "minikube", Command: "stop", Event: Started}
+	// Large enough for two entries, but not three
+	p := Policy{MaxSize: 300, MaxAge: 24 * time.Hour}
+	for _, e := range []Entry{old, recent, latest} {
+		if err := Log(e, p, home); err != nil {
+			t.Fatalf("Log(%v): %v", e, err)
+		}
+	}
+
+	rotated, err := readFile(Path(home) + ".1")
+	if err != nil {
+		t.Fatalf("readFile(): %v", err)
+	}
+	if len(rotated) != 1 || rotated[0].Event != Completed {
+		t.Errorf("rotated log = %v, want only the recent entry", rotated)
+	}
+	entries, err := Read(home)
+	if err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+	if len(entries) != 2 || entries[1].Command != "stop" {
+		t.Errorf("Read() = %v, want the recent and the latest entries", entries)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	at := time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC)
+	e := Entry{Time: at, Profile: "p1", Command: "addons enable", User: "alice"}
+	var tests = []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"all", Filter{}, true},
+		{"profile", Filter{Profile: "p2"}, false},
+		{"user", Filter{User: "alice"}, true},
+		{"other user", Filter{User: "bob"}, false},
+		{"command", Filter{Command: "addons enable"}, true},
+		{"parent command", Filter{Command: "addons"}, true},
+		{"prefix", Filter{Command: "add"}, false},
+		{"since", Filter{Since: at}, true},
+		{"later", Filter{Since: at.Add(time.Second)}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Match(e); got != tc.want {
+				t.Errorf("Match() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
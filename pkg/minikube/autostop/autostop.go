@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package autostop stops clusters whose API server is idle, and starts them again on the next request
+package autostop
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+const fileName = "auto-stop.json"
+
+// State is the auto-stop configuration of a profile, and the process which applies it
+type State struct {
+	Profile string
+	// After is how long the API server may be idle before the cluster is stopped
+	After time.Duration
+	// Restart is set if the cluster is started again by the next request to its API server
+	Restart bool
+	// Port is where the API server is proxied, on localhost
+	Port int
+	// Pid is the process proxying the API server and watching for idleness
+	Pid int `json:",omitempty"`
+}
+
+// Path returns the path of the auto-stop file of a profile
+func Path(profile string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), fileName)
+}
+
+// LogFilePath returns the path of the log file of the auto-stop process of a profile
+func LogFilePath(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), "auto-stop.log")
+}
+
+// Load returns the auto-stop state of a profile, or nil if auto-stop is disabled
+func Load(profile string, miniHome ...string) (*State, error) {
+	b, err := ioutil.ReadFile(Path(profile, miniHome...))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile, miniHome...))
+	}
+	return &s, nil
+}
+
+// Save writes the auto-stop state of a profile. A zero Port is replaced by a free port of localhost.
+func Save(s *State, miniHome ...string) error {
+	if s.Port == 0 {
+		port, err := freePort()
+		if err != nil {
+			return errors.Wrap(err, "finding a free port")
+		}
+		s.Port = port
+	}
+	if err := os.MkdirAll(constants.GetProfilePath(s.Profile, miniHome...), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(s.Profile, miniHome...), b, 0600)
+}
+
+// freePort returns a port of localhost which nothing listens on
+func freePort() (int, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer ln.Close()
+	return ln.Addr().(*net.TCPAddr).Port, nil
+}
+
+// Running returns whether the auto-stop process of the state is running
+func (s *State) Running() bool {
+	return daemon.Running(s.Pid)
+}
+
+// Start runs the auto-stop process of a profile in the background, and records its pid
+func Start(s *State) error {
+	pid, err := daemon.Start(LogFilePath(s.Profile), "auto-stop", "run", "--profile", s.Profile)
+	if err != nil {
+		return err
+	}
+	s.Pid = pid
+	return Save(s)
+}
+
+// Kill stops the auto-stop process of a profile, if there is one, and keeps its state for the next start
+func Kill(profile string) error {
+	s, err := Load(profile)
+	if err != nil || s == nil {
+		return err
+	}
+	if err := daemon.Kill(s.Pid); err != nil {
+		glog.Infof("killing auto-stop process %d: %v", s.Pid, err)
+	}
+	s.Pid = 0
+	return Save(s)
+}
+
+// Disable stops the auto-stop process of a profile, and removes its state
+func Disable(profile string) error {
+	if err := Kill(profile); err != nil {
+		return err
+	}
+	if err := os.Remove(Path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
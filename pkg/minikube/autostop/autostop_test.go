@@ -0,0 +1,184 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autostop
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestSaveLoad(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	if s, err := Load("p1"); err != nil || s != nil {
+		t.Fatalf("Load() = %v, %v, want nil", s, err)
+	}
+	want := &State{Profile: "p1", After: 30 * time.Minute, Restart: true}
+	if err := Save(want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if want.Port == 0 {
+		t.Errorf("Save did not allocate a port")
+	}
+	got, err := Load("p1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if got.Running() {
+		t.Errorf("Running() = true without a process")
+	}
+	if err := Disable("p1"); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	if _, err := os.Stat(Path("p1")); !os.IsNotExist(err) {
+		t.Errorf("%s was not removed: %v", Path("p1"), err)
+	}
+}
+
+// echoServer returns the address of a server which echoes the lines it reads
+func echoServer(t *testing.T) (string, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				r := bufio.NewReader(c)
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fmt.Fprint(c, l)
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() { ln.Close() }
+}
+
+// roundTrip sends a line through the listener and returns the reply
+func roundTrip(t *testing.T, addr string) string {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer c.Close()
+	fmt.Fprint(c, "ping\n")
+	if err := c.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline: %v", err)
+	}
+	l, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		return ""
+	}
+	return l
+}
+
+func TestListener(t *testing.T) {
+	target, stopServer := echoServer(t)
+	defer stopServer()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	var starts int32
+	l := NewListener(ln, func() (string, error) { return target, nil }, func() error {
+		atomic.AddInt32(&starts, 1)
+		return nil
+	})
+	go func() {
+		if err := l.Serve(); err != nil {
+			t.Logf("Serve: %v", err)
+		}
+	}()
+	defer l.Close()
+
+	if got := roundTrip(t, ln.Addr().String()); got != "ping\n" {
+		t.Errorf("reply = %q, want ping", got)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if idle := l.Idle(time.Now()); idle <= 0 || idle > time.Second {
+		t.Errorf("Idle() = %v after a request, want a few milliseconds", idle)
+	}
+	WaitIdle(l, 50*time.Millisecond, 10*time.Millisecond)
+	if idle := l.Idle(time.Now()); idle < 50*time.Millisecond {
+		t.Errorf("WaitIdle returned after %v", idle)
+	}
+
+	// A connection to the stopped cluster starts it
+	l.SetStopped(true)
+	if got := roundTrip(t, ln.Addr().String()); got != "ping\n" {
+		t.Errorf("reply after restart = %q, want ping", got)
+	}
+	if n := atomic.LoadInt32(&starts); n != 1 || l.Stopped() {
+		t.Errorf("starts = %d, stopped = %v, want 1 start", n, l.Stopped())
+	}
+}
+
+func TestListenerWithoutRestart(t *testing.T) {
+	target, stopServer := echoServer(t)
+	defer stopServer()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	l := NewListener(ln, func() (string, error) { return target, nil }, nil)
+	go func() {
+		if err := l.Serve(); err != nil {
+			t.Logf("Serve: %v", err)
+		}
+	}()
+	defer l.Close()
+
+	// Connections to the stopped cluster are closed
+	l.SetStopped(true)
+	if got := roundTrip(t, ln.Addr().String()); got != "" {
+		t.Errorf("reply while stopped = %q, want none", got)
+	}
+}
+
+func TestIdleWithOpenConnection(t *testing.T) {
+	l := NewListener(nil, nil, nil)
+	l.touch(1)
+	if idle := l.Idle(time.Now().Add(time.Hour)); idle != 0 {
+		t.Errorf("Idle() = %v with an open connection, want 0", idle)
+	}
+	l.touch(-1)
+	if idle := l.Idle(time.Now().Add(time.Hour)); idle < time.Hour {
+		t.Errorf("Idle() = %v, want an hour", idle)
+	}
+}
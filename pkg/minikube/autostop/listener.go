@@ -0,0 +1,189 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autostop
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// Listener proxies the connections to the API server, and tracks when it was last used.
+// TLS is not terminated, the certificate of the API server is valid for localhost.
+type Listener struct {
+	ln net.Listener
+	// target returns the address of the API server, which may change when the cluster restarts
+	target func() (string, error)
+	// start starts the stopped cluster, or is nil if the cluster is not restarted
+	start func() error
+
+	mu      sync.Mutex
+	last    time.Time
+	active  int
+	stopped bool
+
+	// startMu serializes the start of the cluster by concurrent connections
+	startMu sync.Mutex
+}
+
+// NewListener returns a Listener accepting the connections of ln
+func NewListener(ln net.Listener, target func() (string, error), start func() error) *Listener {
+	return &Listener{ln: ln, target: target, start: start, last: time.Now()}
+}
+
+// Serve accepts connections until the listener is closed
+func (l *Listener) Serve() error {
+	for {
+		c, err := l.ln.Accept()
+		if err != nil {
+			return err
+		}
+		go l.handle(c)
+	}
+}
+
+// Close stops accepting connections
+func (l *Listener) Close() error {
+	return l.ln.Close()
+}
+
+// Idle returns how long the API server has not been used at now
+func (l *Listener) Idle(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active > 0 {
+		return 0
+	}
+	return now.Sub(l.last)
+}
+
+// SetStopped records whether the cluster is stopped
+func (l *Listener) SetStopped(stopped bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stopped = stopped
+	l.last = time.Now()
+}
+
+// Stopped returns whether the cluster is stopped
+func (l *Listener) Stopped() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stopped
+}
+
+// touch records activity, and adds delta to the number of open connections
+func (l *Listener) touch(delta int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active += delta
+	l.last = time.Now()
+}
+
+// ensureStarted starts the cluster if it is stopped
+func (l *Listener) ensureStarted() error {
+	l.startMu.Lock()
+	defer l.startMu.Unlock()
+	if !l.Stopped() {
+		return nil
+	}
+	glog.Infof("Starting the cluster for a new connection")
+	if err := l.start(); err != nil {
+		return err
+	}
+	l.SetStopped(false)
+	return nil
+}
+
+// handle forwards a connection to the API server
+func (l *Listener) handle(c net.Conn) {
+	defer c.Close()
+	l.touch(1)
+	defer l.touch(-1)
+
+	if l.Stopped() {
+		if l.start == nil {
+			return
+		}
+		if err := l.ensureStarted(); err != nil {
+			glog.Errorf("starting the cluster: %v", err)
+			return
+		}
+	}
+	addr, err := l.target()
+	if err != nil {
+		glog.Errorf("getting the address of the API server: %v", err)
+		return
+	}
+	s, err := net.Dial("tcp", addr)
+	if err != nil {
+		glog.Errorf("connecting to %s: %v", addr, err)
+		return
+	}
+	defer s.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	pipe := func(dst net.Conn, src net.Conn) {
+		defer wg.Done()
+		if _, err := io.Copy(&activityWriter{w: dst, l: l}, src); err != nil {
+			glog.Infof("proxy: %v", err)
+		}
+		// Unblock the other direction once one side is done
+		var err error
+		if tc, ok := dst.(*net.TCPConn); ok {
+			err = tc.CloseWrite()
+		} else {
+			err = dst.Close()
+		}
+		if err != nil {
+			glog.Infof("proxy close: %v", err)
+		}
+	}
+	go pipe(s, c)
+	go pipe(c, s)
+	wg.Wait()
+}
+
+// activityWriter records the activity of the listener on every write
+type activityWriter struct {
+	w io.Writer
+	l *Listener
+}
+
+func (a *activityWriter) Write(p []byte) (int, error) {
+	a.l.touch(0)
+	return a.w.Write(p)
+}
+
+// WaitIdle blocks until the listener has been idle for d, checking every interval
+func WaitIdle(l *Listener, d time.Duration, interval time.Duration) {
+	for {
+		idle := l.Idle(time.Now())
+		if idle >= d {
+			return
+		}
+		wait := d - idle
+		if wait > interval {
+			wait = interval
+		}
+		time.Sleep(wait)
+	}
+}
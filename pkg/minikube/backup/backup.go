@@ -0,0 +1,352 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup saves and restores the state of a kubeadm cluster: an etcd snapshot, the certificates and the kubeadm configuration
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// manifestName is the name of the first entry of a backup
+	manifestName = "manifest.json"
+	// snapshotName is the name of the etcd snapshot in a backup
+	snapshotName = "etcd/snapshot.db"
+	// kubeadmName is the name of the kubeadm configuration in a backup
+	kubeadmName = "kubeadm.yaml"
+	// certsPrefix is the directory of the certificates in a backup
+	certsPrefix = "certs/"
+)
+
+// workDir is where backups are staged in the VM. It is in the etcd data directory,
+// which is mounted at the same path in the etcd container.
+var workDir = path.Join(constants.EtcdDataDir, "backup")
+
+// localCerts are the certificates which minikube keeps in its home, and copies to the VM on start
+var localCerts = []string{"ca.crt", "ca.key", "proxy-client-ca.crt", "proxy-client-ca.key"}
+
+// Manifest describes the cluster a backup was taken from
+type Manifest struct {
+	MinikubeVersion   string
+	KubernetesVersion string
+	NodeName          string
+	Created           time.Time
+	// Files are the names of the other entries of the backup
+	Files []string
+}
+
+// Save takes a snapshot of etcd, and writes it to dst with the certificates and kubeadm configuration of the cluster
+func Save(cr command.Runner, r cruntime.Manager, m Manifest, dst string) error {
+	id, err := etcdContainer(r)
+	if err != nil {
+		return err
+	}
+	snapshot := path.Join(workDir, "snapshot.db")
+	archive := path.Join(workDir, "backup.tar.gz")
+	defer func() {
+		if err := cr.Run("sudo rm -rf " + workDir); err != nil {
+			glog.Warningf("unable to remove %s: %v", workDir, err)
+		}
+	}()
+	if err := cr.Run("sudo mkdir -p " + workDir); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	glog.Infof("Taking etcd snapshot in container %s", id)
+	if err := cr.Run(r.ContainerExecCmd(id, etcdctl("snapshot save "+snapshot))); err != nil {
+		return errors.Wrap(err, "etcd snapshot")
+	}
+	c := fmt.Sprintf("sudo tar -C / -czf %s %s %s %s", util.ShellQuote(archive), strings.TrimPrefix(snapshot, "/"),
+		strings.TrimPrefix(path.Clean(util.DefaultCertPath), "/"), strings.TrimPrefix(constants.KubeadmConfigFile, "/"))
+	if err := cr.Run(c); err != nil {
+		return errors.Wrap(err, "tar")
+	}
+
+	// The archive is streamed to a local file, as it is binary and may be large. Its stderr, such as the warnings of
+	// sudo, must not be mixed into it: it is only read again if cat fails.
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), ".backup")
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	cat := "sudo cat " + util.ShellQuote(archive)
+	if err := cr.CombinedOutputTo(cat+" 2>/dev/null", tmp); err != nil {
+		stderr, _ := cr.CombinedOutput(cat + " >/dev/null")
+		return errors.Wrapf(err, "reading archive: %s", strings.TrimSpace(stderr))
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "seek")
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	if _, err := convert(f, m, tmp); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	return f.Close()
+}
+
+// convert writes a backup from the archive made in the VM
+func convert(w io.Writer, m Manifest, vm io.Reader) (Manifest, error) {
+	files, err := readTarball(vm, func(name string) (string, error) {
+		switch {
+		case name == strings.TrimPrefix(path.Join(workDir, "snapshot.db"), "/"):
+			return snapshotName, nil
+		case name == strings.TrimPrefix(constants.KubeadmConfigFile, "/"):
+			return kubeadmName, nil
+		case strings.HasPrefix("/"+name, util.DefaultCertPath):
+			return certsPrefix + strings.TrimPrefix("/"+name, util.DefaultCertPath), nil
+		}
+		return "", fmt.Errorf("unexpected file in VM archive: %s", name)
+	})
+	if err != nil {
+		return m, err
+	}
+	if _, ok := files[snapshotName]; !ok {
+		return m, errors.New("VM archive has no etcd snapshot")
+	}
+	m.Files = []string{}
+	for name := range files {
+		m.Files = append(m.Files, name)
+	}
+	sort.Strings(m.Files)
+	return m, write(w, m, files)
+}
+
+func write(w io.Writer, m Manifest, files map[string][]byte) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	entries := append([]string{manifestName}, m.Files...)
+	files[manifestName] = b
+	for _, name := range entries {
+		mode := int64(0644)
+		if strings.HasSuffix(name, ".key") || name == snapshotName {
+			mode = 0600
+		}
+		hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(files[name])), Typeflag: tar.TypeReg, ModTime: m.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Load reads a backup
+func Load(src string) (Manifest, map[string][]byte, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+func read(r io.Reader) (Manifest, map[string][]byte, error) {
+	m := Manifest{}
+	files, err := readTarball(r, func(name string) (string, error) {
+		if !validName(name) {
+			return "", fmt.Errorf("invalid file in backup: %s", name)
+		}
+		return name, nil
+	})
+	if err != nil {
+		return m, nil, err
+	}
+	b, ok := files[manifestName]
+	if !ok {
+		return m, nil, errors.New("not a minikube backup: no manifest")
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return m, nil, errors.Wrap(err, "manifest")
+	}
+	delete(files, manifestName)
+	if _, ok := files[snapshotName]; !ok {
+		return m, nil, errors.New("backup has no etcd snapshot")
+	}
+	return m, files, nil
+}
+
+// validName returns whether name may be restored from a backup
+func validName(name string) bool {
+	switch name {
+	case manifestName, snapshotName, kubeadmName:
+		return true
+	}
+	return strings.HasPrefix(name, certsPrefix) && path.Clean(name) == name && !strings.Contains(name, "..")
+}
+
+// readTarball reads the regular files of a gzipped tarball, renamed by rename
+func readTarball(r io.Reader, rename func(string) (string, error)) (map[string][]byte, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "gzip")
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "tar")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := rename(hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+		var b bytes.Buffer
+		if _, err := io.Copy(&b, tr); err != nil {
+			return nil, errors.Wrapf(err, "reading %s", hdr.Name)
+		}
+		files[name] = b.Bytes()
+	}
+}
+
+// Restore replaces the etcd data, certificates and kubeadm configuration of the cluster by those of a backup.
+// The etcd of the cluster must be running, as the snapshot is restored with its etcdctl.
+func Restore(cr command.Runner, r cruntime.Manager, k8s config.KubernetesConfig, files map[string][]byte) error {
+	id, err := etcdContainer(r)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := cr.Run("sudo rm -rf " + workDir); err != nil {
+			glog.Warningf("unable to remove %s: %v", workDir, err)
+		}
+	}()
+
+	for name, b := range files {
+		var target string
+		perms := "0644"
+		switch {
+		case name == snapshotName:
+			target = path.Join(workDir, "snapshot.db")
+		case name == kubeadmName:
+			target = constants.KubeadmConfigFile
+		case strings.HasPrefix(name, certsPrefix):
+			target = path.Join(util.DefaultCertPath, strings.TrimPrefix(name, certsPrefix))
+			if strings.HasSuffix(name, ".key") {
+				perms = "0600"
+			}
+		}
+		glog.Infof("Restoring %s to %s", name, target)
+		if err := cr.Copy(assets.NewMemoryAssetTarget(b, target, perms)); err != nil {
+			return errors.Wrapf(err, "copy %s", name)
+		}
+	}
+
+	// The certificates signed by the restored authorities are generated again, from the minikube home
+	for _, c := range localCerts {
+		b, ok := files[certsPrefix+c]
+		if !ok {
+			continue
+		}
+		if err := ioutil.WriteFile(filepath.Join(constants.GetMinipath(), c), b, 0600); err != nil {
+			return errors.Wrapf(err, "writing %s", c)
+		}
+	}
+	if err := bootstrapper.SetupCerts(cr, k8s); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+
+	restored := path.Join(workDir, "data")
+	peerURL := fmt.Sprintf("https://%s:2380", k8s.NodeIP)
+	c := fmt.Sprintf("snapshot restore %s --data-dir %s --name %s --initial-cluster %s=%s --initial-advertise-peer-urls %s",
+		path.Join(workDir, "snapshot.db"), restored, k8s.NodeName, k8s.NodeName, peerURL, peerURL)
+	glog.Infof("Restoring etcd snapshot in container %s", id)
+	if err := cr.Run(r.ContainerExecCmd(id, etcdctl(c))); err != nil {
+		return errors.Wrap(err, "etcd snapshot restore")
+	}
+
+	// Swap the data directory while nothing uses it, then let the kubelet start the control plane again
+	if err := cr.Run("sudo systemctl stop kubelet"); err != nil {
+		return errors.Wrap(err, "stopping kubelet")
+	}
+	ids, err := r.ListContainers("")
+	if err != nil {
+		return errors.Wrap(err, "list containers")
+	}
+	if err := r.StopContainers(ids); err != nil {
+		return errors.Wrap(err, "stop containers")
+	}
+	member := path.Join(constants.EtcdDataDir, "member")
+	if err := cr.Run(fmt.Sprintf("sudo rm -rf %s && sudo mv %s %s", member, path.Join(restored, "member"), member)); err != nil {
+		return errors.Wrap(err, "replacing etcd data")
+	}
+	return cr.Run("sudo systemctl start kubelet")
+}
+
+// etcdContainer returns the ID of the running etcd container
+func etcdContainer(r cruntime.Manager) (string, error) {
+	ids, err := r.ListContainers("etcd")
+	if err != nil {
+		return "", errors.Wrap(err, "list containers")
+	}
+	if len(ids) == 0 {
+		return "", errors.New("etcd is not running")
+	}
+	return ids[0], nil
+}
+
+// etcdctl returns the etcdctl command to run in the etcd container
+func etcdctl(args string) string {
+	certs := path.Join(util.DefaultCertPath, "etcd")
+	return fmt.Sprintf("ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 --cacert=%s --cert=%s --key=%s %s",
+		path.Join(certs, "ca.crt"), path.Join(certs, "healthcheck-client.crt"), path.Join(certs, "healthcheck-client.key"), args)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+// tarball returns a gzipped tarball of files, with a directory entry first
+func tarball(t *testing.T, files map[string]string, order []string) *bytes.Buffer {
+	var b bytes.Buffer
+	gw := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: "var/lib/minikube/certs/", Mode: 0755, Typeflag: tar.TypeDir}); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	for _, name := range order {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("WriteHeader: %v", err)
+		}
+		if _, err := tw.Write([]byte(files[name])); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return &b
+}
+
+func TestConvertAndRead(t *testing.T) {
+	vm := map[string]string{
+		"data/minikube/backup/snapshot.db":   "snapshot",
+		"var/lib/minikube/certs/ca.crt":      "ca",
+		"var/lib/minikube/certs/etcd/ca.key": "etcd ca key",
+		"var/lib/minikube/certs/sa.pub":      "sa",
+		"var/lib/kubeadm.yaml":               "kubeadm",
+	}
+	order := []string{"data/minikube/backup/snapshot.db", "var/lib/minikube/certs/ca.crt", "var/lib/minikube/certs/etcd/ca.key", "var/lib/minikube/certs/sa.pub", "var/lib/kubeadm.yaml"}
+	m := Manifest{MinikubeVersion: "v1.3.0", KubernetesVersion: "v1.15.2", NodeName: "minikube", Created: time.Unix(1565000000, 0).UTC()}
+
+	var b bytes.Buffer
+	m, err := convert(&b, m, tarball(t, vm, order))
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	wantFiles := []string{"certs/ca.crt", "certs/etcd/ca.key", "certs/sa.pub", "etcd/snapshot.db", "kubeadm.yaml"}
+	if !reflect.DeepEqual(m.Files, wantFiles) {
+		t.Errorf("Files = %v, want %v", m.Files, wantFiles)
+	}
+
+	got, files, err := read(&b)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("read() manifest = %+v, want %+v", got, m)
+	}
+	want := map[string][]byte{
+		"certs/ca.crt":      []byte("ca"),
+		"certs/etcd/ca.key": []byte("etcd ca key"),
+		"certs/sa.pub":      []byte("sa"),
+		"etcd/snapshot.db":  []byte("snapshot"),
+		"kubeadm.yaml":      []byte("kubeadm"),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("read() files = %q, want %q", files, want)
+	}
+}
+
+func TestSave(t *testing.T) {
+	vm := map[string]string{
+		"data/minikube/backup/snapshot.db": "snapshot",
+		"var/lib/minikube/certs/ca.crt":    "ca",
+		"var/lib/kubeadm.yaml":             "kubeadm",
+	}
+	order := []string{"data/minikube/backup/snapshot.db", "var/lib/minikube/certs/ca.crt", "var/lib/kubeadm.yaml"}
+	cmds := map[string]string{
+		"sudo mkdir -p /data/minikube/backup":                                                                       "",
+		"sudo rm -rf /data/minikube/backup":                                                                         "",
+		`docker ps -a --filter="name=k8s_etcd" --format="{{.ID}}"`:                                                  "abc\n",
+		"docker exec abc /bin/sh -c " + util.ShellQuote(etcdctl("snapshot save /data/minikube/backup/snapshot.db")): "",
+		"sudo tar -C / -czf '/data/minikube/backup/backup.tar.gz' data/minikube/backup/snapshot.db var/lib/minikube/certs var/lib/kubeadm.yaml": "",
+	}
+	dir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var tests = []struct {
+		name    string
+		archive string
+		want    []string
+	}{
+		{"saved", tarball(t, vm, order).String(), []string{"certs/ca.crt", "etcd/snapshot.db", "kubeadm.yaml"}},
+		{"no archive", "", nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cr := command.NewFakeCommandRunner()
+			cr.SetCommandToOutput(cmds)
+			if tc.archive != "" {
+				cr.SetCommandToOutput(map[string]string{"sudo cat '/data/minikube/backup/backup.tar.gz' 2>/dev/null": tc.archive})
+			}
+			r, err := cruntime.New(cruntime.Config{Type: "docker", Runner: cr})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			dst := filepath.Join(dir, tc.name+".tar.gz")
+			err = Save(cr, r, Manifest{NodeName: "minikube"}, dst)
+			if tc.want == nil {
+				if err == nil {
+					t.Errorf("Save() = nil, want error")
+				}
+				if _, err := os.Stat(dst); !os.IsNotExist(err) {
+					t.Errorf("Stat(%s) = %v, want not exist", dst, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			f, err := os.Open(dst)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			defer f.Close()
+			m, _, err := read(f)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if !reflect.DeepEqual(m.Files, tc.want) {
+				t.Errorf("Files = %v, want %v", m.Files, tc.want)
+			}
+			if files, _ := filepath.Glob(filepath.Join(dir, ".backup*")); len(files) > 0 {
+				t.Errorf("temporary files left: %v", files)
+			}
+		})
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		files map[string]string
+	}{
+		{"unexpected", map[string]string{"data/minikube/backup/snapshot.db": "snapshot", "etc/passwd": "root"}},
+		{"no snapshot", map[string]string{"var/lib/kubeadm.yaml": "kubeadm"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			order := []string{}
+			for name := range tc.files {
+				order = append(order, name)
+			}
+			var b bytes.Buffer
+			if _, err := convert(&b, Manifest{}, tarball(t, tc.files, order)); err == nil {
+				t.Errorf("convert() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	var tests = []struct {
+		name  string
+		files map[string]string
+	}{
+		{"no manifest", map[string]string{"etcd/snapshot.db": "snapshot"}},
+		{"no snapshot", map[string]string{"manifest.json": "{}"}},
+		{"outside certs", map[string]string{"manifest.json": "{}", "etcd/snapshot.db": "snapshot", "certs/../../etc/passwd": "root"}},
+		{"unknown", map[string]string{"manifest.json": "{}", "etcd/snapshot.db": "snapshot", "kubeconfig": "config"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			order := []string{}
+			for name := range tc.files {
+				order = append(order, name)
+			}
+			if _, _, err := read(tarball(t, tc.files, order)); err == nil {
+				t.Errorf("read() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestEtcdctl(t *testing.T) {
+	got := etcdctl("snapshot save /data/minikube/backup/snapshot.db")
+	want := "ETCDCTL_API=3 etcdctl --endpoints=https://127.0.0.1:2379 --cacert=/var/lib/minikube/certs/etcd/ca.crt --cert=/var/lib/minikube/certs/etcd/healthcheck-client.crt --key=/var/lib/minikube/certs/etcd/healthcheck-client.key snapshot save /data/minikube/backup/snapshot.db"
+	if got != want {
+		t.Errorf("etcdctl() = %q, want %q", got, want)
+	}
+}
@@ -18,6 +18,7 @@ package bootstrapper
 
 import (
 	"net"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -29,6 +30,8 @@ type LogOptions struct {
 	Lines int
 	// Follow is whether or not to actively follow the logs, as in tail -f.
 	Follow bool
+	// Since only includes the log lines of this recent period, if set.
+	Since time.Duration
 }
 
 // Bootstrapper contains all the methods needed to bootstrap a kubernetes cluster
@@ -50,6 +53,10 @@ type Bootstrapper interface {
 const (
 	// BootstrapperTypeKubeadm is the kubeadm bootstrapper type
 	BootstrapperTypeKubeadm = "kubeadm"
+	// BootstrapperTypeK3s is the k3s bootstrapper type
+	BootstrapperTypeK3s = "k3s"
+	// BootstrapperTypeK0s is the k0s bootstrapper type
+	BootstrapperTypeK0s = "k0s"
 )
 
 // GetCachedBinaryList returns the list of binaries
@@ -57,6 +64,10 @@ func GetCachedBinaryList(bootstrapper string) []string {
 	switch bootstrapper {
 	case BootstrapperTypeKubeadm:
 		return constants.GetKubeadmCachedBinaries()
+	case BootstrapperTypeK3s:
+		return constants.GetK3sCachedBinaries()
+	case BootstrapperTypeK0s:
+		return constants.GetK0sCachedBinaries()
 	default:
 		return []string{}
 	}
@@ -17,11 +17,16 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -131,7 +136,22 @@ func generateCerts(k8s config.KubernetesConfig) error {
 	apiServerIPs := append(
 		k8s.APIServerIPs,
 		[]net.IP{net.ParseIP(k8s.NodeIP), serviceIP, net.ParseIP("10.0.0.1")}...)
+	// A dual-stack cluster also has a kubernetes service IP in the second range
+	if cidrs := strings.Split(k8s.ServiceCIDR, ","); len(cidrs) > 1 {
+		ip, err := util.GetServiceClusterIP(cidrs[1])
+		if err != nil {
+			return errors.Wrap(err, "getting secondary service cluster ip")
+		}
+		apiServerIPs = append(apiServerIPs, ip)
+	}
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName)
+	for _, san := range k8s.ExtraSANs {
+		if ip := net.ParseIP(san); ip != nil {
+			apiServerIPs = append(apiServerIPs, ip)
+		} else {
+			apiServerNames = append(apiServerNames, san)
+		}
+	}
 	apiServerAlternateNames := append(
 		apiServerNames,
 		util.GetAlternateDNS(k8s.DNSDomain)...)
@@ -174,6 +194,21 @@ func generateCerts(k8s config.KubernetesConfig) error {
 		},
 	}
 
+	if k8s.CustomCACert != "" {
+		glog.Infof("Using custom CA %s", k8s.CustomCACert)
+		if err := util.ValidateCACert(k8s.CustomCACert, k8s.CustomCAKey); err != nil {
+			return errors.Wrap(err, "invalid custom CA")
+		}
+		for _, caCertSpec := range caCertSpecs {
+			if err := copyFile(k8s.CustomCACert, caCertSpec.certPath, 0644); err != nil {
+				return errors.Wrap(err, "copying custom CA certificate")
+			}
+			if err := copyFile(k8s.CustomCAKey, caCertSpec.keyPath, 0600); err != nil {
+				return errors.Wrap(err, "copying custom CA key")
+			}
+		}
+	}
+
 	for _, caCertSpec := range caCertSpecs {
 		if !(util.CanReadFile(caCertSpec.certPath) &&
 			util.CanReadFile(caCertSpec.keyPath)) {
@@ -186,6 +221,10 @@ func generateCerts(k8s config.KubernetesConfig) error {
 	}
 
 	for _, signedCertSpec := range signedCertSpecs {
+		if signedCertValid(signedCertSpec.certPath, signedCertSpec.keyPath, signedCertSpec.ips, signedCertSpec.alternateNames, signedCertSpec.caCertPath) {
+			glog.Infof("Keeping %s, which is up to date", signedCertSpec.certPath)
+			continue
+		}
 		if err := util.GenerateSignedCert(
 			signedCertSpec.certPath, signedCertSpec.keyPath, signedCertSpec.subject,
 			signedCertSpec.ips, signedCertSpec.alternateNames,
@@ -197,3 +236,87 @@ func generateCerts(k8s config.KubernetesConfig) error {
 
 	return nil
 }
+
+// signedCertValid returns whether a signed certificate can be kept: it is signed by the CA of caCertPath, it has the
+// given IPs and names, and it does not expire soon. Certificates regenerated on every start would change the files of
+// the VM, so that a restart could never skip the kubeadm phases.
+func signedCertValid(certPath, keyPath string, ips []net.IP, names []string, caCertPath string) bool {
+	if !util.CanReadFile(keyPath) {
+		return false
+	}
+	cert, err := readCert(certPath)
+	if err != nil {
+		glog.Infof("Regenerating %s: %v", certPath, err)
+		return false
+	}
+	ca, err := readCert(caCertPath)
+	if err != nil {
+		return false
+	}
+	if err := cert.CheckSignatureFrom(ca); err != nil {
+		glog.Infof("Regenerating %s, which is not signed by %s: %v", certPath, caCertPath, err)
+		return false
+	}
+	if cert.NotAfter.Before(time.Now().Add(CertExpiryWarning)) {
+		glog.Infof("Regenerating %s, which expires on %s", certPath, cert.NotAfter)
+		return false
+	}
+	want := []string{}
+	for _, ip := range ips {
+		if ip != nil {
+			want = append(want, ip.String())
+		}
+	}
+	got := []string{}
+	for _, ip := range cert.IPAddresses {
+		got = append(got, ip.String())
+	}
+	if !sameStrings(got, want) || !sameStrings(cert.DNSNames, names) {
+		glog.Infof("Regenerating %s, whose IPs %v and names %v changed", certPath, cert.IPAddresses, cert.DNSNames)
+		return false
+	}
+	return true
+}
+
+// readCert reads the first certificate of a PEM file
+func readCert(path string) (*x509.Certificate, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.Errorf("no certificate in %s", path)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// sameStrings returns whether a and b hold the same strings, ignoring their order and duplicates
+func sameStrings(a, b []string) bool {
+	set := func(l []string) map[string]bool {
+		m := map[string]bool{}
+		for _, s := range l {
+			m[s] = true
+		}
+		return m
+	}
+	sa, sb := set(a), set(b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for s := range sa {
+		if !sb[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// copyFile copies src to dst, replacing it
+func copyFile(src, dst string, perm os.FileMode) error {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, b, perm)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// CertExpiryWarning is how long before their expiry certificates are reported as expiring
+const CertExpiryWarning = 30 * 24 * time.Hour
+
+// KubeconfigDir is where kubeadm writes the kubeconfig files of the control plane in the VM
+const KubeconfigDir = "/etc/kubernetes"
+
+// CertExpiry is when a certificate of the cluster expires
+type CertExpiry struct {
+	// Name is the path of the certificate, or of the kubeconfig file which embeds it
+	Name     string    `json:"name"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// Expiring returns the certificates which expire before now + within, soonest first
+func Expiring(certs []CertExpiry, now time.Time, within time.Duration) []CertExpiry {
+	expiring := []CertExpiry{}
+	for _, c := range certs {
+		if c.NotAfter.Before(now.Add(within)) {
+			expiring = append(expiring, c)
+		}
+	}
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].NotAfter.Before(expiring[j].NotAfter) })
+	return expiring
+}
+
+// CheckCerts returns when the certificates of the VM, and the client certificates of the kubeconfig files of the
+// control plane, expire
+func CheckCerts(cmd command.Runner) ([]CertExpiry, error) {
+	certDir := strings.TrimSuffix(util.DefaultCertPath, "/")
+	globs := []string{
+		path.Join(certDir, "*.crt"),
+		path.Join(certDir, "etcd", "*.crt"),
+		path.Join(KubeconfigDir, "*.conf"),
+	}
+	// tail prints the name of every file before its content, to read them all at once
+	rr, err := cmd.CombinedOutput("sudo sh -c 'tail -n +1 " + strings.Join(globs, " ") + " 2>/dev/null; true'")
+	if err != nil {
+		return nil, errors.Wrap(err, "reading certificates")
+	}
+
+	certs := []CertExpiry{}
+	for name, data := range splitTailOutput(rr) {
+		var c []CertExpiry
+		var err error
+		if strings.HasSuffix(name, ".conf") {
+			c, err = kubeconfigCertExpiry(name, data)
+		} else {
+			c, err = pemCertExpiry(name, data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c...)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Name < certs[j].Name })
+	return certs, nil
+}
+
+// splitTailOutput splits the output of tail for several files into the content of each file
+func splitTailOutput(output string) map[string][]byte {
+	files := map[string][]byte{}
+	var name string
+	var buf bytes.Buffer
+	flush := func() {
+		if name != "" {
+			files[name] = append([]byte{}, buf.Bytes()...)
+		}
+		buf.Reset()
+	}
+	s := bufio.NewScanner(strings.NewReader(output))
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "==> ") && strings.HasSuffix(line, " <==") {
+			flush()
+			name = strings.TrimSuffix(strings.TrimPrefix(line, "==> "), " <==")
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+	flush()
+	return files
+}
+
+// pemCertExpiry returns when the first certificate of the PEM data expires
+func pemCertExpiry(name string, data []byte) ([]CertExpiry, error) {
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			return nil, errors.Errorf("no certificate in %s", name)
+		}
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(b.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", name)
+		}
+		return []CertExpiry{{Name: name, NotAfter: c.NotAfter}}, nil
+	}
+}
+
+// kubeconfigCertExpiry returns when the client certificates embedded in a kubeconfig file expire. The certificates
+// which are referenced by path, such as the one of the kubelet which rotates it, are skipped.
+func kubeconfigCertExpiry(name string, data []byte) ([]CertExpiry, error) {
+	kc, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", name)
+	}
+	certs := []CertExpiry{}
+	for _, u := range kc.AuthInfos {
+		if len(u.ClientCertificateData) == 0 {
+			continue
+		}
+		c, err := pemCertExpiry(name, u.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, c...)
+	}
+	return certs, nil
+}
+
+// CheckClientCert returns when the client certificate of kubectl on the host expires
+func CheckClientCert() (CertExpiry, error) {
+	p := constants.MakeMiniPath("client.crt")
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return CertExpiry{}, errors.Wrapf(err, "reading %s", p)
+	}
+	c, err := pemCertExpiry(p, data)
+	if err != nil {
+		return CertExpiry{}, err
+	}
+	return c[0], nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestCheckCerts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certs")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := util.GenerateCACert(filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert: %v", err)
+	}
+	if err := util.GenerateSignedCert(filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key"), "admin", nil, nil,
+		filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")); err != nil {
+		t.Fatalf("GenerateSignedCert: %v", err)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	client, err := ioutil.ReadFile(filepath.Join(dir, "client.crt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: %s
+- name: system:node:minikube
+  user:
+    client-certificate: /var/lib/kubelet/pki/kubelet-client-current.pem
+`, base64.StdEncoding.EncodeToString(client))
+	output := fmt.Sprintf("==> /var/lib/minikube/certs/ca.crt <==\n%s\n==> /etc/kubernetes/admin.conf <==\n%s\n==> /etc/kubernetes/kubelet.conf <==\n", ca, kubeconfig)
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo sh -c 'tail -n +1 /var/lib/minikube/certs/*.crt /var/lib/minikube/certs/etcd/*.crt /etc/kubernetes/*.conf 2>/dev/null; true'": output,
+	})
+	certs, err := CheckCerts(f)
+	if err != nil {
+		t.Fatalf("CheckCerts: %v", err)
+	}
+	if len(certs) != 2 || certs[0].Name != "/etc/kubernetes/admin.conf" || certs[1].Name != "/var/lib/minikube/certs/ca.crt" {
+		t.Fatalf("CheckCerts() = %v, want admin.conf and ca.crt", certs)
+	}
+
+	now := time.Now()
+	if got := Expiring(certs, now, CertExpiryWarning); len(got) != 0 {
+		t.Errorf("Expiring() = %v, want none", got)
+	}
+	if got := Expiring(certs, now, 2*365*24*time.Hour); len(got) != 1 || got[0].Name != "/etc/kubernetes/admin.conf" {
+		t.Errorf("Expiring() within 2 years = %v, want admin.conf", got)
+	}
+	if got := Expiring(certs, now, 20*365*24*time.Hour); len(got) != 2 || got[0].Name != "/etc/kubernetes/admin.conf" {
+		t.Errorf("Expiring() within 20 years = %v, want admin.conf then ca.crt", got)
+	}
+}
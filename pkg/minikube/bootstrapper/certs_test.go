@@ -17,6 +17,9 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
@@ -54,3 +57,80 @@ func TestSetupCerts(t *testing.T) {
 		}
 	}
 }
+
+func TestSetupCertsCustomCA(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	caDir, err := ioutil.TempDir("", "custom-ca")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(caDir)
+	caCert := filepath.Join(caDir, "corp.crt")
+	caKey := filepath.Join(caDir, "corp.key")
+	if err := util.GenerateCACert(caCert, caKey, "corpCA"); err != nil {
+		t.Fatalf("GenerateCACert: %v", err)
+	}
+
+	f := command.NewFakeCommandRunner()
+	k8s := config.KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		CustomCACert:  caCert,
+		CustomCAKey:   caKey,
+	}
+	if err := SetupCerts(f, k8s); err != nil {
+		t.Fatalf("SetupCerts: %v", err)
+	}
+
+	want, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	for _, cert := range []string{"ca.crt", "proxy-client-ca.crt"} {
+		got, err := f.GetFileToContents(filepath.Join(constants.GetMinipath(), cert))
+		if err != nil {
+			t.Fatalf("Cert not transferred: %s", cert)
+		}
+		if got != string(want) {
+			t.Errorf("%s is not the custom CA", cert)
+		}
+	}
+}
+
+func TestSetupCertsExtraSANs(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	f := command.NewFakeCommandRunner()
+	k8s := config.KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		ExtraSANs:     []string{"minikube.example.com", "192.168.1.10"},
+	}
+	if err := SetupCerts(f, k8s); err != nil {
+		t.Fatalf("SetupCerts: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(constants.GetMinipath(), "apiserver.crt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	b, _ := pem.Decode(data)
+	if b == nil {
+		t.Fatalf("apiserver.crt is not PEM encoded")
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	if err := c.VerifyHostname("minikube.example.com"); err != nil {
+		t.Errorf("VerifyHostname: %v", err)
+	}
+	if err := c.VerifyHostname("192.168.1.10"); err != nil {
+		t.Errorf("VerifyHostname: %v", err)
+	}
+}
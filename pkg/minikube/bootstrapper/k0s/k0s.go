@@ -0,0 +1,425 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k0s
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util"
+)
+
+// K0s is the extra-config component for flags passed to k0s controller itself
+const K0s = "k0s"
+
+// disabledComponents are k0s components that minikube provides through addons instead
+var disabledComponents = []string{"metrics-server"}
+
+// Bootstrapper is a bootstrapper using k0s
+type Bootstrapper struct {
+	c command.Runner
+}
+
+// NewK0sBootstrapper creates a new k0s.Bootstrapper
+func NewK0sBootstrapper(api libmachine.API) (*Bootstrapper, error) {
+	h, err := api.Load(config.GetMachineName())
+	if err != nil {
+		return nil, errors.Wrap(err, "getting api client")
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "command runner")
+	}
+	return &Bootstrapper{c: runner}, nil
+}
+
+// GetKubeletStatus returns the kubelet status, which is supervised by the k0s service
+func (k *Bootstrapper) GetKubeletStatus() (string, error) {
+	status, err := k.c.CombinedOutput(`sudo systemctl is-active k0s`)
+	if err != nil {
+		return "", errors.Wrap(err, "getting status")
+	}
+	switch strings.TrimSpace(status) {
+	case "active":
+		return state.Running.String(), nil
+	case "inactive":
+		return state.Stopped.String(), nil
+	case "activating":
+		return state.Starting.String(), nil
+	}
+	return state.Error.String(), nil
+}
+
+// GetAPIServerStatus returns the api-server status
+func (k *Bootstrapper) GetAPIServerStatus(ip net.IP, apiserverPort int) (string, error) {
+	url := fmt.Sprintf("https://%s:%d/healthz", ip, apiserverPort)
+	// To avoid: x509: certificate signed by unknown authority
+	tr := &http.Transport{
+		Proxy:           nil, // To avoid connectiv issue if http(s)_proxy is set.
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	client := &http.Client{Transport: tr}
+	resp, err := client.Get(url)
+	glog.Infof("%s response: %v %+v", url, err, resp)
+	// Connection refused, usually.
+	if err != nil {
+		return state.Stopped.String(), nil
+	}
+	defer resp.Body.Close()
+	// k0s disables anonymous access to healthz, so an authentication failure means it is serving
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return state.Error.String(), nil
+	}
+	return state.Running.String(), nil
+}
+
+// LogCommands returns a map of log type to a command which will display that log.
+func (k *Bootstrapper) LogCommands(o bootstrapper.LogOptions) map[string]string {
+	return map[string]string{
+		"k0s":   bootstrapper.JournalCmd("k0s", o),
+		"dmesg": bootstrapper.DmesgCmd(o),
+	}
+}
+
+// StartCluster starts the cluster
+func (k *Bootstrapper) StartCluster(k8s config.KubernetesConfig) error {
+	if err := k.installCA(); err != nil {
+		return errors.Wrap(err, "installing CA")
+	}
+	if err := k.c.Run("sudo systemctl enable k0s && sudo systemctl start k0s"); err != nil {
+		return errors.Wrap(err, "starting k0s")
+	}
+	return k.waitForAPIServer(k8s)
+}
+
+// installCA makes k0s sign its certificates with the minikube CA, so that the
+// kubeconfig and client certificate minikube generates on the host are trusted.
+// k0s only generates the CAs which are missing from its pki directory.
+func (k *Bootstrapper) installCA() error {
+	pkiDir := path.Join(constants.K0sDataDir, "pki")
+	certs := [][]string{
+		{"ca.crt", "ca.crt"},
+		{"ca.key", "ca.key"},
+		{"proxy-client-ca.crt", "front-proxy-ca.crt"},
+		{"proxy-client-ca.key", "front-proxy-ca.key"},
+	}
+	cmds := []string{fmt.Sprintf("sudo mkdir -p %s", pkiDir)}
+	for _, c := range certs {
+		cmds = append(cmds, fmt.Sprintf("sudo cp %s %s", path.Join(util.DefaultCertPath, c[0]), path.Join(pkiDir, c[1])))
+	}
+	cmd := strings.Join(cmds, " && ")
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s\n", cmd, out)
+	}
+	return nil
+}
+
+// WaitCluster blocks until Kubernetes appears to be healthy.
+func (k *Bootstrapper) WaitCluster(k8s config.KubernetesConfig) error {
+	out.T(out.WaitingPods, "Waiting for:")
+	client, err := util.GetClient()
+	if err != nil {
+		return errors.Wrap(err, "k8s client")
+	}
+
+	out.String(" apiserver")
+	if err := k.waitForAPIServer(k8s); err != nil {
+		return errors.Wrap(err, "waiting for apiserver")
+	}
+
+	// The control plane runs as processes of the k0s controller, so only DNS shows up as pods.
+	// With CNI, DNS is not scheduled until the user installs a network plugin.
+	if k8s.NetworkPlugin != "cni" {
+		out.String(" dns")
+		selector := labels.SelectorFromSet(labels.Set(map[string]string{"k8s-app": "kube-dns"}))
+		if err := util.WaitForPodsWithLabelRunning(client, "kube-system", selector); err != nil {
+			return errors.Wrap(err, "waiting for k8s-app=kube-dns")
+		}
+	}
+	out.Ln("")
+	return nil
+}
+
+// RestartCluster restarts the Kubernetes cluster configured by k0s
+func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
+	if err := k.installCA(); err != nil {
+		return errors.Wrap(err, "installing CA")
+	}
+	if err := k.c.Run("sudo systemctl restart k0s"); err != nil {
+		return errors.Wrap(err, "restarting k0s")
+	}
+	return k.waitForAPIServer(k8s)
+}
+
+// waitForAPIServer waits for the apiserver to start up
+func (k *Bootstrapper) waitForAPIServer(k8s config.KubernetesConfig) error {
+	glog.Infof("Waiting for apiserver ...")
+	return wait.PollImmediate(time.Millisecond*300, time.Minute*3, func() (bool, error) {
+		status, err := k.GetAPIServerStatus(net.ParseIP(k8s.NodeIP), k8s.NodePort)
+		glog.Infof("apiserver status: %s, err: %v", status, err)
+		if err != nil {
+			return false, err
+		}
+		return status == state.Running.String(), nil
+	})
+}
+
+// DeleteCluster removes the components that were started earlier
+func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
+	cmd := fmt.Sprintf("sudo systemctl stop k0s && sudo rm -rf %s /run/k0s", constants.K0sDataDir)
+	out, err := k.c.CombinedOutput(cmd)
+	if err != nil {
+		return errors.Wrapf(err, "k0s reset: %s\n%s\n", cmd, out)
+	}
+	return nil
+}
+
+// PullImages is a no-op: k0s pulls the images it needs when it starts
+func (k *Bootstrapper) PullImages(k8s config.KubernetesConfig) error {
+	return nil
+}
+
+// SetupCerts sets up certificates within the cluster.
+func (k *Bootstrapper) SetupCerts(k8s config.KubernetesConfig) error {
+	return bootstrapper.SetupCerts(k.c, k8s)
+}
+
+// UpdateCluster updates the cluster
+func (k *Bootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
+	service, err := NewK0sService(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating k0s service")
+	}
+	glog.Infof("k0s %s service:\n%s", cfg.KubernetesVersion, service)
+	clusterConfig, err := NewK0sConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating k0s config")
+	}
+	glog.Infof("k0s %s config:\n%s", cfg.KubernetesVersion, clusterConfig)
+
+	bin, err := machine.CacheBinary("k0s", cfg.KubernetesVersion, "linux", runtime.GOARCH)
+	if err != nil {
+		return errors.Wrap(err, "downloading k0s")
+	}
+	if err := machine.CopyBinary(k.c, "k0s", bin); err != nil {
+		return errors.Wrap(err, "copying k0s")
+	}
+
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte(service), constants.K0sServiceFile, "0640"),
+		assets.NewMemoryAssetTarget([]byte(clusterConfig), constants.K0sConfigFile, "0640"),
+	}
+	if err := bootstrapper.AddAddons(&files, assets.GenerateTemplateData(cfg)); err != nil {
+		return errors.Wrap(err, "adding addons")
+	}
+	for _, f := range files {
+		if err := k.c.Copy(f); err != nil {
+			return errors.Wrapf(err, "copy")
+		}
+	}
+	if err := k.c.Run("sudo systemctl daemon-reload"); err != nil {
+		return errors.Wrap(err, "reloading systemd")
+	}
+	return nil
+}
+
+// checkVersion returns an error unless k0s is released for the kubernetes version
+func checkVersion(k8s config.KubernetesConfig) error {
+	version, err := kubeadm.ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	if version.LT(semver.MustParse(strings.TrimPrefix(constants.OldestK0sKubernetesVersion, "v"))) {
+		return fmt.Errorf("k0s requires kubernetes %s or newer, got %s", constants.OldestK0sKubernetesVersion, k8s.KubernetesVersion)
+	}
+	return nil
+}
+
+// NewK0sService generates a systemd unit running the k0s controller, which also runs
+// the workloads, configured from the options present in the KubernetesConfig.
+func NewK0sService(k8s config.KubernetesConfig) (string, error) {
+	if err := checkVersion(k8s); err != nil {
+		return "", err
+	}
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Socket: k8s.CRISocket})
+	if err != nil {
+		return "", errors.Wrap(err, "runtime")
+	}
+
+	b := bytes.Buffer{}
+	opts := struct {
+		ExtraOptions string
+	}{
+		ExtraOptions: strings.Join(controllerFlags(k8s, r), " "),
+	}
+	if err := k0sServiceTemplate.Execute(&b, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// controllerFlags returns the flags for k0s controller
+func controllerFlags(k8s config.KubernetesConfig, r cruntime.Manager) []string {
+	flags := []string{
+		"--single",
+		fmt.Sprintf("--config=%s", constants.K0sConfigFile),
+		fmt.Sprintf("--data-dir=%s", constants.K0sDataDir),
+		fmt.Sprintf("--disable-components=%s", strings.Join(disabledComponents, ",")),
+	}
+
+	// k0s talks to docker through dockershim, and to CRI runtimes directly
+	if r.Name() == "Docker" {
+		flags = append(flags, "--cri-socket=docker:unix:///var/run/docker.sock")
+	} else {
+		flags = append(flags, fmt.Sprintf("--cri-socket=remote:unix://%s", r.SocketPath()))
+	}
+
+	extraOpts := k8s.ExtraOptions.AsMap()
+	kubelet := map[string]string{
+		// Addons are stored in /etc/kubernetes/manifests and applied by the addon manager pod
+		"pod-manifest-path": "/etc/kubernetes/manifests",
+	}
+	if k8s.NodeIP != "" {
+		kubelet["node-ip"] = k8s.NodeIP
+	}
+	if k8s.NodeName != "" {
+		kubelet["hostname-override"] = k8s.NodeName
+	}
+	for k, v := range componentArgs(k8s, kubeadm.Kubelet) {
+		kubelet[k] = v
+	}
+	// The kubelet flags are passed as a single argument, which systemd unquotes
+	flags = append(flags, fmt.Sprintf(`"--kubelet-extra-args=%s"`, strings.Join(toFlags(kubelet), " ")))
+
+	// k0s controller flags are passed through as is
+	flags = append(flags, toFlags(extraOpts.Get(K0s))...)
+	return flags
+}
+
+// clusterConfig is the data of the k0s ClusterConfig template
+type clusterConfig struct {
+	Address               string
+	Port                  int
+	SANs                  []string
+	APIServerArgs         map[string]string
+	ControllerManagerArgs map[string]string
+	SchedulerArgs         map[string]string
+	Provider              string
+	PodCIDR               string
+	ServiceCIDR           string
+	DNSDomain             string
+}
+
+// NewK0sConfig generates the k0s ClusterConfig from the options present in the KubernetesConfig
+func NewK0sConfig(k8s config.KubernetesConfig) (string, error) {
+	if err := checkVersion(k8s); err != nil {
+		return "", err
+	}
+	// In case of no port assigned, use util.APIServerPort
+	nodePort := k8s.NodePort
+	if nodePort <= 0 {
+		nodePort = util.APIServerPort
+	}
+	cc := clusterConfig{
+		Address:               k8s.NodeIP,
+		Port:                  nodePort,
+		SANs:                  tlsSANs(k8s),
+		APIServerArgs:         componentArgs(k8s, kubeadm.Apiserver),
+		ControllerManagerArgs: componentArgs(k8s, kubeadm.ControllerManager),
+		SchedulerArgs:         componentArgs(k8s, kubeadm.Scheduler),
+		// kube-router is the network plugin k0s installs by default
+		Provider:    "kuberouter",
+		PodCIDR:     k8s.ExtraOptions.Get("pod-network-cidr", kubeadm.Kubeadm),
+		ServiceCIDR: util.DefaultServiceCIDR,
+		DNSDomain:   k8s.DNSDomain,
+	}
+	if k8s.NetworkPlugin == "cni" {
+		cc.Provider = "custom"
+	}
+	if k8s.ServiceCIDR != "" {
+		cc.ServiceCIDR = k8s.ServiceCIDR
+	}
+
+	b := bytes.Buffer{}
+	if err := k0sConfigTemplate.Execute(&b, cc); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// componentArgs returns the extra-config options of a component, with the feature gates of the cluster
+func componentArgs(k8s config.KubernetesConfig, component string) map[string]string {
+	opts := map[string]string{}
+	for k, v := range k8s.ExtraOptions.AsMap().Get(component) {
+		opts[k] = v
+	}
+	if _, ok := opts["feature-gates"]; !ok && k8s.FeatureGates != "" {
+		opts["feature-gates"] = k8s.FeatureGates
+	}
+	return opts
+}
+
+// tlsSANs returns the extra names and addresses the apiserver certificate must be valid for
+func tlsSANs(k8s config.KubernetesConfig) []string {
+	names := []string{}
+	if k8s.APIServerName != "" && k8s.APIServerName != constants.APIServerName {
+		names = append(names, k8s.APIServerName)
+	}
+	names = append(names, k8s.APIServerNames...)
+	for _, ip := range k8s.APIServerIPs {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// toFlags converts options to sorted flags
+func toFlags(opts map[string]string) []string {
+	keys := []string{}
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	flags := []string{}
+	for _, k := range keys {
+		flags = append(flags, fmt.Sprintf("--%s=%s", k, opts[k]))
+	}
+	return flags
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k0s
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestNewK0sService(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         config.KubernetesConfig
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "docker",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				NodeName:          "minikube",
+				KubernetesVersion: constants.DefaultK0sKubernetesVersion,
+				ContainerRuntime:  "docker",
+			},
+			expected: `ExecStart=/usr/bin/k0s controller --single --config=/etc/k0s/k0s.yaml --data-dir=/var/lib/minikube/k0s --disable-components=metrics-server --cri-socket=docker:unix:///var/run/docker.sock "--kubelet-extra-args=--hostname-override=minikube --node-ip=192.168.1.100 --pod-manifest-path=/etc/kubernetes/manifests"` + "\n",
+		},
+		{
+			description: "containerd with extra options",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				NodeName:          "minikube",
+				KubernetesVersion: constants.DefaultK0sKubernetesVersion,
+				ContainerRuntime:  "containerd",
+				FeatureGates:      "EphemeralContainers=true",
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: K0s, Key: "debug", Value: "true"},
+					util.ExtraOption{Component: "kubelet", Key: "max-pods", Value: "200"},
+				},
+			},
+			expected: `ExecStart=/usr/bin/k0s controller --single --config=/etc/k0s/k0s.yaml --data-dir=/var/lib/minikube/k0s --disable-components=metrics-server --cri-socket=remote:unix:///run/containerd/containerd.sock "--kubelet-extra-args=--feature-gates=EphemeralContainers=true --hostname-override=minikube --max-pods=200 --node-ip=192.168.1.100 --pod-manifest-path=/etc/kubernetes/manifests" --debug=true` + "\n",
+		},
+		{
+			description: "too old",
+			cfg: config.KubernetesConfig{
+				KubernetesVersion: constants.DefaultKubernetesVersion,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := NewK0sService(test.cfg)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("expected error but got none, service: %s", got)
+			}
+			if test.shouldErr {
+				return
+			}
+			var execStart string
+			for _, line := range strings.SplitAfter(got, "\n") {
+				if strings.HasPrefix(line, "ExecStart=") {
+					execStart = line
+				}
+			}
+			if execStart != test.expected {
+				t.Errorf("ExecStart = %q, want %q", execStart, test.expected)
+			}
+		})
+	}
+}
+
+func TestNewK0sConfig(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         config.KubernetesConfig
+		expected    string
+	}{
+		{
+			description: "defaults",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				KubernetesVersion: constants.DefaultK0sKubernetesVersion,
+			},
+			expected: `apiVersion: k0s.k0sproject.io/v1beta1
+kind: ClusterConfig
+metadata:
+  name: minikube
+spec:
+  api:
+    address: 192.168.1.100
+    port: 8443
+  network:
+    provider: kuberouter
+    serviceCIDR: 10.96.0.0/12
+  telemetry:
+    enabled: false
+`,
+		},
+		{
+			description: "cni with extra options",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				KubernetesVersion: constants.DefaultK0sKubernetesVersion,
+				NetworkPlugin:     "cni",
+				DNSDomain:         "cluster.local",
+				FeatureGates:      "EphemeralContainers=true",
+				APIServerIPs:      []net.IP{net.ParseIP("127.0.0.1")},
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: "apiserver", Key: "audit-log-maxage", Value: "7"},
+					util.ExtraOption{Component: "kubeadm", Key: "pod-network-cidr", Value: "10.10.0.0/16"},
+				},
+			},
+			expected: `apiVersion: k0s.k0sproject.io/v1beta1
+kind: ClusterConfig
+metadata:
+  name: minikube
+spec:
+  api:
+    address: 192.168.1.100
+    port: 8443
+    sans:
+    - 127.0.0.1
+    extraArgs:
+      audit-log-maxage: "7"
+      feature-gates: "EphemeralContainers=true"
+  controllerManager:
+    extraArgs:
+      feature-gates: "EphemeralContainers=true"
+  scheduler:
+    extraArgs:
+      feature-gates: "EphemeralContainers=true"
+  network:
+    provider: custom
+    podCIDR: 10.10.0.0/16
+    serviceCIDR: 10.96.0.0/12
+    clusterDomain: cluster.local
+  telemetry:
+    enabled: false
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := NewK0sConfig(test.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.expected {
+				t.Errorf("NewK0sConfig() = %s, want %s", got, test.expected)
+			}
+		})
+	}
+}
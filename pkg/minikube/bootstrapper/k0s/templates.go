@@ -0,0 +1,87 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k0s
+
+import "text/template"
+
+var k0sServiceTemplate = template.Must(template.New("k0sServiceTemplate").Parse(`[Unit]
+Description=k0s: Zero Friction Kubernetes
+Documentation=https://docs.k0sproject.io
+After=network-online.target
+
+[Service]
+Type=simple
+ExecStartPre=-/sbin/modprobe br_netfilter
+ExecStartPre=-/sbin/modprobe overlay
+ExecStart=/usr/bin/k0s controller {{.ExtraOptions}}
+KillMode=process
+Delegate=yes
+LimitNOFILE=999999
+LimitNPROC=infinity
+LimitCORE=infinity
+TasksMax=infinity
+Restart=always
+StartLimitInterval=0
+RestartSec=1s
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// k0sConfigTemplate is the k0s ClusterConfig. Sections which only hold defaults are left out, so that k0s fills them in.
+var k0sConfigTemplate = template.Must(template.New("k0sConfigTemplate").Parse(`
+{{- define "extraArgs"}}
+    extraArgs:
+{{- range $k, $v := .}}
+      {{$k}}: {{printf "%q" $v}}
+{{- end}}
+{{- end -}}
+apiVersion: k0s.k0sproject.io/v1beta1
+kind: ClusterConfig
+metadata:
+  name: minikube
+spec:
+  api:
+{{- if .Address}}
+    address: {{.Address}}
+{{- end}}
+    port: {{.Port}}
+{{- if .SANs}}
+    sans:
+{{- range .SANs}}
+    - {{.}}
+{{- end}}
+{{- end}}
+{{- if .APIServerArgs}}{{template "extraArgs" .APIServerArgs}}{{end}}
+{{- if .ControllerManagerArgs}}
+  controllerManager:{{template "extraArgs" .ControllerManagerArgs}}
+{{- end}}
+{{- if .SchedulerArgs}}
+  scheduler:{{template "extraArgs" .SchedulerArgs}}
+{{- end}}
+  network:
+    provider: {{.Provider}}
+{{- if .PodCIDR}}
+    podCIDR: {{.PodCIDR}}
+{{- end}}
+    serviceCIDR: {{.ServiceCIDR}}
+{{- if .DNSDomain}}
+    clusterDomain: {{.DNSDomain}}
+{{- end}}
+  telemetry:
+    enabled: false
+`))
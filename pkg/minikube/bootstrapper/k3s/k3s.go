@@ -0,0 +1,395 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k3s
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util"
+)
+
+// K3s is the extra-config component for flags passed to k3s server itself
+const K3s = "k3s"
+
+// componentArgs maps extra-config components to the k3s flag that forwards arguments to them
+var componentArgs = map[string]string{
+	kubeadm.Kubelet:           "kubelet-arg",
+	kubeadm.Apiserver:         "kube-apiserver-arg",
+	kubeadm.ControllerManager: "kube-controller-manager-arg",
+	kubeadm.Scheduler:         "kube-scheduler-arg",
+}
+
+// disabledComponents are packaged k3s components that minikube provides through addons instead
+var disabledComponents = []string{"traefik", "servicelb", "local-storage", "metrics-server"}
+
+// Bootstrapper is a bootstrapper using k3s
+type Bootstrapper struct {
+	c command.Runner
+}
+
+// NewK3sBootstrapper creates a new k3s.Bootstrapper
+func NewK3sBootstrapper(api libmachine.API) (*Bootstrapper, error) {
+	h, err := api.Load(config.GetMachineName())
+	if err != nil {
+		return nil, errors.Wrap(err, "getting api client")
+	}
+	runner, err := machine.CommandRunner(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "command runner")
+	}
+	return &Bootstrapper{c: runner}, nil
+}
+
+// GetKubeletStatus returns the kubelet status, which is embedded in the k3s service
+func (k *Bootstrapper) GetKubeletStatus() (string, error) {
+	status, err := k.c.CombinedOutput(`sudo systemctl is-active k3s`)
+	if err != nil {
+		return "", errors.Wrap(err, "getting status")
+	}
+	switch strings.TrimSpace(status) {
+	case "active":
+		return state.Running.String(), nil
+	case "inactive":
+		return state.Stopped.String(), nil
+	case "activating":
+		return state.Starting.String(), nil
+	}
+	return state.Error.String(), nil
+}
+
+// GetAPIServerStatus returns the api-server status
+func (k *Bootstrapper) GetAPIServerStatus(ip net.IP, apiserverPort int) (string, error) {
+	url := fmt.Sprintf("https://%s:%d/healthz", ip, apiserverPort)
+	// To avoid: x509: certificate signed by unknown authority
+	tr := &http.Transport{
+		Proxy:           nil, // To avoid connectiv issue if http(s)_proxy is set.
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	client := &http.Client{Transport: tr}
+	resp, err := client.Get(url)
+	glog.Infof("%s response: %v %+v", url, err, resp)
+	// Connection refused, usually.
+	if err != nil {
+		return state.Stopped.String(), nil
+	}
+	defer resp.Body.Close()
+	// k3s only answers healthz for authenticated clients, so any response means it is serving
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusUnauthorized {
+		return state.Error.String(), nil
+	}
+	return state.Running.String(), nil
+}
+
+// LogCommands returns a map of log type to a command which will display that log.
+func (k *Bootstrapper) LogCommands(o bootstrapper.LogOptions) map[string]string {
+	return map[string]string{
+		"k3s":   bootstrapper.JournalCmd("k3s", o),
+		"dmesg": bootstrapper.DmesgCmd(o),
+	}
+}
+
+// StartCluster starts the cluster
+func (k *Bootstrapper) StartCluster(k8s config.KubernetesConfig) error {
+	if err := k.installCA(); err != nil {
+		return errors.Wrap(err, "installing CA")
+	}
+	if err := k.c.Run("sudo systemctl enable k3s && sudo systemctl start k3s"); err != nil {
+		return errors.Wrap(err, "starting k3s")
+	}
+	return k.waitForAPIServer(k8s)
+}
+
+// installCA makes k3s sign its certificates with the minikube CA, so that the
+// kubeconfig and client certificate minikube generates on the host are trusted.
+func (k *Bootstrapper) installCA() error {
+	tlsDir := path.Join(constants.K3sDataDir, "server", "tls")
+	// The client CA is the minikube CA as well, so that the minikube client certificate is accepted
+	certs := [][]string{
+		{"ca.crt", "server-ca.crt"},
+		{"ca.key", "server-ca.key"},
+		{"ca.crt", "client-ca.crt"},
+		{"ca.key", "client-ca.key"},
+		{"proxy-client-ca.crt", "request-header-ca.crt"},
+		{"proxy-client-ca.key", "request-header-ca.key"},
+	}
+	cmds := []string{fmt.Sprintf("sudo mkdir -p %s", tlsDir)}
+	for _, c := range certs {
+		cmds = append(cmds, fmt.Sprintf("sudo cp %s %s", path.Join(util.DefaultCertPath, c[0]), path.Join(tlsDir, c[1])))
+	}
+	cmd := strings.Join(cmds, " && ")
+	if out, err := k.c.CombinedOutput(cmd); err != nil {
+		return errors.Wrapf(err, "cmd failed: %s\n%s\n", cmd, out)
+	}
+	return nil
+}
+
+// WaitCluster blocks until Kubernetes appears to be healthy.
+func (k *Bootstrapper) WaitCluster(k8s config.KubernetesConfig) error {
+	out.T(out.WaitingPods, "Waiting for:")
+	client, err := util.GetClient()
+	if err != nil {
+		return errors.Wrap(err, "k8s client")
+	}
+
+	out.String(" apiserver")
+	if err := k.waitForAPIServer(k8s); err != nil {
+		return errors.Wrap(err, "waiting for apiserver")
+	}
+
+	// The control plane runs inside the k3s process, so only DNS shows up as pods.
+	// With CNI, DNS is not scheduled until the user installs a network plugin.
+	if k8s.NetworkPlugin != "cni" {
+		out.String(" dns")
+		selector := labels.SelectorFromSet(labels.Set(map[string]string{"k8s-app": "kube-dns"}))
+		if err := util.WaitForPodsWithLabelRunning(client, "kube-system", selector); err != nil {
+			return errors.Wrap(err, "waiting for k8s-app=kube-dns")
+		}
+	}
+	out.Ln("")
+	return nil
+}
+
+// RestartCluster restarts the Kubernetes cluster configured by k3s
+func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
+	if err := k.installCA(); err != nil {
+		return errors.Wrap(err, "installing CA")
+	}
+	if err := k.c.Run("sudo systemctl restart k3s"); err != nil {
+		return errors.Wrap(err, "restarting k3s")
+	}
+	return k.waitForAPIServer(k8s)
+}
+
+// waitForAPIServer waits for the apiserver to start up
+func (k *Bootstrapper) waitForAPIServer(k8s config.KubernetesConfig) error {
+	glog.Infof("Waiting for apiserver ...")
+	return wait.PollImmediate(time.Millisecond*300, time.Minute*3, func() (bool, error) {
+		status, err := k.GetAPIServerStatus(net.ParseIP(k8s.NodeIP), k8s.NodePort)
+		glog.Infof("apiserver status: %s, err: %v", status, err)
+		if err != nil {
+			return false, err
+		}
+		return status == state.Running.String(), nil
+	})
+}
+
+// DeleteCluster removes the components that were started earlier
+func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
+	cmd := fmt.Sprintf("sudo systemctl stop k3s && sudo rm -rf %s", constants.K3sDataDir)
+	out, err := k.c.CombinedOutput(cmd)
+	if err != nil {
+		return errors.Wrapf(err, "k3s reset: %s\n%s\n", cmd, out)
+	}
+	return nil
+}
+
+// PullImages is a no-op: k3s pulls the few images it needs when it starts
+func (k *Bootstrapper) PullImages(k8s config.KubernetesConfig) error {
+	return nil
+}
+
+// SetupCerts sets up certificates within the cluster.
+func (k *Bootstrapper) SetupCerts(k8s config.KubernetesConfig) error {
+	return bootstrapper.SetupCerts(k.c, k8s)
+}
+
+// UpdateCluster updates the cluster
+func (k *Bootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
+	service, err := NewK3sService(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating k3s service")
+	}
+	glog.Infof("k3s %s service:\n%s", cfg.KubernetesVersion, service)
+
+	bin, err := machine.CacheBinary("k3s", cfg.KubernetesVersion, "linux", runtime.GOARCH)
+	if err != nil {
+		return errors.Wrap(err, "downloading k3s")
+	}
+	if err := machine.CopyBinary(k.c, "k3s", bin); err != nil {
+		return errors.Wrap(err, "copying k3s")
+	}
+
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte(service), constants.K3sServiceFile, "0640"),
+	}
+	if err := bootstrapper.AddAddons(&files, assets.GenerateTemplateData(cfg)); err != nil {
+		return errors.Wrap(err, "adding addons")
+	}
+	for _, f := range files {
+		if err := k.c.Copy(f); err != nil {
+			return errors.Wrapf(err, "copy")
+		}
+	}
+	if err := k.c.Run("sudo systemctl daemon-reload"); err != nil {
+		return errors.Wrap(err, "reloading systemd")
+	}
+	return nil
+}
+
+// NewK3sService generates a systemd unit running k3s configured from the options
+// present in the KubernetesConfig.
+func NewK3sService(k8s config.KubernetesConfig) (string, error) {
+	version, err := kubeadm.ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing kubernetes version")
+	}
+	if version.LT(semver.MustParse(strings.TrimPrefix(constants.OldestK3sKubernetesVersion, "v"))) {
+		return "", fmt.Errorf("k3s requires kubernetes %s or newer, got %s", constants.OldestK3sKubernetesVersion, k8s.KubernetesVersion)
+	}
+
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Socket: k8s.CRISocket})
+	if err != nil {
+		return "", errors.Wrap(err, "runtime")
+	}
+
+	b := bytes.Buffer{}
+	opts := struct {
+		ExtraOptions string
+	}{
+		ExtraOptions: strings.Join(serverFlags(k8s, r), " "),
+	}
+	if err := k3sServiceTemplate.Execute(&b, opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// serverFlags returns the flags for k3s server
+func serverFlags(k8s config.KubernetesConfig, r cruntime.Manager) []string {
+	// In case of no port assigned, use util.APIServerPort
+	nodePort := k8s.NodePort
+	if nodePort <= 0 {
+		nodePort = util.APIServerPort
+	}
+	serviceCIDR := util.DefaultServiceCIDR
+	if k8s.ServiceCIDR != "" {
+		serviceCIDR = k8s.ServiceCIDR
+	}
+
+	flags := []string{
+		fmt.Sprintf("--data-dir=%s", constants.K3sDataDir),
+		fmt.Sprintf("--https-listen-port=%d", nodePort),
+		fmt.Sprintf("--service-cidr=%s", serviceCIDR),
+		"--write-kubeconfig-mode=0644",
+		// Addons are stored in /etc/kubernetes/manifests and applied by the addon manager pod
+		"--kubelet-arg=pod-manifest-path=/etc/kubernetes/manifests",
+	}
+	if k8s.NodeIP != "" {
+		flags = append(flags, fmt.Sprintf("--node-ip=%s", k8s.NodeIP))
+	}
+	if k8s.NodeName != "" {
+		flags = append(flags, fmt.Sprintf("--node-name=%s", k8s.NodeName))
+	}
+	if k8s.DNSDomain != "" {
+		flags = append(flags, fmt.Sprintf("--cluster-domain=%s", k8s.DNSDomain))
+	}
+	if cidr := k8s.ExtraOptions.Get("pod-network-cidr", kubeadm.Kubeadm); cidr != "" {
+		flags = append(flags, fmt.Sprintf("--cluster-cidr=%s", cidr))
+	}
+	for _, name := range tlsSANs(k8s) {
+		flags = append(flags, fmt.Sprintf("--tls-san=%s", name))
+	}
+	for _, c := range disabledComponents {
+		flags = append(flags, fmt.Sprintf("--disable=%s", c))
+	}
+
+	// k3s talks to docker through its embedded dockershim, and to CRI runtimes directly
+	if r.Name() == "Docker" {
+		flags = append(flags, "--docker")
+	} else {
+		flags = append(flags, fmt.Sprintf("--container-runtime-endpoint=%s", r.SocketPath()))
+	}
+	if k8s.NetworkPlugin == "cni" {
+		flags = append(flags, "--flannel-backend=none")
+	}
+
+	extraOpts := k8s.ExtraOptions.AsMap()
+	// k3s server flags are passed through as is
+	flags = append(flags, toFlags("", extraOpts.Get(K3s))...)
+	components := []string{}
+	for c := range componentArgs {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	for _, c := range components {
+		opts := map[string]string{}
+		for k, v := range extraOpts.Get(c) {
+			opts[k] = v
+		}
+		if _, ok := opts["feature-gates"]; !ok && k8s.FeatureGates != "" {
+			opts["feature-gates"] = k8s.FeatureGates
+		}
+		flags = append(flags, toFlags(componentArgs[c], opts)...)
+	}
+	return flags
+}
+
+// tlsSANs returns the extra names and addresses the apiserver certificate must be valid for
+func tlsSANs(k8s config.KubernetesConfig) []string {
+	names := []string{}
+	if k8s.APIServerName != "" && k8s.APIServerName != constants.APIServerName {
+		names = append(names, k8s.APIServerName)
+	}
+	names = append(names, k8s.APIServerNames...)
+	for _, ip := range k8s.APIServerIPs {
+		names = append(names, ip.String())
+	}
+	return names
+}
+
+// toFlags converts options to sorted flags, optionally wrapped in a k3s pass-through flag
+func toFlags(wrapper string, opts map[string]string) []string {
+	keys := []string{}
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	flags := []string{}
+	for _, k := range keys {
+		if wrapper == "" {
+			flags = append(flags, fmt.Sprintf("--%s=%s", k, opts[k]))
+			continue
+		}
+		flags = append(flags, fmt.Sprintf("--%s=%s=%s", wrapper, k, opts[k]))
+	}
+	return flags
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k3s
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestNewK3sService(t *testing.T) {
+	tests := []struct {
+		description string
+		cfg         config.KubernetesConfig
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "docker",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				NodeName:          "minikube",
+				KubernetesVersion: constants.DefaultK3sKubernetesVersion,
+				ContainerRuntime:  "docker",
+			},
+			expected: "ExecStart=/usr/bin/k3s server --data-dir=/var/lib/minikube/k3s --https-listen-port=8443 --service-cidr=10.96.0.0/12 --write-kubeconfig-mode=0644 --kubelet-arg=pod-manifest-path=/etc/kubernetes/manifests --node-ip=192.168.1.100 --node-name=minikube --disable=traefik --disable=servicelb --disable=local-storage --disable=metrics-server --docker\n",
+		},
+		{
+			description: "containerd with cni and extra options",
+			cfg: config.KubernetesConfig{
+				NodeIP:            "192.168.1.100",
+				NodePort:          8443,
+				NodeName:          "minikube",
+				KubernetesVersion: constants.DefaultK3sKubernetesVersion,
+				ContainerRuntime:  "containerd",
+				NetworkPlugin:     "cni",
+				FeatureGates:      "EphemeralContainers=true",
+				APIServerIPs:      []net.IP{net.ParseIP("127.0.0.1")},
+				ExtraOptions: util.ExtraOptionSlice{
+					util.ExtraOption{Component: K3s, Key: "flannel-iface", Value: "eth1"},
+					util.ExtraOption{Component: "apiserver", Key: "audit-log-maxage", Value: "7"},
+				},
+			},
+			expected: "ExecStart=/usr/bin/k3s server --data-dir=/var/lib/minikube/k3s --https-listen-port=8443 --service-cidr=10.96.0.0/12 --write-kubeconfig-mode=0644 --kubelet-arg=pod-manifest-path=/etc/kubernetes/manifests --node-ip=192.168.1.100 --node-name=minikube --tls-san=127.0.0.1 --disable=traefik --disable=servicelb --disable=local-storage --disable=metrics-server --container-runtime-endpoint=/run/containerd/containerd.sock --flannel-backend=none --flannel-iface=eth1 --kube-apiserver-arg=audit-log-maxage=7 --kube-apiserver-arg=feature-gates=EphemeralContainers=true --kube-controller-manager-arg=feature-gates=EphemeralContainers=true --kubelet-arg=feature-gates=EphemeralContainers=true --kube-scheduler-arg=feature-gates=EphemeralContainers=true\n",
+		},
+		{
+			description: "too old",
+			cfg: config.KubernetesConfig{
+				KubernetesVersion: constants.DefaultKubernetesVersion,
+			},
+			shouldErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := NewK3sService(test.cfg)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("expected error but got none, service: %s", got)
+			}
+			if test.shouldErr {
+				return
+			}
+			var execStart string
+			for _, line := range strings.SplitAfter(got, "\n") {
+				if strings.HasPrefix(line, "ExecStart=") {
+					execStart = line
+				}
+			}
+			if execStart != test.expected {
+				t.Errorf("ExecStart = %q, want %q", execStart, test.expected)
+			}
+		})
+	}
+}
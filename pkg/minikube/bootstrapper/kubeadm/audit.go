@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+const (
+	// AuditDir is the directory of the VM with the audit policy and the audit log, mounted writable into the apiserver
+	AuditDir = "/var/lib/minikube/audit"
+	// AuditPolicyPath is where the audit policy of the apiserver is copied to
+	AuditPolicyPath = AuditDir + "/policy.yaml"
+	// AuditLogPath is where the apiserver writes its audit log
+	AuditLogPath = AuditDir + "/audit.log"
+)
+
+// HostPathMount is a directory of the VM which is mounted into the pod of a control plane component
+type HostPathMount struct {
+	Name      string
+	HostPath  string
+	MountPath string
+	PathType  string
+}
+
+// auditVolumes returns the volumes of the apiserver pod which the audit log needs
+func auditVolumes(policy string) []HostPathMount {
+	if policy == "" {
+		return nil
+	}
+	return []HostPathMount{{Name: "audit", HostPath: AuditDir, MountPath: AuditDir, PathType: "DirectoryOrCreate"}}
+}
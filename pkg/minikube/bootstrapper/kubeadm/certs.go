@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+// generatedCerts are the certificates which kubeadm generates when they are missing. The CAs, the keys of the
+// service accounts and the certificates of minikube are not part of them.
+var generatedCerts = []string{
+	"apiserver-kubelet-client",
+	"apiserver-etcd-client",
+	"front-proxy-client",
+	"etcd/server",
+	"etcd/peer",
+	"etcd/healthcheck-client",
+}
+
+// generatedKubeconfigs are the kubeconfig files of the control plane, which embed a client certificate
+var generatedKubeconfigs = []string{"admin.conf", "controller-manager.conf", "scheduler.conf", "kubelet.conf"}
+
+// controlPlanePods are the static pods which read their certificates on start
+var controlPlanePods = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"}
+
+// rotatedFiles returns the files of the VM which RotateCerts removes before kubeadm generates them again
+func rotatedFiles() []string {
+	files := []string{}
+	for _, c := range generatedCerts {
+		files = append(files, path.Join(util.DefaultCertPath, c+".crt"), path.Join(util.DefaultCertPath, c+".key"))
+	}
+	for _, k := range generatedKubeconfigs {
+		files = append(files, path.Join(bootstrapper.KubeconfigDir, k))
+	}
+	return files
+}
+
+// RotateCerts renews the certificates of the cluster which are signed by its CAs, and the kubeconfig files of the
+// control plane, then restarts the control plane and the kubelet to use them. The CAs are kept, so that the
+// kubeconfig files of the users stay valid.
+func RotateCerts(c command.Runner, r cruntime.Manager, k8s config.KubernetesConfig) error {
+	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing kubernetes version")
+	}
+	// The certificates of minikube, including the serving certificate of the apiserver, are signed again
+	if err := bootstrapper.SetupCerts(c, k8s); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+
+	if err := c.Run("sudo rm -f " + strings.Join(rotatedFiles(), " ")); err != nil {
+		return errors.Wrap(err, "removing certificates")
+	}
+	baseCmd := phaseCmd(version)
+	cmds := []string{
+		fmt.Sprintf("%s phase certs all --config %s", baseCmd, constants.KubeadmConfigFile),
+		fmt.Sprintf("%s phase kubeconfig all --config %s", baseCmd, constants.KubeadmConfigFile),
+	}
+	for _, cmd := range cmds {
+		if err := c.Run(cmd); err != nil {
+			return errors.Wrapf(err, "running cmd: %s", cmd)
+		}
+	}
+
+	if err := restartPods(r, controlPlanePods); err != nil {
+		return err
+	}
+	if err := c.Run("sudo systemctl restart kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
+
+// UpdateAPIServerCert signs the serving certificate of the apiserver again, for the names and IPs of k8s,
+// and restarts the apiserver to use it
+func UpdateAPIServerCert(c command.Runner, r cruntime.Manager, k8s config.KubernetesConfig) error {
+	if err := bootstrapper.SetupCerts(c, k8s); err != nil {
+		return errors.Wrap(err, "setting up certs")
+	}
+	return RestartAPIServer(r)
+}
+
+// RestartAPIServer restarts the apiserver, which reads its certificates and configuration files again
+func RestartAPIServer(r cruntime.Manager) error {
+	return restartPods(r, []string{"kube-apiserver"})
+}
+
+// restartPods stops the containers of static pods, which the kubelet then starts again
+func restartPods(r cruntime.Manager, pods []string) error {
+	for _, pod := range pods {
+		ids, err := r.ListContainers(pod)
+		if err != nil {
+			return errors.Wrapf(err, "list %s containers", pod)
+		}
+		if err := r.StopContainers(ids); err != nil {
+			return errors.Wrapf(err, "stop %s containers", pod)
+		}
+	}
+	return nil
+}
@@ -16,6 +16,13 @@ limitations under the License.
 
 package kubeadm
 
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
 // defaultCNIConfig is the CNI config which is provisioned when --enable-default-cni
 // has been passed to `minikube start`.
 //
@@ -42,3 +49,43 @@ const defaultCNIConfig = `
   }
 }
 `
+
+// dualStackCNIConfig is the default CNI config for IPv6 and dual-stack clusters, which
+// hands out an address from each of the pod CIDRs.
+const dualStackCNIConfig = `
+{
+  "cniVersion": "0.3.1",
+  "name": "rkt.kubernetes.io",
+  "type": "bridge",
+  "bridge": "mybridge",
+  "mtu": 1460,
+  "addIf": "true",
+  "isGateway": true,
+  "ipMasq": true,
+  "ipam": {
+    "type": "host-local",
+    "ranges": [%s
+    ],
+    "routes": [%s
+    ]
+  }
+}
+`
+
+// cniConfig returns the default CNI config for the IP family of the cluster
+func cniConfig(k8s config.KubernetesConfig) string {
+	if !isIPv6Enabled(k8s) {
+		return defaultCNIConfig
+	}
+	ranges := []string{}
+	routes := []string{}
+	for _, cidr := range strings.Split(podSubnet(k8s), ",") {
+		ranges = append(ranges, fmt.Sprintf("\n      [{\"subnet\": \"%s\"}]", cidr))
+		dst := "0.0.0.0/0"
+		if strings.Contains(cidr, ":") {
+			dst = "::/0"
+		}
+		routes = append(routes, fmt.Sprintf("\n      {\"dst\": \"%s\"}", dst))
+	}
+	return fmt.Sprintf(dualStackCNIConfig, strings.Join(ranges, ","), strings.Join(routes, ","))
+}
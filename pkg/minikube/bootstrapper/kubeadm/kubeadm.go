@@ -153,26 +153,9 @@ func (k *Bootstrapper) GetAPIServerStatus(ip net.IP, apiserverPort int) (string,
 
 // LogCommands returns a map of log type to a command which will display that log.
 func (k *Bootstrapper) LogCommands(o bootstrapper.LogOptions) map[string]string {
-	var kubelet strings.Builder
-	kubelet.WriteString("journalctl -u kubelet")
-	if o.Lines > 0 {
-		kubelet.WriteString(fmt.Sprintf(" -n %d", o.Lines))
-	}
-	if o.Follow {
-		kubelet.WriteString(" -f")
-	}
-
-	var dmesg strings.Builder
-	dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
-	if o.Follow {
-		dmesg.WriteString(" --follow")
-	}
-	if o.Lines > 0 {
-		dmesg.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
-	}
 	return map[string]string{
-		"kubelet": kubelet.String(),
-		"dmesg":   dmesg.String(),
+		"kubelet": bootstrapper.JournalCmd("kubelet", o),
+		"dmesg":   bootstrapper.DmesgCmd(o),
 	}
 }
 
@@ -246,6 +229,7 @@ func (k *Bootstrapper) StartCluster(k8s config.KubernetesConfig) error {
 	if err := k.adjustResourceLimits(); err != nil {
 		glog.Warningf("unable to adjust resource limits: %v", err)
 	}
+	k.saveFingerprint()
 	return nil
 }
 
@@ -270,35 +254,6 @@ func (k *Bootstrapper) adjustResourceLimits() error {
 	return nil
 }
 
-func addAddons(files *[]assets.CopyableFile, data interface{}) error {
-	// add addons to file list
-	// custom addons
-	if err := assets.AddMinikubeDirAssets(files); err != nil {
-		return errors.Wrap(err, "adding minikube dir assets")
-	}
-	// bundled addons
-	for _, addonBundle := range assets.Addons {
-		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				if addon.IsTemplate() {
-					addonFile, err := addon.Evaluate(data)
-					if err != nil {
-						return errors.Wrapf(err, "evaluate bundled addon %s asset", addon.GetAssetName())
-					}
-
-					*files = append(*files, addonFile)
-				} else {
-					*files = append(*files, addon)
-				}
-			}
-		} else if err != nil {
-			return nil
-		}
-	}
-
-	return nil
-}
-
 // WaitCluster blocks until Kubernetes appears to be healthy.
 func (k *Bootstrapper) WaitCluster(k8s config.KubernetesConfig) error {
 	// Do not wait for "k8s-app" pods in the case of CNI, as they are managed
@@ -340,15 +295,26 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 		return errors.Wrap(err, "parsing kubernetes version")
 	}
 
-	phase := "alpha"
+	// The kubelet starts the control plane from its manifests, so when they would be generated from the same files,
+	// the kubeadm phases would only slow the restart down
+	if k.unchanged() {
+		glog.Infof("The cluster config is unchanged since the last start: skipping the kubeadm phases")
+		if err := k.waitForAPIServer(k8s); err != nil {
+			return errors.Wrap(err, "waiting for apiserver")
+		}
+		if err := k.adjustResourceLimits(); err != nil {
+			glog.Warningf("unable to adjust resource limits: %v", err)
+		}
+		return nil
+	}
+
 	controlPlane := "controlplane"
 	if version.GTE(semver.MustParse("1.13.0")) {
-		phase = "init"
 		controlPlane = "control-plane"
 	}
 
 	configPath := constants.KubeadmConfigFile
-	baseCmd := fmt.Sprintf("sudo kubeadm %s", phase)
+	baseCmd := phaseCmd(version)
 	cmds := []string{
 		fmt.Sprintf("%s phase certs all --config %s", baseCmd, configPath),
 		fmt.Sprintf("%s phase kubeconfig all --config %s", baseCmd, configPath),
@@ -374,9 +340,18 @@ func (k *Bootstrapper) RestartCluster(k8s config.KubernetesConfig) error {
 	if err := k.adjustResourceLimits(); err != nil {
 		glog.Warningf("unable to adjust resource limits: %v", err)
 	}
+	k.saveFingerprint()
 	return nil
 }
 
+// phaseCmd returns the kubeadm command which runs the phases of kubeadm init for version
+func phaseCmd(version semver.Version) string {
+	if version.GTE(semver.MustParse("1.13.0")) {
+		return "sudo kubeadm init"
+	}
+	return "sudo kubeadm alpha"
+}
+
 // waitForAPIServer waits for the apiserver to start up
 func (k *Bootstrapper) waitForAPIServer(k8s config.KubernetesConfig) error {
 	glog.Infof("Waiting for apiserver ...")
@@ -461,6 +436,11 @@ func NewKubeletConfig(k8s config.KubernetesConfig, r cruntime.Manager) (string,
 		extraOpts["feature-gates"] = kubeletFeatureArgs
 	}
 
+	// Without it, kubelet registers the IPv4 address of the interface with the default route
+	if _, ok := extraOpts["node-ip"]; !ok && isIPv6Enabled(k8s) && k8s.NodeIP != "" {
+		extraOpts["node-ip"] = k8s.NodeIP
+	}
+
 	extraFlags := convertToFlags(extraOpts)
 
 	b := bytes.Buffer{}
@@ -508,7 +488,7 @@ func (k *Bootstrapper) UpdateCluster(cfg config.KubernetesConfig) error {
 		return errors.Wrap(err, "downloading binaries")
 	}
 
-	if err := addAddons(&files, assets.GenerateTemplateData(cfg)); err != nil {
+	if err := bootstrapper.AddAddons(&files, assets.GenerateTemplateData(cfg)); err != nil {
 		return errors.Wrap(err, "adding addons")
 	}
 
@@ -558,11 +538,20 @@ func generateConfig(k8s config.KubernetesConfig, r cruntime.Manager) (string, er
 	if err != nil {
 		return "", errors.Wrap(err, "parses feature gate config for kubeadm and component")
 	}
+	// kubeadm only accepts dual-stack subnets with its own feature gate enabled too
+	if k8s.IPFamily == util.IPFamilyDual {
+		kubeadmFeatureArgs["IPv6DualStack"] = true
+	}
 
 	extraComponentConfig, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs)
 	if err != nil {
 		return "", errors.Wrap(err, "generating extra component config for kubeadm")
 	}
+	for i := range extraComponentConfig {
+		if extraComponentConfig[i].Component == componentToKubeadmConfigKey[Apiserver] {
+			extraComponentConfig[i].ExtraVolumes = auditVolumes(k8s.AuditPolicy)
+		}
+	}
 
 	// In case of no port assigned, use util.APIServerPort
 	nodePort := k8s.NodePort
@@ -584,20 +573,24 @@ func generateConfig(k8s config.KubernetesConfig, r cruntime.Manager) (string, er
 		ExtraArgs         []ComponentExtraArgs
 		FeatureArgs       map[string]bool
 		NoTaintMaster     bool
+		IPFamily          string
+		IPv6              bool
 	}{
 		CertDir:           util.DefaultCertPath,
 		ServiceCIDR:       util.DefaultServiceCIDR,
-		PodSubnet:         k8s.ExtraOptions.Get("pod-network-cidr", Kubeadm),
+		PodSubnet:         podSubnet(k8s),
 		AdvertiseAddress:  k8s.NodeIP,
 		APIServerPort:     nodePort,
 		KubernetesVersion: k8s.KubernetesVersion,
-		EtcdDataDir:       "/data/minikube", //TODO(r2d4): change to something else persisted
+		EtcdDataDir:       constants.EtcdDataDir, //TODO(r2d4): change to something else persisted
 		NodeName:          k8s.NodeName,
 		CRISocket:         r.SocketPath(),
 		ImageRepository:   k8s.ImageRepository,
 		ExtraArgs:         extraComponentConfig,
 		FeatureArgs:       kubeadmFeatureArgs,
 		NoTaintMaster:     false, // That does not work with k8s 1.12+
+		IPFamily:          k8s.IPFamily,
+		IPv6:              isIPv6Enabled(k8s),
 	}
 
 	if k8s.ServiceCIDR != "" {
@@ -624,6 +617,22 @@ func generateConfig(k8s config.KubernetesConfig, r cruntime.Manager) (string, er
 	return b.String(), nil
 }
 
+// podSubnet returns the comma separated pod CIDRs. They are always set for IPv6 and dual-stack
+// clusters, so that the controller manager allocates node CIDRs for each IP family.
+func podSubnet(k8s config.KubernetesConfig) string {
+	if cidr := k8s.ExtraOptions.Get("pod-network-cidr", Kubeadm); cidr != "" {
+		return cidr
+	}
+	if isIPv6Enabled(k8s) {
+		return util.DefaultPodCIDRs(k8s.IPFamily)
+	}
+	return ""
+}
+
+func isIPv6Enabled(k8s config.KubernetesConfig) bool {
+	return k8s.IPFamily == util.IPFamilyIPv6 || k8s.IPFamily == util.IPFamilyDual
+}
+
 func copyConfig(cfg config.KubernetesConfig, files []assets.CopyableFile, kubeadmCfg string, kubeletCfg string) []assets.CopyableFile {
 	files = append(files,
 		assets.NewMemoryAssetTarget([]byte(kubeletService), constants.KubeletServiceFile, "0640"),
@@ -635,7 +644,7 @@ func copyConfig(cfg config.KubernetesConfig, files []assets.CopyableFile, kubead
 	// and minikube was started with "--extra-config=kubelet.network-plugin=cni".
 	if cfg.EnableDefaultCNI {
 		files = append(files,
-			assets.NewMemoryAssetTarget([]byte(defaultCNIConfig), constants.DefaultCNIConfigPath, "0644"))
+			assets.NewMemoryAssetTarget([]byte(cniConfig(cfg)), constants.DefaultCNIConfigPath, "0644"))
 	}
 
 	return files
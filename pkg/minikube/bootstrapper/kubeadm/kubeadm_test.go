@@ -17,6 +17,7 @@ limitations under the License.
 package kubeadm
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"strings"
@@ -282,3 +283,116 @@ func TestGenerateConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateConfigIPFamily(t *testing.T) {
+	var tests = []struct {
+		family      string
+		serviceCIDR string
+		want        []string
+	}{
+		{
+			family:      util.IPFamilyIPv6,
+			serviceCIDR: util.DefaultServiceCIDRv6,
+			want:        []string{"podSubnet: fd00:10:1::/64", "serviceSubnet: fd00:10:96::/112", "advertiseAddress: fd00:39::2"},
+		},
+		{
+			family:      util.IPFamilyDual,
+			serviceCIDR: util.DefaultServiceCIDR + "," + util.DefaultServiceCIDRv6,
+			want:        []string{"podSubnet: 10.1.0.0/16,fd00:10:1::/64", "serviceSubnet: 10.96.0.0/12,fd00:10:96::/112", "kind: KubeProxyConfiguration", "mode: ipvs", "IPv6DualStack: true"},
+		},
+	}
+	runtime, err := cruntime.New(cruntime.Config{Type: "docker"})
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	for _, tc := range tests {
+		t.Run(tc.family, func(t *testing.T) {
+			cfg := config.KubernetesConfig{
+				NodeIP:            "fd00:39::2",
+				NodeName:          "mk",
+				KubernetesVersion: "v1.15.0",
+				ServiceCIDR:       tc.serviceCIDR,
+				IPFamily:          tc.family,
+			}
+			got, err := generateConfig(cfg, runtime)
+			if err != nil {
+				t.Fatalf("generateConfig() error = %v", err)
+			}
+			for _, w := range tc.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("expected %q in config:\n%s", w, got)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateConfigAuditPolicy(t *testing.T) {
+	runtime, err := cruntime.New(cruntime.Config{Type: "docker"})
+	if err != nil {
+		t.Fatalf("runtime: %v", err)
+	}
+	cfg := config.KubernetesConfig{
+		NodeIP:            "1.1.1.1",
+		NodeName:          "mk",
+		KubernetesVersion: "v1.15.0",
+		AuditPolicy:       "/home/user/policy.yaml",
+		ExtraOptions: util.ExtraOptionSlice{
+			util.ExtraOption{Component: Apiserver, Key: "audit-policy-file", Value: AuditPolicyPath},
+		},
+	}
+	got, err := generateConfig(cfg, runtime)
+	if err != nil {
+		t.Fatalf("generateConfig() error = %v", err)
+	}
+	want := `apiServer:
+  extraArgs:
+    audit-policy-file: "/var/lib/minikube/audit/policy.yaml"
+    enable-admission-plugins: "NamespaceLifecycle,LimitRanger,ServiceAccount,DefaultStorageClass,DefaultTolerationSeconds,NodeRestriction,MutatingAdmissionWebhook,ValidatingAdmissionWebhook,ResourceQuota"
+  extraVolumes:
+  - name: audit
+    hostPath: /var/lib/minikube/audit
+    mountPath: /var/lib/minikube/audit
+    pathType: DirectoryOrCreate
+`
+	if !strings.Contains(got, want) {
+		t.Errorf("expected %q in config:\n%s", want, got)
+	}
+
+	cfg.AuditPolicy = ""
+	got, err = generateConfig(cfg, runtime)
+	if err != nil {
+		t.Fatalf("generateConfig() error = %v", err)
+	}
+	if strings.Contains(got, "extraVolumes") {
+		t.Errorf("expected no extraVolumes without an audit policy:\n%s", got)
+	}
+}
+
+func TestCNIConfig(t *testing.T) {
+	got := cniConfig(config.KubernetesConfig{IPFamily: util.IPFamilyIPv4})
+	if got != defaultCNIConfig {
+		t.Errorf("expected the default CNI config for IPv4, got: %s", got)
+	}
+
+	got = cniConfig(config.KubernetesConfig{IPFamily: util.IPFamilyDual})
+	var parsed struct {
+		IPAM struct {
+			Ranges [][]struct {
+				Subnet string `json:"subnet"`
+			} `json:"ranges"`
+			Routes []struct {
+				Dst string `json:"dst"`
+			} `json:"routes"`
+		} `json:"ipam"`
+	}
+	if err := json.Unmarshal([]byte(got), &parsed); err != nil {
+		t.Fatalf("invalid CNI config: %v\n%s", err, got)
+	}
+	if len(parsed.IPAM.Ranges) != 2 || parsed.IPAM.Ranges[0][0].Subnet != util.DefaultPodCIDR || parsed.IPAM.Ranges[1][0].Subnet != util.DefaultPodCIDRv6 {
+		t.Errorf("unexpected ranges: %+v", parsed.IPAM.Ranges)
+	}
+	if len(parsed.IPAM.Routes) != 2 || parsed.IPAM.Routes[1].Dst != "::/0" {
+		t.Errorf("unexpected routes: %+v", parsed.IPAM.Routes)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// fingerprintFile keeps the checksums of the fingerprintFiles of the last successful start of the cluster
+const fingerprintFile = util.DefaultMinikubeDirectory + "/kubeadm.sha256"
+
+// fingerprintFiles are the files from which the kubeadm phases of a restart generate the control plane: the kubeadm
+// config, which has the Kubernetes version and the settings of the components, and the kubelet unit
+var fingerprintFiles = []string{
+	constants.KubeadmConfigFile,
+	constants.KubeletServiceFile,
+	constants.KubeletSystemdConfFile,
+}
+
+// fingerprintCertDir holds the certificates of the control plane, which SetupCerts only regenerates when they change.
+// It has subdirectories, such as the one of the etcd certificates.
+const fingerprintCertDir = util.DefaultCertPath
+
+// controlPlaneFiles are written by the kubeadm phases, which can only be skipped if the files are in the VM
+var controlPlaneFiles = []string{
+	"/etc/kubernetes/admin.conf",
+	"/etc/kubernetes/manifests/kube-apiserver.yaml",
+	"/etc/kubernetes/manifests/kube-controller-manager.yaml",
+	"/etc/kubernetes/manifests/kube-scheduler.yaml",
+	"/etc/kubernetes/manifests/etcd.yaml",
+}
+
+// fingerprintCmd returns the command which prints the checksums of the fingerprintFiles and of the certificates, with
+// its output redirected to a file if it is not empty. The certificates are only readable by root.
+func fingerprintCmd(redirect string) string {
+	cmd := fmt.Sprintf("sha256sum %s && find %s -type f -exec sha256sum {} +", strings.Join(fingerprintFiles, " "), fingerprintCertDir)
+	if redirect != "" {
+		cmd = fmt.Sprintf("{ %s; } > %s", cmd, redirect)
+	}
+	return fmt.Sprintf("sudo sh -c '%s'", cmd)
+}
+
+// unchanged returns whether the files of the control plane were generated from the same files as in the last
+// successful start of the cluster, in which case a restart only has to wait for the kubelet to start the control plane
+func (k *Bootstrapper) unchanged() bool {
+	for _, f := range controlPlaneFiles {
+		if err := k.c.Run("sudo test -f " + f); err != nil {
+			glog.Infof("%s is missing: %v", f, err)
+			return false
+		}
+	}
+	last, err := k.c.CombinedOutput("sudo cat " + fingerprintFile)
+	if err != nil {
+		glog.Infof("No fingerprint of the last start: %v", err)
+		return false
+	}
+	current, err := k.c.CombinedOutput(fingerprintCmd(""))
+	if err != nil {
+		glog.Warningf("Unable to fingerprint the cluster config: %v", err)
+		return false
+	}
+	if strings.TrimSpace(current) != strings.TrimSpace(last) {
+		glog.Infof("The cluster config changed since the last start:\n%s\nwas:\n%s", current, last)
+		return false
+	}
+	return true
+}
+
+// saveFingerprint records the files of a successful start, for the next restart to compare them with
+func (k *Bootstrapper) saveFingerprint() {
+	if err := k.c.Run(fingerprintCmd(fingerprintFile)); err != nil {
+		glog.Warningf("Unable to save the fingerprint of the cluster config, so the next restart runs the kubeadm phases: %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestUnchanged(t *testing.T) {
+	const sums = "1111  /var/lib/kubeadm.yaml\n2222  /var/lib/minikube/certs/apiserver.crt\n"
+	manifests := map[string]string{}
+	for _, f := range controlPlaneFiles {
+		manifests["sudo test -f "+f] = ""
+	}
+	var tests = []struct {
+		name string
+		cmds map[string]string
+		want bool
+	}{
+		{"unchanged", map[string]string{"sudo cat " + fingerprintFile: sums, fingerprintCmd(""): sums}, true},
+		{"changed", map[string]string{"sudo cat " + fingerprintFile: sums, fingerprintCmd(""): "3333  /var/lib/kubeadm.yaml\n"}, false},
+		{"first restart", map[string]string{fingerprintCmd(""): sums}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := command.NewFakeCommandRunner()
+			f.SetCommandToOutput(manifests)
+			f.SetCommandToOutput(tc.cmds)
+			k := &Bootstrapper{c: f}
+			if got := k.unchanged(); got != tc.want {
+				t.Errorf("unchanged() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{"sudo cat " + fingerprintFile: sums, fingerprintCmd(""): sums})
+	if (&Bootstrapper{c: f}).unchanged() {
+		t.Error("unchanged() = true, want false without the manifests of the control plane")
+	}
+}
+
+func TestFingerprintCmd(t *testing.T) {
+	want := "sudo sh -c '{ sha256sum /var/lib/kubeadm.yaml /lib/systemd/system/kubelet.service /etc/systemd/system/kubelet.service.d/10-kubeadm.conf" +
+		" && find /var/lib/minikube/certs/ -type f -exec sha256sum {} +; } > /var/lib/minikube/kubeadm.sha256'"
+	if got := fingerprintCmd(fingerprintFile); got != want {
+		t.Errorf("fingerprintCmd() = %q, want %q", got, want)
+	}
+}
+
+// vmRunner is a command runner which keeps the files copied to the VM, and runs the commands of a restart on them
+type vmRunner struct {
+	files map[string]string
+	cmds  []string
+}
+
+func (r *vmRunner) Run(cmd string) error {
+	_, err := r.CombinedOutput(cmd)
+	return err
+}
+
+func (r *vmRunner) CombinedOutputTo(cmd string, out io.Writer) error {
+	o, err := r.CombinedOutput(cmd)
+	fmt.Fprint(out, o)
+	return err
+}
+
+func (r *vmRunner) CombinedOutput(cmd string) (string, error) {
+	r.cmds = append(r.cmds, cmd)
+	switch {
+	case strings.HasPrefix(cmd, "sudo test -f "):
+		if _, ok := r.files[strings.TrimPrefix(cmd, "sudo test -f ")]; !ok {
+			return "", errors.New("exit status 1")
+		}
+	case cmd == "sudo cat "+fingerprintFile:
+		sums, ok := r.files[fingerprintFile]
+		if !ok {
+			return "", errors.New("exit status 1")
+		}
+		return sums, nil
+	case cmd == fingerprintCmd(""):
+		return r.sums()
+	case cmd == fingerprintCmd(fingerprintFile):
+		sums, err := r.sums()
+		if err != nil {
+			return "", err
+		}
+		r.files[fingerprintFile] = sums
+	}
+	return "", nil
+}
+
+// sums returns the output of the fingerprint command
+func (r *vmRunner) sums() (string, error) {
+	var b bytes.Buffer
+	for _, f := range fingerprintFiles {
+		c, ok := r.files[f]
+		if !ok {
+			return "", fmt.Errorf("sha256sum: %s: No such file or directory", f)
+		}
+		fmt.Fprintf(&b, "%x  %s\n", sha256.Sum256([]byte(c)), f)
+	}
+	certs := []string{}
+	for f := range r.files {
+		if strings.HasPrefix(f, fingerprintCertDir) {
+			certs = append(certs, f)
+		}
+	}
+	sort.Strings(certs)
+	for _, f := range certs {
+		fmt.Fprintf(&b, "%x  %s\n", sha256.Sum256([]byte(r.files[f])), f)
+	}
+	return b.String(), nil
+}
+
+func (r *vmRunner) Copy(f assets.CopyableFile) error {
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, f); err != nil {
+		return err
+	}
+	r.files[path.Join(f.GetTargetDir(), f.GetTargetName())] = b.String()
+	return nil
+}
+
+func (r *vmRunner) Remove(f assets.CopyableFile) error {
+	delete(r.files, path.Join(f.GetTargetDir(), f.GetTargetName()))
+	return nil
+}
+
+// ranPhases returns whether the kubeadm phases were run since the last call
+func (r *vmRunner) ranPhases() bool {
+	defer func() { r.cmds = nil }()
+	for _, c := range r.cmds {
+		if strings.Contains(c, " phase certs all ") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetupCertsRestartCluster(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	apiserver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer apiserver.Close()
+	u, err := url.Parse(apiserver.URL)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", apiserver.URL, err)
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatalf("splitting %s: %v", u.Host, err)
+	}
+	nodePort, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("parsing port %s: %v", port, err)
+	}
+
+	r := &vmRunner{files: map[string]string{
+		constants.KubeadmConfigFile:                  "kind: ClusterConfiguration",
+		constants.KubeletServiceFile:                 "[Unit]",
+		constants.KubeletSystemdConfFile:             "[Service]",
+		path.Join(fingerprintCertDir, "etcd/ca.crt"): "etcd CA",
+	}}
+	for _, f := range controlPlaneFiles {
+		r.files[f] = "apiVersion: v1"
+	}
+	k := &Bootstrapper{c: r}
+	k8s := config.KubernetesConfig{
+		KubernetesVersion: constants.DefaultKubernetesVersion,
+		NodeIP:            host,
+		NodePort:          nodePort,
+		APIServerName:     constants.APIServerName,
+		DNSDomain:         constants.ClusterDNSDomain,
+		ServiceCIDR:       util.DefaultServiceCIDR,
+	}
+	start := func() {
+		t.Helper()
+		if err := k.SetupCerts(k8s); err != nil {
+			t.Fatalf("SetupCerts: %v", err)
+		}
+		if err := k.RestartCluster(k8s); err != nil {
+			t.Fatalf("RestartCluster: %v", err)
+		}
+	}
+
+	start()
+	if !r.ranPhases() {
+		t.Errorf("the first restart skipped the kubeadm phases")
+	}
+	start()
+	if r.ranPhases() {
+		t.Errorf("a restart with the same config ran the kubeadm phases")
+	}
+	k8s.APIServerNames = []string{"minikube.example.com"}
+	start()
+	if !r.ranPhases() {
+		t.Errorf("a restart with a new name of the apiserver skipped the kubeadm phases")
+	}
+	start()
+	if r.ranPhases() {
+		t.Errorf("a restart with the same config ran the kubeadm phases")
+	}
+}
@@ -124,6 +124,15 @@ kind: ClusterConfiguration
 {{- range $i, $val := printMapInOrder .Options ": " }}
     {{$val}}
 {{- end}}
+{{- if .ExtraVolumes}}
+  extraVolumes:
+{{- range .ExtraVolumes}}
+  - name: {{.Name}}
+    hostPath: {{.HostPath}}
+    mountPath: {{.MountPath}}
+    pathType: {{.PathType}}
+{{- end}}
+{{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
 {{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
@@ -139,7 +148,7 @@ etcd:
 kubernetesVersion: {{.KubernetesVersion}}
 networking:
   dnsDomain: cluster.local
-  podSubnet: ""
+  podSubnet: {{if .IPv6}}{{.PodSubnet}}{{else}}""{{end}}
   serviceSubnet: {{.ServiceCIDR}}
 ---
 apiVersion: kubelet.config.k8s.io/v1beta1
@@ -149,7 +158,14 @@ evictionHard:
   nodefs.available: "0%"
   nodefs.inodesFree: "0%"
   imagefs.available: "0%"
-`))
+{{if eq .IPFamily "dual" -}}
+---
+apiVersion: kubeproxy.config.k8s.io/v1alpha1
+kind: KubeProxyConfiguration
+mode: ipvs
+featureGates:
+  IPv6DualStack: true
+{{end}}`))
 
 var kubeletSystemdTemplate = template.Must(template.New("kubeletSystemdTemplate").Parse(`
 [Unit]
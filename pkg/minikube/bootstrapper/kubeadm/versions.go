@@ -63,8 +63,9 @@ func ExtraConfigForComponent(component string, opts util.ExtraOptionSlice, versi
 
 // ComponentExtraArgs holds extra args for a component
 type ComponentExtraArgs struct {
-	Component string
-	Options   map[string]string
+	Component    string
+	Options      map[string]string
+	ExtraVolumes []HostPathMount
 }
 
 // mapping of component to the section name in kubeadm.
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JournalCmd returns the command which displays the journal of a systemd unit, as selected by o
+func JournalCmd(unit string, o LogOptions) string {
+	return "journalctl -u " + unit + journalArgs(o)
+}
+
+// DmesgCmd returns the command which displays the warnings and errors of the kernel, as selected by o.
+// As dmesg cannot select messages by time, they are read from the journal when o.Since is set.
+func DmesgCmd(o LogOptions) string {
+	if o.Since > 0 {
+		return "sudo journalctl -k -p warning" + journalArgs(o)
+	}
+	var cmd strings.Builder
+	cmd.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	if o.Follow {
+		cmd.WriteString(" --follow")
+	}
+	if o.Lines > 0 {
+		cmd.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
+	}
+	return cmd.String()
+}
+
+// journalArgs returns the journalctl flags which select the entries of o.
+// Since is relative, so that the clocks of the host and of the VM do not need to match.
+func journalArgs(o LogOptions) string {
+	var args strings.Builder
+	if o.Lines > 0 {
+		args.WriteString(fmt.Sprintf(" -n %d", o.Lines))
+	}
+	if o.Since > 0 {
+		args.WriteString(fmt.Sprintf(" --since=-%ds", seconds(o.Since)))
+	}
+	if o.Follow {
+		args.WriteString(" -f")
+	}
+	return args.String()
+}
+
+// seconds returns d in whole seconds, and at least 1
+func seconds(d time.Duration) int64 {
+	if s := int64(d / time.Second); s > 0 {
+		return s
+	}
+	return 1
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLogCmds(t *testing.T) {
+	var tests = []struct {
+		name    string
+		opts    LogOptions
+		journal string
+		dmesg   string
+	}{
+		{"lines", LogOptions{Lines: 30}, "journalctl -u kubelet -n 30", "sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg | tail -n 30"},
+		{"follow", LogOptions{Follow: true}, "journalctl -u kubelet -f", "sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg --follow"},
+		{"since", LogOptions{Lines: 30, Since: 10 * time.Minute}, "journalctl -u kubelet -n 30 --since=-600s", "sudo journalctl -k -p warning -n 30 --since=-600s"},
+		{"short since", LogOptions{Since: time.Millisecond, Follow: true}, "journalctl -u kubelet --since=-1s -f", "sudo journalctl -k -p warning --since=-1s -f"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := JournalCmd("kubelet", tc.opts); got != tc.journal {
+				t.Errorf("JournalCmd() = %q, want %q", got, tc.journal)
+			}
+			if got := DmesgCmd(tc.opts); got != tc.dmesg {
+				t.Errorf("DmesgCmd() = %q, want %q", got, tc.dmesg)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle packs the files minikube downloads into an archive, so that clusters can be started offline
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// manifestName is the name of the first entry of a bundle
+const manifestName = "manifest.json"
+
+// cachePrefix is the only directory of the minikube home a bundle may write to
+const cachePrefix = "cache/"
+
+// Manifest describes what a bundle was created for
+type Manifest struct {
+	MinikubeVersion   string
+	KubernetesVersion string
+	Bootstrapper      string
+	ISO               string   `json:",omitempty"`
+	Images            []string `json:",omitempty"`
+	// Files are the paths of the bundled files, relative to the minikube home
+	Files []string
+}
+
+// Create writes a bundle of the given files of the minikube home to dst
+func Create(dst string, home string, m Manifest) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	if err := write(f, home, m); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	return f.Close()
+}
+
+func write(w io.Writer, home string, m Manifest) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0644, Size: int64(len(b)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+
+	for _, name := range m.Files {
+		if err := validName(name); err != nil {
+			return err
+		}
+		if err := addFile(tw, filepath.Join(home, filepath.FromSlash(name)), name); err != nil {
+			return errors.Wrapf(err, "adding %s", name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func addFile(tw *tar.Writer, src string, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	glog.Infof("Bundling %s (%d bytes)", name, info.Size())
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: int64(info.Mode().Perm()), Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Load extracts the bundle src into the minikube home, and returns its manifest
+func Load(src string, home string) (Manifest, error) {
+	var m Manifest
+	f, err := os.Open(src)
+	if err != nil {
+		return m, errors.Wrap(err, "open")
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return m, errors.Wrapf(err, "%s is not a minikube bundle", src)
+	}
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return m, fmt.Errorf("%s is not a minikube bundle: missing %s", src, manifestName)
+	}
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return m, errors.Wrap(err, "decoding manifest")
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return m, errors.Wrap(err, "reading bundle")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := validName(hdr.Name); err != nil {
+			return m, err
+		}
+		if err := extractFile(tr, filepath.Join(home, filepath.FromSlash(hdr.Name)), os.FileMode(hdr.Mode)); err != nil {
+			return m, errors.Wrapf(err, "extracting %s", hdr.Name)
+		}
+	}
+}
+
+func extractFile(r io.Reader, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that an interrupted load does not leave a truncated file in the cache
+	tmp := dst + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// validName returns an error if name is not a clean path within the cache directory
+func validName(name string) error {
+	if path.Clean(name) != name || !strings.HasPrefix(name, cachePrefix) {
+		return fmt.Errorf("invalid bundle path %q: must be within %s", name, cachePrefix)
+	}
+	return nil
+}
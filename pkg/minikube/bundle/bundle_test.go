@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCreateAndLoad(t *testing.T) {
+	src, err := ioutil.TempDir("", "bundle-src")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "bundle-dst")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	files := map[string]string{
+		"cache/iso/minikube-v1.3.0.iso":     "iso",
+		"cache/v1.15.2/kubeadm":             "kubeadm",
+		"cache/images/k8s.gcr.io/pause_3.1": "pause",
+	}
+	m := Manifest{MinikubeVersion: "v1.3.0", KubernetesVersion: "v1.15.2", Bootstrapper: "kubeadm"}
+	for name, content := range files {
+		p := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		m.Files = append(m.Files, name)
+	}
+
+	archive := filepath.Join(src, "bundle.tar.gz")
+	if err := Create(archive, src, m); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := Load(archive, dst)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Load() manifest = %+v, want %+v", got, m)
+	}
+	for name, content := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("reading %s: %v", name, err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	var tests = []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "cache/v1.15.2/kubelet"},
+		{name: "cache/../config/config.json", wantErr: true},
+		{name: "machines/minikube/config.json", wantErr: true},
+		{name: "/cache/iso/minikube.iso", wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := validName(tc.name); (err != nil) != tc.wantErr {
+			t.Errorf("validName(%q) = %v, wantErr: %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
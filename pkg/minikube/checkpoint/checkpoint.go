@@ -0,0 +1,169 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkpoint records the phases of 'minikube start' which completed, so that a failed start can continue
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Phase is a phase of 'minikube start'
+type Phase string
+
+const (
+	// Download downloads the ISO and caches the images
+	Download Phase = "download"
+	// Machine creates or starts the VM
+	Machine Phase = "machine"
+	// Provision configures the container runtime
+	Provision Phase = "provision"
+	// Bootstrap sets up the certificates and starts Kubernetes
+	Bootstrap Phase = "bootstrap"
+	// Addons loads the cached images and enables the requested addons
+	Addons Phase = "addons"
+	// Verify waits for Kubernetes to be healthy
+	Verify Phase = "verify"
+)
+
+// Phases are all the phases of 'minikube start', in order
+var Phases = []Phase{Download, Machine, Provision, Bootstrap, Addons, Verify}
+
+const fileName = "start-checkpoint.json"
+
+// Checkpoint is the progress of a start of a profile
+type Checkpoint struct {
+	Profile string
+	// Config is the fingerprint of the configuration of the start
+	Config string
+	// Created is set if the VM was created by this start, so that continuing it does not treat the VM as pre-existing
+	Created bool `json:",omitempty"`
+	// BootID identifies the boot of the VM which the runtime was provisioned in
+	BootID string `json:",omitempty"`
+	// Phases are the completed phases
+	Phases []Phase
+}
+
+// Path returns the path of the checkpoint file of a profile
+func Path(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), fileName)
+}
+
+// New returns the checkpoint of a start which has not completed any phase
+func New(profile string, config string) *Checkpoint {
+	return &Checkpoint{Profile: profile, Config: config, Phases: []Phase{}}
+}
+
+// Load returns the checkpoint of the last start of a profile, or nil if there is none
+func Load(profile string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(Path(profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var c Checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile))
+	}
+	return &c, nil
+}
+
+// Save writes the checkpoint to the profile directory
+func (c *Checkpoint) Save() error {
+	if err := os.MkdirAll(constants.GetProfilePath(c.Profile), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(c.Profile), b, 0600)
+}
+
+// Completed returns whether a phase completed
+func (c *Checkpoint) Completed(p Phase) bool {
+	for _, done := range c.Phases {
+		if done == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete records that a phase completed, and saves the checkpoint
+func (c *Checkpoint) Complete(p Phase) error {
+	if !c.Completed(p) {
+		c.Phases = append(c.Phases, p)
+	}
+	return c.Save()
+}
+
+// Last returns the last phase which completed, in the order of Phases, or an empty phase if none did
+func (c *Checkpoint) Last() Phase {
+	last := Phase("")
+	for _, p := range Phases {
+		if c.Completed(p) {
+			last = p
+		}
+	}
+	return last
+}
+
+// Boot records the boot of the VM. If the VM booted again since the runtime was provisioned,
+// the phases after Machine are forgotten, and Boot returns true.
+func (c *Checkpoint) Boot(bootID string) bool {
+	rebooted := c.BootID != "" && c.BootID != bootID
+	c.BootID = bootID
+	if !rebooted {
+		return false
+	}
+	kept := []Phase{}
+	for _, p := range c.Phases {
+		if p == Download || p == Machine {
+			kept = append(kept, p)
+		}
+	}
+	c.Phases = kept
+	return true
+}
+
+// Clear removes the checkpoint of a profile, once its start completed
+func Clear(profile string) error {
+	if err := os.Remove(Path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Fingerprint returns a digest of a configuration, to tell whether a start continues with the same one
+func Fingerprint(config interface{}) (string, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
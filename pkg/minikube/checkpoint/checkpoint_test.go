@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestCompleteLoadClear(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	if c, err := Load("p1"); err != nil || c != nil {
+		t.Fatalf("Load() without checkpoint = %v, %v, want nil", c, err)
+	}
+	c := New("p1", "abc")
+	if c.Last() != "" {
+		t.Errorf("Last() of a new checkpoint = %q, want none", c.Last())
+	}
+	for _, p := range []Phase{Download, Machine, Provision, Machine} {
+		if err := c.Complete(p); err != nil {
+			t.Fatalf("Complete(%s): %v", p, err)
+		}
+	}
+
+	got, err := Load("p1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := &Checkpoint{Profile: "p1", Config: "abc", Phases: []Phase{Download, Machine, Provision}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if !got.Completed(Machine) || got.Completed(Bootstrap) {
+		t.Errorf("Completed() does not match the phases %v", got.Phases)
+	}
+	if got.Last() != Provision {
+		t.Errorf("Last() = %q, want %q", got.Last(), Provision)
+	}
+
+	if err := Clear("p1"); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if c, err := Load("p1"); err != nil || c != nil {
+		t.Errorf("Load() after Clear = %v, %v, want nil", c, err)
+	}
+	if err := Clear("p1"); err != nil {
+		t.Errorf("Clear() without checkpoint = %v, want nil", err)
+	}
+}
+
+func TestBoot(t *testing.T) {
+	c := &Checkpoint{Phases: []Phase{Download, Machine}}
+	if c.Boot("boot1") {
+		t.Errorf("first Boot() = true, want false")
+	}
+	c.Phases = append(c.Phases, Provision, Bootstrap)
+	if c.Boot("boot1") {
+		t.Errorf("Boot() with the same ID = true, want false")
+	}
+	if !c.Boot("boot2") {
+		t.Errorf("Boot() after a reboot = false, want true")
+	}
+	if want := []Phase{Download, Machine}; !reflect.DeepEqual(c.Phases, want) {
+		t.Errorf("phases after a reboot = %v, want %v", c.Phases, want)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	type config struct{ CPUs int }
+	a, err := Fingerprint(config{2})
+	if err != nil {
+		t.Fatalf("Fingerprint: %v", err)
+	}
+	b, _ := Fingerprint(config{2})
+	c, _ := Fingerprint(config{4})
+	if a != b || a == c {
+		t.Errorf("Fingerprint() = %s, %s, %s: want equal configs to match, and different ones not to", a, b, c)
+	}
+}
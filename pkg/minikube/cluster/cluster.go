@@ -43,8 +43,10 @@ import (
 	"github.com/shirou/gopsutil/disk"
 	"github.com/shirou/gopsutil/mem"
 	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/drivers/qemu"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -177,6 +179,15 @@ func configureHost(h *host.Host, e *engine.Options) error {
 	return nil
 }
 
+// SetRegistries saves the registry mirrors and insecure registries of config to the host, which passes them to dockerd
+// whenever it is provisioned
+func SetRegistries(api libmachine.API, h *host.Host, config cfg.MachineConfig) error {
+	e := engineOptions(config)
+	h.HostOptions.EngineOptions.InsecureRegistry = e.InsecureRegistry
+	h.HostOptions.EngineOptions.RegistryMirror = e.RegistryMirror
+	return api.Save(h)
+}
+
 // ensureGuestClockSync ensures that the guest system clock is relatively in-sync
 func ensureSyncedGuestClock(h hostRunner) error {
 	d, err := guestClockDelta(h, time.Now())
@@ -339,6 +350,9 @@ func engineOptions(config cfg.MachineConfig) *engine.Options {
 		RegistryMirror:   config.RegistryMirror,
 		ArbitraryFlags:   config.DockerOpt,
 	}
+	if config.Rootless {
+		o.ArbitraryFlags = append(append([]string{}, config.DockerOpt...), cruntime.RootlessDockerOpt)
+	}
 	return &o
 }
 
@@ -517,7 +531,7 @@ func GetVMHostIP(host *host.Host) (net.IP, error) {
 		return ip, nil
 	case constants.DriverHyperkit:
 		return net.ParseIP("192.168.64.1"), nil
-	case constants.DriverVmware:
+	case constants.DriverVmware, constants.DriverVfkit:
 		vmIPString, err := host.Driver.GetIP()
 		if err != nil {
 			return []byte{}, errors.Wrap(err, "Error getting VM IP address")
@@ -527,6 +541,20 @@ func GetVMHostIP(host *host.Host) (net.IP, error) {
 			return []byte{}, errors.Wrap(err, "Error converting VM IP address to IPv4 address")
 		}
 		return net.IPv4(vmIP[0], vmIP[1], vmIP[2], byte(1)), nil
+	case constants.DriverQemu2:
+		re := regexp.MustCompile(`"Network":\s*"(.*?)"`)
+		if m := re.FindStringSubmatch(string(host.RawDriver)); m == nil || m[1] == qemu.NetworkUser {
+			return net.ParseIP(qemu.UserNetworkHostIP), nil
+		}
+		vmIPString, err := host.Driver.GetIP()
+		if err != nil {
+			return []byte{}, errors.Wrap(err, "Error getting VM IP address")
+		}
+		vmIP := net.ParseIP(vmIPString).To4()
+		if vmIP == nil {
+			return []byte{}, errors.Errorf("VM IP address %q is not an IPv4 address", vmIPString)
+		}
+		return net.IPv4(vmIP[0], vmIP[1], vmIP[2], byte(1)), nil
 	default:
 		return []byte{}, errors.New("Error, attempted to get host ip address for unsupported driver")
 	}
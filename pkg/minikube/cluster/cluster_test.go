@@ -19,6 +19,7 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -240,6 +241,21 @@ func TestStartHostConfig(t *testing.T) {
 
 }
 
+func TestEngineOptionsRootless(t *testing.T) {
+	config := config.MachineConfig{
+		DockerOpt: []string{"param=value"},
+		Rootless:  true,
+	}
+	got := engineOptions(config).ArbitraryFlags
+	want := []string{"param=value", "userns-remap=docker"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ArbitraryFlags = %v, want %v", got, want)
+	}
+	if len(config.DockerOpt) != 1 {
+		t.Errorf("DockerOpt was modified: %v", config.DockerOpt)
+	}
+}
+
 func TestStopHostError(t *testing.T) {
 	RegisterMockDriver(t)
 	api := tests.NewMockAPI(t)
@@ -397,6 +413,56 @@ func TestGetHostDockerEnvIPv6(t *testing.T) {
 	}
 }
 
+func TestGetVMHostIPQemu2(t *testing.T) {
+	var testCases = []struct {
+		description string
+		rawDriver   string
+		ip          string
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "user network",
+			rawDriver:   `{"Network": "user"}`,
+			want:        "10.0.2.2",
+		},
+		{
+			description: "socket_vmnet",
+			rawDriver:   `{"Network": "socket_vmnet"}`,
+			ip:          "192.168.105.3",
+			want:        "192.168.105.1",
+		},
+		{
+			description: "socket_vmnet without IPv4 address",
+			rawDriver:   `{"Network": "socket_vmnet"}`,
+			ip:          "fd00:105::3",
+			wantErr:     true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			h := &host.Host{
+				DriverName: constants.DriverQemu2,
+				RawDriver:  []byte(test.rawDriver),
+				Driver:     &tests.MockDriver{IP: test.ip, T: t},
+			}
+			ip, err := GetVMHostIP(h)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("GetVMHostIP() = %v, want an error", ip)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetVMHostIP: %v", err)
+			}
+			if ip.String() != test.want {
+				t.Errorf("GetVMHostIP() = %v, want %s", ip, test.want)
+			}
+		})
+	}
+}
+
 func TestCreateSSHShell(t *testing.T) {
 	api := tests.NewMockAPI(t)
 
@@ -23,6 +23,8 @@ import (
 	_ "k8s.io/minikube/pkg/minikube/drivers/kvm2"
 	_ "k8s.io/minikube/pkg/minikube/drivers/none"
 	_ "k8s.io/minikube/pkg/minikube/drivers/parallels"
+	_ "k8s.io/minikube/pkg/minikube/drivers/qemu2"
+	_ "k8s.io/minikube/pkg/minikube/drivers/vfkit"
 	_ "k8s.io/minikube/pkg/minikube/drivers/virtualbox"
 	_ "k8s.io/minikube/pkg/minikube/drivers/vmware"
 	_ "k8s.io/minikube/pkg/minikube/drivers/vmwarefusion"
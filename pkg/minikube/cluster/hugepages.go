@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// hugePagesPath is where the kernel of the VM exposes the huge pages of each size
+const hugePagesPath = "/sys/kernel/mm/hugepages"
+
+// hugePageSizes are the sizes of huge pages which can be reserved in the VM, with their size in MB
+// and the directory of the size in hugePagesPath
+var hugePageSizes = map[string]struct {
+	mb  int
+	dir string
+}{
+	"2Mi": {2, "hugepages-2048kB"},
+	"1Gi": {1024, "hugepages-1048576kB"},
+}
+
+// HugePages are a number of huge pages of a size, such as 2Mi or 1Gi
+type HugePages struct {
+	Size  string
+	Count int
+}
+
+// ParseHugePages parses huge pages in the size:count,... format, such as 2Mi:512,1Gi:4
+func ParseHugePages(s string) ([]HugePages, error) {
+	var pages []HugePages
+	seen := map[string]bool{}
+	for _, p := range strings.Split(s, ",") {
+		fields := strings.Split(strings.TrimSpace(p), ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%q is not in the size:count format, such as 2Mi:512", p)
+		}
+		if _, ok := hugePageSizes[fields[0]]; !ok {
+			return nil, fmt.Errorf("unsupported huge page size %q: use 2Mi or 1Gi", fields[0])
+		}
+		if seen[fields[0]] {
+			return nil, fmt.Errorf("huge pages of %s are set twice", fields[0])
+		}
+		seen[fields[0]] = true
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%q is not a positive number of huge pages", fields[1])
+		}
+		pages = append(pages, HugePages{Size: fields[0], Count: n})
+	}
+	return pages, nil
+}
+
+// HugePagesMemory returns the memory taken by huge pages, in MB
+func HugePagesMemory(pages []HugePages) int {
+	mb := 0
+	for _, p := range pages {
+		mb += hugePageSizes[p.Size].mb * p.Count
+	}
+	return mb
+}
+
+// ReserveHugePages reserves huge pages in the VM. They are reserved again on every start, as the kernel does not keep
+// them across reboots. 1Gi pages need contiguous memory, so the kernel may reserve fewer pages: this is an error.
+func ReserveHugePages(runner command.Runner, pages []HugePages) error {
+	for _, p := range pages {
+		nr := path.Join(hugePagesPath, hugePageSizes[p.Size].dir, "nr_hugepages")
+		if err := runner.Run(fmt.Sprintf("echo %d | sudo tee %s", p.Count, nr)); err != nil {
+			return errors.Wrapf(err, "reserving %s huge pages", p.Size)
+		}
+		out, err := runner.CombinedOutput("cat " + nr)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", nr)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(out))
+		if err != nil {
+			return errors.Wrapf(err, "parsing %s", nr)
+		}
+		if n < p.Count {
+			return fmt.Errorf("only %d of %d huge pages of %s could be reserved: give the VM more memory", n, p.Count, p.Size)
+		}
+	}
+	return nil
+}
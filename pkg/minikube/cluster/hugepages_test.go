@@ -0,0 +1,68 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestParseHugePages(t *testing.T) {
+	var tests = []struct {
+		pages   string
+		want    []HugePages
+		wantErr bool
+	}{
+		{"2Mi:512", []HugePages{{"2Mi", 512}}, false},
+		{"2Mi:512,1Gi:4", []HugePages{{"2Mi", 512}, {"1Gi", 4}}, false},
+		{"2Mi:512,2Mi:4", nil, true},
+		{"4Mi:512", nil, true},
+		{"2Mi", nil, true},
+		{"2Mi:0", nil, true},
+		{"2Mi:many", nil, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseHugePages(tc.pages)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseHugePages(%q) = %v, want error: %v", tc.pages, err, tc.wantErr)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseHugePages(%q) = %v, want %v", tc.pages, got, tc.want)
+		}
+	}
+	if mb := HugePagesMemory([]HugePages{{"2Mi", 512}, {"1Gi", 4}}); mb != 5120 {
+		t.Errorf("HugePagesMemory() = %d, want 5120", mb)
+	}
+}
+
+func TestReserveHugePages(t *testing.T) {
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"echo 512 | sudo tee /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages":  "512",
+		"cat /sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages":                  "512\n",
+		"echo 4 | sudo tee /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages": "4",
+		"cat /sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages":               "2\n",
+	})
+	if err := ReserveHugePages(f, []HugePages{{"2Mi", 512}}); err != nil {
+		t.Errorf("ReserveHugePages(2Mi:512) = %v, want nil", err)
+	}
+	if err := ReserveHugePages(f, []HugePages{{"1Gi", 4}}); err == nil {
+		t.Error("ReserveHugePages(1Gi:4) = nil, want an error when fewer pages are reserved")
+	}
+}
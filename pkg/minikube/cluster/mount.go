@@ -19,14 +19,20 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"path"
+	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/drivers"
 )
 
+// virtiofsMountRoot is where virtiofs shares are mounted on the VM, to be bind mounted from
+const virtiofsMountRoot = "/mnt/virtiofs"
+
 // MountConfig defines the options available to the Mount command
 type MountConfig struct {
 	// Type is the filesystem type (Typically 9p)
@@ -68,6 +74,49 @@ func Mount(r mountRunner, source string, target string, c *MountConfig) error {
 	return nil
 }
 
+// FindVirtiofsShare returns the virtiofs share which contains hostPath
+func FindVirtiofsShare(shares []string, hostPath string) (string, bool) {
+	hostPath = filepath.Clean(hostPath)
+	for _, s := range shares {
+		s = filepath.Clean(s)
+		if hostPath == s || strings.HasPrefix(hostPath, s+string(filepath.Separator)) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// MountVirtiofs mounts hostPath, which is share or a directory within it, to target on the VM.
+// Each share is mounted once under /mnt/virtiofs, and bind mounted to the targets from there.
+func MountVirtiofs(r mountRunner, share string, hostPath string, target string, c *MountConfig) error {
+	rel, err := filepath.Rel(share, hostPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return fmt.Errorf("%s is not within the virtiofs share %s", hostPath, share)
+	}
+	if out, err := r.CombinedOutput("sudo modprobe virtiofs 2>/dev/null; grep -qw virtiofs /proc/filesystems"); err != nil {
+		return errors.Wrapf(err, "the VM kernel does not support virtiofs, which requires Linux 5.4 or newer: %s", out)
+	}
+	if err := Unmount(r, target); err != nil {
+		return errors.Wrap(err, "umount")
+	}
+
+	tag := drivers.VirtiofsTag(share)
+	shareMount := path.Join(virtiofsMountRoot, tag)
+	mountShare := fmt.Sprintf("sudo mount -t virtiofs %s %s", tag, shareMount)
+	if opts := sortedOptions(c.Options); len(opts) > 0 {
+		mountShare = fmt.Sprintf("sudo mount -t virtiofs -o %s %s %s", strings.Join(opts, ","), tag, shareMount)
+	}
+	cmd := fmt.Sprintf("sudo mkdir -p %s && { findmnt %s >/dev/null || %s; } && sudo mkdir -m %o -p %s && sudo mount --bind %s %s",
+		shareMount, shareMount, mountShare, c.Mode, target, path.Join(shareMount, filepath.ToSlash(rel)), target)
+	glog.Infof("Will run: %s", cmd)
+	out, err := r.CombinedOutput(cmd)
+	glog.Infof("mount err=%s, out=%s", err, out)
+	if err != nil {
+		return errors.Wrap(err, out)
+	}
+	return nil
+}
+
 // returns either a raw UID number, or the subshell to resolve it.
 func resolveUID(id string) string {
 	_, err := strconv.ParseInt(id, 10, 64)
@@ -116,7 +165,11 @@ func mntCmd(source string, target string, c *MountConfig) string {
 		options[k] = v
 	}
 
-	// Convert everything into a sorted list for better test results
+	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(sortedOptions(options), ","), source, target)
+}
+
+// sortedOptions converts mount options into a sorted list for better test results
+func sortedOptions(options map[string]string) []string {
 	opts := []string{}
 	for k, v := range options {
 		// Mount option with no value, such as "noextend"
@@ -127,7 +180,7 @@ func mntCmd(source string, target string, c *MountConfig) string {
 		opts = append(opts, fmt.Sprintf("%s=%s", k, v))
 	}
 	sort.Strings(opts)
-	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(opts, ","), source, target)
+	return opts
 }
 
 // umountCmd returns a command for unmounting
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/drivers"
 )
 
 type mockMountRunner struct {
@@ -120,3 +121,74 @@ func TestUnmount(t *testing.T) {
 		t.Errorf("command diff (-want +got): %s", diff)
 	}
 }
+
+func TestFindVirtiofsShare(t *testing.T) {
+	shares := []string{"/home/me/src", "/data/"}
+	var tests = []struct {
+		hostPath string
+		share    string
+		found    bool
+	}{
+		{hostPath: "/home/me/src", share: "/home/me/src", found: true},
+		{hostPath: "/home/me/src/app", share: "/home/me/src", found: true},
+		{hostPath: "/data", share: "/data", found: true},
+		{hostPath: "/home/me/src2", found: false},
+		{hostPath: "/home/me", found: false},
+	}
+	for _, tc := range tests {
+		share, found := FindVirtiofsShare(shares, tc.hostPath)
+		if share != tc.share || found != tc.found {
+			t.Errorf("FindVirtiofsShare(%q) = %q, %t, want %q, %t", tc.hostPath, share, found, tc.share, tc.found)
+		}
+	}
+}
+
+func TestMountVirtiofs(t *testing.T) {
+	r := newMockMountRunner(t)
+	cfg := &MountConfig{Type: "virtiofs", Mode: os.FileMode(0755), Options: map[string]string{"ro": ""}}
+	if err := MountVirtiofs(r, "/home/me/src", "/home/me/src/app", "/app", cfg); err != nil {
+		t.Fatalf("MountVirtiofs: %v", err)
+	}
+	tag := drivers.VirtiofsTag("/home/me/src")
+	want := []string{
+		"sudo modprobe virtiofs 2>/dev/null; grep -qw virtiofs /proc/filesystems",
+		"[ \"x$(findmnt -T /app | grep /app)\" != \"x\" ] && { sudo fuser -km /app; sudo umount /app; } || echo ",
+		"sudo mkdir -p /mnt/virtiofs/" + tag + " && { findmnt /mnt/virtiofs/" + tag + " >/dev/null || sudo mount -t virtiofs -o ro " + tag + " /mnt/virtiofs/" + tag + "; } && sudo mkdir -m 755 -p /app && sudo mount --bind /mnt/virtiofs/" + tag + "/app /app",
+	}
+	if diff := cmp.Diff(r.cmds, want); diff != "" {
+		t.Errorf("command diff (-want +got): %s", diff)
+	}
+
+	if err := MountVirtiofs(r, "/home/me/src", "/home/me/other", "/app", cfg); err == nil {
+		t.Errorf("expected an error mounting a path outside of the share")
+	}
+}
+
+func TestSSHFSCmd(t *testing.T) {
+	var tests = []struct {
+		name string
+		cfg  *MountConfig
+		want string
+	}{
+		{
+			name: "simple",
+			cfg:  &MountConfig{Type: "sshfs", Mode: os.FileMode(0755)},
+			want: "sudo mkdir -m 755 -p /target && sudo sshfs -f -o allow_other,gid=0,slave,uid=0 :/home/user/src /target",
+		},
+		{
+			name: "options",
+			cfg: &MountConfig{Type: "sshfs", Mode: os.FileMode(0700), UID: "docker", GID: "docker", Options: map[string]string{
+				"cache": "no",
+				"ro":    "",
+			}},
+			want: "sudo mkdir -m 700 -p /target && sudo sshfs -f -o allow_other,cache=no,gid=$(grep ^docker: /etc/group | cut -d: -f3),ro,slave,uid=$(id -u docker) :/home/user/src /target",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := sshfsCmd("/home/user/src", "/target", tc.cfg); got != tc.want {
+				t.Errorf("sshfsCmd() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
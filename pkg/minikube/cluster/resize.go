@@ -0,0 +1,195 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Resources are the resources of a VM. Zero values are left unchanged.
+type Resources struct {
+	CPUs int
+	// Memory is in MB
+	Memory int
+	// DiskSize is in MB
+	DiskSize int
+}
+
+// resourceFields are the names of the resource fields in the configuration of a driver. An empty name cannot be changed.
+type resourceFields struct {
+	cpus     string
+	memory   string
+	diskSize string
+}
+
+// resizableDrivers are the drivers whose VMs can be resized, with the fields of their configuration
+var resizableDrivers = map[string]resourceFields{
+	constants.DriverKvm2:       {"CPU", "Memory", "DiskSize"},
+	constants.DriverQemu2:      {"CPU", "Memory", "DiskSize"},
+	constants.DriverHyperkit:   {"CPU", "Memory", "DiskSize"},
+	constants.DriverVfkit:      {"CPU", "Memory", "DiskSize"},
+	constants.DriverHyperv:     {"CPU", "MemSize", "DiskSize"},
+	constants.DriverVirtualbox: {"CPU", "Memory", ""},
+}
+
+// ResizeHost changes the CPUs, memory and disk size of a stopped VM. Disks can only grow:
+// the guest grows its data partition to the end of the disk on its next boot.
+func ResizeHost(api libmachine.API, name string, r Resources) error {
+	h, err := CheckIfHostExistsAndLoad(api, name)
+	if err != nil {
+		return errors.Wrap(err, "load")
+	}
+	fields, ok := resizableDrivers[h.DriverName]
+	if !ok {
+		return fmt.Errorf("the %s driver does not support resizing", h.DriverName)
+	}
+	if r.DiskSize != 0 && fields.diskSize == "" {
+		return fmt.Errorf("the %s driver does not support resizing the disk", h.DriverName)
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		return errors.Wrap(err, "state")
+	}
+	if s != state.Stopped {
+		return fmt.Errorf("%s is %s: it must be stopped to be resized", name, s)
+	}
+
+	raw, err := json.Marshal(h.Driver)
+	if err != nil {
+		return errors.Wrap(err, "marshal driver config")
+	}
+	raw, err = resizeDriverConfig(raw, fields, r)
+	if err != nil {
+		return err
+	}
+	if err := setDriverConfig(h, raw); err != nil {
+		return errors.Wrap(err, "set driver config")
+	}
+
+	if err := resizeVM(h, r); err != nil {
+		return err
+	}
+	return api.Save(h)
+}
+
+// resizeDriverConfig returns the driver configuration raw, with the resources of r
+func resizeDriverConfig(raw []byte, fields resourceFields, r Resources) ([]byte, error) {
+	var c map[string]interface{}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, errors.Wrap(err, "unmarshal driver config")
+	}
+	if r.DiskSize != 0 {
+		// JSON numbers are decoded as float64
+		current, _ := c[fields.diskSize].(float64)
+		if r.DiskSize < int(current) {
+			return nil, fmt.Errorf("the disk cannot shrink from %dMB to %dMB", int(current), r.DiskSize)
+		}
+		c[fields.diskSize] = r.DiskSize
+	}
+	if r.CPUs != 0 {
+		c[fields.cpus] = r.CPUs
+	}
+	if r.Memory != 0 {
+		c[fields.memory] = r.Memory
+	}
+	return json.Marshal(c)
+}
+
+// setDriverConfig replaces the configuration of the driver of h, whether it is built in or a plugin
+func setDriverConfig(h *host.Host, raw []byte) error {
+	h.RawDriver = raw
+	if d, ok := h.Driver.(interface{ SetConfigRaw([]byte) error }); ok {
+		return d.SetConfigRaw(raw)
+	}
+	return json.Unmarshal(raw, h.Driver)
+}
+
+// resizeVM applies the new resources to the hypervisor, where the driver does not read them on start
+func resizeVM(h *host.Host, r Resources) error {
+	machineDir := filepath.Join(constants.GetMinipath(), "machines", h.Name)
+	switch h.DriverName {
+	case constants.DriverVirtualbox:
+		args := []string{"modifyvm", h.Name}
+		if r.CPUs != 0 {
+			args = append(args, "--cpus", strconv.Itoa(r.CPUs))
+		}
+		if r.Memory != 0 {
+			args = append(args, "--memory", strconv.Itoa(r.Memory))
+		}
+		return runResize("VBoxManage", args...)
+	case constants.DriverHyperv:
+		if r.CPUs != 0 {
+			if err := runResize("powershell.exe", "-NoProfile", "-NonInteractive", "Hyper-V\\Set-VMProcessor", h.Name, "-Count", strconv.Itoa(r.CPUs)); err != nil {
+				return err
+			}
+		}
+		if r.Memory != 0 {
+			if err := runResize("powershell.exe", "-NoProfile", "-NonInteractive", "Hyper-V\\Set-VMMemory", h.Name, "-StartupBytes", fmt.Sprintf("%dMB", r.Memory)); err != nil {
+				return err
+			}
+		}
+		if r.DiskSize != 0 {
+			return runResize("powershell.exe", "-NoProfile", "-NonInteractive", "Hyper-V\\Resize-VHD", "-Path", quotePS(filepath.Join(machineDir, "disk.vhd")), "-SizeBytes", fmt.Sprintf("%dMB", r.DiskSize))
+		}
+	default:
+		// The other drivers read the CPUs and memory from their configuration on start, and use a raw disk image
+		if r.DiskSize != 0 {
+			return growRawDisk(filepath.Join(machineDir, h.Name+".rawdisk"), r.DiskSize)
+		}
+	}
+	return nil
+}
+
+// growRawDisk extends a sparse raw disk image to sizeMB
+func growRawDisk(path string, sizeMB int) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "disk image")
+	}
+	size := int64(sizeMB) * 1000000
+	if size <= fi.Size() {
+		return nil
+	}
+	glog.Infof("Growing %s from %d to %d bytes", path, fi.Size(), size)
+	return os.Truncate(path, size)
+}
+
+// quotePS quotes a string for powershell
+func quotePS(s string) string {
+	return "'" + s + "'"
+}
+
+func runResize(name string, args ...string) error {
+	glog.Infof("Running: %s %v", name, args)
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "%s: %s", name, out)
+	}
+	return nil
+}
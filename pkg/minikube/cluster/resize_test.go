@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResizeDriverConfig(t *testing.T) {
+	raw := []byte(`{"CPU":2,"Memory":2000,"DiskSize":20000,"MachineName":"minikube"}`)
+	fields := resourceFields{"CPU", "Memory", "DiskSize"}
+
+	var tests = []struct {
+		description string
+		r           Resources
+		want        map[string]interface{}
+		err         bool
+	}{
+		{"cpus only", Resources{CPUs: 4}, map[string]interface{}{"CPU": 4.0, "Memory": 2000.0, "DiskSize": 20000.0, "MachineName": "minikube"}, false},
+		{"everything", Resources{CPUs: 1, Memory: 8192, DiskSize: 40000}, map[string]interface{}{"CPU": 1.0, "Memory": 8192.0, "DiskSize": 40000.0, "MachineName": "minikube"}, false},
+		{"same disk", Resources{DiskSize: 20000}, map[string]interface{}{"CPU": 2.0, "Memory": 2000.0, "DiskSize": 20000.0, "MachineName": "minikube"}, false},
+		{"shrink disk", Resources{DiskSize: 10000}, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := resizeDriverConfig(raw, fields, test.r)
+			if test.err {
+				if err == nil {
+					t.Errorf("resizeDriverConfig() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resizeDriverConfig: %v", err)
+			}
+			var c map[string]interface{}
+			if err := json.Unmarshal(got, &c); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(c, test.want) {
+				t.Errorf("resizeDriverConfig() = %v, want %v", c, test.want)
+			}
+		})
+	}
+}
+
+func TestGrowRawDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resize")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "minikube.rawdisk")
+	if err := ioutil.WriteFile(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	for _, size := range []int{2, 1} {
+		if err := growRawDisk(path, size); err != nil {
+			t.Fatalf("growRawDisk(%d): %v", size, err)
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat: %v", err)
+		}
+		if fi.Size() != 2000000 {
+			t.Errorf("size after growRawDisk(%d) = %d, want 2000000", size, fi.Size())
+		}
+	}
+	if b, err := ioutil.ReadFile(path); err != nil || string(b[:4]) != "data" {
+		t.Errorf("growRawDisk lost the data of the disk: %v", err)
+	}
+	if err := growRawDisk(filepath.Join(dir, "missing"), 2); err == nil {
+		t.Errorf("growRawDisk of a missing disk = nil, want error")
+	}
+}
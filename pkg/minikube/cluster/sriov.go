@@ -0,0 +1,66 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// igbDriverPath is where the kernel of the VM lists the NICs bound to the igb driver, such as the emulated SR-IOV NIC of kvm2
+const igbDriverPath = "/sys/bus/pci/drivers/igb"
+
+// MaxVirtualFunctions is the number of virtual functions of the igb NIC emulated by QEMU
+const MaxVirtualFunctions = 7
+
+// EnableVirtualFunctions creates n virtual functions of the igb NIC of the VM. They are created again on every start,
+// as the kernel does not keep them across reboots. The number of virtual functions of a NIC can only be changed from 0.
+func EnableVirtualFunctions(runner command.Runner, n int) error {
+	if err := runner.Run("sudo modprobe igb"); err != nil {
+		return errors.Wrap(err, "loading the igb driver")
+	}
+	out, err := runner.CombinedOutput("ls -d " + igbDriverPath + "/0000:*")
+	if err != nil || strings.TrimSpace(out) == "" {
+		return fmt.Errorf("no igb NIC in the VM: it is recreated with the VM, by minikube delete")
+	}
+	numvfs := path.Join(strings.Fields(out)[0], "sriov_numvfs")
+	out, err = runner.CombinedOutput("cat " + numvfs)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", numvfs)
+	}
+	cur, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return errors.Wrapf(err, "parsing %s", numvfs)
+	}
+	if cur == n {
+		return nil
+	}
+	if cur != 0 {
+		if err := runner.Run("echo 0 | sudo tee " + numvfs); err != nil {
+			return errors.Wrap(err, "removing virtual functions")
+		}
+	}
+	if err := runner.Run(fmt.Sprintf("echo %d | sudo tee %s", n, numvfs)); err != nil {
+		return errors.Wrapf(err, "creating %d virtual functions", n)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestEnableVirtualFunctions(t *testing.T) {
+	const numvfs = "/sys/bus/pci/drivers/igb/0000:00:05.0/sriov_numvfs"
+	var tests = []struct {
+		name    string
+		cmds    map[string]string
+		n       int
+		wantErr bool
+	}{
+		{"unchanged", map[string]string{"cat " + numvfs: "4\n"}, 4, false},
+		{"from 0", map[string]string{"cat " + numvfs: "0\n", "echo 4 | sudo tee " + numvfs: "4"}, 4, false},
+		{"changed", map[string]string{"cat " + numvfs: "2\n", "echo 0 | sudo tee " + numvfs: "0", "echo 4 | sudo tee " + numvfs: "4"}, 4, false},
+		{"not removed", map[string]string{"cat " + numvfs: "2\n", "echo 4 | sudo tee " + numvfs: "4"}, 4, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := command.NewFakeCommandRunner()
+			f.SetCommandToOutput(map[string]string{
+				"sudo modprobe igb":                     "",
+				"ls -d /sys/bus/pci/drivers/igb/0000:*": "/sys/bus/pci/drivers/igb/0000:00:05.0\n",
+			})
+			f.SetCommandToOutput(tc.cmds)
+			err := EnableVirtualFunctions(f, tc.n)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("EnableVirtualFunctions(%d) = %v, want error: %v", tc.n, err, tc.wantErr)
+			}
+		})
+	}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{"sudo modprobe igb": ""})
+	if err := EnableVirtualFunctions(f, 4); err == nil {
+		t.Error("EnableVirtualFunctions() = nil, want an error without an igb NIC")
+	}
+}
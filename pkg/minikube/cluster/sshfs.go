@@ -0,0 +1,114 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// sftpServerPaths are where OpenSSH installs its sftp server on the supported hosts
+var sftpServerPaths = []string{
+	"/usr/lib/openssh/sftp-server",
+	"/usr/libexec/openssh/sftp-server",
+	"/usr/lib/ssh/sftp-server",
+	"/usr/libexec/sftp-server",
+	`C:\Windows\System32\OpenSSH\sftp-server.exe`,
+}
+
+// FindSftpServer returns the path of the OpenSSH sftp server of the host
+func FindSftpServer() (string, error) {
+	for _, p := range sftpServerPaths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	if p, err := exec.LookPath("sftp-server"); err == nil {
+		return p, nil
+	}
+	return "", fmt.Errorf("no sftp server found in %s: install OpenSSH", strings.Join(sftpServerPaths, ", "))
+}
+
+// sshfsCmd returns the command which mounts hostPath to target, reading the sftp protocol on its standard input and output
+func sshfsCmd(hostPath string, target string, c *MountConfig) string {
+	options := map[string]string{
+		"allow_other": "",
+		"gid":         resolveGID(c.GID),
+		"slave":       "",
+		"uid":         resolveUID(c.UID),
+	}
+	for k, v := range c.Options {
+		options[k] = v
+	}
+	return fmt.Sprintf("sudo mkdir -m %o -p %s && sudo sshfs -f -o %s :%s %s",
+		c.Mode, target, strings.Join(sortedOptions(options), ","), filepath.ToSlash(hostPath), target)
+}
+
+// MountSSHFS mounts hostPath to target on the VM with sshfs. Instead of connecting back to an ssh server on the host,
+// sshfs talks to the sftp server of the host over the ssh session of the mount. It returns once the target is unmounted.
+func MountSSHFS(client *ssh.Client, sftpServer string, hostPath string, target string, c *MountConfig) error {
+	if err := Unmount(command.NewSSHRunner(client), target); err != nil {
+		return errors.Wrap(err, "umount")
+	}
+	sess, err := client.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "ssh session")
+	}
+	defer sess.Close()
+
+	srv := exec.Command(sftpServer)
+	toVM, err := srv.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "stdout")
+	}
+	fromVM, err := sess.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "stdout")
+	}
+	var srvErr, sshfsErr bytes.Buffer
+	srv.Stdin = fromVM
+	srv.Stderr = &srvErr
+	sess.Stdin = toVM
+	sess.Stderr = &sshfsErr
+	if err := srv.Start(); err != nil {
+		return errors.Wrapf(err, "starting %s", sftpServer)
+	}
+	defer func() {
+		if err := srv.Process.Kill(); err != nil {
+			glog.Infof("sftp server: %v", err)
+		}
+		if err := srv.Wait(); err != nil {
+			glog.Infof("sftp server exited: %v: %s", err, srvErr.String())
+		}
+	}()
+
+	cmd := sshfsCmd(hostPath, target, c)
+	glog.Infof("Will run: %s", cmd)
+	if err := sess.Run(cmd); err != nil {
+		return errors.Wrap(err, sshfsErr.String())
+	}
+	return nil
+}
@@ -54,15 +54,16 @@ func (s *SSHRunner) Remove(f assets.CopyableFile) error {
 	return sess.Run(cmd)
 }
 
+// singleWriter serializes the writes of both the stdout and the stderr of a command to w
 type singleWriter struct {
-	b  bytes.Buffer
+	w  io.Writer
 	mu sync.Mutex
 }
 
 func (w *singleWriter) Write(p []byte) (int, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	return w.b.Write(p)
+	return w.w.Write(p)
 }
 
 // teeSSH runs an SSH command, streaming stdout, stderr to logs
@@ -121,33 +122,25 @@ func (s *SSHRunner) Run(cmd string) error {
 }
 
 // CombinedOutputTo runs the command and stores both command
-// output and error to out.
+// output and error to out, as they are written.
 func (s *SSHRunner) CombinedOutputTo(cmd string, w io.Writer) error {
-	out, err := s.CombinedOutput(cmd)
+	glog.Infoln("Run with output:", cmd)
+	sess, err := s.c.NewSession()
 	if err != nil {
-		return err
+		return errors.Wrap(err, "NewSession")
 	}
-	_, err = w.Write([]byte(out))
-	return err
+	defer sess.Close()
+
+	combined := &singleWriter{w: w}
+	return teeSSH(sess, cmd, combined, combined)
 }
 
 // CombinedOutput runs the command on the remote and returns its combined
 // standard output and standard error.
 func (s *SSHRunner) CombinedOutput(cmd string) (string, error) {
-	glog.Infoln("Run with output:", cmd)
-	sess, err := s.c.NewSession()
-	if err != nil {
-		return "", errors.Wrap(err, "NewSession")
-	}
-	defer sess.Close()
-
-	var combined singleWriter
-	err = teeSSH(sess, cmd, &combined, &combined)
-	out := combined.b.String()
-	if err != nil {
-		return out, err
-	}
-	return out, nil
+	var b bytes.Buffer
+	err := s.CombinedOutputTo(cmd, &b)
+	return b.String(), err
 }
 
 // Copy copies a file to the remote over SSH.
@@ -38,6 +38,16 @@ const (
 	WantReportError = "WantReportError"
 	// WantReportErrorPrompt is the key for WantReportErrorPrompt
 	WantReportErrorPrompt = "WantReportErrorPrompt"
+	// ErrorReportingURL is the key for the endpoint errors are reported to
+	ErrorReportingURL = "error-reporting-url"
+	// WantRemoteProblems is the key for WantRemoteProblems
+	WantRemoteProblems = "WantRemoteProblems"
+	// ProblemsURL is the key for the URL of the remote database of known problems
+	ProblemsURL = "problems-url"
+	// AuditMaxSize is the key for the size above which the audit log is rotated
+	AuditMaxSize = "audit-max-size"
+	// AuditMaxAge is the key for how long the entries of the audit log are kept
+	AuditMaxAge = "audit-max-age"
 	// WantKubectlDownloadMsg is the key for WantKubectlDownloadMsg
 	WantKubectlDownloadMsg = "WantKubectlDownloadMsg"
 	// WantNoneDriverWarning is the key for WantNoneDriverWarning
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ProfileExport is a profile as written by minikube profile export, and read by minikube profile import
+type ProfileExport struct {
+	Name            string          `json:"name"`
+	MinikubeVersion string          `json:"minikubeVersion,omitempty"`
+	Addons          map[string]bool `json:"addons,omitempty"`
+	Config          Config          `json:"config"`
+}
+
+// WriteProfileExport writes a profile export as YAML, with the fields of the config in the order of the config file
+func WriteProfileExport(w io.Writer, p ProfileExport) error {
+	j, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	// JSON is YAML, and a MapSlice keeps the order of the fields
+	var m yaml.MapSlice
+	if err := yaml.Unmarshal(j, &m); err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// ReadProfileExport reads a YAML or JSON profile export, rejecting unknown fields
+func ReadProfileExport(path string) (*ProfileExport, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseProfileExport(data)
+}
+
+func parseProfileExport(data []byte) (*ProfileExport, error) {
+	j, err := k8syaml.ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile export: %v", err)
+	}
+	var p ProfileExport
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&p); err != nil {
+		return nil, fmt.Errorf("invalid profile export: %v", err)
+	}
+	if p.Config.MachineConfig.VMDriver == "" || p.Config.KubernetesConfig.KubernetesVersion == "" {
+		return nil, fmt.Errorf("invalid profile export: the config has no VMDriver or KubernetesVersion")
+	}
+	return &p, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestProfileExport(t *testing.T) {
+	p := ProfileExport{
+		Name:            "dev",
+		MinikubeVersion: "v1.3.0",
+		Addons:          map[string]bool{"ingress": true, "dashboard": false},
+		Config: Config{
+			MachineConfig: MachineConfig{
+				Memory:           4096,
+				CPUs:             4,
+				VMDriver:         "kvm2",
+				ContainerRuntime: "containerd",
+				RegistryMirror:   []string{"https://mirror.example.com"},
+			},
+			KubernetesConfig: KubernetesConfig{
+				KubernetesVersion: "v1.15.2",
+				APIServerIPs:      []net.IP{net.ParseIP("192.168.1.5")},
+				ContainerRuntime:  "containerd",
+				ExtraOptions:      util.ExtraOptionSlice{{Component: "apiserver", Key: "v", Value: "10"}},
+			},
+		},
+	}
+	var b bytes.Buffer
+	if err := WriteProfileExport(&b, p); err != nil {
+		t.Fatalf("WriteProfileExport: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "name: dev\n") {
+		t.Errorf("WriteProfileExport() = %q, want it to start with the name", b.String())
+	}
+	if strings.Index(b.String(), "KeepContext") > strings.Index(b.String(), "MinikubeISO") {
+		t.Errorf("WriteProfileExport() = %q, want the fields in the order of the config", b.String())
+	}
+	got, err := parseProfileExport(b.Bytes())
+	if err != nil {
+		t.Fatalf("parseProfileExport: %v", err)
+	}
+	if !reflect.DeepEqual(*got, p) {
+		t.Errorf("parseProfileExport() = %+v, want %+v", *got, p)
+	}
+
+	for _, data := range []string{
+		"name: dev\nconfig:\n  MachineConfig:\n    VMDriver: kvm2\n",
+		"name: dev\nvmdriver: kvm2\n",
+	} {
+		if _, err := parseProfileExport([]byte(data)); err == nil {
+			t.Errorf("parseProfileExport(%q) succeeded, want an error", data)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+// lockWriteGrace is how long a lock which cannot be decoded is considered held, as it may still be being written
+const lockWriteGrace = 10 * time.Second
+
+// ProfileLock is held by a minikube command which changes the cluster of a profile
+type ProfileLock struct {
+	Pid     int       `json:"pid"`
+	Command string    `json:"command"`
+	Started time.Time `json:"started"`
+
+	path string
+}
+
+// ProfileBusyError is returned when another minikube command holds the lock of a profile
+type ProfileBusyError struct {
+	Profile string
+	// Path is the lock file, which can be removed if the command which holds it is no longer running
+	Path   string
+	Holder ProfileLock
+}
+
+func (e *ProfileBusyError) Error() string {
+	return fmt.Sprintf("profile %s is busy: 'minikube %s' (pid %d) is running since %s", e.Profile, e.Holder.Command, e.Holder.Pid, e.Holder.Started.Local().Format(time.Kitchen))
+}
+
+// LockPath returns the lock file of a profile. Locks are kept out of the profile directory,
+// which must not be created by a command which fails before the profile exists.
+func LockPath(profile string, miniHome ...string) string {
+	miniPath := constants.GetMinipath()
+	if len(miniHome) > 0 {
+		miniPath = miniHome[0]
+	}
+	return filepath.Join(miniPath, "locks", profile+".lock")
+}
+
+// LockProfile acquires the lock of a profile for command, without waiting: if another running process holds it,
+// a *ProfileBusyError is returned. A lock left by a process which has exited, such as after a crash, is taken over.
+func LockProfile(profile string, command string, miniHome ...string) (*ProfileLock, error) {
+	path := LockPath(profile, miniHome...)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	l := &ProfileLock{Pid: os.Getpid(), Command: command, Started: time.Now(), path: path}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	// The lock is written aside and linked into place, so that other processes never read it half written
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// One attempt to take over a stale lock
+	for attempt := 0; attempt < 2; attempt++ {
+		err := os.Link(tmp.Name(), path)
+		if err == nil {
+			glog.Infof("acquired lock %s", path)
+			return l, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		fi, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			// released in the meantime
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		b, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var holder ProfileLock
+		if err := json.Unmarshal(b, &holder); err != nil {
+			// Older versions of minikube wrote the lock in place: it may still be being written
+			if time.Since(fi.ModTime()) < lockWriteGrace {
+				return nil, &ProfileBusyError{Profile: profile, Path: path, Holder: holder}
+			}
+		} else if daemon.Running(holder.Pid) {
+			return nil, &ProfileBusyError{Profile: profile, Path: path, Holder: holder}
+		}
+		glog.Infof("taking over the stale lock %s of pid %d", path, holder.Pid)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("unable to acquire lock %s", path)
+}
+
+// Unlock releases the lock, unless it was taken over by another process
+func (l *ProfileLock) Unlock() error {
+	var holder ProfileLock
+	b, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := json.Unmarshal(b, &holder); err == nil && holder.Pid != l.Pid {
+		return nil
+	}
+	glog.Infof("releasing lock %s", l.path)
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
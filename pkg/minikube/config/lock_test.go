@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestLockProfile(t *testing.T) {
+	miniDir, err := ioutil.TempDir("", "minikube")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(miniDir)
+
+	l, err := LockProfile("p1", "start", miniDir)
+	if err != nil {
+		t.Fatalf("LockProfile: %v", err)
+	}
+	_, err = LockProfile("p1", "stop", miniDir)
+	busy, ok := err.(*ProfileBusyError)
+	if !ok {
+		t.Fatalf("second LockProfile = %v, want a ProfileBusyError", err)
+	}
+	if busy.Holder.Command != "start" || busy.Holder.Pid != os.Getpid() {
+		t.Errorf("holder = %+v, want start by pid %d", busy.Holder, os.Getpid())
+	}
+	if _, err := LockProfile("p2", "stop", miniDir); err != nil {
+		t.Errorf("LockProfile of another profile: %v", err)
+	}
+	if err := l.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := os.Stat(LockPath("p1", miniDir)); !os.IsNotExist(err) {
+		t.Errorf("lock file still exists after Unlock: %v", err)
+	}
+
+	// A process which has exited
+	c := exec.Command(os.Args[0], "-test.run=^$")
+	if err := c.Run(); err != nil {
+		t.Fatalf("running %s: %v", c.Path, err)
+	}
+	var stale = map[string]string{
+		"dead process": `{"pid":` + strconv.Itoa(c.Process.Pid) + `,"command":"start"}`,
+		"unreadable":   `{"pid":`,
+	}
+	for name, content := range stale {
+		if err := ioutil.WriteFile(LockPath("p1", miniDir), []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		old := time.Now().Add(-2 * lockWriteGrace)
+		if err := os.Chtimes(LockPath("p1", miniDir), old, old); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+		l, err := LockProfile("p1", "start", miniDir)
+		if err != nil {
+			t.Errorf("%s: LockProfile did not take over the stale lock: %v", name, err)
+			continue
+		}
+		if err := l.Unlock(); err != nil {
+			t.Errorf("%s: Unlock: %v", name, err)
+		}
+	}
+
+	// A lock which is still being written by another process is held
+	if err := ioutil.WriteFile(LockPath("p1", miniDir), nil, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := LockProfile("p1", "start", miniDir); err == nil {
+		t.Fatalf("LockProfile took over an empty lock which was just created")
+	} else if _, ok := err.(*ProfileBusyError); !ok {
+		t.Fatalf("LockProfile of an empty lock = %v, want a ProfileBusyError", err)
+	}
+	if err := os.Remove(LockPath("p1", miniDir)); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if files, err := ioutil.ReadDir(filepath.Dir(LockPath("p1", miniDir))); err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	} else if len(files) != 1 || files[0].Name() != "p2.lock" {
+		t.Errorf("lock dir has %d files, want only p2.lock: the temporary lock files are left behind", len(files))
+	}
+
+	// A lock taken over by another process is kept
+	l, err = LockProfile("p1", "start", miniDir)
+	if err != nil {
+		t.Fatalf("LockProfile: %v", err)
+	}
+	other := `{"pid":` + strconv.Itoa(os.Getppid()) + `,"command":"stop"}`
+	if err := ioutil.WriteFile(LockPath("p1", miniDir), []byte(other), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := l.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := os.Stat(LockPath("p1", miniDir)); err != nil {
+		t.Errorf("Unlock removed the lock of another process: %v", err)
+	}
+}
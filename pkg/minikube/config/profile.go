@@ -17,7 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -82,3 +85,29 @@ func profileDirs(miniHome ...string) (dirs []string, err error) {
 	}
 	return dirs, err
 }
+
+// CreateProfile writes the config of a new profile, refusing to replace the config of an existing one
+func CreateProfile(name string, cc Config, miniHome ...string) error {
+	path := constants.GetProfileFile(name, miniHome...)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("profile %s already exists: delete it with 'minikube delete -p %s', or choose another name", name, name)
+	}
+	data, err := json.MarshalIndent(cc, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// ProfileKubeconfig returns the kubeconfig file of a profile started with --kubeconfig-per-profile,
+// or "" if its context is in the shared kubeconfig
+func ProfileKubeconfig(profile string) string {
+	cc, err := DefaultLoader.LoadConfigFromFile(profile)
+	if err != nil || !cc.MachineConfig.KubeconfigPerProfile {
+		return ""
+	}
+	return constants.MakeProfileKubeconfigPath(profile)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -70,3 +72,29 @@ func TestListProfiles(t *testing.T) {
 		t.Errorf("error listing profiles %v", err)
 	}
 }
+
+func TestCreateProfile(t *testing.T) {
+	miniDir, err := ioutil.TempDir("", "minikube")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(miniDir)
+
+	cc := Config{
+		MachineConfig:    MachineConfig{VMDriver: "kvm2"},
+		KubernetesConfig: KubernetesConfig{KubernetesVersion: "v1.15.2"},
+	}
+	if err := CreateProfile("p1", cc, miniDir); err != nil {
+		t.Fatalf("CreateProfile: %v", err)
+	}
+	got, err := DefaultLoader.LoadConfigFromFile("p1", miniDir)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if got.MachineConfig.VMDriver != "kvm2" || got.KubernetesConfig.KubernetesVersion != "v1.15.2" {
+		t.Errorf("loaded %+v, want %+v", got, cc)
+	}
+	if err := CreateProfile("p1", cc, miniDir); err == nil {
+		t.Errorf("CreateProfile replaced an existing profile")
+	}
+}
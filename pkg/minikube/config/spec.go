@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ClusterSpec is a declarative description of a cluster, read by minikube start --file.
+// Empty fields leave the corresponding start flag at its default.
+type ClusterSpec struct {
+	KubernetesVersion string          `json:"kubernetesVersion,omitempty"`
+	Driver            string          `json:"driver,omitempty"`
+	ContainerRuntime  string          `json:"containerRuntime,omitempty"`
+	Bootstrapper      string          `json:"bootstrapper,omitempty"`
+	CPUs              int             `json:"cpus,omitempty"`
+	Memory            string          `json:"memory,omitempty"`
+	DiskSize          string          `json:"diskSize,omitempty"`
+	Nodes             int             `json:"nodes,omitempty"`
+	Addons            map[string]bool `json:"addons,omitempty"`
+	ExtraConfig       []string        `json:"extraConfig,omitempty"`
+	FeatureGates      string          `json:"featureGates,omitempty"`
+	Mounts            []string        `json:"mounts,omitempty"`
+	Ports             []string        `json:"ports,omitempty"`
+}
+
+// LoadClusterSpec reads a YAML or JSON cluster spec, rejecting unknown fields
+func LoadClusterSpec(path string) (*ClusterSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterSpec(data)
+}
+
+func parseClusterSpec(data []byte) (*ClusterSpec, error) {
+	// YAML is a superset of JSON, so this handles both
+	j, err := yaml.ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster spec: %v", err)
+	}
+	var spec ClusterSpec
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("invalid cluster spec: %v", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks the parts of the spec that cannot be checked by the start flags they map to
+func (s *ClusterSpec) Validate() error {
+	if s.Nodes > 1 {
+		return fmt.Errorf("nodes: %d requested, but only single node clusters are supported", s.Nodes)
+	}
+	if len(s.Ports) > 0 {
+		return fmt.Errorf("ports: publishing ports is not supported by any driver yet")
+	}
+	if len(s.Mounts) > 1 {
+		return fmt.Errorf("mounts: only one mount is supported, got %d", len(s.Mounts))
+	}
+	for _, m := range s.Mounts {
+		if !strings.Contains(m, ":") {
+			return fmt.Errorf("mounts: %q must be of the form <source directory>:<target directory>", m)
+		}
+	}
+	for _, e := range s.ExtraConfig {
+		if !strings.Contains(e, ".") || !strings.Contains(e, "=") {
+			return fmt.Errorf("extraConfig: %q must be of the form component.key=value", e)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseClusterSpec(t *testing.T) {
+	var tests = []struct {
+		description string
+		data        string
+		expected    *ClusterSpec
+		shouldErr   bool
+	}{
+		{
+			description: "yaml",
+			data: `
+driver: kvm2
+containerRuntime: containerd
+cpus: 4
+memory: 4g
+addons:
+  ingress: true
+  dashboard: false
+extraConfig:
+- apiserver.v=10
+mounts:
+- /src:/src
+`,
+			expected: &ClusterSpec{
+				Driver:           "kvm2",
+				ContainerRuntime: "containerd",
+				CPUs:             4,
+				Memory:           "4g",
+				Addons:           map[string]bool{"ingress": true, "dashboard": false},
+				ExtraConfig:      []string{"apiserver.v=10"},
+				Mounts:           []string{"/src:/src"},
+			},
+		},
+		{
+			description: "json",
+			data:        `{"kubernetesVersion": "v1.15.2", "nodes": 1}`,
+			expected:    &ClusterSpec{KubernetesVersion: "v1.15.2", Nodes: 1},
+		},
+		{
+			description: "unknown field",
+			data:        `vmdriver: kvm2`,
+			shouldErr:   true,
+		},
+		{
+			description: "multiple nodes",
+			data:        `nodes: 3`,
+			shouldErr:   true,
+		},
+		{
+			description: "ports",
+			data:        `ports: ["8080:80"]`,
+			shouldErr:   true,
+		},
+		{
+			description: "invalid mount",
+			data:        `mounts: ["/src"]`,
+			shouldErr:   true,
+		},
+		{
+			description: "multiple mounts",
+			data:        `mounts: ["/src:/src", "/data:/data"]`,
+			shouldErr:   true,
+		},
+		{
+			description: "invalid extra config",
+			data:        `extraConfig: ["v=10"]`,
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			spec, err := parseClusterSpec([]byte(test.data))
+			if err != nil && !test.shouldErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatalf("expected error but got none: %+v", spec)
+			}
+			if !test.shouldErr && !reflect.DeepEqual(spec, test.expected) {
+				t.Errorf("got %+v, want %+v", spec, test.expected)
+			}
+		})
+	}
+}
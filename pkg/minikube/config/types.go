@@ -36,33 +36,51 @@ type Config struct {
 
 // MachineConfig contains the parameters used to start a cluster.
 type MachineConfig struct {
-	KeepContext         bool // used by start and profile command to or not to switch kubectl's current context
-	MinikubeISO         string
-	Memory              int
-	CPUs                int
-	DiskSize            int
-	VMDriver            string
-	ContainerRuntime    string
-	HyperkitVpnKitSock  string   // Only used by the Hyperkit driver
-	HyperkitVSockPorts  []string // Only used by the Hyperkit driver
-	DockerEnv           []string // Each entry is formatted as KEY=VALUE.
-	InsecureRegistry    []string
-	RegistryMirror      []string
-	HostOnlyCIDR        string // Only used by the virtualbox driver
-	HypervVirtualSwitch string
-	KVMNetwork          string             // Only used by the KVM driver
-	KVMQemuURI          string             // Only used by kvm2
-	KVMGPU              bool               // Only used by kvm2
-	KVMHidden           bool               // Only used by kvm2
-	Downloader          util.ISODownloader `json:"-"`
-	DockerOpt           []string           // Each entry is formatted as KEY=VALUE.
-	DisableDriverMounts bool               // Only used by virtualbox
-	NFSShare            []string
-	NFSSharesRoot       string
-	UUID                string // Only used by hyperkit to restore the mac address
-	NoVTXCheck          bool   // Only used by virtualbox
-	DNSProxy            bool   // Only used by virtualbox
-	HostDNSResolver     bool   // Only used by virtualbox
+	KeepContext           bool // used by start and profile command to or not to switch kubectl's current context
+	KubeconfigPerProfile  bool // the context is written to a kubeconfig file of its own, rather than to the shared one
+	MinikubeISO           string
+	Memory                int
+	CPUs                  int
+	DiskSize              int
+	VMDriver              string
+	ContainerRuntime      string
+	HyperkitVpnKitSock    string   // Only used by the Hyperkit driver
+	HyperkitVSockPorts    []string // Only used by the Hyperkit driver
+	DockerEnv             []string // Each entry is formatted as KEY=VALUE.
+	InsecureRegistry      []string
+	RegistryMirror        []string
+	HostOnlyCIDR          string // Only used by the virtualbox driver
+	HypervVirtualSwitch   string
+	KVMNetwork            string             // Only used by the KVM driver
+	KVMQemuURI            string             // Only used by kvm2
+	KVMGPU                bool               // Only used by kvm2
+	KVMGPUVendor          string             // Only used by kvm2: nvidia, the default, amd or intel
+	KVMHidden             bool               // Only used by kvm2
+	Downloader            util.ISODownloader `json:"-"`
+	DockerOpt             []string           // Each entry is formatted as KEY=VALUE.
+	DisableDriverMounts   bool               // Only used by virtualbox
+	NFSShare              []string
+	NFSSharesRoot         string
+	UUID                  string   // Only used by hyperkit to restore the mac address
+	NoVTXCheck            bool     // Only used by virtualbox
+	DNSProxy              bool     // Only used by virtualbox
+	HostDNSResolver       bool     // Only used by virtualbox
+	QemuNetwork           string   // Only used by qemu2
+	QemuFirmwarePath      string   // Only used by qemu2
+	SocketVMnetClientPath string   // Only used by qemu2
+	SocketVMnetPath       string   // Only used by qemu2
+	APIServerPort         int      // Only used by qemu2, to forward the apiserver port
+	IPFamily              string   // Only used by kvm2, to add IPv6 to the private network
+	VirtiofsShares        []string // Only used by qemu2, kvm2 and vfkit
+	USBDevices            []string // Only used by qemu2 and kvm2: vendor:product
+	NUMANodes             int      // Only used by qemu2 and kvm2
+	SRIOVVFs              int      // Only used by kvm2: virtual functions of an emulated igb NIC
+	ExtraDisks            int      // Only used by kvm2, qemu2 and hyperkit
+	HugePages             string   // size:count,... such as 2Mi:512
+	Rootless              bool     // Only used by the docker runtime
+	StaticIP              string   // Only used by kvm2
+	MountString           string   // The host folder mounted by start, empty without --mount
+	MountType             string   // The type of MountString
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -77,11 +95,18 @@ type KubernetesConfig struct {
 	DNSDomain         string
 	ContainerRuntime  string
 	CRISocket         string
+	Snapshotter       string
 	NetworkPlugin     string
 	FeatureGates      string
 	ServiceCIDR       string
+	IPFamily          string
 	ImageRepository   string
 	ExtraOptions      util.ExtraOptionSlice
+	CustomCACert      string   // Path to a CA certificate which replaces the generated cluster and front-proxy CAs
+	CustomCAKey       string   // Path to the private key of CustomCACert
+	ExtraSANs         []string // Names and IPs added to the apiserver certificate with 'minikube certs add-san'
+	EncryptSecrets    bool     // Whether the secrets are encrypted in etcd, with the key of the profile
+	AuditPolicy       string   // Path of the audit policy of the apiserver, which enables its audit log
 
 	ShouldLoadCachedImages bool
 	EnableDefaultCNI       bool
@@ -0,0 +1,207 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package console draws the screen of minikube console, a terminal UI of the profiles and of the cluster of the selected one
+package console
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/events"
+)
+
+// Help lists the keys of the console
+const Help = "up/down: select profile   s: stop   o: open service   l: tail logs   r: refresh   q: quit"
+
+// Profile is a line of the list of profiles
+type Profile struct {
+	Name              string
+	Host              string
+	Driver            string
+	KubernetesVersion string
+}
+
+// Item is the health of a node or of an addon
+type Item struct {
+	Name    string
+	Status  string
+	Message string
+	// OK is whether the node or addon is healthy, so that its status is drawn in green rather than in red
+	OK bool
+}
+
+// Screen is what the console shows
+type Screen struct {
+	Profiles []Profile
+	// Selected is the index of the profile whose cluster is shown
+	Selected  int
+	Kubelet   string
+	APIServer string
+	Nodes     []Item
+	Addons    []Item
+	// Events are the recent events of the profile, the latest last
+	Events []events.Event
+	// Status is the line under the screen: the result of the last action, or a prompt
+	Status  string
+	Updated time.Time
+}
+
+// The ANSI escape sequences of the console
+const (
+	home      = "\x1b[H"
+	clearLine = "\x1b[K"
+	clearDown = "\x1b[J"
+	reset     = "\x1b[0m"
+	bold      = "\x1b[1m"
+	reverse   = "\x1b[7m"
+	red       = "\x1b[31m"
+	green     = "\x1b[32m"
+	yellow    = "\x1b[33m"
+	faint     = "\x1b[2m"
+)
+
+// ClearScreen clears the terminal, before running a command outside of the console
+const ClearScreen = "\x1b[H\x1b[2J"
+
+// segment is a part of a line, drawn with an escape sequence
+type segment struct {
+	text  string
+	style string
+}
+
+// line is a line of the screen
+type line []segment
+
+// Render draws the screen on a terminal of width columns and height rows. Events which don't fit are left out, the oldest first.
+func Render(w io.Writer, s Screen, width int, height int) error {
+	var top []line
+	top = append(top, line{{text: fmt.Sprintf("minikube console: %d profile(s)", len(s.Profiles)), style: bold}, {text: "   updated " + s.Updated.Format("15:04:05"), style: faint}}, nil)
+	if len(s.Profiles) == 0 {
+		top = append(top, line{{text: "No minikube profile was found. You can create one using `minikube start`."}})
+	} else {
+		top = append(top, line{{text: fmt.Sprintf("  %-20s %-10s %-12s %s", "PROFILE", "HOST", "DRIVER", "KUBERNETES"), style: bold}})
+		for i, p := range s.Profiles {
+			l := line{{text: fmt.Sprintf("  %-20s %-10s %-12s %s", p.Name, p.Host, p.Driver, p.KubernetesVersion)}}
+			if i == s.Selected {
+				l = line{{text: fmt.Sprintf("> %-20s %-10s %-12s %s", p.Name, p.Host, p.Driver, p.KubernetesVersion), style: reverse}}
+			}
+			top = append(top, l)
+		}
+		p := s.Profiles[s.Selected]
+		top = append(top, nil, line{{text: p.Name, style: bold}, {text: ": host "}, status(p.Host, p.Host == "Running"),
+			{text: ", kubelet "}, status(s.Kubelet, s.Kubelet == "Running"), {text: ", apiserver "}, status(s.APIServer, s.APIServer == "Running")})
+		top = append(top, items("NODES", s.Nodes)...)
+		top = append(top, items("ADDONS", s.Addons)...)
+	}
+
+	bottom := line{{text: Help, style: faint}}
+	if s.Status != "" {
+		bottom = line{{text: s.Status}}
+	}
+	// The events fill the rows between the top and the bottom line
+	var evs []line
+	if rows := height - len(top) - 3; rows > 0 && len(s.Events) > 0 {
+		list := s.Events
+		if len(list) > rows {
+			list = list[len(list)-rows:]
+		}
+		evs = append(evs, nil, line{{text: "RECENT EVENTS", style: bold}})
+		for _, e := range list {
+			style := ""
+			if e.Type == "Warning" {
+				style = yellow
+			}
+			evs = append(evs, line{{text: fmt.Sprintf("  %s  %-10s %-16s %-30s %s", e.Time.Local().Format("15:04:05"), e.Source, e.Reason, e.Object, oneLine(e.Message)), style: style}})
+		}
+	}
+
+	lines := append(top, evs...)
+	if height > 1 && len(lines) > height-1 {
+		lines = lines[:height-1]
+	}
+	var b bytes.Buffer
+	b.WriteString(home)
+	for _, l := range lines {
+		b.WriteString(l.render(width))
+		b.WriteString(clearLine + "\r\n")
+	}
+	b.WriteString(clearDown)
+	if height > len(lines)+1 {
+		b.WriteString(fmt.Sprintf("\x1b[%d;1H", height))
+	}
+	b.WriteString(bottom.render(width))
+	b.WriteString(clearLine)
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// status returns the segment of a status, in green if ok
+func status(s string, ok bool) segment {
+	if s == "" {
+		s = "-"
+	}
+	if ok {
+		return segment{text: s, style: green}
+	}
+	return segment{text: s, style: red}
+}
+
+// items returns the lines of a list of nodes or addons under a title
+func items(title string, list []Item) []line {
+	if len(list) == 0 {
+		return nil
+	}
+	lines := []line{{{text: title, style: bold}}}
+	for _, i := range list {
+		l := line{{text: fmt.Sprintf("  %-24s ", i.Name)}, status(i.Status, i.OK)}
+		if i.Message != "" {
+			l = append(l, segment{text: "  " + oneLine(i.Message), style: faint})
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// oneLine replaces the line breaks of a message, which would break the screen
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// render returns the line with its escape sequences, cut to width columns
+func (l line) render(width int) string {
+	var b strings.Builder
+	left := width
+	for _, s := range l {
+		if left <= 0 {
+			break
+		}
+		text := []rune(s.text)
+		if len(text) > left {
+			text = text[:left]
+		}
+		left -= len(text)
+		if s.style != "" {
+			b.WriteString(s.style + string(text) + reset)
+		} else {
+			b.WriteString(string(text))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/events"
+)
+
+// escapes matches the ANSI escape sequences of the screen
+var escapes = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+func TestRender(t *testing.T) {
+	s := Screen{
+		Profiles: []Profile{
+			{Name: "minikube", Host: "Running", Driver: "virtualbox", KubernetesVersion: "v1.15.2"},
+			{Name: "dev", Host: "Stopped", Driver: "kvm2", KubernetesVersion: "v1.14.0"},
+		},
+		Kubelet:   "Running",
+		APIServer: "Running",
+		Nodes:     []Item{{Name: "minikube", Status: "Ready", OK: true}},
+		Addons:    []Item{{Name: "ingress", Status: "NotReady", Message: "0/1 ready,\nwaiting for deployment/nginx"}},
+		Updated:   time.Date(2019, 8, 5, 10, 0, 0, 0, time.Local),
+	}
+	for i := 0; i < 10; i++ {
+		s.Events = append(s.Events, events.Event{Time: s.Updated, Source: events.Minikube, Type: "Normal", Reason: "Completed", Object: "profile/minikube", Message: "event " + string('0'+rune(i))})
+	}
+
+	var b bytes.Buffer
+	if err := Render(&b, s, 120, 20); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	got := escapes.ReplaceAllString(b.String(), "")
+	lines := strings.Split(got, "\r\n")
+	if len(lines) != 20 {
+		t.Errorf("Render() wrote %d lines, want 20:\n%s", len(lines), got)
+	}
+	for _, l := range lines {
+		if len([]rune(l)) > 120 {
+			t.Errorf("line %q is wider than 120 columns", l)
+		}
+	}
+	for _, want := range []string{"> minikube", "  dev", "Ready", "0/1 ready, waiting", "event 9", Help} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() = %q, want it to contain %q", got, want)
+		}
+	}
+	// The oldest events don't fit
+	if strings.Contains(got, "event 0") {
+		t.Errorf("Render() = %q, want the oldest events to be left out", got)
+	}
+
+	s.Status = "Stopping dev ..."
+	b.Reset()
+	if err := Render(&b, s, 120, 20); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got := escapes.ReplaceAllString(b.String(), ""); !strings.HasSuffix(got, "Stopping dev ...") || strings.Contains(got, Help) {
+		t.Errorf("Render() = %q, want the status instead of the help", got)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("q\x1b[A\x1bOB\x1b[1;5C\r\x7f\x03é"))
+	want := []Key{{Code: KeyRune, Rune: 'q'}, {Code: KeyUp}, {Code: KeyDown}, {Code: KeyUnknown}, {Code: KeyEnter}, {Code: KeyBackspace}, {Code: KeyInterrupt}, {Code: KeyRune, Rune: 'é'}}
+	for _, w := range want {
+		got, err := ReadKey(r)
+		if err != nil {
+			t.Fatalf("ReadKey: %v", err)
+		}
+		if got != w {
+			t.Errorf("ReadKey() = %v, want %v", got, w)
+		}
+	}
+}
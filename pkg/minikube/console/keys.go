@@ -0,0 +1,94 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package console
+
+import (
+	"bufio"
+)
+
+// KeyCode is a key of the keyboard which is not a character
+type KeyCode int
+
+// The keys which the console tells apart
+const (
+	// KeyRune is a character, whose value is in Key.Rune
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyEnter
+	KeyBackspace
+	KeyEscape
+	// KeyInterrupt is Ctrl-C, which a terminal in raw mode doesn't turn into a signal
+	KeyInterrupt
+	KeyUnknown
+)
+
+// Key is a key pressed in a terminal in raw mode
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ReadKey reads a key from a terminal in raw mode. The arrow keys send escape sequences, which are read at once.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	switch c {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case 0x7f, 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case 0x03:
+		return Key{Code: KeyInterrupt}, nil
+	case 0x1b:
+		return readEscape(r)
+	}
+	return Key{Code: KeyRune, Rune: c}, nil
+}
+
+// readEscape reads the rest of an escape sequence, such as "\x1b[A" for the up arrow. A lone escape is the escape key.
+func readEscape(r *bufio.Reader) (Key, error) {
+	if r.Buffered() == 0 {
+		return Key{Code: KeyEscape}, nil
+	}
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	if c != '[' && c != 'O' {
+		return Key{Code: KeyUnknown}, nil
+	}
+	// CSI sequences end with a letter or ~, after optional parameters
+	for {
+		c, _, err = r.ReadRune()
+		if err != nil {
+			return Key{}, err
+		}
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || c == '~' {
+			break
+		}
+	}
+	switch c {
+	case 'A':
+		return Key{Code: KeyUp}, nil
+	case 'B':
+		return Key{Code: KeyDown}, nil
+	}
+	return Key{Code: KeyUnknown}, nil
+}
@@ -18,6 +18,7 @@ package constants
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -86,12 +87,23 @@ const DriverHyperv = "hyperv"
 // DriverParallels is the parallels driver option name
 const DriverParallels = "parallels"
 
+// DriverQemu2 is the qemu2 driver option name
+const DriverQemu2 = "qemu2"
+
+// DriverVfkit is the vfkit driver option name for mac os
+const DriverVfkit = "vfkit"
+
 // DefaultMinipath is the default Minikube path (under the home directory)
 var DefaultMinipath = filepath.Join(homedir.HomeDir(), ".minikube")
 
 // KubeconfigPath is the path to the Kubernetes client config
 var KubeconfigPath = clientcmd.RecommendedHomeFile
 
+// MakeProfileKubeconfigPath returns the kubeconfig file of a profile started with --kubeconfig-per-profile
+func MakeProfileKubeconfigPath(profile string) string {
+	return filepath.Join(clientcmd.RecommendedConfigDir, "minikube-"+profile+".yaml")
+}
+
 // KubeconfigEnvVar is the env var to check for the Kubernetes client config
 var KubeconfigEnvVar = clientcmd.RecommendedConfigPathEnvVar
 
@@ -113,6 +125,19 @@ const DefaultStorageClassProvisioner = "standard"
 // Cache is used to modify the cache field in the config file
 const Cache = "cache"
 
+// DownloadMirrors is the config key of the comma-separated mirrors of the files minikube downloads
+const DownloadMirrors = "download-mirrors"
+
+// DownloadCache is the config key of the URL of a cache served by minikube cache serve, which downloads are tried from first
+const DownloadCache = "download-cache"
+
+const (
+	// VerifyDownloads is the config key of how the signatures of downloads are verified: strict, warn or off
+	VerifyDownloads = "verify-downloads"
+	// VerifyDownloadsKey is the config key of the path of the public key which verifies the signatures of downloads
+	VerifyDownloadsKey = "verify-downloads-key"
+)
+
 // TunnelRegistryPath returns the path to the runnel registry file
 func TunnelRegistryPath() string {
 	return filepath.Join(GetMinipath(), "tunnels.json")
@@ -150,6 +175,9 @@ const (
 kubelet: {{.Kubelet}}
 apiserver: {{.APIServer}}
 kubectl: {{.Kubeconfig}}
+{{range .Nodes}}node {{.Name}}: {{.Kubelet}}{{if .Message}} ({{.Message}}){{end}}
+{{end}}{{range .Addons}}addon {{.Name}}: {{if .Ready}}Ready{{else}}NotReady ({{.Message}}){{end}}
+{{end}}ready: {{.Ready}}
 `
 	// DefaultAddonListFormat is the default format of addon list
 	DefaultAddonListFormat = "- {{.AddonName}}: {{.AddonStatus}}\n"
@@ -159,6 +187,8 @@ kubectl: {{.Kubeconfig}}
 	DefaultCacheListFormat = "{{.CacheImage}}\n"
 	// GithubMinikubeReleasesURL is the URL of the minikube github releases JSON file
 	GithubMinikubeReleasesURL = "https://storage.googleapis.com/minikube/releases.json"
+	// DefaultProblemsURL is the URL of the remote database of known problems, which is signed as the downloads of minikube
+	DefaultProblemsURL = "https://storage.googleapis.com/minikube/problems.json"
 	// DefaultWait is the default wait time, in seconds
 	DefaultWait = 20
 	// DefaultInterval is the default interval, in seconds
@@ -184,6 +214,18 @@ var NewestKubernetesVersion = "v1.15.2"
 // OldestKubernetesVersion is the oldest Kubernetes version to test against
 var OldestKubernetesVersion = "v1.10.13"
 
+// DefaultK3sKubernetesVersion is the default kubernetes version used by the k3s bootstrapper
+var DefaultK3sKubernetesVersion = "v1.17.4"
+
+// OldestK3sKubernetesVersion is the oldest kubernetes version the k3s bootstrapper supports
+var OldestK3sKubernetesVersion = "v1.17.0"
+
+// DefaultK0sKubernetesVersion is the default kubernetes version used by the k0s bootstrapper
+var DefaultK0sKubernetesVersion = "v1.21.3"
+
+// OldestK0sKubernetesVersion is the oldest kubernetes version the k0s bootstrapper supports
+var OldestK0sKubernetesVersion = "v1.21.0"
+
 // ConfigFilePath is the path of the config directory
 var ConfigFilePath = MakeMiniPath("config")
 
@@ -214,6 +256,9 @@ const AddonsPath = "/etc/kubernetes/addons"
 // FilesPath is the default path of files
 const FilesPath = "/files"
 
+// RegistryProxyCacheURL is where the registry-proxy-cache addon listens on the node
+const RegistryProxyCacheURL = "http://localhost:5001"
+
 const (
 	// KubeletServiceFile is the path to the kubelet systemd service
 	KubeletServiceFile = "/lib/systemd/system/kubelet.service"
@@ -221,8 +266,24 @@ const (
 	KubeletSystemdConfFile = "/etc/systemd/system/kubelet.service.d/10-kubeadm.conf"
 	// KubeadmConfigFile is the path to the kubeadm configuration
 	KubeadmConfigFile = "/var/lib/kubeadm.yaml"
+	// EtcdDataDir is the path etcd keeps its state in, with the kubeadm bootstrapper
+	EtcdDataDir = "/data/minikube"
 	// DefaultCNIConfigPath is the path to the CNI configuration
 	DefaultCNIConfigPath = "/etc/cni/net.d/k8s.conf"
+	// K3sServiceFile is the path to the k3s systemd service
+	K3sServiceFile = "/lib/systemd/system/k3s.service"
+	// K3sDataDir is the path k3s keeps its state and certificates in
+	K3sDataDir = "/var/lib/minikube/k3s"
+	// K3sReleaseSuffix is appended to the kubernetes version to get the k3s release
+	K3sReleaseSuffix = "+k3s1"
+	// K0sServiceFile is the path to the k0s systemd service
+	K0sServiceFile = "/lib/systemd/system/k0s.service"
+	// K0sConfigFile is the path to the k0s cluster configuration
+	K0sConfigFile = "/etc/k0s/k0s.yaml"
+	// K0sDataDir is the path k0s keeps its state and certificates in
+	K0sDataDir = "/var/lib/minikube/k0s"
+	// K0sReleaseSuffix is appended to the kubernetes version to get the k0s release
+	K0sReleaseSuffix = "+k0s.0"
 )
 
 const (
@@ -254,6 +315,31 @@ func GetKubernetesReleaseURLSHA1(binaryName, version, osName, archName string) s
 	return fmt.Sprintf("%s.sha1", GetKubernetesReleaseURL(binaryName, version, osName, archName))
 }
 
+// GetK3sReleaseURL gets the location of the k3s binary for a kubernetes version
+func GetK3sReleaseURL(version, archName string) string {
+	binary := "k3s"
+	if archName != "amd64" {
+		binary = fmt.Sprintf("k3s-%s", archName)
+	}
+	return fmt.Sprintf("https://github.com/rancher/k3s/releases/download/%s/%s", url.PathEscape(version+K3sReleaseSuffix), binary)
+}
+
+// GetK3sReleaseURLSHA256 gets the location of the k3s checksums for a kubernetes version
+func GetK3sReleaseURLSHA256(version, archName string) string {
+	return fmt.Sprintf("https://github.com/rancher/k3s/releases/download/%s/sha256sum-%s.txt", url.PathEscape(version+K3sReleaseSuffix), archName)
+}
+
+// GetK0sReleaseURL gets the location of the k0s binary for a kubernetes version
+func GetK0sReleaseURL(version, archName string) string {
+	release := version + K0sReleaseSuffix
+	return fmt.Sprintf("https://github.com/k0sproject/k0s/releases/download/%s/k0s-%s-%s", url.PathEscape(release), release, archName)
+}
+
+// GetK0sReleaseURLSHA256 gets the location of the k0s checksums for a kubernetes version
+func GetK0sReleaseURLSHA256(version string) string {
+	return fmt.Sprintf("https://github.com/k0sproject/k0s/releases/download/%s/sha256sums.txt", url.PathEscape(version+K0sReleaseSuffix))
+}
+
 // IsMinikubeChildProcess is the name of "is minikube child process" variable
 const IsMinikubeChildProcess = "IS_MINIKUBE_CHILD_PROCESS"
 
@@ -265,6 +351,16 @@ func GetKubeadmCachedBinaries() []string {
 	return []string{"kubelet", "kubeadm"}
 }
 
+// GetK3sCachedBinaries gets the binaries to cache for k3s
+func GetK3sCachedBinaries() []string {
+	return []string{"k3s"}
+}
+
+// GetK0sCachedBinaries gets the binaries to cache for k0s
+func GetK0sCachedBinaries() []string {
+	return []string{"k0s"}
+}
+
 // GetKubeadmCachedImages gets the images to cache for kubeadm for a version
 func GetKubeadmCachedImages(imageRepository string, kubernetesVersionStr string) (string, []string) {
 	minikubeRepository := imageRepository
@@ -390,9 +486,8 @@ func GetKubeadmCachedImages(imageRepository string, kubernetesVersionStr string)
 	}
 
 	images = append(images, []string{
-		imageRepository + "kubernetes-dashboard" + ArchTag(true) + "v1.10.1",
 		imageRepository + "kube-addon-manager" + ArchTag(false) + "v9.0",
-		minikubeRepository + "storage-provisioner" + ArchTag(false) + "v1.8.1",
+		minikubeRepository + "storage-provisioner" + ArchTag(false) + "v1.9.0",
 	}...)
 
 	return podInfraContainerImage, images
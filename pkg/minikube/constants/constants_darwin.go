@@ -27,4 +27,6 @@ var SupportedVMDrivers = [...]string{
 	DriverVmwareFusion,
 	DriverHyperkit,
 	DriverVmware,
+	DriverQemu2,
+	DriverVfkit,
 }
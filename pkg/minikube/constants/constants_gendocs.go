@@ -28,6 +28,8 @@ var SupportedVMDrivers = [...]string{
 	DriverHyperv,
 	DriverHyperkit,
 	DriverKvm2,
+	DriverQemu2,
+	DriverVfkit,
 	DriverVmware,
 	DriverNone,
 }
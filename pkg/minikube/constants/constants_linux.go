@@ -31,6 +31,7 @@ var SupportedVMDrivers = [...]string{
 	DriverParallels,
 	DriverVmwareFusion,
 	DriverKvm2,
+	DriverQemu2,
 	DriverVmware,
 	DriverNone,
 }
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credential implements the exec credential protocol of client-go, so that kubeconfig can get the client
+// certificate of minikube from the minikube binary, rather than from a copy which goes stale once it is regenerated
+package credential
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// APIVersion is the version of the exec credential protocol
+const APIVersion = "client.authentication.k8s.io/v1beta1"
+
+// ExecCredential is what an exec credential plugin writes to its standard output
+type ExecCredential struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Status     Status `json:"status"`
+}
+
+// Status holds the credential: here, a client certificate and its key, in PEM
+type Status struct {
+	// ExpirationTimestamp is when the certificate expires, after which clients run the plugin again
+	ExpirationTimestamp   *time.Time `json:"expirationTimestamp,omitempty"`
+	ClientCertificateData string     `json:"clientCertificateData"`
+	ClientKeyData         string     `json:"clientKeyData"`
+}
+
+// New returns the credential of a client certificate and its key, which are read from disk each time
+func New(certFile string, keyFile string) (*ExecCredential, error) {
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading client certificate")
+	}
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading client key")
+	}
+	b, _ := pem.Decode(cert)
+	if b == nil {
+		return nil, errors.Errorf("no PEM certificate in %s", certFile)
+	}
+	c, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", certFile)
+	}
+	expires := c.NotAfter.UTC()
+	return &ExecCredential{
+		APIVersion: APIVersion,
+		Kind:       "ExecCredential",
+		Status: Status{
+			ExpirationTimestamp:   &expires,
+			ClientCertificateData: string(cert),
+			ClientKeyData:         string(key),
+		},
+	}, nil
+}
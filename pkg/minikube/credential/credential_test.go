@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credential
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/util"
+)
+
+func TestNew(t *testing.T) {
+	dir, err := ioutil.TempDir("", "credential")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+
+	if _, err := New(certFile, keyFile); err == nil {
+		t.Errorf("New() without a certificate succeeded, want an error")
+	}
+	if err := util.GenerateCACert(certFile, keyFile, "minikubeUser"); err != nil {
+		t.Fatalf("GenerateCACert: %v", err)
+	}
+	c, err := New(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if c.APIVersion != APIVersion || c.Kind != "ExecCredential" {
+		t.Errorf("New() = %s %s, want %s ExecCredential", c.APIVersion, c.Kind, APIVersion)
+	}
+	cert, _ := ioutil.ReadFile(certFile)
+	key, _ := ioutil.ReadFile(keyFile)
+	if c.Status.ClientCertificateData != string(cert) || c.Status.ClientKeyData != string(key) {
+		t.Errorf("New() did not return the certificate and the key in PEM")
+	}
+	if c.Status.ExpirationTimestamp == nil || c.Status.ExpirationTimestamp.Before(time.Now()) {
+		t.Errorf("ExpirationTimestamp = %v, want the expiry of the certificate", c.Status.ExpirationTimestamp)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/util"
+)
+
+// buildkitSocket is where buildkitd listens inside the VM
+const buildkitSocket = "/run/buildkit/buildkitd.sock"
+
+// qemuArchs are the names used by the qemu user emulators of the ISO for the architectures of OCI platforms
+var qemuArchs = map[string]string{
+	"386":     "i386",
+	"amd64":   "x86_64",
+	"arm":     "arm",
+	"arm64":   "aarch64",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+}
+
+// BuildOptions are the options for building an image from a Dockerfile
+type BuildOptions struct {
+	// Tag is the name given to the built image
+	Tag string
+	// Dockerfile is the path of the Dockerfile, relative to the build context
+	Dockerfile string
+	// BuildArgs are KEY=VALUE build-time variables
+	BuildArgs []string
+	// CacheFrom are cache sources to import, e.g. type=local,src=/data/cache
+	CacheFrom []string
+	// CacheTo are cache destinations to export, e.g. type=local,dest=/data/cache
+	CacheTo []string
+	// Platforms are the platforms to build for, e.g. linux/arm64. If empty, the image is built for the VM
+	Platforms []string
+}
+
+func (o BuildOptions) dockerfile() string {
+	if o.Dockerfile == "" {
+		return "Dockerfile"
+	}
+	return o.Dockerfile
+}
+
+// enableBuildkit idempotently starts buildkitd with the given worker options
+func enableBuildkit(cr CommandRunner, opts string) error {
+	if err := cr.Run("systemctl is-active --quiet service buildkit"); err == nil {
+		return nil
+	}
+	if err := cr.Run("command -v buildkitd"); err != nil {
+		return errors.Wrap(err, "buildkitd is not available, this may require a newer minikube ISO")
+	}
+	c := fmt.Sprintf(`sudo mkdir -p /etc/sysconfig && printf %%s "BUILDKITD_OPTIONS='%s'" | sudo tee /etc/sysconfig/buildkit`, opts)
+	if err := cr.Run(c); err != nil {
+		return errors.Wrap(err, "buildkit options")
+	}
+	glog.Infof("Starting buildkitd with: %s", opts)
+	return cr.Run("sudo systemctl start buildkit")
+}
+
+// buildctlCmd returns the buildctl command which builds dir with the dockerfile frontend
+func buildctlCmd(dir string, o BuildOptions, output string) string {
+	df := path.Join(dir, o.dockerfile())
+	args := []string{
+		"sudo", "buildctl", "--addr", "unix://" + buildkitSocket, "build",
+		"--frontend", "dockerfile.v0",
+		"--local", util.ShellQuote("context=" + dir),
+		"--local", util.ShellQuote("dockerfile=" + path.Dir(df)),
+		"--opt", util.ShellQuote("filename=" + path.Base(df)),
+	}
+	for _, a := range o.BuildArgs {
+		args = append(args, "--opt", util.ShellQuote("build-arg:"+a))
+	}
+	if len(o.Platforms) > 0 {
+		args = append(args, "--opt", util.ShellQuote("platform="+strings.Join(o.Platforms, ",")))
+	}
+	for _, c := range o.CacheFrom {
+		args = append(args, "--import-cache", util.ShellQuote(c))
+	}
+	for _, c := range o.CacheTo {
+		args = append(args, "--export-cache", util.ShellQuote(c))
+	}
+	args = append(args, "--output", util.ShellQuote(output))
+	return strings.Join(args, " ")
+}
+
+// dockerBuildCmd returns the docker build command which builds dir with BuildKit enabled.
+// The docker daemon can only import caches from images, and only export them inline.
+func dockerBuildCmd(dir string, o BuildOptions) (string, error) {
+	args := []string{"sudo", "env", "DOCKER_BUILDKIT=1", "docker", "build", "-t", util.ShellQuote(o.Tag), "-f", util.ShellQuote(path.Join(dir, o.dockerfile()))}
+	for _, a := range o.BuildArgs {
+		args = append(args, "--build-arg", util.ShellQuote(a))
+	}
+	if len(o.Platforms) > 1 {
+		return "", fmt.Errorf("the docker runtime stores a single platform per image, got %s", strings.Join(o.Platforms, ","))
+	}
+	if len(o.Platforms) == 1 {
+		args = append(args, "--platform", util.ShellQuote(o.Platforms[0]))
+	}
+	for _, c := range o.CacheFrom {
+		ref, err := cacheImageRef(c)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--cache-from", util.ShellQuote(ref))
+	}
+	for _, c := range o.CacheTo {
+		if c != "type=inline" {
+			return "", fmt.Errorf("cache export %q is not supported by the docker runtime, only type=inline is", c)
+		}
+		args = append(args, "--build-arg", "BUILDKIT_INLINE_CACHE=1")
+	}
+	args = append(args, util.ShellQuote(dir))
+	return strings.Join(args, " "), nil
+}
+
+// qemuArch returns the qemu name of the architecture of a platform, such as aarch64 for linux/arm64/v8
+func qemuArch(platform string) (string, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] != "linux" {
+		return "", fmt.Errorf("invalid platform %q, expected linux/<arch>[/<variant>]", platform)
+	}
+	arch, ok := qemuArchs[parts[1]]
+	if !ok {
+		return "", fmt.Errorf("unsupported architecture %q of platform %s", parts[1], platform)
+	}
+	return arch, nil
+}
+
+// checkEmulators returns an error if the binaries of a platform other than the one of the VM can't be run,
+// due to a missing qemu emulator in binfmt_misc
+func checkEmulators(cr CommandRunner, platforms []string) error {
+	if len(platforms) == 0 {
+		return nil
+	}
+	native, err := cr.CombinedOutput("uname -m")
+	if err != nil {
+		return errors.Wrap(err, "uname")
+	}
+	for _, p := range platforms {
+		arch, err := qemuArch(p)
+		if err != nil {
+			return err
+		}
+		if arch == strings.TrimSpace(native) {
+			continue
+		}
+		if err := cr.Run("test -e /proc/sys/fs/binfmt_misc/qemu-" + arch); err != nil {
+			return fmt.Errorf("no emulator is registered for %s, this may require a newer minikube ISO", p)
+		}
+	}
+	return nil
+}
+
+// cacheImageRef returns the image of a cache source: either a plain image name or type=registry,ref=<image>
+func cacheImageRef(spec string) (string, error) {
+	if !strings.Contains(spec, "=") {
+		return spec, nil
+	}
+	attrs := map[string]string{}
+	for _, kv := range strings.Split(spec, ",") {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			return "", fmt.Errorf("invalid cache source %q", spec)
+		}
+		attrs[parts[0]] = parts[1]
+	}
+	if attrs["type"] != "registry" || attrs["ref"] == "" {
+		return "", fmt.Errorf("cache source %q is not supported by the docker runtime, only images are", spec)
+	}
+	return attrs["ref"], nil
+}
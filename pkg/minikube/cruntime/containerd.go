@@ -19,15 +19,18 @@ package cruntime
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
 // Containerd contains containerd runtime state
 type Containerd struct {
-	Socket string
-	Runner CommandRunner
+	Socket      string
+	Runner      CommandRunner
+	Snapshotter string
 }
 
 // Name is a human readable name for containerd
@@ -92,6 +95,11 @@ func (r *Containerd) Enable(disOthers bool) error {
 	if err := enableIPForwarding(r.Runner); err != nil {
 		return err
 	}
+	if r.Snapshotter == StargzSnapshotter {
+		if err := r.enableStargz(); err != nil {
+			return errors.Wrap(err, "stargz snapshotter")
+		}
+	}
 	// Oherwise, containerd will fail API requests with 'Unimplemented'
 	return r.Runner.Run("sudo systemctl restart containerd")
 }
@@ -101,10 +109,75 @@ func (r *Containerd) Disable() error {
 	return r.Runner.Run("sudo systemctl stop containerd")
 }
 
-// LoadImage loads an image into this runtime
+// LoadImage loads an image into the namespace used by Kubernetes.
+// All the platforms of manifest lists are kept, so that the image can be pushed again.
 func (r *Containerd) LoadImage(path string) error {
 	glog.Infof("Loading image: %s", path)
-	return r.Runner.Run(fmt.Sprintf("sudo ctr images import %s", path))
+	return r.Runner.Run(fmt.Sprintf("sudo ctr -n=k8s.io images import --all-platforms %s", path))
+}
+
+// SaveImage exports an image of the namespace used by Kubernetes to an OCI archive
+func (r *Containerd) SaveImage(image string, path string) error {
+	glog.Infof("Saving image %s: %s", image, path)
+	return r.Runner.Run(fmt.Sprintf("sudo ctr -n=k8s.io images export %s %s", path, image))
+}
+
+// PullImage pulls an image with crictl, into the image store used by Kubernetes
+func (r *Containerd) PullImage(image string) error {
+	return pullCRIImage(r.Runner, image)
+}
+
+// BuildImage builds an image with buildkitd, which stores it in the containerd namespace used by Kubernetes
+func (r *Containerd) BuildImage(dir string, opts BuildOptions) error {
+	glog.Infof("Building image: %s", dir)
+	if err := checkEmulators(r.Runner, opts.Platforms); err != nil {
+		return err
+	}
+	if err := r.EnableBuildkit(); err != nil {
+		return err
+	}
+	return r.Runner.Run(buildctlCmd(dir, opts, fmt.Sprintf("type=image,name=%s", opts.Tag)))
+}
+
+// EnableBuildkit idempotently starts buildkitd, storing the images it builds in the containerd namespace used by Kubernetes
+func (r *Containerd) EnableBuildkit() error {
+	workerOpts := fmt.Sprintf("--oci-worker=false --containerd-worker=true --containerd-worker-addr=%s --containerd-worker-namespace=k8s.io", r.SocketPath())
+	return enableBuildkit(r.Runner, workerOpts)
+}
+
+// BuildkitSocket returns the path of the socket buildkitd listens on
+func (r *Containerd) BuildkitSocket() string {
+	return buildkitSocket
+}
+
+// ListImages returns the tagged images stored by this runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// UnusedImages returns the images which no container uses
+func (r *Containerd) UnusedImages(all bool) ([]Image, error) {
+	return unusedCRIImages(r.Runner, all)
+}
+
+// RemoveImages removes images based on ID
+func (r *Containerd) RemoveImages(ids []string) error {
+	return removeCRIImages(r.Runner, ids)
+}
+
+// ConfigureRegistries makes containerd pull the images of Docker Hub from mirrors first, and trust the insecure registries
+func (r *Containerd) ConfigureRegistries(reg Registries) error {
+	section, err := containerdRegistries(reg)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(r.Runner, containerdRegistriesFile, section); err != nil {
+		return errors.Wrap(err, "registries.toml")
+	}
+	if err := r.Runner.Run(containerdRegistriesCmd()); err != nil {
+		return errors.Wrap(err, "config.toml")
+	}
+	return r.Runner.Run("sudo systemctl restart containerd")
 }
 
 // KubeletOptions returns kubelet options for a containerd
@@ -133,11 +206,16 @@ func (r *Containerd) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(id, len, follow)
+func (r *Containerd) ContainerLogCmd(id string, len int, since time.Duration, follow bool) string {
+	return criContainerLogCmd(id, len, since, follow)
+}
+
+// ContainerExecCmd returns the command to run a shell command in a container based on ID
+func (r *Containerd) ContainerExecCmd(id string, cmd string) string {
+	return criContainerExecCmd(id, cmd)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Containerd) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
+func (r *Containerd) SystemLogCmd(len int, since time.Duration, follow bool) string {
+	return journalCmd("containerd", len, since, follow)
 }
@@ -18,12 +18,18 @@ package cruntime
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/util"
 )
 
 // listCRIContainers returns a list of containers using crictl
@@ -48,6 +54,92 @@ func listCRIContainers(cr CommandRunner, filter string) ([]string, error) {
 	return ids, nil
 }
 
+// listCRIImages returns the tagged images using crictl
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	content, err := cr.CombinedOutput("sudo crictl images -o json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Images []struct {
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal([]byte(content), &list); err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, i := range list.Images {
+		names = append(names, i.RepoTags...)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// unusedCRIImages returns the images which no container uses, using crictl
+func unusedCRIImages(cr CommandRunner, all bool) ([]Image, error) {
+	content, err := cr.CombinedOutput("sudo crictl images -o json")
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Images []struct {
+			ID          string   `json:"id"`
+			RepoTags    []string `json:"repoTags"`
+			RepoDigests []string `json:"repoDigests"`
+			Size        string   `json:"size"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal([]byte(content), &list); err != nil {
+		return nil, errors.Wrap(err, "images")
+	}
+	content, err = cr.CombinedOutput("sudo crictl ps -a -o json")
+	if err != nil {
+		return nil, err
+	}
+	var ps struct {
+		Containers []struct {
+			Image struct {
+				Image string `json:"image"`
+			} `json:"image"`
+			ImageRef string `json:"imageRef"`
+		} `json:"containers"`
+	}
+	if err := json.Unmarshal([]byte(content), &ps); err != nil {
+		return nil, errors.Wrap(err, "containers")
+	}
+
+	used := map[string]bool{}
+	for _, c := range ps.Containers {
+		used[strings.TrimPrefix(c.ImageRef, "sha256:")] = true
+		used[strings.TrimPrefix(c.Image.Image, "sha256:")] = true
+	}
+	images := []Image{}
+	for _, i := range list.Images {
+		size, err := strconv.ParseInt(i.Size, 10, 64)
+		if err != nil {
+			glog.Warningf("image %s: invalid size %q", i.ID, i.Size)
+		}
+		images = append(images, Image{ID: i.ID, Tags: i.RepoTags, Digests: i.RepoDigests, Size: size})
+	}
+	return unusedImages(images, used, all), nil
+}
+
+// pullCRIImage pulls an image using crictl
+func pullCRIImage(cr CommandRunner, image string) error {
+	glog.Infof("Pulling image: %s", image)
+	return cr.Run(fmt.Sprintf("sudo crictl pull %s", image))
+}
+
+// removeCRIImages removes a list of images using crictl
+func removeCRIImages(cr CommandRunner, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Removing images: %s", ids)
+	return cr.Run(fmt.Sprintf("sudo crictl rmi %s", strings.Join(ids, " ")))
+}
+
 // criCRIContainers kills a list of containers using crictl
 func killCRIContainers(cr CommandRunner, ids []string) error {
 	if len(ids) == 0 {
@@ -84,13 +176,21 @@ image-endpoint: unix://{{.Socket}}
 	return cr.Run(fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | sudo tee %s", path.Dir(cPath), b.String(), cPath))
 }
 
+// criContainerExecCmd returns the command to run a shell command in a container based on ID
+func criContainerExecCmd(id string, cmd string) string {
+	return fmt.Sprintf("sudo crictl exec %s /bin/sh -c %s", id, util.ShellQuote(cmd))
+}
+
 // criContainerLogCmd returns the command to retrieve the log for a container based on ID
-func criContainerLogCmd(id string, len int, follow bool) string {
+func criContainerLogCmd(id string, len int, since time.Duration, follow bool) string {
 	var cmd strings.Builder
 	cmd.WriteString("sudo crictl logs ")
 	if len > 0 {
 		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
 	}
+	if since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %s ", sinceArg(since)))
+	}
 	if follow {
 		cmd.WriteString("--follow ")
 	}
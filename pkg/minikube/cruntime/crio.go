@@ -19,8 +19,10 @@ package cruntime
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -104,6 +106,67 @@ func (r *CRIO) LoadImage(path string) error {
 	return r.Runner.Run(fmt.Sprintf("sudo podman load -i %s", path))
 }
 
+// SaveImage saves an image of this runtime to an archive
+func (r *CRIO) SaveImage(image string, path string) error {
+	glog.Infof("Saving image %s: %s", image, path)
+	return r.Runner.Run(fmt.Sprintf("sudo podman save -o %s %s", path, image))
+}
+
+// PullImage pulls an image with crictl, into the image store used by Kubernetes
+func (r *CRIO) PullImage(image string) error {
+	return pullCRIImage(r.Runner, image)
+}
+
+// BuildImage builds an image with buildkitd, then loads it into the CRI-O image store with podman
+func (r *CRIO) BuildImage(dir string, opts BuildOptions) error {
+	glog.Infof("Building image: %s", dir)
+	if len(opts.Platforms) > 1 {
+		return fmt.Errorf("the cri-o runtime stores a single platform per image, got %s", strings.Join(opts.Platforms, ","))
+	}
+	if err := checkEmulators(r.Runner, opts.Platforms); err != nil {
+		return err
+	}
+	if err := enableBuildkit(r.Runner, "--oci-worker=true --containerd-worker=false"); err != nil {
+		return err
+	}
+	tarball := dir + ".tar"
+	output := fmt.Sprintf("type=docker,name=%s,dest=%s", opts.Tag, tarball)
+	if err := r.Runner.Run(buildctlCmd(dir, opts, output)); err != nil {
+		return err
+	}
+	if err := r.LoadImage(tarball); err != nil {
+		return err
+	}
+	return r.Runner.Run(fmt.Sprintf("sudo rm -f %s", tarball))
+}
+
+// ListImages returns the tagged images stored by this runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// UnusedImages returns the images which no container uses
+func (r *CRIO) UnusedImages(all bool) ([]Image, error) {
+	return unusedCRIImages(r.Runner, all)
+}
+
+// RemoveImages removes images based on ID
+func (r *CRIO) RemoveImages(ids []string) error {
+	return removeCRIImages(r.Runner, ids)
+}
+
+// ConfigureRegistries makes CRI-O pull the images of Docker Hub from mirrors first, and trust the insecure registries
+func (r *CRIO) ConfigureRegistries(reg Registries) error {
+	conf, err := crioRegistriesConf(reg)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(r.Runner, "/etc/containers/registries.conf", conf); err != nil {
+		return errors.Wrap(err, "registries.conf")
+	}
+	return r.Runner.Run("sudo systemctl restart crio")
+}
+
 // KubeletOptions returns kubelet options for a runtime.
 func (r *CRIO) KubeletOptions() map[string]string {
 	return map[string]string{
@@ -130,11 +193,16 @@ func (r *CRIO) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(id, len, follow)
+func (r *CRIO) ContainerLogCmd(id string, len int, since time.Duration, follow bool) string {
+	return criContainerLogCmd(id, len, since, follow)
+}
+
+// ContainerExecCmd returns the command to run a shell command in a container based on ID
+func (r *CRIO) ContainerExecCmd(id string, cmd string) string {
+	return criContainerExecCmd(id, cmd)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *CRIO) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
+func (r *CRIO) SystemLogCmd(len int, since time.Duration, follow bool) string {
+	return journalCmd("crio", len, since, follow)
 }
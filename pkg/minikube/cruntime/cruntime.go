@@ -19,6 +19,9 @@ package cruntime
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -57,6 +60,21 @@ type Manager interface {
 
 	// Load an image idempotently into the runtime on a host
 	LoadImage(string) error
+	// SaveImage saves an image of the runtime to an archive on a host, which LoadImage loads
+	SaveImage(string, string) error
+	// PullImage pulls an image from its registry into the runtime on a host
+	PullImage(string) error
+	// BuildImage builds an image from a build context directory on a host
+	BuildImage(string, BuildOptions) error
+	// ListImages returns the tagged images stored by this runtime, sorted by name
+	ListImages() ([]string, error)
+	// UnusedImages returns the images which no container uses. Unless all is set, only untagged images are returned
+	UnusedImages(all bool) ([]Image, error)
+	// RemoveImages removes images based on ID
+	RemoveImages([]string) error
+
+	// ConfigureRegistries configures the mirrors of Docker Hub and the insecure registries of the runtime
+	ConfigureRegistries(Registries) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(string) ([]string, error)
@@ -64,10 +82,71 @@ type Manager interface {
 	KillContainers([]string) error
 	// StopContainers stops containers based on ID
 	StopContainers([]string) error
-	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
-	ContainerLogCmd(string, int, bool) string
-	// SystemLogCmd returns the command to return the system logs
-	SystemLogCmd(int) string
+	// ContainerLogCmd returns the command to retrieve the log for a container based on ID,
+	// with the given number of recent lines, since the given period if it is not zero, and whether to follow it
+	ContainerLogCmd(string, int, time.Duration, bool) string
+	// ContainerExecCmd returns the command to run a shell command in a container based on ID
+	ContainerExecCmd(string, string) string
+	// SystemLogCmd returns the command to return the system logs, selected as with ContainerLogCmd
+	SystemLogCmd(int, time.Duration, bool) string
+}
+
+// Image is an image stored by a container runtime
+type Image struct {
+	ID      string
+	Tags    []string
+	Digests []string
+	// Size includes the layers shared with other images
+	Size int64
+}
+
+// unusedImages returns the images whose ID, tags and digests are not in used, sorted by ID. Unless all is set, tagged images are skipped
+func unusedImages(images []Image, used map[string]bool, all bool) []Image {
+	unused := []Image{}
+	for _, i := range images {
+		if !all && len(i.Tags) > 0 {
+			continue
+		}
+		if used[strings.TrimPrefix(i.ID, "sha256:")] {
+			continue
+		}
+		inUse := false
+		for _, ref := range append(i.Tags, i.Digests...) {
+			if used[ref] {
+				inUse = true
+			}
+		}
+		if !inUse {
+			unused = append(unused, i)
+		}
+	}
+	sort.Slice(unused, func(a, b int) bool { return unused[a].ID < unused[b].ID })
+	return unused
+}
+
+// NormalizeImage returns the fully qualified reference of an image, as listed by containerd and cri-o:
+// images of Docker Hub get the docker.io registry and the library namespace, and untagged images the latest tag
+func NormalizeImage(image string) string {
+	name, digest := image, ""
+	if i := strings.Index(image, "@"); i >= 0 {
+		name, digest = image[:i], image[i:]
+	}
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 || !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		parts = append([]string{"docker.io"}, parts...)
+		name = strings.Join(parts, "/")
+	}
+	if parts[0] == "index.docker.io" {
+		parts[0] = "docker.io"
+		name = strings.Join(parts, "/")
+	}
+	if parts[0] == "docker.io" && len(parts) == 2 && !strings.Contains(parts[1], "/") {
+		name = "docker.io/library/" + parts[1]
+	}
+	if digest == "" && !strings.Contains(name[strings.LastIndex(name, "/")+1:], ":") {
+		name += ":latest"
+	}
+	return name + digest
 }
 
 // Config is runtime configuration
@@ -78,6 +157,8 @@ type Config struct {
 	Socket string
 	// Runner is the CommandRunner object to execute commands with
 	Runner CommandRunner
+	// Snapshotter is the containerd snapshotter which Enable configures, or empty for the default one
+	Snapshotter string
 }
 
 // New returns an appropriately configured runtime
@@ -88,7 +169,7 @@ func New(c Config) (Manager, error) {
 	case "crio", "cri-o":
 		return &CRIO{Socket: c.Socket, Runner: c.Runner}, nil
 	case "containerd":
-		return &Containerd{Socket: c.Socket, Runner: c.Runner}, nil
+		return &Containerd{Socket: c.Socket, Runner: c.Runner, Snapshotter: c.Snapshotter}, nil
 	default:
 		return nil, fmt.Errorf("unknown runtime type: %q", c.Type)
 	}
@@ -134,3 +215,26 @@ func enableIPForwarding(cr CommandRunner) error {
 	}
 	return nil
 }
+
+// journalCmd returns the command which displays the journal of the systemd unit of a runtime
+func journalCmd(unit string, len int, since time.Duration, follow bool) string {
+	var cmd strings.Builder
+	cmd.WriteString(fmt.Sprintf("sudo journalctl -u %s -n %d", unit, len))
+	if since > 0 {
+		cmd.WriteString(fmt.Sprintf(" --since=-%s", sinceArg(since)))
+	}
+	if follow {
+		cmd.WriteString(" -f")
+	}
+	return cmd.String()
+}
+
+// sinceArg formats a period for the --since flags of the runtimes and of journalctl, in whole seconds.
+// The periods are relative, so that the clocks of the host and of the VM do not need to match.
+func sinceArg(d time.Duration) string {
+	s := int64(d / time.Second)
+	if s < 1 {
+		s = 1
+	}
+	return fmt.Sprintf("%ds", s)
+}
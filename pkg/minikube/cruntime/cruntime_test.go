@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -97,6 +98,7 @@ type FakeRunner struct {
 	cmds       []string
 	services   map[string]serviceState
 	containers map[string]string
+	images     []string
 	t          *testing.T
 }
 
@@ -184,6 +186,9 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 		if args[1] == "--format" && args[2] == "'{{.Server.Version}}'" {
 			return "18.06.2-ce", nil
 		}
+	case "images":
+		// images --format="{{.Repository}}:{{.Tag}}"
+		return strings.Join(append(f.images, "<none>:<none>"), "\n"), nil
 
 	}
 	return "", nil
@@ -208,6 +213,13 @@ func (f *FakeRunner) containerd(args []string, _ bool) (string, error) {
 // crictl is a fake implementation of crictl
 func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 	switch cmd := args[0]; cmd {
+	case "images":
+		// crictl images -o json
+		tags := []string{}
+		for _, i := range f.images {
+			tags = append(tags, fmt.Sprintf(`{"repoTags":[%q]}`, i))
+		}
+		return fmt.Sprintf(`{"images":[%s,{"repoTags":[]}]}`, strings.Join(tags, ",")), nil
 	case "ps":
 		// crictl ps -a --name=apiserver --state=Running --quiet
 		if args[1] == "-a" && strings.HasPrefix(args[2], "--name") {
@@ -290,6 +302,14 @@ func (f *FakeRunner) systemctl(args []string, root bool) (string, error) {
 			}
 			f.services[svc] = Restarted
 			f.t.Logf("fake systemctl: restarted %s", svc)
+		case "reload":
+			if !root {
+				return out, fmt.Errorf("not root")
+			}
+			if state != Running {
+				return out, fmt.Errorf("%s in state: %v", svc, state)
+			}
+			f.t.Logf("fake systemctl: reloaded %s", svc)
 		case "is-active":
 			f.t.Logf("fake systemctl: %s is-status: %v", svc, state)
 			if state == Running {
@@ -491,3 +511,497 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerExecCmd(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    string
+	}{
+		{"docker", `docker exec abc0 /bin/sh -c 'echo '"'"'hi'"'"''`},
+		{"crio", `sudo crictl exec abc0 /bin/sh -c 'echo '"'"'hi'"'"''`},
+		{"containerd", `sudo crictl exec abc0 /bin/sh -c 'echo '"'"'hi'"'"''`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			cr, err := New(Config{Type: tc.runtime, Runner: NewFakeRunner(t)})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if got := cr.ContainerExecCmd("abc0", "echo 'hi'"); got != tc.want {
+				t.Errorf("ContainerExecCmd() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLogCmds(t *testing.T) {
+	var tests = []struct {
+		runtime   string
+		container string
+		system    string
+	}{
+		{"docker", "docker logs --tail 30 --since 600s --follow abc0", "sudo journalctl -u docker -n 30 --since=-600s -f"},
+		{"crio", "sudo crictl logs --tail 30 --since 600s --follow abc0", "sudo journalctl -u crio -n 30 --since=-600s -f"},
+		{"containerd", "sudo crictl logs --tail 30 --since 600s --follow abc0", "sudo journalctl -u containerd -n 30 --since=-600s -f"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			cr, err := New(Config{Type: tc.runtime, Runner: NewFakeRunner(t)})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if got := cr.ContainerLogCmd("abc0", 30, 10*time.Minute, true); got != tc.container {
+				t.Errorf("ContainerLogCmd() = %q, want %q", got, tc.container)
+			}
+			if got := cr.SystemLogCmd(30, 10*time.Minute, true); got != tc.system {
+				t.Errorf("SystemLogCmd() = %q, want %q", got, tc.system)
+			}
+		})
+	}
+}
+
+func TestListImages(t *testing.T) {
+	for _, rt := range []string{"docker", "crio", "containerd"} {
+		t.Run(rt, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.images = []string{"k8s.gcr.io/pause:3.1", "gcr.io/k8s-minikube/storage-provisioner:v1.8.1"}
+			cr, err := New(Config{Type: rt, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", rt, err)
+			}
+			got, err := cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want := []string{"gcr.io/k8s-minikube/storage-provisioner:v1.8.1", "k8s.gcr.io/pause:3.1"}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ListImages() returned diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// cannedRunner is a CommandRunner which returns the given output of commands, and records the other commands
+type cannedRunner struct {
+	outputs map[string]string
+	cmds    []string
+}
+
+func (c *cannedRunner) CombinedOutput(cmd string) (string, error) {
+	if out, ok := c.outputs[cmd]; ok {
+		return out, nil
+	}
+	c.cmds = append(c.cmds, cmd)
+	return "", nil
+}
+
+func (c *cannedRunner) Run(cmd string) error {
+	_, err := c.CombinedOutput(cmd)
+	return err
+}
+
+func TestUnusedImages(t *testing.T) {
+	images := []Image{
+		{ID: "sha256:c", Tags: []string{"app:dev"}},
+		{ID: "sha256:a"},
+		{ID: "sha256:b", Tags: []string{"k8s.gcr.io/pause:3.1"}},
+		{ID: "sha256:d", Tags: []string{"nginx:latest"}, Digests: []string{"nginx@sha256:1"}},
+		{ID: "sha256:e"},
+	}
+	used := map[string]bool{"e": true, "k8s.gcr.io/pause:3.1": true, "nginx@sha256:1": true}
+	var tests = []struct {
+		all  bool
+		want []string
+	}{
+		{false, []string{"sha256:a"}},
+		{true, []string{"sha256:a", "sha256:c"}},
+	}
+	for _, tc := range tests {
+		got := []string{}
+		for _, i := range unusedImages(images, used, tc.all) {
+			got = append(got, i.ID)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unusedImages(all=%v) returned diff (-want +got):\n%s", tc.all, diff)
+		}
+	}
+}
+
+func TestNormalizeImage(t *testing.T) {
+	var tests = []struct {
+		image string
+		want  string
+	}{
+		{"nginx", "docker.io/library/nginx:latest"},
+		{"nginx:1.17", "docker.io/library/nginx:1.17"},
+		{"docker.io/library/nginx:1.17", "docker.io/library/nginx:1.17"},
+		{"index.docker.io/nginx:1.17", "docker.io/library/nginx:1.17"},
+		{"kubernetesui/dashboard:v2.0.0", "docker.io/kubernetesui/dashboard:v2.0.0"},
+		{"k8s.gcr.io/pause:3.1", "k8s.gcr.io/pause:3.1"},
+		{"gcr.io/k8s-minikube/storage-provisioner", "gcr.io/k8s-minikube/storage-provisioner:latest"},
+		{"localhost:5000/app", "localhost:5000/app:latest"},
+		{"localhost/app:dev", "localhost/app:dev"},
+		{"nginx@sha256:1", "docker.io/library/nginx@sha256:1"},
+	}
+	for _, tc := range tests {
+		if got := NormalizeImage(tc.image); got != tc.want {
+			t.Errorf("NormalizeImage(%q) = %q, want %q", tc.image, got, tc.want)
+		}
+	}
+}
+
+func TestDockerUnusedImages(t *testing.T) {
+	runner := &cannedRunner{outputs: map[string]string{
+		`docker images -q --no-trunc | xargs -r docker image inspect --format '{{.Id}}|{{.Size}}|{{join .RepoTags ","}}|{{join .RepoDigests ","}}'`: "sha256:a|100|app:dev,app:latest|\nsha256:a|100|app:dev,app:latest|\nsha256:b|200||\nsha256:c|300|k8s.gcr.io/pause:3.1|k8s.gcr.io/pause@sha256:1\n",
+		`docker ps -aq --no-trunc | xargs -r docker inspect --format '{{.Image}} {{.Config.Image}}'`:                                                "sha256:c k8s.gcr.io/pause:3.1\n",
+	}}
+	cr, err := New(Config{Type: "docker", Runner: runner})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	got, err := cr.UnusedImages(true)
+	if err != nil {
+		t.Fatalf("UnusedImages: %v", err)
+	}
+	want := []Image{
+		{ID: "sha256:a", Tags: []string{"app:dev", "app:latest"}, Size: 100},
+		{ID: "sha256:b", Size: 200},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UnusedImages() returned diff (-want +got):\n%s", diff)
+	}
+	if err := cr.RemoveImages([]string{"sha256:a", "sha256:b"}); err != nil {
+		t.Fatalf("RemoveImages: %v", err)
+	}
+	if diff := cmp.Diff([]string{"docker rmi -f sha256:a sha256:b"}, runner.cmds); diff != "" {
+		t.Errorf("RemoveImages() ran diff (-want +got):\n%s", diff)
+	}
+}
+
+func TestCRIUnusedImages(t *testing.T) {
+	for _, rt := range []string{"crio", "containerd"} {
+		t.Run(rt, func(t *testing.T) {
+			runner := &cannedRunner{outputs: map[string]string{
+				"sudo crictl images -o json": `{"images":[` +
+					`{"id":"sha256:a","repoTags":["app:dev"],"repoDigests":[],"size":"100"},` +
+					`{"id":"sha256:b","repoTags":[],"repoDigests":["app@sha256:1"],"size":"200"},` +
+					`{"id":"c","repoTags":["k8s.gcr.io/pause:3.1"],"repoDigests":["k8s.gcr.io/pause@sha256:2"],"size":"300"},` +
+					`{"id":"d","repoTags":["nginx:latest"],"repoDigests":["nginx@sha256:3"],"size":"400"}]}`,
+				"sudo crictl ps -a -o json": `{"containers":[` +
+					`{"image":{"image":"k8s.gcr.io/pause:3.1"},"imageRef":"sha256:c"},` +
+					`{"image":{"image":"nginx"},"imageRef":"nginx@sha256:3"}]}`,
+			}}
+			cr, err := New(Config{Type: rt, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", rt, err)
+			}
+			got, err := cr.UnusedImages(true)
+			if err != nil {
+				t.Fatalf("UnusedImages: %v", err)
+			}
+			want := []Image{
+				{ID: "sha256:a", Tags: []string{"app:dev"}, Digests: []string{}, Size: 100},
+				{ID: "sha256:b", Tags: []string{}, Digests: []string{"app@sha256:1"}, Size: 200},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("UnusedImages() returned diff (-want +got):\n%s", diff)
+			}
+			if err := cr.RemoveImages([]string{"sha256:a"}); err != nil {
+				t.Fatalf("RemoveImages: %v", err)
+			}
+			if diff := cmp.Diff([]string{"sudo crictl rmi sha256:a"}, runner.cmds); diff != "" {
+				t.Errorf("RemoveImages() ran diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSaveImage(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    string
+	}{
+		{"docker", "docker save -o /tmp/app.tar app:dev"},
+		{"crio", "sudo podman save -o /tmp/app.tar app:dev"},
+		{"containerd", "sudo ctr -n=k8s.io images export /tmp/app.tar app:dev"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := &cannedRunner{}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.SaveImage("app:dev", "/tmp/app.tar"); err != nil {
+				t.Fatalf("SaveImage: %v", err)
+			}
+			if diff := cmp.Diff([]string{tc.want}, runner.cmds); diff != "" {
+				t.Errorf("SaveImage() ran diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPullImage(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		want    string
+	}{
+		{"docker", "docker pull kubernetesui/dashboard:v2.0.0-beta1"},
+		{"crio", "sudo crictl pull kubernetesui/dashboard:v2.0.0-beta1"},
+		{"containerd", "sudo crictl pull kubernetesui/dashboard:v2.0.0-beta1"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := &cannedRunner{}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.PullImage("kubernetesui/dashboard:v2.0.0-beta1"); err != nil {
+				t.Fatalf("PullImage: %v", err)
+			}
+			if diff := cmp.Diff([]string{tc.want}, runner.cmds); diff != "" {
+				t.Errorf("PullImage() ran diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildctlCmd(t *testing.T) {
+	opts := BuildOptions{
+		Tag:        "app:dev",
+		Dockerfile: "build/Dockerfile.dev",
+		BuildArgs:  []string{"VERSION=1"},
+		CacheFrom:  []string{"type=local,src=/data/cache"},
+		CacheTo:    []string{"type=local,dest=/data/cache"},
+		Platforms:  []string{"linux/amd64", "linux/arm64"},
+	}
+	got := buildctlCmd("/tmp/ctx", opts, "type=image,name=app:dev")
+	want := "sudo buildctl --addr unix:///run/buildkit/buildkitd.sock build --frontend dockerfile.v0" +
+		" --local 'context=/tmp/ctx' --local 'dockerfile=/tmp/ctx/build' --opt 'filename=Dockerfile.dev'" +
+		" --opt 'build-arg:VERSION=1' --opt 'platform=linux/amd64,linux/arm64' --import-cache 'type=local,src=/data/cache' --export-cache 'type=local,dest=/data/cache'" +
+		" --output 'type=image,name=app:dev'"
+	if got != want {
+		t.Errorf("buildctlCmd() = %q, want %q", got, want)
+	}
+}
+
+func TestDockerBuildCmd(t *testing.T) {
+	var tests = []struct {
+		description string
+		opts        BuildOptions
+		want        string
+		wantErr     bool
+	}{
+		{
+			description: "defaults",
+			opts:        BuildOptions{Tag: "app:dev"},
+			want:        "sudo env DOCKER_BUILDKIT=1 docker build -t 'app:dev' -f '/tmp/ctx/Dockerfile' '/tmp/ctx'",
+		},
+		{
+			description: "inline cache",
+			opts:        BuildOptions{Tag: "app:dev", CacheFrom: []string{"app:latest", "type=registry,ref=reg/app:cache"}, CacheTo: []string{"type=inline"}},
+			want:        "sudo env DOCKER_BUILDKIT=1 docker build -t 'app:dev' -f '/tmp/ctx/Dockerfile' --cache-from 'app:latest' --cache-from 'reg/app:cache' --build-arg BUILDKIT_INLINE_CACHE=1 '/tmp/ctx'",
+		},
+		{
+			description: "platform",
+			opts:        BuildOptions{Tag: "app:dev", Platforms: []string{"linux/arm64"}},
+			want:        "sudo env DOCKER_BUILDKIT=1 docker build -t 'app:dev' -f '/tmp/ctx/Dockerfile' --platform 'linux/arm64' '/tmp/ctx'",
+		},
+		{
+			description: "build args and tag with shell characters",
+			opts:        BuildOptions{Tag: "app:$(id)", BuildArgs: []string{"GREETING=hello world", "QUOTE=it's"}},
+			want:        `sudo env DOCKER_BUILDKIT=1 docker build -t 'app:$(id)' -f '/tmp/ctx/Dockerfile' --build-arg 'GREETING=hello world' --build-arg 'QUOTE=it'"'"'s' '/tmp/ctx'`,
+		},
+		{
+			description: "several platforms",
+			opts:        BuildOptions{Tag: "app:dev", Platforms: []string{"linux/amd64", "linux/arm64"}},
+			wantErr:     true,
+		},
+		{
+			description: "local cache import",
+			opts:        BuildOptions{Tag: "app:dev", CacheFrom: []string{"type=local,src=/data/cache"}},
+			wantErr:     true,
+		},
+		{
+			description: "local cache export",
+			opts:        BuildOptions{Tag: "app:dev", CacheTo: []string{"type=local,dest=/data/cache"}},
+			wantErr:     true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := dockerBuildCmd("/tmp/ctx", tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("dockerBuildCmd() error = %v, wantErr: %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("dockerBuildCmd() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// emulatorRunner is a CommandRunner of a VM with the given architecture and registered qemu emulators
+type emulatorRunner struct {
+	native     string
+	registered []string
+}
+
+func (e emulatorRunner) CombinedOutput(cmd string) (string, error) {
+	if cmd == "uname -m" {
+		return e.native + "\n", nil
+	}
+	for _, r := range e.registered {
+		if cmd == "test -e /proc/sys/fs/binfmt_misc/qemu-"+r {
+			return "", nil
+		}
+	}
+	return "", fmt.Errorf("%s: exit status 1", cmd)
+}
+
+func (e emulatorRunner) Run(cmd string) error {
+	_, err := e.CombinedOutput(cmd)
+	return err
+}
+
+func TestCheckEmulators(t *testing.T) {
+	runner := emulatorRunner{native: "x86_64", registered: []string{"aarch64"}}
+	var tests = []struct {
+		platforms []string
+		wantErr   bool
+	}{
+		{nil, false},
+		{[]string{"linux/amd64"}, false},
+		{[]string{"linux/amd64", "linux/arm64/v8"}, false},
+		{[]string{"linux/arm/v7"}, true},
+		{[]string{"windows/amd64"}, true},
+		{[]string{"linux/mips"}, true},
+		{[]string{"arm64"}, true},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.platforms, ","), func(t *testing.T) {
+			err := checkEmulators(runner, tc.platforms)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("checkEmulators(%v) error = %v, wantErr: %v", tc.platforms, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigureRegistries(t *testing.T) {
+	var tests = []struct {
+		runtime string
+		file    string
+		service string
+		want    serviceState
+	}{
+		{"docker", "/etc/docker/daemon.json", "docker", Running},
+		{"containerd", "/etc/containerd/registries.toml", "containerd", Restarted},
+		{"crio", "/etc/containers/registries.conf", "crio", Restarted},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			for k, v := range defaultServices {
+				runner.services[k] = v
+			}
+			runner.services[tc.service] = Running
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if err := cr.ConfigureRegistries(Registries{Mirrors: []string{"http://localhost:5001"}}); err != nil {
+				t.Fatalf("ConfigureRegistries: %v", err)
+			}
+			if len(runner.cmds) == 0 || !strings.Contains(runner.cmds[0], tc.file) || !strings.Contains(runner.cmds[0], "localhost:5001") {
+				t.Errorf("commands = %v, want the mirror in %s", runner.cmds, tc.file)
+			}
+			if got := runner.services[tc.service]; got != tc.want {
+				t.Errorf("%s is %v, want %v", tc.service, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContainerdRegistries(t *testing.T) {
+	got, err := containerdRegistries(Registries{
+		Mirrors:  []string{"https://mirror.example.com"},
+		Insecure: []string{"10.0.0.5:5000", "10.96.0.0/12", "http://registry.local"},
+	})
+	if err != nil {
+		t.Fatalf("containerdRegistries: %v", err)
+	}
+	want := `      [plugins.cri.registry.mirrors]
+        [plugins.cri.registry.mirrors."docker.io"]
+          endpoint = ["https://mirror.example.com", "https://registry-1.docker.io"]
+        [plugins.cri.registry.mirrors."10.0.0.5:5000"]
+          endpoint = ["http://10.0.0.5:5000"]
+        [plugins.cri.registry.mirrors."registry.local"]
+          endpoint = ["http://registry.local"]
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("containerdRegistries() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := containerdRegistries(Registries{Mirrors: []string{"localhost"}}); err == nil {
+		t.Errorf("containerdRegistries(localhost) = nil, want error")
+	}
+}
+
+func TestCrioRegistriesConf(t *testing.T) {
+	got, err := crioRegistriesConf(Registries{
+		Mirrors:  []string{"http://localhost:5001", "https://mirror.example.com"},
+		Insecure: []string{"10.0.0.5:5000"},
+	})
+	if err != nil {
+		t.Fatalf("crioRegistriesConf: %v", err)
+	}
+	want := `unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+location = "docker.io"
+
+[[registry.mirror]]
+location = "localhost:5001"
+insecure = true
+
+[[registry.mirror]]
+location = "mirror.example.com"
+insecure = false
+
+[[registry]]
+location = "10.0.0.5:5000"
+insecure = true
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("crioRegistriesConf() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := crioRegistriesConf(Registries{Mirrors: []string{"localhost"}}); err == nil {
+		t.Errorf("crioRegistriesConf(localhost) = nil, want error")
+	}
+}
+
+func TestEnableStargz(t *testing.T) {
+	var tests = []struct {
+		version string
+		want    []string
+		wantErr bool
+	}{
+		{"containerd github.com/containerd/containerd v1.2.6 894b81a4b802e4eb2a91d1ce216b8817763c29fb", []string{}, true},
+		{"containerd github.com/containerd/containerd v1.4.3 269548fa27e0089a8b8278fc4fc781d7f65a939b", []string{"sudo systemctl start stargz-snapshotter", stargzConfigCmd()}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			runner := &cannedRunner{outputs: map[string]string{"containerd --version": tc.version}, cmds: []string{}}
+			r := &Containerd{Runner: runner, Snapshotter: StargzSnapshotter}
+			err := r.enableStargz()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("enableStargz() error = %v, want error: %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, runner.cmds); diff != "" {
+				t.Errorf("enableStargz() ran diff (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
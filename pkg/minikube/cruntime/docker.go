@@ -19,15 +19,27 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util"
 )
 
 // KubernetesContainerPrefix is the prefix of each kubernetes container
 const KubernetesContainerPrefix = "k8s_"
 
+// RemapUser is the user whose subordinate IDs the containers run as, with RootlessDockerOpt
+const RemapUser = "docker"
+
+// RootlessDockerOpt is the dockerd option which runs containers in a user namespace,
+// where root is remapped to the unprivileged subordinate IDs of RemapUser
+const RootlessDockerOpt = "userns-remap=" + RemapUser
+
 // Docker contains Docker runtime state
 type Docker struct {
 	Socket string
@@ -98,6 +110,120 @@ func (r *Docker) LoadImage(path string) error {
 	return r.Runner.Run(fmt.Sprintf("docker load -i %s", path))
 }
 
+// SaveImage saves an image of this runtime to an archive
+func (r *Docker) SaveImage(image string, path string) error {
+	glog.Infof("Saving image %s: %s", image, path)
+	return r.Runner.Run(fmt.Sprintf("docker save -o %s %s", path, image))
+}
+
+// PullImage pulls an image with the docker daemon
+func (r *Docker) PullImage(image string) error {
+	glog.Infof("Pulling image: %s", image)
+	return r.Runner.Run(fmt.Sprintf("docker pull %s", image))
+}
+
+// BuildImage builds an image with the docker daemon, using BuildKit
+func (r *Docker) BuildImage(dir string, opts BuildOptions) error {
+	glog.Infof("Building image: %s", dir)
+	c, err := dockerBuildCmd(dir, opts)
+	if err != nil {
+		return err
+	}
+	if err := checkEmulators(r.Runner, opts.Platforms); err != nil {
+		return err
+	}
+	return r.Runner.Run(c)
+}
+
+// ListImages returns the tagged images stored by this runtime
+func (r *Docker) ListImages() ([]string, error) {
+	content, err := r.Runner.CombinedOutput(`docker images --format="{{.Repository}}:{{.Tag}}"`)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if line != "" && !strings.Contains(line, "<none>") {
+			names = append(names, line)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UnusedImages returns the images which no container uses
+func (r *Docker) UnusedImages(all bool) ([]Image, error) {
+	content, err := r.Runner.CombinedOutput(`docker images -q --no-trunc | xargs -r docker image inspect --format '{{.Id}}|{{.Size}}|{{join .RepoTags ","}}|{{join .RepoDigests ","}}'`)
+	if err != nil {
+		return nil, err
+	}
+	images, err := parseDockerImages(content)
+	if err != nil {
+		return nil, err
+	}
+	content, err = r.Runner.CombinedOutput(`docker ps -aq --no-trunc | xargs -r docker inspect --format '{{.Image}} {{.Config.Image}}'`)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	for _, ref := range strings.Fields(content) {
+		used[strings.TrimPrefix(ref, "sha256:")] = true
+	}
+	return unusedImages(images, used, all), nil
+}
+
+// parseDockerImages parses the ID|size|tags|digests lines of docker image inspect, skipping the images listed twice
+func parseDockerImages(content string) ([]Image, error) {
+	images := []Image{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected image: %q", line)
+		}
+		if seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "size of %s", fields[0])
+		}
+		images = append(images, Image{ID: fields[0], Tags: splitNonEmpty(fields[2]), Digests: splitNonEmpty(fields[3]), Size: size})
+	}
+	return images, nil
+}
+
+// splitNonEmpty splits a comma-separated list, which may be empty
+func splitNonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// RemoveImages removes images based on ID. They are forced, as docker refuses to remove the images with several tags otherwise
+func (r *Docker) RemoveImages(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	glog.Infof("Removing images: %s", ids)
+	return r.Runner.Run(fmt.Sprintf("docker rmi -f %s", strings.Join(ids, " ")))
+}
+
+// ConfigureRegistries makes docker pull the images of Docker Hub from mirrors first.
+// The insecure registries are flags of dockerd, which are set by the provisioner.
+func (r *Docker) ConfigureRegistries(reg Registries) error {
+	if err := writeFile(r.Runner, "/etc/docker/daemon.json", dockerDaemonJSON(reg.Mirrors)); err != nil {
+		return errors.Wrap(err, "daemon.json")
+	}
+	// The registry mirrors are reloaded on SIGHUP, without restarting the containers
+	return r.Runner.Run("sudo systemctl reload docker")
+}
+
 // KubeletOptions returns kubelet options for a runtime.
 func (r *Docker) KubeletOptions() map[string]string {
 	return map[string]string{
@@ -139,13 +265,21 @@ func (r *Docker) StopContainers(ids []string) error {
 	return r.Runner.Run(fmt.Sprintf("docker stop %s", strings.Join(ids, " ")))
 }
 
+// ContainerExecCmd returns the command to run a shell command in a container based on ID
+func (r *Docker) ContainerExecCmd(id string, cmd string) string {
+	return fmt.Sprintf("docker exec %s /bin/sh -c %s", id, util.ShellQuote(cmd))
+}
+
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
+func (r *Docker) ContainerLogCmd(id string, len int, since time.Duration, follow bool) string {
 	var cmd strings.Builder
 	cmd.WriteString("docker logs ")
 	if len > 0 {
 		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
 	}
+	if since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %s ", sinceArg(since)))
+	}
 	if follow {
 		cmd.WriteString("--follow ")
 	}
@@ -155,6 +289,6 @@ func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Docker) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
+func (r *Docker) SystemLogCmd(len int, since time.Duration, follow bool) string {
+	return journalCmd("docker", len, since, follow)
 }
@@ -0,0 +1,157 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/util"
+)
+
+// dockerHubEndpoint is where the images of Docker Hub are pulled from without a mirror
+const dockerHubEndpoint = "https://registry-1.docker.io"
+
+// containerdRegistriesFile is where the registry section of the containerd config is staged before being merged into it
+const containerdRegistriesFile = "/etc/containerd/registries.toml"
+
+// Registries are the registry mirrors and insecure registries of a container runtime
+type Registries struct {
+	// Mirrors are the URLs of the mirrors of Docker Hub, tried in order before it
+	Mirrors []string
+	// Insecure are the registries pulled from without TLS verification, as host[:port]
+	Insecure []string
+}
+
+var crioRegistriesTmpl = template.Must(template.New("registries").Parse(`unqualified-search-registries = ["docker.io"]
+
+[[registry]]
+location = "docker.io"
+{{range .Mirrors}}
+[[registry.mirror]]
+location = "{{.Host}}"
+insecure = {{.Insecure}}
+{{end}}{{range .Insecure}}
+[[registry]]
+location = "{{.}}"
+insecure = true
+{{end}}`))
+
+// writeFile writes contents to a file of the host, as root
+func writeFile(cr CommandRunner, file string, contents string) error {
+	return cr.Run(fmt.Sprintf("sudo mkdir -p %s && printf %%s %s | sudo tee %s", path.Dir(file), util.ShellQuote(contents), file))
+}
+
+// dockerDaemonJSON returns a docker daemon.json with registry mirrors
+func dockerDaemonJSON(mirrors []string) string {
+	quoted := []string{}
+	for _, m := range mirrors {
+		quoted = append(quoted, fmt.Sprintf("%q", m))
+	}
+	return fmt.Sprintf("{\n  \"registry-mirrors\": [%s]\n}\n", strings.Join(quoted, ", "))
+}
+
+// insecureHosts returns the insecure registries as host[:port], without the CIDRs only dockerd understands
+func insecureHosts(insecure []string) []string {
+	hosts := []string{}
+	for _, r := range insecure {
+		if _, _, err := net.ParseCIDR(r); err == nil {
+			glog.Infof("Skipping insecure registry CIDR %s, which only docker supports", r)
+			continue
+		}
+		if u, err := url.Parse(r); err == nil && u.Host != "" {
+			r = u.Host
+		}
+		hosts = append(hosts, r)
+	}
+	return hosts
+}
+
+// parseMirror parses the URL of a mirror, which must have a host
+func parseMirror(mirror string) (*url.URL, error) {
+	u, err := url.Parse(mirror)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid mirror URL: %q", mirror)
+	}
+	return u, nil
+}
+
+// containerdRegistries returns the registry mirrors section of the containerd config.
+// containerd 1.2 has no setting to skip TLS verification, so insecure registries are pulled from over plain HTTP.
+func containerdRegistries(r Registries) (string, error) {
+	endpoints := []string{}
+	for _, m := range append(append([]string{}, r.Mirrors...), dockerHubEndpoint) {
+		if _, err := parseMirror(m); err != nil {
+			return "", err
+		}
+		endpoints = append(endpoints, fmt.Sprintf("%q", m))
+	}
+	var b strings.Builder
+	b.WriteString("      [plugins.cri.registry.mirrors]\n")
+	fmt.Fprintf(&b, "        [plugins.cri.registry.mirrors.\"docker.io\"]\n          endpoint = [%s]\n", strings.Join(endpoints, ", "))
+	for _, h := range insecureHosts(r.Insecure) {
+		fmt.Fprintf(&b, "        [plugins.cri.registry.mirrors.%q]\n          endpoint = [%q]\n", h, "http://"+h)
+	}
+	return b.String(), nil
+}
+
+// containerdRegistriesCmd returns the command which replaces the registry mirrors of the containerd config by the staged ones
+func containerdRegistriesCmd() string {
+	return fmt.Sprintf(`sudo sed -i -e '/^ *\[plugins\.cri\.registry\.mirrors/d' -e '/^ *endpoint = /d' -e '/^ *\[plugins\.cri\.registry\]$/r %s' /etc/containerd/config.toml`, containerdRegistriesFile)
+}
+
+// crioRegistriesConf returns a containers registries.conf with the mirrors of Docker Hub, and the insecure registries
+func crioRegistriesConf(r Registries) (string, error) {
+	insecure := insecureHosts(r.Insecure)
+	type mirror struct {
+		Host     string
+		Insecure bool
+	}
+	opts := struct {
+		Mirrors  []mirror
+		Insecure []string
+	}{Insecure: insecure}
+	for _, m := range r.Mirrors {
+		u, err := parseMirror(m)
+		if err != nil {
+			return "", err
+		}
+		opts.Mirrors = append(opts.Mirrors, mirror{Host: u.Host, Insecure: u.Scheme == "http" || contains(insecure, u.Host)})
+	}
+	var b bytes.Buffer
+	if err := crioRegistriesTmpl.Execute(&b, opts); err != nil {
+		return "", errors.Wrap(err, "registries.conf template")
+	}
+	return b.String(), nil
+}
+
+// contains returns whether s is one of list
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
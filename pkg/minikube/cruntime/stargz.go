@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+)
+
+const (
+	// OverlayfsSnapshotter is the default snapshotter of containerd, which unpacks all the layers of an image when it is pulled
+	OverlayfsSnapshotter = "overlayfs"
+	// StargzSnapshotter lazily pulls eStargz images, fetching their files from the registry as containers read them
+	StargzSnapshotter = "stargz"
+)
+
+// Snapshotters are the containerd snapshotters which minikube can configure
+var Snapshotters = []string{OverlayfsSnapshotter, StargzSnapshotter}
+
+// stargzSocket is where the stargz snapshotter serves the snapshot API to containerd
+const stargzSocket = "/run/containerd-stargz-grpc/containerd-stargz-grpc.sock"
+
+// minStargzVersion is the first containerd whose CRI plugin passes the image references to remote snapshotters
+var minStargzVersion = semver.MustParse("1.4.0")
+
+// stargzConfigCmd returns the command which makes the CRI plugin of containerd unpack images with the stargz snapshotter.
+// The lines added by a previous start are removed first, as /etc/containerd/config.toml is only reset when the VM boots,
+// and the proxy plugin starts on a new line, as the config.toml of the ISO does not end with one.
+func stargzConfigCmd() string {
+	proxy := fmt.Sprintf(`\n[proxy_plugins]\n  [proxy_plugins.stargz]\n    type = "snapshot"\n    address = "%s"`, stargzSocket)
+	return fmt.Sprintf(`sudo sed -i -e '/^$/d' -e '/^ *disable_snapshot_annotations = /d' -e '/^\[proxy_plugins\]/,$d' `+
+		`-e 's|^\( *\)snapshotter = .*|\1snapshotter = "%s"\n\1disable_snapshot_annotations = false|' /etc/containerd/config.toml && `+
+		`printf '%s\n' | sudo tee -a /etc/containerd/config.toml >/dev/null`, StargzSnapshotter, proxy)
+}
+
+// enableStargz starts the stargz snapshotter of the VM, and configures containerd to use it
+func (r *Containerd) enableStargz() error {
+	v, err := r.Version()
+	if err != nil {
+		return errors.Wrap(err, "containerd version")
+	}
+	sv, err := semver.Make(v)
+	if err != nil {
+		return errors.Wrapf(err, "parsing containerd version %q", v)
+	}
+	if sv.LT(minStargzVersion) {
+		return fmt.Errorf("the stargz snapshotter needs containerd %s or later, the VM runs containerd %s", minStargzVersion, v)
+	}
+	if err := r.Runner.Run("sudo systemctl start stargz-snapshotter"); err != nil {
+		return errors.Wrap(err, "starting stargz-snapshotter")
+	}
+	return r.Runner.Run(stargzConfigCmd())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package daemon runs minikube commands in detached processes, which keep running after the terminal is closed
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// Start runs the minikube binary with args in a detached process, which writes its output to logPath, and returns its
+// pid. The process is released, so that it is not waited for.
+func Start(logPath string, args ...string) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return 0, errors.Wrap(err, "creating log dir")
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, errors.Wrap(err, "opening log file")
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return 0, errors.Wrapf(err, "starting %s", strings.Join(cmd.Args, " "))
+	}
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release()
+}
+
+// Running returns whether the process pid is running. Pids of processes which exited may have been reused since.
+func Running(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess always succeeds on unix
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// Kill kills the process pid, if it is running and is not the current process
+func Kill(pid int) error {
+	if !Running(pid) || pid == os.Getpid() {
+		return nil
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// Interrupt asks the process pid to exit, as Ctrl+C does. Windows processes can not be sent an interrupt, so they are
+// killed, and what they clean up on exit is left to the caller.
+func Interrupt(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return interrupt(p)
+}
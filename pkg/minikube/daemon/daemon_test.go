@@ -0,0 +1,42 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRunning(t *testing.T) {
+	if !Running(os.Getpid()) {
+		t.Errorf("Running(%d) = false, want true for the current process", os.Getpid())
+	}
+	for _, pid := range []int{0, -1} {
+		if Running(pid) {
+			t.Errorf("Running(%d) = true, want false", pid)
+		}
+	}
+}
+
+func TestKillCurrentProcess(t *testing.T) {
+	if err := Kill(os.Getpid()); err != nil {
+		t.Fatalf("Kill(%d): %v", os.Getpid(), err)
+	}
+	if !Running(os.Getpid()) {
+		t.Errorf("the current process was killed")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// ImageIDs returns the IDs of images by name, from id. The images id fails for, such as missing ones, are left out.
+func ImageIDs(images []string, id func(string) (string, error)) map[string]string {
+	ids := map[string]string{}
+	for _, i := range images {
+		v, err := id(i)
+		if err != nil {
+			glog.Infof("image %s: %v", i, err)
+			continue
+		}
+		ids[i] = v
+	}
+	return ids
+}
+
+// WatchImages gets the IDs of images every interval from id, and sends the images whose ID changed since the last time,
+// starting from the IDs of last. An image which is missing for a while, such as during a build, has not changed.
+func WatchImages(images []string, last map[string]string, id func(string) (string, error), interval time.Duration, stop <-chan struct{}) <-chan []string {
+	ch := make(chan []string)
+	go func() {
+		defer close(ch)
+		seen := map[string]string{}
+		for k, v := range last {
+			seen[k] = v
+		}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(interval):
+			}
+			changed := []string{}
+			for i, v := range ImageIDs(images, id) {
+				if seen[i] != v {
+					changed = append(changed, i)
+					seen[i] = v
+				}
+			}
+			if len(changed) == 0 {
+				continue
+			}
+			sort.Strings(changed)
+			select {
+			case ch <- changed:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return ch
+}
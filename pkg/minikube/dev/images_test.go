@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeImages are the image IDs of a docker daemon
+type fakeImages struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+func (f *fakeImages) set(image string, id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ids[image] = id
+}
+
+func (f *fakeImages) id(image string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if id, ok := f.ids[image]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("no such image: %s", image)
+}
+
+func TestImageIDs(t *testing.T) {
+	f := &fakeImages{ids: map[string]string{"app:dev": "sha256:1"}}
+	want := map[string]string{"app:dev": "sha256:1"}
+	if got := ImageIDs([]string{"app:dev", "missing:dev"}, f.id); !reflect.DeepEqual(got, want) {
+		t.Errorf("ImageIDs() = %v, want %v", got, want)
+	}
+}
+
+func TestWatchImages(t *testing.T) {
+	f := &fakeImages{ids: map[string]string{"app:dev": "sha256:1", "web:dev": "sha256:2"}}
+	images := []string{"app:dev", "web:dev", "api:dev"}
+	stop := make(chan struct{})
+	defer close(stop)
+	ch := WatchImages(images, ImageIDs(images, f.id), f.id, 10*time.Millisecond, stop)
+
+	time.Sleep(50 * time.Millisecond)
+	f.set("web:dev", "sha256:3")
+	f.set("api:dev", "sha256:4")
+	select {
+	case changed := <-ch:
+		if want := []string{"api:dev", "web:dev"}; !reflect.DeepEqual(changed, want) {
+			t.Errorf("WatchImages() sent %v, want %v", changed, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("WatchImages() sent no changes")
+	}
+
+	// Rebuilding the image under the same ID is not a change
+	f.set("app:dev", "sha256:1")
+	select {
+	case changed := <-ch:
+		t.Errorf("WatchImages() sent %v, want no change", changed)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apps "k8s.io/api/apps/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// restartedAtAnnotation is the pod template annotation set by kubectl rollout restart
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// pollInterval is how often the status of a rollout is checked
+var pollInterval = time.Second
+
+// RestartDeployment replaces the pods of a deployment, as kubectl rollout restart does, so that they run the image just built
+func RestartDeployment(client kubernetes.Interface, namespace string, name string, now time.Time) error {
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, now.Format(time.RFC3339))
+	if _, err := client.AppsV1().Deployments(namespace).Patch(name, types.StrategicMergePatchType, []byte(patch)); err != nil {
+		return errors.Wrapf(err, "restarting deployment %s/%s", namespace, name)
+	}
+	return nil
+}
+
+// rolledOut returns whether all the replicas of a deployment run its latest template
+func rolledOut(d *apps.Deployment) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
+
+// WaitForRollout waits until all the replicas of a deployment run its latest template
+func WaitForRollout(client kubernetes.Interface, namespace string, name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		d, err := client.AppsV1().Deployments(namespace).Get(name, meta.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "getting deployment %s/%s", namespace, name)
+		}
+		if rolledOut(d) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("deployment %s/%s has %d of %d replicas updated after %s", namespace, name, d.Status.UpdatedReplicas, d.Status.Replicas, timeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
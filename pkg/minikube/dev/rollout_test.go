@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestartDeployment(t *testing.T) {
+	client := fake.NewSimpleClientset(&apps.Deployment{ObjectMeta: meta.ObjectMeta{Name: "app", Namespace: "default"}})
+	now := time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	if err := RestartDeployment(client, "default", "app", now); err != nil {
+		t.Fatalf("RestartDeployment: %v", err)
+	}
+	d, err := client.AppsV1().Deployments("default").Get("app", meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := d.Spec.Template.Annotations[restartedAtAnnotation]; got != "2019-08-01T12:00:00Z" {
+		t.Errorf("%s = %q, want %q", restartedAtAnnotation, got, "2019-08-01T12:00:00Z")
+	}
+	if err := RestartDeployment(client, "default", "missing", now); err == nil {
+		t.Errorf("RestartDeployment of a missing deployment = nil, want error")
+	}
+}
+
+func TestRolledOut(t *testing.T) {
+	two := int32(2)
+	var tests = []struct {
+		name   string
+		gen    int64
+		status apps.DeploymentStatus
+		want   bool
+	}{
+		{"done", 2, apps.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, true},
+		{"not observed", 3, apps.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}, false},
+		{"updating", 2, apps.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 2}, false},
+		{"old pods terminating", 2, apps.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}, false},
+	}
+	for _, tc := range tests {
+		d := &apps.Deployment{ObjectMeta: meta.ObjectMeta{Generation: tc.gen}, Spec: apps.DeploymentSpec{Replicas: &two}, Status: tc.status}
+		if got := rolledOut(d); got != tc.want {
+			t.Errorf("%s: rolledOut() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
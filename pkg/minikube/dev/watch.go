@@ -0,0 +1,148 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dev implements the inner loop of minikube dev: watching sources, and restarting deployments on new images
+package dev
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// fileState is what is compared to detect changes to a file
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Tree is the state of the files of a directory
+type Tree map[string]fileState
+
+// ignored returns whether the relative path is excluded by one of the patterns,
+// which are matched against the path and each of its elements
+func ignored(rel string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		for _, e := range strings.Split(filepath.ToSlash(rel), "/") {
+			if ok, _ := filepath.Match(p, e); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scan returns the state of the files of dir, skipping the files and directories matching ignore
+func Scan(dir string, ignore []string) (Tree, error) {
+	t := Tree{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// Files may be deleted while walking
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if ignored(rel, ignore) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			t[rel] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+		return nil
+	})
+	return t, err
+}
+
+// Changes returns the files which were added, modified or removed between two states, sorted
+func Changes(before Tree, after Tree) []string {
+	changed := []string{}
+	for p, s := range after {
+		if b, ok := before[p]; !ok || !b.modTime.Equal(s.modTime) || b.size != s.size {
+			changed = append(changed, p)
+		}
+	}
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			changed = append(changed, p)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Watch scans dir every interval, and sends the changed files once they settle: when a scan finds no new change.
+// This coalesces the files written together by editors and version control into a single change.
+func Watch(dir string, ignore []string, interval time.Duration, stop <-chan struct{}) <-chan []string {
+	ch := make(chan []string)
+	go func() {
+		defer close(ch)
+		last, err := Scan(dir, ignore)
+		if err != nil {
+			glog.Warningf("scanning %s: %v", dir, err)
+		}
+		pending := map[string]bool{}
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(interval):
+			}
+			t, err := Scan(dir, ignore)
+			if err != nil {
+				glog.Warningf("scanning %s: %v", dir, err)
+				continue
+			}
+			changed := Changes(last, t)
+			last = t
+			for _, p := range changed {
+				pending[p] = true
+			}
+			if len(changed) > 0 || len(pending) == 0 {
+				continue
+			}
+			files := []string{}
+			for p := range pending {
+				files = append(files, p)
+			}
+			sort.Strings(files)
+			pending = map[string]bool{}
+			select {
+			case ch <- files:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) {
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestScanAndChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dev")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile(t, dir, "main.go", "package main")
+	writeFile(t, dir, "pkg/lib.go", "package pkg")
+	writeFile(t, dir, "pkg/lib.go~", "backup")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/master")
+	writeFile(t, dir, "node_modules/dep/index.js", "")
+	ignore := []string{".git", "node_modules", "*~"}
+
+	before, err := Scan(dir, ignore)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	files := []string{}
+	for p := range before {
+		files = append(files, filepath.ToSlash(p))
+	}
+	if len(files) != 2 {
+		t.Errorf("Scan() = %v, want main.go and pkg/lib.go", files)
+	}
+
+	writeFile(t, dir, "pkg/lib.go", "package pkg // changed")
+	writeFile(t, dir, "Dockerfile", "FROM scratch")
+	writeFile(t, dir, ".git/HEAD", "ref: refs/heads/dev")
+	if err := os.Remove(filepath.Join(dir, "main.go")); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	after, err := Scan(dir, ignore)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	want := []string{"Dockerfile", "main.go", filepath.Join("pkg", "lib.go")}
+	if got := Changes(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %v, want %v", got, want)
+	}
+	if got := Changes(after, after); len(got) != 0 {
+		t.Errorf("Changes() without changes = %v, want none", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dev")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile(t, dir, "main.go", "package main")
+
+	stop := make(chan struct{})
+	defer close(stop)
+	ch := Watch(dir, nil, 10*time.Millisecond, stop)
+	time.Sleep(50 * time.Millisecond)
+	writeFile(t, dir, "a.go", "package main")
+	writeFile(t, dir, "b.go", "package main")
+
+	select {
+	case files := <-ch:
+		if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(files, want) {
+			t.Errorf("Watch() sent %v, want %v", files, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Watch() sent no changes")
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns resolves the services of a cluster from the host, with a DNS server the resolver of the host forwards a domain to
+package dns
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+const fileName = "dns.json"
+
+// State is the DNS configuration of a profile, and the process which answers for it
+type State struct {
+	Profile string
+	// Domain is the domain whose names are resolved, as <service>.<namespace>.svc.<domain>
+	Domain string
+	// Port is where the DNS server listens, on localhost
+	Port int
+	// Pid is the process of the DNS server
+	Pid int `json:",omitempty"`
+}
+
+// Path returns the path of the DNS file of a profile
+func Path(profile string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), fileName)
+}
+
+// LogFilePath returns the path of the log file of the DNS server of a profile
+func LogFilePath(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), "dns.log")
+}
+
+// Load returns the DNS state of a profile, or nil if it is disabled
+func Load(profile string, miniHome ...string) (*State, error) {
+	b, err := ioutil.ReadFile(Path(profile, miniHome...))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile, miniHome...))
+	}
+	return &s, nil
+}
+
+// Save writes the DNS state of a profile
+func Save(s *State, miniHome ...string) error {
+	if err := os.MkdirAll(constants.GetProfilePath(s.Profile, miniHome...), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(s.Profile, miniHome...), b, 0600)
+}
+
+// Running returns whether the DNS server of the state is running
+func (s *State) Running() bool {
+	return daemon.Running(s.Pid)
+}
+
+// Start runs the DNS server of a profile in the background, and records its pid
+func Start(s *State) error {
+	pid, err := daemon.Start(LogFilePath(s.Profile), "dns", "run", "--profile", s.Profile)
+	if err != nil {
+		return err
+	}
+	s.Pid = pid
+	return Save(s)
+}
+
+// Kill stops the DNS server of a profile, if there is one, and keeps its state for the next start
+func Kill(profile string) error {
+	s, err := Load(profile)
+	if err != nil || s == nil {
+		return err
+	}
+	if err := daemon.Kill(s.Pid); err != nil {
+		glog.Infof("killing DNS process %d: %v", s.Pid, err)
+	}
+	s.Pid = 0
+	return Save(s)
+}
+
+// Disable stops the DNS server of a profile, removes it from the resolver of the host, and removes its state
+func Disable(profile string) error {
+	s, err := Load(profile)
+	if err != nil || s == nil {
+		return err
+	}
+	if err := Kill(profile); err != nil {
+		return err
+	}
+	if err := Unregister(s.Domain); err != nil {
+		return errors.Wrap(err, "removing the resolver")
+	}
+	if err := os.Remove(Path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
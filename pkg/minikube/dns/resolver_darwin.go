@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import "fmt"
+
+// DefaultPort is where the DNS server listens. The resolver of macOS can forward to any port.
+const DefaultPort = 10053
+
+// resolverPath returns the path of the resolver configuration of a domain
+func resolverPath(domain string) string {
+	return "/etc/resolver/" + domain
+}
+
+// Register forwards the queries for names under domain to the DNS server on port of localhost
+func Register(domain string, port int) error {
+	return writeRootFile(resolverPath(domain), fmt.Sprintf("nameserver 127.0.0.1\nport %d\nsearch_order 1\n", port))
+}
+
+// Unregister stops forwarding the queries for names under domain
+func Unregister(domain string) error {
+	return removeRootFile(resolverPath(domain))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPort is where the DNS server listens. systemd-resolved forwards to ports other than 53 since version 246.
+const DefaultPort = 10053
+
+// resolverPath returns the path of the systemd-resolved configuration of a domain
+func resolverPath(domain string) string {
+	return fmt.Sprintf("/etc/systemd/resolved.conf.d/minikube-%s.conf", domain)
+}
+
+// restartResolved restarts systemd-resolved, so that it reads its configuration again
+func restartResolved() error {
+	if out, err := exec.Command("sudo", "systemctl", "restart", "systemd-resolved").CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "restarting systemd-resolved: %s", out)
+	}
+	return nil
+}
+
+// Register forwards the queries for names under domain to the DNS server on port of localhost.
+// The ~ makes domain a routing domain of the server, rather than a search domain.
+func Register(domain string, port int) error {
+	if err := exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run(); err != nil {
+		return fmt.Errorf("systemd-resolved is not running: configure the resolver of the host to forward %s to 127.0.0.1:%d", domain, port)
+	}
+	if err := writeRootFile(resolverPath(domain), fmt.Sprintf("[Resolve]\nDNS=127.0.0.1:%d\nDomains=~%s\n", port, domain)); err != nil {
+		return err
+	}
+	return restartResolved()
+}
+
+// Unregister stops forwarding the queries for names under domain
+func Unregister(domain string) error {
+	if err := removeRootFile(resolverPath(domain)); err != nil {
+		return err
+	}
+	if err := exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run(); err != nil {
+		return nil
+	}
+	return restartResolved()
+}
@@ -0,0 +1,59 @@
+// +build darwin linux
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// writeRootFile writes a file only root can write, with sudo
+func writeRootFile(path string, content string) error {
+	// The content is written to a temporary file, then copied into place
+	tmpFile, err := ioutil.TempFile("", "minikube-dns-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.WriteString(content); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if out, err := exec.Command("sudo", "mkdir", "-p", filepath.Dir(path)).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "mkdir: %s", out)
+	}
+	if out, err := exec.Command("sudo", "cp", "-f", tmpFile.Name(), path).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "cp: %s", out)
+	}
+	return nil
+}
+
+// removeRootFile removes a file only root can write, with sudo
+func removeRootFile(path string) error {
+	if out, err := exec.Command("sudo", "rm", "-f", path).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "rm: %s", out)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultPort is where the DNS server listens. The rules of the Name Resolution Policy Table can only forward to port 53.
+const DefaultPort = 53
+
+// powershell runs a PowerShell command. Changing the resolution policy requires an administrator.
+func powershell(cmd string) error {
+	if out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", cmd).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", cmd, out)
+	}
+	return nil
+}
+
+// Register forwards the queries for names under domain to the DNS server on port of localhost, with a rule of the Name Resolution Policy Table
+func Register(domain string, port int) error {
+	if port != 53 {
+		return fmt.Errorf("the resolver of Windows only forwards to port 53, not %d", port)
+	}
+	if err := Unregister(domain); err != nil {
+		return err
+	}
+	return powershell(fmt.Sprintf(`Add-DnsClientNrptRule -Namespace ".%s" -NameServers "127.0.0.1" -Comment "minikube"`, domain))
+}
+
+// Unregister stops forwarding the queries for names under domain
+func Unregister(domain string) error {
+	return powershell(fmt.Sprintf(`Get-DnsClientNrptRule | Where-Object { $_.Namespace -eq ".%s" } | Remove-DnsClientNrptRule -Force`, domain))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+const (
+	typeA    = 1
+	typeAAAA = 28
+	classIN  = 1
+
+	rcodeNameError = 3
+	rcodeRefused   = 5
+	rcodeFormError = 1
+
+	// ttl is how long answers are cached, in seconds. It is short, as the addresses of services change with the tunnel.
+	ttl = 5
+)
+
+// LookupFunc returns the address of a name under the domain of the server, or false if there is none
+type LookupFunc func(name string) (net.IP, bool)
+
+// question is the question of a DNS query
+type question struct {
+	name  string
+	qtype uint16
+	class uint16
+	// end is the offset of the end of the question in the query
+	end int
+}
+
+// parseQuestion returns the first question of a query
+func parseQuestion(msg []byte) (question, error) {
+	if len(msg) < 12 || binary.BigEndian.Uint16(msg[4:6]) == 0 {
+		return question{}, fmt.Errorf("no question")
+	}
+	labels := []string{}
+	i := 12
+	for {
+		if i >= len(msg) {
+			return question{}, fmt.Errorf("truncated name")
+		}
+		n := int(msg[i])
+		i++
+		if n == 0 {
+			break
+		}
+		// Questions never hold compression pointers
+		if n > 63 || i+n > len(msg) {
+			return question{}, fmt.Errorf("invalid label")
+		}
+		labels = append(labels, string(msg[i:i+n]))
+		i += n
+	}
+	if i+4 > len(msg) {
+		return question{}, fmt.Errorf("truncated question")
+	}
+	return question{
+		name:  strings.ToLower(strings.Join(labels, ".")),
+		qtype: binary.BigEndian.Uint16(msg[i : i+2]),
+		class: binary.BigEndian.Uint16(msg[i+2 : i+4]),
+		end:   i + 4,
+	}, nil
+}
+
+// answer returns the response to a query for names under domain
+func answer(query []byte, domain string, lookup LookupFunc) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	q, err := parseQuestion(query)
+	if err != nil {
+		glog.Infof("invalid query: %v", err)
+		return header(query, 0, rcodeFormError, 0)
+	}
+	// The response repeats the question
+	reply := func(rcode byte, answers uint16) []byte {
+		return append(header(query, 1, rcode, answers), query[12:q.end]...)
+	}
+	if q.name != domain && !strings.HasSuffix(q.name, "."+domain) {
+		return reply(rcodeRefused, 0)
+	}
+	ip, ok := lookup(q.name)
+	glog.Infof("query %s type %d: %v %v", q.name, q.qtype, ip, ok)
+	if !ok {
+		return reply(rcodeNameError, 0)
+	}
+	ip4 := ip.To4()
+	// Names have no IPv6 address: AAAA queries get an empty answer, rather than an error which would hide the A record
+	if q.qtype != typeA || q.class != classIN || ip4 == nil {
+		return reply(0, 0)
+	}
+	rr := make([]byte, 16)
+	binary.BigEndian.PutUint16(rr[0:2], 0xc00c) // a pointer to the name of the question
+	binary.BigEndian.PutUint16(rr[2:4], typeA)
+	binary.BigEndian.PutUint16(rr[4:6], classIN)
+	binary.BigEndian.PutUint32(rr[6:10], ttl)
+	binary.BigEndian.PutUint16(rr[10:12], 4)
+	copy(rr[12:], ip4)
+	return append(reply(0, 1), rr...)
+}
+
+// header returns the header of a response to query
+func header(query []byte, questions uint16, rcode byte, answers uint16) []byte {
+	h := make([]byte, 12)
+	copy(h[0:2], query[0:2])
+	// QR and AA are set, the opcode and RD are kept from the query
+	h[2] = 0x80 | 0x04 | (query[2] & 0x79)
+	h[3] = rcode & 0x0f
+	binary.BigEndian.PutUint16(h[4:6], questions)
+	binary.BigEndian.PutUint16(h[6:8], answers)
+	return h
+}
+
+// Serve answers the DNS queries received on conn for names under domain, until conn is closed
+func Serve(conn net.PacketConn, domain string, lookup LookupFunc) error {
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		resp := answer(buf[:n], domain, lookup)
+		if resp == nil {
+			continue
+		}
+		if _, err := conn.WriteTo(resp, addr); err != nil {
+			glog.Warningf("reply to %s: %v", addr, err)
+		}
+	}
+}
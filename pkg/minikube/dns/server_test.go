@@ -0,0 +1,74 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer conn.Close()
+	lookup := func(name string) (net.IP, bool) {
+		if name == "web.default.svc.minikube" {
+			return net.ParseIP("192.168.39.2"), true
+		}
+		return nil, false
+	}
+	go func() {
+		if err := Serve(conn, "minikube.", lookup); err != nil {
+			t.Logf("Serve: %v", err)
+		}
+	}()
+
+	r := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return net.Dial("udp", conn.LocalAddr().String())
+		},
+	}
+	addrs, err := r.LookupHost(context.Background(), "Web.Default.svc.minikube")
+	if err != nil {
+		t.Fatalf("LookupHost: %v", err)
+	}
+	if want := []string{"192.168.39.2"}; !reflect.DeepEqual(addrs, want) {
+		t.Errorf("LookupHost() = %v, want %v", addrs, want)
+	}
+	for _, name := range []string{"missing.default.svc.minikube", "example.com"} {
+		if addrs, err := r.LookupHost(context.Background(), name); err == nil {
+			t.Errorf("LookupHost(%s) = %v, want error", name, addrs)
+		}
+	}
+}
+
+func TestAnswerMalformed(t *testing.T) {
+	lookup := func(string) (net.IP, bool) { return nil, false }
+	if got := answer([]byte{1, 2, 3}, "minikube", lookup); got != nil {
+		t.Errorf("answer(short) = %v, want nil", got)
+	}
+	// A header announcing a question which is not there
+	got := answer([]byte{0xab, 0xcd, 0x01, 0, 0, 1, 0, 0, 0, 0, 0, 0, 7, 'm'}, "minikube", lookup)
+	if len(got) != 12 || got[0] != 0xab || got[1] != 0xcd || got[3]&0x0f != rcodeFormError {
+		t.Errorf("answer(truncated) = %v, want a format error", got)
+	}
+}
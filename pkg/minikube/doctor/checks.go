@@ -0,0 +1,305 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shirou/gopsutil/disk"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// minFreeDiskGB is the free disk space needed for the ISO, the image cache and a VM disk to grow into
+const minFreeDiskGB = 5
+
+// Host access, replaced in tests
+var (
+	lookPath   = exec.LookPath
+	readFile   = ioutil.ReadFile
+	stat       = os.Stat
+	getenv     = os.Getenv
+	interfaces = net.Interfaces
+	freeDisk   = func(path string) (uint64, error) {
+		u, err := disk.Usage(path)
+		if err != nil {
+			return 0, err
+		}
+		return u.Free, nil
+	}
+	output = func(name string, args ...string) (string, error) {
+		b, err := exec.Command(name, args...).Output()
+		return string(b), err
+	}
+	portFree = func(port int) bool {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			return false
+		}
+		l.Close()
+		return true
+	}
+	lookupHost = func(host string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := net.DefaultResolver.LookupHost(ctx, host)
+		return err
+	}
+)
+
+// Checks are the checks run by minikube doctor
+var Checks = []Check{
+	{Name: "Virtualization", Func: checkVirtualization},
+	{Name: "Driver", Func: checkDriver},
+	{Name: "Cgroups", Func: checkCgroups},
+	{Name: "Proxy", Func: checkProxy, Warn: true},
+	{Name: "VPN", Func: checkVPN, Warn: true},
+	{Name: "Ports", Func: checkPorts},
+	{Name: "Disk space", Func: checkDisk, Warn: true},
+	{Name: "DNS", Func: checkDNS},
+}
+
+// virtFlags matches the cpuinfo flags of CPUs with Intel VT-x or AMD-v
+var virtFlags = regexp.MustCompile(`(?m)^flags\s*:.*\b(vmx|svm)\b`)
+
+func checkVirtualization(env Env) (string, error) {
+	if env.Driver == constants.DriverNone {
+		return "", skip("the none driver does not create a VM")
+	}
+	switch env.GOOS {
+	case "linux":
+		cpuinfo, err := readFile("/proc/cpuinfo")
+		if err != nil {
+			return "", err
+		}
+		if !virtFlags.Match(cpuinfo) {
+			return "", errors.New("CPU does not support hardware virtualization, or it is disabled in the BIOS")
+		}
+		if env.Driver != constants.DriverKvm2 && env.Driver != constants.DriverQemu2 {
+			return "VT-x/AMD-v is available", nil
+		}
+		if _, err := stat("/dev/kvm"); err != nil {
+			return "", errors.New("/dev/kvm does not exist")
+		}
+		f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+		if err != nil {
+			if os.IsPermission(err) {
+				return "", errors.New("/dev/kvm: permission denied")
+			}
+			return "", err
+		}
+		f.Close()
+		return "KVM is available", nil
+	case "darwin":
+		out, err := output("sysctl", "-n", "kern.hv_support")
+		if err != nil || strings.TrimSpace(out) != "1" {
+			return "", errors.New("this Mac does not support Hypervisor.framework")
+		}
+		return "Hypervisor.framework is available", nil
+	case "windows":
+		if env.Driver == constants.DriverHyperv {
+			return "", skip("Hyper-V hides the firmware virtualization setting")
+		}
+		out, err := output("powershell", "-NoProfile", "-NonInteractive", "(Get-CimInstance Win32_Processor).VirtualizationFirmwareEnabled")
+		if err != nil {
+			return "", skip("unable to query the firmware virtualization setting")
+		}
+		if !strings.Contains(out, "True") {
+			return "", errors.New("CPU does not support hardware virtualization, or it is disabled in the BIOS")
+		}
+		return "VT-x/AMD-v is enabled in the firmware", nil
+	default:
+		return "", skip("unsupported OS: " + env.GOOS)
+	}
+}
+
+// driverBinaries are the binaries each driver needs, with the error given when one is missing
+var driverBinaries = map[string][]struct {
+	name    string
+	missing string
+}{
+	constants.DriverVirtualbox: {{"VBoxManage", "VBoxManage not found. Make sure VirtualBox is installed and VBoxManage is in the path"}},
+	constants.DriverKvm2: {
+		{"docker-machine-driver-kvm2", `Driver "kvm2" not found. Do you have the plugin binary "docker-machine-driver-kvm2" accessible in your PATH?`},
+		{"virsh", "virsh not found, libvirt is not installed"},
+	},
+	constants.DriverHyperkit: {{"docker-machine-driver-hyperkit", `Driver "hyperkit" not found. Do you have the plugin binary "docker-machine-driver-hyperkit" accessible in your PATH?`}},
+	constants.DriverVfkit:    {{"vfkit", "vfkit not found in your PATH"}},
+	constants.DriverNone:     {{"docker", "docker not found, the none driver runs Kubernetes with the docker daemon of the host"}},
+	constants.DriverHyperv:   {{"powershell", "Powershell was not found in the path"}},
+}
+
+func checkDriver(env Env) (string, error) {
+	binaries := driverBinaries[env.Driver]
+	if env.Driver == constants.DriverQemu2 {
+		program := "qemu-system-x86_64"
+		if runtime.GOARCH == "arm64" {
+			program = "qemu-system-aarch64"
+		}
+		binaries = append(binaries, struct {
+			name    string
+			missing string
+		}{program, program + " not found, qemu is not installed"})
+	}
+	if len(binaries) == 0 {
+		return "", skip("no known requirements for the " + env.Driver + " driver")
+	}
+	found := []string{}
+	for _, b := range binaries {
+		p, err := lookPath(b.name)
+		if err != nil {
+			return "", errors.New(b.missing)
+		}
+		found = append(found, p)
+	}
+	return strings.Join(found, ", "), nil
+}
+
+func checkCgroups(env Env) (string, error) {
+	if env.GOOS != "linux" || env.Driver != constants.DriverNone {
+		return "", skip("only the none driver uses the cgroups of the host")
+	}
+	if _, err := stat("/sys/fs/cgroup/cgroup.controllers"); err == nil {
+		return "", errors.New("the none driver requires cgroup v1, but this host uses cgroup v2")
+	}
+	return "cgroup v1", nil
+}
+
+// driverSubnets are the networks VMs are attached to by default
+var driverSubnets = map[string]string{
+	constants.DriverVirtualbox: "192.168.99.0/24",
+	constants.DriverKvm2:       "192.168.39.0/24",
+	constants.DriverHyperkit:   "192.168.64.0/24",
+	constants.DriverVfkit:      "192.168.64.0/24",
+}
+
+func proxyEnv(name string) string {
+	if v := getenv(name); v != "" {
+		return v
+	}
+	return getenv(strings.ToLower(name))
+}
+
+func checkProxy(env Env) (string, error) {
+	proxy := proxyEnv("HTTPS_PROXY")
+	if proxy == "" {
+		proxy = proxyEnv("HTTP_PROXY")
+	}
+	if proxy == "" {
+		return "no proxy is configured", nil
+	}
+	subnet, ok := driverSubnets[env.Driver]
+	if !ok {
+		return "using proxy " + proxy, nil
+	}
+	_, vmNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range strings.Split(proxyEnv("NO_PROXY"), ",") {
+		e = strings.TrimSpace(e)
+		if ip := net.ParseIP(e); ip != nil && vmNet.Contains(ip) {
+			return "using proxy " + proxy, nil
+		}
+		if _, n, err := net.ParseCIDR(e); err == nil && n.Contains(vmNet.IP) {
+			return "using proxy " + proxy, nil
+		}
+	}
+	return "", fmt.Errorf("a proxy is configured, but NO_PROXY does not include the minikube VM network %s", subnet)
+}
+
+var vpnInterface = regexp.MustCompile(`^(tun|tap|utun|ppp|wg|ipsec|gpd|cscotun)\d*$`)
+
+func checkVPN(env Env) (string, error) {
+	ifaces, err := interfaces()
+	if err != nil {
+		return "", err
+	}
+	for _, i := range ifaces {
+		if i.Flags&net.FlagUp == 0 || !vpnInterface.MatchString(i.Name) {
+			continue
+		}
+		addrs, err := i.Addrs()
+		if err != nil {
+			continue
+		}
+		// macOS system services keep utun interfaces up with only link-local IPv6 addresses
+		for _, a := range addrs {
+			if ipn, ok := a.(*net.IPNet); ok && ipn.IP.To4() != nil && !ipn.IP.IsLinkLocalUnicast() {
+				return "", fmt.Errorf("VPN interface %s is up", i.Name)
+			}
+		}
+	}
+	return "no VPN interface is up", nil
+}
+
+// nonePorts are the host ports the none driver runs Kubernetes on
+var nonePorts = []int{8443, 2379, 2380, 10250, 10251, 10252}
+
+func checkPorts(env Env) (string, error) {
+	if env.Driver != constants.DriverNone {
+		return "", skip("only the none driver uses the ports of the host")
+	}
+	for _, p := range nonePorts {
+		if !portFree(p) {
+			return "", fmt.Errorf("port %d is already in use", p)
+		}
+	}
+	return "the Kubernetes ports are free", nil
+}
+
+func checkDisk(env Env) (string, error) {
+	// The minikube home may not have been created yet
+	dir := env.MinikubeHome
+	for {
+		if _, err := stat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	free, err := freeDisk(dir)
+	if err != nil {
+		return "", err
+	}
+	gb := float64(free) / (1 << 30)
+	if gb < minFreeDiskGB {
+		return "", fmt.Errorf("free disk space in %s is below %d GB (%.1f GB free)", dir, minFreeDiskGB, gb)
+	}
+	return fmt.Sprintf("%.1f GB free in %s", gb, dir), nil
+}
+
+// dnsHosts are the hosts minikube downloads from
+var dnsHosts = []string{"storage.googleapis.com", "k8s.gcr.io"}
+
+func checkDNS(env Env) (string, error) {
+	for _, h := range dnsHosts {
+		if err := lookupHost(h); err != nil {
+			return "", fmt.Errorf("cannot resolve %s: %v", h, err)
+		}
+	}
+	return "resolved " + strings.Join(dnsHosts, ", "), nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package doctor diagnoses host environment issues which keep minikube from working
+package doctor
+
+import (
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/problem"
+)
+
+// Status is the outcome of a check
+type Status int
+
+const (
+	// OK means no issue was found
+	OK Status = iota
+	// Warning means an issue was found which may keep minikube from working
+	Warning
+	// Failure means an issue was found which keeps minikube from working
+	Failure
+	// Skipped means the check does not apply to this host or driver
+	Skipped
+)
+
+// Env is the host environment being checked
+type Env struct {
+	// GOOS is the host operating system
+	GOOS string
+	// Driver is the VM driver minikube is used with
+	Driver string
+	// MinikubeHome is the directory minikube stores its files in
+	MinikubeHome string
+}
+
+// Check is a diagnostic of the host environment
+type Check struct {
+	Name string
+	// Func returns a description of what it found, or an error describing the issue.
+	// Errors are matched against known problems to suggest a fix.
+	Func func(Env) (string, error)
+	// Warn reports issues found by this check as warnings rather than failures
+	Warn bool
+}
+
+// Result is the outcome of running a check
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+	// Problem is the known problem matching the issue found, if any
+	Problem *problem.Problem
+}
+
+// skip is returned by checks which do not apply
+type skip string
+
+func (s skip) Error() string {
+	return string(s)
+}
+
+// Run runs checks against an environment, in order
+func Run(env Env, checks []Check) []Result {
+	results := []Result{}
+	for _, c := range checks {
+		glog.Infof("Running check %q", c.Name)
+		msg, err := c.Func(env)
+		r := Result{Name: c.Name, Status: OK, Message: msg}
+		switch err := err.(type) {
+		case nil:
+		case skip:
+			r.Status = Skipped
+			r.Message = err.Error()
+		default:
+			r.Status = Failure
+			if c.Warn {
+				r.Status = Warning
+			}
+			r.Message = err.Error()
+			r.Problem = problem.FromError(err, env.GOOS)
+		}
+		glog.Infof("Check %q: %d %s", c.Name, r.Status, r.Message)
+		results = append(results, r)
+	}
+	return results
+}
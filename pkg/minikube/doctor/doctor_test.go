@@ -0,0 +1,141 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doctor
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Func: func(Env) (string, error) { return "fine", nil }},
+		{Name: "skipped", Func: func(Env) (string, error) { return "", skip("not here") }},
+		{Name: "warning", Warn: true, Func: func(Env) (string, error) { return "", errors.New("VPN interface utun4 is up") }},
+		{Name: "failure", Func: func(Env) (string, error) { return "", errors.New("port 8443 is already in use") }},
+		{Name: "unknown", Func: func(Env) (string, error) { return "", errors.New("something else") }},
+	}
+	var want = []struct {
+		status  Status
+		problem string
+	}{
+		{status: OK},
+		{status: Skipped},
+		{status: Warning, problem: "VPN_ACTIVE"},
+		{status: Failure, problem: "PORT_IN_USE"},
+		{status: Failure},
+	}
+	got := Run(Env{GOOS: "linux"}, checks)
+	if len(got) != len(want) {
+		t.Fatalf("Run() returned %d results, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		r := got[i]
+		if r.Status != w.status {
+			t.Errorf("%s: status = %d, want %d", r.Name, r.Status, w.status)
+		}
+		id := ""
+		if r.Problem != nil {
+			id = r.Problem.ID
+		}
+		if id != w.problem {
+			t.Errorf("%s: problem = %q, want %q", r.Name, id, w.problem)
+		}
+	}
+}
+
+func TestCheckDriver(t *testing.T) {
+	defer func(f func(string) (string, error)) { lookPath = f }(lookPath)
+	lookPath = func(name string) (string, error) {
+		if name == "virsh" {
+			return "", errors.New("not found")
+		}
+		return "/usr/bin/" + name, nil
+	}
+
+	if msg, err := checkDriver(Env{Driver: "virtualbox"}); err != nil || msg != "/usr/bin/VBoxManage" {
+		t.Errorf("checkDriver(virtualbox) = %q, %v", msg, err)
+	}
+	_, err := checkDriver(Env{Driver: "kvm2"})
+	if r := Run(Env{Driver: "kvm2"}, []Check{{Name: "driver", Func: checkDriver}}); err == nil || r[0].Problem == nil || r[0].Problem.ID != "LIBVIRT_NOT_FOUND" {
+		t.Errorf("checkDriver(kvm2) = %v, %+v, want LIBVIRT_NOT_FOUND", err, r[0].Problem)
+	}
+	if _, err := checkDriver(Env{Driver: "mock-driver"}); err == nil {
+		t.Errorf("checkDriver(mock-driver) should be skipped")
+	}
+}
+
+func TestCheckVirtualization(t *testing.T) {
+	defer func(f func(string) ([]byte, error)) { readFile = f }(readFile)
+	var tests = []struct {
+		cpuinfo string
+		wantErr bool
+	}{
+		{cpuinfo: "processor\t: 0\nflags\t\t: fpu vme de pse vmx ssse3\n"},
+		{cpuinfo: "processor\t: 0\nflags\t\t: fpu vme de pse svm\n"},
+		{cpuinfo: "processor\t: 0\nflags\t\t: fpu vme de pse sse2\n", wantErr: true},
+	}
+	for _, tc := range tests {
+		readFile = func(string) ([]byte, error) { return []byte(tc.cpuinfo), nil }
+		if _, err := checkVirtualization(Env{GOOS: "linux", Driver: "virtualbox"}); (err != nil) != tc.wantErr {
+			t.Errorf("checkVirtualization(%q) = %v, wantErr: %v", tc.cpuinfo, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCheckProxy(t *testing.T) {
+	defer func(f func(string) string) { getenv = f }(getenv)
+	var tests = []struct {
+		env     map[string]string
+		wantErr bool
+	}{
+		{env: map[string]string{}},
+		{env: map[string]string{"HTTPS_PROXY": "http://proxy:3128"}, wantErr: true},
+		{env: map[string]string{"HTTPS_PROXY": "http://proxy:3128", "NO_PROXY": "localhost,192.168.39.0/24"}},
+		{env: map[string]string{"https_proxy": "http://proxy:3128", "no_proxy": "192.168.39.12"}},
+		{env: map[string]string{"HTTP_PROXY": "http://proxy:3128", "NO_PROXY": "192.168.99.0/24"}, wantErr: true},
+	}
+	for _, tc := range tests {
+		getenv = func(k string) string { return tc.env[k] }
+		if _, err := checkProxy(Env{Driver: "kvm2"}); (err != nil) != tc.wantErr {
+			t.Errorf("checkProxy(%v) = %v, wantErr: %v", tc.env, err, tc.wantErr)
+		}
+	}
+}
+
+func TestCheckDisk(t *testing.T) {
+	defer func(f func(string) (uint64, error)) { freeDisk = f }(freeDisk)
+	defer func(f func(string) (os.FileInfo, error)) { stat = f }(stat)
+	stat = func(p string) (os.FileInfo, error) {
+		if p == "/home/me" {
+			return nil, nil
+		}
+		return nil, os.ErrNotExist
+	}
+	var checked string
+	freeDisk = func(p string) (uint64, error) {
+		checked = p
+		return 2 << 30, nil
+	}
+	if _, err := checkDisk(Env{MinikubeHome: "/home/me/.minikube"}); err == nil {
+		t.Errorf("checkDisk() with 2 GB free should fail")
+	}
+	if checked != "/home/me" {
+		t.Errorf("checkDisk() checked %q, want /home/me", checked)
+	}
+}
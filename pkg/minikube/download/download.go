@@ -0,0 +1,428 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package download downloads the files minikube caches, such as the ISO and the Kubernetes binaries
+package download
+
+import (
+	"crypto"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/minikube/pkg/minikube/constants"
+
+	// Register the hash functions which checksums can use
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// chunks is the number of parallel requests of a download
+const chunks = 4
+
+// minChunkSize is the size below which files are not split
+var minChunkSize int64 = 8 << 20
+
+// Options are the options of a download
+type Options struct {
+	// Checksum is the hex digest of the file, or the URL of a file which lists it, such as a .sha256 file
+	Checksum string
+	// Hash is the hash function of the checksum. If zero, the file is not verified
+	Hash crypto.Hash
+	// Signature is the URL of the cosign signature of the file, verified according to the verify-downloads setting
+	Signature string
+	// Mirrors are tried in order, before the URL itself. See MirrorURL
+	Mirrors []string
+	// Name is shown with a progress bar. If empty, there is no progress bar
+	Name string
+}
+
+// state describes the remote file of a partial download, to only resume it from the same file
+type state struct {
+	URL    string
+	Size   int64
+	ETag   string
+	Chunks int
+}
+
+// Mirrors returns the mirrors of the download-mirrors setting, in order
+func Mirrors() []string {
+	mirrors := []string{}
+	for _, m := range strings.Split(viper.GetString(constants.DownloadMirrors), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			mirrors = append(mirrors, m)
+		}
+	}
+	return mirrors
+}
+
+// MirrorURL returns the address of a file on a mirror: the scheme and host of the mirror, followed by its path
+// and the path of the file. For instance, https://storage.googleapis.com/minikube/iso/minikube.iso is
+// https://mirror.example.com/cache/minikube/iso/minikube.iso on the https://mirror.example.com/cache mirror.
+func MirrorURL(src string, mirror string) (string, error) {
+	s, err := url.Parse(src)
+	if err != nil {
+		return "", errors.Wrap(err, "url")
+	}
+	m, err := url.Parse(mirror)
+	if err != nil || m.Scheme == "" || m.Host == "" {
+		return "", fmt.Errorf("invalid mirror %q, expected an URL such as https://mirror.example.com/path", mirror)
+	}
+	m.Path = strings.TrimSuffix(m.Path, "/") + s.Path
+	m.RawQuery = s.RawQuery
+	return m.String(), nil
+}
+
+// ToFile downloads src to dst, from the download cache, the first of the mirrors which has it, or from src itself.
+// Large files are downloaded in parallel chunks from the servers which support ranges, and interrupted
+// downloads resume from the partial files left next to dst.
+func ToFile(src string, dst string, opts Options) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if _, _, err := verification(); err != nil {
+		return err
+	}
+	var errs []string
+	// A peer running minikube cache serve has the file at the same path of its cache, and is tried first
+	if u := PeerURL(dst); u != "" {
+		err := fetchPeer(u, src, dst, opts)
+		if err == nil {
+			return nil
+		}
+		glog.Warningf("Failed to download %s from the download cache: %v", u, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", u, err))
+	}
+	for _, m := range append(opts.Mirrors, "") {
+		u, o := src, opts
+		if m != "" {
+			var err error
+			if u, err = MirrorURL(src, m); err != nil {
+				return err
+			}
+			if isURL(o.Checksum) {
+				if o.Checksum, err = MirrorURL(o.Checksum, m); err != nil {
+					return err
+				}
+			}
+			if o.Signature != "" {
+				if o.Signature, err = MirrorURL(o.Signature, m); err != nil {
+					return err
+				}
+			}
+		}
+		err := fetch(u, dst, o)
+		if err == nil {
+			return nil
+		}
+		glog.Warningf("Failed to download %s: %v", u, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", u, err))
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// fetchPeer downloads the file src from the download cache u. Checksum files list files by the name of src,
+// so the checksum is looked up before.
+func fetchPeer(u string, src string, dst string, opts Options) error {
+	if opts.Hash != 0 && opts.Checksum != "" {
+		var err error
+		if opts.Checksum, err = wantChecksum(opts.Checksum, path.Base(src)); err != nil {
+			return errors.Wrap(err, "checksum")
+		}
+	}
+	return fetch(u, dst, opts)
+}
+
+// fetch downloads src to dst, and verifies its checksum and signature
+func fetch(src string, dst string, opts Options) error {
+	want := ""
+	if opts.Hash != 0 && opts.Checksum != "" {
+		var err error
+		if want, err = wantChecksum(opts.Checksum, path.Base(src)); err != nil {
+			return errors.Wrap(err, "checksum")
+		}
+	}
+
+	// Probing for ranges with the first byte also fetches the whole file from the servers which don't support them
+	req, err := http.NewRequest("GET", src, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	tmp := dst + partialSuffix
+	st := state{URL: src, ETag: resp.Header.Get("ETag"), Chunks: 1}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if st.Size, err = rangeSize(resp.Header.Get("Content-Range")); err != nil {
+			return err
+		}
+		st.Chunks = int(st.Size/minChunkSize) + 1
+		if st.Chunks > chunks {
+			st.Chunks = chunks
+		}
+		resp.Body.Close()
+		if err := resume(tmp, st); err != nil {
+			return err
+		}
+	case http.StatusOK:
+		st.Size = resp.ContentLength
+		clean(tmp)
+	default:
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	var bar *pb.ProgressBar
+	if opts.Name != "" {
+		bar = pb.Full.Start64(st.Size)
+		bar.Set("prefix", opts.Name+": ")
+		bar.Set(pb.Bytes, true)
+		// Just a hair less than 80 (standard terminal width) for aesthetics & pasting into docs
+		bar.SetWidth(79)
+		defer bar.Finish()
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		err = writeChunk(partPath(tmp, 0), resp.Body, bar)
+	} else {
+		err = fetchChunks(src, tmp, st, bar)
+	}
+	if err != nil {
+		return err
+	}
+	if err := join(tmp, st.Chunks); err != nil {
+		return err
+	}
+	if want != "" {
+		if err := verify(tmp, opts.Hash, want); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := checkSignature(tmp, opts.Signature, path.Base(src)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// fetchChunks downloads the missing bytes of every chunk in parallel
+func fetchChunks(src string, tmp string, st state, bar *pb.ProgressBar) error {
+	size := st.Size / int64(st.Chunks)
+	var g errgroup.Group
+	for i := 0; i < st.Chunks; i++ {
+		start := int64(i) * size
+		end := start + size - 1
+		if i == st.Chunks-1 {
+			end = st.Size - 1
+		}
+		part := partPath(tmp, i)
+		done := int64(0)
+		if fi, err := os.Stat(part); err == nil {
+			done = fi.Size()
+		}
+		if start+done > end+1 {
+			os.Remove(part)
+			done = 0
+		}
+		if bar != nil {
+			bar.Add64(done)
+		}
+		if start+done > end {
+			continue
+		}
+		from := start + done
+		g.Go(func() error {
+			req, err := http.NewRequest("GET", src, nil)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", from, end))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusPartialContent {
+				return fmt.Errorf("unexpected response to a range request: %s", resp.Status)
+			}
+			return writeChunk(part, resp.Body, bar)
+		})
+	}
+	return g.Wait()
+}
+
+// writeChunk appends the body of a response to a part file
+func writeChunk(part string, body io.Reader, bar *pb.ProgressBar) error {
+	f, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if bar != nil {
+		body = bar.NewProxyReader(body)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return errors.Wrap(err, "downloading")
+	}
+	return f.Close()
+}
+
+// resume keeps the parts of a previous download of the same file, and deletes them otherwise
+func resume(tmp string, st state) error {
+	b, err := ioutil.ReadFile(tmp + ".json")
+	var prev state
+	if err == nil && json.Unmarshal(b, &prev) == nil && prev == st {
+		glog.Infof("Resuming the download of %s", st.URL)
+		return nil
+	}
+	clean(tmp)
+	b, err = json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(tmp+".json", b, 0644)
+}
+
+// clean deletes the parts of a download
+func clean(tmp string) {
+	parts, _ := filepath.Glob(tmp + ".part*")
+	for _, p := range append(parts, tmp+".json") {
+		os.Remove(p)
+	}
+}
+
+// join concatenates the parts of a download into tmp, and deletes them
+func join(tmp string, n int) error {
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		p, err := os.Open(partPath(tmp, i))
+		if err != nil {
+			f.Close()
+			return err
+		}
+		_, err = io.Copy(f, p)
+		p.Close()
+		if err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	clean(tmp)
+	return nil
+}
+
+func partPath(tmp string, i int) string {
+	return fmt.Sprintf("%s.part%d", tmp, i)
+}
+
+// rangeSize returns the total size of a Content-Range header, such as bytes 0-0/1234
+func rangeSize(header string) (int64, error) {
+	i := strings.LastIndex(header, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	size, err := strconv.ParseInt(header[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Range %q", header)
+	}
+	return size, nil
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// wantChecksum returns the checksum of a file named name, either given directly or listed by a checksum file
+func wantChecksum(checksum string, name string) (string, error) {
+	if !isURL(checksum) {
+		return checksum, nil
+	}
+	resp, err := http.Get(checksum)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: unexpected response: %s", checksum, resp.Status)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return parseChecksum(string(b), name)
+}
+
+// parseChecksum returns the checksum of a file from the content of a checksum file: either a single
+// checksum, or lines of a checksum and a file name, as written by sha256sum
+func parseChecksum(content string, name string) (string, error) {
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 1 {
+			return fields[0], nil
+		}
+		if len(fields) >= 2 && path.Base(strings.TrimPrefix(fields[1], "*")) == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s", name)
+}
+
+// verify returns an error if the checksum of a file is not want
+func verify(file string, h crypto.Hash, want string) error {
+	if !h.Available() {
+		return fmt.Errorf("unsupported hash function: %v", h)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hash := h.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); !strings.EqualFold(got, want) {
+		return fmt.Errorf("invalid checksum: got %s, want %s", got, want)
+	}
+	return nil
+}
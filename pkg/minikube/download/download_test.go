@@ -0,0 +1,240 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// server serves content at /files/minikube.iso, with ranges, and counts the bytes it sends
+type server struct {
+	content []byte
+	lock    sync.Mutex
+	sent    int
+	ranges  int
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/files/minikube.iso":
+		s.lock.Lock()
+		if r.Header.Get("Range") != "" && r.Header.Get("Range") != "bytes=0-0" {
+			s.ranges++
+		}
+		s.lock.Unlock()
+		cw := &countingWriter{ResponseWriter: w, s: s}
+		http.ServeContent(cw, r, "minikube.iso", time.Time{}, bytes.NewReader(s.content))
+	case "/files/minikube.iso.sha256":
+		sum := sha256.Sum256(s.content)
+		fmt.Fprintf(w, "%s  other.iso\n%s  minikube.iso\n", strings.Repeat("0", 64), hex.EncodeToString(sum[:]))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	s *server
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	c.s.lock.Lock()
+	c.s.sent += len(b)
+	c.s.lock.Unlock()
+	return c.ResponseWriter.Write(b)
+}
+
+func newServer(t *testing.T, size int) (*server, *httptest.Server) {
+	s := &server{content: make([]byte, size)}
+	for i := range s.content {
+		s.content[i] = byte(i % 251)
+	}
+	return s, httptest.NewServer(s)
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	return dir
+}
+
+func TestToFile(t *testing.T) {
+	defer func(size int64) { minChunkSize = size }(minChunkSize)
+	minChunkSize = 1000
+
+	s, ts := newServer(t, 10000)
+	defer ts.Close()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "iso", "minikube.iso")
+	opts := Options{Checksum: ts.URL + "/files/minikube.iso.sha256", Hash: crypto.SHA256}
+	if err := ToFile(ts.URL+"/files/minikube.iso", dst, opts); err != nil {
+		t.Fatalf("ToFile: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, s.content) {
+		t.Errorf("downloaded %d bytes which differ from the %d bytes served", len(got), len(s.content))
+	}
+	if s.ranges != chunks {
+		t.Errorf("downloaded in %d chunks, want %d", s.ranges, chunks)
+	}
+	if left, _ := filepath.Glob(dst + ".*"); len(left) != 0 {
+		t.Errorf("files left after the download: %v", left)
+	}
+}
+
+func TestToFileResumes(t *testing.T) {
+	defer func(size int64) { minChunkSize = size }(minChunkSize)
+	minChunkSize = 1000
+
+	s, ts := newServer(t, 10000)
+	defer ts.Close()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// A previous download got the first chunk, and half of the second one
+	src := ts.URL + "/files/minikube.iso"
+	dst := filepath.Join(dir, "minikube.iso")
+	tmp := dst + ".download"
+	b, err := json.Marshal(state{URL: src, Size: 10000, Chunks: chunks})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for name, content := range map[string][]byte{tmp + ".json": b, partPath(tmp, 0): s.content[:2500], partPath(tmp, 1): s.content[2500:3750]} {
+		if err := ioutil.WriteFile(name, content, 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	if err := ToFile(src, dst, Options{}); err != nil {
+		t.Fatalf("ToFile: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, s.content) {
+		t.Errorf("downloaded %d bytes which differ from the %d bytes served", len(got), len(s.content))
+	}
+	// The probe of the ranges sends one byte
+	if want := 1 + 10000 - 3750; s.sent != want {
+		t.Errorf("the server sent %d bytes, want %d", s.sent, want)
+	}
+}
+
+func TestToFileMirrors(t *testing.T) {
+	s, ts := newServer(t, 100)
+	defer ts.Close()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// The first mirror doesn't have the file, and the origin doesn't exist
+	mirrors := []string{ts.URL + "/missing", ts.URL}
+	dst := filepath.Join(dir, "minikube.iso")
+	opts := Options{Checksum: "https://unused.invalid/files/minikube.iso.sha256", Hash: crypto.SHA256, Mirrors: mirrors}
+	if err := ToFile("https://unused.invalid/files/minikube.iso", dst, opts); err != nil {
+		t.Fatalf("ToFile: %v", err)
+	}
+	got, err := ioutil.ReadFile(dst)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, s.content) {
+		t.Errorf("downloaded %q, want %q", got, s.content)
+	}
+}
+
+func TestToFileChecksumMismatch(t *testing.T) {
+	_, ts := newServer(t, 100)
+	defer ts.Close()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	dst := filepath.Join(dir, "minikube.iso")
+	if err := ToFile(ts.URL+"/files/minikube.iso", dst, Options{Checksum: strings.Repeat("0", 64), Hash: crypto.SHA256}); err == nil {
+		t.Fatalf("ToFile succeeded, want a checksum error")
+	}
+	if left, _ := filepath.Glob(dst + "*"); len(left) != 0 {
+		t.Errorf("files left after the failed download: %v", left)
+	}
+}
+
+func TestMirrorURL(t *testing.T) {
+	var tests = []struct {
+		mirror  string
+		want    string
+		wantErr bool
+	}{
+		{"https://mirror.example.com", "https://mirror.example.com/kubernetes-release/release/v1.15.2/bin/linux/amd64/kubelet.sha1", false},
+		{"http://10.0.0.5:8080/cache/", "http://10.0.0.5:8080/cache/kubernetes-release/release/v1.15.2/bin/linux/amd64/kubelet.sha1", false},
+		{"mirror.example.com", "", true},
+	}
+	for _, tc := range tests {
+		got, err := MirrorURL("https://storage.googleapis.com/kubernetes-release/release/v1.15.2/bin/linux/amd64/kubelet.sha1", tc.mirror)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("MirrorURL(%s) error = %v, wantErr: %v", tc.mirror, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("MirrorURL(%s) = %q, want %q", tc.mirror, got, tc.want)
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	var tests = []struct {
+		description string
+		content     string
+		want        string
+		wantErr     bool
+	}{
+		{"single checksum", "abc\n", "abc", false},
+		{"sha256sum", "abc  k3s\ndef  k3s-arm64\n", "def", false},
+		{"binary mode", "abc *dist/k3s-arm64\n", "abc", false},
+		{"missing", "abc  k3s\n", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got, err := parseChecksum(tc.content, "k3s-arm64")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseChecksum() error = %v, wantErr: %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseChecksum() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// partialSuffix is in the names of the files of interrupted downloads
+const partialSuffix = ".download"
+
+// PeerURL returns the address of dst on the cache of the download-cache setting, which is served by minikube cache serve,
+// or an empty string if it isn't set or dst isn't in the cache
+func PeerURL(dst string) string {
+	peer := strings.TrimSuffix(viper.GetString(constants.DownloadCache), "/")
+	if peer == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(constants.MakeMiniPath("cache"), dst)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return peer + "/" + (&url.URL{Path: filepath.ToSlash(rel)}).EscapedPath()
+}
+
+// CacheHandler serves the files of the cache directory dir, with ranges, so that peers download them as from a mirror.
+// The files of interrupted downloads are hidden, so that peers only get complete files.
+func CacheHandler(dir string) http.Handler {
+	fs := http.FileServer(cacheFS{http.Dir(dir)})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		glog.Infof("%s %s %s", r.RemoteAddr, r.Method, r.URL.Path)
+		fs.ServeHTTP(w, r)
+	})
+}
+
+// cacheFS is a file system which hides the files of interrupted downloads
+type cacheFS struct {
+	http.FileSystem
+}
+
+// Open opens a file, unless it is the file of an interrupted download
+func (c cacheFS) Open(name string) (http.File, error) {
+	if partial(name) {
+		return nil, os.ErrNotExist
+	}
+	f, err := c.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return cacheFile{f}, nil
+}
+
+// cacheFile is a file whose directory listing hides the files of interrupted downloads
+type cacheFile struct {
+	http.File
+}
+
+// Readdir lists the files of a directory, without the files of interrupted downloads
+func (c cacheFile) Readdir(n int) ([]os.FileInfo, error) {
+	infos, err := c.File.Readdir(n)
+	shown := []os.FileInfo{}
+	for _, i := range infos {
+		if !partial(i.Name()) {
+			shown = append(shown, i)
+		}
+	}
+	return shown, err
+}
+
+// partial returns whether name is the file of an interrupted download, such as a .download.part0 file
+func partial(name string) bool {
+	return strings.Contains(path.Base(name), partialSuffix)
+}
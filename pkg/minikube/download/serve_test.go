@@ -0,0 +1,125 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestCacheHandler(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"iso/minikube-v1.3.0.iso":                "iso",
+		"iso/minikube-v1.4.0.iso.download.part0": "partial",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ts := httptest.NewServer(CacheHandler(dir))
+	defer ts.Close()
+
+	var tests = []struct {
+		path     string
+		status   int
+		contains string
+		hidden   string
+	}{
+		{"/iso/minikube-v1.3.0.iso", http.StatusOK, "iso", ""},
+		{"/iso/minikube-v1.4.0.iso.download.part0", http.StatusNotFound, "", ""},
+		{"/iso/", http.StatusOK, "minikube-v1.3.0.iso", ".download"},
+	}
+	for _, tc := range tests {
+		resp, err := http.Get(ts.URL + tc.path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", tc.path, err)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", tc.path, err)
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("GET %s = %s, want %d", tc.path, resp.Status, tc.status)
+		}
+		if !strings.Contains(string(b), tc.contains) {
+			t.Errorf("GET %s = %q, want it to contain %q", tc.path, b, tc.contains)
+		}
+		if tc.hidden != "" && strings.Contains(string(b), tc.hidden) {
+			t.Errorf("GET %s = %q, want it not to contain %q", tc.path, b, tc.hidden)
+		}
+	}
+}
+
+func TestToFilePeer(t *testing.T) {
+	peer := tempDir(t)
+	defer os.RemoveAll(peer)
+	if err := os.MkdirAll(filepath.Join(peer, "v1.15.2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(peer, "v1.15.2", "kubelet"), []byte("from the peer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(CacheHandler(peer))
+	defer ts.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("from the origin"))
+	}))
+	defer origin.Close()
+
+	home := tempDir(t)
+	defer os.RemoveAll(home)
+	os.Setenv(constants.MinikubeHome, home)
+	defer os.Unsetenv(constants.MinikubeHome)
+	viper.Set(constants.DownloadCache, ts.URL+"/")
+	defer viper.Set(constants.DownloadCache, "")
+
+	var tests = []struct {
+		dst  string
+		want string
+	}{
+		{constants.MakeMiniPath("cache", "v1.15.2", "kubelet"), "from the peer"},
+		{constants.MakeMiniPath("cache", "v1.15.2", "kubeadm"), "from the origin"},
+		{filepath.Join(home, "kubelet"), "from the origin"},
+	}
+	for _, tc := range tests {
+		if err := ToFile(origin.URL+"/kubelet", tc.dst, Options{}); err != nil {
+			t.Fatalf("ToFile(%s): %v", tc.dst, err)
+		}
+		b, err := ioutil.ReadFile(tc.dst)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if string(b) != tc.want {
+			t.Errorf("%s = %q, want %q", tc.dst, b, tc.want)
+		}
+	}
+}
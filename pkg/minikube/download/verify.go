@@ -0,0 +1,183 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/version"
+)
+
+// The modes of the verification of signatures
+const (
+	// VerifyStrict fails the downloads whose signature is missing or invalid
+	VerifyStrict = "strict"
+	// VerifyWarn warns about the downloads whose signature is missing or invalid
+	VerifyWarn = "warn"
+	// VerifyOff doesn't verify signatures
+	VerifyOff = "off"
+)
+
+// VerifyModes are the valid values of the verify-downloads setting
+var VerifyModes = []string{VerifyStrict, VerifyWarn, VerifyOff}
+
+// verification returns the mode of the verify-downloads setting, and the public key which verifies the signatures:
+// the one of the verify-downloads-key setting, or the one minikube is built with
+func verification() (string, *ecdsa.PublicKey, error) {
+	mode := viper.GetString(constants.VerifyDownloads)
+	switch mode {
+	case "", VerifyOff:
+		return VerifyOff, nil, nil
+	case VerifyStrict, VerifyWarn:
+	default:
+		return "", nil, fmt.Errorf("invalid %s %q, expected one of %s", constants.VerifyDownloads, mode, strings.Join(VerifyModes, ", "))
+	}
+	pub, err := PublicKey()
+	return mode, pub, err
+}
+
+// PublicKey returns the public key which verifies the signatures of downloads: the one of the verify-downloads-key setting,
+// or the one minikube is built with. It is nil if there is none.
+func PublicKey() (*ecdsa.PublicKey, error) {
+	key := version.GetDownloadKey()
+	if p := viper.GetString(constants.VerifyDownloadsKey); p != "" {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "public key")
+		}
+		key = string(b)
+	}
+	if key == "" {
+		return nil, nil
+	}
+	return parsePublicKey(key)
+}
+
+// parsePublicKey parses an ECDSA public key, as PEM or as base64 DER
+func parsePublicKey(key string) (*ecdsa.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		der, err = block.Bytes, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "public key")
+	}
+	ec, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key %T, cosign keys are ECDSA", pub)
+	}
+	return ec, nil
+}
+
+// checkSignature verifies the signature of file according to the verify-downloads setting.
+// In warn mode, failures are reported and nil is returned.
+func checkSignature(file string, sigURL string, name string) error {
+	mode, key, err := verification()
+	if err != nil {
+		return err
+	}
+	if mode == VerifyOff || sigURL == "" {
+		return nil
+	}
+	if key == nil {
+		err = fmt.Errorf("no public key, set %s", constants.VerifyDownloadsKey)
+	} else {
+		err = verifySignature(file, sigURL, key)
+	}
+	if err == nil {
+		return nil
+	}
+	if mode == VerifyWarn {
+		out.WarningT("Unable to verify the signature of {{.name}}: {{.error}}", out.V{"name": name, "error": err})
+		return nil
+	}
+	return errors.Wrap(err, "signature")
+}
+
+// verifySignature returns an error if the signature at sigURL, as written by 'cosign sign-blob', is not a signature of file by key
+func verifySignature(file string, sigURL string, key *ecdsa.PublicKey) error {
+	resp, err := http.Get(sigURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected response: %s", sigURL, resp.Status)
+	}
+	sig, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if err := verifyDigest(h.Sum(nil), sig, key); err != nil {
+		return errors.Wrap(err, sigURL)
+	}
+	return nil
+}
+
+// VerifyBlob returns an error if sig, as written by 'cosign sign-blob', is not a signature of data by key
+func VerifyBlob(data []byte, sig []byte, key *ecdsa.PublicKey) error {
+	digest := sha256.Sum256(data)
+	return verifyDigest(digest[:], sig, key)
+}
+
+// verifyDigest returns an error if sig, as written by 'cosign sign-blob', is not a signature of the SHA-256 digest by key
+func verifyDigest(digest []byte, sig []byte, key *ecdsa.PublicKey) error {
+	der, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return errors.Wrap(err, "decoding signature")
+	}
+	var rs struct {
+		R, S *big.Int
+	}
+	if _, err := asn1.Unmarshal(der, &rs); err != nil {
+		return errors.Wrap(err, "parsing signature")
+	}
+	if !ecdsa.Verify(key, digest, rs.R, rs.S) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package download
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestToFileSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	content := []byte("minikube")
+	digest := sha256.Sum256(content)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	sig := base64.StdEncoding.EncodeToString(der)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/minikube.iso", "/unsigned.iso":
+			w.Write(content)
+		case "/minikube.iso.sig":
+			w.Write([]byte(sig + "\n"))
+		case "/other.iso.sig":
+			w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("invalid"))))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	keyFile := filepath.Join(dir, "cosign.pub")
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	viper.Set(constants.VerifyDownloadsKey, keyFile)
+	defer viper.Set(constants.VerifyDownloadsKey, "")
+	defer viper.Set(constants.VerifyDownloads, "")
+
+	var tests = []struct {
+		description string
+		mode        string
+		file        string
+		sig         string
+		wantErr     bool
+	}{
+		{"valid", VerifyStrict, "minikube.iso", "minikube.iso.sig", false},
+		{"missing", VerifyStrict, "unsigned.iso", "unsigned.iso.sig", true},
+		{"invalid", VerifyStrict, "minikube.iso", "other.iso.sig", true},
+		{"missing with warn", VerifyWarn, "unsigned.iso", "unsigned.iso.sig", false},
+		{"off", VerifyOff, "unsigned.iso", "unsigned.iso.sig", false},
+		{"unknown mode", "yes", "minikube.iso", "minikube.iso.sig", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			viper.Set(constants.VerifyDownloads, tc.mode)
+			dst := filepath.Join(dir, tc.description, tc.file)
+			err := ToFile(ts.URL+"/"+tc.file, dst, Options{Signature: ts.URL + "/" + tc.sig})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToFile() error = %v, wantErr: %v", err, tc.wantErr)
+			}
+			if _, err := os.Stat(dst); os.IsNotExist(err) != tc.wantErr {
+				t.Errorf("%s exists: %v, want %v", dst, !os.IsNotExist(err), !tc.wantErr)
+			}
+		})
+	}
+}
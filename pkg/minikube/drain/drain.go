@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package drain cordons and drains nodes, as kubectl drain does
+package drain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1beta1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+)
+
+// mirrorAnnotation is set on the pods the kubelet creates in the apiserver for static pods
+const mirrorAnnotation = "kubernetes.io/config.mirror"
+
+// pollInterval is how often evictions are retried, and deletions checked
+var pollInterval = 2 * time.Second
+
+// Cordon marks a node as unschedulable, or as schedulable again
+func Cordon(client kubernetes.Interface, node string, unschedulable bool) error {
+	n, err := client.CoreV1().Nodes().Get(node, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "getting node %s", node)
+	}
+	if n.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+	n.Spec.Unschedulable = unschedulable
+	if _, err := client.CoreV1().Nodes().Update(n); err != nil {
+		return errors.Wrapf(err, "updating node %s", node)
+	}
+	return nil
+}
+
+// Drain evicts the pods of a node, and waits until they are deleted.
+// The mirror pods of static pods, the pods of daemon sets and finished pods are left alone, as kubectl drain --ignore-daemonsets does.
+// Evictions refused by a pod disruption budget are retried until the timeout.
+func Drain(client kubernetes.Interface, node string, timeout time.Duration) error {
+	selector := fields.SelectorFromSet(fields.Set{"spec.nodeName": node}).String()
+	list, err := client.CoreV1().Pods(meta.NamespaceAll).List(meta.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return errors.Wrap(err, "listing pods")
+	}
+	pods := []core.Pod{}
+	for _, p := range list.Items {
+		if p.Spec.NodeName == node && evictable(p) {
+			pods = append(pods, p)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	for _, p := range pods {
+		if err := evict(client, p, deadline); err != nil {
+			return err
+		}
+	}
+	for _, p := range pods {
+		if err := waitForDeletion(client, p, deadline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evictable returns whether a pod is evicted by Drain
+func evictable(p core.Pod) bool {
+	if _, ok := p.Annotations[mirrorAnnotation]; ok {
+		return false
+	}
+	if p.Status.Phase == core.PodSucceeded || p.Status.Phase == core.PodFailed {
+		return false
+	}
+	if c := meta.GetControllerOf(&p); c != nil && c.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+// evict evicts a pod, retrying while a pod disruption budget forbids it
+func evict(client kubernetes.Interface, p core.Pod, deadline time.Time) error {
+	e := &policy.Eviction{ObjectMeta: meta.ObjectMeta{Name: p.Name, Namespace: p.Namespace}}
+	for {
+		glog.Infof("Evicting pod %s/%s", p.Namespace, p.Name)
+		err := client.PolicyV1beta1().Evictions(p.Namespace).Evict(e)
+		switch {
+		case err == nil, apierr.IsNotFound(err):
+			return nil
+		case !apierr.IsTooManyRequests(err):
+			return errors.Wrapf(err, "evicting pod %s/%s", p.Namespace, p.Name)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out evicting pod %s/%s: %v", p.Namespace, p.Name, err)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// waitForDeletion waits until a pod is deleted, or replaced by another pod of the same name
+func waitForDeletion(client kubernetes.Interface, p core.Pod, deadline time.Time) error {
+	for {
+		got, err := client.CoreV1().Pods(p.Namespace).Get(p.Name, meta.GetOptions{})
+		if apierr.IsNotFound(err) || (err == nil && got.UID != p.UID) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "getting pod %s/%s", p.Namespace, p.Name)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for pod %s/%s to be deleted", p.Namespace, p.Name)
+		}
+		time.Sleep(pollInterval)
+	}
+}
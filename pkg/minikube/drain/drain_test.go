@@ -0,0 +1,118 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drain
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newPod(name, node string) *core.Pod {
+	return &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default", UID: "uid-" + name},
+		Spec:       core.PodSpec{NodeName: node},
+		Status:     core.PodStatus{Phase: core.PodRunning},
+	}
+}
+
+func TestCordon(t *testing.T) {
+	client := fake.NewSimpleClientset(&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube"}})
+	for _, unschedulable := range []bool{true, false} {
+		if err := Cordon(client, "minikube", unschedulable); err != nil {
+			t.Fatalf("Cordon(%v) error = %v", unschedulable, err)
+		}
+		n, err := client.CoreV1().Nodes().Get("minikube", meta.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if n.Spec.Unschedulable != unschedulable {
+			t.Errorf("Cordon(%v): Unschedulable = %v", unschedulable, n.Spec.Unschedulable)
+		}
+	}
+	if err := Cordon(client, "missing", true); err == nil {
+		t.Errorf("Cordon(missing) = nil, want error")
+	}
+}
+
+func TestDrain(t *testing.T) {
+	pollInterval = time.Millisecond
+
+	mirror := newPod("kube-apiserver-minikube", "minikube")
+	mirror.Annotations = map[string]string{mirrorAnnotation: "hash"}
+	daemon := newPod("kube-proxy-abcde", "minikube")
+	isController := true
+	daemon.OwnerReferences = []meta.OwnerReference{{Kind: "DaemonSet", Name: "kube-proxy", Controller: &isController}}
+	done := newPod("job-abcde", "minikube")
+	done.Status.Phase = core.PodSucceeded
+
+	objects := []runtime.Object{
+		newPod("web", "minikube"),
+		newPod("db", "minikube"),
+		newPod("elsewhere", "other"),
+		mirror,
+		daemon,
+		done,
+	}
+	client := fake.NewSimpleClientset(objects...)
+
+	evicted := []string{}
+	refused := false
+	client.PrependReactor("post", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		a, ok := action.(k8stesting.GetAction)
+		if !ok || a.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		// The first eviction of db is refused, as if by a pod disruption budget
+		if a.GetName() == "db" && !refused {
+			refused = true
+			return true, nil, apierr.NewTooManyRequests("disruption budget", 0)
+		}
+		evicted = append(evicted, a.GetName())
+		return true, nil, client.Tracker().Delete(core.SchemeGroupVersion.WithResource("pods"), a.GetNamespace(), a.GetName())
+	})
+
+	if err := Drain(client, "minikube", time.Minute); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	sort.Strings(evicted)
+	if want := []string{"db", "web"}; !reflect.DeepEqual(evicted, want) {
+		t.Errorf("evicted %v, want %v", evicted, want)
+	}
+	if !refused {
+		t.Errorf("refused eviction was not retried")
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	pollInterval = time.Millisecond
+	client := fake.NewSimpleClientset(newPod("web", "minikube"))
+	client.PrependReactor("post", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierr.NewTooManyRequests("disruption budget", 0)
+	})
+	if err := Drain(client, "minikube", 10*time.Millisecond); err == nil {
+		t.Errorf("Drain() = nil, want timeout error")
+	}
+}
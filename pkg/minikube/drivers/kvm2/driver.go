@@ -26,6 +26,7 @@ import (
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/registry"
+	"k8s.io/minikube/pkg/util"
 )
 
 func init() {
@@ -52,11 +53,25 @@ type kvmDriver struct {
 	Boot2DockerURL string
 	DiskPath       string
 	GPU            bool
+	GPUVendor      string
 	Hidden         bool
 	ConnectionURI  string
+	IPv6           bool
+	VirtiofsShares []string
+	StaticIP       string
+	USBDevices     []string
+	NUMANodes      int
+	SRIOVVFs       int
+	ExtraDisks     int
 }
 
 func createKVM2Host(config cfg.MachineConfig) interface{} {
+	// IPv6 lives on its own private network, as the existing one can't be changed under running VMs
+	privateNetwork := "minikube-net"
+	ipv6 := config.IPFamily == util.IPFamilyIPv6 || config.IPFamily == util.IPFamilyDual
+	if ipv6 {
+		privateNetwork = "minikube-net-ipv6"
+	}
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
 			MachineName: cfg.GetMachineName(),
@@ -66,13 +81,21 @@ func createKVM2Host(config cfg.MachineConfig) interface{} {
 		Memory:         config.Memory,
 		CPU:            config.CPUs,
 		Network:        config.KVMNetwork,
-		PrivateNetwork: "minikube-net",
+		PrivateNetwork: privateNetwork,
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
 		DiskSize:       config.DiskSize,
 		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), fmt.Sprintf("%s.rawdisk", cfg.GetMachineName())),
 		ISO:            filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), "boot2docker.iso"),
 		GPU:            config.KVMGPU,
+		GPUVendor:      config.KVMGPUVendor,
 		Hidden:         config.KVMHidden,
 		ConnectionURI:  config.KVMQemuURI,
+		IPv6:           ipv6,
+		VirtiofsShares: config.VirtiofsShares,
+		StaticIP:       config.StaticIP,
+		USBDevices:     config.USBDevices,
+		NUMANodes:      config.NUMANodes,
+		SRIOVVFs:       config.SRIOVVFs,
+		ExtraDisks:     config.ExtraDisks,
 	}
 }
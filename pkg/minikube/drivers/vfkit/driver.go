@@ -0,0 +1,53 @@
+// +build darwin
+
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfkit
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/drivers/vfkit"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/registry"
+)
+
+func init() {
+	if err := registry.Register(registry.DriverDef{
+		Name:          constants.DriverVfkit,
+		Builtin:       true,
+		ConfigCreator: createVfkitHost,
+		DriverCreator: func() drivers.Driver {
+			return vfkit.NewDriver("", "")
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("register failed: %v", err))
+	}
+}
+
+// createVfkitHost creates a vfkit Driver from a MachineConfig
+func createVfkitHost(config cfg.MachineConfig) interface{} {
+	d := vfkit.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
+	d.Memory = config.Memory
+	d.CPU = config.CPUs
+	d.DiskSize = config.DiskSize
+	d.VirtiofsShares = config.VirtiofsShares
+	return d
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption manages the EncryptionConfiguration of the apiserver, which encrypts the secrets in etcd
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// APIVersion is the API version of the EncryptionConfiguration, supported by Kubernetes v1.13 and newer
+	APIVersion = "apiserver.config.k8s.io/v1"
+	// Kind is the kind of the EncryptionConfiguration
+	Kind = "EncryptionConfiguration"
+	// keySize is the size of the AES keys, in bytes
+	keySize = 32
+	// keyPrefix is the prefix of the names of the keys, which are numbered
+	keyPrefix = "key"
+)
+
+// Configuration is an EncryptionConfiguration which encrypts the secrets with AES-CBC keys. The first key encrypts,
+// and all the keys decrypt. Secrets which were written before the encryption was enabled are read as they are.
+type Configuration struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Resources  []Resource `yaml:"resources"`
+}
+
+// Resource are the providers of a list of resources
+type Resource struct {
+	Resources []string   `yaml:"resources"`
+	Providers []Provider `yaml:"providers"`
+}
+
+// Provider is an encryption provider, of which only one field is set
+type Provider struct {
+	AESCBC   *AESConfig `yaml:"aescbc,omitempty"`
+	Identity *struct{}  `yaml:"identity,omitempty"`
+}
+
+// AESConfig are the keys of an AES provider
+type AESConfig struct {
+	Keys []Key `yaml:"keys"`
+}
+
+// Key is a named key, base64 encoded
+type Key struct {
+	Name   string `yaml:"name"`
+	Secret string `yaml:"secret"`
+}
+
+// VMPath is where the EncryptionConfiguration is copied in the VM, in the certificates directory which the
+// apiserver mounts
+var VMPath = path.Join(util.DefaultCertPath, "encryption-config.yaml")
+
+// Path returns where the EncryptionConfiguration of a profile is stored on the host
+func Path(profile string) string {
+	return constants.MakeMiniPath("profiles", profile, "encryption-config.yaml")
+}
+
+// New returns an EncryptionConfiguration of the secrets, with a new key
+func New() (*Configuration, error) {
+	k, err := newKey(1)
+	if err != nil {
+		return nil, err
+	}
+	return &Configuration{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Resources: []Resource{{
+			Resources: []string{"secrets"},
+			Providers: []Provider{
+				{AESCBC: &AESConfig{Keys: []Key{k}}},
+				{Identity: &struct{}{}},
+			},
+		}},
+	}, nil
+}
+
+// newKey returns a random key with the number n
+func newKey(n int) (Key, error) {
+	b := make([]byte, keySize)
+	if _, err := rand.Read(b); err != nil {
+		return Key{}, errors.Wrap(err, "generating key")
+	}
+	return Key{Name: keyPrefix + strconv.Itoa(n), Secret: base64.StdEncoding.EncodeToString(b)}, nil
+}
+
+// Load reads an EncryptionConfiguration
+func Load(path string) (*Configuration, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Configuration{}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	if _, err := c.aes(); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", path)
+	}
+	return c, nil
+}
+
+// LoadOrNew reads an EncryptionConfiguration, or writes a new one if there is none
+func LoadOrNew(path string) (*Configuration, error) {
+	c, err := Load(path)
+	if err == nil || !os.IsNotExist(err) {
+		return c, err
+	}
+	if c, err = New(); err != nil {
+		return nil, err
+	}
+	return c, c.Save(path)
+}
+
+// Save writes the EncryptionConfiguration, which is only readable by the user
+func (c *Configuration) Save(path string) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "encoding")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Copy copies the EncryptionConfiguration to the VM, where the apiserver reads it on start
+func (c *Configuration) Copy(runner command.Runner) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.Wrap(err, "encoding")
+	}
+	return runner.Copy(assets.NewMemoryAssetTarget(b, VMPath, "0600"))
+}
+
+// aes returns the AES provider of the secrets
+func (c *Configuration) aes() (*AESConfig, error) {
+	for _, r := range c.Resources {
+		for _, p := range r.Providers {
+			if p.AESCBC != nil && len(p.AESCBC.Keys) > 0 {
+				return p.AESCBC, nil
+			}
+		}
+	}
+	return nil, errors.New("no aescbc provider with keys")
+}
+
+// Keys returns the names of the keys, the one which encrypts first
+func (c *Configuration) Keys() []string {
+	a, err := c.aes()
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, k := range a.Keys {
+		names = append(names, k.Name)
+	}
+	return names
+}
+
+// AddKey adds a new key, which encrypts the secrets from now on. The older keys still decrypt the secrets
+// which they encrypted, until RemoveOldKeys.
+func (c *Configuration) AddKey() (string, error) {
+	a, err := c.aes()
+	if err != nil {
+		return "", err
+	}
+	n := 0
+	for _, k := range a.Keys {
+		if i, err := strconv.Atoi(strings.TrimPrefix(k.Name, keyPrefix)); err == nil && i > n {
+			n = i
+		}
+	}
+	k, err := newKey(n + 1)
+	if err != nil {
+		return "", err
+	}
+	a.Keys = append([]Key{k}, a.Keys...)
+	return k.Name, nil
+}
+
+// RemoveOldKeys only keeps the key which encrypts, once all the secrets are encrypted with it
+func (c *Configuration) RemoveOldKeys() error {
+	a, err := c.aes()
+	if err != nil {
+		return err
+	}
+	a.Keys = a.Keys[:1]
+	return nil
+}
+
+// RewriteSecrets updates every secret without changes, so that the apiserver encrypts it with the current key
+func RewriteSecrets(client kubernetes.Interface) (int, error) {
+	list, err := client.CoreV1().Secrets(meta.NamespaceAll).List(meta.ListOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "listing secrets")
+	}
+	for i := range list.Items {
+		s := &list.Items[i]
+		if _, err := client.CoreV1().Secrets(s.Namespace).Update(s); err != nil {
+			return i, errors.Wrapf(err, "updating secret %s/%s", s.Namespace, s.Name)
+		}
+	}
+	return len(list.Items), nil
+}
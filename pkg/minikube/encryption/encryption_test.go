@@ -0,0 +1,113 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfiguration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "profiles", "p1", "encryption-config.yaml")
+
+	c, err := LoadOrNew(path)
+	if err != nil {
+		t.Fatalf("LoadOrNew: %v", err)
+	}
+	if got := c.Keys(); !reflect.DeepEqual(got, []string{"key1"}) {
+		t.Errorf("Keys() = %v, want [key1]", got)
+	}
+	a, err := c.aes()
+	if err != nil {
+		t.Fatalf("aes: %v", err)
+	}
+	if b, err := base64.StdEncoding.DecodeString(a.Keys[0].Secret); err != nil || len(b) != keySize {
+		t.Errorf("key1 = %q, want %d base64 encoded bytes", a.Keys[0].Secret, keySize)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Stat(%s) = %v, %v, want mode 0600", path, info, err)
+	}
+
+	// The key is kept when loading the configuration again
+	loaded, err := LoadOrNew(path)
+	if err != nil {
+		t.Fatalf("LoadOrNew: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, c) {
+		t.Errorf("LoadOrNew() = %+v, want %+v", loaded, c)
+	}
+	if p := loaded.Resources[0].Providers; len(p) != 2 || p[1].Identity == nil {
+		t.Errorf("providers = %+v, want aescbc then identity", p)
+	}
+
+	name, err := c.AddKey()
+	if err != nil {
+		t.Fatalf("AddKey: %v", err)
+	}
+	if got := c.Keys(); name != "key2" || !reflect.DeepEqual(got, []string{"key2", "key1"}) {
+		t.Errorf("AddKey() = %s, keys %v, want key2, [key2 key1]", name, got)
+	}
+	if err := c.RemoveOldKeys(); err != nil {
+		t.Fatalf("RemoveOldKeys: %v", err)
+	}
+	if got := c.Keys(); !reflect.DeepEqual(got, []string{"key2"}) {
+		t.Errorf("Keys() after RemoveOldKeys = %v, want [key2]", got)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("kind: EncryptionConfiguration\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load() of a configuration without keys = nil, want error")
+	}
+}
+
+func TestRewriteSecrets(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&core.Secret{ObjectMeta: meta.ObjectMeta{Name: "a", Namespace: "default"}},
+		&core.Secret{ObjectMeta: meta.ObjectMeta{Name: "b", Namespace: "kube-system"}},
+	)
+	n, err := RewriteSecrets(client)
+	if err != nil {
+		t.Fatalf("RewriteSecrets: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("RewriteSecrets() = %d, want 2", n)
+	}
+	updates := 0
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "update" {
+			updates++
+		}
+	}
+	if updates != 2 {
+		t.Errorf("%d secrets were updated, want 2", updates)
+	}
+}
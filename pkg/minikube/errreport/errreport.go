@@ -0,0 +1,186 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errreport sends reports of the errors minikube exits with to an endpoint chosen by the user.
+// Reports which cannot be sent, for instance when offline, are spooled and sent along with the next one.
+package errreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxSpooled is how many unsent reports are kept
+	maxSpooled = 20
+	// attempts is how many times a report is posted before it is spooled
+	attempts = 3
+)
+
+// Report describes an error minikube exited with
+type Report struct {
+	Version string    `json:"version"`
+	GOOS    string    `json:"goos"`
+	GOARCH  string    `json:"goarch"`
+	Command string    `json:"command"`
+	Code    int       `json:"code"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// Reporter posts reports as JSON to an endpoint
+type Reporter struct {
+	URL   string
+	Spool string
+	// Backoff is how long to wait after the first failed attempt. It doubles after each attempt.
+	Backoff time.Duration
+	client  *http.Client
+}
+
+// New returns a reporter posting to url through the proxy of the environment, which spools unsent reports in spool
+func New(url string, spool string) *Reporter {
+	return &Reporter{
+		URL:     url,
+		Spool:   spool,
+		Backoff: time.Second,
+		client:  &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}},
+	}
+}
+
+// permanentError is a response which posting the report again would not change
+type permanentError struct {
+	status string
+}
+
+func (e *permanentError) Error() string {
+	return fmt.Sprintf("rejected: %s", e.status)
+}
+
+// post posts a report once
+func (r *Reporter) post(data []byte) error {
+	resp, err := r.client.Post(r.URL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%s: %s", r.URL, resp.Status)
+	default:
+		return &permanentError{status: resp.Status}
+	}
+}
+
+// retry posts a report, backing off exponentially between attempts
+func (r *Reporter) retry(data []byte) error {
+	var err error
+	wait := r.Backoff
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			glog.Infof("error report failed, will retry after %s: %v", wait, err)
+			time.Sleep(wait)
+			wait *= 2
+		}
+		err = r.post(data)
+		if _, ok := err.(*permanentError); err == nil || ok {
+			return err
+		}
+	}
+	return err
+}
+
+// Send sends the spooled reports, then rep. If the endpoint is unreachable, rep is spooled.
+func (r *Reporter) Send(rep Report) error {
+	data, err := json.Marshal(rep)
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	if err := r.Flush(); err != nil {
+		return r.spool(data, err)
+	}
+	err = r.retry(data)
+	if _, ok := err.(*permanentError); err == nil || ok {
+		return err
+	}
+	return r.spool(data, err)
+}
+
+// spool keeps a report to send later, and returns why it was not sent
+func (r *Reporter) spool(data []byte, cause error) error {
+	if err := os.MkdirAll(r.Spool, 0700); err != nil {
+		return errors.Wrap(err, "spool")
+	}
+	path := filepath.Join(r.Spool, fmt.Sprintf("%d.json", time.Now().UnixNano()))
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrap(err, "spool")
+	}
+	// Drop the oldest reports, rather than growing forever when the endpoint is gone
+	paths, err := r.spooled()
+	if err != nil {
+		return errors.Wrap(err, "spool")
+	}
+	for len(paths) > maxSpooled {
+		if err := os.Remove(paths[0]); err != nil {
+			glog.Warningf("remove %s: %v", paths[0], err)
+		}
+		paths = paths[1:]
+	}
+	return errors.Wrap(cause, "spooled")
+}
+
+// spooled returns the paths of the spooled reports, oldest first
+func (r *Reporter) spooled() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(r.Spool, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Flush sends the spooled reports, oldest first. It stops at the first report which cannot be sent.
+func (r *Reporter) Flush() error {
+	paths, err := r.spooled()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		err = r.post(data)
+		if _, ok := err.(*permanentError); err != nil && !ok {
+			return err
+		}
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
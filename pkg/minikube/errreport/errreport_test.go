@@ -0,0 +1,128 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errreport
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// endpoint records the reports it is sent, after failing a number of times
+type endpoint struct {
+	mu      sync.Mutex
+	fail    int
+	status  int
+	reports []Report
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.fail > 0 {
+		e.fail--
+		w.WriteHeader(e.status)
+		return
+	}
+	var r Report
+	if err := json.NewDecoder(req.Body).Decode(&r); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	e.reports = append(e.reports, r)
+}
+
+func spooledReports(t *testing.T, dir string) int {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	return len(paths)
+}
+
+func TestSend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "errreport")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	e := &endpoint{fail: 2, status: http.StatusServiceUnavailable}
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	r := New(srv.URL, dir)
+	r.Backoff = time.Millisecond
+	if err := r.Send(Report{Message: "retried", Code: 70}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(e.reports) != 1 || e.reports[0].Message != "retried" || e.reports[0].Code != 70 {
+		t.Errorf("reports = %+v, want the retried report", e.reports)
+	}
+
+	// Every attempt fails: the report is spooled, and sent with the next one
+	e.fail = attempts
+	if err := r.Send(Report{Message: "offline"}); err == nil {
+		t.Errorf("Send to a failing endpoint = nil, want error")
+	}
+	if n := spooledReports(t, dir); n != 1 {
+		t.Errorf("%d reports spooled, want 1", n)
+	}
+	if err := r.Send(Report{Message: "online"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(e.reports) != 3 || e.reports[1].Message != "offline" || e.reports[2].Message != "online" {
+		t.Errorf("reports = %+v, want the spooled report before the new one", e.reports)
+	}
+	if n := spooledReports(t, dir); n != 0 {
+		t.Errorf("%d reports spooled after a flush, want 0", n)
+	}
+
+	// A rejected report is dropped rather than retried
+	e.fail, e.status = 1, http.StatusBadRequest
+	if err := r.Send(Report{Message: "rejected"}); err == nil {
+		t.Errorf("Send of a rejected report = nil, want error")
+	}
+	if n := spooledReports(t, dir); n != 0 {
+		t.Errorf("%d reports spooled after a rejection, want 0", n)
+	}
+}
+
+func TestSpoolLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "errreport")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := New("http://127.0.0.1:1/unreachable", dir)
+	r.Backoff = time.Millisecond
+	for i := 0; i < maxSpooled+3; i++ {
+		if err := r.Send(Report{Code: i}); err == nil {
+			t.Fatalf("Send to an unreachable endpoint = nil, want error")
+		}
+	}
+	if n := spooledReports(t, dir); n != maxSpooled {
+		t.Errorf("%d reports spooled, want %d", n, maxSpooled)
+	}
+}
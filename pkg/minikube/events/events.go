@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events merges the lifecycle events of minikube with the events of the Kubernetes cluster
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/minikube/pkg/minikube/audit"
+)
+
+// The sources of the events
+const (
+	// Minikube is the source of the events of the audit log
+	Minikube = "minikube"
+	// Kubernetes is the source of the events of the cluster
+	Kubernetes = "kubernetes"
+)
+
+// Event is a lifecycle event of minikube or an event of the cluster, as listed by minikube events
+type Event struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	// Type is Normal or Warning, as for Kubernetes events
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Namespace string `json:"namespace,omitempty"`
+	Object    string `json:"object"`
+	Message   string `json:"message"`
+}
+
+// FromAudit returns the event of an entry of the audit log. The events of failed commands are warnings.
+func FromAudit(e audit.Entry) Event {
+	ev := Event{
+		Time:    e.Time,
+		Source:  Minikube,
+		Type:    core.EventTypeNormal,
+		Reason:  strings.Title(e.Event),
+		Object:  "profile/" + e.Profile,
+		Message: fmt.Sprintf("minikube %s", e.Command),
+	}
+	if e.User != "" {
+		ev.Message += " by " + e.User
+	}
+	if e.Event == audit.Failed {
+		ev.Type = core.EventTypeWarning
+		if e.Message != "" {
+			ev.Message += ": " + e.Message
+		}
+	}
+	return ev
+}
+
+// FromKubernetes returns the event of a Kubernetes event, at the time it last occurred
+func FromKubernetes(e core.Event) Event {
+	t := e.LastTimestamp.Time
+	if t.IsZero() {
+		t = e.EventTime.Time
+	}
+	if t.IsZero() {
+		t = e.FirstTimestamp.Time
+	}
+	if t.IsZero() {
+		t = e.CreationTimestamp.Time
+	}
+	return Event{
+		Time:      t.UTC(),
+		Source:    Kubernetes,
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Namespace: e.Namespace,
+		Object:    strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name,
+		Message:   strings.TrimSpace(e.Message),
+	}
+}
+
+// Filter selects events. Empty fields select all the events.
+type Filter struct {
+	// Since selects the events which occurred after it
+	Since time.Time
+	// Sources are Minikube or Kubernetes
+	Sources []string
+	// Types are Normal or Warning
+	Types []string
+}
+
+// Match returns whether the filter selects an event
+func (f Filter) Match(e Event) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if len(f.Sources) > 0 && !f.HasSource(e.Source) {
+		return false
+	}
+	return len(f.Types) == 0 || contains(f.Types, e.Type)
+}
+
+// HasSource returns whether the filter selects the events of a source
+func (f Filter) HasSource(source string) bool {
+	return len(f.Sources) == 0 || contains(f.Sources, source)
+}
+
+// contains returns whether a list of names contains a name, regardless of case
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort sorts events chronologically. Events which occurred at the same time keep their order.
+func Sort(evs []Event) {
+	sort.SliceStable(evs, func(i, j int) bool { return evs[i].Time.Before(evs[j].Time) })
+}
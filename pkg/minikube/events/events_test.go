@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/minikube/pkg/minikube/audit"
+)
+
+func TestFromAudit(t *testing.T) {
+	at := time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		name  string
+		entry audit.Entry
+		want  Event
+	}{
+		{
+			name:  "started",
+			entry: audit.Entry{Time: at, Profile: "p1", Command: "start", Event: audit.Started, User: "alice"},
+			want:  Event{Time: at, Source: Minikube, Type: "Normal", Reason: "Started", Object: "profile/p1", Message: "minikube start by alice"},
+		},
+		{
+			name:  "failed",
+			entry: audit.Entry{Time: at, Profile: "p1", Command: "addons enable", Event: audit.Failed, Message: "Error enabling addon"},
+			want:  Event{Time: at, Source: Minikube, Type: "Warning", Reason: "Failed", Object: "profile/p1", Message: "minikube addons enable: Error enabling addon"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, FromAudit(tc.entry)); diff != "" {
+				t.Errorf("FromAudit() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFromKubernetes(t *testing.T) {
+	created := time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC)
+	last := created.Add(time.Minute)
+	e := core.Event{
+		ObjectMeta:     meta.ObjectMeta{Namespace: "default", CreationTimestamp: meta.NewTime(created)},
+		InvolvedObject: core.ObjectReference{Kind: "Pod", Name: "nginx"},
+		Type:           "Warning",
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container\n",
+	}
+	want := Event{Time: created, Source: Kubernetes, Type: "Warning", Reason: "BackOff", Namespace: "default", Object: "pod/nginx", Message: "Back-off restarting failed container"}
+	if diff := cmp.Diff(want, FromKubernetes(e)); diff != "" {
+		t.Errorf("FromKubernetes() mismatch (-want +got):\n%s", diff)
+	}
+
+	e.LastTimestamp = meta.NewTime(last)
+	if got := FromKubernetes(e).Time; !got.Equal(last) {
+		t.Errorf("FromKubernetes().Time = %s, want the last timestamp %s", got, last)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	at := time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC)
+	normal := Event{Time: at, Source: Minikube, Type: "Normal"}
+	warning := Event{Time: at.Add(time.Minute), Source: Kubernetes, Type: "Warning"}
+	var tests = []struct {
+		name   string
+		filter Filter
+		want   []bool
+	}{
+		{"all", Filter{}, []bool{true, true}},
+		{"since", Filter{Since: at.Add(time.Second)}, []bool{false, true}},
+		{"source", Filter{Sources: []string{"Minikube"}}, []bool{true, false}},
+		{"type", Filter{Types: []string{"warning"}}, []bool{false, true}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := []bool{tc.filter.Match(normal), tc.filter.Match(warning)}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Match() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	at := time.Date(2019, 8, 5, 10, 0, 0, 0, time.UTC)
+	evs := []Event{
+		{Time: at.Add(time.Minute), Reason: "third"},
+		{Time: at, Reason: "first"},
+		{Time: at, Reason: "second"},
+	}
+	Sort(evs)
+	got := []string{}
+	for _, e := range evs {
+		got = append(got, e.Reason)
+	}
+	if diff := cmp.Diff([]string{"first", "second", "third"}, got); diff != "" {
+		t.Errorf("Sort() mismatch (-want +got):\n%s", diff)
+	}
+}
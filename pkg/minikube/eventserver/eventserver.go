@@ -0,0 +1,368 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventserver streams the events of a minikube command to local WebSocket clients, such as the plugins of IDEs
+package eventserver
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// Path is the path of the WebSocket endpoint
+const Path = "/events"
+
+// websocketGUID is the GUID which the accept key of the handshake is derived with, as defined by RFC 6455
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// The opcodes of the frames
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xA
+)
+
+// clientBuffer is how many events a client may lag behind before it is disconnected, so that minikube never waits for it
+const clientBuffer = 256
+
+// Server sends each event to the connected clients. Clients which connect late first receive the previous events.
+type Server struct {
+	listener net.Listener
+	mu       sync.Mutex
+	history  []out.Event
+	clients  map[*client]bool
+	closed   bool
+}
+
+// client is a WebSocket connection
+type client struct {
+	conn    net.Conn
+	events  chan out.Event
+	writeMu sync.Mutex
+	done    chan struct{}
+}
+
+// Listen starts a server on addr. Addresses without a host, such as ":9999", listen on localhost only.
+func Listen(addr string) (*Server, error) {
+	if strings.HasPrefix(addr, ":") {
+		addr = "127.0.0.1" + addr
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrap(err, "listen")
+	}
+	s := &Server{listener: l, clients: map[*client]bool{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, s.serveEvents)
+	go func() {
+		if err := http.Serve(l, mux); err != nil && !s.isClosed() {
+			glog.Warningf("event server: %v", err)
+		}
+	}()
+	return s, nil
+}
+
+// URL returns the URL of the WebSocket endpoint
+func (s *Server) URL() string {
+	return "ws://" + s.listener.Addr().String() + Path
+}
+
+// Send sends an event to the connected clients, and keeps it for the clients which connect later
+func (s *Server) Send(e out.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.history = append(s.history, e)
+	for c := range s.clients {
+		select {
+		case c.events <- e:
+		default:
+			glog.Warningf("event client %s is too slow, disconnecting it", c.conn.RemoteAddr())
+			s.drop(c)
+		}
+	}
+}
+
+// Close sends the events which are pending to the clients, closes their connections, and stops the server
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	clients := []*client{}
+	for c := range s.clients {
+		clients = append(clients, c)
+		s.drop(c)
+	}
+	s.mu.Unlock()
+	for _, c := range clients {
+		select {
+		case <-c.done:
+		case <-time.After(2 * time.Second):
+			glog.Warningf("timed out sending events to %s", c.conn.RemoteAddr())
+			c.conn.Close()
+		}
+	}
+	return s.listener.Close()
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// drop stops sending events to a client: its writer sends the ones it has, and closes the connection. s.mu must be held.
+func (s *Server) drop(c *client) {
+	if s.clients[c] {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+// serveEvents upgrades a request to a WebSocket connection, and sends the previous and the new events on it
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") || key == "" {
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "expected a WebSocket upgrade", http.StatusUpgradeRequired)
+		return
+	}
+	if !allowedOrigin(r.Header.Get("Origin")) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection cannot be upgraded", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		glog.Warningf("hijack: %v", err)
+		return
+	}
+	if _, err := fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key)); err != nil {
+		conn.Close()
+		return
+	}
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+
+	c := &client{conn: conn, events: make(chan out.Event, clientBuffer+1), done: make(chan struct{})}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		c.close()
+		return
+	}
+	history := s.history
+	if len(history) > clientBuffer {
+		history = history[len(history)-clientBuffer:]
+	}
+	for _, e := range history {
+		c.events <- e
+	}
+	s.clients[c] = true
+	s.mu.Unlock()
+
+	go s.readFrames(c, rw.Reader)
+	c.writeEvents()
+}
+
+// writeEvents writes the events of a client as text frames, until it is dropped
+func (c *client) writeEvents() {
+	defer close(c.done)
+	for e := range c.events {
+		b, err := json.Marshal(e)
+		if err != nil {
+			glog.Errorf("marshal: %v", err)
+			continue
+		}
+		if err := c.writeFrame(opText, b); err != nil {
+			glog.Infof("event client %s: %v", c.conn.RemoteAddr(), err)
+			c.conn.Close()
+			// Wait for the reader to drop the client, so that Send never blocks on it
+			for range c.events {
+			}
+			return
+		}
+	}
+	c.close()
+}
+
+// close closes a connection with a close frame
+func (c *client) close() {
+	if err := c.writeFrame(opClose, nil); err != nil {
+		glog.Infof("close frame: %v", err)
+	}
+	c.conn.Close()
+}
+
+// readFrames reads the frames of a client, which are only pings and closes, and drops the client once it is closed
+func (s *Server) readFrames(c *client, r *bufio.Reader) {
+	defer func() {
+		s.mu.Lock()
+		s.drop(c)
+		s.mu.Unlock()
+	}()
+	for {
+		op, payload, err := readFrame(r)
+		if err != nil {
+			if err != io.EOF {
+				glog.Infof("event client %s: %v", c.conn.RemoteAddr(), err)
+			}
+			return
+		}
+		switch op {
+		case opClose:
+			return
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeFrame writes an unmasked frame, as servers do
+func (c *client) writeFrame(op byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	header := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		header = append(header, byte(n))
+	case n <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	if err := c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return err
+	}
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// maxPayload is the size of the largest frame which is read from clients, which have nothing to send but pings
+const maxPayload = 1 << 16
+
+// readFrame reads a frame, unmasking its payload
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return 0, nil, err
+	}
+	op := h[0] & 0x0F
+	masked := h[1]&0x80 != 0
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+	if n > maxPayload {
+		// Skip the payload, which is never needed
+		if _, err := io.CopyN(ioutil.Discard, r, int64(n)); err != nil {
+			return 0, nil, err
+		}
+		return op, nil, nil
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return op, payload, nil
+}
+
+// acceptKey returns the Sec-WebSocket-Accept header of the handshake for a Sec-WebSocket-Key
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains returns whether a comma-separated header contains a token, regardless of case
+func headerContains(h http.Header, name string, token string) bool {
+	for _, v := range h[http.CanonicalHeaderKey(name)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowedOrigin returns whether a client may connect from an origin. Web pages may only connect from localhost,
+// so that other sites which are open in a browser cannot read the events. IDEs send no origin, or one of their own scheme.
+func allowedOrigin(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return true
+	}
+	host := u.Hostname()
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+func TestAcceptKey(t *testing.T) {
+	// The example of RFC 6455
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("acceptKey() = %q, want %q", got, want)
+	}
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	var tests = []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "", want: true},
+		{origin: "vscode-webview://1234", want: true},
+		{origin: "http://localhost:3000", want: true},
+		{origin: "http://127.0.0.1", want: true},
+		{origin: "https://example.com", want: false},
+		{origin: "http://localhost.example.com", want: false},
+	}
+	for _, tc := range tests {
+		if got := allowedOrigin(tc.origin); got != tc.want {
+			t.Errorf("allowedOrigin(%q) = %v, want %v", tc.origin, got, tc.want)
+		}
+	}
+}
+
+// dial connects to the server, and returns the reader of the connection once the handshake is done
+func dial(t *testing.T, s *Server, origin string) (net.Conn, *bufio.Reader, int) {
+	conn, err := net.Dial("tcp", s.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	req := fmt.Sprintf("GET %s HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n", Path)
+	if origin != "" {
+		req += "Origin: " + origin + "\r\n"
+	}
+	if _, err := conn.Write([]byte(req + "\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols && resp.Header.Get("Sec-WebSocket-Accept") != acceptKey("dGhlIHNhbXBsZSBub25jZQ==") {
+		t.Errorf("Sec-WebSocket-Accept = %q", resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	return conn, r, resp.StatusCode
+}
+
+func readEvent(t *testing.T, conn net.Conn, r *bufio.Reader) out.Event {
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("deadline: %v", err)
+	}
+	op, payload, err := readFrame(r)
+	if err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	if op != opText {
+		t.Fatalf("opcode = %d, want %d", op, opText)
+	}
+	var e out.Event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		t.Fatalf("unmarshal %s: %v", payload, err)
+	}
+	return e
+}
+
+func TestServer(t *testing.T) {
+	s, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if !strings.HasPrefix(s.URL(), "ws://127.0.0.1:") || !strings.HasSuffix(s.URL(), Path) {
+		t.Errorf("URL() = %q", s.URL())
+	}
+
+	first := out.Event{Type: out.InfoEvent, Message: "Starting"}
+	second := out.Event{Type: out.ErrorEvent, Message: "Failed"}
+	s.Send(first)
+
+	conn, r, code := dial(t, s, "")
+	defer conn.Close()
+	if code != http.StatusSwitchingProtocols {
+		t.Fatalf("status = %d, want %d", code, http.StatusSwitchingProtocols)
+	}
+	// The event sent before the client connected is replayed
+	if got := readEvent(t, conn, r); got != first {
+		t.Errorf("first event = %v, want %v", got, first)
+	}
+	s.Send(second)
+	if got := readEvent(t, conn, r); got != second {
+		t.Errorf("second event = %v, want %v", got, second)
+	}
+
+	if _, _, code := dial(t, s, "https://example.com"); code != http.StatusForbidden {
+		t.Errorf("status for another origin = %d, want %d", code, http.StatusForbidden)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if op, _, err := readFrame(r); err != nil || op != opClose {
+		t.Errorf("after Close, read opcode %d (%v), want a close frame", op, err)
+	}
+}
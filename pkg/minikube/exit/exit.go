@@ -45,16 +45,32 @@ const (
 	MaxLogEntries = 3
 )
 
+// hooks run before the functions of this package exit, with the exit code and the untranslated message
+var hooks []func(code int, msg string)
+
+// AtExit registers a function to run before the functions of this package exit the process
+func AtExit(f func(code int, msg string)) {
+	hooks = append(hooks, f)
+}
+
+// exit runs the hooks, and exits with code
+func exit(code int, msg string) {
+	for _, h := range hooks {
+		h(code, msg)
+	}
+	os.Exit(code)
+}
+
 // UsageT outputs a templated usage error and exits with error code 64
 func UsageT(format string, a ...out.V) {
 	out.ErrT(out.Usage, format, a...)
-	os.Exit(BadUsage)
+	exit(BadUsage, format)
 }
 
 // WithCodeT outputs a templated fatal error message and exits with the supplied error code.
 func WithCodeT(code int, format string, a ...out.V) {
 	out.FatalT(format, a...)
-	os.Exit(code)
+	exit(code, format)
 }
 
 // WithError outputs an error and exits.
@@ -64,7 +80,7 @@ func WithError(msg string, err error) {
 		WithProblem(msg, p)
 	}
 	displayError(msg, err)
-	os.Exit(Software)
+	exit(Software, fmt.Sprintf("%s: %v", msg, err))
 }
 
 // WithProblem outputs info related to a known problem and exits.
@@ -75,7 +91,7 @@ func WithProblem(msg string, p *problem.Problem) {
 	out.ErrT(out.Empty, "")
 	out.ErrT(out.Sad, "If the above advice does not help, please let us know: ")
 	out.ErrT(out.URL, "https://github.com/kubernetes/minikube/issues/new/choose")
-	os.Exit(Config)
+	exit(Config, msg)
 }
 
 // WithLogEntries outputs an error along with any important log entries, and exits.
@@ -91,7 +107,7 @@ func WithLogEntries(msg string, err error, entries map[string][]string) {
 			out.T(out.LogEntry, l)
 		}
 	}
-	os.Exit(Software)
+	exit(Software, fmt.Sprintf("%s: %v", msg, err))
 }
 
 func displayError(msg string, err error) {
@@ -0,0 +1,124 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package localpv creates local persistent volumes, backed by a directory of a node
+package localpv
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/util"
+)
+
+// DefaultStorageClass is the storage class of the local volumes, which binds their claims once a pod uses them
+const DefaultStorageClass = "local-storage"
+
+// persistentDirs are the directories of the VM which are kept when it reboots
+var persistentDirs = []string{"/data", "/var/lib/minikube", "/var/lib/docker", "/tmp/hostpath_pv", "/tmp/hostpath-provisioner"}
+
+// invalidNameChars are the characters of a path which can not be in the name of a volume
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Name returns the name of the volume of a directory of a node, such as local-minikube-data-x for minikube:/data/x
+func Name(node string, dir string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(node+"/"+dir), "-"), "-")
+	name = "local-" + name
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// Persistent returns whether a directory of the VM is kept when it reboots
+func Persistent(dir string) bool {
+	dir = path.Clean(dir)
+	for _, p := range persistentDirs {
+		if dir == p || strings.HasPrefix(dir, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// PrepareCmd returns the command which creates the directory of a volume on its node, writable by the pods
+func PrepareCmd(dir string) string {
+	dir = util.ShellQuote(dir)
+	return fmt.Sprintf("sudo mkdir -p %s && sudo chmod 0777 %s", dir, dir)
+}
+
+// Volume returns a local persistent volume of size, backed by a directory of a node. Pods which use it are scheduled
+// on the node, and its data is kept when its claim is deleted.
+func Volume(name string, node string, dir string, size string, class string) (*core.PersistentVolume, error) {
+	if !path.IsAbs(dir) {
+		return nil, fmt.Errorf("%q is not an absolute path", dir)
+	}
+	capacity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid size %q", size)
+	}
+	mode := core.PersistentVolumeFilesystem
+	return &core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"kubernetes.io/minikube-local-pv": "true"},
+		},
+		Spec: core.PersistentVolumeSpec{
+			Capacity:                      core.ResourceList{core.ResourceStorage: capacity},
+			AccessModes:                   []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+			PersistentVolumeReclaimPolicy: core.PersistentVolumeReclaimRetain,
+			StorageClassName:              class,
+			VolumeMode:                    &mode,
+			PersistentVolumeSource: core.PersistentVolumeSource{
+				Local: &core.LocalVolumeSource{Path: path.Clean(dir)},
+			},
+			NodeAffinity: &core.VolumeNodeAffinity{
+				Required: &core.NodeSelector{
+					NodeSelectorTerms: []core.NodeSelectorTerm{{
+						MatchExpressions: []core.NodeSelectorRequirement{{
+							Key:      "kubernetes.io/hostname",
+							Operator: core.NodeSelectorOpIn,
+							Values:   []string{node},
+						}},
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// EnsureStorageClass creates the storage class of local volumes if it does not exist. Its claims are bound once a pod
+// uses them, so that the scheduler picks a volume on a node where the pod fits.
+func EnsureStorageClass(client kubernetes.Interface, class string) error {
+	if _, err := client.StorageV1().StorageClasses().Get(class, meta.GetOptions{}); err == nil {
+		return nil
+	}
+	binding := storage.VolumeBindingWaitForFirstConsumer
+	_, err := client.StorageV1().StorageClasses().Create(&storage.StorageClass{
+		ObjectMeta:        meta.ObjectMeta{Name: class},
+		Provisioner:       "kubernetes.io/no-provisioner",
+		VolumeBindingMode: &binding,
+	})
+	return err
+}
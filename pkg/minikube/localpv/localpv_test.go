@@ -0,0 +1,119 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localpv
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestName(t *testing.T) {
+	var tests = []struct {
+		node string
+		dir  string
+		want string
+	}{
+		{"minikube", "/data/x", "local-minikube-data-x"},
+		{"minikube", "/data/My_Volume/", "local-minikube-data-my-volume"},
+		{"dev", "/data/a-very-long-directory-name/which-is-nested/several-times/deep", "local-dev-data-a-very-long-directory-name-which-is-nested-sever"},
+	}
+	for _, tc := range tests {
+		got := Name(tc.node, tc.dir)
+		if got != tc.want {
+			t.Errorf("Name(%q, %q) = %q, want %q", tc.node, tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestPrepareCmd(t *testing.T) {
+	var tests = []struct {
+		dir  string
+		want string
+	}{
+		{"/data/x", "sudo mkdir -p '/data/x' && sudo chmod 0777 '/data/x'"},
+		{"/data/my volume;reboot", "sudo mkdir -p '/data/my volume;reboot' && sudo chmod 0777 '/data/my volume;reboot'"},
+	}
+	for _, tc := range tests {
+		if got := PrepareCmd(tc.dir); got != tc.want {
+			t.Errorf("PrepareCmd(%q) = %q, want %q", tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestPersistent(t *testing.T) {
+	var tests = []struct {
+		dir  string
+		want bool
+	}{
+		{"/data", true},
+		{"/data/x", true},
+		{"/var/lib/minikube/pv/", true},
+		{"/database", false},
+		{"/mnt/x", false},
+	}
+	for _, tc := range tests {
+		if got := Persistent(tc.dir); got != tc.want {
+			t.Errorf("Persistent(%q) = %v, want %v", tc.dir, got, tc.want)
+		}
+	}
+}
+
+func TestVolume(t *testing.T) {
+	pv, err := Volume("local-minikube-data-x", "minikube", "/data/x/", "10Gi", DefaultStorageClass)
+	if err != nil {
+		t.Fatalf("Volume: %v", err)
+	}
+	if got := pv.Spec.Capacity[core.ResourceStorage]; got.Cmp(resource.MustParse("10Gi")) != 0 {
+		t.Errorf("capacity = %s, want 10Gi", got.String())
+	}
+	if pv.Spec.Local == nil || pv.Spec.Local.Path != "/data/x" {
+		t.Errorf("local source = %+v, want /data/x", pv.Spec.Local)
+	}
+	if got := pv.Spec.NodeAffinity.Required.NodeSelectorTerms[0].MatchExpressions[0].Values; len(got) != 1 || got[0] != "minikube" {
+		t.Errorf("node affinity = %v, want [minikube]", got)
+	}
+	if pv.Spec.StorageClassName != DefaultStorageClass {
+		t.Errorf("storage class = %q, want %q", pv.Spec.StorageClassName, DefaultStorageClass)
+	}
+
+	if _, err := Volume("x", "minikube", "data/x", "10Gi", DefaultStorageClass); err == nil {
+		t.Errorf("Volume with a relative path: expected an error")
+	}
+	if _, err := Volume("x", "minikube", "/data/x", "10 gigs", DefaultStorageClass); err == nil {
+		t.Errorf("Volume with an invalid size: expected an error")
+	}
+}
+
+func TestEnsureStorageClass(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	for i := 0; i < 2; i++ {
+		if err := EnsureStorageClass(client, DefaultStorageClass); err != nil {
+			t.Fatalf("EnsureStorageClass: %v", err)
+		}
+	}
+	sc, err := client.StorageV1().StorageClasses().Get(DefaultStorageClass, meta.GetOptions{})
+	if err != nil {
+		t.Fatalf("get storage class: %v", err)
+	}
+	if sc.Provisioner != "kubernetes.io/no-provisioner" {
+		t.Errorf("provisioner = %q, want kubernetes.io/no-provisioner", sc.Provisioner)
+	}
+}
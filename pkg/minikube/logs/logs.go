@@ -25,6 +25,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
@@ -42,26 +44,138 @@ var ignoreCauseRe = regexp.MustCompile("error: no objects passed to apply")
 // importantPods are a list of pods to retrieve logs for, in addition to the bootstrapper logs.
 var importantPods = []string{
 	"kube-apiserver",
+	"etcd",
 	"coredns",
 	"kube-scheduler",
+	"kube-controller-manager",
 	"kube-proxy",
 	"kube-addon-manager",
 	"kubernetes-dashboard",
 	"storage-provisioner",
 }
 
+// AuditComponent is the name of the audit log of the apiserver. It is only shown when selected by name,
+// as it has an event for every request.
+const AuditComponent = "apiserver-audit"
+
 // lookbackwardsCount is how far back to look in a log for problems. This should be large enough to
 // include usage messages from a failed binary, but small enough to not include irrelevant problems.
 const lookBackwardsCount = 200
 
-// Follow follows logs from multiple files in tail(1) format
-func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner) error {
-	cs := []string{}
-	for _, v := range logCommands(r, bs, 0, true) {
-		cs = append(cs, v+" &")
+// Options select the logs which Output and Follow display
+type Options struct {
+	// Lines is the number of recent lines of each log
+	Lines int
+	// Since only includes the lines of this recent period, if set
+	Since time.Duration
+	// Components are the names of the logs to include, or all of them if empty
+	Components []string
+	// Node is the name of the node of the logs, as written in JSON
+	Node string
+	// AuditLog is the path of the audit log of the apiserver, if it is enabled
+	AuditLog string
+}
+
+// Section is the log of a single source, as written by Output and OutputProblems in JSON
+type Section struct {
+	Node  string   `json:"node,omitempty"`
+	Name  string   `json:"name"`
+	Lines []string `json:"lines"`
+}
+
+// Entry is a line of the log of a single source, as written by Follow in JSON
+type Entry struct {
+	Node string `json:"node,omitempty"`
+	Name string `json:"name"`
+	Line string `json:"line"`
+}
+
+// UnknownComponentError is returned when the components of Options do not match any log
+type UnknownComponentError struct {
+	Unknown   []string
+	Available []string
+}
+
+func (e *UnknownComponentError) Error() string {
+	return fmt.Sprintf("no logs for %s, the components are: %s", strings.Join(e.Unknown, ", "), strings.Join(e.Available, ", "))
+}
+
+// Follow follows the logs of multiple sources at once, prefixing each line with the name of its source
+func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner, o Options) error {
+	cmds := logCommands(r, bs, bootstrapper.LogOptions{Lines: o.Lines, Since: o.Since, Follow: true})
+	if o.AuditLog != "" {
+		cmds[AuditComponent] = auditLogCmd(o.AuditLog, o.Lines, true)
+	}
+	cmds, err := selectCommands(cmds, o.Components)
+	if err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := []string{}
+	for name, cmd := range cmds {
+		wg.Add(1)
+		go func(name string, cmd string) {
+			defer wg.Done()
+			w := &lineWriter{emit: func(line string) {
+				mu.Lock()
+				defer mu.Unlock()
+				writeEntry(Entry{Node: o.Node, Name: name, Line: line})
+			}}
+			err := runner.CombinedOutputTo(cmd, w)
+			w.Flush()
+			if err != nil {
+				glog.Errorf("failed %s: %s: %v", name, cmd, err)
+				mu.Lock()
+				failed = append(failed, name)
+				mu.Unlock()
+			}
+		}(name, cmd)
+	}
+	wg.Wait()
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("unable to follow logs for: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// writeEntry writes a followed log line, as JSON or prefixed with the name of its source
+func writeEntry(e Entry) {
+	if out.IsJSON() {
+		if err := out.JSON(e); err != nil {
+			glog.Errorf("failed to write log entry: %v", err)
+		}
+		return
+	}
+	fmt.Fprintf(os.Stdout, "[%s] %s\n", e.Name, e.Line)
+}
+
+// lineWriter calls emit for each complete line written to it
+type lineWriter struct {
+	buf  bytes.Buffer
+	emit func(string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf.Next(i + 1)
+		w.emit(strings.TrimRight(string(line), "\r\n"))
+	}
+}
+
+// Flush emits the last line, if it was not terminated by a newline
+func (w *lineWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.emit(strings.TrimRight(w.buf.String(), "\r"))
+		w.buf.Reset()
 	}
-	cs = append(cs, "wait")
-	return runner.CombinedOutputTo(strings.Join(cs, " "), os.Stdout)
 }
 
 // IsProblem returns whether this line matches a known problem
@@ -72,7 +186,7 @@ func IsProblem(line string) bool {
 // FindProblems finds possible root causes among the logs
 func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner) map[string][]string {
 	pMap := map[string][]string{}
-	cmds := logCommands(r, bs, lookBackwardsCount, false)
+	cmds := logCommands(r, bs, bootstrapper.LogOptions{Lines: lookBackwardsCount})
 	for name, cmd := range cmds {
 		glog.Infof("Gathering logs for %s ...", name)
 		var b bytes.Buffer
@@ -99,21 +213,42 @@ func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner comma
 
 // OutputProblems outputs discovered problems.
 func OutputProblems(problems map[string][]string, maxLines int) {
+	sections := []Section{}
 	for name, lines := range problems {
-		out.T(out.FailureType, "Problems detected in {{.name}}:", out.V{"name": name})
 		if len(lines) > maxLines {
 			lines = lines[len(lines)-maxLines:]
 		}
+		if out.IsJSON() {
+			sections = append(sections, Section{Name: name, Lines: lines})
+			continue
+		}
+		out.T(out.FailureType, "Problems detected in {{.name}}:", out.V{"name": name})
 		for _, l := range lines {
 			out.T(out.LogEntry, l)
 		}
 	}
+	if out.IsJSON() {
+		sort.Slice(sections, func(i, j int) bool { return sections[i].Name < sections[j].Name })
+		if err := out.JSON(sections); err != nil {
+			glog.Errorf("failed to write problems: %v", err)
+		}
+	}
 }
 
+// kernelCmd shows the kernel and the distribution of the VM
+const kernelCmd = "uptime && uname -a && grep PRETTY /etc/os-release"
+
 // Output displays logs from multiple sources in tail(1) format
-func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner, lines int) error {
-	cmds := logCommands(r, bs, lines, false)
-	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
+func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner, o Options) error {
+	cmds := logCommands(r, bs, bootstrapper.LogOptions{Lines: o.Lines, Since: o.Since})
+	cmds["kernel"] = kernelCmd
+	if o.AuditLog != "" {
+		cmds[AuditComponent] = auditLogCmd(o.AuditLog, o.Lines, false)
+	}
+	cmds, err := selectCommands(cmds, o.Components)
+	if err != nil {
+		return err
+	}
 
 	names := []string{}
 	for k := range cmds {
@@ -122,11 +257,14 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Run
 
 	sort.Strings(names)
 	failed := []string{}
+	sections := []Section{}
 	for i, name := range names {
-		if i > 0 {
+		if i > 0 && !out.IsJSON() {
 			out.T(out.Empty, "")
 		}
-		out.T(out.Empty, "==> {{.name}} <==", out.V{"name": name})
+		if !out.IsJSON() {
+			out.T(out.Empty, "==> {{.name}} <==", out.V{"name": name})
+		}
 		var b bytes.Buffer
 
 		err := runner.CombinedOutputTo(cmds[name], &b)
@@ -135,21 +273,54 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Run
 			failed = append(failed, name)
 			continue
 		}
+		section := Section{Node: o.Node, Name: name, Lines: []string{}}
 		scanner := bufio.NewScanner(&b)
 		for scanner.Scan() {
+			if out.IsJSON() {
+				section.Lines = append(section.Lines, scanner.Text())
+				continue
+			}
 			out.T(out.Empty, scanner.Text())
 		}
+		sections = append(sections, section)
 	}
 
+	if out.IsJSON() {
+		if err := out.JSON(sections); err != nil {
+			return err
+		}
+	}
 	if len(failed) > 0 {
 		return fmt.Errorf("unable to fetch logs for: %s", strings.Join(failed, ", "))
 	}
 	return nil
 }
 
+// Collect returns the logs of the sources which Output displays, by name. The sources which failed are named in the error.
+func Collect(r cruntime.Manager, bs bootstrapper.Bootstrapper, runner command.Runner, lines int) (map[string][]byte, error) {
+	cmds := logCommands(r, bs, bootstrapper.LogOptions{Lines: lines})
+	cmds["kernel"] = kernelCmd
+
+	logs := map[string][]byte{}
+	failed := []string{}
+	for name, cmd := range cmds {
+		var b bytes.Buffer
+		if err := runner.CombinedOutputTo(cmd, &b); err != nil {
+			glog.Errorf("failed: %v", err)
+			failed = append(failed, name)
+		}
+		logs[name] = b.Bytes()
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return logs, fmt.Errorf("unable to fetch logs for: %s", strings.Join(failed, ", "))
+	}
+	return logs, nil
+}
+
 // logCommands returns a list of commands that would be run to receive the anticipated logs
-func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, length int, follow bool) map[string]string {
-	cmds := bs.LogCommands(bootstrapper.LogOptions{Lines: length, Follow: follow})
+func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, o bootstrapper.LogOptions) map[string]string {
+	cmds := bs.LogCommands(o)
 	for _, pod := range importantPods {
 		ids, err := r.ListContainers(pod)
 		if err != nil {
@@ -161,11 +332,63 @@ func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, length int, f
 			glog.Warningf("No container was found matching %q", pod)
 			continue
 		}
-		cmds[pod] = r.ContainerLogCmd(ids[0], length, follow)
+		cmds[pod] = r.ContainerLogCmd(ids[0], o.Lines, o.Since, o.Follow)
 	}
-	cmds[r.Name()] = r.SystemLogCmd(length)
+	cmds[r.Name()] = r.SystemLogCmd(o.Lines, o.Since, o.Follow)
 	// Works across container runtimes with good formatting
 	// Fallback to 'docker ps' if it fails (none driver)
 	cmds["container status"] = "sudo crictl ps -a || sudo docker ps -a"
 	return cmds
 }
+
+// auditLogCmd returns the command which displays the audit log of the apiserver, in tail(1) format
+func auditLogCmd(path string, lines int, follow bool) string {
+	cmd := fmt.Sprintf("sudo tail -n %d", lines)
+	if follow {
+		cmd += " -F"
+	}
+	return fmt.Sprintf("%s %s", cmd, path)
+}
+
+// selectCommands returns the commands of the logs named by components, or all of them but the audit log if there
+// are none. Components match the names of the logs regardless of case, and with or without their "kube-" prefix.
+func selectCommands(cmds map[string]string, components []string) (map[string]string, error) {
+	if len(components) == 0 {
+		all := map[string]string{}
+		for name, cmd := range cmds {
+			if name != AuditComponent {
+				all[name] = cmd
+			}
+		}
+		return all, nil
+	}
+	selected := map[string]string{}
+	unknown := []string{}
+	for _, c := range components {
+		found := false
+		for name, cmd := range cmds {
+			if matchComponent(name, c) {
+				selected[name] = cmd
+				found = true
+			}
+		}
+		if !found {
+			unknown = append(unknown, c)
+		}
+	}
+	if len(unknown) > 0 {
+		available := []string{}
+		for name := range cmds {
+			available = append(available, name)
+		}
+		sort.Strings(available)
+		return nil, &UnknownComponentError{Unknown: unknown, Available: available}
+	}
+	return selected, nil
+}
+
+// matchComponent returns whether the component c names the log name
+func matchComponent(name string, c string) bool {
+	c = strings.TrimSpace(c)
+	return strings.EqualFold(name, c) || strings.EqualFold(name, "kube-"+c) || strings.EqualFold(strings.Replace(name, " ", "-", -1), c)
+}
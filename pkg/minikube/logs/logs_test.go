@@ -17,7 +17,11 @@ limitations under the License.
 package logs
 
 import (
+	"fmt"
+	"sort"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestIsProblem(t *testing.T) {
@@ -45,3 +49,67 @@ func TestIsProblem(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectCommands(t *testing.T) {
+	cmds := map[string]string{
+		"kubelet":          "journalctl -u kubelet",
+		"kube-apiserver":   "docker logs abc0",
+		"Docker":           "sudo journalctl -u docker",
+		"container status": "sudo crictl ps -a",
+		AuditComponent:     "sudo tail -n 30 /var/lib/minikube/audit/audit.log",
+	}
+	var tests = []struct {
+		name       string
+		components []string
+		want       []string
+		unknown    []string
+	}{
+		{"all", nil, []string{"Docker", "container status", "kube-apiserver", "kubelet"}, nil},
+		{"short names", []string{"apiserver", "kubelet"}, []string{"kube-apiserver", "kubelet"}, nil},
+		{"case and spaces", []string{"docker", "container-status"}, []string{"Docker", "container status"}, nil},
+		{"unknown", []string{"kubelet", "etcd"}, nil, []string{"etcd"}},
+		{"apiserver", []string{"apiserver"}, []string{"kube-apiserver"}, nil},
+		{"audit", []string{"apiserver-audit"}, []string{"apiserver-audit"}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := selectCommands(cmds, tc.components)
+			if tc.unknown != nil {
+				e, ok := err.(*UnknownComponentError)
+				if !ok {
+					t.Fatalf("selectCommands() error = %v, want an UnknownComponentError", err)
+				}
+				if diff := cmp.Diff(tc.unknown, e.Unknown); diff != "" {
+					t.Errorf("unknown components mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectCommands(): %v", err)
+			}
+			names := []string{}
+			for name := range got {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if diff := cmp.Diff(tc.want, names); diff != "" {
+				t.Errorf("selected logs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLineWriter(t *testing.T) {
+	lines := []string{}
+	w := &lineWriter{emit: func(l string) { lines = append(lines, l) }}
+	for _, p := range []string{"fir", "st\r\nsec", "ond\n\nthi", "rd"} {
+		if _, err := fmt.Fprint(w, p); err != nil {
+			t.Fatalf("Write(%q): %v", p, err)
+		}
+	}
+	w.Flush()
+	want := []string{"first", "second", "", "third"}
+	if diff := cmp.Diff(want, lines); diff != "" {
+		t.Errorf("lines mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+)
+
+// buildRoot is where build contexts are unpacked in the VM
+const buildRoot = "/var/lib/minikube/build"
+
+// BuildImage builds an image inside the VM from a build context directory on the host
+func BuildImage(cr command.Runner, k8s config.KubernetesConfig, src string, opts cruntime.BuildOptions) error {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+
+	tmp, err := ioutil.TempFile("", "minikube-build")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeTarball(tmp, src); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("build.%d", time.Now().UnixNano())
+	f, err := assets.NewFileAsset(tmp.Name(), buildRoot, name+".tar", "0644")
+	if err != nil {
+		return errors.Wrap(err, "creating copyable file asset")
+	}
+	if err := cr.Copy(f); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+
+	dir := path.Join(buildRoot, name)
+	tarball := dir + ".tar"
+	defer func() {
+		if err := cr.Run(fmt.Sprintf("sudo rm -rf %s %s", dir, tarball)); err != nil {
+			glog.Warningf("failed to remove build context: %v", err)
+		}
+	}()
+	if err := cr.Run(fmt.Sprintf("sudo mkdir -p %s && sudo tar -C %s -xf %s", dir, dir, tarball)); err != nil {
+		return errors.Wrap(err, "extracting build context")
+	}
+
+	glog.Infof("Building %s from %s with %s", opts.Tag, src, r.Name())
+	if err := r.BuildImage(dir, opts); err != nil {
+		return errors.Wrapf(err, "%s build %s", r.Name(), src)
+	}
+	return nil
+}
+
+// writeTarball writes the contents of the directory src to w as a tar archive
+func writeTarball(w io.Writer, src string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() && !strings.HasSuffix(hdr.Name, "/") {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
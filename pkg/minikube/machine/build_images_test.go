@@ -0,0 +1,83 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTarball(t *testing.T) {
+	src, err := ioutil.TempDir("", "writetarball")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(src)
+
+	if err := os.MkdirAll(filepath.Join(src, "app"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"Dockerfile":  "FROM scratch\nCOPY app /app\n",
+		"app/main.go": "package main\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeTarball(&buf, src); err != nil {
+		t.Fatalf("writeTarball: %v", err)
+	}
+
+	got := map[string]string{}
+	dirs := 0
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading tarball: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			dirs++
+			continue
+		}
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[hdr.Name] = string(b)
+	}
+	if dirs != 1 {
+		t.Errorf("got %d directories, want 1", dirs)
+	}
+	for name, content := range files {
+		if got[name] != content {
+			t.Errorf("%s = %q, want %q", name, got[name], content)
+		}
+	}
+}
@@ -23,13 +23,13 @@ import (
 	"runtime"
 
 	"github.com/golang/glog"
-	"github.com/jimmidyson/go-download"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -56,6 +56,12 @@ func CacheBinary(binary, version, osName, archName string) (string, error) {
 	targetFilepath := path.Join(targetDir, binary)
 
 	url := constants.GetKubernetesReleaseURL(binary, version, osName, archName)
+	switch binary {
+	case "k3s":
+		url = constants.GetK3sReleaseURL(version, archName)
+	case "k0s":
+		url = constants.GetK0sReleaseURL(version, archName)
+	}
 
 	_, err := os.Stat(targetFilepath)
 	// If it exists, do no verification and continue
@@ -71,12 +77,19 @@ func CacheBinary(binary, version, osName, archName string) (string, error) {
 		return "", errors.Wrapf(err, "mkdir %s", targetDir)
 	}
 
-	options := download.FileOptions{
-		Mkdirs: download.MkdirAll,
-	}
-
+	options := download.Options{Mirrors: download.Mirrors()}
 	options.Checksum = constants.GetKubernetesReleaseURLSHA1(binary, version, osName, archName)
-	options.ChecksumHash = crypto.SHA1
+	options.Hash = crypto.SHA1
+	switch binary {
+	case "k3s":
+		// k3s publishes a sha256sum file per architecture rather than a checksum per binary
+		options.Checksum = constants.GetK3sReleaseURLSHA256(version, archName)
+		options.Hash = crypto.SHA256
+	case "k0s":
+		// k0s publishes a single sha256sums file, listing the binaries by their release name
+		options.Checksum = constants.GetK0sReleaseURLSHA256(version)
+		options.Hash = crypto.SHA256
+	}
 
 	out.T(out.FileDownload, "Downloading {{.name}} {{.version}}", out.V{"name": binary, "version": version})
 	if err := download.ToFile(url, targetFilepath, options); err != nil {
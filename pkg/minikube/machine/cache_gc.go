@@ -0,0 +1,163 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/blang/semver"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// apiserverImage matches the cached kube-apiserver images, whose tag is the Kubernetes version
+var apiserverImage = regexp.MustCompile(`^kube-apiserver(-[a-z0-9]+)?_(v[0-9.]+.*)$`)
+
+// CacheFile is a file or directory of the cache, with the size of its content
+type CacheFile struct {
+	Path string
+	Size int64
+}
+
+// UnusedCache returns the binaries and images of the cache in dir which are cached for the Kubernetes versions
+// that none of the given configs use. The images in keep are kept, as well as the images which are not cached for Kubernetes.
+func UnusedCache(dir string, configs []config.KubernetesConfig, keep []string) ([]CacheFile, error) {
+	imagesDir := filepath.Join(dir, "images")
+	used := map[string]bool{}
+	repos := map[string]bool{"": true}
+	kept := map[string]bool{}
+	for _, k := range configs {
+		used[k.KubernetesVersion] = true
+		repos[k.ImageRepository] = true
+		_, images := constants.GetKubeadmCachedImages(k.ImageRepository, k.KubernetesVersion)
+		keep = append(keep, images...)
+	}
+	for _, image := range keep {
+		kept[sanitizeCacheDir(filepath.Join(imagesDir, image))] = true
+	}
+
+	versions, err := cachedVersions(dir)
+	if err != nil {
+		return nil, err
+	}
+	unused := []CacheFile{}
+	candidates := map[string]bool{}
+	for _, v := range versions {
+		if used[v] {
+			continue
+		}
+		binDir := filepath.Join(dir, v)
+		if _, err := os.Stat(binDir); err == nil {
+			size, err := diskUsage(binDir)
+			if err != nil {
+				return nil, err
+			}
+			unused = append(unused, CacheFile{Path: binDir, Size: size})
+		}
+		for r := range repos {
+			_, images := constants.GetKubeadmCachedImages(r, v)
+			for _, image := range images {
+				candidates[sanitizeCacheDir(filepath.Join(imagesDir, image))] = true
+			}
+		}
+	}
+	for p := range candidates {
+		if kept[p] {
+			continue
+		}
+		fi, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		unused = append(unused, CacheFile{Path: p, Size: fi.Size()})
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].Path < unused[j].Path })
+	return unused, nil
+}
+
+// cachedVersions returns the Kubernetes versions which have binaries or a kube-apiserver image in the cache
+func cachedVersions(dir string) ([]string, error) {
+	found := map[string]bool{}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "cache")
+	}
+	for _, e := range entries {
+		if e.IsDir() && isVersion(e.Name()) {
+			found[e.Name()] = true
+		}
+	}
+	err = filepath.Walk(filepath.Join(dir, "images"), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if m := apiserverImage.FindStringSubmatch(info.Name()); m != nil && !info.IsDir() && isVersion(m[2]) {
+			found[m[2]] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "images")
+	}
+	versions := []string{}
+	for v := range found {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+// isVersion returns whether a name is a Kubernetes version, such as v1.15.2
+func isVersion(name string) bool {
+	if len(name) < 2 || name[0] != 'v' {
+		return false
+	}
+	_, err := semver.Parse(name[1:])
+	return err == nil
+}
+
+// diskUsage returns the total size of the files in a directory
+func diskUsage(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// RemoveCache deletes files of the cache, and the image directories which are left empty
+func RemoveCache(files []CacheFile) error {
+	for _, f := range files {
+		glog.Infof("Deleting %s", f.Path)
+		if err := os.RemoveAll(f.Path); err != nil {
+			return errors.Wrapf(err, "removing %s", f.Path)
+		}
+	}
+	return cleanImageCacheDir()
+}
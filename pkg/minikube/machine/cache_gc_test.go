@@ -0,0 +1,72 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestUnusedCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cachegc")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"v1.14.0/kubeadm":                          "1234",
+		"v1.14.0/kubelet":                          "56",
+		"v1.15.2/kubeadm":                          "1234",
+		"iso/minikube-v1.3.0.iso":                  "iso",
+		"images/k8s.gcr.io/kube-apiserver_v1.14.0": "api",
+		"images/k8s.gcr.io/kube-proxy_v1.14.0":     "proxy",
+		"images/k8s.gcr.io/kube-apiserver_v1.13.0": "old",
+		"images/k8s.gcr.io/kube-apiserver_v1.15.2": "api",
+		"images/k8s.gcr.io/pause_3.1":              "pause",
+		"images/k8s.gcr.io/kube-proxy_v1.13.0":     "kept",
+		"images/example.com/app_v1":                "app",
+	}
+	for name, contents := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	configs := []config.KubernetesConfig{{KubernetesVersion: "v1.15.2"}}
+	got, err := UnusedCache(dir, configs, []string{"k8s.gcr.io/kube-proxy:v1.13.0"})
+	if err != nil {
+		t.Fatalf("UnusedCache: %v", err)
+	}
+	want := []CacheFile{
+		{Path: filepath.Join(dir, "images", "k8s.gcr.io", "kube-apiserver_v1.13.0"), Size: 3},
+		{Path: filepath.Join(dir, "images", "k8s.gcr.io", "kube-apiserver_v1.14.0"), Size: 3},
+		{Path: filepath.Join(dir, "images", "k8s.gcr.io", "kube-proxy_v1.14.0"), Size: 5},
+		{Path: filepath.Join(dir, "v1.14.0"), Size: 6},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UnusedCache() returned diff (-want +got):\n%s", diff)
+	}
+}
@@ -133,6 +133,11 @@ func CacheAndLoadImages(images []string) error {
 	return LoadImages(runner, images, constants.ImageCacheDir)
 }
 
+// ImageCachePath returns the path of an image in the local image cache
+func ImageCachePath(image string) string {
+	return sanitizeCacheDir(filepath.Join(constants.ImageCacheDir, image))
+}
+
 // # ParseReference cannot have a : in the directory path
 func sanitizeCacheDir(image string) string {
 	if runtime.GOOS == "windows" && hasWindowsDriveLetter(image) {
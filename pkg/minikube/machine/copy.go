@@ -0,0 +1,168 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/util"
+)
+
+// globChars make a path a pattern
+const globChars = "*?["
+
+// unsafePatternChars cannot be part of a guest pattern, which is expanded by the shell of the guest
+const unsafePatternChars = " \t\n;&|$`'\"\\<>(){}!#~"
+
+// CopyToGuest copies the files and directories of the host matching the pattern src to dst in the guest.
+// As with cp, if dst is a directory or ends with "/", the matches are copied into it, and otherwise dst
+// is the path of the single match. It returns the paths of the files copied to the guest.
+func CopyToGuest(cr command.Runner, src string, dst string) ([]string, error) {
+	if !path.IsAbs(dst) {
+		return nil, fmt.Errorf("the path in minikube must be absolute: %s", dst)
+	}
+	matches, err := filepath.Glob(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pattern %s", src)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s: no such file or directory", src)
+	}
+	into := len(matches) > 1 || strings.HasSuffix(dst, "/") || cr.Run(fmt.Sprintf("sudo test -d %s", util.ShellQuote(dst))) == nil
+
+	copied := []string{}
+	for _, m := range matches {
+		target := dst
+		if into {
+			target = path.Join(dst, filepath.Base(m))
+		}
+		err := filepath.Walk(m, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				if !info.IsDir() {
+					glog.Infof("Skipping %s: not a regular file", p)
+				}
+				return nil
+			}
+			rel, err := filepath.Rel(m, p)
+			if err != nil {
+				return err
+			}
+			t := path.Join(target, filepath.ToSlash(rel))
+			f, err := assets.NewFileAsset(p, path.Dir(t), path.Base(t), fmt.Sprintf("%04o", info.Mode().Perm()))
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := cr.Copy(f); err != nil {
+				return errors.Wrapf(err, "copying %s", p)
+			}
+			copied = append(copied, t)
+			return nil
+		})
+		if err != nil {
+			return copied, err
+		}
+	}
+	return copied, nil
+}
+
+// CopyFromGuest copies the files and directories of the guest matching the pattern src to dst on the host,
+// with the same rules as CopyToGuest. It returns the paths of the files copied to the host.
+func CopyFromGuest(cr command.Runner, src string, dst string) ([]string, error) {
+	if !path.IsAbs(src) {
+		return nil, fmt.Errorf("the path in minikube must be absolute: %s", src)
+	}
+	matches, err := guestGlob(cr, src)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(dst)
+	into := len(matches) > 1 || strings.HasSuffix(dst, "/") || strings.HasSuffix(dst, string(filepath.Separator)) || (err == nil && info.IsDir())
+
+	copied := []string{}
+	for _, m := range matches {
+		target := dst
+		if into {
+			target = filepath.Join(dst, path.Base(m))
+		}
+		out, err := cr.CombinedOutput(fmt.Sprintf("sudo find %s -type f", util.ShellQuote(m)))
+		if err != nil {
+			return copied, errors.Wrapf(err, "listing %s: %s", m, out)
+		}
+		for _, f := range strings.Split(strings.TrimSpace(out), "\n") {
+			if f == "" {
+				continue
+			}
+			rel := strings.TrimPrefix(strings.TrimPrefix(f, m), "/")
+			t := filepath.Join(target, filepath.FromSlash(rel))
+			if err := copyGuestFile(cr, f, t); err != nil {
+				return copied, err
+			}
+			copied = append(copied, t)
+		}
+	}
+	return copied, nil
+}
+
+// guestGlob returns the paths of the guest matching pattern, which is expanded by the shell of the guest
+func guestGlob(cr command.Runner, pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, globChars) {
+		return []string{path.Clean(pattern)}, nil
+	}
+	if strings.ContainsAny(pattern, unsafePatternChars) {
+		return nil, fmt.Errorf("unsupported characters in the pattern %q: only *, ? and [] are supported", pattern)
+	}
+	out, err := cr.CombinedOutput(fmt.Sprintf("sudo sh -c %s", util.ShellQuote("ls -d "+pattern)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: no such file or directory", pattern)
+	}
+	matches := []string{}
+	for _, m := range strings.Split(strings.TrimSpace(out), "\n") {
+		if m != "" {
+			matches = append(matches, path.Clean(m))
+		}
+	}
+	return matches, nil
+}
+
+// copyGuestFile copies the file src of the guest to dst on the host
+func copyGuestFile(cr command.Runner, src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "mkdir %s", filepath.Dir(dst))
+	}
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrapf(err, "creating %s", dst)
+	}
+	if err := cr.CombinedOutputTo(fmt.Sprintf("sudo cat %s", util.ShellQuote(src)), f); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return errors.Wrapf(err, "copying %s", src)
+	}
+	return f.Close()
+}
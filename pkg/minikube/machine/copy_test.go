@@ -0,0 +1,132 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestCopyToGuest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for name, contents := range map[string]string{"app/a.conf": "a", "app/sub/b.conf": "b", "c.log": "c", "d.log": "d"} {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	var tests = []struct {
+		description string
+		src         string
+		dst         string
+		want        []string
+		wantErr     bool
+	}{
+		{"file", "c.log", "/tmp/c.txt", []string{"/tmp/c.txt"}, false},
+		{"file into directory", "c.log", "/tmp/", []string{"/tmp/c.log"}, false},
+		{"directory", "app", "/etc/app", []string{"/etc/app/a.conf", "/etc/app/sub/b.conf"}, false},
+		{"directory into directory", "app", "/etc/", []string{"/etc/app/a.conf", "/etc/app/sub/b.conf"}, false},
+		{"pattern", "*.log", "/var/log", []string{"/var/log/c.log", "/var/log/d.log"}, false},
+		{"no match", "*.txt", "/tmp", nil, true},
+		{"relative target", "c.log", "tmp", nil, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cr := command.NewFakeCommandRunner()
+			got, err := CopyToGuest(cr, filepath.Join(dir, tc.src), tc.dst)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("CopyToGuest() error = %v, wantErr: %v", err, tc.wantErr)
+			}
+			sort.Strings(got)
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CopyToGuest() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCopyFromGuest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "copy")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cr := command.NewFakeCommandRunner()
+	cr.SetCommandToOutput(map[string]string{
+		"sudo sh -c 'ls -d /etc/a*'":     "/etc/app\n",
+		"sudo find '/etc/app' -type f":   "/etc/app/a.conf\n/etc/app/sub/b.conf\n",
+		"sudo find '/etc/hosts' -type f": "/etc/hosts\n",
+		"sudo cat '/etc/app/a.conf'":     "a",
+		"sudo cat '/etc/app/sub/b.conf'": "b",
+		"sudo cat '/etc/hosts'":          "127.0.0.1 localhost",
+	})
+
+	var tests = []struct {
+		description string
+		src         string
+		dst         string
+		want        map[string]string
+	}{
+		{"file", "/etc/hosts", "hosts.txt", map[string]string{"hosts.txt": "127.0.0.1 localhost"}},
+		{"directory", "/etc/app", "conf", map[string]string{"conf/a.conf": "a", "conf/sub/b.conf": "b"}},
+		{"pattern into directory", "/etc/a*", "backup/", map[string]string{"backup/app/a.conf": "a", "backup/app/sub/b.conf": "b"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			dst := filepath.Join(dir, tc.dst)
+			if strings.HasSuffix(tc.dst, "/") {
+				dst += string(filepath.Separator)
+			}
+			got, err := CopyFromGuest(cr, tc.src, dst)
+			if err != nil {
+				t.Fatalf("CopyFromGuest: %v", err)
+			}
+			if len(got) != len(tc.want) {
+				t.Errorf("CopyFromGuest() = %v, want %d files", got, len(tc.want))
+			}
+			for name, want := range tc.want {
+				b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+				if err != nil {
+					t.Fatalf("ReadFile: %v", err)
+				}
+				if string(b) != want {
+					t.Errorf("%s = %q, want %q", name, b, want)
+				}
+			}
+		})
+	}
+
+	if _, err := CopyFromGuest(cr, "/etc/$(reboot)*", dir); err == nil {
+		t.Errorf("CopyFromGuest() of an unsafe pattern = nil, want error")
+	}
+}
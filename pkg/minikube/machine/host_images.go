@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// hostDocker runs the docker client of the host, and returns its output
+var hostDocker = func(args ...string) (string, error) {
+	b, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(b)))
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// HostImageID returns the ID of an image of the docker daemon of the host
+func HostImageID(image string) (string, error) {
+	return hostDocker("image", "inspect", "--format", "{{.Id}}", image)
+}
+
+// LoadHostImage saves an image of the docker daemon of the host, and loads it into the container runtime of the cluster
+func LoadHostImage(cr command.Runner, k8s config.KubernetesConfig, image string) error {
+	dir, err := ioutil.TempDir("", "minikube-image")
+	if err != nil {
+		return errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(dir)
+	tarball := filepath.Join(dir, strings.NewReplacer("/", "_", ":", "_").Replace(image)+".tar")
+	if _, err := hostDocker("save", "-o", tarball, image); err != nil {
+		return err
+	}
+	return loadImageFromCache(cr, k8s, tarball)
+}
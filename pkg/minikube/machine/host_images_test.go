@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestLoadHostImage(t *testing.T) {
+	orig := hostDocker
+	defer func() { hostDocker = orig }()
+	var tarball string
+	hostDocker = func(args ...string) (string, error) {
+		if len(args) != 4 || args[0] != "save" || args[1] != "-o" || args[3] != "example.com/my-app:dev" {
+			t.Fatalf("unexpected docker command: %v", args)
+		}
+		tarball = args[2]
+		return "", ioutil.WriteFile(tarball, []byte("image"), 0644)
+	}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"docker load -i /tmp/example.com_my-app_dev.tar": "",
+		"sudo rm -rf /tmp/example.com_my-app_dev.tar":    "",
+	})
+	if err := LoadHostImage(f, config.KubernetesConfig{ContainerRuntime: "docker"}, "example.com/my-app:dev"); err != nil {
+		t.Fatalf("LoadHostImage: %v", err)
+	}
+	if filepath.Base(tarball) != "example.com_my-app_dev.tar" {
+		t.Errorf("saved the image to %s, want a file named example.com_my-app_dev.tar", tarball)
+	}
+	got, err := f.GetFileToContents(tarball)
+	if err != nil {
+		t.Fatalf("GetFileToContents: %v", err)
+	}
+	if got != "image" {
+		t.Errorf("copied %q, want %q", got, "image")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/download"
+	"k8s.io/minikube/pkg/minikube/preload"
+)
+
+// SaveImages saves images of the container runtime of the cluster to archives in dir, and returns their paths, indexed by image
+func SaveImages(cr command.Runner, k8s config.KubernetesConfig, images []string, dir string) (map[string]string, error) {
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return nil, errors.Wrap(err, "runtime")
+	}
+	files := map[string]string{}
+	for _, image := range images {
+		name := path.Base(preload.FileName(image))
+		src := path.Join(tempLoadDir, "preload-"+name)
+		dst := filepath.Join(dir, name)
+		if err := r.SaveImage(image, src); err != nil {
+			return files, errors.Wrapf(err, "%s save %s", r.Name(), image)
+		}
+		if err := copyGuestFile(cr, src, dst); err != nil {
+			return files, err
+		}
+		if err := cr.Run("sudo rm -f " + src); err != nil {
+			return files, errors.Wrap(err, "deleting temp image archive")
+		}
+		files[image] = dst
+	}
+	return files, nil
+}
+
+// CachePreload downloads the preload at url into the cache, unless it is already cached, and returns its path
+func CachePreload(url string) (string, error) {
+	name := path.Base(url)
+	dst := constants.MakeMiniPath("cache", "preloads", name)
+	if _, err := os.Stat(dst); err == nil {
+		glog.Infof("Not caching preload, using %s", dst)
+		return dst, nil
+	}
+	if err := download.ToFile(url, dst, download.Options{Mirrors: download.Mirrors(), Name: name}); err != nil {
+		return "", errors.Wrapf(err, "downloading %s", url)
+	}
+	return dst, nil
+}
+
+// LoadPreload loads the images of the preload src into the container runtime of the cluster, and returns its manifest
+func LoadPreload(cr command.Runner, k8s config.KubernetesConfig, src string) (preload.Manifest, error) {
+	dir, err := ioutil.TempDir("", "minikube-preload")
+	if err != nil {
+		return preload.Manifest{}, errors.Wrap(err, "temp dir")
+	}
+	defer os.RemoveAll(dir)
+	m, files, err := preload.Extract(src, dir)
+	if err != nil {
+		return m, err
+	}
+	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
+	if err != nil {
+		return m, errors.Wrap(err, "runtime")
+	}
+	if m.ContainerRuntime != "" && m.ContainerRuntime != r.Name() {
+		return m, fmt.Errorf("%s was saved from %s, and can't be loaded into %s", src, m.ContainerRuntime, r.Name())
+	}
+
+	var g errgroup.Group
+	for image, file := range files {
+		image, file := image, file
+		g.Go(func() error {
+			if err := loadImageFromCache(cr, k8s, file); err != nil {
+				return errors.Wrapf(err, "loading image %s", image)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return m, errors.Wrap(err, "loading preload")
+	}
+	glog.Infof("Loaded %d images from %s", len(files), src)
+	return m, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/preload"
+)
+
+func TestSaveAndLoadPreload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "preload")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	k8s := config.KubernetesConfig{ContainerRuntime: "containerd"}
+
+	f := command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo ctr -n=k8s.io images export /tmp/preload-example.com_base_dev.tar example.com/base:dev": "",
+		"sudo cat '/tmp/preload-example.com_base_dev.tar'":                                            "image",
+		"sudo rm -f /tmp/preload-example.com_base_dev.tar":                                            "",
+	})
+	files, err := SaveImages(f, k8s, []string{"example.com/base:dev"}, dir)
+	if err != nil {
+		t.Fatalf("SaveImages: %v", err)
+	}
+	archive := filepath.Join(dir, "preload.tar.gz")
+	if err := preload.Create(archive, preload.Manifest{KubernetesVersion: "v1.15.2", ContainerRuntime: "containerd"}, files); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := LoadPreload(command.NewFakeCommandRunner(), config.KubernetesConfig{ContainerRuntime: "docker"}, archive); err == nil {
+		t.Errorf("LoadPreload() into docker succeeded, want an error for a containerd preload")
+	}
+
+	f = command.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo ctr -n=k8s.io images import --all-platforms /tmp/example.com_base_dev.tar": "",
+		"sudo rm -rf /tmp/example.com_base_dev.tar":                                      "",
+	})
+	m, err := LoadPreload(f, k8s, archive)
+	if err != nil {
+		t.Fatalf("LoadPreload: %v", err)
+	}
+	if len(m.Images) != 1 || m.Images[0].Name != "example.com/base:dev" {
+		t.Errorf("LoadPreload() images = %v, want example.com/base:dev", m.Images)
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nerdctl forwards the containerd and BuildKit sockets of the node to the host, for nerdctl to use them,
+// from a process which runs in the background
+package nerdctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+const (
+	fileName = "nerdctl.json"
+	// Namespace is the containerd namespace of the images and containers of Kubernetes
+	Namespace = "k8s.io"
+	// ContainerdSocket is the name of the forwarded containerd socket
+	ContainerdSocket = "containerd.sock"
+	// BuildkitSocket is the name of the forwarded BuildKit socket
+	BuildkitSocket = "buildkitd.sock"
+)
+
+// State is the process which forwards the sockets of the node of a profile
+type State struct {
+	Profile string
+	// Sockets are the paths of the node the sockets of the host are forwarded to, by name
+	Sockets map[string]string
+	// Pid is the process listening on the sockets of the host
+	Pid int `json:",omitempty"`
+}
+
+// Path returns the path of the nerdctl file of a profile
+func Path(profile string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), fileName)
+}
+
+// LogFilePath returns the path of the log file of the process forwarding the sockets of a profile
+func LogFilePath(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), "nerdctl.log")
+}
+
+// SocketPath returns the path of a forwarded socket of a profile, on the host
+func SocketPath(profile string, name string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), "nerdctl", name)
+}
+
+// Load returns the nerdctl state of a profile, or nil if its sockets are not forwarded
+func Load(profile string, miniHome ...string) (*State, error) {
+	b, err := ioutil.ReadFile(Path(profile, miniHome...))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile, miniHome...))
+	}
+	return &s, nil
+}
+
+// Save writes the nerdctl state of a profile
+func Save(s *State, miniHome ...string) error {
+	if err := os.MkdirAll(constants.GetProfilePath(s.Profile, miniHome...), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(s.Profile, miniHome...), b, 0600)
+}
+
+// Running returns whether the process of the state is running
+func (s *State) Running() bool {
+	return daemon.Running(s.Pid)
+}
+
+// Start runs the process forwarding the sockets of a profile in the background, and waits for it to listen
+func Start(s *State) error {
+	// The process is recorded before it starts, as it looks its sockets up
+	if err := Save(s); err != nil {
+		return err
+	}
+	pid, err := daemon.Start(LogFilePath(s.Profile), "nerdctl-env", "run", "--profile", s.Profile)
+	if err != nil {
+		return err
+	}
+	s.Pid = pid
+	if err := Save(s); err != nil {
+		return err
+	}
+	for i := 0; i < 50; i++ {
+		if _, err := os.Stat(SocketPath(s.Profile, ContainerdSocket)); err == nil {
+			return nil
+		}
+		if !s.Running() {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("the sockets are not forwarded, see %s", LogFilePath(s.Profile))
+}
+
+// Stop stops the process forwarding the sockets of a profile, if there is one, and removes its state
+func Stop(profile string) error {
+	s, err := Load(profile)
+	if err != nil || s == nil {
+		return err
+	}
+	if err := daemon.Kill(s.Pid); err != nil {
+		glog.Infof("killing nerdctl process %d: %v", s.Pid, err)
+	}
+	if err := os.RemoveAll(filepath.Dir(SocketPath(profile, ContainerdSocket))); err != nil {
+		return err
+	}
+	if err := os.Remove(Path(profile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Listen listens on the forwarded socket of a profile, which only the user can connect to
+func Listen(profile string, name string) (net.Listener, error) {
+	path := SocketPath(profile, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// A socket left by a killed process refuses new listeners
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return net.Listen("unix", path)
+}
+
+// sessionConn is a connection to a socket of the node, through socat in an ssh session
+type sessionConn struct {
+	io.Reader
+	io.WriteCloser
+	sess *ssh.Session
+	addr socketAddr
+}
+
+// socketAddr is the address of a socket of the node
+type socketAddr string
+
+func (a socketAddr) Network() string { return "unix" }
+func (a socketAddr) String() string  { return string(a) }
+
+// Close closes the session, which stops socat
+func (c *sessionConn) Close() error {
+	if err := c.WriteCloser.Close(); err != nil {
+		glog.Infof("closing stdin: %v", err)
+	}
+	return c.sess.Close()
+}
+
+func (c *sessionConn) LocalAddr() net.Addr                { return c.addr }
+func (c *sessionConn) RemoteAddr() net.Addr               { return c.addr }
+func (c *sessionConn) SetDeadline(t time.Time) error      { return nil }
+func (c *sessionConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *sessionConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// DialSocket connects to a socket of the node over client. The socket is connected to as root, since the sockets of
+// containerd and BuildKit are only accessible to root.
+func DialSocket(client *ssh.Client, path string) (net.Conn, error) {
+	sess, err := client.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "ssh session")
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		sess.Close()
+		return nil, errors.Wrap(err, "stdin")
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		sess.Close()
+		return nil, errors.Wrap(err, "stdout")
+	}
+	if err := sess.Start(fmt.Sprintf("sudo socat STDIO UNIX-CONNECT:%s", path)); err != nil {
+		sess.Close()
+		return nil, errors.Wrap(err, "socat")
+	}
+	return &sessionConn{Reader: stdout, WriteCloser: stdin, sess: sess, addr: socketAddr(path)}, nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nerdctl
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestSaveLoadStop(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	if s, err := Load("p1"); err != nil || s != nil {
+		t.Fatalf("Load() = %v, %v, want nil", s, err)
+	}
+	// This process stands for the process forwarding the sockets, which Stop does not kill
+	want := &State{Profile: "p1", Sockets: map[string]string{ContainerdSocket: "/run/containerd/containerd.sock"}, Pid: os.Getpid()}
+	if err := Save(want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := Load("p1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+	if !got.Running() {
+		t.Errorf("Running() = false for the pid of this process")
+	}
+
+	ln, err := Listen("p1", ContainerdSocket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	ln.Close()
+	if err := Stop("p1"); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if s, err := Load("p1"); err != nil || s != nil {
+		t.Errorf("Load() after Stop = %v, %v, want nil", s, err)
+	}
+	if _, err := os.Stat(SocketPath("p1", ContainerdSocket)); !os.IsNotExist(err) {
+		t.Errorf("the socket was not removed by Stop: %v", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	ln, err := Listen("p1", BuildkitSocket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+	// The socket file of a killed process is left behind
+	again, err := Listen("p1", BuildkitSocket)
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	again.Close()
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package netcheck diagnoses the network of a running cluster, one layer at a time
+package netcheck
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// Namespace holds the pods and service of the checks, and is deleted afterwards
+	Namespace = "minikube-netcheck"
+	// serverName is the name of the pod and service which checks connect to
+	serverName = "netcheck-server"
+	serverPort = 8080
+	// response is what the server and the host answer, so that checks tell them apart from other services
+	response = "minikube-netcheck"
+	image    = "busybox:1.31"
+	// pollInterval is how often the phase of the client pod is checked
+	pollInterval = time.Second
+)
+
+// Layer is the part of the network which a failed check points to
+type Layer string
+
+const (
+	// CNI is the network plugin which connects pods
+	CNI Layer = "CNI"
+	// KubeProxy implements services on the node
+	KubeProxy Layer = "kube-proxy"
+	// DNS is the cluster DNS server
+	DNS Layer = "DNS"
+	// Host is the network between the host and the VM: firewalls, VPNs and the driver network
+	Host Layer = "host network"
+)
+
+// Result is the outcome of a check
+type Result struct {
+	Name string
+	// Layer is what is broken if the check failed
+	Layer   Layer
+	Message string
+	Err     error
+}
+
+// Config describes the cluster to check
+type Config struct {
+	// NodeIP is the address of the node
+	NodeIP string
+	// HostIP is the address of the host, as seen from the node
+	HostIP string
+	// DNSDomain is the cluster domain
+	DNSDomain string
+	// Timeout bounds each check
+	Timeout time.Duration
+}
+
+// Checker runs the checks against a cluster
+type Checker struct {
+	client   kubernetes.Interface
+	cfg      Config
+	serverIP string
+	service  *core.Service
+}
+
+// NewChecker returns a checker of the cluster client connects to
+func NewChecker(client kubernetes.Interface, cfg Config) *Checker {
+	return &Checker{client: client, cfg: cfg}
+}
+
+// Setup creates the server pod and its service, and waits for the pod to run
+func (c *Checker) Setup() error {
+	ns := &core.Namespace{ObjectMeta: meta.ObjectMeta{Name: Namespace}}
+	if _, err := c.client.CoreV1().Namespaces().Create(ns); err != nil {
+		return errors.Wrapf(err, "creating namespace %s", Namespace)
+	}
+	server := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{Name: serverName, Labels: map[string]string{"app": serverName}},
+		Spec: core.PodSpec{
+			Containers: []core.Container{{
+				Name:    "server",
+				Image:   image,
+				Command: []string{"sh", "-c", fmt.Sprintf("mkdir -p /www && echo %s > /www/index.html && httpd -f -p %d -h /www", response, serverPort)},
+				Ports:   []core.ContainerPort{{ContainerPort: serverPort}},
+			}},
+		},
+	}
+	if _, err := c.client.CoreV1().Pods(Namespace).Create(server); err != nil {
+		return errors.Wrap(err, "creating server pod")
+	}
+	svc := &core.Service{
+		ObjectMeta: meta.ObjectMeta{Name: serverName},
+		Spec: core.ServiceSpec{
+			Type:     core.ServiceTypeNodePort,
+			Selector: map[string]string{"app": serverName},
+			Ports:    []core.ServicePort{{Port: 80, TargetPort: intstr.FromInt(serverPort)}},
+		},
+	}
+	svc, err := c.client.CoreV1().Services(Namespace).Create(svc)
+	if err != nil {
+		return errors.Wrap(err, "creating server service")
+	}
+	c.service = svc
+
+	selector := labels.SelectorFromSet(labels.Set(map[string]string{"app": serverName}))
+	if err := util.WaitForPodsWithLabelRunning(c.client, Namespace, selector); err != nil {
+		return errors.Wrap(err, "waiting for server pod")
+	}
+	pod, err := c.client.CoreV1().Pods(Namespace).Get(serverName, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting server pod")
+	}
+	c.serverIP = pod.Status.PodIP
+	return nil
+}
+
+// Cleanup deletes the namespace of the checks
+func (c *Checker) Cleanup() error {
+	return c.client.CoreV1().Namespaces().Delete(Namespace, &meta.DeleteOptions{})
+}
+
+// Run runs the checks, from the innermost layer of the network to the outermost
+func (c *Checker) Run() []Result {
+	svcName := fmt.Sprintf("%s.%s.svc.%s", serverName, Namespace, c.cfg.DNSDomain)
+	results := []Result{
+		c.check("Pod to pod", CNI, fmt.Sprintf("%s:%d", c.serverIP, serverPort), func() error {
+			return c.fromPod(wgetCmd(c.serverIP, serverPort))
+		}),
+		c.check("Pod to service", KubeProxy, fmt.Sprintf("%s:80", c.service.Spec.ClusterIP), func() error {
+			return c.fromPod(wgetCmd(c.service.Spec.ClusterIP, 80))
+		}),
+		c.check("DNS", DNS, svcName, func() error {
+			return c.fromPod(fmt.Sprintf("nslookup %s && %s", svcName, wgetCmd(svcName, 80)))
+		}),
+	}
+
+	hostResult := c.check("Pod to host", Host, c.cfg.HostIP, c.toHost)
+	if c.cfg.HostIP == "" {
+		hostResult = Result{Name: "Pod to host", Layer: Host, Message: "skipped, the address of the host is unknown"}
+	}
+	return append(results,
+		hostResult,
+		c.check("Host to NodePort", Host, fmt.Sprintf("%s:%d", c.cfg.NodeIP, c.nodePort()), func() error {
+			return get(fmt.Sprintf("http://%s:%d", c.cfg.NodeIP, c.nodePort()), c.cfg.Timeout)
+		}),
+	)
+}
+
+// check runs f and reports its outcome
+func (c *Checker) check(name string, layer Layer, target string, f func() error) Result {
+	glog.Infof("Running check %q against %s", name, target)
+	err := f()
+	glog.Infof("Check %q: %v", name, err)
+	return Result{Name: name, Layer: layer, Message: target, Err: err}
+}
+
+// nodePort returns the node port of the server service
+func (c *Checker) nodePort() int32 {
+	if len(c.service.Spec.Ports) == 0 {
+		return 0
+	}
+	return c.service.Spec.Ports[0].NodePort
+}
+
+// toHost checks that pods reach a server which listens on the host
+func (c *Checker) toHost() error {
+	// Drivers with NAT networks forward the address of the host to its loopback interface, so listen on all of them
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return errors.Wrap(err, "listening on the host")
+	}
+	defer l.Close()
+	go func() {
+		err := http.Serve(l, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, response)
+		}))
+		glog.Infof("host server: %v", err)
+	}()
+	return c.fromPod(wgetCmd(c.cfg.HostIP, l.Addr().(*net.TCPAddr).Port))
+}
+
+// wgetCmd returns the command which checks that the netcheck server answers at host:port
+func wgetCmd(host string, port int) string {
+	return fmt.Sprintf("wget -T 5 -qO- http://%s | grep -q %s", net.JoinHostPort(host, fmt.Sprint(port)), response)
+}
+
+// fromPod runs cmd in a new pod, and returns its output as an error if it fails
+func (c *Checker) fromPod(cmd string) error {
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{GenerateName: "netcheck-client-"},
+		Spec: core.PodSpec{
+			RestartPolicy: core.RestartPolicyNever,
+			Containers: []core.Container{{
+				Name:    "client",
+				Image:   image,
+				Command: []string{"sh", "-c", cmd},
+			}},
+		},
+	}
+	pod, err := c.client.CoreV1().Pods(Namespace).Create(pod)
+	if err != nil {
+		return errors.Wrap(err, "creating client pod")
+	}
+	defer func() {
+		if err := c.client.CoreV1().Pods(Namespace).Delete(pod.Name, &meta.DeleteOptions{}); err != nil {
+			glog.Warningf("deleting %s: %v", pod.Name, err)
+		}
+	}()
+
+	var phase core.PodPhase
+	err = wait.PollImmediate(pollInterval, c.cfg.Timeout, func() (bool, error) {
+		p, err := c.client.CoreV1().Pods(Namespace).Get(pod.Name, meta.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		phase = p.Status.Phase
+		return phase == core.PodSucceeded || phase == core.PodFailed, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the client pod did not complete in %s, it is %s", c.cfg.Timeout, phase)
+	}
+	if phase == core.PodSucceeded {
+		return nil
+	}
+	logs, err := c.client.CoreV1().Pods(Namespace).GetLogs(pod.Name, &core.PodLogOptions{}).Do().Raw()
+	if err != nil {
+		return errors.Wrap(err, "getting client pod logs")
+	}
+	if msg := strings.TrimSpace(string(logs)); msg != "" {
+		return errors.New(msg)
+	}
+	return errors.New("no answer")
+}
+
+// get checks that the netcheck server answers at url
+func get(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout, Transport: &http.Transport{Proxy: nil}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(body), response) {
+		return fmt.Errorf("unexpected answer: %s", resp.Status)
+	}
+	return nil
+}
+
+// Broken returns the innermost layer with a failed check, as failures of the outer layers may follow from it
+func Broken(results []Result) (Layer, bool) {
+	for _, r := range results {
+		if r.Err != nil {
+			return r.Layer, true
+		}
+	}
+	return "", false
+}
+
+// layerHints tell where to look when a layer is broken
+var layerHints = map[Layer]string{
+	CNI:       "Pods can't reach each other: check the network plugin pods with 'kubectl get pods -n kube-system', and the --network-plugin and --extra-config=kubeadm.pod-network-cidr flags of minikube start",
+	KubeProxy: "Pods reach each other, but not through services: check the kube-proxy pod with 'kubectl logs -n kube-system -l k8s-app=kube-proxy'",
+	DNS:       "Services work, but not their names: check the DNS pods with 'kubectl logs -n kube-system -l k8s-app=kube-dns'",
+	Host:      "The cluster works, but the host and the VM can't reach each other: check the firewall of the host",
+}
+
+// driverHints tell where to look when the host network is broken, for each driver
+var driverHints = map[string]string{
+	constants.DriverKvm2:       "firewalld may block the libvirt network: check 'sudo firewall-cmd --zone=libvirt --list-all'",
+	constants.DriverVirtualbox: "the host-only network of VirtualBox may be missing or down: check 'VBoxManage list hostonlyifs'",
+	constants.DriverHyperkit:   "VPN clients often take over 192.168.64.0/24, the network of hyperkit: disconnect the VPN and retry",
+	constants.DriverHyperv:     "the virtual switch must be external or have the host sharing its connection: check it in the Hyper-V Manager",
+	constants.DriverQemu2:      "with the user network, the host is only reachable at 10.0.2.2, and NodePorts need port forwarding: use --qemu-network=socket_vmnet",
+	constants.DriverNone:       "the iptables rules of the host may drop the traffic of pods: check 'sudo iptables -L FORWARD'",
+}
+
+// Hints return the advice for a broken layer, for the driver of the cluster
+func Hints(layer Layer, driver string) []string {
+	hints := []string{layerHints[layer]}
+	if h, ok := driverHints[driver]; ok && layer == Host {
+		hints = append(hints, h)
+	}
+	return hints
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netcheck
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBroken(t *testing.T) {
+	failed := errors.New("wget: download timed out")
+	tests := []struct {
+		name    string
+		results []Result
+		want    Layer
+		broken  bool
+	}{
+		{"all ok", []Result{{Layer: CNI}, {Layer: KubeProxy}, {Layer: DNS}, {Layer: Host}}, "", false},
+		{"services", []Result{{Layer: CNI}, {Layer: KubeProxy, Err: failed}, {Layer: DNS, Err: failed}, {Layer: Host}}, KubeProxy, true},
+		{"everything", []Result{{Layer: CNI, Err: failed}, {Layer: KubeProxy, Err: failed}, {Layer: DNS, Err: failed}, {Layer: Host, Err: failed}}, CNI, true},
+		{"host", []Result{{Layer: CNI}, {Layer: KubeProxy}, {Layer: DNS}, {Layer: Host, Err: failed}}, Host, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, broken := Broken(tc.results)
+			if got != tc.want || broken != tc.broken {
+				t.Errorf("Broken() = %q, %v, want %q, %v", got, broken, tc.want, tc.broken)
+			}
+		})
+	}
+}
+
+func TestHints(t *testing.T) {
+	if got := Hints(DNS, "kvm2"); !reflect.DeepEqual(got, []string{layerHints[DNS]}) {
+		t.Errorf("Hints(DNS, kvm2) = %v, want the DNS hint only", got)
+	}
+	if got := Hints(Host, "kvm2"); !reflect.DeepEqual(got, []string{layerHints[Host], driverHints["kvm2"]}) {
+		t.Errorf("Hints(Host, kvm2) = %v, want the host and kvm2 hints", got)
+	}
+	for _, l := range []Layer{CNI, KubeProxy, DNS, Host} {
+		if layerHints[l] == "" {
+			t.Errorf("no hint for layer %q", l)
+		}
+	}
+}
+
+func TestWgetCmd(t *testing.T) {
+	want := "wget -T 5 -qO- http://10.96.0.10:80 | grep -q minikube-netcheck"
+	if got := wgetCmd("10.96.0.10", 80); got != want {
+		t.Errorf("wgetCmd() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package out
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/golang/glog"
+	"k8s.io/minikube/pkg/minikube/translate"
+)
+
+const (
+	// TextOutput is the default, human readable output format
+	TextOutput = "text"
+	// JSONOutput is the machine readable output format
+	JSONOutput = "json"
+)
+
+// Event types
+const (
+	InfoEvent    = "info"
+	WarningEvent = "warning"
+	ErrorEvent   = "error"
+)
+
+// jsonOutput is whether messages are written as JSON events. Set using SetOutputFormat()
+var jsonOutput = false
+
+// Event is written on a line of its own for each message, when the output format is JSON
+type Event struct {
+	// Type is one of InfoEvent, WarningEvent or ErrorEvent
+	Type string `json:"type"`
+	// Message is the translated message, without style prefix
+	Message string `json:"message"`
+}
+
+// listeners receive each message as an event, whatever the output format
+var listeners []func(Event)
+
+// AddListener registers a function which receives each message as an event, whatever the output format.
+// It is called by the goroutine which writes the message, so it should not block.
+func AddListener(f func(Event)) {
+	listeners = append(listeners, f)
+}
+
+// notify sends the event of a message to the listeners
+func notify(style StyleEnum, format string, a ...V) {
+	if len(listeners) == 0 {
+		return
+	}
+	e := event(style, format, a...)
+	if e.Message == "" {
+		return
+	}
+	for _, f := range listeners {
+		f(e)
+	}
+}
+
+// SetOutputFormat configures whether messages are written as text or as JSON events
+func SetOutputFormat(format string) error {
+	switch format {
+	case TextOutput:
+		jsonOutput = false
+	case JSONOutput:
+		jsonOutput = true
+	default:
+		return fmt.Errorf("invalid output format %q, expected %q or %q", format, TextOutput, JSONOutput)
+	}
+	return nil
+}
+
+// IsJSON returns whether the output format is JSON
+func IsJSON() bool {
+	return jsonOutput
+}
+
+// JSON writes v to stdout as JSON, on a single line
+func JSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	writeLine(outFile, b)
+	return nil
+}
+
+// event returns the JSON event for a templated message
+func event(style StyleEnum, format string, a ...V) Event {
+	e := Event{Type: InfoEvent, Message: applyTemplate(format, a...)}
+	switch style {
+	case WarningType:
+		e.Type = WarningEvent
+	case FailureType, FatalType:
+		e.Type = ErrorEvent
+	}
+	return e
+}
+
+func writeEvent(w fdWriter, e Event) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		glog.Errorf("Marshal failed: %v", err)
+		return
+	}
+	writeLine(w, b)
+}
+
+func writeLine(w fdWriter, b []byte) {
+	if w == nil {
+		glog.Warningf("[unset writer]: %s", b)
+		return
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		glog.Errorf("Write failed: %v", err)
+	}
+}
+
+// applyTemplate translates and templates a message, without any style
+func applyTemplate(format string, a ...V) string {
+	format = translate.T(format)
+	if a == nil {
+		a = []V{{}}
+	}
+	var buf bytes.Buffer
+	t, err := template.New(format).Parse(format)
+	if err != nil {
+		glog.Errorf("unable to parse %q: %v - returning raw string.", format, err)
+		return format
+	}
+	if err := t.Execute(&buf, a[0]); err != nil {
+		glog.Errorf("unable to execute %s: %v - returning raw string.", format, err)
+		return format
+	}
+	return strings.TrimSpace(buf.String())
+}
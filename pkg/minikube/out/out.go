@@ -38,6 +38,9 @@ import (
 // out.SetErrFile(os.Stderr)
 // out.Fatal("Oh no, everything failed.")
 
+// With out.SetOutputFormat(out.JSONOutput), T and ErrT write each message as a JSON Event line instead,
+// and commands write their results with out.JSON.
+
 // NOTE: If you do not want colorized output, set MINIKUBE_IN_STYLE=false in your environment.
 
 var (
@@ -62,6 +65,13 @@ type V map[string]interface{}
 
 // T writes a stylized and templated message to stdout
 func T(style StyleEnum, format string, a ...V) {
+	notify(style, format, a...)
+	if jsonOutput {
+		if e := event(style, format, a...); e.Message != "" {
+			writeEvent(outFile, e)
+		}
+		return
+	}
 	outStyled := applyTemplateFormatting(style, useColor, format, a...)
 	String(outStyled)
 }
@@ -85,6 +95,13 @@ func Ln(format string, a ...interface{}) {
 
 // ErrT writes a stylized and templated error message to stderr
 func ErrT(style StyleEnum, format string, a ...V) {
+	notify(style, format, a...)
+	if jsonOutput {
+		if e := event(style, format, a...); e.Message != "" {
+			writeEvent(errFile, e)
+		}
+		return
+	}
 	errStyled := applyTemplateFormatting(style, useColor, format, a...)
 	Err(errStyled)
 }
@@ -115,3 +115,63 @@ func TestErr(t *testing.T) {
 		t.Errorf("Err() = %q, want %q", got, want)
 	}
 }
+
+func TestJSONOutput(t *testing.T) {
+	translate.Translations = map[string]interface{}{}
+	if err := SetOutputFormat("yaml"); err == nil {
+		t.Errorf("SetOutputFormat(yaml) = nil, want error")
+	}
+	if err := SetOutputFormat(JSONOutput); err != nil {
+		t.Fatalf("SetOutputFormat: %v", err)
+	}
+	defer func() {
+		if err := SetOutputFormat(TextOutput); err != nil {
+			t.Errorf("SetOutputFormat: %v", err)
+		}
+	}()
+
+	f := tests.NewFakeFile()
+	SetOutFile(f)
+	ef := tests.NewFakeFile()
+	SetErrFile(ef)
+	T(Running, "Installing Kubernetes version {{.version}} ...", V{"version": "v1.13"})
+	T(Empty, "")
+	WarningT("Warning: {{.error}}", V{"error": "100%"})
+	FatalT("Fatal")
+	if err := JSON(struct {
+		Host string `json:"host"`
+	}{"Running"}); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+
+	want := `{"type":"info","message":"Installing Kubernetes version v1.13 ..."}
+{"host":"Running"}
+`
+	if got := f.String(); got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	want = `{"type":"warning","message":"Warning: 100%"}
+{"type":"error","message":"Fatal"}
+`
+	if got := ef.String(); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestAddListener(t *testing.T) {
+	translate.Translations = map[string]interface{}{}
+	defer func() { listeners = nil }()
+	got := []Event{}
+	AddListener(func(e Event) { got = append(got, e) })
+
+	SetOutFile(tests.NewFakeFile())
+	SetErrFile(tests.NewFakeFile())
+	T(Running, "Installing Kubernetes version {{.version}} ...", V{"version": "v1.13"})
+	T(Empty, "")
+	ErrT(FailureType, "Failed")
+
+	want := []Event{{Type: InfoEvent, Message: "Installing Kubernetes version v1.13 ..."}, {Type: ErrorEvent, Message: "Failed"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package perf measures how long minikube takes to start and stop clusters, and compares the timings with a baseline
+package perf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Phases are what an iteration of a benchmark measures, in order: creating a cluster, stopping it,
+// starting it again from its existing VM, and deleting it
+var Phases = []string{"start", "stop", "restart", "delete"}
+
+// Timings are the durations of a phase for a driver and container runtime, in seconds
+type Timings struct {
+	Driver  string    `json:"driver"`
+	Runtime string    `json:"runtime"`
+	Phase   string    `json:"phase"`
+	Seconds []float64 `json:"seconds"`
+}
+
+// Percentile returns the pth percentile of the timings, interpolated between the closest ones
+func (t Timings) Percentile(p float64) float64 {
+	if len(t.Seconds) == 0 {
+		return 0
+	}
+	s := append([]float64{}, t.Seconds...)
+	sort.Float64s(s)
+	rank := p / 100 * float64(len(s)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return s[lo] + (s[hi]-s[lo])*(rank-float64(lo))
+}
+
+// Report is the timings of a benchmark, as written to baseline files
+type Report struct {
+	MinikubeVersion string    `json:"minikubeVersion"`
+	Timings         []Timings `json:"timings"`
+}
+
+// find returns the timings of a phase, or nil
+func (r *Report) find(driver string, runtime string, phase string) *Timings {
+	for i, t := range r.Timings {
+		if t.Driver == driver && t.Runtime == runtime && t.Phase == phase {
+			return &r.Timings[i]
+		}
+	}
+	return nil
+}
+
+// Add adds how long a phase took
+func (r *Report) Add(driver string, runtime string, phase string, d time.Duration) {
+	t := r.find(driver, runtime, phase)
+	if t == nil {
+		r.Timings = append(r.Timings, Timings{Driver: driver, Runtime: runtime, Phase: phase})
+		t = &r.Timings[len(r.Timings)-1]
+	}
+	t.Seconds = append(t.Seconds, d.Seconds())
+}
+
+// ReadReport reads a report written by WriteReport
+func ReadReport(path string) (*Report, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	return r, nil
+}
+
+// WriteReport writes a report, so that later benchmarks can be compared with it
+func WriteReport(path string, r *Report) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// Delta compares the median and the 95th percentile of a phase with a baseline.
+// The baseline percentiles are 0 when the baseline has no timings of the phase.
+type Delta struct {
+	Driver     string  `json:"driver"`
+	Runtime    string  `json:"runtime"`
+	Phase      string  `json:"phase"`
+	P50        float64 `json:"p50"`
+	P95        float64 `json:"p95"`
+	BaseP50    float64 `json:"baselineP50,omitempty"`
+	BaseP95    float64 `json:"baselineP95,omitempty"`
+	Iterations int     `json:"iterations"`
+}
+
+// Change returns the difference of a percentile with its baseline, in percent, or an empty string without a baseline
+func Change(value float64, base float64) string {
+	if base == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.1f%%", (value-base)/base*100)
+}
+
+// Compare returns the percentiles of the timings of a report, with those of the baseline if it is not nil
+func Compare(r *Report, baseline *Report) []Delta {
+	var ds []Delta
+	for _, t := range r.Timings {
+		d := Delta{
+			Driver:     t.Driver,
+			Runtime:    t.Runtime,
+			Phase:      t.Phase,
+			P50:        t.Percentile(50),
+			P95:        t.Percentile(95),
+			Iterations: len(t.Seconds),
+		}
+		if baseline != nil {
+			if b := baseline.find(t.Driver, t.Runtime, t.Phase); b != nil {
+				d.BaseP50 = b.Percentile(50)
+				d.BaseP95 = b.Percentile(95)
+			}
+		}
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// Run runs a minikube binary with args, and returns how long it took. Its output is written to log.
+func Run(minikube string, args []string, log io.Writer) (time.Duration, error) {
+	fmt.Fprintf(log, "$ minikube %s\n", strings.Join(args, " "))
+	c := exec.Command(minikube, args...)
+	c.Stdout = log
+	c.Stderr = log
+	start := time.Now()
+	err := c.Run()
+	return time.Since(start), err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package perf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	var tests = []struct {
+		seconds []float64
+		p       float64
+		want    float64
+	}{
+		{nil, 50, 0},
+		{[]float64{30}, 95, 30},
+		{[]float64{40, 10, 30, 20}, 50, 25},
+		{[]float64{40, 10, 30, 20}, 100, 40},
+		{[]float64{10, 20, 30, 40, 50}, 95, 48},
+	}
+	for _, tc := range tests {
+		if got := (Timings{Seconds: tc.seconds}).Percentile(tc.p); got != tc.want {
+			t.Errorf("Percentile(%v, %v) = %v, want %v", tc.seconds, tc.p, got, tc.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	r := &Report{}
+	r.Add("kvm2", "docker", "start", 40*time.Second)
+	r.Add("kvm2", "docker", "start", 60*time.Second)
+	r.Add("kvm2", "docker", "stop", 5*time.Second)
+	base := &Report{Timings: []Timings{{Driver: "kvm2", Runtime: "docker", Phase: "start", Seconds: []float64{40}}}}
+
+	want := []Delta{
+		{Driver: "kvm2", Runtime: "docker", Phase: "start", P50: 50, P95: 59, BaseP50: 40, BaseP95: 40, Iterations: 2},
+		{Driver: "kvm2", Runtime: "docker", Phase: "stop", P50: 5, P95: 5, Iterations: 1},
+	}
+	if got := Compare(r, base); !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() = %+v, want %+v", got, want)
+	}
+	if got := Change(50, 40); got != "+25.0%" {
+		t.Errorf("Change(50, 40) = %q, want +25.0%%", got)
+	}
+	if got := Change(50, 0); got != "" {
+		t.Errorf("Change(50, 0) = %q, want an empty string", got)
+	}
+}
+
+func TestReadWriteReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "perf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "baseline.json")
+	r := &Report{MinikubeVersion: "v1.3.0"}
+	r.Add("none", "containerd", "restart", 1500*time.Millisecond)
+	if err := WriteReport(path, r); err != nil {
+		t.Fatalf("WriteReport: %v", err)
+	}
+	got, err := ReadReport(path)
+	if err != nil {
+		t.Fatalf("ReadReport: %v", err)
+	}
+	if !reflect.DeepEqual(got, r) {
+		t.Errorf("ReadReport() = %+v, want %+v", got, r)
+	}
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadReport(path); err == nil {
+		t.Error("ReadReport() = nil, want an error for invalid JSON")
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package portforward forwards ports of the host to services of the cluster, from processes which run in the background
+package portforward
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+const fileName = "port-forwards.json"
+
+// Forward is a port of localhost forwarded to a port of a service
+type Forward struct {
+	HostPort  int
+	Namespace string
+	Service   string
+	Port      int
+	// Target is the address the node connects to, the cluster IP of the service and its port
+	Target string
+	// Pid is the process listening on the port of the host
+	Pid int `json:",omitempty"`
+}
+
+// Path returns the path of the port forwards file of a profile
+func Path(profile string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), fileName)
+}
+
+// LogFilePath returns the path of the log file of the process forwarding a port of the host
+func LogFilePath(profile string, hostPort int) string {
+	return filepath.Join(constants.GetProfilePath(profile), fmt.Sprintf("port-forward-%d.log", hostPort))
+}
+
+// List returns the port forwards of a profile, ordered by port of the host
+func List(profile string, miniHome ...string) ([]Forward, error) {
+	b, err := ioutil.ReadFile(Path(profile, miniHome...))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var fs []Forward
+	if err := json.Unmarshal(b, &fs); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile, miniHome...))
+	}
+	sort.Slice(fs, func(i, j int) bool { return fs[i].HostPort < fs[j].HostPort })
+	return fs, nil
+}
+
+// Get returns the port forward of a profile from a port of the host
+func Get(profile string, hostPort int, miniHome ...string) (Forward, error) {
+	fs, err := List(profile, miniHome...)
+	if err != nil {
+		return Forward{}, err
+	}
+	for _, f := range fs {
+		if f.HostPort == hostPort {
+			return f, nil
+		}
+	}
+	return Forward{}, fmt.Errorf("port %d is not forwarded", hostPort)
+}
+
+// save writes the port forwards of a profile
+func save(profile string, fs []Forward, miniHome ...string) error {
+	if err := os.MkdirAll(constants.GetProfilePath(profile, miniHome...), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(fs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(profile, miniHome...), b, 0600)
+}
+
+// Add records a port forward of a profile. A stopped forward of the same port of the host is replaced.
+func Add(profile string, f Forward, miniHome ...string) error {
+	fs, err := List(profile, miniHome...)
+	if err != nil {
+		return err
+	}
+	kept := []Forward{}
+	for _, o := range fs {
+		if o.HostPort != f.HostPort {
+			kept = append(kept, o)
+			continue
+		}
+		if o.Running() && o.Pid != f.Pid {
+			return fmt.Errorf("port %d is already forwarded to %s/%s", f.HostPort, o.Namespace, o.Service)
+		}
+	}
+	return save(profile, append(kept, f), miniHome...)
+}
+
+// Remove stops a port forward of a profile, and removes it
+func Remove(profile string, hostPort int, miniHome ...string) error {
+	fs, err := List(profile, miniHome...)
+	if err != nil {
+		return err
+	}
+	kept := []Forward{}
+	found := false
+	for _, f := range fs {
+		if f.HostPort != hostPort {
+			kept = append(kept, f)
+			continue
+		}
+		found = true
+		f.kill()
+	}
+	if !found {
+		return fmt.Errorf("port %d is not forwarded", hostPort)
+	}
+	return save(profile, kept, miniHome...)
+}
+
+// RemoveAll stops the port forwards of a profile, and removes them
+func RemoveAll(profile string, miniHome ...string) error {
+	fs, err := List(profile, miniHome...)
+	if err != nil {
+		return err
+	}
+	for _, f := range fs {
+		f.kill()
+	}
+	if err := os.Remove(Path(profile, miniHome...)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Running returns whether the process of the port forward is running
+func (f Forward) Running() bool {
+	return daemon.Running(f.Pid)
+}
+
+// kill stops the process of the port forward, if it runs
+func (f Forward) kill() {
+	if err := daemon.Kill(f.Pid); err != nil {
+		glog.Infof("killing port forward process %d: %v", f.Pid, err)
+	}
+}
+
+// Start runs the process of a port forward in the background, and records it
+func Start(profile string, f Forward) error {
+	// The process is recorded before it starts, as it looks its forward up
+	if err := Add(profile, f); err != nil {
+		return err
+	}
+	pid, err := daemon.Start(LogFilePath(profile, f.HostPort), "port-forward", "run", "--profile", profile, "--port", strconv.Itoa(f.HostPort))
+	if err != nil {
+		return err
+	}
+	f.Pid = pid
+	return Add(profile, f)
+}
+
+// Serve accepts connections on ln, and copies each of them to and from a connection returned by dial
+func Serve(ln net.Listener, dial func() (net.Conn, error)) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			upstream, err := dial()
+			if err != nil {
+				glog.Warningf("dial: %v", err)
+				return
+			}
+			defer upstream.Close()
+			done := make(chan struct{}, 2)
+			go func() {
+				if _, err := io.Copy(upstream, conn); err != nil {
+					glog.Infof("copy to upstream: %v", err)
+				}
+				done <- struct{}{}
+			}()
+			go func() {
+				if _, err := io.Copy(conn, upstream); err != nil {
+					glog.Infof("copy from upstream: %v", err)
+				}
+				done <- struct{}{}
+			}()
+			// Either side closing ends the forward of the connection
+			<-done
+		}()
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestAddListRemove(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	if fs, err := List("p1"); err != nil || len(fs) != 0 {
+		t.Fatalf("List() = %v, %v, want none", fs, err)
+	}
+	web := Forward{HostPort: 8080, Namespace: "default", Service: "web", Port: 80, Target: "10.96.0.10:80"}
+	db := Forward{HostPort: 5432, Namespace: "db", Service: "postgres", Port: 5432, Target: "10.96.0.20:5432"}
+	for _, f := range []Forward{web, db} {
+		if err := Add("p1", f); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	fs, err := List("p1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(fs) != 2 || fs[0] != db || fs[1] != web {
+		t.Errorf("List() = %+v, want the forwards ordered by port", fs)
+	}
+
+	// This process stands for the process of a running forward
+	running := Forward{HostPort: 8080, Namespace: "other", Service: "api", Port: 80, Pid: os.Getpid()}
+	if err := Add("p1", running); err != nil {
+		t.Fatalf("Add over a stopped forward: %v", err)
+	}
+	if err := Add("p1", web); err == nil {
+		t.Errorf("Add over a running forward = nil, want error")
+	}
+	if f, err := Get("p1", 8080); err != nil || f != running {
+		t.Errorf("Get(8080) = %+v, %v, want %+v", f, err, running)
+	}
+
+	if err := Remove("p1", 5432); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := Remove("p1", 5432); err == nil {
+		t.Errorf("Remove of a missing forward = nil, want error")
+	}
+	if _, err := Get("p1", 5432); err == nil {
+		t.Errorf("Get of a removed forward = nil, want error")
+	}
+	if err := RemoveAll("p1"); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+	if fs, err := List("p1"); err != nil || len(fs) != 0 {
+		t.Errorf("List() after RemoveAll = %v, %v, want none", fs, err)
+	}
+}
+
+func TestServe(t *testing.T) {
+	upstream, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer upstream.Close()
+	go func() {
+		for {
+			c, err := upstream.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				line, _ := bufio.NewReader(c).ReadString('\n')
+				fmt.Fprintf(c, "echo %s", line)
+			}()
+		}
+	}()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	go func() {
+		if err := Serve(ln, func() (net.Conn, error) { return net.Dial("tcp", upstream.Addr().String()) }); err != nil {
+			t.Logf("Serve: %v", err)
+		}
+	}()
+	defer ln.Close()
+
+	for i := 0; i < 2; i++ {
+		c, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial: %v", err)
+		}
+		fmt.Fprintf(c, "hello %d\n", i)
+		got, err := bufio.NewReader(c).ReadString('\n')
+		c.Close()
+		if err != nil {
+			t.Fatalf("ReadString: %v", err)
+		}
+		if want := fmt.Sprintf("echo hello %d\n", i); got != want {
+			t.Errorf("forwarded reply = %q, want %q", got, want)
+		}
+	}
+}
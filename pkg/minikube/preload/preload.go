@@ -0,0 +1,202 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preload packs image archives into a preload, which minikube start loads into the container runtime
+// before Kubernetes pulls its images
+package preload
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// manifestName is the name of the first entry of a preload
+const manifestName = "preload.json"
+
+// imagesPrefix is the directory of the image archives in a preload
+const imagesPrefix = "images/"
+
+// Image is an image archive of a preload
+type Image struct {
+	Name string
+	// File is the path of the archive in the preload
+	File string
+}
+
+// Manifest describes what a preload was created for
+type Manifest struct {
+	KubernetesVersion string
+	// ContainerRuntime is the runtime whose archive format the images use, or empty if any runtime can load them
+	ContainerRuntime string `json:",omitempty"`
+	Images           []Image
+}
+
+// FileName returns the name of the archive of an image in a preload
+func FileName(image string) string {
+	return imagesPrefix + strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(image) + ".tar"
+}
+
+// Create writes a preload of the image archives files, indexed by image name, to dst
+func Create(dst string, m Manifest, files map[string]string) error {
+	m.Images = nil
+	for name := range files {
+		m.Images = append(m.Images, Image{Name: name, File: FileName(name)})
+	}
+	sort.Slice(m.Images, func(i, j int) bool { return m.Images[i].Name < m.Images[j].Name })
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	if err := write(f, m, files); err != nil {
+		f.Close()
+		os.Remove(dst)
+		return err
+	}
+	return f.Close()
+}
+
+func write(w io.Writer, m Manifest, files map[string]string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "manifest")
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0644, Size: int64(len(b)), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+
+	for _, i := range m.Images {
+		if err := addFile(tw, files[i.Name], i.File); err != nil {
+			return errors.Wrapf(err, "adding %s", i.Name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func addFile(tw *tar.Writer, src string, name string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	glog.Infof("Preloading %s (%d bytes)", name, info.Size())
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Extract extracts the image archives of the preload src into dir, and returns its manifest
+// and the paths of the archives, indexed by image name
+func Extract(src string, dir string) (Manifest, map[string]string, error) {
+	var m Manifest
+	f, err := os.Open(src)
+	if err != nil {
+		return m, nil, errors.Wrap(err, "open")
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return m, nil, errors.Wrapf(err, "%s is not a minikube preload", src)
+	}
+	tr := tar.NewReader(gr)
+
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return m, nil, fmt.Errorf("%s is not a minikube preload: missing %s", src, manifestName)
+	}
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return m, nil, errors.Wrap(err, "decoding manifest")
+	}
+	names := map[string]string{}
+	for _, i := range m.Images {
+		if err := validName(i.File); err != nil {
+			return m, nil, err
+		}
+		names[i.File] = i.Name
+	}
+
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return m, nil, errors.Wrap(err, "reading preload")
+		}
+		name, ok := names[hdr.Name]
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		dst := filepath.Join(dir, path.Base(hdr.Name))
+		if err := extractFile(tr, dst); err != nil {
+			return m, nil, errors.Wrapf(err, "extracting %s", hdr.Name)
+		}
+		files[name] = dst
+	}
+	for _, i := range m.Images {
+		if _, ok := files[i.Name]; !ok {
+			return m, nil, fmt.Errorf("%s is truncated: missing %s", src, i.File)
+		}
+	}
+	return m, files, nil
+}
+
+func extractFile(r io.Reader, dst string) error {
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// validName returns an error if name is not the path of an image archive
+func validName(name string) error {
+	if path.Clean(name) != name || path.Dir(name)+"/" != imagesPrefix {
+		return fmt.Errorf("invalid preload path %q: must be within %s", name, imagesPrefix)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preload
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCreateAndExtract(t *testing.T) {
+	src, err := ioutil.TempDir("", "preload-src")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "preload-dst")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	images := map[string]string{
+		"k8s.gcr.io/pause:3.1":              "pause",
+		"registry.example.com/base/java:11": "java",
+	}
+	files := map[string]string{}
+	for name, content := range images {
+		p := filepath.Join(src, content+".tar")
+		if err := ioutil.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		files[name] = p
+	}
+
+	archive := filepath.Join(src, "preload.tar.gz")
+	if err := Create(archive, Manifest{KubernetesVersion: "v1.15.2", ContainerRuntime: "containerd"}, files); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	m, got, err := Extract(archive, dst)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	want := Manifest{
+		KubernetesVersion: "v1.15.2",
+		ContainerRuntime:  "containerd",
+		Images: []Image{
+			{Name: "k8s.gcr.io/pause:3.1", File: "images/k8s.gcr.io_pause_3.1.tar"},
+			{Name: "registry.example.com/base/java:11", File: "images/registry.example.com_base_java_11.tar"},
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Extract() manifest = %+v, want %+v", m, want)
+	}
+	for name, content := range images {
+		b, err := ioutil.ReadFile(got[name])
+		if err != nil {
+			t.Errorf("reading %s: %v", name, err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	var tests = []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "images/k8s.gcr.io_pause_3.1.tar"},
+		{name: "images/../config.json", wantErr: true},
+		{name: "images/a/b.tar", wantErr: true},
+		{name: "/images/a.tar", wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := validName(tc.name); (err != nil) != tc.wantErr {
+			t.Errorf("validName(%q) = %v, wantErr: %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -280,3 +280,75 @@ var stateProblems = map[string]match{
 		Issues: []int{3849, 3648},
 	},
 }
+
+// hostProblems are issues with the host environment, as found by minikube doctor
+var hostProblems = map[string]match{
+	"VIRTUALIZATION_DISABLED": {
+		Regexp: re(`CPU does not support hardware virtualization, or it is disabled in the BIOS`),
+		Advice: "Enable VT-x/AMD-v in your BIOS. If this host is a VM, enable nested virtualization, or use --vm-driver=none",
+	},
+	"KVM_DEVICE_MISSING": {
+		Regexp: re(`/dev/kvm does not exist`),
+		Advice: "Load the kvm kernel module: 'sudo modprobe kvm_intel', or 'sudo modprobe kvm_amd' on AMD CPUs",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/kvm2/",
+		GOOS:   "linux",
+	},
+	"KVM_DEVICE_PERMISSION": {
+		Regexp: re(`/dev/kvm: permission denied`),
+		Advice: "Add your user to the kvm and libvirt groups with 'sudo usermod -aG kvm,libvirt $USER', then log in again",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/kvm2/",
+		GOOS:   "linux",
+	},
+	"HYPERVISOR_FRAMEWORK_UNSUPPORTED": {
+		Regexp: re(`this Mac does not support Hypervisor.framework`),
+		Advice: "The hyperkit and vfkit drivers need Hypervisor.framework. Use --vm-driver=virtualbox instead",
+		GOOS:   "darwin",
+	},
+	"LIBVIRT_NOT_FOUND": {
+		Regexp: re(`virsh not found`),
+		Advice: "Install libvirt, as described in the kvm2 driver documentation",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/kvm2/",
+	},
+	"VFKIT_NOT_FOUND": {
+		Regexp: re(`vfkit not found`),
+		Advice: "Install vfkit, for example with 'brew install vfkit'",
+	},
+	"QEMU_NOT_FOUND": {
+		Regexp: re(`qemu-system-\S+ not found`),
+		Advice: "Install qemu, for example with 'brew install qemu' or 'sudo apt install qemu-system'",
+	},
+	"NONE_DOCKER_NOT_FOUND": {
+		Regexp: re(`docker not found, the none driver`),
+		Advice: "Install docker on the host, or use a VM driver",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/none/",
+	},
+	"NONE_CGROUP_V2": {
+		Regexp: re(`requires cgroup v1, but this host uses cgroup v2`),
+		Advice: "Boot the host with the systemd.unified_cgroup_hierarchy=0 kernel parameter, or use a VM driver",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/drivers/none/",
+		GOOS:   "linux",
+	},
+	"PROXY_MISSING_NO_PROXY": {
+		Regexp: re(`NO_PROXY does not include the minikube VM network`),
+		Advice: "Add the minikube VM network to the NO_PROXY environment variable, so that minikube and kubectl connect to the VM directly",
+		URL:    proxyDoc,
+	},
+	"VPN_ACTIVE": {
+		Regexp: re(`VPN interface \S+ is up`),
+		Advice: "VPN software may route the traffic to the minikube VM elsewhere. If minikube cannot connect to the VM, exclude the VM network from the VPN, or turn it off",
+		URL:    "https://minikube.sigs.k8s.io/docs/reference/networking/vpn/",
+	},
+	"PORT_IN_USE": {
+		Regexp: re(`port \d+ is already in use`),
+		Advice: "Stop the process listening on the port, such as another Kubernetes installation on this host",
+	},
+	"LOW_DISK_SPACE": {
+		Regexp: re(`free disk space in .* is below`),
+		Advice: "Free up some disk space, or set MINIKUBE_HOME to a directory on a larger disk",
+	},
+	"DNS_LOOKUP_FAILURE": {
+		Regexp: re(`cannot resolve \S+`),
+		Advice: "Check the DNS configuration of the host. If you are behind a proxy, set the HTTP_PROXY and HTTPS_PROXY environment variables",
+		URL:    proxyDoc,
+	},
+}
@@ -71,14 +71,16 @@ func (p *Problem) Display() {
 	}
 }
 
-// FromError returns a known problem from an error on an OS
+// FromError returns a known problem from an error on an OS. The problems of the cached remote database are matched first.
 func FromError(err error, os string) *Problem {
 	maps := []map[string]match{
+		remoteProblems(),
 		osProblems,
 		vmProblems,
 		netProblems,
 		deployProblems,
 		stateProblems,
+		hostProblems,
 	}
 	for _, m := range maps {
 		for k, v := range m {
@@ -0,0 +1,160 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package problem
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
+)
+
+// Database is the remote database of known problems, which ships new failure signatures and advice between releases
+type Database struct {
+	Problems []RemoteProblem `json:"problems"`
+}
+
+// RemoteProblem is a known problem of the remote database, as the matches which minikube is built with
+type RemoteProblem struct {
+	ID     string `json:"id"`
+	Regexp string `json:"regexp"`
+	Advice string `json:"advice"`
+	URL    string `json:"url,omitempty"`
+	Issues []int  `json:"issues,omitempty"`
+	GOOS   string `json:"goos,omitempty"`
+}
+
+// CachePath returns where the verified remote database is kept, for use offline
+func CachePath() string {
+	return constants.MakeMiniPath("cache", "problems.json")
+}
+
+var (
+	remoteOnce    sync.Once
+	remoteMatches map[string]match
+)
+
+// remoteProblems returns the problems of the cached remote database, which match before the ones minikube is built with
+var remoteProblems = func() map[string]match {
+	remoteOnce.Do(func() {
+		remoteMatches = loadCache(CachePath())
+	})
+	return remoteMatches
+}
+
+// loadCache returns the problems of a cached database. There are none if it does not exist or is invalid.
+func loadCache(path string) map[string]match {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Warningf("reading known problems: %v", err)
+		}
+		return map[string]match{}
+	}
+	m, err := parseDatabase(b)
+	if err != nil {
+		glog.Warningf("parsing known problems %s: %v", path, err)
+		return map[string]match{}
+	}
+	return m
+}
+
+// parseDatabase returns the matches of a database. Problems whose regexp does not compile are skipped.
+func parseDatabase(b []byte) (map[string]match, error) {
+	var db Database
+	if err := json.Unmarshal(b, &db); err != nil {
+		return nil, err
+	}
+	m := map[string]match{}
+	for _, p := range db.Problems {
+		r, err := regexp.Compile(p.Regexp)
+		if err != nil {
+			glog.Warningf("skipping known problem %s: %v", p.ID, err)
+			continue
+		}
+		m[p.ID] = match{Regexp: r, Advice: p.Advice, URL: p.URL, Issues: p.Issues, GOOS: p.GOOS}
+	}
+	return m, nil
+}
+
+// Update downloads the database at url and its signature at url + ".sig", as written by 'cosign sign-blob',
+// and replaces the cache with the database if it is signed by key
+func Update(url string, key *ecdsa.PublicKey, cache string) error {
+	b, err := fetch(url)
+	if err != nil {
+		return err
+	}
+	sig, err := fetch(url + ".sig")
+	if err != nil {
+		return err
+	}
+	if err := download.VerifyBlob(b, sig, key); err != nil {
+		return errors.Wrap(err, "signature")
+	}
+	if _, err := parseDatabase(b); err != nil {
+		return errors.Wrap(err, "parsing database")
+	}
+	if err := os.MkdirAll(filepath.Dir(cache), 0755); err != nil {
+		return err
+	}
+	tmp := cache + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cache)
+}
+
+// UpdateIfStale updates the cached database from url if it was updated more than maxAge ago.
+// The signature is verified with the key which verifies downloads.
+func UpdateIfStale(url string, maxAge time.Duration) error {
+	if fi, err := os.Stat(CachePath()); err == nil && time.Since(fi.ModTime()) < maxAge {
+		return nil
+	}
+	key, err := download.PublicKey()
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("no public key to verify the known problems, set %s", constants.VerifyDownloadsKey)
+	}
+	return Update(url, key, CachePath())
+}
+
+// fetch returns the body of a URL
+func fetch(url string) ([]byte, error) {
+	c := http.Client{Timeout: 30 * time.Second}
+	resp, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected response: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package problem
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testDatabase = `{"problems":[
+	{"id":"NEW_PROBLEM","regexp":"a new failure signature","advice":"Follow the new advice","issues":[9999]},
+	{"id":"INVALID_REGEXP","regexp":"(","advice":"Skipped"}
+]}`
+
+// sign returns the signature of data by key, as written by 'cosign sign-blob'
+func sign(t *testing.T, key *ecdsa.PrivateKey, data string) string {
+	digest := sha256.Sum256([]byte(data))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func TestUpdate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	sig := sign(t, key, testDatabase)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/problems.json", "/tampered.json":
+			fmt.Fprint(w, testDatabase)
+		case "/problems.json.sig":
+			fmt.Fprint(w, sig+"\n")
+		case "/tampered.json.sig":
+			fmt.Fprint(w, sign(t, key, "another database"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "problems")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	cache := filepath.Join(dir, "cache", "problems.json")
+
+	for _, name := range []string{"tampered.json", "unsigned.json"} {
+		if err := Update(ts.URL+"/"+name, &key.PublicKey, cache); err == nil {
+			t.Errorf("Update(%s) = nil, want an error", name)
+		}
+		if _, err := os.Stat(cache); !os.IsNotExist(err) {
+			t.Errorf("Update(%s) wrote the cache", name)
+		}
+	}
+	if err := Update(ts.URL+"/problems.json", &key.PublicKey, cache); err != nil {
+		t.Fatalf("Update(): %v", err)
+	}
+
+	m := loadCache(cache)
+	if len(m) != 1 {
+		t.Fatalf("loadCache() = %v, want the problems with a valid regexp", m)
+	}
+	defer func(f func() map[string]match) { remoteProblems = f }(remoteProblems)
+	remoteProblems = func() map[string]match { return m }
+	p := FromError(fmt.Errorf("start: a new failure signature"), "")
+	if p == nil || p.ID != "NEW_PROBLEM" || p.Advice != "Follow the new advice" {
+		t.Errorf("FromError() = %+v, want NEW_PROBLEM", p)
+	}
+}
+
+func TestLoadCacheOffline(t *testing.T) {
+	if m := loadCache(filepath.Join(os.TempDir(), "no-such-dir", "problems.json")); len(m) != 0 {
+		t.Errorf("loadCache() of a missing cache = %v, want none", m)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Bundle writes a gzipped tarball of redacted files
+type Bundle struct {
+	gz  *gzip.Writer
+	tw  *tar.Writer
+	r   *Redactor
+	now time.Time
+}
+
+// NewBundle returns a bundle writing to w, which redacts files with r
+func NewBundle(w io.Writer, r *Redactor) *Bundle {
+	gz := gzip.NewWriter(w)
+	return &Bundle{gz: gz, tw: tar.NewWriter(gz), r: r, now: time.Now()}
+}
+
+// Add adds a file with data to the bundle, once redacted
+func (b *Bundle) Add(name string, data []byte) error {
+	data = b.r.Redact(data)
+	hdr := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: b.now, Typeflag: tar.TypeReg}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "header of %s", name)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return errors.Wrapf(err, "write %s", name)
+	}
+	return nil
+}
+
+// AddFile adds the file at path to the bundle as name. Only the last maxSize bytes of larger files are kept.
+func (b *Bundle) AddFile(name string, path string, maxSize int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() > maxSize {
+		if _, err := f.Seek(-maxSize, io.SeekEnd); err != nil {
+			return errors.Wrapf(err, "seek %s", path)
+		}
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return errors.Wrapf(err, "read %s", path)
+	}
+	return b.Add(name, data)
+}
+
+// Close flushes the bundle
+func (b *Bundle) Close() error {
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+	return b.gz.Close()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report gathers the diagnostics of minikube into a tarball, with secrets and the identity of the user redacted
+package report
+
+import (
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the secrets removed from reports
+const redacted = "<redacted>"
+
+// secrets match the secrets of logs and configuration files, with the replacement of each
+var secrets = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Private keys, in PEM blocks
+	{regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`), "<redacted private key>"},
+	// Credentials of URLs, such as proxies
+	{regexp.MustCompile(`(://[^/\s:@]+:)[^/\s@]+@`), "${1}" + redacted + "@"},
+	// Authorization headers
+	{regexp.MustCompile(`(?i)(authorization:\s*(?:bearer|basic)\s+)\S+`), "${1}" + redacted},
+	// Keys and flags naming a secret, in YAML, JSON, INI files and command lines
+	{regexp.MustCompile(`(?i)("?[a-z0-9_.-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|client-key-data)[a-z0-9_.-]*"?\s*[:=]\s*"?)([^\s",]+)`), "${1}" + redacted},
+	// Bootstrap tokens of kubeadm
+	{regexp.MustCompile(`\b[a-z0-9]{6}\.[a-z0-9]{16}\b`), redacted},
+}
+
+// systemUsers are user names which are not redacted, as they are also the names of system accounts and directories
+var systemUsers = map[string]bool{"root": true, "docker": true, "admin": true, "user": true}
+
+// Redactor removes secrets, the home directory and the name of the user from the files of a report
+type Redactor struct {
+	home string
+	user *regexp.Regexp
+}
+
+// NewRedactor returns a redactor of the secrets and of the identity of the user with name and home directory
+func NewRedactor(name string, home string) *Redactor {
+	r := &Redactor{home: home}
+	if len(name) > 1 && !systemUsers[strings.ToLower(name)] {
+		r.user = regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	}
+	return r
+}
+
+// Redact returns b without secrets, and with the home directory and the name of the user replaced
+func (r *Redactor) Redact(b []byte) []byte {
+	for _, s := range secrets {
+		b = s.re.ReplaceAll(b, []byte(s.repl))
+	}
+	if len(r.home) > 1 {
+		b = []byte(strings.Replace(string(b), r.home, "$HOME", -1))
+	}
+	if r.user != nil {
+		b = r.user.ReplaceAll(b, []byte("<user>"))
+	}
+	return b
+}
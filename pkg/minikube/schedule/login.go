@@ -0,0 +1,103 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/client-go/util/homedir"
+)
+
+// loginItem returns the path and content of the file which makes the user's session
+// run minikube with args on login: a launchd agent on macOS, an XDG autostart entry
+// on Linux, and a script in the Startup folder on Windows.
+func loginItem(goos string, home string, exe string, profile string, args []string) (string, string) {
+	switch goos {
+	case "darwin":
+		var b strings.Builder
+		for _, a := range append([]string{exe}, args...) {
+			fmt.Fprintf(&b, "\t\t<string>%s</string>\n", html.EscapeString(a))
+		}
+		label := "io.k8s.minikube.start." + profile
+		content := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+</dict>
+</plist>
+`, label, b.String())
+		return filepath.Join(home, "Library", "LaunchAgents", label+".plist"), content
+	case "windows":
+		quoted := []string{}
+		for _, a := range append([]string{exe}, args...) {
+			quoted = append(quoted, `"`+a+`"`)
+		}
+		path := filepath.Join(home, "AppData", "Roaming", "Microsoft", "Windows", "Start Menu", "Programs", "Startup", "minikube-start-"+profile+".cmd")
+		return path, "@echo off\r\n" + strings.Join(quoted, " ") + "\r\n"
+	default:
+		quoted := []string{}
+		for _, a := range append([]string{exe}, args...) {
+			quoted = append(quoted, `"`+strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`).Replace(a)+`"`)
+		}
+		content := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=minikube (%s)
+Comment=Starts the %s minikube cluster
+Exec=%s
+Terminal=false
+NoDisplay=true
+`, profile, profile, strings.Join(quoted, " "))
+		return filepath.Join(home, ".config", "autostart", "minikube-start-"+profile+".desktop"), content
+	}
+}
+
+func installLoginItem(e Entry) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	path, content := loginItem(runtime.GOOS, homedir.HomeDir(), exe, e.Profile, e.Args)
+	glog.Infof("Installing login item %s", path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+func removeLoginItem(profile string) error {
+	return os.Remove(LoginItemPath(profile))
+}
+
+// LoginItemPath returns the path of the file which starts a profile on login
+func LoginItemPath(profile string) string {
+	path, _ := loginItem(runtime.GOOS, homedir.HomeDir(), "", profile, nil)
+	return path
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule starts clusters at a later time, or whenever the user logs in to the host
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+// Login is the --schedule value which starts the cluster whenever the user logs in
+const Login = "login"
+
+const fileName = "schedule.json"
+
+// Entry is a scheduled start of a profile
+type Entry struct {
+	Profile string
+	// At is when the cluster will be started, unless it is started on login
+	At time.Time `json:",omitempty"`
+	// OnLogin is set if the cluster is started each time the user logs in to the host
+	OnLogin bool `json:",omitempty"`
+	// Args are the arguments minikube is run with to start the cluster
+	Args []string
+	// Pid is the process waiting to start the cluster at At
+	Pid int `json:",omitempty"`
+}
+
+// String returns when an entry starts its cluster
+func (e Entry) String() string {
+	if e.OnLogin {
+		return "on login"
+	}
+	return e.At.Format(time.RFC1123)
+}
+
+// Path returns the path of the schedule file of a profile
+func Path(profile string, miniHome ...string) string {
+	return filepath.Join(constants.GetProfilePath(profile, miniHome...), fileName)
+}
+
+// Parse parses a --schedule value: "login", a duration from now such as "2h",
+// a time of day such as "08:30" (the next occurrence is used), or an RFC3339 timestamp.
+func Parse(s string, now time.Time) (at time.Time, onLogin bool, err error) {
+	if s == Login {
+		return time.Time{}, true, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return at, false, fmt.Errorf("schedule %q is not in the future", s)
+		}
+		return now.Add(d), false, nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		at = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		if !t.After(now) {
+			return at, false, fmt.Errorf("schedule %q is not in the future", s)
+		}
+		return t, false, nil
+	}
+	return at, false, fmt.Errorf("invalid schedule %q: expected %q, a duration (2h), a time of day (08:30) or an RFC3339 timestamp", s, Login)
+}
+
+// Load returns the scheduled start of a profile, or nil if there is none
+func Load(profile string, miniHome ...string) (*Entry, error) {
+	b, err := ioutil.ReadFile(Path(profile, miniHome...))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", Path(profile, miniHome...))
+	}
+	return &e, nil
+}
+
+// List returns the scheduled starts of all profiles, sorted by profile
+func List(miniHome ...string) ([]Entry, error) {
+	miniPath := constants.GetMinipath()
+	if len(miniHome) > 0 {
+		miniPath = miniHome[0]
+	}
+	paths, err := filepath.Glob(filepath.Join(miniPath, "profiles", "*", fileName))
+	if err != nil {
+		return nil, err
+	}
+	entries := []Entry{}
+	for _, p := range paths {
+		e, err := Load(filepath.Base(filepath.Dir(p)), miniHome...)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			entries = append(entries, *e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Profile < entries[j].Profile })
+	return entries, nil
+}
+
+func save(e Entry) error {
+	if err := os.MkdirAll(constants.GetProfilePath(e.Profile), 0700); err != nil {
+		return errors.Wrap(err, "creating profile dir")
+	}
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(Path(e.Profile), b, 0600)
+}
+
+// Add schedules the start of a profile, replacing any previous schedule for it
+func Add(e Entry) (*Entry, error) {
+	if err := Clear(e.Profile); err != nil {
+		return nil, errors.Wrap(err, "clearing previous schedule")
+	}
+	if e.OnLogin {
+		if err := installLoginItem(e); err != nil {
+			return nil, errors.Wrap(err, "installing login item")
+		}
+		return &e, save(e)
+	}
+
+	// Save before starting, so that the process finds its entry
+	if err := save(e); err != nil {
+		return nil, err
+	}
+	pid, err := daemon.Start(LogFilePath(e.Profile), "schedule", "run", "--profile", e.Profile)
+	if err != nil {
+		return nil, err
+	}
+	e.Pid = pid
+	return &e, save(e)
+}
+
+// LogFilePath returns the path of the log file of the scheduled start of a profile
+func LogFilePath(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), "schedule.log")
+}
+
+// Clear cancels the scheduled start of a profile, if there is one
+func Clear(profile string) error {
+	e, err := Load(profile)
+	if err != nil || e == nil {
+		return err
+	}
+	if e.OnLogin {
+		if err := removeLoginItem(profile); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "removing login item")
+		}
+	}
+	// Once At has passed, the process is gone and its pid may have been reused
+	if e.Pid != 0 && time.Now().Before(e.At) {
+		if err := daemon.Kill(e.Pid); err != nil {
+			glog.Infof("killing scheduled start %d: %v", e.Pid, err)
+		}
+	}
+	return os.Remove(Path(profile))
+}
+
+// Wait blocks until the scheduled start of a profile is due, then clears it and returns
+// the arguments to start the cluster with. It is run by the process spawned by Add.
+func Wait(profile string) ([]string, error) {
+	e, err := Load(profile)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil || e.OnLogin {
+		return nil, fmt.Errorf("no start is scheduled for %s", profile)
+	}
+	glog.Infof("Waiting until %s to start %s", e.At, profile)
+	// Sleep in steps and check the wall clock, as the monotonic clock does not advance while the host is suspended
+	for {
+		d := time.Until(e.At)
+		if d <= 0 {
+			break
+		}
+		if d > time.Minute {
+			d = time.Minute
+		}
+		time.Sleep(d)
+	}
+	if err := os.Remove(Path(profile)); err != nil {
+		return nil, err
+	}
+	return e.Args, nil
+}
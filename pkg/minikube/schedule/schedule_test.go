@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2019, 8, 20, 10, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		schedule    string
+		wantAt      time.Time
+		wantOnLogin bool
+		wantErr     bool
+	}{
+		{schedule: "login", wantOnLogin: true},
+		{schedule: "2h", wantAt: now.Add(2 * time.Hour)},
+		{schedule: "08:30", wantAt: time.Date(2019, 8, 21, 8, 30, 0, 0, time.UTC)},
+		{schedule: "18:15", wantAt: time.Date(2019, 8, 20, 18, 15, 0, 0, time.UTC)},
+		{schedule: "2019-08-25T09:00:00Z", wantAt: time.Date(2019, 8, 25, 9, 0, 0, 0, time.UTC)},
+		{schedule: "2019-08-01T09:00:00Z", wantErr: true},
+		{schedule: "-1h", wantErr: true},
+		{schedule: "tomorrow", wantErr: true},
+	}
+	for _, tc := range tests {
+		at, onLogin, err := Parse(tc.schedule, now)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr: %v", tc.schedule, err, tc.wantErr)
+			continue
+		}
+		if !at.Equal(tc.wantAt) || onLogin != tc.wantOnLogin {
+			t.Errorf("Parse(%q) = %v, %v, want %v, %v", tc.schedule, at, onLogin, tc.wantAt, tc.wantOnLogin)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	miniHome, err := ioutil.TempDir("", "schedule")
+	if err != nil {
+		t.Fatalf("Error make tmp directory: %v", err)
+	}
+	defer os.RemoveAll(miniHome)
+
+	entries := []Entry{
+		{Profile: "p2", OnLogin: true, Args: []string{"start", "-p", "p2"}},
+		{Profile: "p1", At: time.Date(2019, 8, 21, 8, 30, 0, 0, time.UTC), Args: []string{"start", "-p", "p1"}, Pid: 42},
+	}
+	for _, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(miniHome, "profiles", e.Profile), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(Path(e.Profile, miniHome), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(miniHome, "profiles", "p3"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := List(miniHome)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 2 || got[0].Profile != "p1" || got[1].Profile != "p2" {
+		t.Fatalf("List() = %+v, want p1 and p2", got)
+	}
+	if !got[0].At.Equal(entries[1].At) || got[0].Pid != 42 || !got[1].OnLogin {
+		t.Errorf("List() = %+v, want %+v", got, entries)
+	}
+
+	e, err := Load("p3", miniHome)
+	if err != nil || e != nil {
+		t.Errorf("Load(p3) = %v, %v, want nil, nil", e, err)
+	}
+}
+
+func TestLoginItem(t *testing.T) {
+	args := []string{"start", "-p", "dev", "--memory=4096"}
+	var tests = []struct {
+		goos     string
+		wantPath string
+		want     string
+	}{
+		{
+			goos:     "darwin",
+			wantPath: filepath.Join("/home/me", "Library", "LaunchAgents", "io.k8s.minikube.start.dev.plist"),
+			want:     "\t\t<string>/usr/local/bin/minikube</string>\n\t\t<string>start</string>\n\t\t<string>-p</string>\n\t\t<string>dev</string>\n\t\t<string>--memory=4096</string>\n",
+		},
+		{
+			goos:     "linux",
+			wantPath: filepath.Join("/home/me", ".config", "autostart", "minikube-start-dev.desktop"),
+			want:     `Exec="/usr/local/bin/minikube" "start" "-p" "dev" "--memory=4096"`,
+		},
+		{
+			goos:     "windows",
+			wantPath: filepath.Join("/home/me", "AppData", "Roaming", "Microsoft", "Windows", "Start Menu", "Programs", "Startup", "minikube-start-dev.cmd"),
+			want:     `"/usr/local/bin/minikube" "start" "-p" "dev" "--memory=4096"`,
+		},
+	}
+	for _, tc := range tests {
+		path, content := loginItem(tc.goos, "/home/me", "/usr/local/bin/minikube", "dev", args)
+		if path != tc.wantPath {
+			t.Errorf("loginItem(%s) path = %q, want %q", tc.goos, path, tc.wantPath)
+		}
+		if !strings.Contains(content, tc.want) {
+			t.Errorf("loginItem(%s) content = %q, want it to contain %q", tc.goos, content, tc.want)
+		}
+	}
+}
@@ -76,6 +76,9 @@ func (k *K8sClientGetter) GetCoreClient() (typed_core.CoreV1Interface, error) {
 func (*K8sClientGetter) GetClientset(timeout time.Duration) (*kubernetes.Clientset, error) {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	profile := viper.GetString(config.MachineProfile)
+	if p := config.ProfileKubeconfig(profile); p != "" {
+		loadingRules.ExplicitPath = p
+	}
 	configOverrides := &clientcmd.ConfigOverrides{
 		Context: clientcmdapi.Context{
 			Cluster:  profile,
@@ -99,9 +102,9 @@ func (*K8sClientGetter) GetClientset(timeout time.Duration) (*kubernetes.Clients
 
 // URL represents service URL
 type URL struct {
-	Namespace string
-	Name      string
-	URLs      []string
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	URLs      []string `json:"urls"`
 }
 
 // URLs represents a list of URL
@@ -273,6 +276,10 @@ func WaitAndMaybeOpenService(api libmachine.API, namespace string, service strin
 		return errors.Wrap(err, "Check that minikube is running and that you have specified the correct namespace")
 	}
 
+	if out.IsJSON() {
+		return writeServiceJSON(namespace, service, urls, urlMode, https)
+	}
+
 	if !urlMode {
 		var data [][]string
 		if len(urls) == 0 {
@@ -303,6 +310,29 @@ func WaitAndMaybeOpenService(api libmachine.API, namespace string, service strin
 	return nil
 }
 
+// writeServiceJSON writes the URLs of a service as JSON, and opens the http ones unless urlMode is set
+func writeServiceJSON(namespace string, service string, urls []string, urlMode bool, https bool) error {
+	u := URL{Namespace: namespace, Name: service, URLs: []string{}}
+	for _, bareURLString := range urls {
+		urlString, _ := OptionallyHTTPSFormattedURLString(bareURLString, https)
+		u.URLs = append(u.URLs, urlString)
+	}
+	if err := out.JSON(u); err != nil {
+		return err
+	}
+	if urlMode {
+		return nil
+	}
+	for _, bareURLString := range urls {
+		if urlString, isHTTPSchemedURL := OptionallyHTTPSFormattedURLString(bareURLString, https); isHTTPSchemedURL {
+			if err := browser.OpenURL(urlString); err != nil {
+				out.ErrT(out.Empty, "browser failed to open url: {{.error}}", out.V{"error": err})
+			}
+		}
+	}
+	return nil
+}
+
 // GetServiceListByLabel returns a ServiceList by label
 func GetServiceListByLabel(namespace string, key string, value string) (*core.ServiceList, error) {
 	client, err := K8s.GetCoreClient()
@@ -324,6 +354,15 @@ func getServiceListFromServicesByLabel(services typed_core.ServiceInterface, key
 
 // CreateSecret creates or modifies secrets
 func CreateSecret(namespace, name string, dataValues map[string]string, labels map[string]string) error {
+	return createSecret(namespace, name, dataValues, labels, core.SecretTypeOpaque)
+}
+
+// CreatePullSecret creates a secret, usable as imagePullSecrets, from the contents of a docker config.json
+func CreatePullSecret(namespace, name string, dockerConfigJSON string, labels map[string]string) error {
+	return createSecret(namespace, name, map[string]string{core.DockerConfigJsonKey: dockerConfigJSON}, labels, core.SecretTypeDockerConfigJson)
+}
+
+func createSecret(namespace, name string, dataValues map[string]string, labels map[string]string, secretType core.SecretType) error {
 	client, err := K8s.GetCoreClient()
 	if err != nil {
 		return &util.RetriableError{Err: err}
@@ -352,7 +391,7 @@ func CreateSecret(namespace, name string, dataValues map[string]string, labels m
 			Labels: labels,
 		},
 		Data: data,
-		Type: core.SecretTypeOpaque,
+		Type: secretType,
 	}
 
 	_, err = secrets.Create(secretObj)
@@ -378,3 +417,18 @@ func DeleteSecret(namespace, name string) error {
 
 	return nil
 }
+
+// DeletePods deletes the pods of a namespace which match the label selector, so that their controller recreates them
+func DeletePods(namespace, selector string) error {
+	client, err := K8s.GetCoreClient()
+	if err != nil {
+		return &util.RetriableError{Err: err}
+	}
+
+	err = client.Pods(namespace).DeleteCollection(&meta.DeleteOptions{}, meta.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return &util.RetriableError{Err: err}
+	}
+
+	return nil
+}
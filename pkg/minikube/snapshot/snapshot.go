@@ -0,0 +1,237 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves and restores the disk of a stopped VM, with the configuration of its profile
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const (
+	// metadataName is the file describing a snapshot
+	metadataName = "snapshot.json"
+	// diskName is the copy of the disk in a snapshot
+	diskName = "disk.raw"
+	// configName is the copy of the profile configuration in a snapshot
+	configName = "config.json"
+	// blockSize is the size of the blocks checked for zeroes when copying disks
+	blockSize = 1024 * 1024
+)
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// Snapshot describes a saved disk
+type Snapshot struct {
+	Name              string    `json:"name"`
+	Created           time.Time `json:"created"`
+	KubernetesVersion string    `json:"kubernetesVersion"`
+	// Size is the apparent size of the disk, in bytes
+	Size int64 `json:"size"`
+}
+
+// Dir returns the directory of the snapshots of a profile
+func Dir(profile string) string {
+	return filepath.Join(constants.GetProfilePath(profile), "snapshots")
+}
+
+// Create copies disk and the configuration of profile to a new snapshot
+func Create(profile, name, disk, kubernetesVersion string) (Snapshot, error) {
+	s := Snapshot{Name: name, Created: time.Now().UTC(), KubernetesVersion: kubernetesVersion}
+	if !validName.MatchString(name) {
+		return s, fmt.Errorf("invalid snapshot name %q: use letters, digits, '.', '-' and '_'", name)
+	}
+	dst := filepath.Join(Dir(profile), name)
+	if _, err := os.Stat(dst); err == nil {
+		return s, fmt.Errorf("snapshot %q already exists", name)
+	}
+	if err := os.MkdirAll(Dir(profile), 0700); err != nil {
+		return s, err
+	}
+	// Write to a temporary directory, so that an interrupted copy does not look like a snapshot
+	tmp, err := ioutil.TempDir(Dir(profile), "."+name)
+	if err != nil {
+		return s, err
+	}
+	defer os.RemoveAll(tmp)
+
+	glog.Infof("Copying %s to snapshot %s", disk, name)
+	if s.Size, err = copySparse(disk, filepath.Join(tmp, diskName)); err != nil {
+		return s, errors.Wrap(err, "copying disk")
+	}
+	if err := copyFile(constants.GetProfileFile(profile), filepath.Join(tmp, configName)); err != nil && !os.IsNotExist(err) {
+		return s, errors.Wrap(err, "copying config")
+	}
+	b, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return s, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, metadataName), b, 0600); err != nil {
+		return s, err
+	}
+	return s, os.Rename(tmp, dst)
+}
+
+// List returns the snapshots of a profile, oldest first
+func List(profile string) ([]Snapshot, error) {
+	entries, err := ioutil.ReadDir(Dir(profile))
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ss := []Snapshot{}
+	for _, e := range entries {
+		if !e.IsDir() || !validName.MatchString(e.Name()) {
+			continue
+		}
+		s, err := load(profile, e.Name())
+		if err != nil {
+			glog.Warningf("skipping snapshot %s: %v", e.Name(), err)
+			continue
+		}
+		ss = append(ss, s)
+	}
+	sort.Slice(ss, func(i, j int) bool { return ss[i].Created.Before(ss[j].Created) })
+	return ss, nil
+}
+
+func load(profile, name string) (Snapshot, error) {
+	s := Snapshot{}
+	b, err := ioutil.ReadFile(filepath.Join(Dir(profile), name, metadataName))
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(b, &s)
+	return s, err
+}
+
+// Restore replaces disk and the configuration of profile by those of a snapshot
+func Restore(profile, name, disk string) (Snapshot, error) {
+	if !validName.MatchString(name) {
+		return Snapshot{}, fmt.Errorf("invalid snapshot name %q", name)
+	}
+	s, err := load(profile, name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, fmt.Errorf("snapshot %q does not exist", name)
+		}
+		return s, err
+	}
+	src := filepath.Join(Dir(profile), name)
+
+	glog.Infof("Restoring snapshot %s to %s", name, disk)
+	tmp := disk + ".restore"
+	if _, err := copySparse(filepath.Join(src, diskName), tmp); err != nil {
+		os.Remove(tmp)
+		return s, errors.Wrap(err, "copying disk")
+	}
+	if err := os.Rename(tmp, disk); err != nil {
+		return s, err
+	}
+	if err := copyFile(filepath.Join(src, configName), constants.GetProfileFile(profile)); err != nil && !os.IsNotExist(err) {
+		return s, errors.Wrap(err, "copying config")
+	}
+	return s, nil
+}
+
+// Delete removes a snapshot
+func Delete(profile, name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	dir := filepath.Join(Dir(profile), name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("snapshot %q does not exist", name)
+	}
+	return os.RemoveAll(dir)
+}
+
+// CopyDisk copies the disk image of a stopped VM to dst, such as to the machine directory of another profile
+func CopyDisk(src, dst string) (int64, error) {
+	tmp := dst + ".copy"
+	size, err := copySparse(src, tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return size, err
+	}
+	return size, os.Rename(tmp, dst)
+}
+
+// copySparse copies a disk image, leaving holes for the blocks which are only zeroes.
+// It returns the size of the image.
+func copySparse(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	zeroes := make([]byte, blockSize)
+	buf := make([]byte, blockSize)
+	var size int64
+	for {
+		n, err := io.ReadFull(in, buf)
+		if n > 0 {
+			if bytes.Equal(buf[:n], zeroes[:n]) {
+				if _, err := out.Seek(int64(n), io.SeekCurrent); err != nil {
+					return size, err
+				}
+			} else if _, err := out.Write(buf[:n]); err != nil {
+				return size, err
+			}
+			size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return size, err
+		}
+	}
+	// Trailing holes are only allocated by setting the size
+	if err := out.Truncate(size); err != nil {
+		return size, err
+	}
+	return size, out.Close()
+}
+
+func copyFile(src, dst string) error {
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, b, 0600)
+}
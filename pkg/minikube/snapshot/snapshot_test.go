@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+// writeDisk writes a disk image with data at the start, a hole, and data in the last block
+func writeDisk(t *testing.T, path string, head, tail string) []byte {
+	b := make([]byte, 3*blockSize+10)
+	copy(b, head)
+	copy(b[len(b)-len(tail):], tail)
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return b
+}
+
+func TestCopySparse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, tail := range []string{"tail", ""} {
+		src := filepath.Join(dir, "src")
+		want := writeDisk(t, src, "head", tail)
+		size, err := copySparse(src, filepath.Join(dir, "dst"))
+		if err != nil {
+			t.Fatalf("copySparse: %v", err)
+		}
+		if size != int64(len(want)) {
+			t.Errorf("size = %d, want %d", size, len(want))
+		}
+		got, err := ioutil.ReadFile(filepath.Join(dir, "dst"))
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("copy with tail %q differs from the source", tail)
+		}
+	}
+}
+
+func TestCreateListRestoreDelete(t *testing.T) {
+	home := tests.MakeTempDir()
+	defer os.RemoveAll(home)
+
+	profile := "p1"
+	if err := os.MkdirAll(constants.GetProfilePath(profile), 0700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := ioutil.WriteFile(constants.GetProfileFile(profile), []byte("v1 config"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	disk := filepath.Join(home, "p1.rawdisk")
+	before := writeDisk(t, disk, "before", "end")
+
+	if _, err := Create(profile, "good", disk, "v1.15.2"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := Create(profile, "good", disk, "v1.15.2"); err == nil {
+		t.Errorf("Create of an existing snapshot = nil, want error")
+	}
+	if _, err := Create(profile, "../escape", disk, "v1.15.2"); err == nil {
+		t.Errorf("Create with an invalid name = nil, want error")
+	}
+
+	ss, err := List(profile)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(ss) != 1 || ss[0].Name != "good" || ss[0].KubernetesVersion != "v1.15.2" || ss[0].Size != int64(len(before)) {
+		t.Errorf("List() = %+v, want the good snapshot", ss)
+	}
+
+	writeDisk(t, disk, "after", "broken")
+	if err := ioutil.WriteFile(constants.GetProfileFile(profile), []byte("v2 config"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := Restore(profile, "good", disk); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	got, err := ioutil.ReadFile(disk)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, before) {
+		t.Errorf("restored disk differs from the snapshot")
+	}
+	cfg, err := ioutil.ReadFile(constants.GetProfileFile(profile))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(cfg) != "v1 config" {
+		t.Errorf("restored config = %q, want %q", cfg, "v1 config")
+	}
+	if _, err := Restore(profile, "missing", disk); err == nil {
+		t.Errorf("Restore of a missing snapshot = nil, want error")
+	}
+
+	if err := Delete(profile, "good"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := Delete(profile, "good"); err == nil {
+		t.Errorf("Delete of a missing snapshot = nil, want error")
+	}
+	if ss, err := List(profile); err != nil || len(ss) != 0 {
+		t.Errorf("List() after Delete = %v, %v, want none", ss, err)
+	}
+}
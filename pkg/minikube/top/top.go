@@ -0,0 +1,223 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package top measures the resources used by the VM of a cluster, from the host which runs it
+package top
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shirou/gopsutil/process"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// pidFiles are the files where drivers write the pid of the hypervisor process, in the machine directory
+var pidFiles = map[string]string{
+	constants.DriverHyperkit: "hyperkit.pid",
+	constants.DriverQemu2:    "qemu.pid",
+	constants.DriverVfkit:    "vfkit.pid",
+}
+
+// libvirtPidDir is where libvirt writes the pid of the qemu process of a domain
+var libvirtPidDir = "/var/run/libvirt/qemu"
+
+// diskExts are the extensions of the disk images which drivers keep in the machine directory
+var diskExts = []string{".rawdisk", ".vmdk", ".vhdx", ".qcow2", ".img"}
+
+// Usage is the resources used by the VM of a node
+type Usage struct {
+	Name   string `json:"name"`
+	Driver string `json:"driver"`
+	Pid    int32  `json:"pid"`
+	// CPUPercent is the CPU time used by the hypervisor process, in percent of one host CPU
+	CPUPercent float64 `json:"cpuPercent"`
+	CPUs       int     `json:"cpus"`
+	// MemoryUsed is the resident memory of the hypervisor process, and MemoryLimit the memory of the VM
+	MemoryUsed  uint64 `json:"memoryUsed"`
+	MemoryLimit uint64 `json:"memoryLimit"`
+	// DiskUsed is the space allocated on the host by the disk images, and DiskSize their size in the VM
+	DiskUsed uint64 `json:"diskUsed"`
+	DiskSize uint64 `json:"diskSize"`
+	// RxRate and TxRate are the bytes per second received and sent by the network interfaces of the VM
+	RxRate float64 `json:"rxRate"`
+	TxRate float64 `json:"txRate"`
+}
+
+// HypervisorPid returns the pid of the host process which runs the VM of a machine
+func HypervisorPid(driver string, name string, machineDir string) (int32, error) {
+	if f, ok := pidFiles[driver]; ok {
+		return readPidFile(filepath.Join(machineDir, f))
+	}
+	switch driver {
+	case constants.DriverKvm2:
+		return readPidFile(filepath.Join(libvirtPidDir, name+".pid"))
+	case constants.DriverVirtualbox:
+		return findProcess(func(args []string) bool {
+			return strings.Contains(filepath.Base(args[0]), "VBoxHeadless") && hasArg(args, "--comment", name)
+		})
+	}
+	return 0, fmt.Errorf("the %s driver is not supported", driver)
+}
+
+func readPidFile(path string) (int32, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "reading pid file")
+	}
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing %s", path)
+	}
+	return int32(pid), nil
+}
+
+// findProcess returns the first process whose command line matches
+func findProcess(match func(args []string) bool) (int32, error) {
+	pids, err := process.Pids()
+	if err != nil {
+		return 0, err
+	}
+	for _, pid := range pids {
+		p, err := process.NewProcess(pid)
+		if err != nil {
+			continue
+		}
+		args, err := p.CmdlineSlice()
+		if err != nil || len(args) == 0 {
+			continue
+		}
+		if match(args) {
+			return pid, nil
+		}
+	}
+	return 0, fmt.Errorf("no hypervisor process found")
+}
+
+// hasArg returns whether args contain flag followed by value
+func hasArg(args []string, flag string, value string) bool {
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == flag && args[i+1] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DiskUsed returns the space allocated on the host by the disk images in the machine directory
+func DiskUsed(machineDir string) (uint64, error) {
+	files, err := ioutil.ReadDir(machineDir)
+	if err != nil {
+		return 0, err
+	}
+	var used uint64
+	for _, f := range files {
+		for _, ext := range diskExts {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), ext) {
+				used += allocatedSize(f)
+			}
+		}
+	}
+	return used, nil
+}
+
+// netDev returns the bytes received and sent by the ethernet interfaces of the VM. The interfaces of
+// containers and bridges are left out, since their traffic is also counted by the interfaces of the VM.
+func netDev(r command.Runner) (uint64, uint64, error) {
+	s, err := r.CombinedOutput("cat /proc/net/dev")
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "reading /proc/net/dev")
+	}
+	return parseNetDev(s)
+}
+
+func parseNetDev(s string) (uint64, uint64, error) {
+	var rx, tx uint64
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 || !strings.HasPrefix(strings.TrimSpace(parts[0]), "eth") {
+			continue
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) < 9 {
+			return 0, 0, fmt.Errorf("unexpected line: %q", scanner.Text())
+		}
+		r, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		t, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		rx += r
+		tx += t
+	}
+	return rx, tx, scanner.Err()
+}
+
+// Measure samples the CPU of the hypervisor process and the network of the VM for interval, and fills u with
+// what was used. The network is read inside the VM with r, and is left at zero if r is nil.
+func Measure(u *Usage, r command.Runner, interval time.Duration) error {
+	p, err := process.NewProcess(u.Pid)
+	if err != nil {
+		return errors.Wrapf(err, "hypervisor process %d", u.Pid)
+	}
+	t1, err := p.Times()
+	if err != nil {
+		return errors.Wrap(err, "cpu times")
+	}
+	var rx1, tx1 uint64
+	if r != nil {
+		if rx1, tx1, err = netDev(r); err != nil {
+			return err
+		}
+	}
+	start := time.Now()
+	time.Sleep(interval)
+
+	t2, err := p.Times()
+	if err != nil {
+		return errors.Wrap(err, "cpu times")
+	}
+	elapsed := time.Since(start).Seconds()
+	u.CPUPercent = (t2.Total() - t1.Total()) / elapsed * 100
+	m, err := p.MemoryInfo()
+	if err != nil {
+		return errors.Wrap(err, "memory info")
+	}
+	u.MemoryUsed = m.RSS
+	if r != nil {
+		rx2, tx2, err := netDev(r)
+		if err != nil {
+			return err
+		}
+		// The counters are reset when an interface goes down
+		if rx2 >= rx1 && tx2 >= tx1 {
+			u.RxRate = float64(rx2-rx1) / elapsed
+			u.TxRate = float64(tx2-tx1) / elapsed
+		}
+	}
+	return nil
+}
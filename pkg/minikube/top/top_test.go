@@ -0,0 +1,107 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package top
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const procNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:  123456     100    0    0    0     0          0         0   123456     100    0    0    0     0       0          0
+  eth0: 1000000    1200    0    0    0     0          0         0   200000     900    0    0    0     0       0          0
+  eth1:    5000      40    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+docker0:  999999     999    0    0    0     0          0         0   999999     999    0    0    0     0       0          0
+veth1a2b:  77777     77    0    0    0     0          0         0    77777      77    0    0    0     0       0          0
+`
+
+func TestParseNetDev(t *testing.T) {
+	rx, tx, err := parseNetDev(procNetDev)
+	if err != nil {
+		t.Fatalf("parseNetDev: %v", err)
+	}
+	if rx != 1005000 || tx != 203000 {
+		t.Errorf("parseNetDev() = %d, %d, want 1005000, 203000", rx, tx)
+	}
+	if _, _, err := parseNetDev("  eth0: 12 34\n"); err == nil {
+		t.Errorf("parseNetDev accepted a truncated line")
+	}
+}
+
+func TestHypervisorPid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "top")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "qemu.pid"), []byte("4242\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	pid, err := HypervisorPid(constants.DriverQemu2, "minikube", dir)
+	if err != nil || pid != 4242 {
+		t.Errorf("HypervisorPid(qemu2) = %d, %v, want 4242", pid, err)
+	}
+
+	libvirtPidDir = dir
+	if err := ioutil.WriteFile(filepath.Join(dir, "p1.pid"), []byte("99"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	pid, err = HypervisorPid(constants.DriverKvm2, "p1", filepath.Join(dir, "machines", "p1"))
+	if err != nil || pid != 99 {
+		t.Errorf("HypervisorPid(kvm2) = %d, %v, want 99", pid, err)
+	}
+
+	if _, err := HypervisorPid(constants.DriverHyperkit, "minikube", dir); err == nil {
+		t.Errorf("HypervisorPid without a pid file did not fail")
+	}
+	if _, err := HypervisorPid(constants.DriverNone, "minikube", dir); err == nil {
+		t.Errorf("HypervisorPid(none) did not fail")
+	}
+}
+
+func TestDiskUsed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "top")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := make([]byte, 64*1024)
+	for i := range data {
+		data[i] = 1
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "minikube.rawdisk"), data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "boot2docker.iso"), data, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	used, err := DiskUsed(dir)
+	if err != nil {
+		t.Fatalf("DiskUsed: %v", err)
+	}
+	// The file system may allocate a few more blocks than written
+	if used < uint64(len(data)) || used >= 2*uint64(len(data)) {
+		t.Errorf("DiskUsed() = %d, want the size of the disk image, %d", used, len(data))
+	}
+}
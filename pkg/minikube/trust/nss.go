@@ -0,0 +1,61 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trust
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// nssStore is an NSS database, managed with certutil
+type nssStore struct {
+	certutil string
+	db       string
+}
+
+// Name describes the store to the user
+func (s *nssStore) Name() string {
+	return fmt.Sprintf("the NSS database %s", strings.SplitN(s.db, ":", 2)[1])
+}
+
+// Install adds the certificate, replacing the one of a previous install
+func (s *nssStore) Install(cert *x509.Certificate, file string) error {
+	if err := s.Uninstall(cert); err != nil {
+		return err
+	}
+	return s.run("-A", "-d", s.db, "-t", "C,,", "-n", Nickname, "-i", file)
+}
+
+// Uninstall removes the certificate named Nickname
+func (s *nssStore) Uninstall(cert *x509.Certificate) error {
+	if err := exec.Command(s.certutil, "-L", "-d", s.db, "-n", Nickname).Run(); err != nil {
+		// Not installed
+		return nil
+	}
+	return s.run("-D", "-d", s.db, "-n", Nickname)
+}
+
+func (s *nssStore) run(args ...string) error {
+	if out, err := exec.Command(s.certutil, args...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "certutil %s: %s", strings.Join(args, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trust
+
+import (
+	"crypto/x509"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// systemKeychain is the keychain of the certificates which all the users trust
+const systemKeychain = "/Library/Keychains/System.keychain"
+
+// keychainStore is the System keychain, managed with security(1)
+type keychainStore struct{}
+
+// systemStore returns the System keychain
+func systemStore() (Store, error) {
+	return &keychainStore{}, nil
+}
+
+// Name describes the store to the user
+func (s *keychainStore) Name() string {
+	return "the System keychain"
+}
+
+// Install adds the certificate to the keychain as a trusted root, which asks for the password of sudo
+func (s *keychainStore) Install(cert *x509.Certificate, file string) error {
+	if err := s.Uninstall(cert); err != nil {
+		return err
+	}
+	return security("add-trusted-cert", "-d", "-r", "trustRoot", "-k", systemKeychain, file)
+}
+
+// Uninstall removes the certificate and its trust settings from the keychain
+func (s *keychainStore) Uninstall(cert *x509.Certificate) error {
+	out, err := exec.Command("security", "find-certificate", "-a", "-Z", systemKeychain).CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "security find-certificate: %s", out)
+	}
+	if !strings.Contains(string(out), Fingerprint(cert)) {
+		return nil
+	}
+	return security("delete-certificate", "-t", "-Z", Fingerprint(cert), systemKeychain)
+}
+
+// security runs security(1) with sudo, as changing the System keychain requires an administrator
+func security(args ...string) error {
+	if out, err := exec.Command("sudo", append([]string{"security"}, args...)...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "security %s: %s", strings.Join(args, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trust
+
+import (
+	"crypto/x509"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// anchorStore is a directory of CA certificates which a command of the distribution merges into the trust store
+// of the system
+type anchorStore struct {
+	dir    string
+	update []string
+}
+
+// anchorStores are the trust stores of the supported distributions
+var anchorStores = []anchorStore{
+	// Debian and Ubuntu
+	{dir: "/usr/local/share/ca-certificates", update: []string{"update-ca-certificates"}},
+	// Fedora, RHEL and CentOS
+	{dir: "/etc/pki/ca-trust/source/anchors", update: []string{"update-ca-trust", "extract"}},
+	// Arch
+	{dir: "/etc/ca-certificates/trust-source/anchors", update: []string{"trust", "extract-compat"}},
+	// openSUSE
+	{dir: "/usr/share/pki/trust/anchors", update: []string{"update-ca-certificates"}},
+}
+
+// systemStore returns the first of anchorStores which is present
+func systemStore() (Store, error) {
+	for _, s := range anchorStores {
+		if _, err := os.Stat(s.dir); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(s.update[0]); err != nil {
+			continue
+		}
+		return &s, nil
+	}
+	return nil, fmt.Errorf("no trust store found: install update-ca-certificates, update-ca-trust or p11-kit")
+}
+
+// Name describes the store to the user
+func (s *anchorStore) Name() string {
+	return fmt.Sprintf("the trust store of the system (%s)", s.dir)
+}
+
+// path is where the certificate is copied to. update-ca-certificates only reads files with the .crt extension.
+func (s *anchorStore) path() string {
+	return filepath.Join(s.dir, "minikube-ca.crt")
+}
+
+// Install copies the certificate into the directory with sudo, and updates the trust store
+func (s *anchorStore) Install(cert *x509.Certificate, file string) error {
+	if out, err := exec.Command("sudo", "cp", "-f", file, s.path()).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "cp: %s", out)
+	}
+	if out, err := exec.Command("sudo", "chmod", "0644", s.path()).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "chmod: %s", out)
+	}
+	return s.updateStore()
+}
+
+// Uninstall removes the certificate from the directory with sudo, and updates the trust store
+func (s *anchorStore) Uninstall(cert *x509.Certificate) error {
+	if _, err := os.Stat(s.path()); os.IsNotExist(err) {
+		return nil
+	}
+	if out, err := exec.Command("sudo", "rm", "-f", s.path()).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "rm: %s", out)
+	}
+	return s.updateStore()
+}
+
+func (s *anchorStore) updateStore() error {
+	if out, err := exec.Command("sudo", s.update...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "%s: %s", strings.Join(s.update, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trust
+
+import (
+	"crypto/x509"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// rootStore is the Trusted Root Certification Authorities store of the local machine, managed with certutil.exe
+type rootStore struct{}
+
+// systemStore returns the Trusted Root Certification Authorities store
+func systemStore() (Store, error) {
+	return &rootStore{}, nil
+}
+
+// Name describes the store to the user
+func (s *rootStore) Name() string {
+	return "the Trusted Root Certification Authorities store"
+}
+
+// Install adds the certificate to the store, which requires an administrator
+func (s *rootStore) Install(cert *x509.Certificate, file string) error {
+	return certutil("-addstore", "-f", "Root", file)
+}
+
+// Uninstall removes the certificate from the store, which requires an administrator
+func (s *rootStore) Uninstall(cert *x509.Certificate) error {
+	if err := exec.Command("certutil", "-store", "Root", Fingerprint(cert)).Run(); err != nil {
+		// Not installed
+		return nil
+	}
+	return certutil("-delstore", "Root", Fingerprint(cert))
+}
+
+func certutil(args ...string) error {
+	if out, err := exec.Command("certutil", args...).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "certutil %s: %s", strings.Join(args, " "), out)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trust installs the minikube CA into the trust stores of the host
+package trust
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/homedir"
+)
+
+// Nickname names the minikube CA in the trust stores which need a name
+const Nickname = "minikube CA"
+
+// Store is a trust store of the host
+type Store interface {
+	// Name describes the store to the user
+	Name() string
+	// Install adds the certificate of the PEM file to the store, trusted to identify servers
+	Install(cert *x509.Certificate, file string) error
+	// Uninstall removes the certificate from the store, if it is there
+	Uninstall(cert *x509.Certificate) error
+}
+
+// SystemStore returns the trust store of the operating system
+func SystemStore() (Store, error) {
+	return systemStore()
+}
+
+// BrowserStores returns the NSS databases of the browsers of the user, which do not read the trust store of the
+// system: Firefox, and Chrome on Linux. They are managed with the certutil of NSS, if it is installed.
+func BrowserStores() []Store {
+	if runtime.GOOS == "windows" {
+		// Browsers use the store of the system, and certutil is another tool
+		return nil
+	}
+	dbs := nssDatabases(homedir.HomeDir())
+	if len(dbs) == 0 {
+		return nil
+	}
+	certutil, err := exec.LookPath("certutil")
+	if err != nil {
+		glog.Warningf("found NSS databases %s, but no certutil: %v", dbs, err)
+		return nil
+	}
+	stores := []Store{}
+	for _, db := range dbs {
+		stores = append(stores, &nssStore{certutil: certutil, db: db})
+	}
+	return stores
+}
+
+// ReadCert reads the certificate of a PEM file
+func ReadCert(file string) (*x509.Certificate, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s has no PEM encoded certificate", file)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", file)
+	}
+	return cert, nil
+}
+
+// Fingerprint returns the SHA-1 fingerprint of the certificate, in upper case hex, which identifies it in the stores
+func Fingerprint(cert *x509.Certificate) string {
+	return fmt.Sprintf("%X", sha1.Sum(cert.Raw))
+}
+
+// nssDatabases returns the NSS databases under the home directory: the one of Chrome and Chromium on Linux, and
+// the profiles of Firefox. They are named as certutil expects, with the sql: prefix for the cert9.db format.
+func nssDatabases(home string) []string {
+	patterns := []string{
+		filepath.Join(home, ".pki", "nssdb"),
+		filepath.Join(home, ".mozilla", "firefox", "*"),
+		filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox", "*"),
+		filepath.Join(home, "Library", "Application Support", "Firefox", "Profiles", "*"),
+	}
+	dbs := []string{}
+	for _, p := range patterns {
+		dirs, err := filepath.Glob(p)
+		if err != nil {
+			continue
+		}
+		for _, dir := range dirs {
+			if _, err := os.Stat(filepath.Join(dir, "cert9.db")); err == nil {
+				dbs = append(dbs, "sql:"+dir)
+			} else if _, err := os.Stat(filepath.Join(dir, "cert8.db")); err == nil {
+				dbs = append(dbs, "dbm:"+dir)
+			}
+		}
+	}
+	return dbs
+}
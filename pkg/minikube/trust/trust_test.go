@@ -0,0 +1,116 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trust
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trust")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "minikubeCA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("certificate: %v", err)
+	}
+	ca := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cert, err := ReadCert(ca)
+	if err != nil {
+		t.Fatalf("ReadCert() error = %v", err)
+	}
+	if cert.Subject.CommonName != "minikubeCA" {
+		t.Errorf("CommonName = %q, want minikubeCA", cert.Subject.CommonName)
+	}
+	if got, want := Fingerprint(cert), fmt.Sprintf("%X", sha1.Sum(der)); got != want {
+		t.Errorf("Fingerprint() = %s, want %s", got, want)
+	}
+
+	notPEM := filepath.Join(dir, "ca.key")
+	if err := ioutil.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := ReadCert(notPEM); err == nil {
+		t.Error("expected an error for a file without a certificate")
+	}
+}
+
+func TestNSSDatabases(t *testing.T) {
+	home, err := ioutil.TempDir("", "home")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(home)
+
+	files := []string{
+		".pki/nssdb/cert9.db",
+		".mozilla/firefox/abc.default/cert9.db",
+		".mozilla/firefox/old.default/cert8.db",
+		".mozilla/firefox/Crash Reports/events",
+	}
+	for _, f := range files {
+		p := filepath.Join(home, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, nil, 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	want := []string{
+		"sql:" + filepath.Join(home, ".pki", "nssdb"),
+		"sql:" + filepath.Join(home, ".mozilla", "firefox", "abc.default"),
+		"dbm:" + filepath.Join(home, ".mozilla", "firefox", "old.default"),
+	}
+	if diff := cmp.Diff(want, nssDatabases(home)); diff != "" {
+		t.Errorf("nssDatabases() mismatch (-want +got):\n%s", diff)
+	}
+}
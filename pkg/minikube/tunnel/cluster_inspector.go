@@ -19,6 +19,7 @@ package tunnel
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
@@ -86,7 +87,9 @@ func getRoute(host *host.Host, clusterConfig config.Config) (*Route, error) {
 		return nil, errors.Wrapf(err, "error getting host IP for %s", host.Name)
 	}
 
-	_, ipNet, err := net.ParseCIDR(clusterConfig.KubernetesConfig.ServiceCIDR)
+	// The gateway is the IPv4 address of the host, so only route the IPv4 range of a dual-stack cluster
+	serviceCIDR := strings.Split(clusterConfig.KubernetesConfig.ServiceCIDR, ",")[0]
+	_, ipNet, err := net.ParseCIDR(serviceCIDR)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing service CIDR: %s", err)
 	}
@@ -0,0 +1,170 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/daemon"
+)
+
+// stopTimeout is how long Stop waits for tunnels to clean up their routes and exit
+const stopTimeout = 30 * time.Second
+
+// PidFilePath returns the path of the pid file of the background tunnel for a machine
+func PidFilePath(machineName string) string {
+	return filepath.Join(constants.GetProfilePath(machineName), "tunnel.pid")
+}
+
+// LogFilePath returns the path of the log file of the background tunnel for a machine
+func LogFilePath(machineName string) string {
+	return filepath.Join(constants.GetProfilePath(machineName), "tunnel.log")
+}
+
+// StartBackground runs the minikube binary with args in a detached process and records
+// its pid, so that the tunnel keeps running after the terminal is closed.
+func StartBackground(machineName string, args []string) (int, error) {
+	pid, err := daemon.Start(LogFilePath(machineName), args...)
+	if err != nil {
+		return pid, err
+	}
+	if err := ioutil.WriteFile(PidFilePath(machineName), []byte(strconv.Itoa(pid)), 0600); err != nil {
+		return pid, errors.Wrap(err, "writing pid file")
+	}
+	return pid, nil
+}
+
+// RemovePidFile removes the background tunnel pid file of a machine if it belongs to the current process
+func RemovePidFile(machineName string) error {
+	pid, err := readPidFile(machineName)
+	if err != nil || pid != getPid() {
+		return err
+	}
+	return os.Remove(PidFilePath(machineName))
+}
+
+func readPidFile(machineName string) (int, error) {
+	b, err := ioutil.ReadFile(PidFilePath(machineName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "parsing %s", PidFilePath(machineName))
+	}
+	return pid, nil
+}
+
+// Running returns the tunnels running for a machine, both in the background and in a terminal.
+// A background tunnel that has not set up its route yet is returned with a nil Route.
+func (mgr *Manager) Running(machineName string) ([]*ID, error) {
+	tunnels, err := mgr.registry.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tunnels from registry: %s", err)
+	}
+
+	running := []*ID{}
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		isRunning, err := checkIfRunning(t.Pid)
+		if err != nil {
+			return nil, fmt.Errorf("error checking if tunnel is running: %s", err)
+		}
+		if isRunning {
+			running = append(running, t)
+		}
+	}
+
+	pid, err := readPidFile(machineName)
+	if err != nil {
+		return nil, err
+	}
+	if pid == 0 {
+		return running, nil
+	}
+	for _, t := range running {
+		if t.Pid == pid {
+			return running, nil
+		}
+	}
+	isRunning, err := checkIfRunning(pid)
+	if err != nil {
+		return nil, fmt.Errorf("error checking if tunnel is running: %s", err)
+	}
+	if !isRunning {
+		glog.Infof("removing stale tunnel pid file for %d", pid)
+		return running, os.Remove(PidFilePath(machineName))
+	}
+	return append(running, &ID{MachineName: machineName, Pid: pid}), nil
+}
+
+// Stop interrupts the tunnels running for a machine and waits for them to exit.
+// Routes left behind by tunnels that could not clean up after themselves are removed.
+func (mgr *Manager) Stop(machineName string) error {
+	tunnels, err := mgr.Running(machineName)
+	if err != nil {
+		return err
+	}
+
+	pids := map[int]bool{}
+	for _, t := range tunnels {
+		if pids[t.Pid] {
+			continue
+		}
+		pids[t.Pid] = true
+		glog.Infof("stopping tunnel %v", t)
+		if err := daemon.Interrupt(t.Pid); err != nil {
+			return errors.Wrapf(err, "stopping tunnel with pid %d", t.Pid)
+		}
+	}
+
+	deadline := time.Now().Add(stopTimeout)
+	for pid := range pids {
+		for {
+			isRunning, err := checkIfRunning(pid)
+			if err != nil {
+				return fmt.Errorf("error checking if tunnel is running: %s", err)
+			}
+			if !isRunning {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("tunnel with pid %d did not exit within %s", pid, stopTimeout)
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	if err := os.Remove(PidFilePath(machineName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return mgr.CleanupNotRunningTunnels()
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func setupMinikubeHome(t *testing.T, machineName string) func() {
+	dir, err := ioutil.TempDir("", "tunnel_daemon")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	if err := os.Setenv(constants.MinikubeHome, dir); err != nil {
+		t.Fatalf("failed to set %s: %v", constants.MinikubeHome, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(PidFilePath(machineName)), 0700); err != nil {
+		t.Fatalf("failed to create profile dir: %v", err)
+	}
+	return func() {
+		os.Unsetenv(constants.MinikubeHome)
+		os.RemoveAll(dir)
+	}
+}
+
+func writePidFile(t *testing.T, machineName string, pid int) {
+	if err := ioutil.WriteFile(PidFilePath(machineName), []byte(strconv.Itoa(pid)), 0600); err != nil {
+		t.Fatalf("failed to write pid file: %v", err)
+	}
+}
+
+func TestRunning(t *testing.T) {
+	defer setupMinikubeHome(t, "minikube")()
+	reg, cleanup := createTestRegistry(t)
+	defer cleanup()
+
+	runningTunnel1, runningTunnel2, err := registerRunningTunnels(reg)
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if _, _, err := registerNotRunningTunnels(reg); err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	otherMachine := &ID{
+		Route:       unsafeParseRoute("1.1.1.1", "30.6.7.8/9"),
+		Pid:         os.Getpid(),
+		MachineName: "other",
+	}
+	if err := reg.Register(otherMachine); err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+
+	manager := NewManager()
+	manager.registry = reg
+
+	running, err := manager.Running("minikube")
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if len(running) != 2 || !running[0].Equal(runningTunnel1) || !running[1].Equal(runningTunnel2) {
+		t.Errorf("expected only the running tunnels of the machine, got: %v", running)
+	}
+
+	// the pid file of the tunnel already in the registry does not add another entry
+	writePidFile(t, "minikube", os.Getpid())
+	running, err = manager.Running("minikube")
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if len(running) != 2 {
+		t.Errorf("expected 2 running tunnels, got: %v", running)
+	}
+}
+
+func TestRunningBackgroundWithoutRoute(t *testing.T) {
+	defer setupMinikubeHome(t, "minikube")()
+	reg, cleanup := createTestRegistry(t)
+	defer cleanup()
+
+	manager := NewManager()
+	manager.registry = reg
+
+	writePidFile(t, "minikube", os.Getpid())
+	running, err := manager.Running("minikube")
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if len(running) != 1 || running[0].Pid != os.Getpid() || running[0].Route != nil {
+		t.Errorf("expected the background tunnel without a route, got: %v", running)
+	}
+
+	writePidFile(t, "minikube", 12341234)
+	running, err = manager.Running("minikube")
+	if err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if len(running) != 0 {
+		t.Errorf("expected no running tunnels, got: %v", running)
+	}
+	if _, err := os.Stat(PidFilePath("minikube")); !os.IsNotExist(err) {
+		t.Errorf("expected stale pid file to be removed, got: %v", err)
+	}
+}
+
+func TestRemovePidFile(t *testing.T) {
+	defer setupMinikubeHome(t, "minikube")()
+
+	writePidFile(t, "minikube", 12341234)
+	if err := RemovePidFile("minikube"); err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if _, err := os.Stat(PidFilePath("minikube")); err != nil {
+		t.Errorf("pid file of another process should be kept, got: %v", err)
+	}
+
+	writePidFile(t, "minikube", os.Getpid())
+	if err := RemovePidFile("minikube"); err != nil {
+		t.Fatalf("expected no error got: %v", err)
+	}
+	if _, err := os.Stat(PidFilePath("minikube")); !os.IsNotExist(err) {
+		t.Errorf("expected pid file to be removed, got: %v", err)
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/util"
 )
@@ -195,6 +196,20 @@ func (p *BuildrootProvisioner) Provision(swarmOptions swarm.Options, authOptions
 	return nil
 }
 
+// subordinateIDs is the range of IDs which root in the containers is remapped to, with --rootless
+const subordinateIDs = "100000:65536"
+
+// setSubordinateIDs gives user a range of subordinate user and group IDs, unless it already has one
+func setSubordinateIDs(p *BuildrootProvisioner, user string) error {
+	for _, f := range []string{"/etc/subuid", "/etc/subgid"} {
+		c := fmt.Sprintf("sudo touch %s && (grep -q '^%s:' %s || echo %s:%s | sudo tee -a %s)", f, user, f, user, subordinateIDs, f)
+		if _, err := p.SSHCommand(c); err != nil {
+			return errors.Wrap(err, f)
+		}
+	}
+	return nil
+}
+
 func setRemoteAuthOptions(p provision.Provisioner) auth.Options {
 	dockerDir := p.GetDockerOptionsDir()
 	authOptions := p.GetAuthOptions()
@@ -293,6 +308,12 @@ func configureAuth(p *BuildrootProvisioner) error {
 		return errors.Wrap(err, "generating docker options")
 	}
 
+	if config.MachineConfig.Rootless {
+		if err := setSubordinateIDs(p, cruntime.RemapUser); err != nil {
+			return errors.Wrap(err, "setting subordinate ids")
+		}
+	}
+
 	log.Info("Setting Docker configuration on the remote daemon...")
 
 	if _, err = p.SSHCommand(fmt.Sprintf("sudo mkdir -p %s && printf %%s \"%s\" | sudo tee %s", path.Dir(dockerCfg.EngineOptionsPath), dockerCfg.EngineOptions, dockerCfg.EngineOptionsPath)); err != nil {
@@ -0,0 +1,92 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// provisionedByAnnotation is set on the PVs by the provision controller, to the name of their provisioner
+const provisionedByAnnotation = "pv.kubernetes.io/provisioned-by"
+
+// resizePeriod is how often the claims are checked for new storage requests
+const resizePeriod = 10 * time.Second
+
+// volumeResizer expands the PVs of the provisioner when their claims request more storage, as the CSI
+// external-resizer does. Host paths are not limited in size, so expanding a volume only records its capacity:
+// in the PV, and then in the status of the claim, which completes the resize.
+type volumeResizer struct {
+	client kubernetes.Interface
+}
+
+// resizeAll expands the volumes of the claims whose storage request is larger than their capacity
+func (r *volumeResizer) resizeAll() {
+	pvcs, err := r.client.CoreV1().PersistentVolumeClaims("").List(meta.ListOptions{})
+	if err != nil {
+		glog.Errorf("listing claims: %v", err)
+		return
+	}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if err := r.resize(pvc); err != nil {
+			glog.Errorf("resizing %s/%s: %v", pvc.Namespace, pvc.Name, err)
+		}
+	}
+}
+
+// resize expands the volume of a bound claim to its storage request, if it is larger than its capacity
+func (r *volumeResizer) resize(pvc *core.PersistentVolumeClaim) error {
+	if pvc.Status.Phase != core.ClaimBound || pvc.Spec.VolumeName == "" {
+		return nil
+	}
+	requested := pvc.Spec.Resources.Requests[core.ResourceStorage]
+	capacity := pvc.Status.Capacity[core.ResourceStorage]
+	if requested.Cmp(capacity) <= 0 {
+		return nil
+	}
+	pv, err := r.client.CoreV1().PersistentVolumes().Get(pvc.Spec.VolumeName, meta.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "getting volume")
+	}
+	if pv.Annotations[provisionedByAnnotation] != provisionerName {
+		return nil
+	}
+
+	glog.Infof("Expanding volume %s of %s/%s from %s to %s", pv.Name, pvc.Namespace, pvc.Name, capacity.String(), requested.String())
+	current := pv.Spec.Capacity[core.ResourceStorage]
+	if requested.Cmp(current) > 0 {
+		pv.Spec.Capacity[core.ResourceStorage] = requested
+		if _, err := r.client.CoreV1().PersistentVolumes().Update(pv); err != nil {
+			return errors.Wrap(err, "updating volume")
+		}
+	}
+	if pvc.Status.Capacity == nil {
+		pvc.Status.Capacity = core.ResourceList{}
+	}
+	pvc.Status.Capacity[core.ResourceStorage] = requested
+	pvc.Status.Conditions = nil
+	if _, err := r.client.CoreV1().PersistentVolumeClaims(pvc.Namespace).UpdateStatus(pvc); err != nil {
+		return errors.Wrap(err, "updating claim status")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func volume(name string, provisioner string, size string) *core.PersistentVolume {
+	return &core.PersistentVolume{
+		ObjectMeta: meta.ObjectMeta{Name: name, Annotations: map[string]string{provisionedByAnnotation: provisioner}},
+		Spec: core.PersistentVolumeSpec{
+			Capacity: core.ResourceList{core.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+func claim(name string, volume string, requested string, capacity string) *core.PersistentVolumeClaim {
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: core.PersistentVolumeClaimSpec{
+			VolumeName: volume,
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{core.ResourceStorage: resource.MustParse(requested)},
+			},
+		},
+		Status: core.PersistentVolumeClaimStatus{
+			Phase:    core.ClaimBound,
+			Capacity: core.ResourceList{core.ResourceStorage: resource.MustParse(capacity)},
+		},
+	}
+}
+
+func TestResizeAll(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		volume("pv-grown", provisionerName, "1Gi"), claim("grown", "pv-grown", "2Gi", "1Gi"),
+		volume("pv-same", provisionerName, "1Gi"), claim("same", "pv-same", "1Gi", "1Gi"),
+		volume("pv-other", "kubernetes.io/aws-ebs", "1Gi"), claim("other", "pv-other", "2Gi", "1Gi"),
+	)
+	r := &volumeResizer{client: client}
+	r.resizeAll()
+
+	var tests = []struct {
+		pv   string
+		pvc  string
+		want string
+	}{
+		{"pv-grown", "grown", "2Gi"},
+		{"pv-same", "same", "1Gi"},
+		{"pv-other", "other", "1Gi"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.pvc, func(t *testing.T) {
+			pv, err := client.CoreV1().PersistentVolumes().Get(tc.pv, meta.GetOptions{})
+			if err != nil {
+				t.Fatalf("get volume: %v", err)
+			}
+			pvc, err := client.CoreV1().PersistentVolumeClaims("default").Get(tc.pvc, meta.GetOptions{})
+			if err != nil {
+				t.Fatalf("get claim: %v", err)
+			}
+			want := resource.MustParse(tc.want)
+			if got := pv.Spec.Capacity[core.ResourceStorage]; got.Cmp(want) != 0 {
+				t.Errorf("volume capacity = %s, want %s", got.String(), tc.want)
+			}
+			if got := pvc.Status.Capacity[core.ResourceStorage]; got.Cmp(want) != 0 {
+				t.Errorf("claim capacity = %s, want %s", got.String(), tc.want)
+			}
+		})
+	}
+}
@@ -138,6 +138,10 @@ func StartStorageProvisioner() error {
 	// PVs
 	pc := controller.NewProvisionController(clientset, provisionerName, hostPathProvisioner, serverVersion.GitVersion)
 
+	// Expand the volumes of the claims whose storage requests grew, for storage classes with allowVolumeExpansion
+	resizer := &volumeResizer{client: clientset}
+	go wait.Until(resizer.resizeAll, resizePeriod, wait.NeverStop)
+
 	glog.Info("Storage provisioner initialized, now starting service!")
 	pc.Run(wait.NeverStop)
 	return nil
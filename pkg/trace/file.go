@@ -0,0 +1,106 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/version"
+)
+
+// otlpFileSuffix is the extension of the files which are written with the OTLP JSON encoding, instead of as Chrome traces
+const otlpFileSuffix = ".otlp.json"
+
+// fileExporter writes the spans to a file: as a Chrome trace, which chrome://tracing, Perfetto and speedscope open,
+// or as an OTLP request if its name ends with .otlp.json, which OpenTelemetry collectors can import
+type fileExporter struct {
+	path string
+}
+
+func newFileExporter(path string) *fileExporter {
+	return &fileExporter{path: path}
+}
+
+// chromeTrace is the JSON object format of the Chrome trace event format
+type chromeTrace struct {
+	TraceEvents     []chromeEvent     `json:"traceEvents"`
+	DisplayTimeUnit string            `json:"displayTimeUnit"`
+	OtherData       map[string]string `json:"otherData"`
+}
+
+// chromeEvent is a complete event: a span, with its start and duration in microseconds
+type chromeEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp int64             `json:"ts"`
+	Duration  int64             `json:"dur"`
+	Pid       int               `json:"pid"`
+	Tid       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// chromeTraceOf returns the Chrome trace of spans. The spans of a start are nested in time, so they are all on one
+// thread, which trace viewers draw as a flame graph.
+func chromeTraceOf(spans []*Span) chromeTrace {
+	sorted := append([]*Span{}, spans...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+	events := []chromeEvent{}
+	for _, s := range sorted {
+		args := map[string]string{}
+		for k, v := range s.Attributes {
+			args[k] = v
+		}
+		if s.Error != "" {
+			args["error"] = s.Error
+		}
+		events = append(events, chromeEvent{
+			Name:      s.Name,
+			Category:  "minikube",
+			Phase:     "X",
+			Timestamp: s.Start.UnixNano() / 1000,
+			Duration:  s.End.Sub(s.Start).Nanoseconds() / 1000,
+			Pid:       1,
+			Tid:       1,
+			Args:      args,
+		})
+	}
+	return chromeTrace{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ms",
+		OtherData:       map[string]string{"version": version.GetVersion()},
+	}
+}
+
+func (e *fileExporter) export(spans []*Span) error {
+	var v interface{} = chromeTraceOf(spans)
+	if strings.HasSuffix(e.path, otlpFileSuffix) {
+		v = otlpTrace(spans)
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	if err := ioutil.WriteFile(e.path, append(b, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "writing %s", e.path)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/version"
+)
+
+// DefaultOTLPEndpoint is where an OpenTelemetry collector listens for OTLP over HTTP
+const DefaultOTLPEndpoint = "http://localhost:4318"
+
+// otlpExporter posts spans to an OTLP/HTTP endpoint, with the JSON encoding
+type otlpExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// newOTLPExporter returns an exporter to endpoint, or to $OTEL_EXPORTER_OTLP_ENDPOINT or the default one if it is empty.
+// headers are sent with the requests, in the key1=value1,key2=value2 format of $OTEL_EXPORTER_OTLP_HEADERS.
+func newOTLPExporter(endpoint string, headers string) (*otlpExporter, error) {
+	if endpoint == "" {
+		endpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid trace endpoint %q: use http://host:port or https://host:port", endpoint)
+	}
+	// The endpoint of a collector is its base URL, to which the path of the traces is appended
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	e := &otlpExporter{url: u.String(), headers: map[string]string{}, client: &http.Client{Timeout: 10 * time.Second}}
+	for _, h := range strings.Split(headers, ",") {
+		if strings.TrimSpace(h) == "" {
+			continue
+		}
+		kv := strings.SplitN(h, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid trace header %q: use key=value", h)
+		}
+		e.headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return e, nil
+}
+
+// The types below are the subset of the OTLP JSON encoding which minikube uses
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+// otlpAttributes returns the attributes of a map, sorted by key
+func otlpAttributes(m map[string]string) []otlpAttribute {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := []otlpAttribute{}
+	for _, k := range keys {
+		attrs = append(attrs, otlpAttribute{Key: k, Value: otlpValue{StringValue: m[k]}})
+	}
+	return attrs
+}
+
+// otlpTrace returns the OTLP request which exports spans
+func otlpTrace(spans []*Span) otlpRequest {
+	ss := []otlpSpan{}
+	for _, s := range spans {
+		status := otlpStatus{Code: otlpStatusOK}
+		if s.Error != "" {
+			status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		ss = append(ss, otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            status,
+		})
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: otlpAttributes(map[string]string{
+			"service.name":    "minikube",
+			"service.version": version.GetVersion(),
+		})},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "k8s.io/minikube", Version: version.GetVersion()}, Spans: ss}},
+	}}}
+}
+
+func (e *otlpExporter) export(spans []*Span) error {
+	b, err := json.Marshal(otlpTrace(spans))
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	req, err := http.NewRequest("POST", e.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "post %s", e.url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("post %s: %s: %s", e.url, resp.Status, body)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package trace records the spans of 'minikube start', and exports them to a tracing backend
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// OTLP exports the spans with the OpenTelemetry protocol, over HTTP
+	OTLP = "otlp"
+)
+
+// exporter sends the ended spans of a trace to a backend
+type exporter interface {
+	export(spans []*Span) error
+}
+
+// Span is a timed operation of a trace. The methods of a nil span do nothing, so that callers need not check
+// whether tracing is enabled.
+type Span struct {
+	Name       string
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	// Error is the reason why the operation failed, if it did
+	Error string
+}
+
+// tracer holds the spans of the trace of this process
+type tracer struct {
+	mu      sync.Mutex
+	exps    []exporter
+	traceID string
+	open    []*Span
+	ended   []*Span
+}
+
+var current *tracer
+
+// Initialize enables tracing, with the exporter of kind sending spans to endpoint, and writing them to file if it is
+// not empty. Tracing is disabled if both kind and file are empty.
+func Initialize(kind string, endpoint string, file string) error {
+	var exps []exporter
+	switch kind {
+	case "":
+	case OTLP:
+		e, err := newOTLPExporter(endpoint, os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+		if err != nil {
+			return err
+		}
+		exps = append(exps, e)
+	default:
+		return fmt.Errorf("unknown trace exporter %q, supported: %s", kind, OTLP)
+	}
+	if file != "" {
+		exps = append(exps, newFileExporter(file))
+	}
+	if len(exps) == 0 {
+		return nil
+	}
+	current = &tracer{exps: exps, traceID: randomID(16)}
+	return nil
+}
+
+// StartSpan starts a span, as a child of the innermost span which has not ended. It returns nil if tracing is disabled.
+func StartSpan(name string) *Span {
+	t := current
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &Span{Name: name, TraceID: t.traceID, SpanID: randomID(8), Start: time.Now(), Attributes: map[string]string{}}
+	if len(t.open) > 0 {
+		s.ParentID = t.open[len(t.open)-1].SpanID
+	}
+	t.open = append(t.open, s)
+	return s
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key string, value string) {
+	if s == nil {
+		return
+	}
+	current.mu.Lock()
+	defer current.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Finish ends the span, and the spans started after it which have not ended
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	t := current
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := len(t.open) - 1; i >= 0; i-- {
+		if t.open[i] == s {
+			t.endFrom(i, time.Now())
+			return
+		}
+	}
+}
+
+// endFrom ends the open spans from index i on. The lock must be held.
+func (t *tracer) endFrom(i int, now time.Time) {
+	for j := len(t.open) - 1; j >= i; j-- {
+		t.open[j].End = now
+		t.ended = append(t.ended, t.open[j])
+	}
+	t.open = t.open[:i]
+}
+
+// Fail marks the spans which have not ended as failed with reason
+func Fail(reason string) {
+	t := current
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.open {
+		s.Error = reason
+	}
+}
+
+// Flush ends the spans which have not ended, and exports the trace
+func Flush() error {
+	t := current
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	t.endFrom(0, time.Now())
+	spans := t.ended
+	t.ended = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	glog.Infof("Exporting %d spans of trace %s", len(spans), t.traceID)
+	var errs []string
+	for _, e := range t.exps {
+		if err := e.export(spans); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// randomID returns n random bytes, hex encoded
+func randomID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		glog.Errorf("random ID: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
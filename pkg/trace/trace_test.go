@@ -0,0 +1,207 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trace
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportOTLP(t *testing.T) {
+	var got otlpRequest
+	var path, team string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		team = r.Header.Get("x-honeycomb-team")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Decode: %v", err)
+		}
+	}))
+	defer srv.Close()
+
+	exp, err := newOTLPExporter(srv.URL, "x-honeycomb-team=secret")
+	if err != nil {
+		t.Fatalf("newOTLPExporter: %v", err)
+	}
+	current = &tracer{exps: []exporter{exp}, traceID: randomID(16)}
+	defer func() { current = nil }()
+
+	root := StartSpan("minikube start")
+	root.SetAttribute("driver", "kvm2")
+	StartSpan("download").Finish()
+	StartSpan("machine")
+	Fail("Unable to start VM")
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if path != "/v1/traces" || team != "secret" {
+		t.Errorf("request to %s with team %q, want /v1/traces with the header", path, team)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	byName := map[string]otlpSpan{}
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	r := byName["minikube start"]
+	if r.ParentSpanID != "" || len(r.TraceID) != 32 || len(r.SpanID) != 16 {
+		t.Errorf("root span = %+v, want a 16 byte trace ID, an 8 byte span ID and no parent", r)
+	}
+	if len(r.Attributes) != 1 || r.Attributes[0].Key != "driver" || r.Attributes[0].Value.StringValue != "kvm2" {
+		t.Errorf("root attributes = %+v, want driver=kvm2", r.Attributes)
+	}
+	for _, name := range []string{"download", "machine"} {
+		if byName[name].ParentSpanID != r.SpanID || byName[name].TraceID != r.TraceID {
+			t.Errorf("%s span = %+v, want a child of the root span", name, byName[name])
+		}
+	}
+	if s := byName["download"].Status; s.Code != otlpStatusOK {
+		t.Errorf("download status = %+v, want OK", s)
+	}
+	if s := byName["machine"].Status; s.Code != otlpStatusError || s.Message != "Unable to start VM" {
+		t.Errorf("machine status = %+v, want the error", s)
+	}
+	if s := r.Status; s.Code != otlpStatusError {
+		t.Errorf("root status = %+v, want the error", s)
+	}
+}
+
+func TestExportFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() { current = nil }()
+
+	path := filepath.Join(dir, "trace.json")
+	if err := Initialize("", "", path); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	root := StartSpan("minikube start")
+	StartSpan("download").Finish()
+	StartSpan("machine").SetAttribute("driver", "kvm2")
+	Fail("Unable to start VM")
+	root.Finish()
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got chromeTrace
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(got.TraceEvents) != 3 {
+		t.Fatalf("wrote %d events, want 3", len(got.TraceEvents))
+	}
+	// Events are sorted by start, so that parents come before their children
+	for i, name := range []string{"minikube start", "download", "machine"} {
+		e := got.TraceEvents[i]
+		if e.Name != name || e.Phase != "X" {
+			t.Errorf("event %d = %+v, want a complete %s event", i, e, name)
+		}
+	}
+	r, m := got.TraceEvents[0], got.TraceEvents[2]
+	if m.Timestamp < r.Timestamp || m.Timestamp+m.Duration > r.Timestamp+r.Duration {
+		t.Errorf("machine event %+v is not within the root event %+v", m, r)
+	}
+	if m.Args["driver"] != "kvm2" || m.Args["error"] != "Unable to start VM" {
+		t.Errorf("machine args = %v, want the driver and the error", m.Args)
+	}
+
+	path = filepath.Join(dir, "trace.otlp.json")
+	if err := Initialize("", "", path); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	StartSpan("minikube start").Finish()
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	b, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if spans := req.ResourceSpans[0].ScopeSpans[0].Spans; len(spans) != 1 || spans[0].Name != "minikube start" {
+		t.Errorf("wrote spans %+v, want the minikube start span", spans)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	if err := Initialize("", "", ""); err != nil {
+		t.Fatalf("Initialize: %v", err)
+	}
+	s := StartSpan("minikube start")
+	if s != nil {
+		t.Errorf("StartSpan() = %+v, want nil when tracing is disabled", s)
+	}
+	s.SetAttribute("k", "v")
+	s.Finish()
+	Fail("error")
+	if err := Flush(); err != nil {
+		t.Errorf("Flush() = %v, want nil", err)
+	}
+	if err := Initialize("gcp", "", ""); err == nil {
+		t.Errorf("Initialize(gcp) = nil, want error")
+	}
+}
+
+func TestNewOTLPExporter(t *testing.T) {
+	var tests = []struct {
+		endpoint string
+		headers  string
+		url      string
+		err      bool
+	}{
+		{endpoint: "http://localhost:4318", url: "http://localhost:4318/v1/traces"},
+		{endpoint: "https://api.honeycomb.io/", url: "https://api.honeycomb.io/v1/traces"},
+		{endpoint: "http://tempo:4318/otlp/v1/traces", url: "http://tempo:4318/otlp/v1/traces"},
+		{endpoint: "localhost:4318", err: true},
+		{endpoint: "http://localhost:4318", headers: "novalue", err: true},
+	}
+	for _, tc := range tests {
+		e, err := newOTLPExporter(tc.endpoint, tc.headers)
+		if tc.err {
+			if err == nil {
+				t.Errorf("newOTLPExporter(%q, %q) = nil error, want error", tc.endpoint, tc.headers)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("newOTLPExporter(%q): %v", tc.endpoint, err)
+			continue
+		}
+		if e.url != tc.url {
+			t.Errorf("newOTLPExporter(%q) url = %s, want %s", tc.endpoint, e.url, tc.url)
+		}
+	}
+}
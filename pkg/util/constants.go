@@ -18,6 +18,7 @@ package util
 
 import (
 	"net"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -30,6 +31,9 @@ const (
 	DefaultKubeConfigPath    = DefaultMinikubeDirectory + "/kubeconfig"
 	DefaultDNSDomain         = "cluster.local"
 	DefaultServiceCIDR       = "10.96.0.0/12"
+	DefaultServiceCIDRv6     = "fd00:10:96::/112"
+	DefaultPodCIDR           = "10.1.0.0/16"
+	DefaultPodCIDRv6         = "fd00:10:1::/64"
 )
 
 // DefaultV114AdmissionControllers are admission controllers we default to in v1.14.x
@@ -48,25 +52,35 @@ var DefaultV114AdmissionControllers = []string{
 // DefaultLegacyAdmissionControllers are admission controllers we include with Kubernetes <1.14.0
 var DefaultLegacyAdmissionControllers = append([]string{"Initializers"}, DefaultV114AdmissionControllers...)
 
-// GetServiceClusterIP returns the first IP of the ServiceCIDR
+// GetServiceClusterIP returns the first IP of the ServiceCIDR.
+// For a dual-stack range, the first IP of the first CIDR is returned.
 func GetServiceClusterIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := parseServiceCIDR(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3]++
+	ip[len(ip)-1]++
 	return ip, nil
 }
 
 // GetDNSIP returns x.x.x.10 of the service CIDR
 func GetDNSIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := parseServiceCIDR(serviceCIDR)
+	if err != nil {
+		return nil, err
+	}
+	ip[len(ip)-1] = 10
+	return ip, nil
+}
+
+func parseServiceCIDR(serviceCIDR string) (net.IP, error) {
+	ip, _, err := net.ParseCIDR(strings.Split(serviceCIDR, ",")[0])
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing default service cidr")
 	}
-	ip = ip.To4()
-	ip[3] = 10
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4, nil
+	}
 	return ip, nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"math/big"
 	"net"
@@ -84,7 +85,7 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	if decodedSignerKey == nil {
 		return errors.New("Unable to decode key")
 	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+	signerKey, err := parseRSAPrivateKey(decodedSignerKey.Bytes)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing prive key: decodedSignerKey.Bytes")
 	}
@@ -114,6 +115,63 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
 }
 
+// ValidateCACert returns an error unless certPath is a current CA certificate, which can sign certificates,
+// and keyPath is its RSA private key. Both are PEM encoded.
+func ValidateCACert(certPath, keyPath string) error {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return errors.Wrap(err, "reading certificate")
+	}
+	decodedCert, _ := pem.Decode(certBytes)
+	if decodedCert == nil {
+		return fmt.Errorf("%s is not a PEM encoded certificate", certPath)
+	}
+	c, err := x509.ParseCertificate(decodedCert.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing certificate")
+	}
+	if !c.IsCA || c.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return fmt.Errorf("%s is not a CA certificate which can sign certificates", certPath)
+	}
+	if now := time.Now(); now.Before(c.NotBefore) || now.After(c.NotAfter) {
+		return fmt.Errorf("%s is only valid from %s to %s", certPath, c.NotBefore, c.NotAfter)
+	}
+
+	keyBytes, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "reading key")
+	}
+	decodedKey, _ := pem.Decode(keyBytes)
+	if decodedKey == nil {
+		return fmt.Errorf("%s is not a PEM encoded key", keyPath)
+	}
+	key, err := parseRSAPrivateKey(decodedKey.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing key")
+	}
+	pub, ok := c.PublicKey.(*rsa.PublicKey)
+	if !ok || pub.N.Cmp(key.N) != 0 || pub.E != key.E {
+		return fmt.Errorf("%s is not the key of %s", keyPath, certPath)
+	}
+	return nil
+}
+
+// parseRSAPrivateKey parses a PKCS #1 or PKCS #8 RSA private key
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("only RSA keys are supported")
+	}
+	return rsaKey, nil
+}
+
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
 	keyBytes, err := ioutil.ReadFile(keyPath)
 	if err == nil {
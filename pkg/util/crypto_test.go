@@ -148,3 +148,51 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCACert(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caCertPath := filepath.Join(tmpDir, "ca.crt")
+	caKeyPath := filepath.Join(tmpDir, "ca.key")
+	if err := GenerateCACert(caCertPath, caKeyPath, constants.APIServerName); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	otherCertPath := filepath.Join(tmpDir, "other.crt")
+	otherKeyPath := filepath.Join(tmpDir, "other.key")
+	if err := GenerateCACert(otherCertPath, otherKeyPath, constants.APIServerName); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	signedCertPath := filepath.Join(tmpDir, "signed.crt")
+	signedKeyPath := filepath.Join(tmpDir, "signed.key")
+	if err := GenerateSignedCert(signedCertPath, signedKeyPath, "minikube", nil, nil, caCertPath, caKeyPath); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+
+	var tests = []struct {
+		description string
+		certPath    string
+		keyPath     string
+		err         bool
+	}{
+		{description: "valid CA", certPath: caCertPath, keyPath: caKeyPath},
+		{description: "not a CA", certPath: signedCertPath, keyPath: signedKeyPath, err: true},
+		{description: "key of another CA", certPath: caCertPath, keyPath: otherKeyPath, err: true},
+		{description: "key instead of cert", certPath: caKeyPath, keyPath: caKeyPath, err: true},
+		{description: "missing key", certPath: caCertPath, keyPath: filepath.Join(tmpDir, "missing"), err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateCACert(test.certPath, test.keyPath)
+			if err != nil && !test.err {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if err == nil && test.err {
+				t.Errorf("Expected error but got nil")
+			}
+		})
+	}
+}
@@ -17,14 +17,15 @@ limitations under the License.
 package util
 
 import (
+	"crypto"
 	"net/url"
 	"os"
 	"path/filepath"
 
 	"github.com/golang/glog"
-	"github.com/hashicorp/go-getter"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/download"
 	"k8s.io/minikube/pkg/minikube/out"
 )
 
@@ -60,29 +61,18 @@ func (f DefaultDownloader) CacheMinikubeISOFromURL(url string) error {
 		return nil
 	}
 
-	urlWithChecksum := url
+	opts := download.Options{Mirrors: download.Mirrors(), Name: filepath.Base(url), Signature: url + ".sig"}
 	if url == constants.DefaultISOURL {
-		urlWithChecksum = url + "?checksum=file:" + constants.DefaultISOSHAURL
+		opts.Checksum = constants.DefaultISOSHAURL
+		opts.Hash = crypto.SHA256
 	}
 
-	dst := f.GetISOCacheFilepath(url)
-	// Predictable temp destination so that resume can function
-	tmpDst := dst + ".download"
-
-	opts := []getter.ClientOption{getter.WithProgress(defaultProgressBar)}
-	client := &getter.Client{
-		Src:     urlWithChecksum,
-		Dst:     tmpDst,
-		Mode:    getter.ClientModeFile,
-		Options: opts,
-	}
-
-	glog.Infof("full url: %s", urlWithChecksum)
+	glog.Infof("full url: %s", url)
 	out.T(out.ISODownload, "Downloading VM boot image ...")
-	if err := client.Get(); err != nil {
+	if err := download.ToFile(url, f.GetISOCacheFilepath(url), opts); err != nil {
 		return errors.Wrap(err, url)
 	}
-	return os.Rename(tmpDst, dst)
+	return nil
 }
 
 // ShouldCacheMinikubeISO returns if we need to download the ISO
@@ -0,0 +1,84 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// IPFamilyIPv4 runs the cluster on IPv4 only
+	IPFamilyIPv4 = "ipv4"
+	// IPFamilyIPv6 runs the cluster on IPv6 only
+	IPFamilyIPv6 = "ipv6"
+	// IPFamilyDual gives pods and services both an IPv4 and an IPv6 address
+	IPFamilyDual = "dual"
+)
+
+// IPFamilies is the list of values accepted for the IP family of a cluster
+var IPFamilies = []string{IPFamilyIPv4, IPFamilyIPv6, IPFamilyDual}
+
+// ValidateIPFamily returns an error if family is not a known IP family
+func ValidateIPFamily(family string) error {
+	if !ContainsString(IPFamilies, family) {
+		return fmt.Errorf("unknown IP family %q, valid options: %s", family, strings.Join(IPFamilies, ", "))
+	}
+	return nil
+}
+
+// DefaultServiceCIDRs returns the comma separated default service CIDRs for an IP family
+func DefaultServiceCIDRs(family string) string {
+	switch family {
+	case IPFamilyIPv6:
+		return DefaultServiceCIDRv6
+	case IPFamilyDual:
+		return DefaultServiceCIDR + "," + DefaultServiceCIDRv6
+	default:
+		return DefaultServiceCIDR
+	}
+}
+
+// DefaultPodCIDRs returns the comma separated default pod CIDRs for an IP family
+func DefaultPodCIDRs(family string) string {
+	switch family {
+	case IPFamilyIPv6:
+		return DefaultPodCIDRv6
+	case IPFamilyDual:
+		return DefaultPodCIDR + "," + DefaultPodCIDRv6
+	default:
+		return DefaultPodCIDR
+	}
+}
+
+// ParseGlobalIPv6 returns the first global IPv6 address in the output of `ip -6 -o addr show scope global`
+func ParseGlobalIPv6(output string) (net.IP, error) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		for i, f := range fields {
+			if f != "inet6" || i+1 >= len(fields) {
+				continue
+			}
+			ip, _, err := net.ParseCIDR(fields[i+1])
+			if err == nil && ip.To4() == nil && ip.IsGlobalUnicast() {
+				return ip, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no global IPv6 address found")
+}
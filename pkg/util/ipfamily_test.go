@@ -0,0 +1,96 @@
+/*
+Copyright 2019 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestValidateIPFamily(t *testing.T) {
+	for _, family := range IPFamilies {
+		if err := ValidateIPFamily(family); err != nil {
+			t.Errorf("ValidateIPFamily(%q) = %v, want nil", family, err)
+		}
+	}
+	if err := ValidateIPFamily("ipv5"); err == nil {
+		t.Errorf("ValidateIPFamily(%q) = nil, want an error", "ipv5")
+	}
+}
+
+func TestDefaultCIDRs(t *testing.T) {
+	var tests = []struct {
+		family  string
+		service string
+		pod     string
+	}{
+		{family: IPFamilyIPv4, service: "10.96.0.0/12", pod: "10.1.0.0/16"},
+		{family: IPFamilyIPv6, service: "fd00:10:96::/112", pod: "fd00:10:1::/64"},
+		{family: IPFamilyDual, service: "10.96.0.0/12,fd00:10:96::/112", pod: "10.1.0.0/16,fd00:10:1::/64"},
+	}
+	for _, tc := range tests {
+		if got := DefaultServiceCIDRs(tc.family); got != tc.service {
+			t.Errorf("DefaultServiceCIDRs(%q) = %q, want %q", tc.family, got, tc.service)
+		}
+		if got := DefaultPodCIDRs(tc.family); got != tc.pod {
+			t.Errorf("DefaultPodCIDRs(%q) = %q, want %q", tc.family, got, tc.pod)
+		}
+	}
+}
+
+func TestGetServiceClusterIP(t *testing.T) {
+	var tests = []struct {
+		cidr string
+		ip   string
+		dns  string
+	}{
+		{cidr: "10.96.0.0/12", ip: "10.96.0.1", dns: "10.96.0.10"},
+		{cidr: "fd00:10:96::/112", ip: "fd00:10:96::1", dns: "fd00:10:96::a"},
+		{cidr: "10.96.0.0/12,fd00:10:96::/112", ip: "10.96.0.1", dns: "10.96.0.10"},
+	}
+	for _, tc := range tests {
+		ip, err := GetServiceClusterIP(tc.cidr)
+		if err != nil {
+			t.Fatalf("GetServiceClusterIP(%q) error = %v", tc.cidr, err)
+		}
+		if ip.String() != tc.ip {
+			t.Errorf("GetServiceClusterIP(%q) = %s, want %s", tc.cidr, ip, tc.ip)
+		}
+		dns, err := GetDNSIP(tc.cidr)
+		if err != nil {
+			t.Fatalf("GetDNSIP(%q) error = %v", tc.cidr, err)
+		}
+		if dns.String() != tc.dns {
+			t.Errorf("GetDNSIP(%q) = %s, want %s", tc.cidr, dns, tc.dns)
+		}
+	}
+}
+
+func TestParseGlobalIPv6(t *testing.T) {
+	output := `2: eth0    inet6 fe80::5054:ff:fe12:3456/64 scope link \       valid_lft forever preferred_lft forever
+3: eth1    inet6 fd00:39::2b/128 scope global dynamic noprefixroute \       valid_lft 3595sec preferred_lft 3595sec
+`
+	ip, err := ParseGlobalIPv6(output)
+	if err != nil {
+		t.Fatalf("ParseGlobalIPv6() error = %v", err)
+	}
+	if ip.String() != "fd00:39::2b" {
+		t.Errorf("ParseGlobalIPv6() = %s, want fd00:39::2b", ip)
+	}
+	if _, err := ParseGlobalIPv6("2: eth0    inet6 fe80::1/64 scope link"); err == nil {
+		t.Errorf("expected an error for a link-local address only")
+	}
+}
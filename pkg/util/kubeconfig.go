@@ -56,6 +56,9 @@ type KubeConfigSetup struct {
 	// Should the certificate files be embedded instead of referenced by path
 	EmbedCerts bool
 
+	// Exec is the exec credential plugin which returns the client certificate, instead of the certificate files
+	Exec *api.ExecConfig
+
 	// kubeConfigFile is the path where the kube config is stored
 	// Only access this with atomic ops
 	kubeConfigFile atomic.Value
@@ -90,7 +93,9 @@ func PopulateKubeConfig(cfg *KubeConfigSetup, kubecfg *api.Config) error {
 	// user
 	userName := cfg.ClusterName
 	user := api.NewAuthInfo()
-	if cfg.EmbedCerts {
+	if cfg.Exec != nil {
+		user.Exec = cfg.Exec
+	} else if cfg.EmbedCerts {
 		user.ClientCertificateData, err = ioutil.ReadFile(cfg.ClientCertificate)
 		if err != nil {
 			return err
@@ -370,3 +375,93 @@ func DeleteKubeConfigContext(kubeCfgPath, machineName string) error {
 	}
 	return nil
 }
+
+// UpdateKubeconfigCerts embeds certFile and keyFile again in the user of machineName, if its certificate is embedded.
+// Users which reference the files, or run an exec credential plugin, read the new files by themselves.
+func UpdateKubeconfigCerts(filename, machineName, certFile, keyFile string) (bool, error) {
+	confg, err := ReadConfigOrNew(filename)
+	if err != nil {
+		return false, errors.Wrap(err, "Error getting kubeconfig status")
+	}
+	context, ok := confg.Contexts[machineName]
+	if !ok {
+		return false, errors.Errorf("Kubeconfig does not have a record of the machine context")
+	}
+	user, ok := confg.AuthInfos[context.AuthInfo]
+	if !ok {
+		return false, errors.Errorf("Kubeconfig does not have a record of the machine user")
+	}
+	if len(user.ClientCertificateData) == 0 {
+		return false, nil
+	}
+	if user.ClientCertificateData, err = ioutil.ReadFile(certFile); err != nil {
+		return false, errors.Wrapf(err, "Error reading file %s", certFile)
+	}
+	if user.ClientKeyData, err = ioutil.ReadFile(keyFile); err != nil {
+		return false, errors.Wrapf(err, "Error reading file %s", keyFile)
+	}
+	if err := WriteConfig(confg, filename); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExtractContext returns a config which only holds the context of machineName, with its cluster and its user, as the
+// current context. With embedCerts, the certificate files are embedded, so that the config works on its own.
+func ExtractContext(filename, machineName string, embedCerts bool) (*api.Config, error) {
+	confg, err := ReadConfigOrNew(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error getting kubeconfig status")
+	}
+	context, ok := confg.Contexts[machineName]
+	if !ok {
+		return nil, errors.Errorf("Kubeconfig does not have a record of the machine context")
+	}
+	cluster, ok := confg.Clusters[context.Cluster]
+	if !ok {
+		return nil, errors.Errorf("Kubeconfig does not have a record of the machine cluster")
+	}
+	user, ok := confg.AuthInfos[context.AuthInfo]
+	if !ok {
+		return nil, errors.Errorf("Kubeconfig does not have a record of the machine user")
+	}
+
+	c := api.NewConfig()
+	cl := *cluster
+	u := *user
+	if embedCerts {
+		if err := embedFile(&cl.CertificateAuthority, &cl.CertificateAuthorityData); err != nil {
+			return nil, err
+		}
+		if err := embedFile(&u.ClientCertificate, &u.ClientCertificateData); err != nil {
+			return nil, err
+		}
+		if err := embedFile(&u.ClientKey, &u.ClientKeyData); err != nil {
+			return nil, err
+		}
+	}
+	c.Clusters[context.Cluster] = &cl
+	c.AuthInfos[context.AuthInfo] = &u
+	c.Contexts[machineName] = context
+	c.CurrentContext = machineName
+	return c, nil
+}
+
+// embedFile replaces the path of a certificate file with its data
+func embedFile(path *string, data *[]byte) error {
+	if *path == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(*path)
+	if err != nil {
+		return errors.Wrapf(err, "Error reading file %s", *path)
+	}
+	*data = b
+	*path = ""
+	return nil
+}
+
+// EncodeConfig encodes the configuration as YAML
+func EncodeConfig(config *api.Config) ([]byte, error) {
+	return runtime.Encode(latest.Codec, config)
+}
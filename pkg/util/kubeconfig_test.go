@@ -135,6 +135,17 @@ func TestSetupKubeConfig(t *testing.T) {
 			},
 			existingCfg: fakeKubeCfg,
 		},
+		{
+			description: "exec credential",
+			cfg: &KubeConfigSetup{
+				ClusterName:          "test",
+				ClusterServerAddress: "192.168.1.1:8080",
+				ClientCertificate:    "/home/apiserver.crt",
+				ClientKey:            "/home/apiserver.key",
+				CertificateAuthority: "/home/apiserver.crt",
+				Exec:                 &api.ExecConfig{APIVersion: "client.authentication.k8s.io/v1beta1", Command: "/usr/local/bin/minikube", Args: []string{"credential"}},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -167,6 +178,10 @@ func TestSetupKubeConfig(t *testing.T) {
 			if !test.cfg.KeepContext && config.CurrentContext != test.cfg.ClusterName {
 				t.Errorf("Context was not switched")
 			}
+			user := config.AuthInfos[test.cfg.ClusterName]
+			if test.cfg.Exec != nil && (user.Exec == nil || user.Exec.Command != test.cfg.Exec.Command || user.ClientCertificate != "") {
+				t.Errorf("User = %+v, want the exec credential plugin instead of the certificate", user)
+			}
 
 			os.RemoveAll(tmpDir)
 		})
@@ -663,3 +678,100 @@ func contextEquals(aContext, bContext *api.Context) bool {
 	}
 	return true
 }
+
+func TestExtractContext(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	certFile := filepath.Join(tmpDir, "apiserver.crt")
+	if err := ioutil.WriteFile(certFile, []byte("cert"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	setupCfg := &KubeConfigSetup{
+		ClusterName:          "minikube",
+		ClusterServerAddress: "https://192.168.1.1:8443",
+		ClientCertificate:    certFile,
+		ClientKey:            certFile,
+		CertificateAuthority: certFile,
+	}
+	setupCfg.SetKubeConfigFile(filepath.Join(tmpDir, "kubeconfig"))
+	if err := ioutil.WriteFile(setupCfg.GetKubeConfigFile(), fakeKubeCfg, 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := SetupKubeConfig(setupCfg); err != nil {
+		t.Fatalf("SetupKubeConfig: %v", err)
+	}
+
+	c, err := ExtractContext(setupCfg.GetKubeConfigFile(), "minikube", false)
+	if err != nil {
+		t.Fatalf("ExtractContext: %v", err)
+	}
+	if len(c.Contexts) != 1 || len(c.Clusters) != 1 || len(c.AuthInfos) != 1 || c.CurrentContext != "minikube" {
+		t.Errorf("ExtractContext() = %+v, want only the minikube context", c)
+	}
+	if c.AuthInfos["minikube"].ClientCertificate != certFile {
+		t.Errorf("ClientCertificate = %q, want %q", c.AuthInfos["minikube"].ClientCertificate, certFile)
+	}
+
+	c, err = ExtractContext(setupCfg.GetKubeConfigFile(), "minikube", true)
+	if err != nil {
+		t.Fatalf("ExtractContext: %v", err)
+	}
+	if u := c.AuthInfos["minikube"]; u.ClientCertificate != "" || string(u.ClientCertificateData) != "cert" || string(c.Clusters["minikube"].CertificateAuthorityData) != "cert" {
+		t.Errorf("ExtractContext() with embedCerts = %+v, want the certificates embedded", u)
+	}
+
+	if _, err := ExtractContext(setupCfg.GetKubeConfigFile(), "nonexistent", false); err == nil {
+		t.Errorf("ExtractContext() of a nonexistent context succeeded, want an error")
+	}
+}
+
+func TestUpdateKubeconfigCerts(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error making temp directory %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	certFile := filepath.Join(tmpDir, "client.crt")
+	if err := ioutil.WriteFile(certFile, []byte("old"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	for _, embed := range []bool{false, true} {
+		setupCfg := &KubeConfigSetup{
+			ClusterName:          "minikube",
+			ClusterServerAddress: "https://192.168.1.1:8443",
+			ClientCertificate:    certFile,
+			ClientKey:            certFile,
+			CertificateAuthority: certFile,
+			EmbedCerts:           embed,
+		}
+		setupCfg.SetKubeConfigFile(filepath.Join(tmpDir, "kubeconfig-"+strconv.FormatBool(embed)))
+		if err := SetupKubeConfig(setupCfg); err != nil {
+			t.Fatalf("SetupKubeConfig: %v", err)
+		}
+		if err := ioutil.WriteFile(certFile, []byte("new"), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+
+		updated, err := UpdateKubeconfigCerts(setupCfg.GetKubeConfigFile(), "minikube", certFile, certFile)
+		if err != nil {
+			t.Fatalf("UpdateKubeconfigCerts: %v", err)
+		}
+		if updated != embed {
+			t.Errorf("UpdateKubeconfigCerts() with embedded certs %v = %v, want %v", embed, updated, embed)
+		}
+		c, err := ReadConfigOrNew(setupCfg.GetKubeConfigFile())
+		if err != nil {
+			t.Fatalf("ReadConfigOrNew: %v", err)
+		}
+		if u := c.AuthInfos["minikube"]; embed && (string(u.ClientCertificateData) != "new" || string(u.ClientKeyData) != "new") {
+			t.Errorf("user = %+v, want the new certificate embedded", u)
+		}
+		if err := ioutil.WriteFile(certFile, []byte("old"), 0600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+}
@@ -271,3 +271,8 @@ func ContainsString(slice []string, s string) bool {
 	}
 	return false
 }
+
+// ShellQuote quotes a string as a single argument for the shell, such as the shell which runs the commands of the VM
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'"'"'`, -1) + "'"
+}
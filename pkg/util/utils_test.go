@@ -201,3 +201,21 @@ func TestConcatStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	var tests = []struct {
+		in   string
+		want string
+	}{
+		{in: "/data/x", want: `'/data/x'`},
+		{in: "", want: `''`},
+		{in: "a b", want: `'a b'`},
+		{in: "$HOME;id", want: `'$HOME;id'`},
+		{in: "it's", want: `'it'"'"'s'`},
+	}
+	for _, tc := range tests {
+		if got := ShellQuote(tc.in); got != tc.want {
+			t.Errorf("ShellQuote(%q) = %s, want %s", tc.in, got, tc.want)
+		}
+	}
+}
@@ -38,6 +38,10 @@ var isoVersion = "v0.0.0-unset"
 
 var isoPath = "minikube/iso"
 
+// downloadKey is the base64 public key which signs the downloads of minikube, set when compiling with
+// --ldflags="-X k8s.io/minikube/pkg/version.downloadKey=<key>"
+var downloadKey = ""
+
 // GetVersion returns the current minikube version
 func GetVersion() string {
 	return version
@@ -58,6 +62,11 @@ func GetISOPath() string {
 	return isoPath
 }
 
+// GetDownloadKey returns the public key which signs the downloads of minikube, or an empty string
+func GetDownloadKey() string {
+	return downloadKey
+}
+
 // GetSemverVersion returns the current minikube semantic version (semver)
 func GetSemverVersion() (semver.Version, error) {
 	return semver.Make(strings.TrimPrefix(GetVersion(), VersionPrefix))